@@ -3,7 +3,6 @@ package app
 import (
 	"fmt"
 
-	"github.com/go-ozzo/ozzo-validation"
 	"github.com/spf13/viper"
 )
 
@@ -24,8 +23,18 @@ type appConfig struct {
 	// the data source name (DSN) for connecting to the database. required.
 	DBName string `mapstructure:"db_name"`
 
+	// ApiAuthKey is the legacy admin API key
 	ApiAuthKey string `mapstructure:"api_auth_key"`
 
+	// ApiKeys maps a role (viewer, operator, admin) to its API keys
+	ApiKeys map[string][]string `mapstructure:"api_keys"`
+
+	// SSOHeader is the header set by the SSO proxy with the authenticated identity, SSO is disabled when empty
+	SSOHeader string `mapstructure:"sso_header"`
+
+	// SSORoles maps a role (viewer, operator, admin) to its SSO identities
+	SSORoles map[string][]string `mapstructure:"sso_roles"`
+
 	// the RabbitMQURL is the URI of rabbitmq to use
 	RabbitMQURL string `mapstructure:"rabbitmq_url"`
 
@@ -34,16 +43,224 @@ type appConfig struct {
 
 	Tomochain map[string]string `mapstructure:"tomochain"`
 
-	Env string `mapstructure:"env"`
-}
+	// the number of seconds the latest block may be behind before /healthz reports the node as lagging. Defaults to 60
+	MaxChainLag int64 `mapstructure:"max_chain_lag"`
+
+	// Confirmations is the number of blocks required before an action (trade, lending_trade, deposit) is final. Defaults to 1
+	Confirmations map[string]uint64 `mapstructure:"confirmations"`
+
+	// MaxBodySize is the request body size limit in bytes. Defaults to 1MB
+	MaxBodySize int64 `mapstructure:"max_body_size"`
+
+	// BodyLimits overrides the body size limit per route path template, e.g. "/api/orders"
+	BodyLimits map[string]int64 `mapstructure:"body_limits"`
+
+	// AuthGuard configures the lockout of authentication failures (max_failures, window, lockout, base_delay_ms, max_delay_ms)
+	AuthGuard map[string]int `mapstructure:"auth_guard"`
+
+	// Admission configures the order intake control under engine overload
+	// (throttle_pending, max_pending: new orders of a pair in the order queue and not matched yet, throttle_latency_ms,
+	// max_latency_ms, throttle_delay_ms) and the number of
+	// orders queued by a pair paused in queue mode (pause_queue_limit)
+	Admission map[string]int `mapstructure:"admission"`
+
+	// Settlement configures the reports of the failed trade settlements (max_attempts, retry_delay: reads of a receipt
+	// not available yet, pause_threshold)
+	Settlement map[string]int `mapstructure:"settlement"`
+
+	// Usage configures the API usage statistics of the users (window, max_requests, max_ws_messages)
+	Usage map[string]int `mapstructure:"usage"`
+
+	// AccessLog configures the access logs of the HTTP requests and the websocket messages (enabled,
+	// sample_rate in percent, anonymize_days after which the hash of the caller is removed, retention_days)
+	AccessLog map[string]int `mapstructure:"access_log"`
+
+	// TradeSampling limits the messages per second of the public trades channel, per lower cased pair name or "default"
+	TradeSampling map[string]int `mapstructure:"trade_sampling"`
+
+	// OrderPriority configures the handling of the order messages, the cancels are served before the new orders of other orders
+	// (workers, cancel_weight: cancels served for each waiting new order, 0 for strict priority, queue_size: messages
+	// taken from RabbitMQ and not handled yet)
+	OrderPriority map[string]int `mapstructure:"order_priority"`
+
+	// LendingCollars is the band in basis points around the recent average interest accepted for the new lending orders,
+	// per "<lower cased lending token address>/<term>" or "default" (0 to disable)
+	LendingCollars map[string]int `mapstructure:"lending_collars"`
+
+	// MaturityReminders are the lead times in seconds of the reminders sent before the loans mature
+	MaturityReminders []int64 `mapstructure:"maturity_reminders"`
+
+	// IlliquidCollateral configures the alerts sent to the lenders when the bids on the collateral no longer
+	// cover the collateral locked by the open loans (band_bps, min_coverage_pct)
+	IlliquidCollateral map[string]int `mapstructure:"illiquid_collateral"`
+
+	// Listing configures the requirements of the pairs discovered on the relayers, held in review until approved
+	// (enabled, min_holders, min_liquidity in whole quote tokens, min_decimals, max_decimals)
+	Listing map[string]int `mapstructure:"listing"`
+
+	// Risk configures the pre-trade risk checks of the new orders (enabled, max_order_notional in whole quote tokens,
+	// max_position in whole base tokens), the limits of the accounts and pairs are managed with the admin API
+	Risk map[string]int `mapstructure:"risk"`
+
+	// MarketData configures the public market data feed (delay in seconds), the callers without a viewer
+	// API key get the market data that much later. 0 serves the real-time data to everyone
+	MarketData map[string]int `mapstructure:"market_data"`
+
+	// AccountBatch configures the batch account queries: the number of addresses of a query (max_addresses, 20)
+	// and the queries of a caller per window (requests, 10 per window, 60 seconds)
+	AccountBatch map[string]int `mapstructure:"account_batch"`
+
+	// Microstructure configures the microstructure channel: the metrics are sent every interval (seconds, 5),
+	// the imbalance is computed on the top levels of the book (10) and the toxicity on the trades of the
+	// window (seconds, 300) split in buckets of equal volume (10)
+	Microstructure map[string]int `mapstructure:"microstructure"`
+
+	// DepthSnapshots configures the hourly snapshots of the order books of the active pairs: the number of
+	// levels of each side kept (levels, 20) and the days the snapshots are kept (retention_days, 365)
+	DepthSnapshots map[string]int `mapstructure:"depth_snapshots"`
+
+	// DustThresholds maps a pair name (BASE/QUOTE) to its dust threshold in base units, the remainder of a partially
+	// filled order below it is cancelled and the order is marked FILLED_WITH_DUST. The pairs not listed keep their dust
+	DustThresholds map[string]string `mapstructure:"dust_thresholds"`
+
+	// MakerRebates maps a pair name (BASE/QUOTE) to the tiers of its maker rebate program, a comma separated list
+	// of minVolume:makeFee. The makers who traded at least minVolume of quote token on the pair during the 30
+	// previous days pay makeFee basis points of the quote amount of their trades, a negative fee is a rebate. The
+	// difference with the fee charged by the exchange contract is owed back to the maker and reported by the
+	// daily fee reports
+	MakerRebates map[string]string `mapstructure:"maker_rebates"`
+
+	// Canary configures the synthetic order canary (wallet, base_token, quote_token, price, amount in base units,
+	// interval and timeout in seconds, submit_ms, ack_ms, match_ms and broadcast_ms thresholds, incidents),
+	// the canary is disabled without a wallet
+	Canary map[string]string `mapstructure:"canary"`
+
+	// KMS configures the encryption of the secrets stored by the SDK (backend: local, vault or aws)
+	KMS map[string]string `mapstructure:"kms"`
+
+	// Compliance gates the orders and the lending orders behind a KYC check (provider: allowlist or http),
+	// on the listed pairs or all of them
+	Compliance map[string]string `mapstructure:"compliance"`
 
-func (config appConfig) Validate() error {
-	return validation.ValidateStruct(&config,
-		validation.Field(&config.MongoURL, validation.Required),
-	)
+	// GeoPolicy denies the blocked IPs and countries, and the restricted routes to the restricted countries
+	// (blocked_ips, blocked_countries, restricted_countries, restricted_routes, country_header, cidr_file)
+	GeoPolicy map[string]string `mapstructure:"geo_policy"`
+
+	// KillSwitchSigners are the addresses whose signed requests can activate and release the kill switch
+	// without an admin API key, the signed requests are refused when none is set
+	KillSwitchSigners []string `mapstructure:"kill_switch_signers"`
+
+	// IntegrityRecipients are the addresses notified when the nightly integrity check finds violations
+	IntegrityRecipients []string `mapstructure:"integrity_recipients"`
+
+	// ResponseSigning signs the order acks and the trade confirmations with the key of the relayer (enabled,
+	// wallet defaulting to the default admin wallet), the responses are not signed unless enabled is true
+	ResponseSigning map[string]string `mapstructure:"response_signing"`
+
+	// Secrets configures where the credentials are read at runtime (backend: config or vault)
+	Secrets map[string]string `mapstructure:"secrets"`
+
+	// NodeID is the number of this instance in the public ids of the orders and trades (0 to 1023),
+	// the instances sharing a database must have different numbers. Defaults to 0
+	NodeID int `mapstructure:"node_id"`
+
+	// TokenInfoTTL is how long the metadata of the tokens read by the relayer refreshes is cached, in seconds.
+	// Defaults to a day
+	TokenInfoTTL int `mapstructure:"token_info_ttl"`
+
+	// TokenInfoConcurrency is the number of tokens whose metadata is read at the same time when the relayer
+	// refreshes without a Multicall contract, or for the tokens it fails to read. Defaults to 8
+	TokenInfoConcurrency int `mapstructure:"token_info_concurrency"`
+
+	// TokenInfoBatchSize is the number of calls of the JSON-RPC batch requests reading the metadata of the
+	// tokens without a Multicall contract, the tokens are read one by one if it is not set
+	TokenInfoBatchSize int `mapstructure:"token_info_batch_size"`
+
+	// Relayers are the coinbases of the relayers whose tokens and pairs are merged by the aggregated
+	// relayer API, the relayer of the exchange address by default
+	Relayers []string `mapstructure:"relayers"`
+
+	// RelayerEvents follows the changes of the relayers from the events of the registration contract through
+	// the websocket endpoint of the node (enabled, retry in seconds), the full sync then runs hourly. The fees
+	// of the relayers are also read every fee_interval seconds, 60 by default
+	RelayerEvents map[string]int `mapstructure:"relayer_events"`
+
+	// ChainListener follows the new block headers and the trade and cancel logs of the exchange contract through
+	// the websocket endpoint of the node (enabled, retry in seconds), the changes of the TomoX database are still
+	// followed from the change streams
+	ChainListener map[string]int `mapstructure:"chain_listener"`
+
+	// ReorgDepth is the number of block hashes kept to detect the chain reorganizations, the trades settled in the
+	// replaced blocks are rolled back. 64 by default
+	ReorgDepth int `mapstructure:"reorg_depth"`
+
+	// RPCFallbackURLs are the http endpoints of other nodes the contract calls of the relayer fail over to
+	// when the node of tomochain.http_url does not answer
+	RPCFallbackURLs []string `mapstructure:"rpc_fallback_urls"`
+
+	// RPCRetry configures the retries of the contract calls of the relayer once all the endpoints failed
+	// (attempts, backoff_ms doubled up to max_backoff_ms) and the timeout of each request to an endpoint
+	// (timeout_ms). Defaults to 3 attempts from 500ms to 10s and requests aborted after 10s. The calls are
+	// refused for breaker_cooldown_ms after breaker_failures failed calls in a row, then a probe call is
+	// let through. Defaults to 5 failures and 30s
+	RPCRetry map[string]int `mapstructure:"rpc_retry"`
+
+	// DependencyMetrics configures the metrics of the MongoDB and RabbitMQ clients: the queries slower than
+	// slow_query_ms are counted as slow, 100ms by default
+	DependencyMetrics map[string]int `mapstructure:"dependency_metrics"`
+
+	// AmountFormat is the format of the amounts and the prices of the API responses when the request does not
+	// negotiate one: wei (integer strings), decimal (strings in token units) or float (numbers in token units).
+	// The responses are kept as the endpoints serialize them when it is not set
+	AmountFormat string `mapstructure:"amount_format"`
+
+	// TradeFeedPrivacy shows the maker and taker addresses of the public trades, on the trades channel and
+	// /api/trades, in full, truncated to their first and last 4 hex digits or hidden. The authenticated
+	// raw_trades channel and the trade history of a user always show them in full. Defaults to full
+	TradeFeedPrivacy string `mapstructure:"trade_feed_privacy"`
+
+	// RelayerSigner configures the signer of the transactions of the relayers (backend: key, keystore, ledger
+	// or trezor), with an account per relayer coinbase. The keystore backend decrypts the comma separated keystore
+	// files with the relayer_passphrase secret, else the passphrase of the section, the hardware wallets derive the
+	// accounts at the comma separated derivation_path, m/44'/60'/0'/0/0 by default. The key backend, the default,
+	// uses the relayer_keystore secret or the compiled in keystore. The transactions are signed for chain_id
+	// (EIP-155), with the homestead signer without it
+	RelayerSigner map[string]string `mapstructure:"relayer_signer"`
+
+	// GasOracle configures the gas prices suggested for the transactions sent by the SDK, the safe, standard and
+	// fast percentiles (30, 60 and 90 by default) of the prices paid in the last blocks (20 by default) with
+	// min_price in wei as their floor
+	GasOracle map[string]int `mapstructure:"gas_oracle"`
+
+	// NonceManager configures the nonces of the transactions sent by the SDK: a transaction pending for stuck_after
+	// seconds (180 by default) is replaced with its gas price raised by price_bump percent (10 by default, the minimum
+	// accepted by the nodes) up to max_replacements times (5 by default)
+	NonceManager map[string]int `mapstructure:"nonce_manager"`
+
+	// Faucet sends test TOMO and test tokens to the addresses requesting them on the test networks, from the
+	// account of the keystore file decrypted with the faucet_passphrase secret, else the passphrase of the section.
+	// tokens is the comma separated list of token:amount in the units of the token, TOMO at the native token
+	// address. An address is funded once per interval in seconds (one day by default) and a caller makes up to
+	// requests per window in seconds. The transactions are signed for chain_id. Disabled without keystore
+	Faucet map[string]string `mapstructure:"faucet"`
+
+	// RelayerBranding is the branding of the venue returned by the relayer info API for the white label
+	// frontends: name (the name of the relayer contract by default), logo_url, support_email and the links
+	// website_url, support_url, terms_url, privacy_url, twitter_url and telegram_url
+	RelayerBranding map[string]string `mapstructure:"relayer_branding"`
+
+	// ABIOverrides are the paths of JSON files replacing the compiled in contract ABIs (relayer, lending, token),
+	// for the contracts deployed with another version
+	ABIOverrides map[string]string `mapstructure:"abi_overrides"`
+
+	// Boot overrides the number of attempts of the startup steps (mongo, rabbitmq, chain, public_ids, relayer, engine, http)
+	Boot map[string]int `mapstructure:"boot"`
+
+	Env string `mapstructure:"env"`
 }
 
 // LoadConfig loads configuration from the given list of paths and populates it into the Config variable.
+// The missing settings take their defaults and the configuration is validated, an error lists all the invalid settings.
 // The configuration file(s) should be named as app.yaml.
 // Environment variables with the prefix "RESTFUL_" in their names are also read automatically.
 func LoadConfig(configPath string, env string) error {
@@ -53,6 +270,10 @@ func LoadConfig(configPath string, env string) error {
 		v.SetConfigName("config." + env)
 	}
 
+	for key, value := range defaults {
+		v.SetDefault(key, value)
+	}
+
 	v.SetConfigType("yaml")
 	v.AddConfigPath(configPath)
 
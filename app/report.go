@@ -0,0 +1,83 @@
+package app
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// Parts of the setting names holding secrets, their values are never reported
+var secretKeys = []string{"password", "passphrase", "token", "key", "secret"}
+
+// Report returns the effective configuration, one "name = value" line per setting sorted by name.
+// The secrets and the credentials of the URLs are redacted
+func (config appConfig) Report() string {
+	lines := []string{}
+
+	v := reflect.ValueOf(config)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Tag.Get("mapstructure")
+		if name == "" {
+			continue
+		}
+
+		f := v.Field(i)
+		if f.Kind() != reflect.Map {
+			lines = append(lines, fmt.Sprintf("%s = %s", name, redact(name, f.Interface())))
+			continue
+		}
+
+		for _, k := range f.MapKeys() {
+			key := fmt.Sprintf("%s.%v", name, k.Interface())
+			lines = append(lines, fmt.Sprintf("%s = %s", key, redact(key, f.MapIndex(k).Interface())))
+		}
+	}
+
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+func redact(name string, value interface{}) string {
+	s := fmt.Sprintf("%v", value)
+	if s == "" || s == "[]" {
+		return s
+	}
+
+	lower := strings.ToLower(name)
+	for _, k := range secretKeys {
+		if strings.Contains(lower, k) {
+			return redacted
+		}
+	}
+
+	if strings.Contains(s, "@") {
+		return redactURL(s)
+	}
+
+	return s
+}
+
+// redactURL replaces the password of the credentials of a URL, the mongo urls without scheme included
+func redactURL(s string) string {
+	u, err := url.Parse(s)
+	if err == nil && u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), "xxxxx")
+			return strings.Replace(u.String(), "xxxxx", redacted, 1)
+		}
+
+		return s
+	}
+
+	i := strings.LastIndex(s, "@")
+	if j := strings.Index(s[:i], ":"); j >= 0 {
+		return s[:j+1] + redacted + s[i:]
+	}
+
+	return s
+}
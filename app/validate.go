@@ -0,0 +1,561 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-ozzo/ozzo-validation"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// Defaults of the settings missing from the configuration file
+var defaults = map[string]interface{}{
+	"error_file":    "config/errors.yaml",
+	"log_level":     "ERROR",
+	"server_port":   8080,
+	"max_chain_lag": 60,
+	"max_body_size": 1 << 20,
+}
+
+// Optional addresses of the tomochain section, exchange_address is required
+var tomochainAddresses = []string{
+	"exchange_contract_address",
+	"lending_contract_address",
+	"multicall_address",
+}
+
+// Contract ABIs which can be overridden
+var abiNames = []string{"relayer", "lending", "token"}
+
+// Validate checks the settings, all the invalid settings are reported at once by their setting names
+func (config appConfig) Validate() error {
+	err := validation.ValidateStruct(&config,
+		validation.Field(&config.ErrorFile, validation.Required),
+		validation.Field(&config.LogLevel, validation.By(isLogLevel)),
+		validation.Field(&config.ServerPort, validation.Required, validation.Min(1), validation.Max(65535)),
+		validation.Field(&config.MongoURL, validation.Required, validation.By(isMongoURL)),
+		validation.Field(&config.DBName, validation.Required),
+		validation.Field(&config.RabbitMQURL, validation.Required, validation.By(isURL("amqp", "amqps"))),
+		validation.Field(&config.Tomochain, validation.Required, validation.By(isTomochainConfig)),
+		validation.Field(&config.MaxChainLag, validation.Min(int64(0))),
+		validation.Field(&config.NodeID, validation.Min(0), validation.Max(1023)),
+		validation.Field(&config.TokenInfoTTL, validation.Min(0)),
+		validation.Field(&config.TokenInfoConcurrency, validation.Min(0)),
+		validation.Field(&config.TokenInfoBatchSize, validation.Min(0)),
+		validation.Field(&config.MaxBodySize, validation.Min(int64(0))),
+		validation.Field(&config.BodyLimits, validation.By(nonNegativeInt64s)),
+		validation.Field(&config.AuthGuard, validation.By(nonNegativeInts)),
+		validation.Field(&config.Admission, validation.By(nonNegativeInts)),
+		validation.Field(&config.Settlement, validation.By(nonNegativeInts)),
+		validation.Field(&config.Usage, validation.By(nonNegativeInts)),
+		validation.Field(&config.AccessLog, validation.By(nonNegativeInts)),
+		validation.Field(&config.TradeSampling, validation.By(nonNegativeInts)),
+		validation.Field(&config.OrderPriority, validation.By(nonNegativeInts)),
+		validation.Field(&config.LendingCollars, validation.By(nonNegativeInts)),
+		validation.Field(&config.IlliquidCollateral, validation.By(nonNegativeInts)),
+		validation.Field(&config.Listing, validation.By(nonNegativeInts)),
+		validation.Field(&config.Relayers, validation.By(areChecksumAddresses)),
+		validation.Field(&config.RelayerEvents, validation.By(nonNegativeInts)),
+		validation.Field(&config.ChainListener, validation.By(nonNegativeInts)),
+		validation.Field(&config.ReorgDepth, validation.Min(0)),
+		validation.Field(&config.RPCFallbackURLs, validation.By(areURLs("http", "https"))),
+		validation.Field(&config.RPCRetry, validation.By(nonNegativeInts)),
+		validation.Field(&config.DependencyMetrics, validation.By(nonNegativeInts)),
+		validation.Field(&config.ABIOverrides, validation.By(isABIOverrides)),
+		validation.Field(&config.Risk, validation.By(nonNegativeInts)),
+		validation.Field(&config.MarketData, validation.By(nonNegativeInts)),
+		validation.Field(&config.Microstructure, validation.By(nonNegativeInts)),
+		validation.Field(&config.DepthSnapshots, validation.By(nonNegativeInts)),
+		validation.Field(&config.AccountBatch, validation.By(nonNegativeInts)),
+		validation.Field(&config.DustThresholds, validation.By(positiveBigInts)),
+		validation.Field(&config.MakerRebates, validation.By(isMakerRebates)),
+		validation.Field(&config.Canary, validation.By(isCanaryConfig)),
+		validation.Field(&config.GasOracle, validation.By(isGasOracleConfig)),
+		validation.Field(&config.NonceManager, validation.By(nonNegativeInts)),
+		validation.Field(&config.Faucet, validation.By(isFaucetConfig)),
+		validation.Field(&config.Compliance, validation.By(isComplianceConfig)),
+		validation.Field(&config.GeoPolicy, validation.By(isGeoPolicyConfig)),
+		validation.Field(&config.KillSwitchSigners, validation.By(areChecksumAddresses)),
+		validation.Field(&config.IntegrityRecipients, validation.By(areChecksumAddresses)),
+		validation.Field(&config.ResponseSigning, validation.By(isResponseSigningConfig)),
+		validation.Field(&config.RelayerSigner, validation.By(isRelayerSignerConfig)),
+		validation.Field(&config.RelayerBranding, validation.By(isRelayerBrandingConfig)),
+		validation.Field(&config.AmountFormat, validation.In("wei", "decimal", "float")),
+		validation.Field(&config.TradeFeedPrivacy, validation.In("full", "truncated", "hidden")),
+		validation.Field(&config.Secrets, validation.By(isSecretsConfig)),
+		validation.Field(&config.Boot, validation.By(nonNegativeInts)),
+	)
+
+	errs, ok := err.(validation.Errors)
+	if !ok {
+		return err
+	}
+
+	named := validation.Errors{}
+	t := reflect.TypeOf(config)
+	for field, e := range errs {
+		if f, ok := t.FieldByName(field); ok && f.Tag.Get("mapstructure") != "" {
+			field = f.Tag.Get("mapstructure")
+		}
+
+		named[field] = e
+	}
+
+	return named
+}
+
+func isLogLevel(value interface{}) error {
+	switch strings.ToUpper(value.(string)) {
+	case "DEBUG", "INFO", "NOTICE", "WARNING", "ERROR", "CRITICAL":
+		return nil
+	default:
+		return errors.New("must be one of DEBUG, INFO, NOTICE, WARNING, ERROR, CRITICAL")
+	}
+}
+
+func isMongoURL(value interface{}) error {
+	hosts := mongoHosts(value.(string))
+	if len(hosts) == 0 {
+		return errors.New("must contain at least one host")
+	}
+
+	for _, h := range hosts {
+		_, port, err := net.SplitHostPort(h)
+		if err != nil {
+			return fmt.Errorf("invalid host %q", h)
+		}
+
+		if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+			return fmt.Errorf("invalid port in %q", h)
+		}
+	}
+
+	return nil
+}
+
+// mongoHosts returns the host:port list of a mongo url, with or without the mongodb:// scheme
+func mongoHosts(s string) []string {
+	s = strings.TrimPrefix(s, "mongodb://")
+	if i := strings.IndexAny(s, "/?"); i >= 0 {
+		s = s[:i]
+	}
+
+	if i := strings.LastIndex(s, "@"); i >= 0 {
+		s = s[i+1:]
+	}
+
+	hosts := []string{}
+	for _, h := range strings.Split(s, ",") {
+		if h == "" {
+			continue
+		}
+
+		if !strings.Contains(h, ":") {
+			h = h + ":27017"
+		}
+
+		hosts = append(hosts, h)
+	}
+
+	return hosts
+}
+
+func isURL(schemes ...string) validation.RuleFunc {
+	return func(value interface{}) error {
+		u, err := url.Parse(value.(string))
+		if err != nil || u.Host == "" {
+			return errors.New("must be a valid URL")
+		}
+
+		for _, s := range schemes {
+			if u.Scheme == s {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("must use one of the schemes %s", strings.Join(schemes, ", "))
+	}
+}
+
+func areURLs(schemes ...string) validation.RuleFunc {
+	return func(value interface{}) error {
+		for i, s := range value.([]string) {
+			if err := isURL(schemes...)(s); err != nil {
+				return fmt.Errorf("%d: %s", i, err)
+			}
+		}
+
+		return nil
+	}
+}
+
+func isTomochainConfig(value interface{}) error {
+	m := value.(map[string]string)
+
+	if err := isURL("http", "https")(m["http_url"]); err != nil {
+		return fmt.Errorf("http_url: %s", err)
+	}
+
+	if m["ws_url"] != "" {
+		if err := isURL("ws", "wss")(m["ws_url"]); err != nil {
+			return fmt.Errorf("ws_url: %s", err)
+		}
+	}
+
+	if m["tomoscan_url"] != "" {
+		if err := isURL("http", "https")(m["tomoscan_url"]); err != nil {
+			return fmt.Errorf("tomoscan_url: %s", err)
+		}
+	}
+
+	if err := isChecksumAddress(m["exchange_address"]); err != nil {
+		return fmt.Errorf("exchange_address: %s", err)
+	}
+
+	for _, k := range tomochainAddresses {
+		if m[k] == "" {
+			continue
+		}
+
+		if err := isChecksumAddress(m[k]); err != nil {
+			return fmt.Errorf("%s: %s", k, err)
+		}
+	}
+
+	return nil
+}
+
+// isChecksumAddress accepts the lower and upper case addresses and the mixed case ones
+// matching their EIP-55 checksum
+func isChecksumAddress(s string) error {
+	if !common.IsHexAddress(s) {
+		return errors.New("must be a valid address")
+	}
+
+	hex := strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	if hex == strings.ToLower(hex) || hex == strings.ToUpper(hex) {
+		return nil
+	}
+
+	if common.HexToAddress(s).Hex() != "0x"+hex {
+		return errors.New("invalid address checksum")
+	}
+
+	return nil
+}
+
+func areChecksumAddresses(value interface{}) error {
+	for i, s := range value.([]string) {
+		if err := isChecksumAddress(s); err != nil {
+			return fmt.Errorf("%d: %s", i, err)
+		}
+	}
+
+	return nil
+}
+
+func isSecretsConfig(value interface{}) error {
+	m := value.(map[string]string)
+	switch m["backend"] {
+	case "", "config":
+		return nil
+	case "vault":
+		if err := isURL("http", "https")(m["vault_addr"]); err != nil {
+			return fmt.Errorf("vault_addr: %s", err)
+		}
+
+		if m["vault_path"] == "" {
+			return errors.New("vault_path: cannot be blank")
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("unknown backend %q", m["backend"])
+	}
+}
+
+// canaryDurations are the settings of the canary in seconds or milliseconds
+var canaryDurations = []string{"interval", "timeout", "submit_ms", "ack_ms", "match_ms", "broadcast_ms"}
+
+func isCanaryConfig(value interface{}) error {
+	m := value.(map[string]string)
+	if m["wallet"] == "" {
+		return nil
+	}
+
+	for _, k := range []string{"wallet", "base_token", "quote_token"} {
+		if err := isChecksumAddress(m[k]); err != nil {
+			return fmt.Errorf("%s: %s", k, err)
+		}
+	}
+
+	for _, k := range []string{"price", "amount"} {
+		v, ok := new(big.Int).SetString(m[k], 10)
+		if !ok || v.Sign() <= 0 {
+			return fmt.Errorf("%s: must be a positive integer", k)
+		}
+	}
+
+	for _, k := range canaryDurations {
+		if m[k] == "" {
+			continue
+		}
+
+		if v, err := strconv.Atoi(m[k]); err != nil || v < 0 {
+			return fmt.Errorf("%s: must be no less than 0", k)
+		}
+	}
+
+	return nil
+}
+
+func isMakerRebates(value interface{}) error {
+	_, err := types.NewRebateSchedule(value.(map[string]string))
+	return err
+}
+
+// Percentile settings of the gas oracle
+var gasOraclePercentiles = []string{"safe", "standard", "fast"}
+
+func isGasOracleConfig(value interface{}) error {
+	m := value.(map[string]int)
+	if err := nonNegativeInts(m); err != nil {
+		return err
+	}
+
+	for _, k := range gasOraclePercentiles {
+		if m[k] > 100 {
+			return fmt.Errorf("%s: must be no greater than 100", k)
+		}
+	}
+
+	if m["safe"] > 0 && m["standard"] > 0 && m["safe"] > m["standard"] ||
+		m["standard"] > 0 && m["fast"] > 0 && m["standard"] > m["fast"] {
+		return errors.New("safe, standard and fast must be in increasing order")
+	}
+
+	return nil
+}
+
+// Integer settings of the faucet
+var faucetInts = []string{"interval", "requests", "window", "chain_id"}
+
+func isFaucetConfig(value interface{}) error {
+	m := value.(map[string]string)
+	if m["keystore"] == "" {
+		return nil
+	}
+
+	tokens := strings.Split(m["tokens"], ",")
+	if strings.TrimSpace(m["tokens"]) == "" {
+		return errors.New("tokens: cannot be blank")
+	}
+
+	for _, t := range tokens {
+		parts := strings.Split(strings.TrimSpace(t), ":")
+		if len(parts) != 2 {
+			return fmt.Errorf("tokens: %s must be token:amount", t)
+		}
+
+		if err := isChecksumAddress(parts[0]); err != nil {
+			return fmt.Errorf("tokens: %s %s", parts[0], err)
+		}
+
+		v, ok := new(big.Int).SetString(parts[1], 10)
+		if !ok || v.Sign() <= 0 {
+			return fmt.Errorf("tokens: %s must be a positive integer", parts[1])
+		}
+	}
+
+	for _, k := range faucetInts {
+		if m[k] == "" {
+			continue
+		}
+
+		if v, err := strconv.Atoi(m[k]); err != nil || v < 0 {
+			return fmt.Errorf("%s: must be no less than 0", k)
+		}
+	}
+
+	return nil
+}
+
+func isABIOverrides(value interface{}) error {
+	for name, path := range value.(map[string]string) {
+		if !containsString(abiNames, name) {
+			return fmt.Errorf("%s: must be one of %s", name, strings.Join(abiNames, ", "))
+		}
+
+		if path == "" {
+			return fmt.Errorf("%s: cannot be blank", name)
+		}
+	}
+
+	return nil
+}
+
+func isResponseSigningConfig(value interface{}) error {
+	m := value.(map[string]string)
+
+	switch m["enabled"] {
+	case "", "true", "false":
+	default:
+		return errors.New("enabled: must be true or false")
+	}
+
+	if m["wallet"] != "" {
+		if err := isChecksumAddress(m["wallet"]); err != nil {
+			return fmt.Errorf("wallet: %s", err)
+		}
+	}
+
+	return nil
+}
+
+func isRelayerSignerConfig(value interface{}) error {
+	m := value.(map[string]string)
+
+	switch m["backend"] {
+	case "", "key", "ledger", "trezor":
+	case "keystore":
+		if m["keystore"] == "" {
+			return errors.New("keystore: cannot be blank")
+		}
+	default:
+		return errors.New("backend: must be one of key, keystore, ledger, trezor")
+	}
+
+	for _, path := range strings.Split(m["derivation_path"], ",") {
+		if path = strings.TrimSpace(path); path == "" {
+			continue
+		}
+
+		if _, err := accounts.ParseDerivationPath(path); err != nil {
+			return fmt.Errorf("derivation_path: %s", err)
+		}
+	}
+
+	if m["chain_id"] != "" {
+		if id, err := strconv.ParseUint(m["chain_id"], 10, 64); err != nil || id == 0 {
+			return errors.New("chain_id: must be a positive integer")
+		}
+	}
+
+	return nil
+}
+
+func isRelayerBrandingConfig(value interface{}) error {
+	m := value.(map[string]string)
+
+	for k, v := range m {
+		if !strings.HasSuffix(k, "_url") || v == "" {
+			continue
+		}
+
+		if err := isURL("http", "https")(v); err != nil {
+			return fmt.Errorf("%s: %s", k, err)
+		}
+	}
+
+	if e := m["support_email"]; e != "" && !strings.Contains(e, "@") {
+		return errors.New("support_email: must be a valid email address")
+	}
+
+	return nil
+}
+
+func isComplianceConfig(value interface{}) error {
+	m := value.(map[string]string)
+
+	switch m["provider"] {
+	case "", "allowlist":
+	case "http":
+		if err := isURL("http", "https")(m["url"]); err != nil {
+			return fmt.Errorf("url: %s", err)
+		}
+	default:
+		return errors.New("provider: must be one of allowlist, http")
+	}
+
+	for _, a := range strings.Split(m["addresses"], ",") {
+		if a = strings.TrimSpace(a); a != "" && !common.IsHexAddress(a) {
+			return fmt.Errorf("addresses: invalid address %s", a)
+		}
+	}
+
+	return nil
+}
+
+func isGeoPolicyConfig(value interface{}) error {
+	m := value.(map[string]string)
+
+	for _, s := range strings.Split(m["blocked_ips"], ",") {
+		s = strings.TrimSpace(s)
+		if s == "" || net.ParseIP(s) != nil {
+			continue
+		}
+
+		if _, _, err := net.ParseCIDR(s); err != nil {
+			return fmt.Errorf("blocked_ips: invalid IP or network %s", s)
+		}
+	}
+
+	for _, k := range []string{"blocked_countries", "restricted_countries"} {
+		for _, c := range strings.Split(m[k], ",") {
+			if c = strings.TrimSpace(c); c != "" && len(c) != 2 {
+				return fmt.Errorf("%s: invalid country code %s", k, c)
+			}
+		}
+	}
+
+	return nil
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+func nonNegativeInts(value interface{}) error {
+	for k, v := range value.(map[string]int) {
+		if v < 0 {
+			return fmt.Errorf("%s: must be no less than 0", k)
+		}
+	}
+
+	return nil
+}
+
+func positiveBigInts(value interface{}) error {
+	for k, v := range value.(map[string]string) {
+		n, ok := new(big.Int).SetString(v, 10)
+		if !ok || n.Sign() <= 0 {
+			return fmt.Errorf("%s: must be a positive integer", k)
+		}
+	}
+
+	return nil
+}
+
+func nonNegativeInt64s(value interface{}) error {
+	for k, v := range value.(map[string]int64) {
+		if v < 0 {
+			return fmt.Errorf("%s: must be no less than 0", k)
+		}
+	}
+
+	return nil
+}
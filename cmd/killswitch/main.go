@@ -0,0 +1,95 @@
+// Command killswitch activates or releases the kill switch of a running SDK with a request signed by
+// the private key of one of its kill_switch_signers, without going through the admin API keys.
+//
+//	KILL_SWITCH_KEY=<private key> killswitch -reason "matching bug" -cancel
+//	KILL_SWITCH_KEY=<private key> killswitch -base 0x... -quote 0x... -reason "oracle outage"
+//	KILL_SWITCH_KEY=<private key> killswitch -release 5c7e...
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+func main() {
+	url := flag.String("url", "http://localhost:8080", "url of the SDK")
+	key := flag.String("key", os.Getenv("KILL_SWITCH_KEY"), "hex private key of the signer, KILL_SWITCH_KEY by default")
+	base := flag.String("base", "", "base token of the halted pair, all the pairs are halted without it")
+	quote := flag.String("quote", "", "quote token of the halted pair")
+	cancel := flag.Bool("cancel", false, "cancel the resting orders of the halted pairs")
+	reason := flag.String("reason", "", "reason of the halt, recorded with the activation")
+	release := flag.String("release", "", "id of the kill switch to release")
+	flag.Parse()
+
+	if *key == "" {
+		exit("the private key of the signer is required")
+	}
+
+	req := &types.SignedKillSwitchRequest{Timestamp: time.Now().Unix()}
+	if *release != "" {
+		req.Action = types.KillSwitchActionRelease
+		req.ID = *release
+	} else {
+		if (*base != "" && !common.IsHexAddress(*base)) || (*quote != "" && !common.IsHexAddress(*quote)) {
+			exit("invalid base or quote token")
+		}
+
+		req.Action = types.KillSwitchActionActivate
+		req.Payload = &types.KillSwitchPayload{
+			BaseToken:    common.HexToAddress(*base),
+			QuoteToken:   common.HexToAddress(*quote),
+			CancelOrders: *cancel,
+			Reason:       *reason,
+		}
+
+		err := req.Payload.Validate()
+		if err != nil {
+			exit(err.Error())
+		}
+	}
+
+	_, err := crypto.HexToECDSA(strings.TrimPrefix(*key, "0x"))
+	if err != nil {
+		exit("invalid private key")
+	}
+
+	err = req.Sign(types.NewWalletFromPrivateKey(strings.TrimPrefix(*key, "0x")))
+	if err != nil {
+		exit(err.Error())
+	}
+
+	b, err := json.Marshal(req)
+	if err != nil {
+		exit(err.Error())
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	res, err := client.Post(strings.TrimSuffix(*url, "/")+"/api/kill-switch/signed", "application/json", bytes.NewReader(b))
+	if err != nil {
+		exit(err.Error())
+	}
+	defer res.Body.Close()
+
+	body, _ := ioutil.ReadAll(res.Body)
+	fmt.Println(string(body))
+
+	if res.StatusCode >= 300 {
+		os.Exit(1)
+	}
+}
+
+func exit(msg string) {
+	fmt.Fprintln(os.Stderr, "killswitch:", msg)
+	os.Exit(2)
+}
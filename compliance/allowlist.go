@@ -0,0 +1,61 @@
+package compliance
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Allowlist allows the actions of a fixed set of addresses
+type Allowlist struct {
+	addresses map[common.Address]bool
+}
+
+// NewAllowlist returns a new instance of Allowlist
+func NewAllowlist(addresses ...common.Address) *Allowlist {
+	l := &Allowlist{addresses: make(map[common.Address]bool)}
+	for _, a := range addresses {
+		l.addresses[a] = true
+	}
+
+	return l
+}
+
+// LoadAllowlist reads an allowlist from a file holding an address per line,
+// the blank lines and the lines starting with # are skipped
+func LoadAllowlist(path string) (*Allowlist, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	addresses := []common.Address{}
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if !common.IsHexAddress(line) {
+			return nil, fmt.Errorf("compliance: invalid address on line %d of %s", n, path)
+		}
+
+		addresses = append(addresses, common.HexToAddress(line))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return NewAllowlist(addresses...), nil
+}
+
+// Allowed returns true if the address is listed, for all the actions
+func (l *Allowlist) Allowed(action string, addr common.Address) (bool, error) {
+	return l.addresses[addr], nil
+}
@@ -0,0 +1,98 @@
+// Package compliance gates the trading actions of the users behind a KYC service or an allowlist.
+//
+// A Gate asks its Checker whether an address may run an action, on the pairs the gate enforces.
+// The checker is either a local allowlist or an external service answering over HTTP. The gate
+// is optional, a nil Gate lets every action through.
+package compliance
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/utils"
+)
+
+var logger = utils.Logger
+
+// Actions checked by the gate
+const (
+	ActionOrder        = "order"
+	ActionLendingOrder = "lending_order"
+)
+
+// ErrNotAllowed is returned when the checker refuses an action of an address
+var ErrNotAllowed = errors.New("Address is not allowed to trade, complete the KYC of the operator")
+
+// Checker decides whether an address may run an action
+type Checker interface {
+	Allowed(action string, addr common.Address) (bool, error)
+}
+
+// Gate checks the actions of the addresses on the enforced pairs. All the pairs are enforced when
+// none is set, the lending orders unless lending is false. The actions are refused when the checker
+// fails, unless the gate fails open
+type Gate struct {
+	checker  Checker
+	pairs    map[string]bool
+	lending  bool
+	failOpen bool
+}
+
+// NewGate returns a new instance of Gate enforcing the pair names ("TOMO/USDT")
+func NewGate(checker Checker, pairs []string, lending, failOpen bool) *Gate {
+	g := &Gate{
+		checker:  checker,
+		pairs:    make(map[string]bool),
+		lending:  lending,
+		failOpen: failOpen,
+	}
+
+	for _, p := range pairs {
+		if p = strings.TrimSpace(p); p != "" {
+			g.pairs[strings.ToUpper(p)] = true
+		}
+	}
+
+	return g
+}
+
+// CheckOrder returns ErrNotAllowed if the address cannot place orders on the pair
+func (g *Gate) CheckOrder(addr common.Address, pairName string) error {
+	if g == nil {
+		return nil
+	}
+
+	if len(g.pairs) > 0 && !g.pairs[strings.ToUpper(pairName)] {
+		return nil
+	}
+
+	return g.check(ActionOrder, addr)
+}
+
+// CheckLendingOrder returns ErrNotAllowed if the address cannot place lending orders
+func (g *Gate) CheckLendingOrder(addr common.Address) error {
+	if g == nil || !g.lending {
+		return nil
+	}
+
+	return g.check(ActionLendingOrder, addr)
+}
+
+func (g *Gate) check(action string, addr common.Address) error {
+	ok, err := g.checker.Allowed(action, addr)
+	if err != nil {
+		logger.Error("Compliance check failed:", addr.Hex(), err)
+		if g.failOpen {
+			return nil
+		}
+
+		return err
+	}
+
+	if !ok {
+		return ErrNotAllowed
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package compliance
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	alice = common.HexToAddress("0x0000000000000000000000000000000000000001")
+	bob   = common.HexToAddress("0x0000000000000000000000000000000000000002")
+)
+
+type failingChecker struct{}
+
+func (failingChecker) Allowed(action string, addr common.Address) (bool, error) {
+	return false, errors.New("unavailable")
+}
+
+func TestGate(t *testing.T) {
+	var g *Gate
+	assert.NoError(t, g.CheckOrder(bob, "TOMO/USDT"))
+
+	g = NewGate(NewAllowlist(alice), []string{"tomo/usdt"}, false, false)
+	assert.NoError(t, g.CheckOrder(alice, "TOMO/USDT"))
+	assert.Equal(t, ErrNotAllowed, g.CheckOrder(bob, "TOMO/USDT"))
+	assert.NoError(t, g.CheckOrder(bob, "BTC/USDT"))
+	assert.NoError(t, g.CheckLendingOrder(bob))
+
+	g = NewGate(NewAllowlist(alice), nil, true, false)
+	assert.Equal(t, ErrNotAllowed, g.CheckOrder(bob, "BTC/USDT"))
+	assert.Equal(t, ErrNotAllowed, g.CheckLendingOrder(bob))
+
+	assert.Error(t, NewGate(failingChecker{}, nil, true, false).CheckOrder(alice, "TOMO/USDT"))
+	assert.NoError(t, NewGate(failingChecker{}, nil, true, true).CheckOrder(alice, "TOMO/USDT"))
+}
+
+func TestLoadAllowlist(t *testing.T) {
+	f, err := ioutil.TempFile("", "allowlist")
+	assert.NoError(t, err)
+	defer os.Remove(f.Name())
+
+	f.WriteString("# KYC done\n0x0000000000000000000000000000000000000001\n\n")
+	f.Close()
+
+	l, err := LoadAllowlist(f.Name())
+	assert.NoError(t, err)
+
+	ok, _ := l.Allowed(ActionOrder, alice)
+	assert.True(t, ok)
+
+	ok, _ = l.Allowed(ActionOrder, bob)
+	assert.False(t, ok)
+}
+
+func TestHTTPChecker(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		assert.Equal(t, ActionOrder, r.URL.Query().Get("action"))
+
+		if r.URL.Query().Get("address") == alice.Hex() {
+			w.Write([]byte(`{"allowed": true}`))
+			return
+		}
+
+		w.Write([]byte(`{"allowed": false}`))
+	}))
+	defer server.Close()
+
+	c := NewHTTPChecker(server.URL, "secret", time.Second, time.Minute)
+
+	ok, err := c.Allowed(ActionOrder, alice)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = c.Allowed(ActionOrder, bob)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	c.Allowed(ActionOrder, alice)
+	assert.Equal(t, 2, calls)
+}
+
+func TestNewGateFromConfig(t *testing.T) {
+	g, err := NewGateFromConfig(map[string]string{})
+	assert.NoError(t, err)
+	assert.Nil(t, g)
+
+	g, err = NewGateFromConfig(map[string]string{"provider": "allowlist", "addresses": alice.Hex(), "lending": "false"})
+	assert.NoError(t, err)
+	assert.NoError(t, g.CheckOrder(alice, "TOMO/USDT"))
+	assert.NoError(t, g.CheckLendingOrder(bob))
+
+	_, err = NewGateFromConfig(map[string]string{"provider": "http"})
+	assert.Error(t, err)
+
+	_, err = NewGateFromConfig(map[string]string{"provider": "ldap"})
+	assert.Error(t, err)
+}
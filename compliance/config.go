@@ -0,0 +1,88 @@
+package compliance
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Providers supported by NewGateFromConfig
+const (
+	ProviderAllowlist = "allowlist"
+	ProviderHTTP      = "http"
+)
+
+// Defaults of the http provider
+const (
+	defaultTimeout  = 5 * time.Second
+	defaultCacheTTL = 5 * time.Minute
+)
+
+// NewGateFromConfig builds a Gate from the "compliance" section of the configuration, nil if no
+// provider is set. The allowlist provider reads the comma separated "addresses" and the
+// "allowlist_file", the http provider calls "url" with "token" waiting "timeout_ms" and caches the
+// answers "cache_ttl" seconds. "pairs" lists the enforced pairs, all of them by default,
+// "lending" false does not check the lending orders and "fail_open" true lets the actions through
+// when the provider fails
+func NewGateFromConfig(cfg map[string]string) (*Gate, error) {
+	var checker Checker
+
+	switch cfg["provider"] {
+	case "":
+		return nil, nil
+	case ProviderAllowlist:
+		l := NewAllowlist()
+		if cfg["allowlist_file"] != "" {
+			var err error
+			l, err = LoadAllowlist(cfg["allowlist_file"])
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for _, a := range splitList(cfg["addresses"]) {
+			if !common.IsHexAddress(a) {
+				return nil, fmt.Errorf("compliance: invalid address %s", a)
+			}
+
+			l.addresses[common.HexToAddress(a)] = true
+		}
+
+		checker = l
+	case ProviderHTTP:
+		if cfg["url"] == "" {
+			return nil, fmt.Errorf("compliance: url is required by the http provider")
+		}
+
+		timeout := durationSetting(cfg["timeout_ms"], time.Millisecond, defaultTimeout)
+		ttl := durationSetting(cfg["cache_ttl"], time.Second, defaultCacheTTL)
+		checker = NewHTTPChecker(cfg["url"], cfg["token"], timeout, ttl)
+	default:
+		return nil, fmt.Errorf("compliance: unknown provider %s", cfg["provider"])
+	}
+
+	return NewGate(checker, splitList(cfg["pairs"]), cfg["lending"] != "false", cfg["fail_open"] == "true"), nil
+}
+
+func splitList(s string) []string {
+	res := []string{}
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			res = append(res, v)
+		}
+	}
+
+	return res
+}
+
+func durationSetting(s string, unit, def time.Duration) time.Duration {
+	v, err := strconv.Atoi(s)
+	if err != nil || v <= 0 {
+		return def
+	}
+
+	return time.Duration(v) * unit
+}
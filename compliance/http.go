@@ -0,0 +1,99 @@
+package compliance
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type decision struct {
+	allowed bool
+	expires time.Time
+}
+
+// HTTPChecker asks an external KYC service whether an address may run an action with a
+// GET {url}?address=0x...&action=order answered by {"allowed": true}. The answers are cached
+type HTTPChecker struct {
+	url   string
+	token string
+	ttl   time.Duration
+	http  *http.Client
+	cache map[string]*decision
+	mutex sync.Mutex
+}
+
+// NewHTTPChecker returns a new instance of HTTPChecker, the token is sent as a bearer token if set
+func NewHTTPChecker(serviceURL, token string, timeout, ttl time.Duration) *HTTPChecker {
+	return &HTTPChecker{
+		url:   serviceURL,
+		token: token,
+		ttl:   ttl,
+		http:  &http.Client{Timeout: timeout},
+		cache: make(map[string]*decision),
+	}
+}
+
+// Allowed returns the answer of the service, from the cache if it is recent
+func (c *HTTPChecker) Allowed(action string, addr common.Address) (bool, error) {
+	key := action + ":" + addr.Hex()
+
+	c.mutex.Lock()
+	d := c.cache[key]
+	c.mutex.Unlock()
+
+	if d != nil && time.Now().Before(d.expires) {
+		return d.allowed, nil
+	}
+
+	allowed, err := c.ask(action, addr)
+	if err != nil {
+		return false, err
+	}
+
+	c.mutex.Lock()
+	c.cache[key] = &decision{allowed, time.Now().Add(c.ttl)}
+	c.mutex.Unlock()
+
+	return allowed, nil
+}
+
+func (c *HTTPChecker) ask(action string, addr common.Address) (bool, error) {
+	q := url.Values{}
+	q.Set("address", addr.Hex())
+	q.Set("action", action)
+
+	req, err := http.NewRequest("GET", c.url+"?"+q.Encode(), nil)
+	if err != nil {
+		return false, err
+	}
+
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("compliance: service answered %d", res.StatusCode)
+	}
+
+	var body struct {
+		Allowed bool `json:"allowed"`
+	}
+
+	err = json.NewDecoder(res.Body).Decode(&body)
+	if err != nil {
+		return false, err
+	}
+
+	return body.Allowed, nil
+}
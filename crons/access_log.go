@@ -0,0 +1,10 @@
+package crons
+
+// startAccessLogPurgeCron anonymizes and removes the expired access logs every hour
+func (s *CronService) startAccessLogPurgeCron() {
+	if !s.accessLogService.Enabled() {
+		return
+	}
+
+	s.addJob("access_log_purge", "0 15 * * * *", s.accessLogService.Purge)
+}
@@ -0,0 +1,6 @@
+package crons
+
+// startBalanceSnapshotCron stores the balances of the accounts every hour
+func (s *CronService) startBalanceSnapshotCron() {
+	s.addJob("balance_snapshot", "0 30 * * * *", s.balanceHistoryService.Snapshot)
+}
@@ -0,0 +1,14 @@
+package crons
+
+import (
+	"fmt"
+)
+
+// startCanaryCron runs the synthetic order canary at the interval of the "canary" config section
+func (s *CronService) startCanaryCron() {
+	if !s.canaryService.Enabled() {
+		return
+	}
+
+	s.addJob("canary", fmt.Sprintf("@every %s", s.canaryService.Interval()), s.canaryService.Probe)
+}
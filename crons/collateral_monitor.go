@@ -0,0 +1,6 @@
+package crons
+
+// startCollateralMonitorCron checks the liquidity of the collateral of the open loans every 5 minutes
+func (s *CronService) startCollateralMonitorCron() {
+	s.addJob("collateral_monitor", "0 */5 * * * *", s.collateralMonitor.Check)
+}
@@ -0,0 +1,6 @@
+package crons
+
+// startCompositeIndexCron streams the values of the composite indices at the cadence of the price board
+func (s *CronService) startCompositeIndexCron() {
+	s.addJob("composite_indices", "*/3 * * * * *", s.compositeIndexService.Broadcast)
+}
@@ -0,0 +1,11 @@
+package crons
+
+// startContractVerificationCron checks hourly on Tomoscan whether the contracts of the tokens are verified,
+// the status of a contract is cached for a day
+func (s *CronService) startContractVerificationCron() {
+	if !s.contractVerificationService.Enabled() {
+		return
+	}
+
+	s.addJob("contract_verification", "@every 1h", s.contractVerificationService.Refresh)
+}
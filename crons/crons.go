@@ -1,21 +1,37 @@
 package crons
 
 import (
-	"github.com/robfig/cron"
+	"log"
+
 	"github.com/tomochain/tomox-sdk/engine"
 	"github.com/tomochain/tomox-sdk/services"
 )
 
 // CronService contains the services required to initialize crons
 type CronService struct {
-	OHLCVService             *services.OHLCVService
-	PriceBoardService        *services.PriceBoardService
-	PairService              *services.PairService
-	RelayService             *services.RelayerService
-	Engine                   *engine.Engine
-	lendingPriceBoardService *services.LendingPriceBoardService
-	lendingPairService       *services.LendingPairService
-	lendingOhlcvService      *services.LendingOhlcvService
+	OHLCVService                *services.OHLCVService
+	PriceBoardService           *services.PriceBoardService
+	PairService                 *services.PairService
+	RelayService                *services.RelayerService
+	Engine                      *engine.Engine
+	lendingPriceBoardService    *services.LendingPriceBoardService
+	lendingPairService          *services.LendingPairService
+	lendingOhlcvService         *services.LendingOhlcvService
+	loanMaturityService         *services.LoanMaturityService
+	interestAccrualService      *services.InterestAccrualService
+	collateralMonitor           *services.CollateralMonitorService
+	reportService               *services.ReportService
+	balanceHistoryService       *services.BalanceHistoryService
+	canaryService               *services.CanaryService
+	contractVerificationService *services.ContractVerificationService
+	tokenSafetyService          *services.TokenSafetyService
+	accessLogService            *services.AccessLogService
+	integrityService            *services.IntegrityService
+	microstructureService       *services.MicrostructureService
+	compositeIndexService       *services.CompositeIndexService
+	lendingRateService          *services.LendingRateService
+	depthSnapshotService        *services.DepthSnapshotService
+	scheduler                   *Scheduler
 }
 
 // NewCronService returns a new instance of CronService
@@ -28,29 +44,80 @@ func NewCronService(
 	lendingPriceBoardService *services.LendingPriceBoardService,
 	lendingPairService *services.LendingPairService,
 	lendingOhlcvService *services.LendingOhlcvService,
+	loanMaturityService *services.LoanMaturityService,
+	interestAccrualService *services.InterestAccrualService,
+	collateralMonitor *services.CollateralMonitorService,
+	reportService *services.ReportService,
+	balanceHistoryService *services.BalanceHistoryService,
+	canaryService *services.CanaryService,
+	contractVerificationService *services.ContractVerificationService,
+	tokenSafetyService *services.TokenSafetyService,
+	accessLogService *services.AccessLogService,
+	integrityService *services.IntegrityService,
+	microstructureService *services.MicrostructureService,
+	compositeIndexService *services.CompositeIndexService,
+	lendingRateService *services.LendingRateService,
+	depthSnapshotService *services.DepthSnapshotService,
+	scheduler *Scheduler,
 ) *CronService {
 	return &CronService{
-		OHLCVService:             ohlcvService,
-		PriceBoardService:        priceBoardService,
-		PairService:              pairService,
-		RelayService:             relayService,
-		Engine:                   engine,
-		lendingPriceBoardService: lendingPriceBoardService,
-		lendingPairService:       lendingPairService,
-		lendingOhlcvService:      lendingOhlcvService,
+		OHLCVService:                ohlcvService,
+		PriceBoardService:           priceBoardService,
+		PairService:                 pairService,
+		RelayService:                relayService,
+		Engine:                      engine,
+		lendingPriceBoardService:    lendingPriceBoardService,
+		lendingPairService:          lendingPairService,
+		lendingOhlcvService:         lendingOhlcvService,
+		loanMaturityService:         loanMaturityService,
+		interestAccrualService:      interestAccrualService,
+		collateralMonitor:           collateralMonitor,
+		reportService:               reportService,
+		balanceHistoryService:       balanceHistoryService,
+		canaryService:               canaryService,
+		contractVerificationService: contractVerificationService,
+		tokenSafetyService:          tokenSafetyService,
+		accessLogService:            accessLogService,
+		integrityService:            integrityService,
+		microstructureService:       microstructureService,
+		compositeIndexService:       compositeIndexService,
+		lendingRateService:          lendingRateService,
+		depthSnapshotService:        depthSnapshotService,
+		scheduler:                   scheduler,
 	}
 }
 
 // InitCrons is responsible for initializing all the crons in the system
 func (s *CronService) InitCrons() {
+	s.startRelayerUpdate()
+	s.startRelayerFeeWatcher()
+	// s.tickStreamingCron()   // Cron to fetch OHLCV data
+	s.startPriceBoardCron() // Cron to fetch data for top price board
+	s.startMarketsCron()    // Cron to fetch markets data
+	s.startOHLCVBackfillCron()
+	s.startLendingPriceBoardCron()
+	s.startLendingMarketsCron()
+	s.startLoanMaturityCron()
+	s.startInterestAccrualCron()
+	s.startCollateralMonitorCron()
+	s.startDailyStatsCron()
+	s.startBalanceSnapshotCron()
+	s.startCanaryCron()
+	s.startContractVerificationCron()
+	s.startTokenSafetyCron()
+	s.startAccessLogPurgeCron()
+	s.startIntegrityCron()
+	s.startMicrostructureCron()
+	s.startCompositeIndexCron()
+	s.startLendingRateSnapshotCron()
+	s.startDepthSnapshotCron()
+	s.scheduler.Start()
+}
 
-	s.RelayService.UpdateRelayers()
-	c := cron.New()
-	s.startRelayerUpdate(c)
-	// s.tickStreamingCron(c)   // Cron to fetch OHLCV data
-	s.startPriceBoardCron(c) // Cron to fetch data for top price board
-	s.startMarketsCron(c)    // Cron to fetch markets data
-	s.startLendingPriceBoardCron(c)
-	s.startLendingMarketsCron(c)
-	c.Start()
+// addJob registers a job in the scheduler, an invalid spec is logged and the job is not run
+func (s *CronService) addJob(name, spec string, fn func() error) {
+	err := s.scheduler.Add(name, spec, fn)
+	if err != nil {
+		log.Printf("%s", err)
+	}
 }
@@ -0,0 +1,33 @@
+package crons
+
+import (
+	"time"
+)
+
+// startDailyStatsCron refreshes the daily aggregates of the trades of the current day every 10 minutes.
+// The aggregates of the previous day are finalized on the first run of a new day
+func (s *CronService) startDailyStatsCron() {
+	s.addJob("daily_stats", "0 */10 * * * *", s.refreshDailyStats())
+}
+
+func (s *CronService) refreshDailyStats() func() error {
+	var lastDay int
+
+	return func() error {
+		now := time.Now().UTC()
+		if now.YearDay() != lastDay {
+			err := s.reportService.Refresh(now.AddDate(0, 0, -1))
+			if err != nil {
+				return err
+			}
+		}
+
+		err := s.reportService.Refresh(now)
+		if err != nil {
+			return err
+		}
+
+		lastDay = now.YearDay()
+		return nil
+	}
+}
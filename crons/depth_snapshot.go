@@ -0,0 +1,6 @@
+package crons
+
+// startDepthSnapshotCron stores the top levels of the order books of the active pairs every hour
+func (s *CronService) startDepthSnapshotCron() {
+	s.addJob("depth_snapshot", "0 0 * * * *", s.depthSnapshotService.Snapshot)
+}
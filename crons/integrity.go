@@ -0,0 +1,6 @@
+package crons
+
+// startIntegrityCron cross-checks the orders, trades and OHLCV ticks of the previous day every night
+func (s *CronService) startIntegrityCron() {
+	s.addJob("integrity", "0 30 0 * * *", s.integrityService.CheckAll)
+}
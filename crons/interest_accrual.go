@@ -0,0 +1,16 @@
+package crons
+
+import (
+	"time"
+)
+
+// startInterestAccrualCron stores the interest accruals of the previous day every day after midnight
+func (s *CronService) startInterestAccrualCron() {
+	s.addJob("interest_accrual", "0 5 0 * * *", s.snapshotInterestAccruals())
+}
+
+func (s *CronService) snapshotInterestAccruals() func() error {
+	return func() error {
+		return s.interestAccrualService.Snapshot(time.Now().UTC().AddDate(0, 0, -1))
+	}
+}
@@ -0,0 +1,6 @@
+package crons
+
+// startLendingRateSnapshotCron stores the utilization and the borrow rate of the lending tokens every 10 minutes
+func (s *CronService) startLendingRateSnapshotCron() {
+	s.addJob("lending_rate_snapshot", "0 */10 * * * *", s.lendingRateService.Snapshot)
+}
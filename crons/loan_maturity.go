@@ -0,0 +1,28 @@
+package crons
+
+import (
+	"time"
+)
+
+// startLoanMaturityCron sends the loan maturity reminders every minute
+func (s *CronService) startLoanMaturityCron() {
+	s.addJob("loan_maturity", "0 * * * * *", s.sendLoanMaturityReminders())
+}
+
+// sendLoanMaturityReminders sends the reminders due since its previous run,
+// so that a slow run does not skip the loans maturing meanwhile
+func (s *CronService) sendLoanMaturityReminders() func() error {
+	last := time.Now()
+
+	return func() error {
+		now := time.Now()
+
+		err := s.loanMaturityService.SendReminders(last, now)
+		if err != nil {
+			return err
+		}
+
+		last = now
+		return nil
+	}
+}
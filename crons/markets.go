@@ -1,9 +1,6 @@
 package crons
 
 import (
-	"log"
-
-	"github.com/robfig/cron"
 	"github.com/tomochain/tomox-sdk/types"
 	"github.com/tomochain/tomox-sdk/utils"
 	"github.com/tomochain/tomox-sdk/ws"
@@ -11,19 +8,17 @@ import (
 
 // tickStreamingCron takes instance of cron.Cron and adds tickStreaming
 // crons according to the durations mentioned in config/app.yaml file
-func (s *CronService) startMarketsCron(c *cron.Cron) {
-	c.AddFunc("*/3 * * * * *", s.getMarketsData())
+func (s *CronService) startMarketsCron() {
+	s.addJob("markets", "*/3 * * * * *", s.getMarketsData())
 }
 
 // tickStream function fetches latest tick based on unit and duration for each pair
 // and broadcasts the tick to the client subscribed to pair's respective channel
-func (s *CronService) getMarketsData() func() {
-	return func() {
+func (s *CronService) getMarketsData() func() error {
+	return func() error {
 		pairData, err := s.OHLCVService.GetAllTokenPairData()
-
 		if err != nil {
-			log.Printf("%s", err)
-			return
+			return err
 		}
 
 		smallChartsDataResult, err := s.OHLCVService.GetFiatPriceChart()
@@ -36,5 +31,6 @@ func (s *CronService) getMarketsData() func() {
 		id := utils.GetMarketsChannelID(ws.MarketsChannel)
 
 		ws.GetMarketSocket().BroadcastMessage(id, res)
+		return nil
 	}
 }
@@ -0,0 +1,10 @@
+package crons
+
+import (
+	"fmt"
+)
+
+// startMicrostructureCron streams the microstructure metrics at the interval of the "microstructure" config section
+func (s *CronService) startMicrostructureCron() {
+	s.addJob("microstructure", fmt.Sprintf("@every %s", s.microstructureService.Interval()), s.microstructureService.Broadcast)
+}
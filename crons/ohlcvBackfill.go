@@ -0,0 +1,13 @@
+package crons
+
+// startOHLCVBackfillCron fills the gaps of the cached OHLCV series every hour
+func (s *CronService) startOHLCVBackfillCron() {
+	s.addJob("ohlcv_backfill", "0 0 * * * *", s.backfillOHLCV())
+}
+
+func (s *CronService) backfillOHLCV() func() error {
+	return func() error {
+		s.OHLCVService.BackfillGaps()
+		return nil
+	}
+}
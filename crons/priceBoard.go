@@ -1,11 +1,9 @@
 package crons
 
 import (
-	"log"
 	"math/big"
 	"time"
 
-	"github.com/robfig/cron"
 	"github.com/tomochain/tomox-sdk/types"
 	"github.com/tomochain/tomox-sdk/utils"
 	"github.com/tomochain/tomox-sdk/ws"
@@ -13,18 +11,17 @@ import (
 
 // tickStreamingCron takes instance of cron.Cron and adds tickStreaming
 // crons according to the durations mentioned in config/app.yaml file
-func (s *CronService) startPriceBoardCron(c *cron.Cron) {
-	c.AddFunc("*/3 * * * * *", s.getPriceBoardData())
+func (s *CronService) startPriceBoardCron() {
+	s.addJob("price_board", "*/3 * * * * *", s.getPriceBoardData())
 }
 
 // tickStream function fetches latest tick based on unit and duration for each pair
 // and broadcasts the tick to the client subscribed to pair's respective channel
-func (s *CronService) getPriceBoardData() func() {
-	return func() {
+func (s *CronService) getPriceBoardData() func() error {
+	return func() error {
 		pairs, err := s.PairService.GetAll()
-
 		if err != nil {
-			log.Println(err.Error())
+			return err
 		}
 
 		for _, p := range pairs {
@@ -42,15 +39,13 @@ func (s *CronService) getPriceBoardData() func() {
 
 			ticks, err := s.PriceBoardService.GetPriceBoardData(p, duration, unit)
 			if err != nil {
-				log.Printf("%s", err)
-				return
+				return err
 			}
 
 			quoteToken, err := s.PriceBoardService.TokenDao.GetByAddress(qt)
 
 			if err != nil {
-				log.Printf("%s", err)
-				return
+				return err
 			}
 
 			var lastTradePrice string
@@ -62,8 +57,7 @@ func (s *CronService) getPriceBoardData() func() {
 			}
 
 			if err != nil {
-				log.Printf("%s", err)
-				return
+				return err
 			}
 
 			id := utils.GetPriceBoardChannelID(bt, qt)
@@ -80,5 +74,7 @@ func (s *CronService) getPriceBoardData() func() {
 
 			ws.GetPriceBoardSocket().BroadcastMessage(id, result)
 		}
+
+		return nil
 	}
 }
@@ -1,16 +1,13 @@
 package crons
 
-import (
-	"github.com/robfig/cron"
-)
-
-func (s *CronService) startRelayerUpdate(c *cron.Cron) {
-	s.RelayService.UpdateRelayers()
-	c.AddFunc("*/600 * * * * *", s.updateRelayer())
-}
-
-func (s *CronService) updateRelayer() func() {
-	return func() {
-		s.RelayService.UpdateRelayers()
+// startRelayerUpdate syncs the tokens and pairs of the relayers from the relayer contract,
+// the first sync is done by the boot sequence. When the relayers are followed from the events
+// of the contract the sync only reconciles the changes missed, hourly
+func (s *CronService) startRelayerUpdate() {
+	if s.RelayService.EventsEnabled() {
+		s.addJob("relayer_update", "@every 1h", s.RelayService.UpdateRelayers)
+		return
 	}
+
+	s.addJob("relayer_update", "*/600 * * * * *", s.RelayService.UpdateRelayers)
 }
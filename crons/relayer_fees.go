@@ -0,0 +1,9 @@
+package crons
+
+import "fmt"
+
+// startRelayerFeeWatcher propagates the fee changes of the relayers to the order validation and the
+// websocket clients, for the changes missed by the relayer events or when they are not followed
+func (s *CronService) startRelayerFeeWatcher() {
+	s.addJob("relayer_fees", fmt.Sprintf("@every %s", s.RelayService.FeeInterval()), s.RelayService.WatchFees)
+}
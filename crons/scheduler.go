@@ -0,0 +1,189 @@
+package crons
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/services"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// job is a job registered in the scheduler
+type job struct {
+	state    *types.JobState
+	schedule cron.Schedule
+	fn       func() error
+}
+
+// Scheduler runs the jobs of the crons and keeps their states in the database so that
+// the operators can inspect, trigger, pause and resume them with the admin API.
+// A run is skipped while the previous run of the same job is still running
+type Scheduler struct {
+	cron   *cron.Cron
+	jobDao interfaces.JobDao
+	jobs   map[string]*job
+	mutex  sync.Mutex
+}
+
+// NewScheduler returns a new instance of Scheduler
+func NewScheduler(jobDao interfaces.JobDao) *Scheduler {
+	return &Scheduler{
+		cron:   cron.New(),
+		jobDao: jobDao,
+		jobs:   make(map[string]*job),
+	}
+}
+
+// Add registers a job, the paused state and the history of a job with the same name are restored
+func (s *Scheduler) Add(name, spec string, fn func() error) error {
+	schedule, err := cron.Parse(spec)
+	if err != nil {
+		return fmt.Errorf("job %s: %s", name, err)
+	}
+
+	state, err := s.jobDao.GetByName(name)
+	if err != nil || state == nil {
+		state = &types.JobState{Name: name}
+	}
+
+	state.Spec = spec
+	state.Running = false
+	state.NextRunAt = schedule.Next(time.Now())
+
+	s.mutex.Lock()
+	s.jobs[name] = &job{state, schedule, fn}
+	s.mutex.Unlock()
+
+	s.save(state)
+	s.cron.Schedule(schedule, cron.FuncJob(func() { s.run(name, false) }))
+	return nil
+}
+
+// Start starts running the registered jobs
+func (s *Scheduler) Start() {
+	s.cron.Start()
+}
+
+// GetJobs returns the states of the registered jobs sorted by name
+func (s *Scheduler) GetJobs() []*types.JobState {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	res := []*types.JobState{}
+	for _, j := range s.jobs {
+		state := *j.state
+		res = append(res, &state)
+	}
+
+	sort.Slice(res, func(i, k int) bool { return res[i].Name < res[k].Name })
+	return res
+}
+
+// GetJob returns the state of a registered job
+func (s *Scheduler) GetJob(name string) (*types.JobState, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	j, ok := s.jobs[name]
+	if !ok {
+		return nil, services.ErrJobNotFound
+	}
+
+	state := *j.state
+	return &state, nil
+}
+
+// Trigger runs a job now in the background, even if it is paused
+func (s *Scheduler) Trigger(name string) error {
+	if _, err := s.GetJob(name); err != nil {
+		return err
+	}
+
+	go s.run(name, true)
+	return nil
+}
+
+// Pause stops the scheduled runs of a job until it is resumed
+func (s *Scheduler) Pause(name string) error {
+	return s.setPaused(name, true)
+}
+
+// Resume restarts the scheduled runs of a paused job
+func (s *Scheduler) Resume(name string) error {
+	return s.setPaused(name, false)
+}
+
+func (s *Scheduler) setPaused(name string, paused bool) error {
+	s.mutex.Lock()
+	j, ok := s.jobs[name]
+	if !ok {
+		s.mutex.Unlock()
+		return services.ErrJobNotFound
+	}
+
+	j.state.Paused = paused
+	state := *j.state
+	s.mutex.Unlock()
+
+	return s.jobDao.Upsert(&state)
+}
+
+// run runs a job and records its result, the scheduled runs of the paused jobs
+// and the runs overlapping a previous one are skipped
+func (s *Scheduler) run(name string, manual bool) {
+	s.mutex.Lock()
+	j := s.jobs[name]
+	now := time.Now()
+	j.state.NextRunAt = j.schedule.Next(now)
+
+	if j.state.Running || (j.state.Paused && !manual) {
+		s.mutex.Unlock()
+		return
+	}
+
+	j.state.Running = true
+	s.mutex.Unlock()
+
+	err := s.call(j.fn)
+
+	s.mutex.Lock()
+	j.state.Running = false
+	j.state.LastRunAt = now
+	j.state.LastDuration = int64(time.Since(now) / time.Millisecond)
+	j.state.LastSuccess = err == nil
+	j.state.LastError = ""
+	j.state.Runs++
+	if err != nil {
+		j.state.LastError = err.Error()
+		j.state.Failures++
+		log.Printf("job %s: %s", name, err)
+	}
+
+	state := *j.state
+	s.mutex.Unlock()
+
+	s.save(&state)
+}
+
+// call runs the function of a job, a panic is reported as a failure
+func (s *Scheduler) call(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return fn()
+}
+
+func (s *Scheduler) save(state *types.JobState) {
+	err := s.jobDao.Upsert(state)
+	if err != nil {
+		log.Printf("%s", err)
+	}
+}
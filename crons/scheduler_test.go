@@ -0,0 +1,84 @@
+package crons
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tomochain/tomox-sdk/services"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+type memoryJobDao struct {
+	jobs map[string]types.JobState
+}
+
+func (dao *memoryJobDao) Upsert(j *types.JobState) error {
+	dao.jobs[j.Name] = *j
+	return nil
+}
+
+func (dao *memoryJobDao) GetByName(name string) (*types.JobState, error) {
+	j, ok := dao.jobs[name]
+	if !ok {
+		return nil, nil
+	}
+
+	return &j, nil
+}
+
+func (dao *memoryJobDao) GetAll() ([]*types.JobState, error) {
+	return nil, nil
+}
+
+func TestSchedulerRun(t *testing.T) {
+	dao := &memoryJobDao{jobs: map[string]types.JobState{"restored": {Name: "restored", Paused: true, Runs: 3}}}
+	s := NewScheduler(dao)
+
+	calls := 0
+	fail := false
+	fn := func() error {
+		calls++
+		if fail {
+			return errors.New("failed")
+		}
+
+		return nil
+	}
+
+	assert.Error(t, s.Add("invalid", "not a spec", fn))
+	assert.NoError(t, s.Add("job", "0 * * * * *", fn))
+	assert.NoError(t, s.Add("restored", "0 * * * * *", fn))
+
+	s.run("job", false)
+	j, _ := s.GetJob("job")
+	assert.Equal(t, 1, calls)
+	assert.True(t, j.LastSuccess)
+	assert.Equal(t, int64(1), j.Runs)
+	assert.False(t, j.NextRunAt.IsZero())
+
+	fail = true
+	s.run("job", false)
+	j, _ = s.GetJob("job")
+	assert.False(t, j.LastSuccess)
+	assert.Equal(t, "failed", j.LastError)
+	assert.Equal(t, int64(1), j.Failures)
+	assert.Equal(t, "failed", dao.jobs["job"].LastError)
+
+	// the paused state is restored and the scheduled runs are skipped, not the manual ones
+	s.run("restored", false)
+	assert.Equal(t, 2, calls)
+	s.run("restored", true)
+	assert.Equal(t, 3, calls)
+
+	j, _ = s.GetJob("restored")
+	assert.Equal(t, int64(4), j.Runs)
+
+	assert.NoError(t, s.Resume("restored"))
+	assert.False(t, dao.jobs["restored"].Paused)
+
+	_, err := s.GetJob("unknown")
+	assert.Equal(t, services.ErrJobNotFound, err)
+	assert.Equal(t, services.ErrJobNotFound, s.Pause("unknown"))
+	assert.Len(t, s.GetJobs(), 2)
+}
@@ -2,44 +2,35 @@ package crons
 
 import (
 	"fmt"
-	"log"
 
 	"github.com/tomochain/tomox-sdk/types"
-	"github.com/tomochain/tomox-sdk/utils"
-	"github.com/tomochain/tomox-sdk/ws"
 
-	"github.com/robfig/cron"
 	"github.com/tomochain/tomox-sdk/app"
 )
 
 // tickStreamingCron takes instance of cron.Cron and adds tickStreaming
 // crons according to the durations mentioned in config/app.yaml file
-func (s *CronService) tickStreamingCron(c *cron.Cron) {
+func (s *CronService) tickStreamingCron() {
 	for unit, durations := range app.Config.TickDuration {
 		for _, duration := range durations {
 			schedule := getCronScheduleString(unit, duration)
-			c.AddFunc(schedule, s.tickStream(unit, duration))
+			s.addJob(fmt.Sprintf("tick_%s_%d", unit, duration), schedule, s.tickStream(unit, duration))
 		}
 	}
 }
 
 // tickStream function fetches latest tick based on unit and duration for each pair
 // and broadcasts the tick to the client subscribed to pair's respective channel
-func (s *CronService) tickStream(unit string, duration int64) func() {
-	return func() {
+func (s *CronService) tickStream(unit string, duration int64) func() error {
+	return func() error {
 		p := make([]types.PairAddresses, 0)
 		ticks, err := s.OHLCVService.GetOHLCV(p, duration, unit)
 		if err != nil {
-			log.Printf("%s", err)
-			return
+			return err
 		}
 
-		for _, tick := range ticks {
-			baseTokenAddress := tick.Pair.BaseToken
-			quoteTokenAddress := tick.Pair.QuoteToken
-			id := utils.GetTickChannelID(baseTokenAddress, quoteTokenAddress, unit, duration)
-			ws.GetOHLCVSocket().BroadcastOHLCV(id, tick)
-		}
+		s.OHLCVService.BroadcastTicks(ticks, duration, unit)
+		return nil
 	}
 }
 
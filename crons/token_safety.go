@@ -0,0 +1,6 @@
+package crons
+
+// startTokenSafetyCron runs the safety checks of all the tokens daily, a new token is checked when it is listed
+func (s *CronService) startTokenSafetyCron() {
+	s.addJob("token_safety", "@every 24h", s.tokenSafetyService.CheckAll)
+}
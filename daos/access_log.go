@@ -0,0 +1,111 @@
+package daos
+
+import (
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// AccessLogDao contains:
+// collectionName: MongoDB collection name
+// dbName: name of mongodb to interact with
+type AccessLogDao struct {
+	collectionName string
+	dbName         string
+}
+
+// NewAccessLogDao returns a new instance of AccessLogDao
+func NewAccessLogDao() *AccessLogDao {
+	dao := &AccessLogDao{}
+	dao.collectionName = "access_logs"
+	dao.dbName = app.Config.DBName
+
+	indexes := []mgo.Index{
+		{Key: []string{"-createdAt"}},
+		{Key: []string{"user", "createdAt"}, Sparse: true},
+	}
+
+	for _, i := range indexes {
+		err := db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(i)
+		if err != nil {
+			logger.Warning("Index failed", err)
+		}
+	}
+
+	return dao
+}
+
+// Create inserts a batch of access logs
+func (dao *AccessLogDao) Create(logs ...*types.AccessLog) error {
+	docs := make([]interface{}, len(logs))
+	for i, l := range logs {
+		l.ID = bson.NewObjectId()
+		docs[i] = l
+	}
+
+	err := db.Create(dao.dbName, dao.collectionName, docs...)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetStats returns the estimated calls of each route since a time, of a hashed user if it is not empty
+func (dao *AccessLogDao) GetStats(since time.Time, user string) ([]*types.AccessStats, error) {
+	match := bson.M{"createdAt": bson.M{"$gte": since}}
+	if user != "" {
+		match["user"] = user
+	}
+
+	q := []bson.M{
+		{"$match": match},
+		{"$group": bson.M{
+			"_id":          bson.M{"kind": "$kind", "route": "$route"},
+			"requests":     bson.M{"$sum": "$weight"},
+			"errors":       bson.M{"$sum": bson.M{"$cond": []interface{}{bson.M{"$gte": []interface{}{"$status", 400}}, "$weight", 0}}},
+			"bytes":        bson.M{"$sum": bson.M{"$multiply": []interface{}{"$bytes", "$weight"}}},
+			"avgLatencyMs": bson.M{"$avg": "$latencyMs"},
+			"maxLatencyMs": bson.M{"$max": "$latencyMs"},
+		}},
+		{"$project": bson.M{
+			"_id":          0,
+			"kind":         "$_id.kind",
+			"route":        "$_id.route",
+			"requests":     1,
+			"errors":       1,
+			"bytes":        1,
+			"avgLatencyMs": 1,
+			"maxLatencyMs": 1,
+		}},
+		{"$sort": bson.M{"requests": -1}},
+	}
+
+	res := []*types.AccessStats{}
+	err := db.Aggregate(dao.dbName, dao.collectionName, q, &res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Anonymize removes the hashed user of the logs created before a time
+func (dao *AccessLogDao) Anonymize(before time.Time) error {
+	q := bson.M{"createdAt": bson.M{"$lt": before}, "user": bson.M{"$exists": true}}
+	return db.UpdateAll(dao.dbName, dao.collectionName, q, bson.M{"$unset": bson.M{"user": ""}})
+}
+
+// RemoveBefore removes the logs created before a time
+func (dao *AccessLogDao) RemoveBefore(before time.Time) error {
+	return db.RemoveAll(dao.dbName, dao.collectionName, bson.M{"createdAt": bson.M{"$lt": before}})
+}
+
+// Drop drops all the access logs in the current database
+func (dao *AccessLogDao) Drop() {
+	db.DropCollection(dao.dbName, dao.collectionName)
+}
@@ -0,0 +1,66 @@
+package daos
+
+import (
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// AuditDao contains:
+// collectionName: MongoDB collection name
+// dbName: name of mongodb to interact with
+type AuditDao struct {
+	collectionName string
+	dbName         string
+}
+
+// NewAuditDao returns a new instance of AuditDao
+func NewAuditDao() *AuditDao {
+	dao := &AuditDao{}
+	dao.collectionName = "audit_logs"
+	dao.dbName = app.Config.DBName
+
+	i1 := mgo.Index{
+		Key: []string{"-createdAt"},
+	}
+
+	err := db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(i1)
+	if err != nil {
+		logger.Warning("Index failed", err)
+	}
+
+	return dao
+}
+
+// Create inserts an audit log
+func (dao *AuditDao) Create(l *types.AuditLog) error {
+	l.ID = bson.NewObjectId()
+	l.CreatedAt = time.Now()
+
+	err := db.Create(dao.dbName, dao.collectionName, l)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetAll returns the audit logs, most recent first
+func (dao *AuditDao) GetAll(offset, limit int) ([]*types.AuditLog, error) {
+	if limit == 0 {
+		limit = 50
+	}
+
+	res := []*types.AuditLog{}
+	err := db.GetAndSort(dao.dbName, dao.collectionName, bson.M{}, []string{"-createdAt"}, offset, limit, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,86 @@
+package daos
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// BalanceSnapshotDao contains:
+// collectionName: MongoDB collection name
+// dbName: name of mongodb to interact with
+type BalanceSnapshotDao struct {
+	collectionName string
+	dbName         string
+}
+
+// NewBalanceSnapshotDao returns a new instance of BalanceSnapshotDao
+func NewBalanceSnapshotDao() *BalanceSnapshotDao {
+	dao := &BalanceSnapshotDao{}
+	dao.collectionName = "balance_snapshots"
+	dao.dbName = app.Config.DBName
+
+	i := mgo.Index{
+		Key: []string{"address", "createdAt"},
+	}
+
+	err := db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(i)
+	if err != nil {
+		logger.Warning("Index failed", err)
+	}
+
+	return dao
+}
+
+// Create inserts a new balance snapshot
+func (dao *BalanceSnapshotDao) Create(s *types.BalanceSnapshot) error {
+	s.ID = bson.NewObjectId()
+	if s.CreatedAt.IsZero() {
+		s.CreatedAt = time.Now()
+	}
+
+	err := db.Create(dao.dbName, dao.collectionName, s)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetLastBefore returns the last snapshot of an address taken at or before a time, nil if there is none
+func (dao *BalanceSnapshotDao) GetLastBefore(addr common.Address, t time.Time) (*types.BalanceSnapshot, error) {
+	q := bson.M{"address": addr.Hex(), "createdAt": bson.M{"$lte": t}}
+	return dao.getOne(q, "-createdAt")
+}
+
+// GetFirstAfter returns the first snapshot of an address taken after a time, nil if there is none
+func (dao *BalanceSnapshotDao) GetFirstAfter(addr common.Address, t time.Time) (*types.BalanceSnapshot, error) {
+	q := bson.M{"address": addr.Hex(), "createdAt": bson.M{"$gt": t}}
+	return dao.getOne(q, "createdAt")
+}
+
+func (dao *BalanceSnapshotDao) getOne(q bson.M, sort string) (*types.BalanceSnapshot, error) {
+	res := []*types.BalanceSnapshot{}
+
+	err := db.GetAndSort(dao.dbName, dao.collectionName, q, []string{sort}, 0, 1, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, nil
+	}
+
+	return res[0], nil
+}
+
+// Drop drops all the balance snapshots in the current database
+func (dao *BalanceSnapshotDao) Drop() {
+	db.DropCollection(dao.dbName, dao.collectionName)
+}
@@ -0,0 +1,105 @@
+package daos
+
+import (
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// CompositeIndexDao contains:
+// collectionName: MongoDB collection name
+// dbName: name of mongodb to interact with
+type CompositeIndexDao struct {
+	collectionName string
+	dbName         string
+}
+
+// NewCompositeIndexDao returns a new instance of CompositeIndexDao
+func NewCompositeIndexDao() *CompositeIndexDao {
+	dao := &CompositeIndexDao{}
+	dao.collectionName = "composite_indices"
+	dao.dbName = app.Config.DBName
+
+	i := mgo.Index{
+		Key:    []string{"name"},
+		Unique: true,
+	}
+
+	err := db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(i)
+	if err != nil {
+		logger.Warning("Index failed", err)
+	}
+
+	return dao
+}
+
+// Create inserts a new index, its name is unique
+func (dao *CompositeIndexDao) Create(i *types.CompositeIndex) error {
+	i.ID = bson.NewObjectId()
+	i.CreatedAt = time.Now()
+	i.UpdatedAt = time.Now()
+
+	err := db.Create(dao.dbName, dao.collectionName, i)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetByName returns an index, nil if it does not exist
+func (dao *CompositeIndexDao) GetByName(name string) (*types.CompositeIndex, error) {
+	res := []*types.CompositeIndex{}
+
+	err := db.Get(dao.dbName, dao.collectionName, bson.M{"name": name}, 0, 1, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, nil
+	}
+
+	return res[0], nil
+}
+
+// GetAll returns the indices sorted by name
+func (dao *CompositeIndexDao) GetAll() ([]*types.CompositeIndex, error) {
+	res := []*types.CompositeIndex{}
+
+	err := db.GetAndSort(dao.dbName, dao.collectionName, bson.M{}, []string{"name"}, 0, 0, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Update replaces an index
+func (dao *CompositeIndexDao) Update(i *types.CompositeIndex) error {
+	i.UpdatedAt = time.Now()
+
+	err := db.Update(dao.dbName, dao.collectionName, bson.M{"_id": i.ID}, i)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteByName removes an index
+func (dao *CompositeIndexDao) DeleteByName(name string) error {
+	return db.RemoveItem(dao.dbName, dao.collectionName, bson.M{"name": name})
+}
+
+// Drop drops all the indices in the current database
+func (dao *CompositeIndexDao) Drop() {
+	db.DropCollection(dao.dbName, dao.collectionName)
+}
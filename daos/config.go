@@ -81,6 +81,11 @@ func (dao *ConfigDao) getUint64ValueFromKey(key string) (uint64, error) {
 	if err != nil {
 		return 0, err
 	}
+
+	return uint64Value(value)
+}
+
+func uint64Value(value interface{}) (uint64, error) {
 	switch v := value.(type) {
 	case int:
 		return uint64(value.(int)), nil
@@ -151,6 +156,36 @@ func (dao *ConfigDao) SaveLastProcessedBlock(chain types.Chain, block uint64) er
 	return err
 }
 
+// GetWatcherBlock returns the last block processed by a chain log watcher, 0 if it processed none
+func (dao *ConfigDao) GetWatcherBlock(watcher string) (uint64, error) {
+	var response types.KeyValue
+	err := db.GetOne(dao.dbName, dao.collectionName, bson.M{"key": watcherBlockKey(watcher)}, &response)
+	if err == mgo.ErrNotFound {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64Value(response.Value)
+}
+
+// SaveWatcherBlock saves the last block processed by a chain log watcher
+func (dao *ConfigDao) SaveWatcherBlock(watcher string, block uint64) error {
+	_, err := db.Upsert(dao.dbName, dao.collectionName, bson.M{"key": watcherBlockKey(watcher)}, bson.M{
+		"$set": bson.M{
+			"value": int64(block),
+		},
+	})
+
+	return err
+}
+
+func watcherBlockKey(watcher string) string {
+	return watcher + "_last_block"
+}
+
 // Drop drops all the order documents in the current database
 func (dao *ConfigDao) Drop() {
 	db.DropCollection(dao.dbName, dao.collectionName)
@@ -0,0 +1,148 @@
+package daos
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// DailyStatsDao contains the collections of the materialized daily aggregates of the trades:
+// userVolumes: volume of the users per pair and day
+// pairStats: stats of the pairs per day
+// feeTotals: fees collected per token and day
+// dbName: name of mongodb to interact with
+type DailyStatsDao struct {
+	userVolumes string
+	pairStats   string
+	feeTotals   string
+	dbName      string
+}
+
+// NewDailyStatsDao returns a new instance of DailyStatsDao
+func NewDailyStatsDao() *DailyStatsDao {
+	dao := &DailyStatsDao{
+		userVolumes: "user_daily_volumes",
+		pairStats:   "pair_daily_stats",
+		feeTotals:   "fee_daily_totals",
+		dbName:      app.Config.DBName,
+	}
+
+	indexes := map[string]mgo.Index{
+		dao.userVolumes: {Key: []string{"address", "date", "baseToken", "quoteToken"}, Unique: true},
+		dao.pairStats:   {Key: []string{"baseToken", "quoteToken", "date"}, Unique: true},
+		dao.feeTotals:   {Key: []string{"date", "token"}, Unique: true},
+	}
+
+	for collection, i := range indexes {
+		err := db.Session.DB(dao.dbName).C(collection).EnsureIndex(i)
+		if err != nil {
+			logger.Warning("Index failed", err)
+		}
+	}
+
+	err := db.Session.DB(dao.dbName).C(dao.pairStats).EnsureIndex(mgo.Index{Key: []string{"date"}})
+	if err != nil {
+		logger.Warning("Index failed", err)
+	}
+
+	return dao
+}
+
+// Save creates or replaces the aggregates of a day
+func (dao *DailyStatsDao) Save(a *types.DailyAggregates) error {
+	now := time.Now()
+
+	for _, v := range a.Users {
+		v.UpdatedAt = now
+		q := bson.M{"address": v.Address.Hex(), "date": v.Date, "baseToken": v.BaseToken.Hex(), "quoteToken": v.QuoteToken.Hex()}
+		_, err := db.Upsert(dao.dbName, dao.userVolumes, q, v)
+		if err != nil {
+			logger.Error(err)
+			return err
+		}
+	}
+
+	for _, s := range a.Pairs {
+		s.UpdatedAt = now
+		q := bson.M{"baseToken": s.BaseToken.Hex(), "quoteToken": s.QuoteToken.Hex(), "date": s.Date}
+		_, err := db.Upsert(dao.dbName, dao.pairStats, q, s)
+		if err != nil {
+			logger.Error(err)
+			return err
+		}
+	}
+
+	for _, f := range a.Fees {
+		f.UpdatedAt = now
+		q := bson.M{"date": f.Date, "token": f.Token.Hex()}
+		_, err := db.Upsert(dao.dbName, dao.feeTotals, q, f)
+		if err != nil {
+			logger.Error(err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetUserVolumes returns the daily volumes of a user between two dates included
+func (dao *DailyStatsDao) GetUserVolumes(addr common.Address, from, to string) ([]*types.UserDailyVolume, error) {
+	res := []*types.UserDailyVolume{}
+	q := bson.M{
+		"address": addr.Hex(),
+		"date":    bson.M{"$gte": from, "$lte": to},
+	}
+
+	err := db.GetAndSort(dao.dbName, dao.userVolumes, q, []string{"date", "pairName"}, 0, 0, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetPairStats returns the daily stats of a pair between two dates included,
+// the stats of all the pairs if the tokens are zero addresses
+func (dao *DailyStatsDao) GetPairStats(bt, qt common.Address, from, to string) ([]*types.PairDailyStats, error) {
+	res := []*types.PairDailyStats{}
+	q := bson.M{"date": bson.M{"$gte": from, "$lte": to}}
+
+	if bt != (common.Address{}) && qt != (common.Address{}) {
+		q["baseToken"] = bt.Hex()
+		q["quoteToken"] = qt.Hex()
+	}
+
+	err := db.GetAndSort(dao.dbName, dao.pairStats, q, []string{"date", "pairName"}, 0, 0, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetFeeTotals returns the daily fee totals per token between two dates included
+func (dao *DailyStatsDao) GetFeeTotals(from, to string) ([]*types.FeeTotal, error) {
+	res := []*types.FeeTotal{}
+	q := bson.M{"date": bson.M{"$gte": from, "$lte": to}}
+
+	err := db.GetAndSort(dao.dbName, dao.feeTotals, q, []string{"date", "token"}, 0, 0, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Drop drops the daily aggregates in the current database
+func (dao *DailyStatsDao) Drop() {
+	db.DropCollection(dao.dbName, dao.userVolumes)
+	db.DropCollection(dao.dbName, dao.pairStats)
+	db.DropCollection(dao.dbName, dao.feeTotals)
+}
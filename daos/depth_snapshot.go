@@ -0,0 +1,91 @@
+package daos
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// DepthSnapshotDao contains:
+// collectionName: MongoDB collection name
+// dbName: name of mongodb to interact with
+type DepthSnapshotDao struct {
+	collectionName string
+	dbName         string
+}
+
+// NewDepthSnapshotDao returns a new instance of DepthSnapshotDao
+func NewDepthSnapshotDao() *DepthSnapshotDao {
+	dao := &DepthSnapshotDao{}
+	dao.collectionName = "depth_snapshots"
+	dao.dbName = app.Config.DBName
+
+	i := mgo.Index{
+		Key: []string{"baseToken", "quoteToken", "createdAt"},
+	}
+
+	err := db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(i)
+	if err != nil {
+		logger.Warning("Index failed", err)
+	}
+
+	return dao
+}
+
+// Create inserts the depth snapshots
+func (dao *DepthSnapshotDao) Create(snapshots ...*types.DepthSnapshot) error {
+	docs := []interface{}{}
+	for _, s := range snapshots {
+		s.ID = bson.NewObjectId()
+		if s.CreatedAt.IsZero() {
+			s.CreatedAt = time.Now()
+		}
+
+		docs = append(docs, s)
+	}
+
+	if len(docs) == 0 {
+		return nil
+	}
+
+	err := db.Create(dao.dbName, dao.collectionName, docs...)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetByPairAndPeriod returns a page of the snapshots of a pair taken in [from, to), oldest first,
+// with the number of snapshots of the period
+func (dao *DepthSnapshotDao) GetByPairAndPeriod(baseToken, quoteToken common.Address, from, to time.Time, offset, limit int) ([]*types.DepthSnapshot, int, error) {
+	res := []*types.DepthSnapshot{}
+	q := bson.M{
+		"baseToken":  baseToken.Hex(),
+		"quoteToken": quoteToken.Hex(),
+		"createdAt":  bson.M{"$gte": from, "$lt": to},
+	}
+
+	total, err := db.GetEx(dao.dbName, dao.collectionName, q, []string{"createdAt"}, offset, limit, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, 0, err
+	}
+
+	return res, total, nil
+}
+
+// RemoveBefore removes the snapshots taken before a time
+func (dao *DepthSnapshotDao) RemoveBefore(before time.Time) error {
+	return db.RemoveAll(dao.dbName, dao.collectionName, bson.M{"createdAt": bson.M{"$lt": before}})
+}
+
+// Drop drops all the depth snapshots in the current database
+func (dao *DepthSnapshotDao) Drop() {
+	db.DropCollection(dao.dbName, dao.collectionName)
+}
@@ -0,0 +1,109 @@
+package daos
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// engineEventSequenceKey is the key of the counter of the engine event sequence numbers
+const engineEventSequenceKey = "engine_events"
+
+// EngineEventDao contains:
+// collectionName: MongoDB collection name
+// countersName: MongoDB collection of the sequence counter
+// dbName: name of mongodb to interact with
+type EngineEventDao struct {
+	collectionName string
+	countersName   string
+	dbName         string
+}
+
+// NewEngineEventDao returns a new instance of EngineEventDao
+func NewEngineEventDao() *EngineEventDao {
+	dao := &EngineEventDao{}
+	dao.collectionName = "engine_events"
+	dao.countersName = "counters"
+	dao.dbName = app.Config.DBName
+
+	indexes := []mgo.Index{
+		{Key: []string{"sequence"}, Unique: true},
+		{Key: []string{"orderHash", "sequence"}},
+		{Key: []string{"baseToken", "quoteToken", "sequence"}},
+	}
+
+	for _, i := range indexes {
+		err := db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(i)
+		if err != nil {
+			logger.Warning("Index failed", err)
+		}
+	}
+
+	return dao
+}
+
+// Create assigns the next sequence number to an engine event and inserts it
+func (dao *EngineEventDao) Create(e *types.EngineEvent) error {
+	counter := struct {
+		Value int64 `bson:"value"`
+	}{}
+
+	change := mgo.Change{
+		Update:    bson.M{"$inc": bson.M{"value": 1}},
+		Upsert:    true,
+		ReturnNew: true,
+	}
+
+	err := db.FindAndModify(dao.dbName, dao.countersName, bson.M{"key": engineEventSequenceKey}, change, &counter)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	e.ID = bson.NewObjectId()
+	e.Sequence = counter.Value
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now()
+	}
+
+	err = db.Create(dao.dbName, dao.collectionName, e)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetByOrderHash returns the events of an order with a sequence number greater than after
+func (dao *EngineEventDao) GetByOrderHash(h common.Hash, after int64, limit int) ([]*types.EngineEvent, error) {
+	q := bson.M{"orderHash": h.Hex(), "sequence": bson.M{"$gt": after}}
+	return dao.get(q, limit)
+}
+
+// GetByPair returns the events of a pair with a sequence number greater than after
+func (dao *EngineEventDao) GetByPair(bt, qt common.Address, after int64, limit int) ([]*types.EngineEvent, error) {
+	q := bson.M{"baseToken": bt.Hex(), "quoteToken": qt.Hex(), "sequence": bson.M{"$gt": after}}
+	return dao.get(q, limit)
+}
+
+func (dao *EngineEventDao) get(q bson.M, limit int) ([]*types.EngineEvent, error) {
+	res := []*types.EngineEvent{}
+
+	err := db.GetAndSort(dao.dbName, dao.collectionName, q, []string{"sequence"}, 0, limit, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Drop drops all the engine events in the current database
+func (dao *EngineEventDao) Drop() {
+	db.DropCollection(dao.dbName, dao.collectionName)
+}
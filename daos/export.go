@@ -0,0 +1,32 @@
+package daos
+
+import (
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// exportIndex orders the documents of the export streams by their last update
+var exportIndex = mgo.Index{
+	Key: []string{"updatedAt", "_id"},
+}
+
+// exportSort is the order of the documents of the export streams
+var exportSort = []string{"updatedAt", "_id"}
+
+// exportQuery selects the documents updated after the checkpoint and until the given time included
+func exportQuery(cp *types.ExportCheckpoint, until time.Time) bson.M {
+	if cp.ID == "" {
+		return bson.M{"updatedAt": bson.M{"$lte": until}}
+	}
+
+	return bson.M{
+		"updatedAt": bson.M{"$gte": cp.UpdatedAt, "$lte": until},
+		"$or": []bson.M{
+			{"updatedAt": bson.M{"$gt": cp.UpdatedAt}},
+			{"updatedAt": cp.UpdatedAt, "_id": bson.M{"$gt": cp.ID}},
+		},
+	}
+}
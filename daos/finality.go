@@ -0,0 +1,162 @@
+package daos
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// FinalityDao contains:
+// collectionName: MongoDB collection name
+// dbName: name of mongodb to interact with
+type FinalityDao struct {
+	collectionName string
+	dbName         string
+}
+
+// NewFinalityDao returns a new instance of FinalityDao
+func NewFinalityDao() *FinalityDao {
+	dao := &FinalityDao{}
+	dao.collectionName = "finality_records"
+	dao.dbName = app.Config.DBName
+
+	i1 := mgo.Index{
+		Key:    []string{"hash"},
+		Unique: true,
+	}
+
+	err := db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(i1)
+	if err != nil {
+		panic(err)
+	}
+
+	i2 := mgo.Index{
+		Key: []string{"status"},
+	}
+
+	err = db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(i2)
+	if err != nil {
+		panic(err)
+	}
+
+	return dao
+}
+
+// Create inserts a new finality record
+func (dao *FinalityDao) Create(r *types.FinalityRecord) error {
+	r.ID = bson.NewObjectId()
+	r.CreatedAt = time.Now()
+	r.UpdatedAt = time.Now()
+
+	err := db.Create(dao.dbName, dao.collectionName, r)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetByHash returns the finality record of the given hash
+func (dao *FinalityDao) GetByHash(h common.Hash) (*types.FinalityRecord, error) {
+	q := bson.M{"hash": h.Hex()}
+	res := []*types.FinalityRecord{}
+
+	err := db.Get(dao.dbName, dao.collectionName, q, 0, 1, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, nil
+	}
+
+	return res[0], nil
+}
+
+// GetPending returns all the records that did not reach finality yet
+func (dao *FinalityDao) GetPending() ([]*types.FinalityRecord, error) {
+	q := bson.M{"status": types.FinalityStatusPending}
+	res := []*types.FinalityRecord{}
+
+	err := db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// UpdateConfirmations updates the confirmation count and the status of a record
+func (dao *FinalityDao) UpdateConfirmations(r *types.FinalityRecord) error {
+	r.UpdatedAt = time.Now()
+
+	q := bson.M{"_id": r.ID}
+	update := bson.M{"$set": bson.M{
+		"confirmations": int64(r.Confirmations),
+		"status":        r.Status,
+		"updatedAt":     r.UpdatedAt,
+	}}
+
+	err := db.Update(dao.dbName, dao.collectionName, q, update)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetAboveBlock returns the records settled in the blocks above the given one
+func (dao *FinalityDao) GetAboveBlock(block uint64) ([]*types.FinalityRecord, error) {
+	q := bson.M{"blockNumber": bson.M{"$gt": int64(block)}}
+	res := []*types.FinalityRecord{}
+
+	err := db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// UpdateBlock sets the settlement transaction of a record and the block of its receipt, found once mined or
+// again after a reorganization, with its confirmations and status
+func (dao *FinalityDao) UpdateBlock(r *types.FinalityRecord) error {
+	r.UpdatedAt = time.Now()
+
+	q := bson.M{"_id": r.ID}
+	update := bson.M{"$set": bson.M{
+		"txHash":        r.TxHash.Hex(),
+		"blockNumber":   int64(r.BlockNumber),
+		"confirmations": int64(r.Confirmations),
+		"status":        r.Status,
+		"updatedAt":     r.UpdatedAt,
+	}}
+
+	err := db.Update(dao.dbName, dao.collectionName, q, update)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteByHash deletes the record of a settlement reverted by a reorganization, it is tracked again
+// once settled again
+func (dao *FinalityDao) DeleteByHash(h common.Hash) error {
+	return db.RemoveItem(dao.dbName, dao.collectionName, bson.M{"hash": h.Hex()})
+}
+
+// Drop drops all the finality records in the current database
+func (dao *FinalityDao) Drop() {
+	db.DropCollection(dao.dbName, dao.collectionName)
+}
@@ -0,0 +1,130 @@
+package daos
+
+import (
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// IncidentDao contains:
+// collectionName: MongoDB collection name
+// dbName: name of mongodb to interact with
+type IncidentDao struct {
+	collectionName string
+	dbName         string
+}
+
+// NewIncidentDao returns a new instance of IncidentDao
+func NewIncidentDao() *IncidentDao {
+	dao := &IncidentDao{}
+	dao.collectionName = "incidents"
+	dao.dbName = app.Config.DBName
+
+	i := mgo.Index{
+		Key: []string{"status", "resolvedAt"},
+	}
+
+	err := db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(i)
+	if err != nil {
+		logger.Warning("Index failed", err)
+	}
+
+	return dao
+}
+
+// Create inserts a new incident
+func (dao *IncidentDao) Create(i *types.Incident) error {
+	i.ID = bson.NewObjectId()
+	i.CreatedAt = time.Now()
+	i.UpdatedAt = time.Now()
+
+	err := db.Create(dao.dbName, dao.collectionName, i)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetByID returns an incident, nil if it does not exist
+func (dao *IncidentDao) GetByID(id bson.ObjectId) (*types.Incident, error) {
+	res := []*types.Incident{}
+
+	err := db.Get(dao.dbName, dao.collectionName, bson.M{"_id": id}, 0, 1, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, nil
+	}
+
+	return res[0], nil
+}
+
+// GetActive returns the incidents not resolved, newest first
+func (dao *IncidentDao) GetActive() ([]*types.Incident, error) {
+	q := bson.M{"status": bson.M{"$ne": types.IncidentStatusResolved}}
+	res := []*types.Incident{}
+
+	err := db.GetAndSort(dao.dbName, dao.collectionName, q, []string{"-createdAt"}, 0, 0, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetResolvedSince returns the incidents resolved since a time, the last resolved first
+func (dao *IncidentDao) GetResolvedSince(t time.Time) ([]*types.Incident, error) {
+	q := bson.M{
+		"status":     types.IncidentStatusResolved,
+		"resolvedAt": bson.M{"$gte": t},
+	}
+	res := []*types.Incident{}
+
+	err := db.GetAndSort(dao.dbName, dao.collectionName, q, []string{"-resolvedAt"}, 0, 0, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetAll returns a page of the incidents, newest first
+func (dao *IncidentDao) GetAll(offset, limit int) ([]*types.Incident, error) {
+	res := []*types.Incident{}
+
+	err := db.GetAndSort(dao.dbName, dao.collectionName, bson.M{}, []string{"-createdAt"}, offset, limit, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Update replaces an incident
+func (dao *IncidentDao) Update(i *types.Incident) error {
+	i.UpdatedAt = time.Now()
+
+	err := db.Update(dao.dbName, dao.collectionName, bson.M{"_id": i.ID}, i)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// Drop drops all the incidents in the current database
+func (dao *IncidentDao) Drop() {
+	db.DropCollection(dao.dbName, dao.collectionName)
+}
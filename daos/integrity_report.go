@@ -0,0 +1,68 @@
+package daos
+
+import (
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// IntegrityReportDao contains:
+// collectionName: MongoDB collection name
+// dbName: name of mongodb to interact with
+type IntegrityReportDao struct {
+	collectionName string
+	dbName         string
+}
+
+// NewIntegrityReportDao returns a new instance of IntegrityReportDao
+func NewIntegrityReportDao() *IntegrityReportDao {
+	dao := &IntegrityReportDao{}
+	dao.collectionName = "integrity_reports"
+	dao.dbName = app.Config.DBName
+
+	i := mgo.Index{
+		Key: []string{"-createdAt"},
+	}
+
+	err := db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(i)
+	if err != nil {
+		logger.Warning("Index failed", err)
+	}
+
+	return dao
+}
+
+// Create inserts a new report
+func (dao *IntegrityReportDao) Create(r *types.IntegrityReport) error {
+	r.ID = bson.NewObjectId()
+	r.CreatedAt = time.Now()
+
+	err := db.Create(dao.dbName, dao.collectionName, r)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetAll returns a page of the reports, newest first
+func (dao *IntegrityReportDao) GetAll(offset, limit int) ([]*types.IntegrityReport, error) {
+	res := []*types.IntegrityReport{}
+
+	err := db.GetAndSort(dao.dbName, dao.collectionName, bson.M{}, []string{"-createdAt"}, offset, limit, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Drop drops all the reports in the current database
+func (dao *IntegrityReportDao) Drop() {
+	db.DropCollection(dao.dbName, dao.collectionName)
+}
@@ -0,0 +1,88 @@
+package daos
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// InterestAccrualDao contains:
+// collectionName: MongoDB collection name
+// dbName: name of mongodb to interact with
+type InterestAccrualDao struct {
+	collectionName string
+	dbName         string
+}
+
+// NewInterestAccrualDao returns a new instance of InterestAccrualDao
+func NewInterestAccrualDao() *InterestAccrualDao {
+	dao := &InterestAccrualDao{}
+	dao.collectionName = "interest_accruals"
+	dao.dbName = app.Config.DBName
+
+	i1 := mgo.Index{
+		Key:    []string{"tradeHash", "date"},
+		Unique: true,
+	}
+
+	i2 := mgo.Index{
+		Key: []string{"investor", "date"},
+	}
+
+	for _, i := range []mgo.Index{i1, i2} {
+		err := db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(i)
+		if err != nil {
+			logger.Warning("Index failed", err)
+		}
+	}
+
+	return dao
+}
+
+// Save creates or replaces the accrual of a trade for its date
+func (dao *InterestAccrualDao) Save(a *types.InterestAccrual) error {
+	a.CreatedAt = time.Now()
+
+	q := bson.M{"tradeHash": a.TradeHash.Hex(), "date": a.Date}
+	_, err := db.Upsert(dao.dbName, dao.collectionName, q, a)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetByInvestor returns the accruals of the lends of an investor between two dates included
+func (dao *InterestAccrualDao) GetByInvestor(addr common.Address, from, to string) ([]*types.InterestAccrual, error) {
+	res := []*types.InterestAccrual{}
+	q := bson.M{
+		"investor": addr.Hex(),
+		"date":     bson.M{"$gte": from, "$lte": to},
+	}
+
+	err := db.GetAndSort(dao.dbName, dao.collectionName, q, []string{"date", "tradeHash"}, 0, 0, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetByTradeHash returns the accruals of a trade ordered by date
+func (dao *InterestAccrualDao) GetByTradeHash(hash common.Hash) ([]*types.InterestAccrual, error) {
+	res := []*types.InterestAccrual{}
+
+	err := db.GetAndSort(dao.dbName, dao.collectionName, bson.M{"tradeHash": hash.Hex()}, []string{"date"}, 0, 0, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,97 @@
+package daos
+
+import (
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// JobDao contains:
+// collectionName: MongoDB collection name
+// dbName: name of mongodb to interact with
+type JobDao struct {
+	collectionName string
+	dbName         string
+}
+
+// NewJobDao returns a new instance of JobDao
+func NewJobDao() *JobDao {
+	dao := &JobDao{}
+	dao.collectionName = "jobs"
+	dao.dbName = app.Config.DBName
+
+	i := mgo.Index{
+		Key:    []string{"name"},
+		Unique: true,
+	}
+
+	err := db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(i)
+	if err != nil {
+		logger.Warning("Index failed", err)
+	}
+
+	return dao
+}
+
+// Upsert saves the state of a job by its name
+func (dao *JobDao) Upsert(j *types.JobState) error {
+	j.UpdatedAt = time.Now()
+	if j.ID == "" {
+		j.ID = bson.NewObjectId()
+	}
+
+	update := bson.M{"$set": bson.M{
+		"spec":         j.Spec,
+		"paused":       j.Paused,
+		"running":      j.Running,
+		"lastRunAt":    j.LastRunAt,
+		"lastDuration": j.LastDuration,
+		"lastSuccess":  j.LastSuccess,
+		"lastError":    j.LastError,
+		"nextRunAt":    j.NextRunAt,
+		"runs":         j.Runs,
+		"failures":     j.Failures,
+		"updatedAt":    j.UpdatedAt,
+	}, "$setOnInsert": bson.M{"_id": j.ID}}
+
+	_, err := db.Upsert(dao.dbName, dao.collectionName, bson.M{"name": j.Name}, update)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetByName returns the state of a job, nil if it was never saved
+func (dao *JobDao) GetByName(name string) (*types.JobState, error) {
+	res := []*types.JobState{}
+
+	err := db.Get(dao.dbName, dao.collectionName, bson.M{"name": name}, 0, 1, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, nil
+	}
+
+	return res[0], nil
+}
+
+// GetAll returns the states of all the jobs sorted by name
+func (dao *JobDao) GetAll() ([]*types.JobState, error) {
+	res := []*types.JobState{}
+
+	err := db.GetAndSort(dao.dbName, dao.collectionName, bson.M{}, []string{"name"}, 0, 0, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
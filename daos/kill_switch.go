@@ -0,0 +1,113 @@
+package daos
+
+import (
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// KillSwitchDao contains:
+// collectionName: MongoDB collection name
+// dbName: name of mongodb to interact with
+type KillSwitchDao struct {
+	collectionName string
+	dbName         string
+}
+
+// NewKillSwitchDao returns a new instance of KillSwitchDao
+func NewKillSwitchDao() *KillSwitchDao {
+	dao := &KillSwitchDao{}
+	dao.collectionName = "kill_switches"
+	dao.dbName = app.Config.DBName
+
+	i := mgo.Index{
+		Key: []string{"status", "-createdAt"},
+	}
+
+	err := db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(i)
+	if err != nil {
+		logger.Warning("Index failed", err)
+	}
+
+	return dao
+}
+
+// Create inserts a new kill switch
+func (dao *KillSwitchDao) Create(k *types.KillSwitch) error {
+	k.ID = bson.NewObjectId()
+	k.CreatedAt = time.Now()
+	k.UpdatedAt = time.Now()
+
+	err := db.Create(dao.dbName, dao.collectionName, k)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetByID returns a kill switch, nil if it does not exist
+func (dao *KillSwitchDao) GetByID(id bson.ObjectId) (*types.KillSwitch, error) {
+	res := []*types.KillSwitch{}
+
+	err := db.Get(dao.dbName, dao.collectionName, bson.M{"_id": id}, 0, 1, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, nil
+	}
+
+	return res[0], nil
+}
+
+// GetActive returns the kill switches not released, newest first
+func (dao *KillSwitchDao) GetActive() ([]*types.KillSwitch, error) {
+	q := bson.M{"status": types.KillSwitchStatusActive}
+	res := []*types.KillSwitch{}
+
+	err := db.GetAndSort(dao.dbName, dao.collectionName, q, []string{"-createdAt"}, 0, 0, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetAll returns a page of the kill switches, newest first
+func (dao *KillSwitchDao) GetAll(offset, limit int) ([]*types.KillSwitch, error) {
+	res := []*types.KillSwitch{}
+
+	err := db.GetAndSort(dao.dbName, dao.collectionName, bson.M{}, []string{"-createdAt"}, offset, limit, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Update replaces a kill switch
+func (dao *KillSwitchDao) Update(k *types.KillSwitch) error {
+	k.UpdatedAt = time.Now()
+
+	err := db.Update(dao.dbName, dao.collectionName, bson.M{"_id": k.ID}, k)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// Drop drops all the kill switches in the current database
+func (dao *KillSwitchDao) Drop() {
+	db.DropCollection(dao.dbName, dao.collectionName)
+}
@@ -3,7 +3,6 @@ package daos
 import (
 	"errors"
 	"fmt"
-	m "math"
 	"math/big"
 	"sort"
 	"strconv"
@@ -212,6 +211,23 @@ func (dao *LendingOrderDao) GetByHash(hash common.Hash) (*types.LendingOrder, er
 	return &res[0], nil
 }
 
+// GetOpenBySide returns the open and partially filled lending orders of a side
+func (dao *LendingOrderDao) GetOpenBySide(side string) ([]*types.LendingOrder, error) {
+	q := bson.M{
+		"status": bson.M{"$in": []string{types.OrderStatusOpen, types.OrderStatusPartialFilled}},
+		"side":   side,
+	}
+
+	res := []*types.LendingOrder{}
+	err := db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
 // Drop drops all the order documents in the current database
 func (dao *LendingOrderDao) Drop() error {
 	err := db.DropCollection(dao.dbName, dao.collectionName)
@@ -745,8 +761,8 @@ func (dao *LendingOrderDao) GetLastTokenPrice(bToken, qToken common.Address, bas
 	if err != nil {
 		return nil, err
 	}
-	dbase := big.NewInt(int64(m.Pow10(baseTokenDecimal)))
-	dquote := big.NewInt(int64(m.Pow10(quoteTokenDecinals)))
+	dbase := math.Pow10(baseTokenDecimal)
+	dquote := math.Pow10(quoteTokenDecinals)
 
 	priceBaseQuote := math.Mul(dbase, dquote)
 	priceBaseQuote = math.Div(priceBaseQuote, price)
@@ -799,7 +815,7 @@ func (dao *LendingOrderDao) GetUserLockedBalance(account common.Address, token c
 	if collateralTokenInfo == nil {
 		return nil, errors.New("Collateral token not found")
 	}
-	collateralDecimals := big.NewInt(int64(m.Pow10(collateralTokenInfo.Decimals)))
+	collateralDecimals := math.Pow10(collateralTokenInfo.Decimals)
 	for lt, q := range lendingTokenList {
 		lendingTokenInfo := types.TokensFrom(lt, tokens)
 		if lendingTokenInfo == nil {
@@ -0,0 +1,84 @@
+package daos
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// LendingRateSnapshotDao contains:
+// collectionName: MongoDB collection name
+// dbName: name of mongodb to interact with
+type LendingRateSnapshotDao struct {
+	collectionName string
+	dbName         string
+}
+
+// NewLendingRateSnapshotDao returns a new instance of LendingRateSnapshotDao
+func NewLendingRateSnapshotDao() *LendingRateSnapshotDao {
+	dao := &LendingRateSnapshotDao{}
+	dao.collectionName = "lending_rate_snapshots"
+	dao.dbName = app.Config.DBName
+
+	i := mgo.Index{
+		Key: []string{"lendingToken", "createdAt"},
+	}
+
+	err := db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(i)
+	if err != nil {
+		logger.Warning("Index failed", err)
+	}
+
+	return dao
+}
+
+// Create inserts the lending rate snapshots
+func (dao *LendingRateSnapshotDao) Create(snapshots ...*types.LendingRateSnapshot) error {
+	docs := []interface{}{}
+	for _, s := range snapshots {
+		s.ID = bson.NewObjectId()
+		if s.CreatedAt.IsZero() {
+			s.CreatedAt = time.Now()
+		}
+
+		docs = append(docs, s)
+	}
+
+	if len(docs) == 0 {
+		return nil
+	}
+
+	err := db.Create(dao.dbName, dao.collectionName, docs...)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetByTokenAndPeriod returns the snapshots of a lending token taken in [from, to), oldest first
+func (dao *LendingRateSnapshotDao) GetByTokenAndPeriod(token common.Address, from, to time.Time) ([]*types.LendingRateSnapshot, error) {
+	res := []*types.LendingRateSnapshot{}
+	q := bson.M{
+		"lendingToken": token.Hex(),
+		"createdAt":    bson.M{"$gte": from, "$lt": to},
+	}
+
+	err := db.GetAndSort(dao.dbName, dao.collectionName, q, []string{"createdAt"}, 0, 0, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Drop drops all the lending rate snapshots in the current database
+func (dao *LendingRateSnapshotDao) Drop() {
+	db.DropCollection(dao.dbName, dao.collectionName)
+}
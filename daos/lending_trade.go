@@ -142,6 +142,10 @@ func (dao *LendingTradeDao) GetByHash(h common.Hash) (*types.LendingTrade, error
 		return nil, err
 	}
 
+	if len(res) == 0 {
+		return nil, nil
+	}
+
 	return res[0], nil
 }
 
@@ -373,3 +377,86 @@ func (dao *LendingTradeDao) GetLendingTrades(lendingtradeSpec *types.LendingTrad
 	res.LendingTrades = trades
 	return &res, nil
 }
+
+// GetOpenMaturing returns the open lending trades maturing in [from, to), timestamps in seconds
+func (dao *LendingTradeDao) GetOpenMaturing(from, to int64) ([]*types.LendingTrade, error) {
+	return dao.getOpenMaturing(bson.M{}, from, to)
+}
+
+// GetOpen returns all the open lending trades
+func (dao *LendingTradeDao) GetOpen() ([]*types.LendingTrade, error) {
+	res := []*types.LendingTrade{}
+
+	err := db.Get(dao.dbName, dao.collectionName, bson.M{"status": types.TradeStatusOpen}, 0, 0, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetOpenByUser returns the open lending trades of a borrower or an investor ordered by maturity
+func (dao *LendingTradeDao) GetOpenByUser(addr common.Address) ([]*types.LendingTrade, error) {
+	res := []*types.LendingTrade{}
+	q := bson.M{
+		"status": types.TradeStatusOpen,
+		"$or": []bson.M{
+			{"borrower": addr.Hex()},
+			{"investor": addr.Hex()},
+		},
+	}
+
+	err := db.GetAndSort(dao.dbName, dao.collectionName, q, []string{"liquidationTime"}, 0, 0, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetOpenMaturingByUser returns the open lending trades of a borrower or an investor maturing in [from, to)
+func (dao *LendingTradeDao) GetOpenMaturingByUser(addr common.Address, from, to int64) ([]*types.LendingTrade, error) {
+	q := bson.M{
+		"$or": []bson.M{
+			{"borrower": addr.Hex()},
+			{"investor": addr.Hex()},
+		},
+	}
+
+	return dao.getOpenMaturing(q, from, to)
+}
+
+// getOpenMaturing adds the status and maturity conditions to the query.
+// The liquidation times are stored as decimal strings of the same length, they are compared as strings
+func (dao *LendingTradeDao) getOpenMaturing(q bson.M, from, to int64) ([]*types.LendingTrade, error) {
+	res := []*types.LendingTrade{}
+
+	q["status"] = types.TradeStatusOpen
+	q["liquidationTime"] = bson.M{
+		"$gte": strconv.FormatInt(from, 10),
+		"$lt":  strconv.FormatInt(to, 10),
+	}
+
+	err := db.GetAndSort(dao.dbName, dao.collectionName, q, []string{"liquidationTime"}, 0, 0, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetRecentLiquidated returns the last n liquidated lending trades
+func (dao *LendingTradeDao) GetRecentLiquidated(n int) ([]*types.LendingTrade, error) {
+	res := []*types.LendingTrade{}
+
+	err := db.GetAndSort(dao.dbName, dao.collectionName, bson.M{"status": types.TradeStatusLiquidated}, []string{"-updatedAt"}, 0, n, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,130 @@
+package daos
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// ListingReviewDao contains:
+// collectionName: MongoDB collection name
+// dbName: name of mongodb to interact with
+type ListingReviewDao struct {
+	collectionName string
+	dbName         string
+}
+
+// NewListingReviewDao returns a new instance of ListingReviewDao
+func NewListingReviewDao() *ListingReviewDao {
+	dao := &ListingReviewDao{}
+	dao.collectionName = "listing_reviews"
+	dao.dbName = app.Config.DBName
+
+	i1 := mgo.Index{
+		Key:    []string{"baseToken", "quoteToken", "relayerAddress"},
+		Unique: true,
+	}
+
+	i2 := mgo.Index{
+		Key: []string{"status", "createdAt"},
+	}
+
+	for _, i := range []mgo.Index{i1, i2} {
+		err := db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(i)
+		if err != nil {
+			logger.Warning("Index failed", err)
+		}
+	}
+
+	return dao
+}
+
+// Create inserts a new listing review
+func (dao *ListingReviewDao) Create(l *types.ListingReview) error {
+	l.ID = bson.NewObjectId()
+	l.CreatedAt = time.Now()
+	l.UpdatedAt = time.Now()
+
+	err := db.Create(dao.dbName, dao.collectionName, l)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetByID returns a listing review, nil if it does not exist
+func (dao *ListingReviewDao) GetByID(id bson.ObjectId) (*types.ListingReview, error) {
+	res := []*types.ListingReview{}
+
+	err := db.Get(dao.dbName, dao.collectionName, bson.M{"_id": id}, 0, 1, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, nil
+	}
+
+	return res[0], nil
+}
+
+// GetByPair returns the review of a pair of a relayer, nil if the pair was never reviewed
+func (dao *ListingReviewDao) GetByPair(bt, qt, relayer common.Address) (*types.ListingReview, error) {
+	q := bson.M{
+		"baseToken":      bt.Hex(),
+		"quoteToken":     qt.Hex(),
+		"relayerAddress": relayer.Hex(),
+	}
+	res := []*types.ListingReview{}
+
+	err := db.Get(dao.dbName, dao.collectionName, q, 0, 1, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, nil
+	}
+
+	return res[0], nil
+}
+
+// GetByStatus returns the listing reviews with one of the statuses, oldest first
+func (dao *ListingReviewDao) GetByStatus(statuses ...string) ([]*types.ListingReview, error) {
+	q := bson.M{"status": bson.M{"$in": statuses}}
+	res := []*types.ListingReview{}
+
+	err := db.GetAndSort(dao.dbName, dao.collectionName, q, []string{"createdAt"}, 0, 0, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Update replaces a listing review
+func (dao *ListingReviewDao) Update(l *types.ListingReview) error {
+	l.UpdatedAt = time.Now()
+
+	err := db.Update(dao.dbName, dao.collectionName, bson.M{"_id": l.ID}, l)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// Drop drops all the listing reviews in the current database
+func (dao *ListingReviewDao) Drop() {
+	db.DropCollection(dao.dbName, dao.collectionName)
+}
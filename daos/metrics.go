@@ -0,0 +1,92 @@
+package daos
+
+import (
+	"sync"
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// defaultSlowQuery is the duration above which a query is counted as slow
+const defaultSlowQuery = 100 * time.Millisecond
+
+var (
+	queryMutex      sync.Mutex
+	queryStats      types.LatencyStats
+	slowQueries     int64
+	slowCollections = make(map[string]int64)
+)
+
+// slowQueryThreshold returns the duration of the slow queries, from the "dependency_metrics" config
+func slowQueryThreshold() time.Duration {
+	if ms := app.Config.DependencyMetrics["slow_query_ms"]; ms > 0 {
+		return time.Duration(ms) * time.Millisecond
+	}
+
+	return defaultSlowQuery
+}
+
+// observeQuery records the duration of a query of the collection started at start
+func observeQuery(collection string, start time.Time) {
+	d := time.Since(start)
+	threshold := slowQueryThreshold()
+
+	queryMutex.Lock()
+	defer queryMutex.Unlock()
+
+	queryStats.Add(d)
+	if d >= threshold {
+		slowQueries++
+		slowCollections[collection]++
+		logger.Warningf("Slow query on %s: %s", collection, d)
+	}
+}
+
+// GetMongoMetrics pings the database and returns the stats of the connection pool with the durations
+// of the queries sent since the start
+func GetMongoMetrics() *types.MongoMetrics {
+	m := &types.MongoMetrics{
+		SlowQueryThreshold: int64(slowQueryThreshold() / time.Millisecond),
+		SlowCollections:    make(map[string]int64),
+	}
+
+	if db == nil {
+		m.Error = "Not connected"
+		return m
+	}
+
+	sc := db.Session.Copy()
+	defer sc.Close()
+
+	start := time.Now()
+	err := sc.Ping()
+	m.PingMs = float64(time.Since(start)) / float64(time.Millisecond)
+	m.Reachable = err == nil
+	if err != nil {
+		m.Error = err.Error()
+	}
+
+	stats := mgo.GetStats()
+	m.Clusters = stats.Clusters
+	m.MasterConns = stats.MasterConns
+	m.SlaveConns = stats.SlaveConns
+	m.SocketsAlive = stats.SocketsAlive
+	m.SocketsInUse = stats.SocketsInUse
+	m.SocketRefs = stats.SocketRefs
+	m.SentOps = stats.SentOps
+	m.ReceivedOps = stats.ReceivedOps
+	m.ReceivedDocs = stats.ReceivedDocs
+
+	queryMutex.Lock()
+	defer queryMutex.Unlock()
+
+	m.Queries = queryStats
+	m.SlowQueries = slowQueries
+	for c, n := range slowCollections {
+		m.SlowCollections[c] = n
+	}
+
+	return m
+}
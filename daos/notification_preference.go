@@ -0,0 +1,67 @@
+package daos
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// NotificationPreferenceDao contains:
+// collectionName: MongoDB collection name
+// dbName: name of mongodb to interact with
+type NotificationPreferenceDao struct {
+	collectionName string
+	dbName         string
+}
+
+// NewNotificationPreferenceDao returns a new instance of NotificationPreferenceDao
+func NewNotificationPreferenceDao() *NotificationPreferenceDao {
+	dao := &NotificationPreferenceDao{}
+	dao.collectionName = "notification_preferences"
+	dao.dbName = app.Config.DBName
+
+	i1 := mgo.Index{
+		Key:    []string{"address"},
+		Unique: true,
+	}
+
+	err := db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(i1)
+	if err != nil {
+		logger.Warning("Index failed", err)
+	}
+
+	return dao
+}
+
+// Save creates or replaces the notification preference of an address
+func (dao *NotificationPreferenceDao) Save(p *types.NotificationPreference) error {
+	p.UpdatedAt = time.Now()
+
+	_, err := db.Upsert(dao.dbName, dao.collectionName, bson.M{"address": p.Address.Hex()}, p)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetByAddress returns the notification preference of an address, nil if it has none
+func (dao *NotificationPreferenceDao) GetByAddress(addr common.Address) (*types.NotificationPreference, error) {
+	res := []*types.NotificationPreference{}
+	err := db.Get(dao.dbName, dao.collectionName, bson.M{"address": addr.Hex()}, 0, 1, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, nil
+	}
+
+	return res[0], nil
+}
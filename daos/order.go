@@ -16,6 +16,7 @@ import (
 	"github.com/tomochain/tomox-sdk/app"
 	"github.com/tomochain/tomox-sdk/types"
 	"github.com/tomochain/tomox-sdk/utils/math"
+	"github.com/tomochain/tomox-sdk/utils/snowflake"
 	"github.com/tomochain/tomox-sdk/ws"
 )
 
@@ -132,6 +133,16 @@ func NewOrderDao(opts ...OrderDaoOption) *OrderDao {
 		panic(err)
 	}
 
+	err = db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(publicIDIndex)
+	if err != nil {
+		panic(err)
+	}
+
+	err = db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(exportIndex)
+	if err != nil {
+		panic(err)
+	}
+
 	return dao
 }
 
@@ -153,8 +164,18 @@ func (dao *OrderDao) Create(o *types.Order) error {
 	o.CreatedAt = time.Now()
 	o.UpdatedAt = time.Now()
 
+	if o.PublicID == 0 {
+		o.PublicID = snowflake.Next()
+	}
+
 	if o.Status == "" {
-		o.Status = "OPEN"
+		o.Status = types.OrderStatusOpen
+	}
+
+	if !types.CanTransitionOrderStatus("", o.Status) {
+		err := &types.IllegalOrderTransitionError{To: o.Status}
+		logger.Error(err, o.Hash.Hex())
+		return err
 	}
 
 	err := db.Create(dao.dbName, dao.collectionName, o)
@@ -166,6 +187,28 @@ func (dao *OrderDao) Create(o *types.Order) error {
 	return nil
 }
 
+// AssignPublicID gives a public id to an order stored without one, the order is updated with its public id
+func (dao *OrderDao) AssignPublicID(o *types.Order) error {
+	publicID, err := assignPublicID(dao.dbName, dao.collectionName, o.ID)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	o.PublicID = publicID
+	return nil
+}
+
+// AssignPublicIDs gives a public id to all the orders stored without one, it returns the number of orders updated
+func (dao *OrderDao) AssignPublicIDs() (int, error) {
+	count, err := assignPublicIDs(dao.dbName, dao.collectionName)
+	if err != nil {
+		logger.Error(err)
+	}
+
+	return count, err
+}
+
 func (dao *OrderDao) DeleteByHashes(hashes ...common.Hash) error {
 	err := db.RemoveAll(dao.dbName, dao.collectionName, bson.M{"hash": bson.M{"$in": hashes}})
 	if err != nil {
@@ -260,9 +303,9 @@ func (dao *OrderDao) FindAndModify(h common.Hash, o *types.Order) (*types.Order,
 }
 
 //UpdateByHash updates fields that are considered updateable for an order.
+// The update is rejected if the order cannot move from its stored status to the new one
 func (dao *OrderDao) UpdateByHash(h common.Hash, o *types.Order) error {
 	o.UpdatedAt = time.Now()
-	query := bson.M{"hash": h.Hex()}
 	update := bson.M{"$set": bson.M{
 		"pricepoint":   o.PricePoint.String(),
 		"amount":       o.Amount.String(),
@@ -271,37 +314,36 @@ func (dao *OrderDao) UpdateByHash(h common.Hash, o *types.Order) error {
 		"updatedAt":    o.UpdatedAt,
 	}}
 
-	err := db.Update(dao.dbName, dao.collectionName, query, update)
-	if err != nil {
-		logger.Error(err)
-		return err
-	}
-
-	return nil
+	return dao.updateStatus(h, o.Status, update)
 }
 
+// UpdateOrderStatus moves an order to a status, illegal transitions are rejected
 func (dao *OrderDao) UpdateOrderStatus(h common.Hash, status string) error {
-	query := bson.M{"hash": h.Hex()}
 	update := bson.M{"$set": bson.M{
-		"status": status,
+		"status":    status,
+		"updatedAt": time.Now(),
 	}}
 
-	err := db.Update(dao.dbName, dao.collectionName, query, update)
-	if err != nil {
-		logger.Error(err)
-		return err
-	}
-
-	return nil
+	return dao.updateStatus(h, status, update)
 }
 
+// UpdateOrderStatusesByHashes moves orders to a status and returns the updated orders,
+// the orders that cannot move to the status are left unchanged and not returned
 func (dao *OrderDao) UpdateOrderStatusesByHashes(status string, hashes ...common.Hash) ([]*types.Order, error) {
+	if !types.IsValidOrderStatus(status) {
+		return nil, &types.IllegalOrderTransitionError{To: status}
+	}
+
 	hexes := []string{}
 	for _, h := range hashes {
 		hexes = append(hexes, h.Hex())
 	}
 
-	query := bson.M{"hash": bson.M{"$in": hexes}}
+	query := bson.M{
+		"hash":   bson.M{"$in": hexes},
+		"status": bson.M{"$in": types.OrderStatusPredecessors(status)},
+	}
+
 	update := bson.M{
 		"$set": bson.M{
 			"updatedAt": time.Now(),
@@ -316,15 +358,26 @@ func (dao *OrderDao) UpdateOrderStatusesByHashes(status string, hashes ...common
 	}
 
 	orders := []*types.Order{}
-	err = db.Get(dao.dbName, dao.collectionName, query, 0, 0, &orders)
+	err = db.Get(dao.dbName, dao.collectionName, bson.M{"hash": bson.M{"$in": hexes}}, 0, 0, &orders)
 	if err != nil {
 		logger.Error(err)
 		return nil, nil
 	}
 
-	return orders, nil
+	updated := []*types.Order{}
+	for _, o := range orders {
+		if o.Status != status {
+			logger.Warning(&types.IllegalOrderTransitionError{From: o.Status, To: status}, o.Hash.Hex())
+			continue
+		}
+
+		updated = append(updated, o)
+	}
+
+	return updated, nil
 }
 
+// UpdateOrderFilledAmount adds a value to the filled amount of an order and updates its status
 func (dao *OrderDao) UpdateOrderFilledAmount(hash common.Hash, value *big.Int) error {
 	q := bson.M{"hash": hash.Hex()}
 	res := []types.Order{}
@@ -334,34 +387,24 @@ func (dao *OrderDao) UpdateOrderFilledAmount(hash common.Hash, value *big.Int) e
 		return err
 	}
 
-	o := res[0]
-	status := ""
-	filledAmount := math.Add(o.FilledAmount, value)
-
-	if math.IsEqualOrSmallerThan(filledAmount, big.NewInt(0)) {
-		filledAmount = big.NewInt(0)
-		status = "OPEN"
-	} else if math.IsEqualOrGreaterThan(filledAmount, o.Amount) {
-		filledAmount = o.Amount
-		status = "FILLED"
-	} else {
-		status = "PARTIAL_FILLED"
+	if len(res) == 0 {
+		return mgo.ErrNotFound
 	}
 
+	o := res[0]
+	filledAmount := clampFilledAmount(math.Add(o.FilledAmount, value), o.Amount)
+	status := types.OrderStatusFromFill(filledAmount, o.Amount)
+
 	update := bson.M{"$set": bson.M{
 		"status":       status,
 		"filledAmount": filledAmount.String(),
 	}}
 
-	err = db.Update(dao.dbName, dao.collectionName, q, update)
-	if err != nil {
-		logger.Error(err)
-		return err
-	}
-
-	return nil
+	return dao.updateStatus(hash, status, update)
 }
 
+// UpdateOrderFilledAmounts removes the amounts of reverted trades from the filled amounts of orders
+// and updates their statuses, the orders that cannot move to the new status are skipped
 func (dao *OrderDao) UpdateOrderFilledAmounts(hashes []common.Hash, amount []*big.Int) ([]*types.Order, error) {
 	hexes := []string{}
 	orders := []*types.Order{}
@@ -378,33 +421,36 @@ func (dao *OrderDao) UpdateOrderFilledAmounts(hashes []common.Hash, amount []*bi
 
 	updatedOrders := []*types.Order{}
 	for i, o := range orders {
-		status := ""
-		filledAmount := math.Sub(o.FilledAmount, amount[i])
-
-		if math.IsEqualOrSmallerThan(filledAmount, big.NewInt(0)) {
-			filledAmount = big.NewInt(0)
-			status = "OPEN"
-		} else if math.IsEqualOrGreaterThan(filledAmount, o.Amount) {
-			filledAmount = o.Amount
-			status = "FILLED"
-		} else {
-			status = "PARTIAL_FILLED"
+		filledAmount := clampFilledAmount(math.Sub(o.FilledAmount, amount[i]), o.Amount)
+		status := types.OrderStatusFromFill(filledAmount, o.Amount)
+
+		if !types.CanTransitionOrderStatus(o.Status, status) {
+			logger.Warning(&types.IllegalOrderTransitionError{From: o.Status, To: status}, o.Hash.Hex())
+			continue
+		}
+
+		query := bson.M{
+			"hash":   o.Hash.Hex(),
+			"status": bson.M{"$in": types.OrderStatusPredecessors(status)},
 		}
 
-		query := bson.M{"hash": o.Hash.Hex()}
 		update := bson.M{"$set": bson.M{
 			"status":       status,
 			"filledAmount": filledAmount.String(),
 		}}
 		change := mgo.Change{
 			Update:    update,
-			Upsert:    true,
 			Remove:    false,
 			ReturnNew: true,
 		}
 
 		updated := &types.Order{}
 		err := db.FindAndModify(dao.dbName, dao.collectionName, query, change, updated)
+		if err == mgo.ErrNotFound {
+			logger.Warning(&types.IllegalOrderTransitionError{From: o.Status, To: status}, o.Hash.Hex())
+			continue
+		}
+
 		if err != nil {
 			logger.Error(err)
 			return nil, err
@@ -416,6 +462,53 @@ func (dao *OrderDao) UpdateOrderFilledAmounts(hashes []common.Hash, amount []*bi
 	return updatedOrders, nil
 }
 
+// updateStatus applies an update moving an order to a status only if its stored status allows it,
+// an IllegalOrderTransitionError is returned and logged otherwise
+func (dao *OrderDao) updateStatus(h common.Hash, status string, update bson.M) error {
+	if !types.IsValidOrderStatus(status) {
+		err := &types.IllegalOrderTransitionError{To: status}
+		logger.Error(err, h.Hex())
+		return err
+	}
+
+	query := bson.M{
+		"hash":   h.Hex(),
+		"status": bson.M{"$in": types.OrderStatusPredecessors(status)},
+	}
+
+	err := db.Update(dao.dbName, dao.collectionName, query, update)
+	if err == mgo.ErrNotFound {
+		o, getErr := dao.GetByHash(h)
+		if getErr != nil || o == nil {
+			return err
+		}
+
+		err := &types.IllegalOrderTransitionError{From: o.Status, To: status}
+		logger.Error(err, h.Hex())
+		return err
+	}
+
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// clampFilledAmount keeps a filled amount between zero and the amount of the order
+func clampFilledAmount(filled, amount *big.Int) *big.Int {
+	if math.IsEqualOrSmallerThan(filled, big.NewInt(0)) {
+		return big.NewInt(0)
+	}
+
+	if math.IsEqualOrGreaterThan(filled, amount) {
+		return amount
+	}
+
+	return filled
+}
+
 // GetOrderCountByUserAddress get the total number of orders created by a user
 // Return an integer and error
 func (dao *OrderDao) GetOrderCountByUserAddress(addr common.Address) (int, error) {
@@ -477,6 +570,40 @@ func (dao *OrderDao) GetByHashes(hashes []common.Hash) ([]*types.Order, error) {
 	return res, nil
 }
 
+// GetByStatusAndPeriod returns the orders with the status last updated after from and before to
+func (dao *OrderDao) GetByStatusAndPeriod(status string, from, to time.Time) ([]*types.Order, error) {
+	q := bson.M{
+		"status":    status,
+		"updatedAt": bson.M{"$gte": from, "$lt": to},
+	}
+	res := []*types.Order{}
+
+	err := db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetAfterCheckpoint returns up to limit orders updated after the checkpoint and until the given time,
+// in the order of their last update, without their signatures
+func (dao *OrderDao) GetAfterCheckpoint(cp *types.ExportCheckpoint, until time.Time, limit int) ([]*types.Order, error) {
+	res := []*types.Order{}
+	err := db.GetAndSort(dao.dbName, dao.collectionName, exportQuery(cp, until), exportSort, 0, limit, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	for i := range res {
+		dao.removeSignature(res[i])
+	}
+
+	return res, nil
+}
+
 // GetByUserAddress function fetches list of orders from order collection based on user address.
 // Returns array of Order type struct
 func (dao *OrderDao) GetByUserAddress(addr, bt, qt common.Address, from, to int64, limit ...int) ([]*types.Order, error) {
@@ -577,7 +704,7 @@ func (dao *OrderDao) GetOrders(orderSpec types.OrderSpec, sort []string, offset
 	}
 	var res types.OrderRes
 	orders := []*types.Order{}
-	c, err := db.GetEx(dao.dbName, dao.collectionName, q, sort, offset, size, &orders)
+	c, err := db.QueryEx(dao.dbName, dao.collectionName, q, types.FieldsProjection(orderSpec.Fields, types.OrderFields), sort, offset, size, &orders)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
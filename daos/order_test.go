@@ -554,7 +554,7 @@ func TestGetExchangeRate(t *testing.T) {
 
 	pricepoint := math.ToBigInt(bids[0]["pricepoint"])
 
-	tokenAmount := math.Div(math.Mul(transferAmount, pair.PricepointMultiplier()), pricepoint)
+	tokenAmount := pair.BaseAmount(transferAmount, pricepoint)
 
 	t.Logf("transferAmount: %s, tokenAmount: %s",
 		transferAmount, tokenAmount)
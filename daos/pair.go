@@ -1,6 +1,7 @@
 package daos
 
 import (
+	"math/big"
 	"strings"
 	"time"
 
@@ -87,10 +88,11 @@ func (dao *PairDao) GetAll() ([]types.Pair, error) {
 	return ret, nil
 }
 
-// GetAllByCoinbase get pair by coinbase address
-func (dao *PairDao) GetAllByCoinbase(addr common.Address) ([]types.Pair, error) {
+// GetAllByCoinbase get pair by coinbase address, reading only the fields if any
+func (dao *PairDao) GetAllByCoinbase(addr common.Address, fields ...string) ([]types.Pair, error) {
 	var res []types.Pair
-	err := db.Get(dao.dbName, dao.collectionName, bson.M{"relayerAddress": addr.Hex()}, 0, 0, &res)
+	q := bson.M{"relayerAddress": addr.Hex()}
+	err := db.Query(dao.dbName, dao.collectionName, q, types.FieldsProjection(fields, types.PairFields), 0, 0, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -214,6 +216,28 @@ func (dao *PairDao) GetByTokenAddress(baseToken, quoteToken common.Address) (*ty
 	return res[0], nil
 }
 
+// SetActive activates or deactivates the pair of a relayer
+func (dao *PairDao) SetActive(baseToken, quoteToken, relayer common.Address, active bool) error {
+	query := bson.M{"baseTokenAddress": baseToken.Hex(), "quoteTokenAddress": quoteToken.Hex(), "relayerAddress": relayer.Hex()}
+	update := bson.M{"$set": bson.M{"active": active, "updatedAt": time.Now()}}
+	return db.Update(dao.dbName, dao.collectionName, query, update)
+}
+
+// UpdateFeesByCoinbase sets the fees of the pairs of a relayer still charged the previous fees of the
+// relayer, the fees set for a pair by an operator are kept
+func (dao *PairDao) UpdateFeesByCoinbase(addr common.Address, prevMakeFee, prevTakeFee, makeFee, takeFee *big.Int) error {
+	query := bson.M{"relayerAddress": addr.Hex(), "makeFee": prevMakeFee.String(), "takeFee": prevTakeFee.String()}
+	update := bson.M{"$set": bson.M{"makeFee": makeFee.String(), "takeFee": takeFee.String(), "updatedAt": time.Now()}}
+	return db.UpdateAll(dao.dbName, dao.collectionName, query, update)
+}
+
+// SetUnverifiedTokens flags the pairs of the tokens whose contract source is not verified
+func (dao *PairDao) SetUnverifiedTokens(baseToken, quoteToken common.Address, unverified bool) error {
+	query := bson.M{"baseTokenAddress": baseToken.Hex(), "quoteTokenAddress": quoteToken.Hex()}
+	update := bson.M{"$set": bson.M{"unverifiedTokens": unverified}}
+	return db.UpdateAll(dao.dbName, dao.collectionName, query, update)
+}
+
 // DeleteByToken delete token by contract address
 func (dao *PairDao) DeleteByToken(baseAddress common.Address, quoteAddress common.Address) error {
 	query := bson.M{"baseTokenAddress": baseAddress.Hex(), "quoteTokenAddress": quoteAddress.Hex()}
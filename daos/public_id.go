@@ -0,0 +1,78 @@
+package daos
+
+import (
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/utils/snowflake"
+)
+
+// publicIDBatchSize is the number of documents read at once by assignPublicIDs
+const publicIDBatchSize = 1000
+
+// publicIDIndex makes the public ids unique, the documents without a public id yet are left out
+var publicIDIndex = mgo.Index{
+	Key:    []string{"publicId"},
+	Unique: true,
+	Sparse: true,
+}
+
+// assignPublicID gives a public id to a document unless it already has one,
+// it returns the public id of the document
+func assignPublicID(dbName, collection string, id bson.ObjectId) (int64, error) {
+	publicID := snowflake.Next()
+
+	query := bson.M{"_id": id, "publicId": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"publicId": publicID}}
+
+	err := db.Update(dbName, collection, query, update)
+	if err == mgo.ErrNotFound {
+		// assigned in the meantime
+		var doc struct {
+			PublicID int64 `bson:"publicId"`
+		}
+
+		err = db.GetByID(dbName, collection, id, &doc)
+		return doc.PublicID, err
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return publicID, nil
+}
+
+// assignPublicIDs gives a public id to all the documents of a collection without one,
+// in the order they were created. It returns the number of documents updated
+func assignPublicIDs(dbName, collection string) (int, error) {
+	query := bson.M{"publicId": bson.M{"$exists": false}}
+	count := 0
+
+	for {
+		var docs []struct {
+			ID bson.ObjectId `bson:"_id"`
+		}
+
+		err := db.GetAndSort(dbName, collection, query, []string{"createdAt", "_id"}, 0, publicIDBatchSize, &docs)
+		if err != nil {
+			return count, err
+		}
+
+		if len(docs) == 0 {
+			return count, nil
+		}
+
+		for _, doc := range docs {
+			err := db.Update(dbName, collection, bson.M{"_id": doc.ID, "publicId": bson.M{"$exists": false}}, bson.M{"$set": bson.M{"publicId": snowflake.Next()}})
+			if err == mgo.ErrNotFound {
+				continue
+			}
+
+			if err != nil {
+				return count, err
+			}
+
+			count++
+		}
+	}
+}
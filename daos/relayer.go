@@ -134,6 +134,7 @@ func (dao *RelayerDao) UpdateByAddress(addr common.Address, relayer *types.Relay
 			"rid":        relayer.RID,
 			"resign":     relayer.Resign,
 			"lockTime":   relayer.LockTime,
+			"status":     relayer.Status,
 		},
 	}
 	err := db.Update(dao.dbName, dao.collectionName, q, update)
@@ -0,0 +1,90 @@
+package daos
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// RiskLimitDao contains:
+// collectionName: MongoDB collection name
+// dbName: name of mongodb to interact with
+type RiskLimitDao struct {
+	collectionName string
+	dbName         string
+}
+
+// NewRiskLimitDao returns a new instance of RiskLimitDao
+func NewRiskLimitDao() *RiskLimitDao {
+	dao := &RiskLimitDao{}
+	dao.collectionName = "risk_limits"
+	dao.dbName = app.Config.DBName
+
+	i := mgo.Index{
+		Key:    []string{"account", "baseToken", "quoteToken"},
+		Unique: true,
+	}
+
+	err := db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(i)
+	if err != nil {
+		logger.Warning("Index failed", err)
+	}
+
+	return dao
+}
+
+// GetAll returns all the risk limits
+func (dao *RiskLimitDao) GetAll() ([]*types.RiskLimit, error) {
+	res := []*types.RiskLimit{}
+
+	err := db.Get(dao.dbName, dao.collectionName, bson.M{}, 0, 0, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Upsert creates or replaces the risk limit of an account on a pair
+func (dao *RiskLimitDao) Upsert(l *types.RiskLimit) error {
+	if l.ID == "" {
+		l.ID = bson.NewObjectId()
+		l.CreatedAt = time.Now()
+	}
+
+	l.UpdatedAt = time.Now()
+	q := bson.M{
+		"account":    l.Account.Hex(),
+		"baseToken":  l.BaseToken.Hex(),
+		"quoteToken": l.QuoteToken.Hex(),
+	}
+
+	_, err := db.Upsert(dao.dbName, dao.collectionName, q, l)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// Delete removes the risk limit of an account on a pair
+func (dao *RiskLimitDao) Delete(account, bt, qt common.Address) error {
+	q := bson.M{
+		"account":    account.Hex(),
+		"baseToken":  bt.Hex(),
+		"quoteToken": qt.Hex(),
+	}
+
+	return db.RemoveItem(dao.dbName, dao.collectionName, q)
+}
+
+// Drop drops all the risk limits in the current database
+func (dao *RiskLimitDao) Drop() {
+	db.DropCollection(dao.dbName, dao.collectionName)
+}
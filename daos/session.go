@@ -2,6 +2,7 @@ package daos
 
 import (
 	"reflect"
+	"time"
 
 	"github.com/globalsign/mgo"
 	"github.com/globalsign/mgo/bson"
@@ -30,13 +31,41 @@ func InitSession(session *mgo.Session) (*mgo.Session, error) {
 			}
 
 			session = db1
+
+			if app.Config.MongoDBUsername != "" {
+				err = Login(session, app.Config.MongoDBUsername, app.Config.MongoDBPassword)
+				if err != nil {
+					return nil, err
+				}
+			}
 		}
 
+		mgo.SetStats(true)
 		db = &Database{session}
 	}
 	return db.Session, nil
 }
 
+// Login authenticates the session, it is called again when the credentials are rotated
+func Login(session *mgo.Session, username, password string) error {
+	err := session.Login(&mgo.Credential{Username: username, Password: password})
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// Relogin authenticates the global session with new credentials
+func Relogin(username, password string) error {
+	if db == nil {
+		return nil
+	}
+
+	return Login(db.Session, username, password)
+}
+
 func (d *Database) InitDatabase(session *mgo.Session) {
 	d.Session = session
 }
@@ -53,6 +82,7 @@ func (d *Database) GetCollection(dbName, collection string) *mgo.Collection {
 func (d *Database) Create(dbName, collection string, data ...interface{}) (err error) {
 	sc := d.Session.Copy()
 	defer sc.Close()
+	defer observeQuery(collection, time.Now())
 
 	err = sc.DB(dbName).C(collection).Insert(data...)
 	return
@@ -71,6 +101,7 @@ func (d *Database) Watch(dbName, collection string, options mgo.ChangeStreamOpti
 func (d *Database) Count(dbName, collection string, query interface{}) (int, error) {
 	sc := d.Session.Copy()
 	defer sc.Close()
+	defer observeQuery(collection, time.Now())
 
 	return sc.DB(dbName).C(collection).Find(query).Count()
 }
@@ -81,6 +112,7 @@ func (d *Database) Count(dbName, collection string, query interface{}) (int, err
 func (d *Database) GetByID(dbName, collection string, id bson.ObjectId, response interface{}) (err error) {
 	sc := d.Session.Copy()
 	defer sc.Close()
+	defer observeQuery(collection, time.Now())
 
 	err = sc.DB(dbName).C(collection).FindId(id).One(response)
 	return
@@ -92,6 +124,7 @@ func (d *Database) GetByID(dbName, collection string, id bson.ObjectId, response
 func (d *Database) Get(dbName, collection string, query interface{}, offset, limit int, response interface{}) (err error) {
 	sc := d.Session.Copy()
 	defer sc.Close()
+	defer observeQuery(collection, time.Now())
 
 	err = sc.DB(dbName).C(collection).Find(query).Skip(offset).Limit(limit).All(response)
 	return
@@ -101,6 +134,7 @@ func (d *Database) Get(dbName, collection string, query interface{}, offset, lim
 func (d *Database) GetOne(dbName, collection string, query interface{}, response interface{}) (err error) {
 	sc := d.Session.Copy()
 	defer sc.Close()
+	defer observeQuery(collection, time.Now())
 
 	err = sc.DB(dbName).C(collection).Find(query).One(response)
 	// logger.Debug(err, query)
@@ -110,6 +144,7 @@ func (d *Database) GetOne(dbName, collection string, query interface{}, response
 func (d *Database) Query(dbName, collection string, query interface{}, selector interface{}, offset, limit int, response interface{}) (err error) {
 	sc := d.Session.Copy()
 	defer sc.Close()
+	defer observeQuery(collection, time.Now())
 
 	err = sc.DB(dbName).C(collection).Find(query).Skip(offset).Limit(limit).Select(selector).All(response)
 	return
@@ -121,6 +156,7 @@ func (d *Database) Query(dbName, collection string, query interface{}, selector
 func (d *Database) GetAndSort(dbName, collection string, query interface{}, sort []string, offset, limit int, response interface{}) (err error) {
 	sc := d.Session.Copy()
 	defer sc.Close()
+	defer observeQuery(collection, time.Now())
 
 	err = sc.DB(dbName).C(collection).Find(query).Sort(sort...).Skip(offset).Limit(limit).All(response)
 	return
@@ -130,18 +166,31 @@ func (d *Database) GetAndSort(dbName, collection string, query interface{}, sort
 func (d *Database) GetEx(dbName, collection string, query interface{}, sort []string, offset, limit int, response interface{}) (count int, err error) {
 	sc := d.Session.Copy()
 	defer sc.Close()
+	defer observeQuery(collection, time.Now())
 	cursor := sc.DB(dbName).C(collection).Find(query).Sort(sort...)
 	c, _ := cursor.Count()
 	err = cursor.Skip(offset).Limit(limit).All(response)
 	return c, err
 }
 
+// QueryEx is GetEx reading only the fields of the selector
+func (d *Database) QueryEx(dbName, collection string, query interface{}, selector interface{}, sort []string, offset, limit int, response interface{}) (count int, err error) {
+	sc := d.Session.Copy()
+	defer sc.Close()
+	defer observeQuery(collection, time.Now())
+	cursor := sc.DB(dbName).C(collection).Find(query).Sort(sort...)
+	c, _ := cursor.Count()
+	err = cursor.Select(selector).Skip(offset).Limit(limit).All(response)
+	return c, err
+}
+
 // GetSortOne is a wrapper for mgo.Find function with SORT function in pipeline.
 // It creates a copy of session initialized, sends query over this session
 // and returns the session to connection pool
 func (d *Database) GetSortOne(dbName, collection string, query interface{}, sort []string, response interface{}) (err error) {
 	sc := d.Session.Copy()
 	defer sc.Close()
+	defer observeQuery(collection, time.Now())
 	err = sc.DB(dbName).C(collection).Find(query).Sort(sort...).One(response)
 	return
 }
@@ -152,6 +201,7 @@ func (d *Database) GetSortOne(dbName, collection string, query interface{}, sort
 func (d *Database) Update(dbName, collection string, query interface{}, update interface{}) error {
 	sc := d.Session.Copy()
 	defer sc.Close()
+	defer observeQuery(collection, time.Now())
 
 	err := sc.DB(dbName).C(collection).Update(query, update)
 	if err != nil {
@@ -165,6 +215,7 @@ func (d *Database) Update(dbName, collection string, query interface{}, update i
 func (d *Database) Upsert(dbName, collection string, query interface{}, update interface{}) (interface{}, error) {
 	sc := d.Session.Copy()
 	defer sc.Close()
+	defer observeQuery(collection, time.Now())
 
 	changed, err := sc.DB(dbName).C(collection).Upsert(query, update)
 	if err != nil {
@@ -177,6 +228,7 @@ func (d *Database) Upsert(dbName, collection string, query interface{}, update i
 func (d *Database) UpdateAll(dbName, collection string, query interface{}, update interface{}) error {
 	sc := d.Session.Copy()
 	defer sc.Close()
+	defer observeQuery(collection, time.Now())
 
 	_, err := sc.DB(dbName).C(collection).UpdateAll(query, update)
 	if err != nil {
@@ -191,6 +243,7 @@ func (d *Database) UpdateAll(dbName, collection string, query interface{}, updat
 func (d *Database) ChangeAll(dbName, collection string, query interface{}, update interface{}) (*mgo.ChangeInfo, error) {
 	sc := d.Session.Copy()
 	defer sc.Close()
+	defer observeQuery(collection, time.Now())
 
 	changeInfo, err := sc.DB(dbName).C(collection).UpdateAll(query, update)
 	if err != nil {
@@ -204,6 +257,7 @@ func (d *Database) ChangeAll(dbName, collection string, query interface{}, updat
 func (d *Database) FindAndModify(dbName, collection string, query interface{}, change mgo.Change, response interface{}) error {
 	sc := d.Session.Copy()
 	defer sc.Close()
+	defer observeQuery(collection, time.Now())
 
 	_, err := sc.DB(dbName).C(collection).Find(query).Apply(change, response)
 	if err != nil {
@@ -218,6 +272,7 @@ func (d *Database) FindAndModify(dbName, collection string, query interface{}, c
 func (d *Database) AggregateEx(dbName, collection string, query []bson.M, response interface{}) error {
 	sc := d.Session.Copy()
 	defer sc.Close()
+	defer observeQuery(collection, time.Now())
 
 	result := reflect.ValueOf(response).Interface()
 	c := mgo.Collation{
@@ -245,6 +300,7 @@ func (d *Database) Aggregate(dbName, collection string, query []bson.M, response
 func (d *Database) Remove(dbName, collection string, query []bson.M) error {
 	sc := d.Session.Copy()
 	defer sc.Close()
+	defer observeQuery(collection, time.Now())
 
 	err := sc.DB(dbName).C(collection).Remove(query)
 	if err != nil {
@@ -259,6 +315,7 @@ func (d *Database) Remove(dbName, collection string, query []bson.M) error {
 func (d *Database) RemoveItem(dbName, collection string, query interface{}) error {
 	sc := d.Session.Copy()
 	defer sc.Close()
+	defer observeQuery(collection, time.Now())
 
 	err := sc.DB(dbName).C(collection).Remove(query)
 	if err != nil {
@@ -273,6 +330,7 @@ func (d *Database) RemoveItem(dbName, collection string, query interface{}) erro
 func (d *Database) RemoveAll(dbName, collection string, query interface{}) error {
 	sc := d.Session.Copy()
 	defer sc.Close()
+	defer observeQuery(collection, time.Now())
 
 	_, err := sc.DB(dbName).C(collection).RemoveAll(query)
 	if err != nil {
@@ -287,6 +345,7 @@ func (d *Database) RemoveAll(dbName, collection string, query interface{}) error
 func (d *Database) DropCollection(dbName, collection string) error {
 	sc := d.Session.Copy()
 	defer sc.Close()
+	defer observeQuery(collection, time.Now())
 
 	err := sc.DB(dbName).C(collection).DropCollection()
 	if err != nil {
@@ -0,0 +1,126 @@
+package daos
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// SettlementDao contains:
+// collectionName: MongoDB collection name
+// dbName: name of mongodb to interact with
+type SettlementDao struct {
+	collectionName string
+	dbName         string
+}
+
+// NewSettlementDao returns a new instance of SettlementDao
+func NewSettlementDao() *SettlementDao {
+	dao := &SettlementDao{}
+	dao.collectionName = "settlements"
+	dao.dbName = app.Config.DBName
+
+	i1 := mgo.Index{
+		Key:    []string{"tradeHash"},
+		Unique: true,
+	}
+
+	i2 := mgo.Index{
+		Key: []string{"status", "nextRetryAt"},
+	}
+
+	for _, i := range []mgo.Index{i1, i2} {
+		err := db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(i)
+		if err != nil {
+			logger.Warning("Index failed", err)
+		}
+	}
+
+	return dao
+}
+
+// Create inserts a new settlement
+func (dao *SettlementDao) Create(s *types.Settlement) error {
+	s.ID = bson.NewObjectId()
+	s.CreatedAt = time.Now()
+	s.UpdatedAt = time.Now()
+
+	err := db.Create(dao.dbName, dao.collectionName, s)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetByTradeHash returns the settlement of a trade
+func (dao *SettlementDao) GetByTradeHash(h common.Hash) (*types.Settlement, error) {
+	q := bson.M{"tradeHash": h.Hex()}
+	res := []*types.Settlement{}
+
+	err := db.Get(dao.dbName, dao.collectionName, q, 0, 1, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, nil
+	}
+
+	return res[0], nil
+}
+
+// GetByStatus returns the settlements with one of the statuses, oldest first
+func (dao *SettlementDao) GetByStatus(statuses ...string) ([]*types.Settlement, error) {
+	q := bson.M{"status": bson.M{"$in": statuses}}
+	res := []*types.Settlement{}
+
+	err := db.GetAndSort(dao.dbName, dao.collectionName, q, []string{"createdAt"}, 0, 0, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetDue returns the settlements waiting for a retry whose retry time is reached
+func (dao *SettlementDao) GetDue(now time.Time) ([]*types.Settlement, error) {
+	q := bson.M{
+		"status":      types.SettlementStatusRetrying,
+		"nextRetryAt": bson.M{"$lte": now},
+	}
+	res := []*types.Settlement{}
+
+	err := db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Update replaces a settlement
+func (dao *SettlementDao) Update(s *types.Settlement) error {
+	s.UpdatedAt = time.Now()
+
+	err := db.Update(dao.dbName, dao.collectionName, bson.M{"_id": s.ID}, s)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// Drop drops all the settlements in the current database
+func (dao *SettlementDao) Drop() {
+	db.DropCollection(dao.dbName, dao.collectionName)
+}
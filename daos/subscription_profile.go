@@ -0,0 +1,109 @@
+package daos
+
+import (
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// SubscriptionProfileDao contains:
+// collectionName: MongoDB collection name
+// dbName: name of mongodb to interact with
+type SubscriptionProfileDao struct {
+	collectionName string
+	dbName         string
+}
+
+// NewSubscriptionProfileDao returns a new instance of SubscriptionProfileDao
+func NewSubscriptionProfileDao() *SubscriptionProfileDao {
+	dao := &SubscriptionProfileDao{}
+	dao.collectionName = "subscription_profiles"
+	dao.dbName = app.Config.DBName
+
+	i1 := mgo.Index{
+		Key:    []string{"owner", "name"},
+		Unique: true,
+	}
+
+	err := db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(i1)
+	if err != nil {
+		logger.Warning("Index failed", err)
+	}
+
+	return dao
+}
+
+// Save creates or replaces the profile of an owner with the same name
+func (dao *SubscriptionProfileDao) Save(p *types.SubscriptionProfile) error {
+	now := time.Now()
+	q := bson.M{"owner": p.Owner, "name": p.Name}
+	u := bson.M{
+		"$set": bson.M{
+			"subscriptions": p.Subscriptions,
+			"updatedAt":     now,
+		},
+		"$setOnInsert": bson.M{
+			"_id":       bson.NewObjectId(),
+			"createdAt": now,
+		},
+	}
+
+	_, err := db.Upsert(dao.dbName, dao.collectionName, q, u)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetByOwner returns the profiles of an owner
+func (dao *SubscriptionProfileDao) GetByOwner(owner string) ([]*types.SubscriptionProfile, error) {
+	res := []*types.SubscriptionProfile{}
+	err := db.GetAndSort(dao.dbName, dao.collectionName, bson.M{"owner": owner}, []string{"name"}, 0, 0, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetByName returns the profile of an owner with the name, nil if it does not exist
+func (dao *SubscriptionProfileDao) GetByName(owner, name string) (*types.SubscriptionProfile, error) {
+	res := []*types.SubscriptionProfile{}
+	err := db.Get(dao.dbName, dao.collectionName, bson.M{"owner": owner, "name": name}, 0, 1, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, nil
+	}
+
+	return res[0], nil
+}
+
+// Count returns the number of profiles of an owner
+func (dao *SubscriptionProfileDao) Count(owner string) (int, error) {
+	return db.Count(dao.dbName, dao.collectionName, bson.M{"owner": owner})
+}
+
+// Delete removes the profile of an owner
+func (dao *SubscriptionProfileDao) Delete(owner, name string) error {
+	err := db.RemoveItem(dao.dbName, dao.collectionName, bson.M{"owner": owner, "name": name})
+	if err == mgo.ErrNotFound {
+		return nil
+	}
+
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
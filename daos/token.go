@@ -166,6 +166,29 @@ func (dao *TokenDao) GetBySymbol(symbol string) (*types.Token, error) {
 	return &resp[0], nil
 }
 
+// GetAllBySymbol returns the tokens of different contracts sharing a symbol
+func (dao *TokenDao) GetAllBySymbol(symbol string) ([]types.Token, error) {
+	var res []types.Token
+	err := db.Get(dao.dbName, dao.collectionName, bson.M{"symbol": symbol}, 0, 0, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	ret := []types.Token{}
+	keys := make(map[string]bool)
+
+	for _, it := range res {
+		code := it.ContractAddress.Hex()
+		if !keys[code] {
+			keys[code] = true
+			ret = append(ret, it)
+		}
+	}
+
+	return ret, nil
+}
+
 // GetByAddress function fetches details of a token based on its contract address
 func (dao *TokenDao) GetByAddress(addr common.Address) (*types.Token, error) {
 	q := bson.M{"contractAddress": addr.Hex()}
@@ -200,13 +223,26 @@ func (dao *TokenDao) UpdateFiatPriceBySymbol(symbol string, price float64) error
 func (dao *TokenDao) UpdateByToken(addr common.Address, token *types.Token) error {
 	q := bson.M{"contractAddress": addr.Hex()}
 
-	update := bson.M{
-		"$set": bson.M{
-			"makeFee": token.MakeFee.String(),
-			"takeFee": token.TakeFee.String(),
-		},
+	set := bson.M{
+		"makeFee": token.MakeFee.String(),
+		"takeFee": token.TakeFee.String(),
+		"trc21":   token.TRC21,
+		"issuer":  "",
+		"minFee":  "",
 	}
-	err := db.Update(dao.dbName, dao.collectionName, q, update)
+
+	if token.TRC21 {
+		set["issuer"] = token.Issuer.Hex()
+		if token.MinFee != nil {
+			set["minFee"] = token.MinFee.String()
+		}
+	}
+
+	if token.Collateral != nil {
+		set["collateral"] = token.Collateral
+	}
+
+	err := db.Update(dao.dbName, dao.collectionName, q, bson.M{"$set": set})
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -218,13 +254,68 @@ func (dao *TokenDao) UpdateByToken(addr common.Address, token *types.Token) erro
 func (dao *TokenDao) UpdateByTokenAndCoinbase(addr common.Address, coinbase common.Address, token *types.Token) error {
 	q := bson.M{"contractAddress": addr.Hex(), "relayerAddress": coinbase.Hex()}
 
-	update := bson.M{
-		"$set": bson.M{
-			"makeFee": token.MakeFee.String(),
-			"takeFee": token.TakeFee.String(),
-		},
+	set := bson.M{
+		"makeFee": token.MakeFee.String(),
+		"takeFee": token.TakeFee.String(),
+		"trc21":   token.TRC21,
+		"issuer":  "",
+		"minFee":  "",
 	}
-	err := db.Update(dao.dbName, dao.collectionName, q, update)
+
+	if token.TRC21 {
+		set["issuer"] = token.Issuer.Hex()
+		if token.MinFee != nil {
+			set["minFee"] = token.MinFee.String()
+		}
+	}
+
+	if token.Collateral != nil {
+		set["collateral"] = token.Collateral
+	}
+
+	err := db.Update(dao.dbName, dao.collectionName, q, bson.M{"$set": set})
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// SetVerified marks a token contract as verified or not for all the relayers
+func (dao *TokenDao) SetVerified(addr common.Address, verified bool) error {
+	q := bson.M{"contractAddress": addr.Hex()}
+	update := bson.M{"$set": bson.M{"verified": verified, "updatedAt": time.Now()}}
+
+	err := db.UpdateAll(dao.dbName, dao.collectionName, q, update)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// SetSourceVerified records whether the source of a token contract is verified, for all the relayers
+func (dao *TokenDao) SetSourceVerified(addr common.Address, verified bool) error {
+	q := bson.M{"contractAddress": addr.Hex()}
+	update := bson.M{"$set": bson.M{"sourceVerified": verified}}
+
+	err := db.UpdateAll(dao.dbName, dao.collectionName, q, update)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// SetSafety records the result of the safety checks of a token contract, for all the relayers
+func (dao *TokenDao) SetSafety(addr common.Address, safety *types.TokenSafety) error {
+	q := bson.M{"contractAddress": addr.Hex()}
+	update := bson.M{"$set": bson.M{"safety": safety}}
+
+	err := db.UpdateAll(dao.dbName, dao.collectionName, q, update)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -0,0 +1,95 @@
+package daos
+
+import (
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// TokenAliasDao contains:
+// collectionName: MongoDB collection name
+// dbName: name of mongodb to interact with
+type TokenAliasDao struct {
+	collectionName string
+	dbName         string
+}
+
+// NewTokenAliasDao returns a new instance of TokenAliasDao
+func NewTokenAliasDao() *TokenAliasDao {
+	dao := &TokenAliasDao{}
+	dao.collectionName = "token_aliases"
+	dao.dbName = app.Config.DBName
+
+	i := mgo.Index{
+		Key:    []string{"symbol"},
+		Unique: true,
+	}
+
+	err := db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(i)
+	if err != nil {
+		logger.Warning("Index failed", err)
+	}
+
+	return dao
+}
+
+// GetAll returns all the symbol aliases
+func (dao *TokenAliasDao) GetAll() ([]*types.TokenAlias, error) {
+	res := []*types.TokenAlias{}
+
+	err := db.GetAndSort(dao.dbName, dao.collectionName, bson.M{}, []string{"symbol"}, 0, 0, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetBySymbol returns the alias of a symbol, nil if it has none
+func (dao *TokenAliasDao) GetBySymbol(symbol string) (*types.TokenAlias, error) {
+	res := []*types.TokenAlias{}
+
+	err := db.Get(dao.dbName, dao.collectionName, bson.M{"symbol": symbol}, 0, 1, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, nil
+	}
+
+	return res[0], nil
+}
+
+// Upsert creates or replaces the alias of a symbol
+func (dao *TokenAliasDao) Upsert(a *types.TokenAlias) error {
+	if a.ID == "" {
+		a.ID = bson.NewObjectId()
+		a.CreatedAt = time.Now()
+	}
+
+	a.UpdatedAt = time.Now()
+
+	_, err := db.Upsert(dao.dbName, dao.collectionName, bson.M{"symbol": a.Symbol}, a)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// Delete removes the alias of a symbol
+func (dao *TokenAliasDao) Delete(symbol string) error {
+	return db.RemoveItem(dao.dbName, dao.collectionName, bson.M{"symbol": symbol})
+}
+
+// Drop drops all the symbol aliases in the current database
+func (dao *TokenAliasDao) Drop() {
+	db.DropCollection(dao.dbName, dao.collectionName)
+}
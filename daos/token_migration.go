@@ -0,0 +1,86 @@
+package daos
+
+import (
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// TokenMigrationDao contains:
+// collectionName: MongoDB collection name
+// dbName: name of mongodb to interact with
+type TokenMigrationDao struct {
+	collectionName string
+	dbName         string
+}
+
+// NewTokenMigrationDao returns a new instance of TokenMigrationDao
+func NewTokenMigrationDao() *TokenMigrationDao {
+	dao := &TokenMigrationDao{}
+	dao.collectionName = "token_migrations"
+	dao.dbName = app.Config.DBName
+
+	i := mgo.Index{
+		Key: []string{"oldToken", "createdAt"},
+	}
+
+	err := db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(i)
+	if err != nil {
+		logger.Warning("Index failed", err)
+	}
+
+	return dao
+}
+
+// Create inserts the report of a token migration
+func (dao *TokenMigrationDao) Create(m *types.TokenMigration) error {
+	m.ID = bson.NewObjectId()
+	m.CreatedAt = time.Now()
+	m.UpdatedAt = time.Now()
+
+	err := db.Create(dao.dbName, dao.collectionName, m)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetByID returns the report of a token migration, nil if it does not exist
+func (dao *TokenMigrationDao) GetByID(id bson.ObjectId) (*types.TokenMigration, error) {
+	res := []*types.TokenMigration{}
+
+	err := db.Get(dao.dbName, dao.collectionName, bson.M{"_id": id}, 0, 1, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, nil
+	}
+
+	return res[0], nil
+}
+
+// GetAll returns the reports of the token migrations, latest first
+func (dao *TokenMigrationDao) GetAll() ([]*types.TokenMigration, error) {
+	res := []*types.TokenMigration{}
+
+	err := db.GetAndSort(dao.dbName, dao.collectionName, bson.M{}, []string{"-createdAt"}, 0, 0, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Drop drops all the token migrations in the current database
+func (dao *TokenMigrationDao) Drop() {
+	db.DropCollection(dao.dbName, dao.collectionName)
+}
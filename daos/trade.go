@@ -1,6 +1,7 @@
 package daos
 
 import (
+	"math/big"
 	"strconv"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/globalsign/mgo/bson"
 	"github.com/tomochain/tomox-sdk/app"
 	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/snowflake"
 )
 
 // TradeDao contains:
@@ -27,7 +29,28 @@ func NewTradeDao() *TradeDao {
 		Key: []string{"createdAt"},
 	}
 
-	db.Session.DB(dbName).C(collection).EnsureIndex(i3)
+	i4 := mgo.Index{
+		Key: []string{"baseToken", "quoteToken", "createdAt"},
+	}
+
+	i5 := mgo.Index{
+		Key: []string{"baseToken", "quoteToken", "takerOrderSide", "createdAt"},
+	}
+
+	i6 := mgo.Index{
+		Key: []string{"maker", "createdAt"},
+	}
+
+	i7 := mgo.Index{
+		Key: []string{"taker", "createdAt"},
+	}
+
+	for _, i := range []mgo.Index{i3, i4, i5, i6, i7, publicIDIndex, exportIndex} {
+		err := db.Session.DB(dbName).C(collection).EnsureIndex(i)
+		if err != nil {
+			logger.Warning("Index failed", err)
+		}
+	}
 	return &TradeDao{collection, dbName}
 }
 
@@ -53,6 +76,9 @@ func (dao *TradeDao) Create(trades ...*types.Trade) error {
 		trade.ID = bson.NewObjectId()
 		trade.CreatedAt = time.Now()
 		trade.UpdatedAt = time.Now()
+		if trade.PublicID == 0 {
+			trade.PublicID = snowflake.Next()
+		}
 		y = append(y, trade)
 	}
 
@@ -65,6 +91,28 @@ func (dao *TradeDao) Create(trades ...*types.Trade) error {
 	return nil
 }
 
+// AssignPublicID gives a public id to a trade stored without one, the trade is updated with its public id
+func (dao *TradeDao) AssignPublicID(t *types.Trade) error {
+	publicID, err := assignPublicID(dao.dbName, dao.collectionName, t.ID)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	t.PublicID = publicID
+	return nil
+}
+
+// AssignPublicIDs gives a public id to all the trades stored without one, it returns the number of trades updated
+func (dao *TradeDao) AssignPublicIDs() (int, error) {
+	count, err := assignPublicIDs(dao.dbName, dao.collectionName)
+	if err != nil {
+		logger.Error(err)
+	}
+
+	return count, err
+}
+
 func (dao *TradeDao) DeleteByHashes(hashes ...common.Hash) error {
 	err := db.RemoveAll(dao.dbName, dao.collectionName, bson.M{"hash": bson.M{"$in": hashes}})
 	if err != nil {
@@ -193,6 +241,124 @@ func (dao *TradeDao) Aggregate(q []bson.M) ([]*types.Tick, error) {
 	return res, nil
 }
 
+// GetVolumeProfile returns the volume traded since from per price level and taker side.
+// The price levels are the multiples of priceStep
+func (dao *TradeDao) GetVolumeProfile(bt, qt common.Address, from time.Time, priceStep *big.Int) ([]*types.TradeVolumeLevel, error) {
+	step, err := bson.ParseDecimal128(priceStep.String())
+	if err != nil {
+		return nil, err
+	}
+
+	price := bson.M{"$toDecimal": "$pricepoint"}
+	q := []bson.M{
+		{"$match": bson.M{
+			"baseToken":  bt.Hex(),
+			"quoteToken": qt.Hex(),
+			"createdAt":  bson.M{"$gte": from},
+			"status":     bson.M{"$ne": types.TradeStatusError},
+		}},
+		{"$group": bson.M{
+			"_id": bson.M{
+				"pricepoint": bson.M{"$subtract": []interface{}{price, bson.M{"$mod": []interface{}{price, step}}}},
+				"side":       "$takerOrderSide",
+			},
+			"volume": bson.M{"$sum": bson.M{"$toDecimal": "$amount"}},
+			"count":  bson.M{"$sum": 1},
+		}},
+		{"$sort": bson.M{"_id.pricepoint": 1}},
+	}
+
+	res := []*types.TradeVolumeLevel{}
+	err = db.Aggregate(dao.dbName, dao.collectionName, q, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetByPairAndPeriod returns the trades of a pair created between from included and to excluded
+func (dao *TradeDao) GetByPairAndPeriod(bt, qt common.Address, from, to time.Time) ([]*types.Trade, error) {
+	res := []*types.Trade{}
+	q := bson.M{
+		"baseToken":  bt.Hex(),
+		"quoteToken": qt.Hex(),
+		"createdAt":  bson.M{"$gte": from, "$lt": to},
+	}
+
+	err := db.GetAndSort(dao.dbName, dao.collectionName, q, []string{"createdAt"}, 0, 0, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetAfterCheckpoint returns up to limit trades updated after the checkpoint and until the given time,
+// in the order of their last update
+func (dao *TradeDao) GetAfterCheckpoint(cp *types.ExportCheckpoint, until time.Time, limit int) ([]*types.Trade, error) {
+	res := []*types.Trade{}
+	err := db.GetAndSort(dao.dbName, dao.collectionName, exportQuery(cp, until), exportSort, 0, limit, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetByUserAndPeriod returns the trades of a maker or a taker created after from and until to included
+func (dao *TradeDao) GetByUserAndPeriod(a common.Address, from, to time.Time) ([]*types.Trade, error) {
+	res := []*types.Trade{}
+	q := bson.M{
+		"createdAt": bson.M{"$gt": from, "$lte": to},
+		"$or": []bson.M{
+			{"maker": a.Hex()},
+			{"taker": a.Hex()},
+		},
+	}
+
+	err := db.GetAndSort(dao.dbName, dao.collectionName, q, []string{"createdAt"}, 0, 0, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// CountUniqueTraders returns the number of distinct makers and takers of the trades since the date
+func (dao *TradeDao) CountUniqueTraders(from time.Time) (int, error) {
+	q := []bson.M{
+		{"$match": bson.M{
+			"createdAt": bson.M{"$gte": from},
+			"status":    bson.M{"$ne": types.TradeStatusError},
+		}},
+		{"$project": bson.M{"traders": []string{"$maker", "$taker"}}},
+		{"$unwind": "$traders"},
+		{"$group": bson.M{"_id": "$traders"}},
+		{"$count": "count"},
+	}
+
+	res := []struct {
+		Count int `bson:"count"`
+	}{}
+
+	err := db.Aggregate(dao.dbName, dao.collectionName, q, &res)
+	if err != nil {
+		logger.Error(err)
+		return 0, err
+	}
+
+	if len(res) == 0 {
+		return 0, nil
+	}
+
+	return res[0].Count, nil
+}
+
 // GetByPairName fetches all the trades corresponding to a particular pair name.
 func (dao *TradeDao) GetByPairName(name string) ([]*types.Trade, error) {
 	var res []*types.Trade
@@ -221,6 +387,10 @@ func (dao *TradeDao) GetByHash(h common.Hash) (*types.Trade, error) {
 		return nil, err
 	}
 
+	if len(res) == 0 {
+		return nil, nil
+	}
+
 	return res[0], nil
 }
 
@@ -269,6 +439,28 @@ func (dao *TradeDao) GetByOrderHashes(hashes []common.Hash) ([]*types.Trade, err
 	return res, nil
 }
 
+// GetByMakerOrTakerOrderHashes returns the trades whose maker or taker order is one of the hashes
+func (dao *TradeDao) GetByMakerOrTakerOrderHashes(hashes []common.Hash) ([]*types.Trade, error) {
+	hexes := []string{}
+	for _, h := range hashes {
+		hexes = append(hexes, h.Hex())
+	}
+
+	q := bson.M{"$or": []bson.M{
+		{"makerOrderHash": bson.M{"$in": hexes}},
+		{"takerOrderHash": bson.M{"$in": hexes}},
+	}}
+	res := []*types.Trade{}
+
+	err := db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
 func (dao *TradeDao) GetSortedTrades(bt, qt common.Address, from, to int64, n int) ([]*types.Trade, error) {
 	res := make([]*types.Trade, 0)
 
@@ -558,10 +750,18 @@ func (dao *TradeDao) GetTrades(tradeSpec *types.TradeSpec, sortedBy []string, pa
 	if tradeSpec.QuoteToken != "" {
 		q["quoteToken"] = tradeSpec.QuoteToken
 	}
+	if tradeSpec.Address != "" {
+		addRoleFilter(q, tradeSpec.Address, tradeSpec.Role)
+	}
+
+	err := addTradeFilters(q, tradeSpec)
+	if err != nil {
+		return nil, err
+	}
 
 	var res types.TradeRes
 	trades := []*types.Trade{}
-	c, err := db.GetEx(dao.dbName, dao.collectionName, q, sortedBy, pageOffset, pageSize, &trades)
+	c, err := db.QueryEx(dao.dbName, dao.collectionName, q, types.FieldsProjection(tradeSpec.Fields, types.TradeFields), sortedBy, pageOffset, pageSize, &trades)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -571,6 +771,40 @@ func (dao *TradeDao) GetTrades(tradeSpec *types.TradeSpec, sortedBy []string, pa
 	return &res, nil
 }
 
+// addRoleFilter restricts the query to the trades of the address as maker, taker or both
+func addRoleFilter(q bson.M, address string, role string) {
+	switch role {
+	case types.TradeRoleMaker:
+		q["maker"] = address
+	case types.TradeRoleTaker:
+		q["taker"] = address
+	default:
+		q["$or"] = []bson.M{
+			{"maker": address},
+			{"taker": address},
+		}
+	}
+}
+
+// addTradeFilters adds the taker side and minimum amount filters of the spec to the query
+func addTradeFilters(q bson.M, tradeSpec *types.TradeSpec) error {
+	if tradeSpec.Side != "" {
+		q["takerOrderSide"] = tradeSpec.Side
+	}
+
+	if tradeSpec.MinAmount != nil {
+		// amounts are stored as strings and have to be compared as decimals
+		min, err := bson.ParseDecimal128(tradeSpec.MinAmount.String())
+		if err != nil {
+			return err
+		}
+
+		q["$expr"] = bson.M{"$gte": []interface{}{bson.M{"$toDecimal": "$amount"}, min}}
+	}
+
+	return nil
+}
+
 // GetTradeByTime get range trade
 func (dao *TradeDao) GetTradeByTime(dateFrom, dateTo int64, pageOffset int, pageSize int) ([]*types.Trade, error) {
 	q := bson.M{}
@@ -604,12 +838,6 @@ func (dao *TradeDao) GetTradesUserHistory(a common.Address, tradeSpec *types.Tra
 		q["createdAt"] = dateFilter
 	}
 	q["$and"] = []bson.M{
-		{
-			"$or": []bson.M{
-				{"maker": a.Hex()},
-				{"taker": a.Hex()},
-			},
-		},
 		{
 			"$or": []bson.M{
 				{"makerExchange": tradeSpec.RelayerAddress.Hex()},
@@ -617,15 +845,22 @@ func (dao *TradeDao) GetTradesUserHistory(a common.Address, tradeSpec *types.Tra
 			},
 		},
 	}
+	addRoleFilter(q, a.Hex(), tradeSpec.Role)
 	if tradeSpec.BaseToken != "" {
 		q["baseToken"] = tradeSpec.BaseToken
 	}
 	if tradeSpec.QuoteToken != "" {
 		q["quoteToken"] = tradeSpec.QuoteToken
 	}
+
+	err := addTradeFilters(q, tradeSpec)
+	if err != nil {
+		return nil, err
+	}
+
 	var res types.TradeRes
 	trades := []*types.Trade{}
-	c, err := db.GetEx(dao.dbName, dao.collectionName, q, sortedBy, pageOffset, pageSize, &trades)
+	c, err := db.QueryEx(dao.dbName, dao.collectionName, q, types.FieldsProjection(tradeSpec.Fields, types.TradeFields), sortedBy, pageOffset, pageSize, &trades)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -36,6 +36,17 @@ func (dao *WalletDao) Create(wallet *types.Wallet) error {
 	return nil
 }
 
+// Update saves the wallet, the private key is encrypted again with the current master key
+func (dao *WalletDao) Update(wallet *types.Wallet) error {
+	err := db.Update(dao.dbName, dao.collectionName, bson.M{"_id": wallet.ID}, wallet)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
 func (dao *WalletDao) GetAll() ([]types.Wallet, error) {
 	var response []types.Wallet
 
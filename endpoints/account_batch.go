@@ -0,0 +1,54 @@
+package endpoints
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/justinas/alice"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/middlewares"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+)
+
+type accountBatchEndpoint struct {
+	accountBatchService interfaces.AccountBatchService
+}
+
+// ServeAccountBatchResource sets up the routing of the batch account queries, rate limited by their own limiter
+func ServeAccountBatchResource(
+	r *mux.Router,
+	accountBatchService interfaces.AccountBatchService,
+	limiter *middlewares.RateLimiter,
+) {
+	e := &accountBatchEndpoint{accountBatchService}
+
+	r.Handle(
+		"/api/accounts/batch",
+		alice.New(limiter.Limit).Then(http.HandlerFunc(e.handleGetBatch)),
+	).Methods("POST")
+}
+
+// handleGetBatch returns the balances, the number of open orders and the volumes of the last 24 hours
+// of the addresses of the payload
+func (e *accountBatchEndpoint) handleGetBatch(w http.ResponseWriter, r *http.Request) {
+	p := &types.AccountBatchPayload{}
+
+	decoder := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+
+	err := decoder.Decode(p)
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
+		return
+	}
+
+	err = p.Validate(e.accountBatchService.MaxAddresses())
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, e.accountBatchService.GetBatch(p.Addresses))
+}
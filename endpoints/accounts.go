@@ -1,29 +1,40 @@
 package endpoints
 
 import (
-	"encoding/json"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/gorilla/mux"
 	"github.com/justinas/alice"
 	"github.com/tomochain/tomox-sdk/interfaces"
 	"github.com/tomochain/tomox-sdk/middlewares"
+	"github.com/tomochain/tomox-sdk/services"
 	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/usage"
 	"github.com/tomochain/tomox-sdk/utils"
 	"github.com/tomochain/tomox-sdk/utils/httputils"
 )
 
 type AccountEndpoint struct {
-	AccountService interfaces.AccountService
+	AccountService        interfaces.AccountService
+	BalanceHistoryService interfaces.BalanceHistoryService
+	AccessLogService      interfaces.AccessLogService
 }
 
 func ServeAccountResource(
 	r *mux.Router,
 	accountService interfaces.AccountService,
+	balanceHistoryService interfaces.BalanceHistoryService,
+	accessLogService interfaces.AccessLogService,
 ) {
 
-	e := &AccountEndpoint{AccountService: accountService}
+	e := &AccountEndpoint{
+		AccountService:        accountService,
+		BalanceHistoryService: balanceHistoryService,
+		AccessLogService:      accessLogService,
+	}
 
 	/*
 		r.Handle(
@@ -48,10 +59,20 @@ func ServeAccountResource(
 			).Methods("POST")
 	*/
 
+	// registered before "/api/account/{address}" which would match it
+	r.Handle(
+		"/api/account/usage", http.HandlerFunc(e.handleGetUsage),
+	).Methods("GET")
+
 	r.Handle(
 		"/api/account/{address}", http.HandlerFunc(e.handleGetAccount),
 	).Methods("GET")
 
+	// registered before "/api/account/{address}/{token}" which would match it
+	r.Handle(
+		"/api/account/{address}/balances", http.HandlerFunc(e.handleGetBalancesAt),
+	).Methods("GET")
+
 	r.Handle(
 		"/api/account/{address}/{token}", http.HandlerFunc(e.handleGetAccountTokenBalance),
 	).Methods("GET")
@@ -114,6 +135,57 @@ func (e *AccountEndpoint) handleGetAccount(w http.ResponseWriter, r *http.Reques
 	httputils.WriteJSON(w, http.StatusOK, a)
 }
 
+// handleGetUsage returns the API usage of the caller, identified by its API key ("X-Api-Key" header),
+// its signature public key ("Pubkey" header), the "address" param or its IP. The calls per route
+// are added when the access logs are enabled
+func (e *AccountEndpoint) handleGetUsage(w http.ResponseWriter, r *http.Request) {
+	key := usage.Key(r)
+	u := usage.GetTracker().Get(key)
+
+	if e.AccessLogService.Enabled() {
+		routes, err := e.AccessLogService.GetUserStats(key, u.WindowStart)
+		if err != nil {
+			logger.Error(err)
+			httputils.WriteError(w, http.StatusInternalServerError, "")
+			return
+		}
+
+		u.Routes = routes
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, u)
+}
+
+// handleGetBalancesAt returns the approximate balances of an address at the "at" param,
+// a unix timestamp in seconds, reconstructed from the balance snapshots and the trades
+func (e *AccountEndpoint) handleGetBalancesAt(w http.ResponseWriter, r *http.Request) {
+	addr := mux.Vars(r)["address"]
+	if !common.IsHexAddress(addr) {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid Address")
+		return
+	}
+
+	ts, err := strconv.ParseInt(r.URL.Query().Get("at"), 10, 64)
+	if err != nil || time.Unix(ts, 0).After(time.Now()) {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid at")
+		return
+	}
+
+	res, err := e.BalanceHistoryService.GetBalancesAt(common.HexToAddress(addr), time.Unix(ts, 0))
+	if err == services.ErrBalanceSnapshotNotFound {
+		httputils.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, res)
+}
+
 func (e *AccountEndpoint) handleGetAccountTokenBalance(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 
@@ -171,11 +243,9 @@ func (e *AccountEndpoint) handleGetFavoriteTokens(w http.ResponseWriter, r *http
 
 func (e *AccountEndpoint) handleAddFavoriteToken(w http.ResponseWriter, r *http.Request) {
 	var tr *types.FavoriteTokenRequest
-	decoder := json.NewDecoder(r.Body)
-
 	defer r.Body.Close()
 
-	err := decoder.Decode(&tr)
+	err := httputils.DecodeJSON(r, &tr)
 	if err != nil {
 		logger.Error(err)
 		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
@@ -216,11 +286,9 @@ func (e *AccountEndpoint) handleAddFavoriteToken(w http.ResponseWriter, r *http.
 
 func (e *AccountEndpoint) handleRemoveFavoriteToken(w http.ResponseWriter, r *http.Request) {
 	var tr *types.FavoriteTokenRequest
-	decoder := json.NewDecoder(r.Body)
-
 	defer r.Body.Close()
 
-	err := decoder.Decode(&tr)
+	err := httputils.DecodeJSON(r, &tr)
 	if err != nil {
 		logger.Error(err)
 		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
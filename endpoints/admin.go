@@ -0,0 +1,439 @@
+package endpoints
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/globalsign/mgo/bson"
+	"github.com/gorilla/mux"
+	"github.com/justinas/alice"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/middlewares"
+	"github.com/tomochain/tomox-sdk/services"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/usage"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+	"github.com/tomochain/tomox-sdk/utils/pdf"
+)
+
+type adminEndpoint struct {
+	auditService      interfaces.AuditService
+	settlementService interfaces.SettlementService
+	disputeService    interfaces.DisputeService
+	listingService    interfaces.ListingService
+	accessLogService  interfaces.AccessLogService
+}
+
+// ServeAdminResource sets up the routing of the admin endpoints
+func ServeAdminResource(
+	r *mux.Router,
+	auditService interfaces.AuditService,
+	settlementService interfaces.SettlementService,
+	disputeService interfaces.DisputeService,
+	listingService interfaces.ListingService,
+	accessLogService interfaces.AccessLogService,
+	rbac *middlewares.RBAC,
+) {
+	e := &adminEndpoint{auditService, settlementService, disputeService, listingService, accessLogService}
+
+	r.Handle(
+		"/api/admin/whoami",
+		alice.New(rbac.Require(types.RoleViewer, "admin.whoami")).Then(http.HandlerFunc(e.handleWhoAmI)),
+	).Methods("GET")
+
+	r.Handle(
+		"/api/admin/audit",
+		alice.New(rbac.Require(types.RoleAdmin, "admin.audit")).Then(http.HandlerFunc(e.handleGetAuditLogs)),
+	).Methods("GET")
+
+	r.Handle(
+		"/api/admin/auth/blocked",
+		alice.New(rbac.Require(types.RoleOperator, "admin.auth.blocked")).Then(http.HandlerFunc(e.handleGetAuthGuardStatus)),
+	).Methods("GET")
+
+	r.Handle(
+		"/api/admin/auth/unblock",
+		alice.New(rbac.Require(types.RoleAdmin, "admin.auth.unblock")).Then(http.HandlerFunc(e.handleUnblock)),
+	).Methods("POST")
+
+	r.Handle(
+		"/api/admin/settlements",
+		alice.New(rbac.Require(types.RoleOperator, "admin.settlements")).Then(http.HandlerFunc(e.handleGetSettlements)),
+	).Methods("GET")
+
+	r.Handle(
+		"/api/admin/settlements/{hash}/retry",
+		alice.New(rbac.Require(types.RoleOperator, "admin.settlements.retry")).Then(http.HandlerFunc(e.handleRetrySettlement)),
+	).Methods("POST")
+
+	r.Handle(
+		"/api/admin/settlements/{hash}/abandon",
+		alice.New(rbac.Require(types.RoleAdmin, "admin.settlements.abandon")).Then(http.HandlerFunc(e.handleAbandonSettlement)),
+	).Methods("POST")
+
+	r.Handle(
+		"/api/admin/pairs/pause",
+		alice.New(rbac.Require(types.RoleOperator, "admin.pairs.pause")).Then(http.HandlerFunc(e.handlePausePair)),
+	).Methods("POST")
+
+	r.Handle(
+		"/api/admin/pairs/resume",
+		alice.New(rbac.Require(types.RoleOperator, "admin.pairs.resume")).Then(http.HandlerFunc(e.handleResumePair)),
+	).Methods("POST")
+
+	r.Handle(
+		"/api/admin/listings",
+		alice.New(rbac.Require(types.RoleOperator, "admin.listings")).Then(http.HandlerFunc(e.handleGetListings)),
+	).Methods("GET")
+
+	r.Handle(
+		"/api/admin/listings/{id}/approve",
+		alice.New(rbac.Require(types.RoleAdmin, "admin.listings.approve")).Then(http.HandlerFunc(e.handleApproveListing)),
+	).Methods("POST")
+
+	r.Handle(
+		"/api/admin/listings/{id}/reject",
+		alice.New(rbac.Require(types.RoleAdmin, "admin.listings.reject")).Then(http.HandlerFunc(e.handleRejectListing)),
+	).Methods("POST")
+
+	r.Handle(
+		"/api/admin/disputes/{hash}",
+		alice.New(rbac.Require(types.RoleOperator, "admin.disputes")).Then(http.HandlerFunc(e.handleGetDisputeReport)),
+	).Methods("GET")
+
+	r.Handle(
+		"/api/admin/usage",
+		alice.New(rbac.Require(types.RoleOperator, "admin.usage")).Then(http.HandlerFunc(e.handleGetUsage)),
+	).Methods("GET")
+
+	r.Handle(
+		"/api/admin/access",
+		alice.New(rbac.Require(types.RoleOperator, "admin.access")).Then(http.HandlerFunc(e.handleGetAccessStats)),
+	).Methods("GET")
+}
+
+func (e *adminEndpoint) handleWhoAmI(w http.ResponseWriter, r *http.Request) {
+	httputils.WriteJSON(w, http.StatusOK, middlewares.GetIdentity(r))
+}
+
+func (e *adminEndpoint) handleGetAuditLogs(w http.ResponseWriter, r *http.Request) {
+	v := r.URL.Query()
+	offset, _ := strconv.Atoi(v.Get("pageOffset"))
+	limit, _ := strconv.Atoi(v.Get("pageSize"))
+
+	res, err := e.auditService.GetAll(offset*limit, limit)
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, res)
+}
+
+// handleGetAuthGuardStatus returns the locked out IPs and API keys with the authentication failure metrics
+func (e *adminEndpoint) handleGetAuthGuardStatus(w http.ResponseWriter, r *http.Request) {
+	httputils.WriteJSON(w, http.StatusOK, middlewares.GetAuthGuard().GetStatus())
+}
+
+// handleUnblock removes the lockout of an IP ("ip:1.2.3.4") or an API key ("apikey:<sha256 of the key>")
+func (e *adminEndpoint) handleUnblock(w http.ResponseWriter, r *http.Request) {
+	key := r.URL.Query().Get("key")
+	if key == "" {
+		httputils.WriteError(w, http.StatusBadRequest, "key Parameter Missing")
+		return
+	}
+
+	if !middlewares.GetAuthGuard().Unblock(key) {
+		httputils.WriteError(w, http.StatusNotFound, "Key is not blocked")
+		return
+	}
+
+	httputils.WriteMessage(w, http.StatusOK, "OK")
+}
+
+// handleGetUsage returns the total API usage of the current window with the "top" heaviest users (50 by default)
+func (e *adminEndpoint) handleGetUsage(w http.ResponseWriter, r *http.Request) {
+	top, _ := strconv.Atoi(r.URL.Query().Get("top"))
+	if top <= 0 {
+		top = 50
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, usage.GetTracker().GetRollup(top))
+}
+
+// handleGetAccessStats returns the estimated calls, errors, bytes and latencies of each route since
+// the "since" unix timestamp (the last 24 hours by default), from the sampled access logs
+func (e *adminEndpoint) handleGetAccessStats(w http.ResponseWriter, r *http.Request) {
+	if !e.accessLogService.Enabled() {
+		httputils.WriteError(w, http.StatusNotFound, "Access logs are disabled")
+		return
+	}
+
+	since := time.Now().Add(-24 * time.Hour)
+	if v := r.URL.Query().Get("since"); v != "" {
+		ts, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			httputils.WriteError(w, http.StatusBadRequest, "Invalid since")
+			return
+		}
+
+		since = time.Unix(ts, 0)
+	}
+
+	res, err := e.accessLogService.GetStats(since)
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, res)
+}
+
+// handleGetSettlements returns the settlements with the statuses of the "status" param
+// (comma separated), the stuck and retrying ones by default
+func (e *adminEndpoint) handleGetSettlements(w http.ResponseWriter, r *http.Request) {
+	statuses := []string{types.SettlementStatusStuck, types.SettlementStatusRetrying}
+	if v := r.URL.Query().Get("status"); v != "" {
+		statuses = strings.Split(strings.ToUpper(v), ",")
+	}
+
+	res, err := e.settlementService.GetByStatus(statuses...)
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, res)
+}
+
+func (e *adminEndpoint) handleRetrySettlement(w http.ResponseWriter, r *http.Request) {
+	e.handleSettlementAction(w, r, e.settlementService.Retry)
+}
+
+func (e *adminEndpoint) handleAbandonSettlement(w http.ResponseWriter, r *http.Request) {
+	e.handleSettlementAction(w, r, e.settlementService.Abandon)
+}
+
+func (e *adminEndpoint) handleSettlementAction(
+	w http.ResponseWriter,
+	r *http.Request,
+	action func(hash common.Hash, identity string) (*types.Settlement, error),
+) {
+	hash := mux.Vars(r)["hash"]
+	if len(common.FromHex(hash)) != common.HashLength {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid trade hash")
+		return
+	}
+
+	res, err := action(common.HexToHash(hash), middlewares.GetIdentity(r).Name)
+	switch err {
+	case nil:
+		httputils.WriteJSON(w, http.StatusOK, res)
+	case services.ErrSettlementNotFound:
+		httputils.WriteError(w, http.StatusNotFound, err.Error())
+	case services.ErrSettlementResolved:
+		httputils.WriteError(w, http.StatusConflict, err.Error())
+	default:
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+	}
+}
+
+// handlePausePair pauses a pair. With the "mode=queue" param the new orders are queued until it is
+// resumed instead of being rejected, up to the "limit" param or the pause_queue_limit setting
+func (e *adminEndpoint) handlePausePair(w http.ResponseWriter, r *http.Request) {
+	v := r.URL.Query()
+	switch v.Get("mode") {
+	case "":
+		e.handlePairAction(w, r, func(bt, qt common.Address) error {
+			return e.settlementService.PausePair(bt, qt, types.PauseReasonOperator)
+		})
+	case "queue":
+		limit := 0
+		if l := v.Get("limit"); l != "" {
+			n, err := strconv.Atoi(l)
+			if err != nil || n <= 0 {
+				httputils.WriteError(w, http.StatusBadRequest, "Invalid limit")
+				return
+			}
+
+			limit = n
+		}
+
+		e.handlePairAction(w, r, func(bt, qt common.Address) error {
+			return e.settlementService.PausePairQueued(bt, qt, limit)
+		})
+	default:
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid mode")
+	}
+}
+
+// handleResumePair lifts the operator and settlement pauses of a pair, a pair halted by a kill switch or a
+// token migration is left paused with a conflict
+func (e *adminEndpoint) handleResumePair(w http.ResponseWriter, r *http.Request) {
+	e.handlePairAction(w, r, e.settlementService.ResumePair)
+}
+
+func (e *adminEndpoint) handlePairAction(w http.ResponseWriter, r *http.Request, action func(bt, qt common.Address) error) {
+	v := r.URL.Query()
+	bt := v.Get("baseToken")
+	qt := v.Get("quoteToken")
+
+	if !common.IsHexAddress(bt) || !common.IsHexAddress(qt) {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid baseToken or quoteToken")
+		return
+	}
+
+	err := action(common.HexToAddress(bt), common.HexToAddress(qt))
+	switch err {
+	case nil:
+		httputils.WriteMessage(w, http.StatusOK, "OK")
+	case services.ErrPairNotFound:
+		httputils.WriteError(w, http.StatusNotFound, err.Error())
+	case services.ErrPairHalted:
+		httputils.WriteError(w, http.StatusConflict, err.Error())
+	default:
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+	}
+}
+
+// handleGetListings returns the listing reviews with the statuses of the "status" param
+// (comma separated), the pending ones by default
+func (e *adminEndpoint) handleGetListings(w http.ResponseWriter, r *http.Request) {
+	statuses := []string{types.ListingStatusPending}
+	if v := r.URL.Query().Get("status"); v != "" {
+		statuses = strings.Split(strings.ToUpper(v), ",")
+	}
+
+	res, err := e.listingService.GetByStatus(statuses...)
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, res)
+}
+
+// handleApproveListing approves a listing with the holders and the liquidity commitment
+// (in quote token units) attested by the reviewer, the failed checks are returned if a requirement is not met
+func (e *adminEndpoint) handleApproveListing(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if !bson.IsObjectIdHex(id) {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid listing id")
+		return
+	}
+
+	var payload struct {
+		Holders             int    `json:"holders"`
+		LiquidityCommitment string `json:"liquidityCommitment"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+
+	err := decoder.Decode(&payload)
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
+		return
+	}
+
+	commitment, ok := new(big.Int).SetString(payload.LiquidityCommitment, 10)
+	if !ok || commitment.Sign() < 0 {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid liquidityCommitment")
+		return
+	}
+
+	res, err := e.listingService.Approve(bson.ObjectIdHex(id), payload.Holders, commitment, middlewares.GetIdentity(r).Name)
+	switch err {
+	case nil:
+		httputils.WriteJSON(w, http.StatusOK, res)
+	case services.ErrListingRequirementsNotMet:
+		httputils.WriteJSON(w, http.StatusBadRequest, map[string]interface{}{"error": err.Error(), "checks": res.Checks})
+	default:
+		e.writeListingError(w, err)
+	}
+}
+
+// handleRejectListing rejects a listing, the pair stays inactive
+func (e *adminEndpoint) handleRejectListing(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if !bson.IsObjectIdHex(id) {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid listing id")
+		return
+	}
+
+	var payload struct {
+		Reason string `json:"reason"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+
+	err := decoder.Decode(&payload)
+	if err != nil || payload.Reason == "" {
+		httputils.WriteError(w, http.StatusBadRequest, "reason Parameter Missing")
+		return
+	}
+
+	res, err := e.listingService.Reject(bson.ObjectIdHex(id), payload.Reason, middlewares.GetIdentity(r).Name)
+	if err != nil {
+		e.writeListingError(w, err)
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, res)
+}
+
+func (e *adminEndpoint) writeListingError(w http.ResponseWriter, err error) {
+	switch err {
+	case services.ErrListingNotFound:
+		httputils.WriteError(w, http.StatusNotFound, err.Error())
+	case services.ErrListingReviewed:
+		httputils.WriteError(w, http.StatusConflict, err.Error())
+	default:
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+	}
+}
+
+// handleGetDisputeReport returns the evidence report of an order or a trade hash,
+// as JSON or as a PDF document with "format=pdf"
+func (e *adminEndpoint) handleGetDisputeReport(w http.ResponseWriter, r *http.Request) {
+	hash := mux.Vars(r)["hash"]
+	if len(common.FromHex(hash)) != common.HashLength {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid hash")
+		return
+	}
+
+	res, err := e.disputeService.GetReport(common.HexToHash(hash))
+	if err == services.ErrDisputeHashNotFound {
+		httputils.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	if r.URL.Query().Get("format") != "pdf" {
+		httputils.WriteJSON(w, http.StatusOK, res)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", "attachment; filename=\"dispute-"+res.Hash.Hex()+".pdf\"")
+	w.WriteHeader(http.StatusOK)
+	w.Write(pdf.Render("Dispute report", res.Lines()))
+}
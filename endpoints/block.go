@@ -0,0 +1,96 @@
+package endpoints
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/gorilla/mux"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+	"github.com/tomochain/tomox-sdk/ws"
+)
+
+type blockEndpoint struct {
+	blockService    interfaces.BlockService
+	finalityService interfaces.FinalityService
+}
+
+// ServeBlockResource sets up the routing of the health check, the finality status and the blocks channel
+func ServeBlockResource(
+	r *mux.Router,
+	blockService interfaces.BlockService,
+	finalityService interfaces.FinalityService,
+) {
+	e := &blockEndpoint{blockService, finalityService}
+	r.HandleFunc("/healthz", e.handleHealthz).Methods("GET")
+	r.HandleFunc("/api/finality/{hash}", e.handleGetFinality).Methods("GET")
+
+	ws.RegisterChannel(ws.BlockChannel, e.handleBlockWebSocket)
+}
+
+// handleHealthz returns the chain status of the connected node and the health check of the node
+// answering the contract calls. It responds with 503 when the SDK is not ready to serve traffic
+func (e *blockEndpoint) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	health := e.blockService.GetHealth()
+	if !health.Ready {
+		httputils.WriteJSON(w, http.StatusServiceUnavailable, health)
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, health)
+}
+
+// handleGetFinality returns the confirmations of a settled trade or deposit
+func (e *blockEndpoint) handleGetFinality(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	b, err := hexutil.Decode(vars["hash"])
+	if err != nil || len(b) != common.HashLength {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid hash")
+		return
+	}
+
+	res, err := e.finalityService.GetByHash(common.BytesToHash(b))
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	if res == nil {
+		httputils.WriteError(w, http.StatusNotFound, "Record not found")
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, res)
+}
+
+func (e *blockEndpoint) handleBlockWebSocket(input interface{}, c *ws.Client) {
+	b, _ := json.Marshal(input)
+	var ev *types.WebsocketEvent
+
+	socket := ws.GetBlockSocket()
+	err := json.Unmarshal(b, &ev)
+	if err != nil || ev == nil {
+		logger.Error(err)
+		socket.SendErrorMessage(c, map[string]string{"Message": "Invalid payload"})
+		return
+	}
+
+	if ev.Type != types.SUBSCRIBE && ev.Type != types.UNSUBSCRIBE {
+		logger.Info("Event Type", ev.Type)
+		err := map[string]string{"Message": "Invalid payload"}
+		socket.SendErrorMessage(c, err)
+		return
+	}
+
+	if ev.Type == types.SUBSCRIBE {
+		e.blockService.Subscribe(c)
+	}
+
+	if ev.Type == types.UNSUBSCRIBE {
+		e.blockService.Unsubscribe(c)
+	}
+}
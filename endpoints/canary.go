@@ -0,0 +1,47 @@
+package endpoints
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/justinas/alice"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/middlewares"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+)
+
+type canaryEndpoint struct {
+	canaryService interfaces.CanaryService
+}
+
+// ServeCanaryResource sets up the routing of the synthetic order canary metrics,
+// the probes are run by the "canary" job
+func ServeCanaryResource(
+	r *mux.Router,
+	canaryService interfaces.CanaryService,
+	rbac *middlewares.RBAC,
+) {
+	e := &canaryEndpoint{canaryService}
+
+	r.Handle(
+		"/api/admin/canary",
+		alice.New(rbac.Require(types.RoleOperator, "admin.canary")).Then(http.HandlerFunc(e.handleGetStats)),
+	).Methods("GET")
+}
+
+// handleGetStats returns the latency percentiles of the recent probes with the "latest" ones (20 by default)
+func (e *canaryEndpoint) handleGetStats(w http.ResponseWriter, r *http.Request) {
+	if !e.canaryService.Enabled() {
+		httputils.WriteError(w, http.StatusNotFound, "Canary disabled")
+		return
+	}
+
+	latest, _ := strconv.Atoi(r.URL.Query().Get("latest"))
+	if latest <= 0 {
+		latest = 20
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, e.canaryService.GetStats(latest))
+}
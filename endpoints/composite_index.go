@@ -0,0 +1,186 @@
+package endpoints
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/justinas/alice"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/middlewares"
+	"github.com/tomochain/tomox-sdk/services"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+	"github.com/tomochain/tomox-sdk/ws"
+)
+
+type compositeIndexEndpoint struct {
+	indexService interfaces.CompositeIndexService
+}
+
+// ServeCompositeIndexResource sets up the routing of the composite indices, their admin endpoints
+// and the indices channel
+func ServeCompositeIndexResource(
+	r *mux.Router,
+	indexService interfaces.CompositeIndexService,
+	rbac *middlewares.RBAC,
+) {
+	e := &compositeIndexEndpoint{indexService}
+
+	r.HandleFunc("/api/indices", e.handleGetIndices).Methods("GET")
+	r.HandleFunc("/api/indices/{name}", e.handleGetIndexValue).Methods("GET")
+
+	r.Handle(
+		"/api/admin/indices",
+		alice.New(rbac.Require(types.RoleOperator, "admin.indices.create")).Then(http.HandlerFunc(e.handleCreateIndex)),
+	).Methods("POST")
+
+	r.Handle(
+		"/api/admin/indices/{name}",
+		alice.New(rbac.Require(types.RoleOperator, "admin.indices.update")).Then(http.HandlerFunc(e.handleUpdateIndex)),
+	).Methods("PUT")
+
+	r.Handle(
+		"/api/admin/indices/{name}",
+		alice.New(rbac.Require(types.RoleOperator, "admin.indices.delete")).Then(http.HandlerFunc(e.handleDeleteIndex)),
+	).Methods("DELETE")
+
+	ws.RegisterChannel(ws.IndicesChannel, e.handleIndicesWebSocket)
+}
+
+// handleGetIndices returns the definitions of the indices
+func (e *compositeIndexEndpoint) handleGetIndices(w http.ResponseWriter, r *http.Request) {
+	res, err := e.indexService.GetAll()
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, res)
+}
+
+// handleGetIndexValue returns the current value of an index and of its components
+func (e *compositeIndexEndpoint) handleGetIndexValue(w http.ResponseWriter, r *http.Request) {
+	res, err := e.indexService.GetValue(mux.Vars(r)["name"])
+	e.writeResult(w, http.StatusOK, res, err)
+}
+
+// handleCreateIndex defines an index from its name, description, base value and weighted tokens
+func (e *compositeIndexEndpoint) handleCreateIndex(w http.ResponseWriter, r *http.Request) {
+	p := &types.CompositeIndexPayload{}
+
+	decoder := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+
+	err := decoder.Decode(p)
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
+		return
+	}
+
+	err = p.Validate()
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := e.indexService.Create(p, middlewares.GetIdentity(r).Name)
+	e.writeResult(w, http.StatusCreated, res, err)
+}
+
+// handleUpdateIndex changes the description and the components of an index, which is rebased
+func (e *compositeIndexEndpoint) handleUpdateIndex(w http.ResponseWriter, r *http.Request) {
+	p := &types.CompositeIndexPayload{}
+
+	decoder := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+
+	err := decoder.Decode(p)
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
+		return
+	}
+
+	p.Name = mux.Vars(r)["name"]
+	err = p.Validate()
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := e.indexService.Update(p.Name, p)
+	e.writeResult(w, http.StatusOK, res, err)
+}
+
+func (e *compositeIndexEndpoint) handleDeleteIndex(w http.ResponseWriter, r *http.Request) {
+	err := e.indexService.Delete(mux.Vars(r)["name"])
+	e.writeResult(w, http.StatusOK, map[string]string{"status": "deleted"}, err)
+}
+
+func (e *compositeIndexEndpoint) writeResult(w http.ResponseWriter, status int, res interface{}, err error) {
+	switch err {
+	case nil:
+		httputils.WriteJSON(w, status, res)
+	case services.ErrIndexNotFound:
+		httputils.WriteError(w, http.StatusNotFound, err.Error())
+	case services.ErrIndexExists:
+		httputils.WriteError(w, http.StatusConflict, err.Error())
+	case services.ErrTokenNotFound, services.ErrIndexPriceNotFound:
+		httputils.WriteError(w, http.StatusUnprocessableEntity, err.Error())
+	default:
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+	}
+}
+
+func (e *compositeIndexEndpoint) handleIndicesWebSocket(input interface{}, c *ws.Client) {
+	socket := ws.GetCompositeIndexSocket()
+	errInvalidPayload := map[string]string{"Message": "Invalid payload"}
+	if input == nil {
+		socket.SendErrorMessage(c, errInvalidPayload)
+		return
+	}
+
+	b, _ := json.Marshal(input)
+	var ev *types.WebsocketEvent
+
+	err := json.Unmarshal(b, &ev)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	if ev == nil || (ev.Type != types.SUBSCRIBE && ev.Type != types.UNSUBSCRIBE) {
+		socket.SendErrorMessage(c, errInvalidPayload)
+		return
+	}
+
+	b, _ = json.Marshal(ev.Payload)
+	var p *types.SubscriptionPayload
+
+	err = json.Unmarshal(b, &p)
+	if err != nil {
+		logger.Error(err)
+		socket.SendErrorMessage(c, errInvalidPayload)
+		return
+	}
+
+	if ev.Type == types.SUBSCRIBE {
+		if p == nil || p.Index == "" {
+			socket.SendErrorMessage(c, errInvalidPayload)
+			return
+		}
+
+		e.indexService.Subscribe(c, p.Index)
+	}
+
+	if ev.Type == types.UNSUBSCRIBE {
+		if p == nil || p.Index == "" {
+			e.indexService.Unsubscribe(c)
+			return
+		}
+
+		e.indexService.UnsubscribeChannel(c, p.Index)
+	}
+}
@@ -0,0 +1,35 @@
+package endpoints
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/justinas/alice"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/middlewares"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+)
+
+type dependencyEndpoint struct {
+	dependencyService interfaces.DependencyService
+}
+
+// ServeDependencyResource sets up the routing of the metrics of the MongoDB and RabbitMQ clients
+func ServeDependencyResource(
+	r *mux.Router,
+	dependencyService interfaces.DependencyService,
+	rbac *middlewares.RBAC,
+) {
+	e := &dependencyEndpoint{dependencyService}
+	r.Handle(
+		"/api/admin/dependencies",
+		alice.New(rbac.Require(types.RoleOperator, "admin.dependencies")).Then(http.HandlerFunc(e.handleGetMetrics)),
+	).Methods("GET")
+}
+
+// handleGetMetrics returns the connection pool and the slow queries of MongoDB with the publish latencies,
+// the waiting messages and the consumer lag of the RabbitMQ queues
+func (e *dependencyEndpoint) handleGetMetrics(w http.ResponseWriter, r *http.Request) {
+	httputils.WriteJSON(w, http.StatusOK, e.dependencyService.GetMetrics())
+}
@@ -0,0 +1,84 @@
+package endpoints
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/services"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+)
+
+type depthSnapshotEndpoint struct {
+	depthSnapshotService interfaces.DepthSnapshotService
+}
+
+// ServeDepthSnapshotResource sets up the routing of the history of the depth of the order books
+func ServeDepthSnapshotResource(
+	r *mux.Router,
+	depthSnapshotService interfaces.DepthSnapshotService,
+) {
+	e := &depthSnapshotEndpoint{depthSnapshotService}
+	r.HandleFunc("/api/orderbook/depth/history", e.handleGetDepthHistory).Methods("GET")
+}
+
+// handleGetDepthHistory returns the hourly snapshots of the top levels of the order book of the pair of
+// the baseToken and quoteToken params between from and to, unix timestamps in seconds, the last 7 days by
+// default. The snapshots are paged with the pageOffset and pageSize params
+func (e *depthSnapshotEndpoint) handleGetDepthHistory(w http.ResponseWriter, r *http.Request) {
+	v := r.URL.Query()
+	bt := v.Get("baseToken")
+	qt := v.Get("quoteToken")
+
+	if !common.IsHexAddress(bt) {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid Base Token Address")
+		return
+	}
+
+	if !common.IsHexAddress(qt) {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid Quote Token Address")
+		return
+	}
+
+	to := time.Now()
+	from := to.AddDate(0, 0, -7)
+
+	if f := v.Get("from"); f != "" {
+		ts, err := strconv.ParseInt(f, 10, 64)
+		if err != nil {
+			httputils.WriteError(w, http.StatusBadRequest, "Invalid from")
+			return
+		}
+
+		from = time.Unix(ts, 0)
+	}
+
+	if t := v.Get("to"); t != "" {
+		ts, err := strconv.ParseInt(t, 10, 64)
+		if err != nil {
+			httputils.WriteError(w, http.StatusBadRequest, "Invalid to")
+			return
+		}
+
+		to = time.Unix(ts, 0)
+	}
+
+	page, _ := strconv.Atoi(v.Get("pageOffset"))
+	size, _ := strconv.Atoi(v.Get("pageSize"))
+
+	res, err := e.depthSnapshotService.GetSnapshots(common.HexToAddress(bt), common.HexToAddress(qt), from, to, page, size)
+	switch err {
+	case nil:
+		httputils.WriteJSON(w, http.StatusOK, res)
+	case services.ErrInvalidReportPeriod:
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+	case services.ErrPairNotFound:
+		httputils.WriteError(w, http.StatusNotFound, err.Error())
+	default:
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+	}
+}
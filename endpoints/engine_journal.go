@@ -0,0 +1,84 @@
+package endpoints
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+	"github.com/justinas/alice"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/middlewares"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+)
+
+type engineJournalEndpoint struct {
+	engineJournalService interfaces.EngineJournalService
+}
+
+// ServeEngineJournalResource sets up the routing of the engine journal endpoints
+func ServeEngineJournalResource(
+	r *mux.Router,
+	engineJournalService interfaces.EngineJournalService,
+	rbac *middlewares.RBAC,
+) {
+	e := &engineJournalEndpoint{engineJournalService}
+
+	r.Handle(
+		"/api/admin/engine/events",
+		alice.New(rbac.Require(types.RoleOperator, "admin.engine.events")).Then(http.HandlerFunc(e.handleGetEngineEvents)),
+	).Methods("GET")
+}
+
+// handleGetEngineEvents returns the engine events of the orderHash param, or of the pair of the
+// baseToken and quoteToken params, with a sequence number greater than the after param.
+// Clients page through the journal by passing the last sequence number they received as after
+func (e *engineJournalEndpoint) handleGetEngineEvents(w http.ResponseWriter, r *http.Request) {
+	v := r.URL.Query()
+	hash := v.Get("orderHash")
+	bt := v.Get("baseToken")
+	qt := v.Get("quoteToken")
+
+	var after int64
+	if a := v.Get("after"); a != "" {
+		var err error
+		after, err = strconv.ParseInt(a, 10, 64)
+		if err != nil || after < 0 {
+			httputils.WriteError(w, http.StatusBadRequest, "Invalid after")
+			return
+		}
+	}
+
+	limit, _ := strconv.Atoi(v.Get("limit"))
+
+	var res []*types.EngineEvent
+	var err error
+	switch {
+	case hash != "":
+		if len(common.FromHex(hash)) != common.HashLength {
+			httputils.WriteError(w, http.StatusBadRequest, "Invalid orderHash")
+			return
+		}
+
+		res, err = e.engineJournalService.GetByOrderHash(common.HexToHash(hash), after, limit)
+	case bt != "" || qt != "":
+		if !common.IsHexAddress(bt) || !common.IsHexAddress(qt) {
+			httputils.WriteError(w, http.StatusBadRequest, "Invalid baseToken or quoteToken")
+			return
+		}
+
+		res, err = e.engineJournalService.GetByPair(common.HexToAddress(bt), common.HexToAddress(qt), after, limit)
+	default:
+		httputils.WriteError(w, http.StatusBadRequest, "orderHash or baseToken and quoteToken Parameters missing")
+		return
+	}
+
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, res)
+}
@@ -0,0 +1,64 @@
+package endpoints
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/justinas/alice"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/middlewares"
+	"github.com/tomochain/tomox-sdk/services"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+)
+
+type exportEndpoint struct {
+	exportService interfaces.ExportService
+}
+
+// ServeExportResource sets up the routing of the export streams of the trades and the orders
+func ServeExportResource(
+	r *mux.Router,
+	exportService interfaces.ExportService,
+	rbac *middlewares.RBAC,
+) {
+	e := &exportEndpoint{exportService}
+
+	r.Handle(
+		"/api/export/{stream}",
+		alice.New(rbac.Require(types.RoleViewer, "export.stream")).Then(http.HandlerFunc(e.handleGetExport)),
+	).Methods("GET")
+}
+
+// handleGetExport returns the trades or the orders changed after the checkpoint param, from the start
+// of the stream without it, up to the limit param. The request waits for new documents up to the wait
+// param in seconds, 0 by default. The checkpoint of the response is sent with the next request
+func (e *exportEndpoint) handleGetExport(w http.ResponseWriter, r *http.Request) {
+	v := r.URL.Query()
+
+	limit, _ := strconv.Atoi(v.Get("limit"))
+	wait := 0
+	if s := v.Get("wait"); s != "" {
+		var err error
+		wait, err = strconv.Atoi(s)
+		if err != nil || wait < 0 {
+			httputils.WriteError(w, http.StatusBadRequest, "Invalid wait")
+			return
+		}
+	}
+
+	res, err := e.exportService.Pull(r.Context(), mux.Vars(r)["stream"], v.Get("checkpoint"), limit, time.Duration(wait)*time.Second)
+	switch err {
+	case nil:
+		httputils.WriteJSON(w, http.StatusOK, res)
+	case services.ErrInvalidExportStream:
+		httputils.WriteError(w, http.StatusNotFound, err.Error())
+	case services.ErrInvalidCheckpoint:
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+	default:
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+	}
+}
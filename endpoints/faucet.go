@@ -0,0 +1,64 @@
+package endpoints
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+	"github.com/justinas/alice"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/middlewares"
+	"github.com/tomochain/tomox-sdk/services"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+)
+
+type faucetEndpoint struct {
+	faucetService interfaces.FaucetService
+}
+
+// ServeFaucetResource sets up the routing of the faucet of the test networks, the callers are rate limited
+// by their own limiter and each address by the interval of the faucet
+func ServeFaucetResource(
+	r *mux.Router,
+	faucetService interfaces.FaucetService,
+	limiter *middlewares.RateLimiter,
+) {
+	e := &faucetEndpoint{faucetService}
+
+	r.Handle(
+		"/api/faucet/{address}",
+		alice.New(limiter.Limit).Then(http.HandlerFunc(e.handleRequest)),
+	).Methods("POST")
+}
+
+// handleRequest sends the test TOMO and the test tokens of the faucet to the address
+func (e *faucetEndpoint) handleRequest(w http.ResponseWriter, r *http.Request) {
+	if !e.faucetService.Enabled() {
+		httputils.WriteError(w, http.StatusNotFound, "Faucet disabled")
+		return
+	}
+
+	vars := mux.Vars(r)
+	addr := vars["address"]
+	if !common.IsHexAddress(addr) {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid Address")
+		return
+	}
+
+	res, err := e.faucetService.Request(common.HexToAddress(addr))
+	switch err {
+	case nil:
+		httputils.WriteJSON(w, http.StatusOK, res)
+	case services.ErrFaucetTooSoon:
+		retry := time.Until(res.NextRequestAt)
+		w.Header().Set("Retry-After", strconv.Itoa(int(retry.Seconds())+1))
+		httputils.WriteError(w, http.StatusTooManyRequests, err.Error())
+	case services.ErrFaucetFailed:
+		httputils.WriteJSON(w, http.StatusBadGateway, res)
+	default:
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+	}
+}
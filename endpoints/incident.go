@@ -0,0 +1,169 @@
+package endpoints
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/gorilla/mux"
+	"github.com/justinas/alice"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/middlewares"
+	"github.com/tomochain/tomox-sdk/services"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+	"github.com/tomochain/tomox-sdk/ws"
+)
+
+type incidentEndpoint struct {
+	incidentService interfaces.IncidentService
+}
+
+// ServeIncidentResource sets up the routing of the status page, the incidents admin endpoints
+// and the system_status channel
+func ServeIncidentResource(
+	r *mux.Router,
+	incidentService interfaces.IncidentService,
+	rbac *middlewares.RBAC,
+) {
+	e := &incidentEndpoint{incidentService}
+
+	r.HandleFunc("/status", e.handleGetStatus).Methods("GET")
+
+	r.Handle(
+		"/api/admin/incidents",
+		alice.New(rbac.Require(types.RoleViewer, "admin.incidents")).Then(http.HandlerFunc(e.handleGetIncidents)),
+	).Methods("GET")
+
+	r.Handle(
+		"/api/admin/incidents",
+		alice.New(rbac.Require(types.RoleOperator, "admin.incidents.create")).Then(http.HandlerFunc(e.handleCreateIncident)),
+	).Methods("POST")
+
+	r.Handle(
+		"/api/admin/incidents/{id}",
+		alice.New(rbac.Require(types.RoleOperator, "admin.incidents.update")).Then(http.HandlerFunc(e.handleUpdateIncident)),
+	).Methods("PUT")
+
+	ws.RegisterChannel(ws.SystemStatusChannel, e.handleSystemStatusWebSocket)
+}
+
+// handleGetStatus returns the status of the system and its components with the active
+// and the recently resolved incidents
+func (e *incidentEndpoint) handleGetStatus(w http.ResponseWriter, r *http.Request) {
+	res, err := e.incidentService.GetStatus()
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, res)
+}
+
+func (e *incidentEndpoint) handleGetIncidents(w http.ResponseWriter, r *http.Request) {
+	v := r.URL.Query()
+	offset, _ := strconv.Atoi(v.Get("pageOffset"))
+	limit, _ := strconv.Atoi(v.Get("pageSize"))
+
+	res, err := e.incidentService.GetAll(offset*limit, limit)
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, res)
+}
+
+// handleCreateIncident opens an incident with its title, components, impact and first message
+func (e *incidentEndpoint) handleCreateIncident(w http.ResponseWriter, r *http.Request) {
+	p := &types.IncidentPayload{}
+
+	decoder := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+
+	err := decoder.Decode(p)
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
+		return
+	}
+
+	err = p.ValidateNew()
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := e.incidentService.Create(p, middlewares.GetIdentity(r).Name)
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusCreated, res)
+}
+
+// handleUpdateIncident changes the status, the impact or the components of an incident and posts
+// its message, the "RESOLVED" status closes the incident
+func (e *incidentEndpoint) handleUpdateIncident(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if !bson.IsObjectIdHex(id) {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid incident id")
+		return
+	}
+
+	p := &types.IncidentPayload{}
+
+	decoder := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+
+	err := decoder.Decode(p)
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
+		return
+	}
+
+	err = p.Validate()
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := e.incidentService.Update(bson.ObjectIdHex(id), p, middlewares.GetIdentity(r).Name)
+	switch err {
+	case nil:
+		httputils.WriteJSON(w, http.StatusOK, res)
+	case services.ErrIncidentNotFound:
+		httputils.WriteError(w, http.StatusNotFound, err.Error())
+	case services.ErrIncidentResolved:
+		httputils.WriteError(w, http.StatusConflict, err.Error())
+	default:
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+	}
+}
+
+func (e *incidentEndpoint) handleSystemStatusWebSocket(input interface{}, c *ws.Client) {
+	b, _ := json.Marshal(input)
+	var ev *types.WebsocketEvent
+
+	socket := ws.GetSystemStatusSocket()
+	err := json.Unmarshal(b, &ev)
+	if err != nil || ev == nil {
+		logger.Error(err)
+		socket.SendErrorMessage(c, map[string]string{"Message": "Invalid payload"})
+		return
+	}
+
+	switch ev.Type {
+	case types.SUBSCRIBE:
+		e.incidentService.Subscribe(c)
+	case types.UNSUBSCRIBE:
+		e.incidentService.Unsubscribe(c)
+	default:
+		socket.SendErrorMessage(c, map[string]string{"Message": "Invalid payload"})
+	}
+}
@@ -0,0 +1,78 @@
+package endpoints
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/justinas/alice"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/middlewares"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+)
+
+type integrityEndpoint struct {
+	integrityService interfaces.IntegrityService
+}
+
+// ServeIntegrityResource sets up the routing of the integrity reports admin endpoints
+func ServeIntegrityResource(
+	r *mux.Router,
+	integrityService interfaces.IntegrityService,
+	rbac *middlewares.RBAC,
+) {
+	e := &integrityEndpoint{integrityService}
+
+	r.Handle(
+		"/api/admin/integrity",
+		alice.New(rbac.Require(types.RoleOperator, "admin.integrity")).Then(http.HandlerFunc(e.handleGetReports)),
+	).Methods("GET")
+
+	r.Handle(
+		"/api/admin/integrity/check",
+		alice.New(rbac.Require(types.RoleOperator, "admin.integrity.check")).Then(http.HandlerFunc(e.handleCheck)),
+	).Methods("POST")
+}
+
+func (e *integrityEndpoint) handleGetReports(w http.ResponseWriter, r *http.Request) {
+	v := r.URL.Query()
+	offset, _ := strconv.Atoi(v.Get("pageOffset"))
+	limit, _ := strconv.Atoi(v.Get("pageSize"))
+
+	res, err := e.integrityService.GetAll(offset*limit, limit)
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, res)
+}
+
+// handleCheck runs the checks on the records created or updated between the from and to timestamps
+// of the query, in seconds, and returns the report
+func (e *integrityEndpoint) handleCheck(w http.ResponseWriter, r *http.Request) {
+	v := r.URL.Query()
+	from, err := strconv.ParseInt(v.Get("from"), 10, 64)
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid from")
+		return
+	}
+
+	to, err := strconv.ParseInt(v.Get("to"), 10, 64)
+	if err != nil || to <= from {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid to")
+		return
+	}
+
+	res, err := e.integrityService.Check(time.Unix(from, 0), time.Unix(to, 0))
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, res)
+}
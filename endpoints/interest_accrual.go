@@ -0,0 +1,109 @@
+package endpoints
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/services"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+)
+
+type interestAccrualEndpoint struct {
+	interestAccrualService interfaces.InterestAccrualService
+}
+
+// ServeInterestAccrualResource sets up the routing of the interest accrual endpoints
+func ServeInterestAccrualResource(
+	r *mux.Router,
+	interestAccrualService interfaces.InterestAccrualService,
+) {
+	e := &interestAccrualEndpoint{interestAccrualService}
+	r.HandleFunc("/api/lending/interest/{address}", e.handleGetIncome).Methods("GET")
+	r.HandleFunc("/api/lending/trades/{hash}/interest", e.handleGetTradeInterest).Methods("GET")
+}
+
+// handleGetIncome returns the daily interest accruals of the lends of an address between from and to,
+// unix timestamps in seconds, the last 30 days by default
+func (e *interestAccrualEndpoint) handleGetIncome(w http.ResponseWriter, r *http.Request) {
+	addr := mux.Vars(r)["address"]
+	if !common.IsHexAddress(addr) {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid Address")
+		return
+	}
+
+	v := r.URL.Query()
+	to := time.Now()
+	from := to.AddDate(0, 0, -30)
+
+	if f := v.Get("from"); f != "" {
+		ts, err := strconv.ParseInt(f, 10, 64)
+		if err != nil {
+			httputils.WriteError(w, http.StatusBadRequest, "Invalid from")
+			return
+		}
+
+		from = time.Unix(ts, 0)
+	}
+
+	if t := v.Get("to"); t != "" {
+		ts, err := strconv.ParseInt(t, 10, 64)
+		if err != nil {
+			httputils.WriteError(w, http.StatusBadRequest, "Invalid to")
+			return
+		}
+
+		to = time.Unix(ts, 0)
+	}
+
+	res, err := e.interestAccrualService.GetIncome(common.HexToAddress(addr), from, to)
+	if err == services.ErrInvalidIncomePeriod {
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, res)
+}
+
+// handleGetTradeInterest returns the interest accrued by a lending trade to date and its stored daily accruals
+func (e *interestAccrualEndpoint) handleGetTradeInterest(w http.ResponseWriter, r *http.Request) {
+	hash := mux.Vars(r)["hash"]
+	if len(common.FromHex(hash)) != common.HashLength {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid hash")
+		return
+	}
+
+	h := common.HexToHash(hash)
+	accrued, err := e.interestAccrualService.GetAccrued(h)
+	if err == services.ErrLendingTradeNotFound {
+		httputils.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	accruals, err := e.interestAccrualService.GetAccruals(h)
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"accrued":  accrued,
+		"accruals": accruals,
+	})
+}
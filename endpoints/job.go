@@ -0,0 +1,94 @@
+package endpoints
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/justinas/alice"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/middlewares"
+	"github.com/tomochain/tomox-sdk/services"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+)
+
+type jobEndpoint struct {
+	scheduler interfaces.JobScheduler
+}
+
+// ServeJobResource sets up the routing of the scheduled jobs admin endpoints
+func ServeJobResource(
+	r *mux.Router,
+	scheduler interfaces.JobScheduler,
+	rbac *middlewares.RBAC,
+) {
+	e := &jobEndpoint{scheduler}
+
+	r.Handle(
+		"/api/admin/jobs",
+		alice.New(rbac.Require(types.RoleViewer, "admin.jobs")).Then(http.HandlerFunc(e.handleGetJobs)),
+	).Methods("GET")
+
+	r.Handle(
+		"/api/admin/jobs/{name}",
+		alice.New(rbac.Require(types.RoleViewer, "admin.jobs")).Then(http.HandlerFunc(e.handleGetJob)),
+	).Methods("GET")
+
+	r.Handle(
+		"/api/admin/jobs/{name}/trigger",
+		alice.New(rbac.Require(types.RoleOperator, "admin.jobs.trigger")).Then(http.HandlerFunc(e.handleAction(scheduler.Trigger))),
+	).Methods("POST")
+
+	r.Handle(
+		"/api/admin/jobs/{name}/pause",
+		alice.New(rbac.Require(types.RoleOperator, "admin.jobs.pause")).Then(http.HandlerFunc(e.handleAction(scheduler.Pause))),
+	).Methods("POST")
+
+	r.Handle(
+		"/api/admin/jobs/{name}/resume",
+		alice.New(rbac.Require(types.RoleOperator, "admin.jobs.resume")).Then(http.HandlerFunc(e.handleAction(scheduler.Resume))),
+	).Methods("POST")
+}
+
+// handleGetJobs returns the states of the scheduled jobs
+func (e *jobEndpoint) handleGetJobs(w http.ResponseWriter, r *http.Request) {
+	httputils.WriteJSON(w, http.StatusOK, e.scheduler.GetJobs())
+}
+
+// handleGetJob returns the state of a scheduled job
+func (e *jobEndpoint) handleGetJob(w http.ResponseWriter, r *http.Request) {
+	res, err := e.scheduler.GetJob(mux.Vars(r)["name"])
+	if err == services.ErrJobNotFound {
+		httputils.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, res)
+}
+
+// handleAction applies an action to a job and returns its state
+func (e *jobEndpoint) handleAction(action func(name string) error) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := mux.Vars(r)["name"]
+
+		err := action(name)
+		if err == services.ErrJobNotFound {
+			httputils.WriteError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		if err != nil {
+			logger.Error(err)
+			httputils.WriteError(w, http.StatusInternalServerError, "")
+			return
+		}
+
+		e.handleGetJob(w, r)
+	}
+}
@@ -0,0 +1,153 @@
+package endpoints
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/gorilla/mux"
+	"github.com/justinas/alice"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/middlewares"
+	"github.com/tomochain/tomox-sdk/services"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+)
+
+type killSwitchEndpoint struct {
+	killSwitchService interfaces.KillSwitchService
+	rbac              *middlewares.RBAC
+}
+
+// ServeKillSwitchResource sets up the routing of the kill switch admin endpoints
+// and of the signed requests sent by the kill-switch command
+func ServeKillSwitchResource(
+	r *mux.Router,
+	killSwitchService interfaces.KillSwitchService,
+	rbac *middlewares.RBAC,
+) {
+	e := &killSwitchEndpoint{killSwitchService, rbac}
+
+	r.Handle(
+		"/api/admin/kill-switch",
+		alice.New(rbac.Require(types.RoleOperator, "admin.killswitch")).Then(http.HandlerFunc(e.handleGetKillSwitches)),
+	).Methods("GET")
+
+	r.Handle(
+		"/api/admin/kill-switch",
+		alice.New(rbac.Require(types.RoleAdmin, "admin.killswitch.activate")).Then(http.HandlerFunc(e.handleActivate)),
+	).Methods("POST")
+
+	r.Handle(
+		"/api/admin/kill-switch/{id}/release",
+		alice.New(rbac.Require(types.RoleAdmin, "admin.killswitch.release")).Then(http.HandlerFunc(e.handleRelease)),
+	).Methods("POST")
+
+	r.HandleFunc("/api/kill-switch/signed", e.handleSignedRequest).Methods("POST")
+}
+
+func (e *killSwitchEndpoint) handleGetKillSwitches(w http.ResponseWriter, r *http.Request) {
+	v := r.URL.Query()
+	offset, _ := strconv.Atoi(v.Get("pageOffset"))
+	limit, _ := strconv.Atoi(v.Get("pageSize"))
+
+	res, err := e.killSwitchService.GetAll(offset*limit, limit)
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, res)
+}
+
+// handleActivate halts the pair of the baseToken and quoteToken of the payload, or all the pairs without
+// them, and cancels their resting orders if cancelOrders is set
+func (e *killSwitchEndpoint) handleActivate(w http.ResponseWriter, r *http.Request) {
+	p := &types.KillSwitchPayload{}
+
+	decoder := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+
+	err := decoder.Decode(p)
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
+		return
+	}
+
+	err = p.Validate()
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := e.killSwitchService.Activate(p, middlewares.GetIdentity(r).Name)
+	e.writeResult(w, http.StatusCreated, res, err)
+}
+
+// handleRelease resumes the pairs halted by a kill switch
+func (e *killSwitchEndpoint) handleRelease(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if !bson.IsObjectIdHex(id) {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid kill switch id")
+		return
+	}
+
+	res, err := e.killSwitchService.Release(bson.ObjectIdHex(id), middlewares.GetIdentity(r).Name)
+	e.writeResult(w, http.StatusOK, res, err)
+}
+
+// handleSignedRequest activates or releases a kill switch with a request signed by one of the
+// kill_switch_signers, the requests are audited as the admin API ones
+func (e *killSwitchEndpoint) handleSignedRequest(w http.ResponseWriter, r *http.Request) {
+	req := &types.SignedKillSwitchRequest{}
+
+	decoder := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+
+	err := decoder.Decode(req)
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
+		return
+	}
+
+	action := "killswitch." + req.Action
+	identity, err := e.killSwitchService.VerifySigned(req)
+	if err != nil {
+		e.rbac.Audit(r, &types.Identity{Name: "anonymous", Method: types.AuthMethodSignature}, action, http.StatusUnauthorized)
+		httputils.WriteError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var res *types.KillSwitch
+	status := http.StatusOK
+	if req.Action == types.KillSwitchActionActivate {
+		status = http.StatusCreated
+		res, err = e.killSwitchService.Activate(req.Payload, identity)
+	} else {
+		res, err = e.killSwitchService.Release(bson.ObjectIdHex(req.ID), identity)
+	}
+
+	status = e.writeResult(w, status, res, err)
+	e.rbac.Audit(r, &types.Identity{Name: identity, Role: types.RoleAdmin, Method: types.AuthMethodSignature}, action, status)
+}
+
+// writeResult writes the kill switch or the error and returns the status of the response
+func (e *killSwitchEndpoint) writeResult(w http.ResponseWriter, status int, res *types.KillSwitch, err error) int {
+	switch err {
+	case nil:
+		httputils.WriteJSON(w, status, res)
+		return status
+	case services.ErrPairNotFound, services.ErrKillSwitchNotFound:
+		httputils.WriteError(w, http.StatusNotFound, err.Error())
+		return http.StatusNotFound
+	case services.ErrKillSwitchReleased:
+		httputils.WriteError(w, http.StatusConflict, err.Error())
+		return http.StatusConflict
+	default:
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+		return http.StatusInternalServerError
+	}
+}
@@ -9,7 +9,10 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/gorilla/mux"
+	"github.com/tomochain/tomox-sdk/compliance"
 	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/middlewares"
+	"github.com/tomochain/tomox-sdk/services"
 	"github.com/tomochain/tomox-sdk/types"
 	"github.com/tomochain/tomox-sdk/utils/httputils"
 	"github.com/tomochain/tomox-sdk/ws"
@@ -208,18 +211,32 @@ func (e *lendingorderEndpoint) handleLendingByHash(w http.ResponseWriter, r *htt
 }
 func (e *lendingorderEndpoint) handleNewLendingOrder(w http.ResponseWriter, r *http.Request) {
 	var o *types.LendingOrder
-	decoder := json.NewDecoder(r.Body)
-
 	defer r.Body.Close()
 
-	err := decoder.Decode(&o)
+	err := httputils.DecodeJSON(r, &o)
 	if err != nil {
 		logger.Error(err)
 		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
 		return
 	}
 	o.Hash = o.ComputeHash()
+
+	guardKeys := middlewares.ClientKeys(r.RemoteAddr, r.Header.Get("X-Api-Key"))
+	if err := middlewares.WaitAuthGuard(guardKeys...); err != nil {
+		httputils.WriteError(w, http.StatusTooManyRequests, err.Error())
+		return
+	}
+
 	err = e.lendingorderService.NewLendingOrder(o)
+	if err == services.ErrInvalidSignature {
+		middlewares.GetAuthGuard().Fail(guardKeys...)
+	}
+
+	if err == compliance.ErrNotAllowed {
+		httputils.WriteError(w, http.StatusForbidden, err.Error())
+		return
+	}
+
 	if err != nil {
 		logger.Error(err)
 		httputils.WriteError(w, http.StatusBadRequest, err.Error())
@@ -230,9 +247,8 @@ func (e *lendingorderEndpoint) handleNewLendingOrder(w http.ResponseWriter, r *h
 
 func (e *lendingorderEndpoint) handleCancelLendingOrder(w http.ResponseWriter, r *http.Request) {
 	o := &types.LendingOrder{}
-	decoder := json.NewDecoder(r.Body)
 	defer r.Body.Close()
-	err := decoder.Decode(&o)
+	err := httputils.DecodeJSON(r, &o)
 	if err != nil {
 		logger.Error(err)
 		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
@@ -249,11 +265,9 @@ func (e *lendingorderEndpoint) handleCancelLendingOrder(w http.ResponseWriter, r
 
 func (e *lendingorderEndpoint) handleRepayLendingOrder(w http.ResponseWriter, r *http.Request) {
 	o := &types.LendingOrder{}
-	decoder := json.NewDecoder(r.Body)
-
 	defer r.Body.Close()
 
-	err := decoder.Decode(&o)
+	err := httputils.DecodeJSON(r, &o)
 	if err != nil {
 		logger.Error(err)
 		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
@@ -269,11 +283,9 @@ func (e *lendingorderEndpoint) handleRepayLendingOrder(w http.ResponseWriter, r
 }
 func (e *lendingorderEndpoint) handleTopupLendingOrder(w http.ResponseWriter, r *http.Request) {
 	o := &types.LendingOrder{}
-	decoder := json.NewDecoder(r.Body)
-
 	defer r.Body.Close()
 
-	err := decoder.Decode(&o)
+	err := httputils.DecodeJSON(r, &o)
 	if err != nil {
 		logger.Error(err)
 		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
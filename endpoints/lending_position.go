@@ -0,0 +1,42 @@
+package endpoints
+
+import (
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+)
+
+type lendingPositionEndpoint struct {
+	lendingPositionService interfaces.LendingPositionService
+}
+
+// ServeLendingPositionResource sets up the routing of the lending positions endpoint
+func ServeLendingPositionResource(
+	r *mux.Router,
+	lendingPositionService interfaces.LendingPositionService,
+) {
+	e := &lendingPositionEndpoint{lendingPositionService}
+	r.HandleFunc("/api/lending/positions/{address}", e.handleGetPositions).Methods("GET")
+}
+
+// handleGetPositions returns the open lends and borrows of an address with their accrued interest,
+// collateral and health factor
+func (e *lendingPositionEndpoint) handleGetPositions(w http.ResponseWriter, r *http.Request) {
+	addr := mux.Vars(r)["address"]
+	if !common.IsHexAddress(addr) {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid Address")
+		return
+	}
+
+	res, err := e.lendingPositionService.GetPositions(common.HexToAddress(addr))
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, res)
+}
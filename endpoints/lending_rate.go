@@ -0,0 +1,80 @@
+package endpoints
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/services"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+)
+
+type lendingRateEndpoint struct {
+	lendingRateService interfaces.LendingRateService
+}
+
+// ServeLendingRateResource sets up the routing of the lending rate series
+func ServeLendingRateResource(
+	r *mux.Router,
+	lendingRateService interfaces.LendingRateService,
+) {
+	e := &lendingRateEndpoint{lendingRateService}
+	r.HandleFunc("/api/lending/rates/{lendingToken}", e.handleGetRates).Methods("GET")
+}
+
+// handleGetRates returns the utilization and the borrow rate of a lending token between from and to,
+// unix timestamps in seconds, the last 7 days by default. The points are downsampled to the interval
+// param, 1h by default, and paged with the pageOffset and pageSize params
+func (e *lendingRateEndpoint) handleGetRates(w http.ResponseWriter, r *http.Request) {
+	token := mux.Vars(r)["lendingToken"]
+	if !common.IsHexAddress(token) {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid lending token address")
+		return
+	}
+
+	v := r.URL.Query()
+	to := time.Now()
+	from := to.AddDate(0, 0, -7)
+
+	if f := v.Get("from"); f != "" {
+		ts, err := strconv.ParseInt(f, 10, 64)
+		if err != nil {
+			httputils.WriteError(w, http.StatusBadRequest, "Invalid from")
+			return
+		}
+
+		from = time.Unix(ts, 0)
+	}
+
+	if t := v.Get("to"); t != "" {
+		ts, err := strconv.ParseInt(t, 10, 64)
+		if err != nil {
+			httputils.WriteError(w, http.StatusBadRequest, "Invalid to")
+			return
+		}
+
+		to = time.Unix(ts, 0)
+	}
+
+	interval := v.Get("interval")
+	if interval == "" {
+		interval = "1h"
+	}
+
+	page, _ := strconv.Atoi(v.Get("pageOffset"))
+	size, _ := strconv.Atoi(v.Get("pageSize"))
+
+	res, err := e.lendingRateService.GetSeries(common.HexToAddress(token), from, to, interval, page, size)
+	switch err {
+	case nil:
+		httputils.WriteJSON(w, http.StatusOK, res)
+	case services.ErrInvalidLendingRateInterval, services.ErrInvalidReportPeriod:
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+	default:
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+	}
+}
@@ -29,6 +29,31 @@ func ServeLendingTradeResource(
 	r.HandleFunc("/api/lending/trades", e.handleGetLendingTrades).Methods("GET")
 	r.HandleFunc("/api/lending/trades/history", e.handleGetLendingTradesHistory).Methods("GET")
 	ws.RegisterChannel(ws.LendingTradeChannel, e.lendingTradeWebsocket)
+	ws.RegisterChannel(ws.LendingLiquidationChannel, e.lendingLiquidationWebsocket)
+}
+
+// lendingLiquidationWebsocket handles the subscriptions to the public lending liquidations channel
+func (e *lendingTradeEndpoint) lendingLiquidationWebsocket(input interface{}, c *ws.Client) {
+	b, _ := json.Marshal(input)
+	var ev *types.WebsocketEvent
+
+	socket := ws.GetLendingLiquidationSocket()
+	err := json.Unmarshal(b, &ev)
+	if err != nil || ev == nil {
+		logger.Error(err)
+		socket.SendErrorMessage(c, map[string]string{"Message": "Invalid payload"})
+		return
+	}
+
+	switch ev.Type {
+	case types.SUBSCRIBE:
+		e.lendingTradeService.SubscribeLiquidations(c)
+	case types.UNSUBSCRIBE:
+		e.lendingTradeService.UnsubscribeLiquidations(c)
+	default:
+		logger.Info("Event Type", ev.Type)
+		socket.SendErrorMessage(c, map[string]string{"Message": "Invalid payload"})
+	}
 }
 func (e *lendingTradeEndpoint) lendingTradeWebsocket(input interface{}, c *ws.Client) {
 	b, _ := json.Marshal(input)
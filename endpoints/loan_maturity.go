@@ -0,0 +1,59 @@
+package endpoints
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+)
+
+type loanMaturityEndpoint struct {
+	loanMaturityService interfaces.LoanMaturityService
+}
+
+// ServeLoanMaturityResource sets up the routing of the loan maturity calendar
+func ServeLoanMaturityResource(
+	r *mux.Router,
+	loanMaturityService interfaces.LoanMaturityService,
+) {
+	e := &loanMaturityEndpoint{loanMaturityService}
+	r.HandleFunc("/api/lending/maturities", e.handleGetMaturities).Methods("GET")
+}
+
+// handleGetMaturities returns the open loans of an address maturing in the next days (30 by default), grouped by date
+func (e *loanMaturityEndpoint) handleGetMaturities(w http.ResponseWriter, r *http.Request) {
+	v := r.URL.Query()
+	addr := v.Get("address")
+
+	if addr == "" {
+		httputils.WriteError(w, http.StatusBadRequest, "address Parameter missing")
+		return
+	}
+
+	if !common.IsHexAddress(addr) {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid Address")
+		return
+	}
+
+	days := 30
+	if d := v.Get("days"); d != "" {
+		var err error
+		days, err = strconv.Atoi(d)
+		if err != nil || days <= 0 {
+			httputils.WriteError(w, http.StatusBadRequest, "Invalid days")
+			return
+		}
+	}
+
+	res, err := e.loanMaturityService.GetCalendar(common.HexToAddress(addr), days)
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, res)
+}
@@ -3,19 +3,24 @@ package endpoints
 import (
 	"encoding/json"
 	"net/http"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/gorilla/mux"
 	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/services"
 	"github.com/tomochain/tomox-sdk/types"
 	"github.com/tomochain/tomox-sdk/utils/httputils"
 	"github.com/tomochain/tomox-sdk/ws"
 )
 
+const maxTickerPairs = 100
+
 type MarketsEndpoint struct {
 	marketsService interfaces.MarketsService
 	pairService    interfaces.PairService
 	relayerService interfaces.RelayerService
+	gasOracle      interfaces.GasOracleService
 }
 
 // ServeTokenResource sets up the routing of token endpoints and the corresponding handlers.
@@ -24,16 +29,27 @@ func ServeMarketsResource(
 	marketsService interfaces.MarketsService,
 	pairService interfaces.PairService,
 	relayerService interfaces.RelayerService,
+	gasOracle interfaces.GasOracleService,
 ) {
-	e := &MarketsEndpoint{marketsService, pairService, relayerService}
+	e := &MarketsEndpoint{marketsService, pairService, relayerService, gasOracle}
 	r.HandleFunc("/api/market/stats/all", e.HandleGetAllMarketStats).Methods("GET")
 	r.HandleFunc("/api/market/stats", e.HandleGetMarketStats).Methods("GET")
+	r.HandleFunc("/api/market/status", e.HandleGetMarketStatus).Methods("GET")
+	r.HandleFunc("/api/market/snapshot", e.HandleGetMarketSnapshot).Methods("GET")
+	r.HandleFunc("/api/market/tickers", e.HandleGetMarketTickers).Methods("GET")
+	r.HandleFunc("/api/market/relayer", e.HandleGetRelayerDeposit).Methods("GET")
+	r.HandleFunc("/api/market/gas", e.HandleGetGasPrices).Methods("GET")
 
 	ws.RegisterChannel(ws.MarketsChannel, e.handleMarketsWebSocket)
 }
 
-// HandleGetAllMarketStats get all market token data
+// HandleGetAllMarketStats get all market token data, ranked by their volume in USDT with sortBy=volume
 func (e *MarketsEndpoint) HandleGetAllMarketStats(w http.ResponseWriter, r *http.Request) {
+	sortBy := r.URL.Query().Get("sortBy")
+	if sortBy != "" && sortBy != "volume" {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid sortBy")
+		return
+	}
 
 	ex := e.relayerService.GetRelayerAddress(r)
 	res, err := e.pairService.GetAllTokenPairDataByCoinbase(ex)
@@ -48,11 +64,142 @@ func (e *MarketsEndpoint) HandleGetAllMarketStats(w http.ResponseWriter, r *http
 		return
 	}
 
+	if sortBy == "volume" {
+		types.SortPairDataByVolumeUsd(res)
+	}
+
 	httputils.WriteJSON(w, http.StatusOK, res)
 	return
 
 }
 
+// HandleGetMarketStatus returns the order intake status of every pair
+func (e *MarketsEndpoint) HandleGetMarketStatus(w http.ResponseWriter, r *http.Request) {
+	res, err := e.pairService.GetMarketStatus()
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, res)
+}
+
+// HandleGetRelayerDeposit returns the deposit locked on the registration contract by the relayer of the
+// "coinbase" param, the relayer of the request by default, with the minimum deposit and its resign status
+func (e *MarketsEndpoint) HandleGetRelayerDeposit(w http.ResponseWriter, r *http.Request) {
+	coinbase := e.relayerService.GetRelayerAddress(r)
+	if v := r.URL.Query().Get("coinbase"); v != "" {
+		if !common.IsHexAddress(v) {
+			httputils.WriteError(w, http.StatusBadRequest, "Invalid coinbase")
+			return
+		}
+
+		coinbase = common.HexToAddress(v)
+	}
+
+	res, err := e.relayerService.GetDeposit(coinbase)
+	switch err {
+	case nil:
+		httputils.WriteJSON(w, http.StatusOK, res)
+	case services.ErrRelayerNotRegistered:
+		httputils.WriteError(w, http.StatusNotFound, err.Error())
+	default:
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+	}
+}
+
+// HandleGetGasPrices returns the safe, standard and fast gas prices suggested from the recent blocks, with
+// their priority fees above the lowest price included
+func (e *MarketsEndpoint) HandleGetGasPrices(w http.ResponseWriter, r *http.Request) {
+	res, err := e.gasOracle.GetGasPrices()
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, res)
+}
+
+// HandleGetMarketTickers returns the tickers of the comma separated pair names, or of every pair with "all"
+func (e *MarketsEndpoint) HandleGetMarketTickers(w http.ResponseWriter, r *http.Request) {
+	v := r.URL.Query()
+	pairs := v.Get("pairs")
+
+	if pairs == "" {
+		httputils.WriteError(w, http.StatusBadRequest, "pairs Parameter missing")
+		return
+	}
+
+	names := strings.Split(pairs, ",")
+	if len(names) > maxTickerPairs {
+		httputils.WriteError(w, http.StatusBadRequest, "Too many pairs")
+		return
+	}
+
+	ex := e.relayerService.GetRelayerAddress(r)
+	data, err := e.pairService.GetAllTokenPairDataByCoinbase(ex)
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if pairs == "all" {
+		if data == nil {
+			data = []*types.PairData{}
+		}
+
+		httputils.WriteJSON(w, http.StatusOK, data)
+		return
+	}
+
+	res := []*types.PairData{}
+	for _, d := range data {
+		for _, name := range names {
+			if strings.EqualFold(d.Pair.PairName, strings.TrimSpace(name)) {
+				res = append(res, d)
+				break
+			}
+		}
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, res)
+}
+
+// HandleGetMarketSnapshot returns the ticker, depth, last trades and candles of a pair in one response
+func (e *MarketsEndpoint) HandleGetMarketSnapshot(w http.ResponseWriter, r *http.Request) {
+	v := r.URL.Query()
+	pair := v.Get("pair")
+	timeInterval := v.Get("timeInterval")
+
+	if pair == "" {
+		httputils.WriteError(w, http.StatusBadRequest, "pair Parameter missing")
+		return
+	}
+
+	if timeInterval == "" {
+		timeInterval = "1h"
+	}
+
+	unit, duration := processTimeInterval(timeInterval)
+	res, err := e.marketsService.GetSnapshot(pair, int64(duration), unit)
+	if err == services.ErrPairNotFound {
+		httputils.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, res)
+}
+
 // HandleGetMarketStats get market specific token data
 func (e *MarketsEndpoint) HandleGetMarketStats(w http.ResponseWriter, r *http.Request) {
 	v := r.URL.Query()
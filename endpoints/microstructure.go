@@ -0,0 +1,109 @@
+package endpoints
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/services"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+	"github.com/tomochain/tomox-sdk/ws"
+)
+
+type microstructureEndpoint struct {
+	microstructureService interfaces.MicrostructureService
+}
+
+// ServeMicrostructureResource sets up the routing of the microstructure metrics of the pairs
+func ServeMicrostructureResource(
+	r *mux.Router,
+	microstructureService interfaces.MicrostructureService,
+) {
+	e := &microstructureEndpoint{microstructureService}
+	r.HandleFunc("/api/market/microstructure", e.handleGetMicrostructure).Methods("GET")
+	ws.RegisterChannel(ws.MicrostructureChannel, e.handleMicrostructureWebSocket)
+}
+
+func (e *microstructureEndpoint) handleGetMicrostructure(w http.ResponseWriter, r *http.Request) {
+	v := r.URL.Query()
+	bt := v.Get("baseToken")
+	qt := v.Get("quoteToken")
+
+	if !common.IsHexAddress(bt) {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid Base Token Address")
+		return
+	}
+
+	if !common.IsHexAddress(qt) {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid Quote Token Address")
+		return
+	}
+
+	m, err := e.microstructureService.Get(common.HexToAddress(bt), common.HexToAddress(qt))
+	if err == services.ErrPairNotFound {
+		httputils.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, m)
+}
+
+func (e *microstructureEndpoint) handleMicrostructureWebSocket(input interface{}, c *ws.Client) {
+	socket := ws.GetMicrostructureSocket()
+	errInvalidPayload := map[string]string{"Message": "Invalid payload"}
+	if input == nil {
+		socket.SendErrorMessage(c, errInvalidPayload)
+		return
+	}
+
+	b, _ := json.Marshal(input)
+	var ev *types.WebsocketEvent
+
+	err := json.Unmarshal(b, &ev)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	if ev == nil || (ev.Type != types.SUBSCRIBE && ev.Type != types.UNSUBSCRIBE) {
+		socket.SendErrorMessage(c, errInvalidPayload)
+		return
+	}
+
+	b, _ = json.Marshal(ev.Payload)
+	var p *types.SubscriptionPayload
+
+	err = json.Unmarshal(b, &p)
+	if err != nil {
+		logger.Error(err)
+		socket.SendErrorMessage(c, errInvalidPayload)
+		return
+	}
+
+	if ev.Type == types.SUBSCRIBE {
+		if p == nil || (p.BaseToken == common.Address{}) || (p.QuoteToken == common.Address{}) {
+			socket.SendErrorMessage(c, errInvalidPayload)
+			return
+		}
+
+		e.microstructureService.Subscribe(c, p.BaseToken, p.QuoteToken)
+	}
+
+	if ev.Type == types.UNSUBSCRIBE {
+		if p == nil {
+			e.microstructureService.Unsubscribe(c)
+			return
+		}
+
+		e.microstructureService.UnsubscribeChannel(c, p.BaseToken, p.QuoteToken)
+	}
+}
@@ -32,14 +32,16 @@ func ServeNotificationResource(
 	r.HandleFunc("/api/notification/mark/unread", e.HandleMarkUnReadNotification).Methods("PUT")
 	r.HandleFunc("/api/notification/mark/readall", e.HandleMarkReadAllNotification).Methods("PUT")
 
+	r.HandleFunc("/api/notification/preferences", e.HandleGetPreference).Methods("GET")
+	r.HandleFunc("/api/notification/preferences", e.HandleSetPreference).Methods("PUT")
+
 	ws.RegisterChannel(ws.NotificationChannel, e.handleNotificationWebSocket)
 }
 
 // HandleMarkReadAllNotification mark all read status
 func (e *NotificationEndpoint) HandleMarkReadAllNotification(w http.ResponseWriter, r *http.Request) {
 	var n types.Notification
-	decoder := json.NewDecoder(r.Body)
-	err := decoder.Decode(&n)
+	err := httputils.DecodeJSON(r, &n)
 	if err != nil {
 		logger.Error(err)
 		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
@@ -59,8 +61,7 @@ func (e *NotificationEndpoint) HandleMarkReadAllNotification(w http.ResponseWrit
 // HandleMarkReadNotification mark read status by id
 func (e *NotificationEndpoint) HandleMarkReadNotification(w http.ResponseWriter, r *http.Request) {
 	var n types.Notification
-	decoder := json.NewDecoder(r.Body)
-	err := decoder.Decode(&n)
+	err := httputils.DecodeJSON(r, &n)
 	if err != nil {
 		logger.Error(err)
 		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
@@ -80,8 +81,7 @@ func (e *NotificationEndpoint) HandleMarkReadNotification(w http.ResponseWriter,
 // HandleMarkUnReadNotification mark unread status by id
 func (e *NotificationEndpoint) HandleMarkUnReadNotification(w http.ResponseWriter, r *http.Request) {
 	var n types.Notification
-	decoder := json.NewDecoder(r.Body)
-	err := decoder.Decode(&n)
+	err := httputils.DecodeJSON(r, &n)
 	if err != nil {
 		logger.Error(err)
 		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
@@ -151,12 +151,59 @@ func (e *NotificationEndpoint) HandleGetNotifications(w http.ResponseWriter, r *
 	httputils.WriteJSON(w, http.StatusOK, notifications)
 }
 
+// HandleGetPreference returns the channels the notifications of a user address are delivered over
+func (e *NotificationEndpoint) HandleGetPreference(w http.ResponseWriter, r *http.Request) {
+	userAddress := r.URL.Query().Get("userAddress")
+	if !common.IsHexAddress(userAddress) {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid user address")
+		return
+	}
+
+	p, err := e.NotificationService.GetPreference(common.HexToAddress(userAddress))
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, p)
+}
+
+// HandleSetPreference saves the channels the notifications of a user address are delivered over
+func (e *NotificationEndpoint) HandleSetPreference(w http.ResponseWriter, r *http.Request) {
+	var p types.NotificationPreference
+	err := httputils.DecodeJSON(r, &p)
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
+		return
+	}
+	defer r.Body.Close()
+
+	if p.Address == (common.Address{}) {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid user address")
+		return
+	}
+
+	if err := p.Validate(); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = e.NotificationService.SetPreference(&p)
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, p)
+}
+
 // HandleUpdateNotification handle notification update
 func (e *NotificationEndpoint) HandleUpdateNotification(w http.ResponseWriter, r *http.Request) {
 	var n types.Notification
-	decoder := json.NewDecoder(r.Body)
-
-	err := decoder.Decode(&n)
+	err := httputils.DecodeJSON(r, &n)
 
 	if err != nil {
 		logger.Error(err)
@@ -9,6 +9,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/gorilla/mux"
 	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/services"
 	"github.com/tomochain/tomox-sdk/types"
 	"github.com/tomochain/tomox-sdk/utils/httputils"
 	"github.com/tomochain/tomox-sdk/ws"
@@ -24,6 +25,7 @@ func ServeOHLCVResource(
 ) {
 	e := &OHLCVEndpoint{ohlcvService}
 	r.HandleFunc("/api/ohlcv", e.handleGetOHLCV).Methods("GET")
+	r.HandleFunc("/api/tokens/{address}/price-history", e.handleGetTokenPriceHistory).Methods("GET")
 	ws.RegisterChannel(ws.OHLCVChannel, e.ohlcvWebSocket)
 }
 
@@ -106,12 +108,19 @@ func (e *OHLCVEndpoint) handleGetOHLCV(w http.ResponseWriter, r *http.Request) {
 	from := v.Get("from")
 	to := v.Get("to")
 	timeInterval := v.Get("timeInterval")
+	fillGaps := v.Get("fillGaps") == "true"
 
 	if timeInterval == "" {
 		httputils.WriteError(w, http.StatusBadRequest, "timeInterval Parameter is missing")
 		return
 	}
 
+	prefs, err := preferencesFromRequest(r)
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	unit, duration := processTimeInterval(timeInterval)
 
 	p.Units = unit
@@ -158,7 +167,7 @@ func (e *OHLCVEndpoint) handleGetOHLCV(w http.ResponseWriter, r *http.Request) {
 		QuoteToken: common.HexToAddress(qt),
 	}}
 
-	res, err := e.ohlcvService.GetOHLCV(p.Pair, p.Duration, p.Units, p.From, p.To)
+	res, err := e.ohlcvService.GetOHLCVIn(p.Pair, p.Duration, p.Units, prefs.Location(), p.From, p.To)
 	if err != nil {
 		logger.Error(err)
 		httputils.WriteError(w, http.StatusInternalServerError, err.Error())
@@ -170,6 +179,81 @@ func (e *OHLCVEndpoint) handleGetOHLCV(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if fillGaps {
+		res = types.FillTickGaps(res, p.Duration, p.Units, p.To*1000, time.Millisecond)
+	}
+
+	if prefs.TokenUnits() {
+		ticks, err := e.ohlcvService.TokenTicks(res)
+		if err != nil {
+			logger.Error(err)
+			httputils.WriteError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		httputils.WriteJSON(w, http.StatusOK, ticks)
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, res)
+}
+
+func (e *OHLCVEndpoint) handleGetTokenPriceHistory(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	a := vars["address"]
+	v := r.URL.Query()
+	interval := v.Get("interval")
+	from := v.Get("from")
+	to := v.Get("to")
+
+	if !common.IsHexAddress(a) {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid Address")
+		return
+	}
+
+	if interval == "" {
+		interval = "1h"
+	}
+
+	unit, duration := processTimeInterval(interval)
+
+	end := time.Now().Unix()
+	if to != "" {
+		t, err := strconv.ParseInt(to, 10, 64)
+		if err != nil {
+			httputils.WriteError(w, http.StatusBadRequest, "Invalid to")
+			return
+		}
+		end = t
+	}
+
+	start := end - 24*60*60
+	if from != "" {
+		f, err := strconv.ParseInt(from, 10, 64)
+		if err != nil || f > end {
+			httputils.WriteError(w, http.StatusBadRequest, "Invalid from")
+			return
+		}
+		start = f
+	}
+
+	res, err := e.ohlcvService.GetTokenPriceHistory(common.HexToAddress(a), int64(duration), unit, start, end)
+	if err == services.ErrTokenNotFound {
+		httputils.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if err == services.ErrTooManyPricePoints {
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
 	httputils.WriteJSON(w, http.StatusOK, res)
 }
 
@@ -9,8 +9,11 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/gorilla/mux"
+	"github.com/tomochain/tomox-sdk/compliance"
 	"github.com/tomochain/tomox-sdk/errors"
 	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/middlewares"
+	"github.com/tomochain/tomox-sdk/services"
 	"github.com/tomochain/tomox-sdk/types"
 	"github.com/tomochain/tomox-sdk/utils/httputils"
 	"github.com/tomochain/tomox-sdk/ws"
@@ -20,6 +23,7 @@ type orderEndpoint struct {
 	orderService   interfaces.OrderService
 	accountService interfaces.AccountService
 	relayerService interfaces.RelayerService
+	attestation    interfaces.AttestationService
 }
 
 // ServeOrderResource sets up the routing of order endpoints and the corresponding handlers.
@@ -28,8 +32,9 @@ func ServeOrderResource(
 	orderService interfaces.OrderService,
 	accountService interfaces.AccountService,
 	relayerService interfaces.RelayerService,
+	attestation interfaces.AttestationService,
 ) {
-	e := &orderEndpoint{orderService, accountService, relayerService}
+	e := &orderEndpoint{orderService, accountService, relayerService, attestation}
 
 	r.HandleFunc("/api/orders/count", e.handleGetCountOrder).Methods("GET")
 	r.HandleFunc("/api/orders/nonce", e.handleGetOrderNonce).Methods("GET")
@@ -37,6 +42,7 @@ func ServeOrderResource(
 	r.HandleFunc("/api/orders/positions", e.handleGetPositions).Methods("GET")
 	r.HandleFunc("/api/orders", e.handleGetOrders).Methods("GET")
 	r.HandleFunc("/api/orders", e.handleNewOrder).Methods("POST")
+	r.HandleFunc("/api/orders/validate", e.handleValidateOrder).Methods("POST")
 	r.HandleFunc("/api/orders/cancel", e.handleCancelOrder).Methods("POST")
 	r.HandleFunc("/api/orders/cancelAll", e.handleCancelAllOrders).Methods("POST")
 	r.HandleFunc("/api/orders/balance/lock", e.handleGetLockedBalanceInOrder).Methods("GET")
@@ -227,7 +233,13 @@ func (e *orderEndpoint) handleGetOrders(w http.ResponseWriter, r *http.Request)
 	if orderType != "" {
 		orderSpec.OrderType = orderType
 	}
-	var err error
+	fields, err := types.ParseFields(v.Get("fields"), types.OrderFields)
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	orderSpec.Fields = fields
+
 	var orders *types.OrderRes
 
 	orders, err = e.orderService.GetOrders(orderSpec, sortDB, offset*size, size)
@@ -247,6 +259,18 @@ func (e *orderEndpoint) handleGetOrders(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if len(orderSpec.Fields) > 0 {
+		selected, err := types.SelectFields(orders.Orders, orderSpec.Fields)
+		if err != nil {
+			logger.Error(err)
+			httputils.WriteError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		httputils.WriteJSON(w, http.StatusOK, map[string]interface{}{"total": orders.Total, "orders": selected})
+		return
+	}
+
 	httputils.WriteJSON(w, http.StatusOK, orders)
 }
 
@@ -400,7 +424,13 @@ func (e *orderEndpoint) handleGetOrderHistory(w http.ResponseWriter, r *http.Req
 	if orderType != "" {
 		orderSpec.OrderType = orderType
 	}
-	var err error
+	fields, err := types.ParseFields(v.Get("fields"), types.OrderFields)
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	orderSpec.Fields = fields
+
 	var orders *types.OrderRes
 
 	orders, err = e.orderService.GetOrders(orderSpec, sortDB, offset*size, size)
@@ -420,16 +450,26 @@ func (e *orderEndpoint) handleGetOrderHistory(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	if len(orderSpec.Fields) > 0 {
+		selected, err := types.SelectFields(orders.Orders, orderSpec.Fields)
+		if err != nil {
+			logger.Error(err)
+			httputils.WriteError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		httputils.WriteJSON(w, http.StatusOK, map[string]interface{}{"total": orders.Total, "orders": selected})
+		return
+	}
+
 	httputils.WriteJSON(w, http.StatusOK, orders)
 }
 
 func (e *orderEndpoint) handleNewOrder(w http.ResponseWriter, r *http.Request) {
 	var o *types.Order
-	decoder := json.NewDecoder(r.Body)
-
 	defer r.Body.Close()
 
-	err := decoder.Decode(&o)
+	err := httputils.DecodeJSON(r, &o)
 	if err != nil {
 		logger.Error(err)
 		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
@@ -454,24 +494,80 @@ func (e *orderEndpoint) handleNewOrder(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	guardKeys := middlewares.ClientKeys(r.RemoteAddr, r.Header.Get("X-Api-Key"))
+	if err := middlewares.WaitAuthGuard(guardKeys...); err != nil {
+		httputils.WriteError(w, http.StatusTooManyRequests, err.Error())
+		return
+	}
+
 	err = e.orderService.NewOrder(o)
+	if err == services.ErrInvalidSignature {
+		middlewares.GetAuthGuard().Fail(guardKeys...)
+	}
+
+	if err == services.ErrPairOverloaded {
+		httputils.WriteError(w, http.StatusTooManyRequests, err.Error())
+		return
+	}
+
+	if err == services.ErrPairPaused || err == services.ErrPairQueueFull {
+		httputils.WriteError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+
+	if err == compliance.ErrNotAllowed {
+		httputils.WriteError(w, http.StatusForbidden, err.Error())
+		return
+	}
+
 	if err != nil {
 		logger.Error(err)
 		httputils.WriteError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	httputils.WriteJSON(w, http.StatusCreated, o)
+	httputils.WriteJSON(w, http.StatusCreated, e.attestation.Attest(types.AttestationOrderAck, o))
+}
+
+// handleValidateOrder runs the validation of a new order without sending it to the engine
+// and returns all the violations, used by the integrators to debug the rejected orders
+func (e *orderEndpoint) handleValidateOrder(w http.ResponseWriter, r *http.Request) {
+	var o *types.Order
+	defer r.Body.Close()
+
+	err := httputils.DecodeJSON(r, &o)
+	if err != nil || o == nil {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
+		return
+	}
+
+	res, err := e.orderService.ValidateOrder(o)
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	acc, err := e.accountService.GetByAddress(o.UserAddress)
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	if acc != nil && acc.IsBlocked {
+		res.Add(types.OrderRuleAccount, "Account is blocked")
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, res)
 }
 
 func (e *orderEndpoint) handleCancelOrder(w http.ResponseWriter, r *http.Request) {
 	oc := &types.OrderCancel{}
 
-	decoder := json.NewDecoder(r.Body)
-
 	defer r.Body.Close()
 
-	err := decoder.Decode(&oc)
+	err := httputils.DecodeJSON(r, &oc)
 	if err != nil {
 		logger.Error(err)
 		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
@@ -479,6 +575,11 @@ func (e *orderEndpoint) handleCancelOrder(w http.ResponseWriter, r *http.Request
 	}
 	logger.Info("handle cancel order nonce", oc.Nonce)
 	err = e.orderService.CancelOrder(oc)
+	if err == services.ErrPairQueueFull {
+		httputils.WriteError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+
 	if err != nil {
 		logger.Error(err)
 		httputils.WriteError(w, http.StatusInternalServerError, err.Error())
@@ -607,7 +708,17 @@ func (e *orderEndpoint) handleWSNewOrder(ev *types.WebsocketEvent, c *ws.Client)
 		c.SendMessage(ws.OrderChannel, types.ERROR, errors.New("Account is blocked"))
 	}
 
+	guardKeys := middlewares.ClientKeys(c.RemoteAddr().String(), c.APIKey())
+	if err := middlewares.WaitAuthGuard(guardKeys...); err != nil {
+		c.SendOrderErrorMessage(err, o.Hash)
+		return
+	}
+
 	err = e.orderService.NewOrder(o)
+	if err == services.ErrInvalidSignature {
+		middlewares.GetAuthGuard().Fail(guardKeys...)
+	}
+
 	if err != nil {
 		logger.Error(err)
 		c.SendOrderErrorMessage(err, o.Hash)
@@ -1,13 +1,12 @@
 package endpoints
 
 import (
-	"encoding/json"
 	"net/http"
 
-	"github.com/ethereum/go-ethereum/common"
-
 	"github.com/gorilla/mux"
+	"github.com/justinas/alice"
 	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/middlewares"
 	"github.com/tomochain/tomox-sdk/services"
 	"github.com/tomochain/tomox-sdk/types"
 	"github.com/tomochain/tomox-sdk/utils/httputils"
@@ -15,6 +14,7 @@ import (
 
 type pairEndpoint struct {
 	pairService    interfaces.PairService
+	tokenService   interfaces.TokenService
 	relayerService interfaces.RelayerService
 }
 
@@ -22,21 +22,27 @@ type pairEndpoint struct {
 func ServePairResource(
 	r *mux.Router,
 	p interfaces.PairService,
+	t interfaces.TokenService,
 	rl interfaces.RelayerService,
+	rbac *middlewares.RBAC,
 ) {
-	e := &pairEndpoint{p, rl}
+	e := &pairEndpoint{p, t, rl}
 	r.HandleFunc("/api/pairs", e.HandleGetPairs).Methods("GET")
 	r.HandleFunc("/api/pair", e.HandleGetPair).Methods("GET")
 	// r.HandleFunc("/api/pair", e.HandleCreatePair).Methods("POST")
 	r.HandleFunc("/api/pairs/data", e.HandleGetPairsData).Methods("GET")
 	r.HandleFunc("/api/pair/data", e.HandleGetPairData).Methods("GET")
+
+	r.Handle(
+		"/api/admin/pairs",
+		alice.New(rbac.Require(types.RoleAdmin, "admin.pairs.create")).Then(http.HandlerFunc(e.HandleCreateRelayerPair)),
+	).Methods("POST")
 }
 
 func (e *pairEndpoint) HandleCreatePair(w http.ResponseWriter, r *http.Request) {
 	p := &types.Pair{}
 
-	decoder := json.NewDecoder(r.Body)
-	err := decoder.Decode(p)
+	err := httputils.DecodeJSON(r, p)
 	if err != nil {
 		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
 		return
@@ -76,11 +82,49 @@ func (e *pairEndpoint) HandleCreatePair(w http.ResponseWriter, r *http.Request)
 	httputils.WriteJSON(w, http.StatusCreated, p)
 }
 
+// HandleCreateRelayerPair creates a pair registered on the relayer contract with the parameters of the operator
+func (e *pairEndpoint) HandleCreateRelayerPair(w http.ResponseWriter, r *http.Request) {
+	payload := &types.CreatePairPayload{}
+
+	err := httputils.DecodeJSON(r, payload)
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
+		return
+	}
+
+	defer r.Body.Close()
+
+	err = payload.Validate()
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	p := payload.ToPair()
+	err = e.relayerService.CreatePair(p)
+	switch err {
+	case nil:
+		httputils.WriteJSON(w, http.StatusCreated, p)
+	case services.ErrPairExists:
+		httputils.WriteError(w, http.StatusConflict, "Pair exists")
+	case services.ErrBaseTokenNotFound, services.ErrQuoteTokenNotFound, services.ErrPairNotRegistered:
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+	default:
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+	}
+}
+
 func (e *pairEndpoint) HandleGetPairs(w http.ResponseWriter, r *http.Request) {
+	fields, err := types.ParseFields(r.URL.Query().Get("fields"), types.PairFields)
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	ex := e.relayerService.GetRelayerAddress(r)
 
-	res, err := e.pairService.GetAllByCoinbase(ex)
+	res, err := e.pairService.GetAllByCoinbase(ex, fields...)
 	if err != nil {
 		logger.Error(err)
 		httputils.WriteError(w, http.StatusInternalServerError, err.Error())
@@ -92,36 +136,38 @@ func (e *pairEndpoint) HandleGetPairs(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if len(fields) > 0 {
+		selected, err := types.SelectFields(res, fields)
+		if err != nil {
+			logger.Error(err)
+			httputils.WriteError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		httputils.WriteJSON(w, http.StatusOK, selected)
+		return
+	}
+
 	httputils.WriteJSON(w, http.StatusOK, res)
 }
 
+// HandleGetPair returns the pair of the "baseToken" and "quoteToken" params, given as
+// contract addresses or as symbols
 func (e *pairEndpoint) HandleGetPair(w http.ResponseWriter, r *http.Request) {
 	v := r.URL.Query()
 	baseToken := v.Get("baseToken")
 	quoteToken := v.Get("quoteToken")
 
-	if baseToken == "" {
-		httputils.WriteError(w, http.StatusBadRequest, "baseToken Parameter missing")
-		return
-	}
-
-	if quoteToken == "" {
-		httputils.WriteError(w, http.StatusBadRequest, "quoteToken Parameter missing")
-		return
-	}
-
-	if !common.IsHexAddress(baseToken) {
-		httputils.WriteError(w, http.StatusBadRequest, "Invalid Base Token Address")
+	baseTokenAddress, ok := tokenParam(w, e.tokenService, "baseToken", baseToken)
+	if !ok {
 		return
 	}
 
-	if !common.IsHexAddress(quoteToken) {
-		httputils.WriteError(w, http.StatusBadRequest, "Invalid Quote Token Address")
+	quoteTokenAddress, ok := tokenParam(w, e.tokenService, "quoteToken", quoteToken)
+	if !ok {
 		return
 	}
 
-	baseTokenAddress := common.HexToAddress(baseToken)
-	quoteTokenAddress := common.HexToAddress(quoteToken)
 	res, err := e.pairService.GetByTokenAddress(baseTokenAddress, quoteTokenAddress)
 	if err != nil {
 		logger.Error(err)
@@ -157,34 +203,23 @@ func (e *pairEndpoint) HandleGetPairsData(w http.ResponseWriter, r *http.Request
 
 }
 
+// HandleGetPairData returns the data of the pair of the "baseToken" and "quoteToken" params,
+// given as contract addresses or as symbols
 func (e *pairEndpoint) HandleGetPairData(w http.ResponseWriter, r *http.Request) {
 	v := r.URL.Query()
 	baseToken := v.Get("baseToken")
 	quoteToken := v.Get("quoteToken")
 
-	if quoteToken == "" {
-		httputils.WriteError(w, http.StatusBadRequest, "quoteToken Parameter missing")
+	baseTokenAddress, ok := tokenParam(w, e.tokenService, "baseToken", baseToken)
+	if !ok {
 		return
 	}
 
-	if baseToken == "" {
-		httputils.WriteError(w, http.StatusBadRequest, "baseToken Parameter missing")
+	quoteTokenAddress, ok := tokenParam(w, e.tokenService, "quoteToken", quoteToken)
+	if !ok {
 		return
 	}
 
-	if !common.IsHexAddress(baseToken) {
-		httputils.WriteError(w, http.StatusBadRequest, "Invalid Base Token Address")
-		return
-	}
-
-	if !common.IsHexAddress(quoteToken) {
-		httputils.WriteError(w, http.StatusBadRequest, "Invalid Quote Token Address")
-		return
-	}
-
-	baseTokenAddress := common.HexToAddress(baseToken)
-	quoteTokenAddress := common.HexToAddress(quoteToken)
-
 	res, err := e.pairService.GetTokenPairData(baseTokenAddress, quoteTokenAddress)
 	if err != nil {
 		logger.Error(err)
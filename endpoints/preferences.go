@@ -0,0 +1,93 @@
+package endpoints
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+	"github.com/tomochain/tomox-sdk/ws"
+)
+
+// ServePreferencesResource sets up the routing of the display preferences.
+// The websocket connections set their preferences on the preferences channel (or with the
+// "timezone" and "units" params of the connection), the REST calls send them with each request
+func ServePreferencesResource(r *mux.Router) {
+	r.HandleFunc("/api/preferences", handleGetPreferences).Methods("GET")
+	ws.RegisterChannel(ws.PreferencesChannel, preferencesWebsocket)
+}
+
+// preferencesFromRequest returns the display preferences of a REST call, set by the "timezone"
+// (or "offset") and "units" query params or the "X-Timezone" and "X-Units" headers
+func preferencesFromRequest(r *http.Request) (*types.Preferences, error) {
+	v := r.URL.Query()
+	timezone := v.Get("timezone")
+	if timezone == "" {
+		timezone = v.Get("offset")
+	}
+
+	if timezone == "" {
+		timezone = r.Header.Get("X-Timezone")
+	}
+
+	// the + of an unescaped offset is decoded as a space
+	if strings.HasPrefix(timezone, " ") {
+		timezone = "+" + strings.TrimSpace(timezone)
+	}
+
+	units := v.Get("units")
+	if units == "" {
+		units = r.Header.Get("X-Units")
+	}
+
+	return types.NewPreferences(timezone, units)
+}
+
+// handleGetPreferences returns the display preferences resolved from the request
+func handleGetPreferences(w http.ResponseWriter, r *http.Request) {
+	prefs, err := preferencesFromRequest(r)
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, prefs)
+}
+
+// preferencesWebsocket gets and sets the display preferences of the connection,
+// the new preferences apply to the subscriptions made afterwards
+func preferencesWebsocket(input interface{}, c *ws.Client) {
+	b, _ := json.Marshal(input)
+	var ev *types.WebsocketEvent
+	if err := json.Unmarshal(b, &ev); err != nil || ev == nil {
+		c.SendMessage(ws.PreferencesChannel, types.ERROR, "Invalid payload")
+		return
+	}
+
+	switch ev.Type {
+	case types.GetPreferences:
+		c.SendMessage(ws.PreferencesChannel, ev.Type, c.Preferences())
+	case types.SetPreferences:
+		p := &types.Preferences{}
+		if ev.Payload != nil {
+			b, _ = json.Marshal(ev.Payload)
+			if err := json.Unmarshal(b, p); err != nil {
+				c.SendMessage(ws.PreferencesChannel, types.ERROR, "Invalid payload")
+				return
+			}
+		}
+
+		prefs, err := types.NewPreferences(p.Timezone, p.Units)
+		if err != nil {
+			c.SendMessage(ws.PreferencesChannel, types.ERROR, err.Error())
+			return
+		}
+
+		c.SetPreferences(prefs)
+		c.SendMessage(ws.PreferencesChannel, ev.Type, prefs)
+	default:
+		c.SendMessage(ws.PreferencesChannel, types.ERROR, "Invalid event type")
+	}
+}
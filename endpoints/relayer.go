@@ -4,11 +4,17 @@ import (
 	"math/big"
 	"net/http"
 	"net/url"
+	"strconv"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/gorilla/mux"
-	"github.com/tomochain/tomox-sdk/app"
+	"github.com/justinas/alice"
 	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/middlewares"
+	"github.com/tomochain/tomox-sdk/relayer"
+	"github.com/tomochain/tomox-sdk/services"
+	"github.com/tomochain/tomox-sdk/types"
 	"github.com/tomochain/tomox-sdk/utils/httputils"
 )
 
@@ -24,27 +30,34 @@ func ServeRelayerResource(
 	relayerService interfaces.RelayerService,
 	ohlcvService interfaces.OHLCVService,
 	lendingOhlcvService interfaces.LendingOhlcvService,
+	rbac *middlewares.RBAC,
 ) {
 	e := &relayerEndpoint{relayerService, ohlcvService, lendingOhlcvService}
-	r.HandleFunc("/api/relayer", e.handleRelayerUpdate).Methods("PUT")
+	r.Handle(
+		"/api/relayer",
+		alice.New(rbac.Require(types.RoleAdmin, "relayer.update")).Then(http.HandlerFunc(e.handleRelayerUpdate)),
+	).Methods("PUT")
+	r.Handle(
+		"/api/admin/relayer/token-info",
+		alice.New(rbac.Require(types.RoleOperator, "admin.relayer.token_info")).Then(http.HandlerFunc(e.handleInvalidateTokenInfo)),
+	).Methods("DELETE")
 	r.HandleFunc("/api/relayer/all", e.handleGetRelayers).Methods("GET")
+	r.HandleFunc("/api/relayer/aggregated", e.handleGetAggregated).Methods("GET")
+	r.HandleFunc("/api/relayer/info", e.handleGetInfo).Methods("GET")
+	r.HandleFunc("/api/relayer/state", e.handleGetState).Methods("GET")
+	r.HandleFunc("/api/relayer/owner/{owner}", e.handleGetRelayersByOwner).Methods("GET")
+	r.HandleFunc("/api/relayer/id/{id}", e.handleGetRelayerByID).Methods("GET")
 	r.HandleFunc("/api/relayer/volume", e.handleGetVolume).Methods("GET")
 	r.HandleFunc("/api/relayer/lending", e.handleGetLendingVolume).Methods("GET")
 }
 
 func (e *relayerEndpoint) handleRelayerUpdate(w http.ResponseWriter, r *http.Request) {
 	v := r.URL.Query()
-	authKey := v.Get("authKey")
 	relayerName := v.Get("relayerName")
 	relayerUrl := v.Get("relayerUrl")
 	address := v.Get("relayerAddress")
 	relayerAddress := common.HexToAddress(address)
 
-	if app.Config.ApiAuthKey != authKey {
-		httputils.WriteError(w, http.StatusBadRequest, "Invalid auth key")
-		return
-	}
-
 	relayer, err := e.relayerService.GetByAddress(relayerAddress)
 	if relayer == nil {
 		err = e.relayerService.UpdateRelayer(relayerAddress)
@@ -70,6 +83,135 @@ func (e *relayerEndpoint) handleRelayerUpdate(w http.ResponseWriter, r *http.Req
 	httputils.WriteMessage(w, http.StatusOK, "OK")
 }
 
+// handleGetAggregated returns the tokens and the pairs of the aggregated relayers with the fees of each relayer
+func (e *relayerEndpoint) handleGetAggregated(w http.ResponseWriter, r *http.Request) {
+	res, err := e.relayerService.GetAggregated()
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, res)
+}
+
+// handleGetInfo returns the branding, the fees and the listings of the relayer of the request for the
+// white label frontends
+func (e *relayerEndpoint) handleGetInfo(w http.ResponseWriter, r *http.Request) {
+	res, err := e.relayerService.GetInfo(e.relayerService.GetRelayerAddress(r))
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, res)
+}
+
+// handleGetState returns the registrations of the relayer of the "coinbase" param, the relayer of the request
+// without param, at the block of the "blockNumber" or "blockHash" param, the latest one without param
+func (e *relayerEndpoint) handleGetState(w http.ResponseWriter, r *http.Request) {
+	v := r.URL.Query()
+	coinbase := e.relayerService.GetRelayerAddress(r)
+	if c := v.Get("coinbase"); c != "" {
+		if !common.IsHexAddress(c) {
+			httputils.WriteError(w, http.StatusBadRequest, "Invalid coinbase")
+			return
+		}
+
+		coinbase = common.HexToAddress(c)
+	}
+
+	number, hash := v.Get("blockNumber"), v.Get("blockHash")
+	var block relayer.BlockRef
+	switch {
+	case number != "" && hash != "":
+		httputils.WriteError(w, http.StatusBadRequest, "Only one of blockNumber and blockHash can be set")
+		return
+	case number != "":
+		n, ok := new(big.Int).SetString(number, 10)
+		if !ok || n.Sign() < 0 {
+			httputils.WriteError(w, http.StatusBadRequest, "Invalid block number")
+			return
+		}
+
+		block = relayer.AtBlockNumber(n)
+	case hash != "":
+		b, err := hexutil.Decode(hash)
+		if err != nil || len(b) != common.HashLength {
+			httputils.WriteError(w, http.StatusBadRequest, "Invalid block hash")
+			return
+		}
+
+		block = relayer.AtBlockHash(common.BytesToHash(b))
+	}
+
+	res, err := e.relayerService.GetStateAt(coinbase, block)
+	switch err {
+	case nil:
+		httputils.WriteJSON(w, http.StatusOK, res)
+	case services.ErrRelayerNotRegistered:
+		httputils.WriteError(w, http.StatusNotFound, err.Error())
+	default:
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+	}
+}
+
+// handleInvalidateTokenInfo drops the cached metadata of the "token" params, of all the tokens without param
+// handleGetRelayersByOwner returns the relayers registered by the owner on the registration contract
+func (e *relayerEndpoint) handleGetRelayersByOwner(w http.ResponseWriter, r *http.Request) {
+	owner := mux.Vars(r)["owner"]
+	if !common.IsHexAddress(owner) {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid owner")
+		return
+	}
+
+	res, err := e.relayerService.GetByOwner(common.HexToAddress(owner))
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, res)
+}
+
+// handleGetRelayerByID returns the relayer at the index of the registration contract
+func (e *relayerEndpoint) handleGetRelayerByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil || id < 0 {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid id")
+		return
+	}
+
+	res, err := e.relayerService.GetByID(id)
+	switch err {
+	case nil:
+		httputils.WriteJSON(w, http.StatusOK, res)
+	case services.ErrRelayerNotRegistered:
+		httputils.WriteError(w, http.StatusNotFound, err.Error())
+	default:
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+	}
+}
+
+func (e *relayerEndpoint) handleInvalidateTokenInfo(w http.ResponseWriter, r *http.Request) {
+	tokens := []common.Address{}
+	for _, t := range r.URL.Query()["token"] {
+		if !common.IsHexAddress(t) {
+			httputils.WriteError(w, http.StatusBadRequest, "Invalid token")
+			return
+		}
+
+		tokens = append(tokens, common.HexToAddress(t))
+	}
+
+	e.relayerService.InvalidateTokenInfo(tokens...)
+	httputils.WriteMessage(w, http.StatusOK, "OK")
+}
+
 // HandleGetVolume get volume relayer
 func (e *relayerEndpoint) handleGetVolume(w http.ResponseWriter, r *http.Request) {
 	type res struct {
@@ -0,0 +1,130 @@
+package endpoints
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+	"github.com/justinas/alice"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/middlewares"
+	"github.com/tomochain/tomox-sdk/services"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+)
+
+type reportEndpoint struct {
+	reportService interfaces.ReportService
+}
+
+// ServeReportResource sets up the routing of the report endpoints, served from the daily aggregates
+func ServeReportResource(
+	r *mux.Router,
+	reportService interfaces.ReportService,
+	rbac *middlewares.RBAC,
+) {
+	e := &reportEndpoint{reportService}
+	r.HandleFunc("/api/reports/volume/{address}", e.handleGetUserVolumes).Methods("GET")
+	r.HandleFunc("/api/reports/pairs", e.handleGetPairStats).Methods("GET")
+
+	r.Handle(
+		"/api/admin/reports/fees",
+		alice.New(rbac.Require(types.RoleOperator, "admin.reports.fees")).Then(http.HandlerFunc(e.handleGetFeeTotals)),
+	).Methods("GET")
+}
+
+// handleGetUserVolumes returns the daily volumes of an address per pair between from and to,
+// unix timestamps in seconds, the last 30 days by default
+func (e *reportEndpoint) handleGetUserVolumes(w http.ResponseWriter, r *http.Request) {
+	addr := mux.Vars(r)["address"]
+	if !common.IsHexAddress(addr) {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid Address")
+		return
+	}
+
+	from, to, ok := e.parsePeriod(w, r.URL.Query())
+	if !ok {
+		return
+	}
+
+	res, err := e.reportService.GetUserVolumes(common.HexToAddress(addr), from, to)
+	e.writeReport(w, res, err)
+}
+
+// handleGetPairStats returns the daily stats of the pair of the baseToken and quoteToken params,
+// of all the pairs if they are missing
+func (e *reportEndpoint) handleGetPairStats(w http.ResponseWriter, r *http.Request) {
+	v := r.URL.Query()
+	bt := v.Get("baseToken")
+	qt := v.Get("quoteToken")
+
+	if (bt != "" || qt != "") && (!common.IsHexAddress(bt) || !common.IsHexAddress(qt)) {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid baseToken or quoteToken")
+		return
+	}
+
+	from, to, ok := e.parsePeriod(w, v)
+	if !ok {
+		return
+	}
+
+	res, err := e.reportService.GetPairStats(common.HexToAddress(bt), common.HexToAddress(qt), from, to)
+	e.writeReport(w, res, err)
+}
+
+// handleGetFeeTotals returns the daily fees collected per token
+func (e *reportEndpoint) handleGetFeeTotals(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := e.parsePeriod(w, r.URL.Query())
+	if !ok {
+		return
+	}
+
+	res, err := e.reportService.GetFeeTotals(from, to)
+	e.writeReport(w, res, err)
+}
+
+// parsePeriod reads the from and to params, unix timestamps in seconds, the last 30 days by default
+func (e *reportEndpoint) parsePeriod(w http.ResponseWriter, v url.Values) (time.Time, time.Time, bool) {
+	to := time.Now()
+	from := to.AddDate(0, 0, -30)
+
+	if f := v.Get("from"); f != "" {
+		ts, err := strconv.ParseInt(f, 10, 64)
+		if err != nil {
+			httputils.WriteError(w, http.StatusBadRequest, "Invalid from")
+			return from, to, false
+		}
+
+		from = time.Unix(ts, 0)
+	}
+
+	if t := v.Get("to"); t != "" {
+		ts, err := strconv.ParseInt(t, 10, 64)
+		if err != nil {
+			httputils.WriteError(w, http.StatusBadRequest, "Invalid to")
+			return from, to, false
+		}
+
+		to = time.Unix(ts, 0)
+	}
+
+	return from, to, true
+}
+
+func (e *reportEndpoint) writeReport(w http.ResponseWriter, res interface{}, err error) {
+	if err == services.ErrInvalidReportPeriod {
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, res)
+}
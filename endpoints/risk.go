@@ -0,0 +1,122 @@
+package endpoints
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+	"github.com/justinas/alice"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/middlewares"
+	"github.com/tomochain/tomox-sdk/services"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+)
+
+type riskEndpoint struct {
+	riskService interfaces.RiskService
+}
+
+// ServeRiskResource sets up the routing of the pre-trade risk limits admin endpoints
+func ServeRiskResource(
+	r *mux.Router,
+	riskService interfaces.RiskService,
+	rbac *middlewares.RBAC,
+) {
+	e := &riskEndpoint{riskService}
+
+	r.Handle(
+		"/api/admin/risk/limits",
+		alice.New(rbac.Require(types.RoleViewer, "admin.risk")).Then(http.HandlerFunc(e.handleGetLimits)),
+	).Methods("GET")
+
+	r.Handle(
+		"/api/admin/risk/limits",
+		alice.New(rbac.Require(types.RoleAdmin, "admin.risk.set")).Then(http.HandlerFunc(e.handleSetLimit)),
+	).Methods("PUT")
+
+	r.Handle(
+		"/api/admin/risk/limits",
+		alice.New(rbac.Require(types.RoleAdmin, "admin.risk.delete")).Then(http.HandlerFunc(e.handleDeleteLimit)),
+	).Methods("DELETE")
+}
+
+// handleGetLimits returns the risk limits, the ones applying to the "account" param if set
+func (e *riskEndpoint) handleGetLimits(w http.ResponseWriter, r *http.Request) {
+	var account *common.Address
+	if a := r.URL.Query().Get("account"); a != "" {
+		if !common.IsHexAddress(a) {
+			httputils.WriteError(w, http.StatusBadRequest, "Invalid account")
+			return
+		}
+
+		addr := common.HexToAddress(a)
+		account = &addr
+	}
+
+	res, err := e.riskService.GetLimits(account)
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, res)
+}
+
+// handleSetLimit creates or replaces the risk limit of an account on a pair. The zero address
+// (or no address) applies the limit to all the accounts or all the pairs, the amounts are in base units
+func (e *riskEndpoint) handleSetLimit(w http.ResponseWriter, r *http.Request) {
+	l := &types.RiskLimit{}
+
+	decoder := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+
+	err := decoder.Decode(l)
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if (l.BaseToken == common.Address{}) != (l.QuoteToken == common.Address{}) {
+		httputils.WriteError(w, http.StatusBadRequest, "baseToken and quoteToken must be both set or both empty")
+		return
+	}
+
+	res, err := e.riskService.SetLimit(l, middlewares.GetIdentity(r).Name)
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, res)
+}
+
+// handleDeleteLimit removes the risk limit of the "account", "baseToken" and "quoteToken" params,
+// the missing params select the limits of all the accounts or all the pairs
+func (e *riskEndpoint) handleDeleteLimit(w http.ResponseWriter, r *http.Request) {
+	v := r.URL.Query()
+	addrs := []common.Address{}
+	for _, param := range []string{"account", "baseToken", "quoteToken"} {
+		a := v.Get(param)
+		if a != "" && !common.IsHexAddress(a) {
+			httputils.WriteError(w, http.StatusBadRequest, "Invalid "+param)
+			return
+		}
+
+		addrs = append(addrs, common.HexToAddress(a))
+	}
+
+	err := e.riskService.DeleteLimit(addrs[0], addrs[1], addrs[2], middlewares.GetIdentity(r).Name)
+	switch err {
+	case nil:
+		httputils.WriteMessage(w, http.StatusOK, "OK")
+	case services.ErrRiskLimitNotFound:
+		httputils.WriteError(w, http.StatusNotFound, err.Error())
+	default:
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+	}
+}
@@ -0,0 +1,36 @@
+package endpoints
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+)
+
+type statsEndpoint struct {
+	statsService interfaces.StatsService
+}
+
+// ServeStatsResource sets up the routing of the public stats endpoint
+func ServeStatsResource(
+	r *mux.Router,
+	statsService interfaces.StatsService,
+) {
+	e := &statsEndpoint{statsService}
+	r.HandleFunc("/api/stats/public", e.handleGetPublicStats).Methods("GET")
+}
+
+// handleGetPublicStats returns the network-wide aggregates of the last 24 hours.
+// They are anonymous and may be embedded in the ecosystem dashboards
+func (e *statsEndpoint) handleGetPublicStats(w http.ResponseWriter, r *http.Request) {
+	res, err := e.statsService.GetPublicStats()
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=60")
+	httputils.WriteJSON(w, http.StatusOK, res)
+}
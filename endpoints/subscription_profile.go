@@ -0,0 +1,75 @@
+package endpoints
+
+import (
+	"encoding/json"
+
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/middlewares"
+	"github.com/tomochain/tomox-sdk/services"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/ws"
+)
+
+type subscriptionProfileEndpoint struct {
+	profileService interfaces.SubscriptionProfileService
+}
+
+// ServeSubscriptionProfileResource sets up the websocket channel of the subscription profiles
+func ServeSubscriptionProfileResource(profileService interfaces.SubscriptionProfileService) {
+	e := &subscriptionProfileEndpoint{profileService}
+	ws.RegisterChannel(ws.ProfileChannel, e.profileWebsocket)
+}
+
+// profileWebsocket saves, lists, deletes and activates the subscription profiles of the API key of the connection
+func (e *subscriptionProfileEndpoint) profileWebsocket(input interface{}, c *ws.Client) {
+	if !types.HasRole(middlewares.APIKeyRole(c.APIKey()), types.RoleViewer) {
+		c.SendMessage(ws.ProfileChannel, types.ERROR, "Invalid API key")
+		return
+	}
+
+	b, _ := json.Marshal(input)
+	var ev *types.WebsocketEvent
+	if err := json.Unmarshal(b, &ev); err != nil || ev == nil {
+		c.SendMessage(ws.ProfileChannel, types.ERROR, "Invalid payload")
+		return
+	}
+
+	p := &types.SubscriptionProfilePayload{}
+	if ev.Payload != nil {
+		b, _ = json.Marshal(ev.Payload)
+		if err := json.Unmarshal(b, p); err != nil {
+			c.SendMessage(ws.ProfileChannel, types.ERROR, "Invalid payload")
+			return
+		}
+	}
+
+	owner := middlewares.APIKeyOwner(c.APIKey())
+
+	var res interface{}
+	var err error
+	switch ev.Type {
+	case types.GetProfiles:
+		res, err = e.profileService.GetAll(owner)
+	case types.SaveProfile:
+		err = e.profileService.Save(owner, p)
+		res = p
+	case types.DeleteProfile:
+		err = e.profileService.Delete(owner, p.Name)
+		res = p.Name
+	case types.ActivateProfile:
+		res, err = e.profileService.Activate(owner, p.Name, c)
+	default:
+		c.SendMessage(ws.ProfileChannel, types.ERROR, "Invalid event type")
+		return
+	}
+
+	switch err {
+	case nil:
+		c.SendMessage(ws.ProfileChannel, ev.Type, res)
+	case services.ErrProfileNotFound, services.ErrInvalidProfile, services.ErrTooManyProfiles:
+		c.SendMessage(ws.ProfileChannel, types.ERROR, err.Error())
+	default:
+		logger.Error(err)
+		c.SendMessage(ws.ProfileChannel, types.ERROR, "Internal error")
+	}
+}
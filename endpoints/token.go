@@ -7,8 +7,10 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/gorilla/mux"
+	"github.com/justinas/alice"
 	"github.com/tomochain/tomox-sdk/app"
 	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/middlewares"
 	"github.com/tomochain/tomox-sdk/services"
 	"github.com/tomochain/tomox-sdk/types"
 	"github.com/tomochain/tomox-sdk/utils/httputils"
@@ -25,22 +27,42 @@ func ServeTokenResource(
 	r *mux.Router,
 	tokenService interfaces.TokenService,
 	relayerService interfaces.RelayerService,
+	rbac *middlewares.RBAC,
 ) {
 	e := &tokenEndpoint{tokenService, relayerService}
 	r.HandleFunc("/api/tokens/base", e.HandleGetBaseTokens).Methods("GET")
 	r.HandleFunc("/api/tokens/quote", e.HandleGetQuoteTokens).Methods("GET")
+	r.HandleFunc("/api/tokens/symbol/{symbol}", e.HandleGetTokenBySymbol).Methods("GET")
 	r.HandleFunc("/api/tokens/{address}", e.HandleGetToken).Methods("GET")
 	r.HandleFunc("/api/tokens", e.HandleGetTokens).Methods("GET")
 	// r.HandleFunc("/api/tokens", e.HandleCreateToken).Methods("POST")
 
+	r.Handle(
+		"/api/admin/tokens/aliases",
+		alice.New(rbac.Require(types.RoleViewer, "admin.tokens.aliases")).Then(http.HandlerFunc(e.handleGetAliases)),
+	).Methods("GET")
+
+	r.Handle(
+		"/api/admin/tokens/aliases/{symbol}",
+		alice.New(rbac.Require(types.RoleOperator, "admin.tokens.aliases.set")).Then(http.HandlerFunc(e.handleSetAlias)),
+	).Methods("PUT")
+
+	r.Handle(
+		"/api/admin/tokens/aliases/{symbol}",
+		alice.New(rbac.Require(types.RoleOperator, "admin.tokens.aliases.delete")).Then(http.HandlerFunc(e.handleDeleteAlias)),
+	).Methods("DELETE")
+
+	r.Handle(
+		"/api/admin/tokens/{address}/verified",
+		alice.New(rbac.Require(types.RoleOperator, "admin.tokens.verify")).Then(http.HandlerFunc(e.handleSetVerified)),
+	).Methods("PUT")
+
 	ws.RegisterChannel(ws.TokenChannel, e.ws)
 }
 
 func (e *tokenEndpoint) HandleCreateToken(w http.ResponseWriter, r *http.Request) {
 	var t types.Token
-	decoder := json.NewDecoder(r.Body)
-
-	err := decoder.Decode(&t)
+	err := httputils.DecodeJSON(r, &t)
 	if err != nil {
 		logger.Error(err)
 		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
@@ -64,7 +86,21 @@ func (e *tokenEndpoint) HandleCreateToken(w http.ResponseWriter, r *http.Request
 	httputils.WriteJSON(w, http.StatusCreated, t)
 }
 
+// HandleGetTokens returns the tokens of the relayer, the tokens of all the contracts sharing
+// the "symbol" param if set
 func (e *tokenEndpoint) HandleGetTokens(w http.ResponseWriter, r *http.Request) {
+	if symbol := r.URL.Query().Get("symbol"); symbol != "" {
+		res, err := e.tokenService.GetAllBySymbol(symbol)
+		if err != nil {
+			logger.Error(err)
+			httputils.WriteError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		httputils.WriteJSON(w, http.StatusOK, res)
+		return
+	}
+
 	ex := e.relayerService.GetRelayerAddress(r)
 	res, err := e.tokenService.GetAllByCoinbase(ex)
 
@@ -123,6 +159,148 @@ func (e *tokenEndpoint) HandleGetToken(w http.ResponseWriter, r *http.Request) {
 	httputils.WriteJSON(w, http.StatusOK, res)
 }
 
+// HandleGetTokenBySymbol returns the token a symbol refers to, a symbol shared by several
+// tokens without a canonical one is answered 409 with the candidate tokens
+func (e *tokenEndpoint) HandleGetTokenBySymbol(w http.ResponseWriter, r *http.Request) {
+	symbol := mux.Vars(r)["symbol"]
+
+	res, candidates, err := e.tokenService.ResolveSymbol(symbol)
+	if err != nil {
+		writeSymbolError(w, err, candidates)
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, res)
+}
+
+// tokenParam returns the address of a token param given as a contract address or as a symbol.
+// An unknown or ambiguous symbol is answered and false is returned
+func tokenParam(w http.ResponseWriter, tokenService interfaces.TokenService, name, value string) (common.Address, bool) {
+	if value == "" {
+		httputils.WriteError(w, http.StatusBadRequest, name+" Parameter missing")
+		return common.Address{}, false
+	}
+
+	if common.IsHexAddress(value) {
+		return common.HexToAddress(value), true
+	}
+
+	t, candidates, err := tokenService.ResolveSymbol(value)
+	if err != nil {
+		writeSymbolError(w, err, candidates)
+		return common.Address{}, false
+	}
+
+	return t.ContractAddress, true
+}
+
+// writeSymbolError answers a symbol not resolved, with the candidate tokens if it is ambiguous
+func writeSymbolError(w http.ResponseWriter, err error, candidates []types.Token) {
+	switch err {
+	case services.ErrTokenNotFound:
+		httputils.WriteError(w, http.StatusNotFound, err.Error())
+	case services.ErrTokenSymbolAmbiguous:
+		httputils.Write(w, http.StatusConflict, map[string]interface{}{
+			"error":      err.Error(),
+			"candidates": candidates,
+		})
+	default:
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+	}
+}
+
+func (e *tokenEndpoint) handleGetAliases(w http.ResponseWriter, r *http.Request) {
+	res, err := e.tokenService.GetAliases()
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, res)
+}
+
+// handleSetAlias makes the token of the "address" field the canonical one of the symbol
+func (e *tokenEndpoint) handleSetAlias(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Address string `json:"address"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+
+	err := decoder.Decode(&payload)
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !common.IsHexAddress(payload.Address) {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid Address")
+		return
+	}
+
+	res, err := e.tokenService.SetAlias(mux.Vars(r)["symbol"], common.HexToAddress(payload.Address), middlewares.GetIdentity(r).Name)
+	switch err {
+	case nil:
+		httputils.WriteJSON(w, http.StatusOK, res)
+	case services.ErrTokenNotFound:
+		httputils.WriteError(w, http.StatusNotFound, err.Error())
+	case services.ErrTokenSymbolMismatch:
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+	default:
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+	}
+}
+
+func (e *tokenEndpoint) handleDeleteAlias(w http.ResponseWriter, r *http.Request) {
+	err := e.tokenService.DeleteAlias(mux.Vars(r)["symbol"], middlewares.GetIdentity(r).Name)
+	switch err {
+	case nil:
+		httputils.WriteMessage(w, http.StatusOK, "OK")
+	case services.ErrTokenAliasNotFound:
+		httputils.WriteError(w, http.StatusNotFound, err.Error())
+	default:
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+	}
+}
+
+// handleSetVerified sets the verified flag of a token contract from the "verified" field
+func (e *tokenEndpoint) handleSetVerified(w http.ResponseWriter, r *http.Request) {
+	a := mux.Vars(r)["address"]
+	if !common.IsHexAddress(a) {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid Address")
+		return
+	}
+
+	var payload struct {
+		Verified *bool `json:"verified"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+
+	err := decoder.Decode(&payload)
+	if err != nil || payload.Verified == nil {
+		httputils.WriteError(w, http.StatusBadRequest, "verified is required")
+		return
+	}
+
+	res, err := e.tokenService.SetVerified(common.HexToAddress(a), *payload.Verified, middlewares.GetIdentity(r).Name)
+	switch err {
+	case nil:
+		httputils.WriteJSON(w, http.StatusOK, res)
+	case services.ErrTokenNotFound:
+		httputils.WriteError(w, http.StatusNotFound, err.Error())
+	default:
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+	}
+}
+
 // ws function handles incoming websocket messages on the order channel
 func (e *tokenEndpoint) ws(input interface{}, c *ws.Client) {
 	// it means that we can handle not only WebSocketPayload but other Payloads as well
@@ -0,0 +1,118 @@
+package endpoints
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/globalsign/mgo/bson"
+	"github.com/gorilla/mux"
+	"github.com/justinas/alice"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/middlewares"
+	"github.com/tomochain/tomox-sdk/services"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+)
+
+type tokenMigrationEndpoint struct {
+	tokenMigrationService interfaces.TokenMigrationService
+}
+
+// ServeTokenMigrationResource sets up the routing of the token migration admin endpoints
+func ServeTokenMigrationResource(
+	r *mux.Router,
+	tokenMigrationService interfaces.TokenMigrationService,
+	rbac *middlewares.RBAC,
+) {
+	e := &tokenMigrationEndpoint{tokenMigrationService}
+
+	r.Handle(
+		"/api/admin/tokens/migrations",
+		alice.New(rbac.Require(types.RoleViewer, "admin.tokens.migrations")).Then(http.HandlerFunc(e.handleGetMigrations)),
+	).Methods("GET")
+
+	r.Handle(
+		"/api/admin/tokens/migrations",
+		alice.New(rbac.Require(types.RoleAdmin, "admin.tokens.migrations.create")).Then(http.HandlerFunc(e.handleMigrate)),
+	).Methods("POST")
+
+	r.Handle(
+		"/api/admin/tokens/migrations/{id}",
+		alice.New(rbac.Require(types.RoleViewer, "admin.tokens.migrations")).Then(http.HandlerFunc(e.handleGetMigration)),
+	).Methods("GET")
+}
+
+func (e *tokenMigrationEndpoint) handleGetMigrations(w http.ResponseWriter, r *http.Request) {
+	res, err := e.tokenMigrationService.GetAll()
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, res)
+}
+
+// handleMigrate migrates the pairs of the old token to the new token and returns the migration report,
+// with "dryRun" the report is returned without halting or creating any pair
+func (e *tokenMigrationEndpoint) handleMigrate(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		OldToken string `json:"oldToken"`
+		NewToken string `json:"newToken"`
+		DryRun   bool   `json:"dryRun"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+
+	err := decoder.Decode(&payload)
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
+		return
+	}
+
+	if !common.IsHexAddress(payload.OldToken) || !common.IsHexAddress(payload.NewToken) {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid oldToken or newToken")
+		return
+	}
+
+	oldToken := common.HexToAddress(payload.OldToken)
+	newToken := common.HexToAddress(payload.NewToken)
+
+	res, err := e.tokenMigrationService.Migrate(oldToken, newToken, payload.DryRun, middlewares.GetIdentity(r).Name)
+	switch err {
+	case nil:
+		if payload.DryRun {
+			httputils.WriteJSON(w, http.StatusOK, res)
+		} else {
+			httputils.WriteJSON(w, http.StatusCreated, res)
+		}
+	case services.ErrInvalidTokenMigration:
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+	case services.ErrTokenNotFound, services.ErrNewTokenNotListed:
+		httputils.WriteError(w, http.StatusNotFound, err.Error())
+	default:
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+	}
+}
+
+func (e *tokenMigrationEndpoint) handleGetMigration(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if !bson.IsObjectIdHex(id) {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid migration id")
+		return
+	}
+
+	res, err := e.tokenMigrationService.GetByID(bson.ObjectIdHex(id))
+	switch err {
+	case nil:
+		httputils.WriteJSON(w, http.StatusOK, res)
+	case services.ErrTokenMigrationNotFound:
+		httputils.WriteError(w, http.StatusNotFound, err.Error())
+	default:
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+	}
+}
@@ -2,17 +2,28 @@ package endpoints
 
 import (
 	"encoding/json"
+	"math/big"
 	"net/http"
+	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/gorilla/mux"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/errors"
 	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/middlewares"
 	"github.com/tomochain/tomox-sdk/types"
 	"github.com/tomochain/tomox-sdk/utils/httputils"
 	"github.com/tomochain/tomox-sdk/ws"
 )
 
+const (
+	defaultAggregationWindow = time.Hour
+	maxAggregationWindow     = 7 * 24 * time.Hour
+)
+
 type tradeEndpoint struct {
 	tradeService   interfaces.TradeService
 	relayerService interfaces.RelayerService
@@ -28,7 +39,9 @@ func ServeTradeResource(
 	e := &tradeEndpoint{tradeService, relayerService}
 	r.HandleFunc("/api/trades", e.HandleGetTrades)
 	r.HandleFunc("/api/trades/history", e.HandleGetTradesHistory)
+	r.HandleFunc("/api/trades/aggregated", e.HandleGetAggregatedTrades).Methods("GET")
 	ws.RegisterChannel(ws.TradeChannel, e.tradeWebsocket)
+	ws.RegisterChannel(ws.RawTradeChannel, e.rawTradeWebsocket)
 }
 
 // HandleGetTrades is responsible for getting pair's trade history requests
@@ -78,6 +91,12 @@ func (e *tradeEndpoint) HandleGetTrades(w http.ResponseWriter, r *http.Request)
 		tradeSpec.DateFrom = int64(t)
 	}
 
+	err := parseTradeFilters(v, &tradeSpec)
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	offset := 0
 	size := types.DefaultLimit
 	sortDB := []string{}
@@ -127,7 +146,20 @@ func (e *tradeEndpoint) HandleGetTrades(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	httputils.WriteJSON(w, http.StatusOK, res)
+	trades := types.PublicTrades(res.Trades, app.Config.TradeFeedPrivacy)
+	if len(tradeSpec.Fields) > 0 {
+		selected, err := types.SelectFields(trades, tradeSpec.Fields)
+		if err != nil {
+			logger.Error(err)
+			httputils.WriteError(w, http.StatusInternalServerError, "")
+			return
+		}
+
+		httputils.WriteJSON(w, http.StatusOK, map[string]interface{}{"total": res.Total, "trades": selected})
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, map[string]interface{}{"total": res.Total, "trades": trades})
 }
 
 // HandleGetTradesHistory is responsible for handling user's trade history requests
@@ -190,6 +222,12 @@ func (e *tradeEndpoint) HandleGetTradesHistory(w http.ResponseWriter, r *http.Re
 		tradeSpec.DateFrom = int64(t)
 	}
 
+	err := parseTradeFilters(v, &tradeSpec)
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	offset := 0
 	size := types.DefaultLimit
 	sortDB := []string{}
@@ -235,11 +273,137 @@ func (e *tradeEndpoint) HandleGetTradesHistory(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	if len(tradeSpec.Fields) > 0 {
+		selected, err := types.SelectFields(res.Trades, tradeSpec.Fields)
+		if err != nil {
+			logger.Error(err)
+			httputils.WriteError(w, http.StatusInternalServerError, "")
+			return
+		}
+
+		httputils.WriteJSON(w, http.StatusOK, map[string]interface{}{"total": res.Total, "trades": selected})
+		return
+	}
+
 	httputils.WriteJSON(w, http.StatusOK, res)
+}
+
+// parseTradeFilters reads the time-and-sales filters (side, minAmount, address, role) and the
+// selected fields of the query
+func parseTradeFilters(v url.Values, tradeSpec *types.TradeSpec) error {
+	side := v.Get("side")
+	minAmount := v.Get("minAmount")
+	addr := v.Get("address")
+	role := v.Get("role")
+
+	if side != "" {
+		if side != types.BUY && side != types.SELL {
+			return errors.New("Invalid side")
+		}
+		tradeSpec.Side = side
+	}
+
+	if minAmount != "" {
+		amount, ok := new(big.Int).SetString(minAmount, 10)
+		if !ok || amount.Sign() < 0 {
+			return errors.New("Invalid min amount")
+		}
+		tradeSpec.MinAmount = amount
+	}
+
+	if role != "" {
+		if role != types.TradeRoleMaker && role != types.TradeRoleTaker {
+			return errors.New("Invalid role")
+		}
 
+		if addr == "" {
+			return errors.New("address Parameter missing")
+		}
+		tradeSpec.Role = role
+	}
+
+	if addr != "" {
+		if !common.IsHexAddress(addr) {
+			return errors.New("Invalid Address")
+		}
+		tradeSpec.Address = common.HexToAddress(addr).Hex()
+	}
+
+	fields, err := types.ParseFields(v.Get("fields"), types.TradeFields)
+	if err != nil {
+		return err
+	}
+	tradeSpec.Fields = fields
+
+	return nil
+}
+
+// HandleGetAggregatedTrades returns the volume traded per price level and side over a window
+func (e *tradeEndpoint) HandleGetAggregatedTrades(w http.ResponseWriter, r *http.Request) {
+	v := r.URL.Query()
+	bt := v.Get("baseToken")
+	qt := v.Get("quoteToken")
+	windowParam := v.Get("window")
+	priceStepParam := v.Get("priceStep")
+
+	if !common.IsHexAddress(bt) {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid base token address")
+		return
+	}
+
+	if !common.IsHexAddress(qt) {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid quote token address")
+		return
+	}
+
+	window := defaultAggregationWindow
+	if windowParam != "" {
+		t, err := strconv.Atoi(windowParam)
+		if err != nil || t <= 0 || time.Duration(t)*time.Second > maxAggregationWindow {
+			httputils.WriteError(w, http.StatusBadRequest, "Invalid window")
+			return
+		}
+		window = time.Duration(t) * time.Second
+	}
+
+	priceStep, ok := new(big.Int).SetString(priceStepParam, 10)
+	if !ok || priceStep.Sign() <= 0 {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid price step")
+		return
+	}
+
+	res, err := e.tradeService.GetVolumeProfile(common.HexToAddress(bt), common.HexToAddress(qt), window, priceStep)
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, res)
 }
 
 func (e *tradeEndpoint) tradeWebsocket(input interface{}, c *ws.Client) {
+	e.handleTradeSubscription(input, c, ws.GetTradeSocket(), e.tradeService.Subscribe, e.tradeService.UnsubscribeChannel, e.tradeService.Unsubscribe)
+}
+
+// rawTradeWebsocket handles the subscriptions of the unsampled trades channel, restricted to the API keys
+func (e *tradeEndpoint) rawTradeWebsocket(input interface{}, c *ws.Client) {
+	if !types.HasRole(middlewares.APIKeyRole(c.APIKey()), types.RoleViewer) {
+		ws.GetRawTradeSocket().SendErrorMessage(c, map[string]string{"Message": "Invalid API key"})
+		return
+	}
+
+	e.handleTradeSubscription(input, c, ws.GetRawTradeSocket(), e.tradeService.SubscribeRawTrades, e.tradeService.UnsubscribeRawTradesChannel, e.tradeService.UnsubscribeRawTrades)
+}
+
+func (e *tradeEndpoint) handleTradeSubscription(
+	input interface{},
+	c *ws.Client,
+	socket *ws.TradeSocket,
+	subscribe func(c *ws.Client, bt, qt common.Address),
+	unsubscribeChannel func(c *ws.Client, bt, qt common.Address),
+	unsubscribe func(c *ws.Client),
+) {
 	b, _ := json.Marshal(input)
 	var ev *types.WebsocketEvent
 	errInvalidPayload := map[string]string{"Message": "Invalid payload"}
@@ -247,7 +411,6 @@ func (e *tradeEndpoint) tradeWebsocket(input interface{}, c *ws.Client) {
 		logger.Error(err)
 		return
 	}
-	socket := ws.GetTradeSocket()
 	if ev == nil {
 		socket.SendErrorMessage(c, errInvalidPayload)
 		return
@@ -284,15 +447,15 @@ func (e *tradeEndpoint) tradeWebsocket(input interface{}, c *ws.Client) {
 			return
 		}
 
-		e.tradeService.Subscribe(c, p.BaseToken, p.QuoteToken)
+		subscribe(c, p.BaseToken, p.QuoteToken)
 	}
 
 	if ev.Type == types.UNSUBSCRIBE {
 		if p == nil {
-			e.tradeService.Unsubscribe(c)
+			unsubscribe(c)
 			return
 		}
 
-		e.tradeService.UnsubscribeChannel(c, p.BaseToken, p.QuoteToken)
+		unsubscribeChannel(c, p.BaseToken, p.QuoteToken)
 	}
 }
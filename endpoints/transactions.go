@@ -0,0 +1,82 @@
+package endpoints
+
+import (
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+	"github.com/justinas/alice"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/middlewares"
+	"github.com/tomochain/tomox-sdk/services"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+)
+
+type transactionEndpoint struct {
+	nonceManagerService interfaces.NonceManagerService
+}
+
+// ServeTransactionResource sets up the routing of the admin endpoints of the transactions sent by the SDK
+// and not mined yet
+func ServeTransactionResource(
+	r *mux.Router,
+	nonceManagerService interfaces.NonceManagerService,
+	rbac *middlewares.RBAC,
+) {
+	e := &transactionEndpoint{nonceManagerService}
+
+	r.Handle(
+		"/api/admin/transactions",
+		alice.New(rbac.Require(types.RoleOperator, "admin.transactions")).Then(http.HandlerFunc(e.handleGetPending)),
+	).Methods("GET")
+
+	r.Handle(
+		"/api/admin/transactions/{hash}/speedup",
+		alice.New(rbac.Require(types.RoleOperator, "admin.transactions.speedup")).Then(http.HandlerFunc(e.handleSpeedUp)),
+	).Methods("POST")
+
+	r.Handle(
+		"/api/admin/transactions/{hash}/cancel",
+		alice.New(rbac.Require(types.RoleOperator, "admin.transactions.cancel")).Then(http.HandlerFunc(e.handleCancel)),
+	).Methods("POST")
+}
+
+func (e *transactionEndpoint) handleGetPending(w http.ResponseWriter, r *http.Request) {
+	httputils.WriteJSON(w, http.StatusOK, e.nonceManagerService.GetPending())
+}
+
+// handleSpeedUp replaces a pending transaction at the fast gas price
+func (e *transactionEndpoint) handleSpeedUp(w http.ResponseWriter, r *http.Request) {
+	e.handleReplace(w, r, e.nonceManagerService.SpeedUp)
+}
+
+// handleCancel replaces a pending transaction by an empty transfer to its sender
+func (e *transactionEndpoint) handleCancel(w http.ResponseWriter, r *http.Request) {
+	e.handleReplace(w, r, e.nonceManagerService.Cancel)
+}
+
+func (e *transactionEndpoint) handleReplace(
+	w http.ResponseWriter,
+	r *http.Request,
+	replace func(hash common.Hash) (*types.PendingTx, error),
+) {
+	hash := mux.Vars(r)["hash"]
+	if len(common.FromHex(hash)) != common.HashLength {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid transaction hash")
+		return
+	}
+
+	res, err := replace(common.HexToHash(hash))
+	switch err {
+	case nil:
+		httputils.WriteJSON(w, http.StatusOK, res)
+	case services.ErrPendingTxNotFound:
+		httputils.WriteError(w, http.StatusNotFound, err.Error())
+	case services.ErrTooManyReplacements:
+		httputils.WriteError(w, http.StatusConflict, err.Error())
+	default:
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+	}
+}
@@ -0,0 +1,343 @@
+package engine
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// Defaults of the admission control, overridden by the "admission" config section
+const (
+	defaultThrottlePending = 500
+	defaultMaxPending      = 1000
+	defaultThrottleLatency = 500 * time.Millisecond
+	defaultMaxLatency      = 2 * time.Second
+	defaultThrottleDelay   = 200 * time.Millisecond
+	defaultPauseQueueLimit = 1000
+)
+
+// weight of the last match in the average match latency
+const latencyWeight = 0.2
+
+type pairLoad struct {
+	pending int
+	latency time.Duration
+}
+
+// pairQueue counts the orders queued by a pair paused in queue mode
+type pairQueue struct {
+	queued int
+	limit  int
+}
+
+// Admission tracks the depth of the order queue of each pair, the new orders taken from RabbitMQ and not
+// matched yet, and the average match latency of each pair. A pair is throttled or overloaded once one of
+// them crosses its threshold. A pair is paused while it has a pause reason, of its own or of all the pairs,
+// each reason is only lifted by its source
+type Admission struct {
+	loads           map[string]*pairLoad
+	paused          map[string]map[string]bool
+	pausedAll       map[string]bool
+	queues          map[string]*pairQueue
+	queueLimit      int
+	mutex           sync.Mutex
+	throttlePending int
+	maxPending      int
+	throttleLatency time.Duration
+	maxLatency      time.Duration
+	throttleDelay   time.Duration
+}
+
+// NewAdmission returns a new instance of Admission
+func NewAdmission(throttlePending, maxPending int, throttleLatency, maxLatency, throttleDelay time.Duration) *Admission {
+	return &Admission{
+		loads:           make(map[string]*pairLoad),
+		paused:          make(map[string]map[string]bool),
+		pausedAll:       make(map[string]bool),
+		queues:          make(map[string]*pairQueue),
+		queueLimit:      defaultPauseQueueLimit,
+		throttlePending: throttlePending,
+		maxPending:      maxPending,
+		throttleLatency: throttleLatency,
+		maxLatency:      maxLatency,
+		throttleDelay:   throttleDelay,
+	}
+}
+
+// NewAdmissionFromConfig returns an Admission configured from app.Config
+func NewAdmissionFromConfig() *Admission {
+	a := NewAdmission(
+		configInt("throttle_pending", defaultThrottlePending),
+		configInt("max_pending", defaultMaxPending),
+		configDuration("throttle_latency_ms", time.Millisecond, defaultThrottleLatency),
+		configDuration("max_latency_ms", time.Millisecond, defaultMaxLatency),
+		configDuration("throttle_delay_ms", time.Millisecond, defaultThrottleDelay),
+	)
+
+	a.queueLimit = configInt("pause_queue_limit", defaultPauseQueueLimit)
+	return a
+}
+
+// Enqueue records a new order of the pair taken from RabbitMQ, it is pending until End or Drop is called
+func (a *Admission) Enqueue(code string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.load(code).pending++
+}
+
+// End records a pending order of the pair matched in the elapsed duration
+func (a *Admission) End(code string, elapsed time.Duration) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	l := a.load(code)
+	if l.pending > 0 {
+		l.pending--
+	}
+
+	if l.latency == 0 {
+		l.latency = elapsed
+		return
+	}
+
+	l.latency = time.Duration(latencyWeight*float64(elapsed) + (1-latencyWeight)*float64(l.latency))
+}
+
+// Drop records a pending order of the pair leaving the order queue without being matched
+func (a *Admission) Drop(code string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if l := a.loads[code]; l != nil && l.pending > 0 {
+		l.pending--
+	}
+}
+
+// Check returns the delay to apply before accepting a new order of the pair.
+// It returns false if the pair is overloaded, paused or its queue is full
+func (a *Admission) Check(code string) (time.Duration, bool) {
+	switch a.Status(code) {
+	case types.MarketStatusOverloaded, types.MarketStatusPaused:
+		return 0, false
+	case types.MarketStatusQueueing:
+		return 0, !a.IsQueueFull(code)
+	case types.MarketStatusThrottled:
+		return a.throttleDelay, true
+	default:
+		return 0, true
+	}
+}
+
+// Pause rejects the new orders of the pair until the reason is lifted by Resume
+func (a *Admission) Pause(code string, reason string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.paused[code] == nil {
+		a.paused[code] = make(map[string]bool)
+	}
+
+	a.paused[code][reason] = true
+}
+
+// PauseAll rejects the new orders of every pair, including the pairs created meanwhile, until the reason is
+// lifted by ResumeAll
+func (a *Admission) PauseAll(reason string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.pausedAll[reason] = true
+}
+
+// PauseQueued accepts up to limit order messages of the pair, new orders and cancels, the default limit if
+// zero, which are queued until it is resumed
+func (a *Admission) PauseQueued(code string, limit int) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if limit <= 0 {
+		limit = a.queueLimit
+	}
+
+	q := a.queues[code]
+	if q == nil {
+		q = &pairQueue{}
+		a.queues[code] = q
+	}
+
+	q.limit = limit
+}
+
+// Queue records an order message queued by a pair paused in queue mode
+func (a *Admission) Queue(code string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if q := a.queues[code]; q != nil {
+		q.queued++
+	}
+}
+
+// IsQueueFull returns true if a pair paused in queue mode queued its limit of order messages
+func (a *Admission) IsQueueFull(code string) bool {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	q := a.queues[code]
+	return q != nil && q.queued >= q.limit
+}
+
+// Resume lifts a pause reason of the pair, the operator reason also ends the queue mode. The pair accepts
+// the new orders again once it has no pause reason left. It returns false if the pair was not paused for
+// the reason
+func (a *Admission) Resume(code string, reason string) bool {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	lifted := a.paused[code][reason]
+	delete(a.paused[code], reason)
+	if len(a.paused[code]) == 0 {
+		delete(a.paused, code)
+	}
+
+	if reason == types.PauseReasonOperator && a.queues[code] != nil {
+		lifted = true
+		delete(a.queues, code)
+	}
+
+	return lifted
+}
+
+// ResumeAll lifts a pause reason of all the pairs
+func (a *Admission) ResumeAll(reason string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	delete(a.pausedAll, reason)
+}
+
+// PauseReasons returns the sorted pause reasons of the pair, the ones of all the pairs included
+func (a *Admission) PauseReasons(code string) []string {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	return a.reasons(code)
+}
+
+// Status returns the intake status of the pair
+func (a *Admission) Status(code string) string {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if len(a.paused[code]) > 0 || len(a.pausedAll) > 0 {
+		return types.MarketStatusPaused
+	}
+
+	if a.queues[code] != nil {
+		return types.MarketStatusQueueing
+	}
+
+	l := a.loads[code]
+	if l == nil {
+		return types.MarketStatusNormal
+	}
+
+	return a.status(l)
+}
+
+// MarketStatus returns the load of the pair
+func (a *Admission) MarketStatus(p *types.Pair) *types.MarketStatus {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	s := &types.MarketStatus{
+		PairName: p.Name(),
+		Code:     p.Code(),
+		Status:   types.MarketStatusNormal,
+	}
+
+	l := a.loads[s.Code]
+	if l != nil {
+		s.Status = a.status(l)
+		s.PendingOrders = l.pending
+		s.MatchLatency = int64(l.latency / time.Millisecond)
+	}
+
+	if q := a.queues[s.Code]; q != nil {
+		s.Status = types.MarketStatusQueueing
+		s.QueuedOrders = q.queued
+		s.QueueLimit = q.limit
+	}
+
+	if reasons := a.reasons(s.Code); len(reasons) > 0 {
+		s.Status = types.MarketStatusPaused
+		s.PauseReasons = reasons
+	}
+
+	return s
+}
+
+func (a *Admission) reasons(code string) []string {
+	reasons := []string{}
+	for r := range a.pausedAll {
+		reasons = append(reasons, r)
+	}
+
+	for r := range a.paused[code] {
+		if !a.pausedAll[r] {
+			reasons = append(reasons, r)
+		}
+	}
+
+	sort.Strings(reasons)
+	return reasons
+}
+
+func (a *Admission) load(code string) *pairLoad {
+	l := a.loads[code]
+	if l == nil {
+		l = &pairLoad{}
+		a.loads[code] = l
+	}
+
+	return l
+}
+
+// status only considers the latency while orders are in flight,
+// otherwise a pair shed because of a slow match would never recover
+func (a *Admission) status(l *pairLoad) string {
+	latency := time.Duration(0)
+	if l.pending > 0 {
+		latency = l.latency
+	}
+
+	if l.pending >= a.maxPending || latency >= a.maxLatency {
+		return types.MarketStatusOverloaded
+	}
+
+	if l.pending >= a.throttlePending || latency >= a.throttleLatency {
+		return types.MarketStatusThrottled
+	}
+
+	return types.MarketStatusNormal
+}
+
+func configInt(key string, def int) int {
+	if v, ok := app.Config.Admission[key]; ok && v > 0 {
+		return v
+	}
+
+	return def
+}
+
+func configDuration(key string, unit time.Duration, def time.Duration) time.Duration {
+	if v, ok := app.Config.Admission[key]; ok && v > 0 {
+		return time.Duration(v) * unit
+	}
+
+	return def
+}
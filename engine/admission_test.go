@@ -0,0 +1,131 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+func TestAdmissionPending(t *testing.T) {
+	a := NewAdmission(2, 3, time.Second, 2*time.Second, 10*time.Millisecond)
+
+	delay, ok := a.Check("pair")
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), delay)
+
+	a.Enqueue("pair")
+	a.Enqueue("pair")
+	delay, ok = a.Check("pair")
+	assert.True(t, ok)
+	assert.Equal(t, 10*time.Millisecond, delay)
+	assert.Equal(t, types.MarketStatusThrottled, a.Status("pair"))
+
+	a.Enqueue("pair")
+	_, ok = a.Check("pair")
+	assert.False(t, ok)
+	assert.Equal(t, types.MarketStatusNormal, a.Status("other"))
+
+	a.End("pair", time.Millisecond)
+	a.Drop("pair")
+	a.End("pair", time.Millisecond)
+	assert.Equal(t, types.MarketStatusNormal, a.Status("pair"))
+
+	// a drop never makes the depth negative
+	a.Drop("pair")
+	a.Enqueue("pair")
+	a.Enqueue("pair")
+	assert.Equal(t, types.MarketStatusThrottled, a.Status("pair"))
+}
+
+func TestAdmissionLatency(t *testing.T) {
+	a := NewAdmission(100, 200, 100*time.Millisecond, time.Second, 0)
+
+	a.Enqueue("pair")
+	a.End("pair", 3*time.Second)
+	assert.Equal(t, types.MarketStatusNormal, a.Status("pair"))
+
+	a.Enqueue("pair")
+	assert.Equal(t, types.MarketStatusOverloaded, a.Status("pair"))
+
+	a.End("pair", 0)
+	a.Enqueue("pair")
+	assert.Equal(t, types.MarketStatusOverloaded, a.Status("pair"))
+
+	for i := 0; i < 20; i++ {
+		a.End("pair", 0)
+		a.Enqueue("pair")
+	}
+	assert.Equal(t, types.MarketStatusNormal, a.Status("pair"))
+}
+
+func TestAdmissionPauseQueued(t *testing.T) {
+	a := NewAdmission(100, 200, time.Second, 2*time.Second, 0)
+
+	a.PauseQueued("pair", 2)
+	assert.Equal(t, types.MarketStatusQueueing, a.Status("pair"))
+
+	_, ok := a.Check("pair")
+	assert.True(t, ok)
+
+	a.Queue("pair")
+	a.Queue("pair")
+	_, ok = a.Check("pair")
+	assert.False(t, ok)
+	assert.True(t, a.IsQueueFull("pair"))
+
+	a.Pause("pair", types.PauseReasonOperator)
+	assert.Equal(t, types.MarketStatusPaused, a.Status("pair"))
+
+	a.Resume("pair", types.PauseReasonOperator)
+	assert.Equal(t, types.MarketStatusNormal, a.Status("pair"))
+	_, ok = a.Check("pair")
+	assert.True(t, ok)
+
+	a.PauseQueued("pair", 0)
+	assert.Equal(t, types.MarketStatusQueueing, a.Status("pair"))
+	assert.False(t, a.IsQueueFull("pair"))
+}
+
+func TestAdmissionPauseReasons(t *testing.T) {
+	a := NewAdmission(100, 200, time.Second, 2*time.Second, 0)
+	p := &types.Pair{}
+
+	a.Pause("pair", "killswitch:1")
+	a.Pause("pair", types.PauseReasonSettlement)
+	assert.Equal(t, []string{"killswitch:1", types.PauseReasonSettlement}, a.PauseReasons("pair"))
+
+	// a reason is only lifted by its own source
+	assert.False(t, a.Resume("pair", types.PauseReasonOperator))
+	assert.True(t, a.Resume("pair", types.PauseReasonSettlement))
+	assert.Equal(t, types.MarketStatusPaused, a.Status("pair"))
+	_, ok := a.Check("pair")
+	assert.False(t, ok)
+
+	a.Resume("pair", "killswitch:1")
+	assert.Equal(t, types.MarketStatusNormal, a.Status("pair"))
+	assert.Equal(t, []string{}, a.PauseReasons("pair"))
+
+	// the operator resume of a pair paused in queue mode keeps the other reasons
+	a.PauseQueued("pair", 2)
+	a.Pause("pair", "relayer:0x1")
+	assert.True(t, a.Resume("pair", types.PauseReasonOperator))
+	assert.Equal(t, types.MarketStatusPaused, a.Status("pair"))
+	a.Resume("pair", "relayer:0x1")
+	assert.Equal(t, types.MarketStatusNormal, a.Status("pair"))
+
+	// the reasons of all the pairs also pause the pairs unknown when they were set
+	a.PauseAll("killswitch:2")
+	a.Pause("other", "killswitch:2")
+	assert.Equal(t, types.MarketStatusPaused, a.Status("new"))
+	assert.Equal(t, []string{"killswitch:2"}, a.PauseReasons("other"))
+
+	s := a.MarketStatus(p)
+	assert.Equal(t, types.MarketStatusPaused, s.Status)
+	assert.Equal(t, []string{"killswitch:2"}, s.PauseReasons)
+
+	a.ResumeAll("killswitch:2")
+	assert.Equal(t, types.MarketStatusNormal, a.Status("new"))
+	assert.Equal(t, types.MarketStatusPaused, a.Status("other"))
+}
@@ -2,13 +2,19 @@ package engine
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/tomochain/tomox-sdk/errors"
 	"github.com/tomochain/tomox-sdk/ethereum"
 	"github.com/tomochain/tomox-sdk/interfaces"
 	"github.com/tomochain/tomox-sdk/rabbitmq"
 	"github.com/tomochain/tomox-sdk/types"
 	"github.com/tomochain/tomox-sdk/utils"
+	"github.com/tomochain/tomox-sdk/ws"
 )
 
 // Engine contains daos required for engine to work
@@ -19,6 +25,12 @@ type Engine struct {
 	tradeDao     interfaces.TradeDao
 	pairDao      interfaces.PairDao
 	provider     *ethereum.EthereumProvider
+	admission    *Admission
+	suspended    map[common.Address]string
+	mutex        sync.Mutex
+	held         map[string]bool
+	releasing    map[string]bool
+	heldMutex    sync.Mutex
 }
 
 var logger = utils.Logger
@@ -44,7 +56,18 @@ func NewEngine(
 		obs[p.Code()] = ob
 	}
 
-	engine := &Engine{obs, rabbitMQConn, orderDao, tradeDao, pairDao, provider}
+	engine := &Engine{
+		orderbooks:   obs,
+		rabbitMQConn: rabbitMQConn,
+		orderDao:     orderDao,
+		tradeDao:     tradeDao,
+		pairDao:      pairDao,
+		provider:     provider,
+		admission:    NewAdmissionFromConfig(),
+		suspended:    make(map[common.Address]string),
+		held:         make(map[string]bool),
+		releasing:    make(map[string]bool),
+	}
 	return engine
 }
 
@@ -53,6 +76,287 @@ func (e *Engine) Provider() interfaces.EthereumProvider {
 	return e.provider
 }
 
+// AdmitOrder returns the delay to apply before accepting a new order of the pair.
+// It returns false if the pair is overloaded and the order must be rejected
+func (e *Engine) AdmitOrder(code string) (time.Duration, bool) {
+	return e.admission.Check(code)
+}
+
+// PausePair rejects the new orders of the pair until ResumePair lifts the reason
+func (e *Engine) PausePair(code string, reason string) {
+	logger.Warningf("Order intake paused for %s: %s", code, reason)
+	e.admission.Pause(code, reason)
+}
+
+// PausePairQueued stops the matching of the pair until ResumePair lifts the operator reason. Up to limit order
+// messages, the pause_queue_limit of the admission config if zero, are accepted meanwhile and held in RabbitMQ
+// with the cancels of the pair, they are processed in their arrival order on resume
+func (e *Engine) PausePairQueued(code string, limit int) {
+	logger.Warningf("Order matching paused for %s, the new orders are queued", code)
+
+	e.heldMutex.Lock()
+	defer e.heldMutex.Unlock()
+
+	e.held[code] = true
+	e.admission.PauseQueued(code, limit)
+}
+
+// ResumePair lifts a pause reason of the pair, the new orders are accepted again once the pair has no pause
+// reason left. The orders queued while it was paused in queue mode are processed first in the background
+func (e *Engine) ResumePair(code string, reason string) {
+	if !e.admission.Resume(code, reason) {
+		return
+	}
+
+	if reasons := e.admission.PauseReasons(code); len(reasons) > 0 {
+		logger.Infof("Pause %s of %s lifted, still paused: %s", reason, code, strings.Join(reasons, ","))
+		return
+	}
+
+	logger.Infof("Order intake resumed for %s", code)
+	go e.releaseHeld(code)
+}
+
+// PauseAllPairs rejects the new orders of every pair, the pairs created meanwhile included, until
+// ResumeAllPairs lifts the reason
+func (e *Engine) PauseAllPairs(reason string) {
+	logger.Warningf("Order intake paused for all the pairs: %s", reason)
+	e.admission.PauseAll(reason)
+}
+
+// ResumeAllPairs lifts a pause reason of all the pairs
+func (e *Engine) ResumeAllPairs(reason string) {
+	logger.Infof("Pause %s of all the pairs lifted", reason)
+	e.admission.ResumeAll(reason)
+
+	e.heldMutex.Lock()
+	codes := []string{}
+	for code := range e.held {
+		codes = append(codes, code)
+	}
+	e.heldMutex.Unlock()
+
+	for _, code := range codes {
+		if len(e.admission.PauseReasons(code)) == 0 {
+			go e.releaseHeld(code)
+		}
+	}
+}
+
+// PauseReasons returns the reasons the pair is paused for
+func (e *Engine) PauseReasons(code string) []string {
+	return e.admission.PauseReasons(code)
+}
+
+// ReleaseHeldOrders processes in the background the order messages held in RabbitMQ before a restart, the
+// pairs are no longer paused once the SDK restarted. The new messages of a pair are held until its held
+// messages are processed
+func (e *Engine) ReleaseHeldOrders() error {
+	e.heldMutex.Lock()
+	codes := []string{}
+	for code := range e.orderbooks {
+		e.held[code] = true
+		codes = append(codes, code)
+	}
+	e.heldMutex.Unlock()
+
+	go func() {
+		for _, code := range codes {
+			e.releaseHeld(code)
+		}
+	}()
+
+	return nil
+}
+
+// IsPairPaused returns true if the order intake of the pair is paused
+func (e *Engine) IsPairPaused(code string) bool {
+	return e.admission.Status(code) == types.MarketStatusPaused
+}
+
+// IsPairQueueing returns true if the pair is paused in queue mode
+func (e *Engine) IsPairQueueing(code string) bool {
+	return e.admission.Status(code) == types.MarketStatusQueueing
+}
+
+// hold publishes the message to the held queue of its pair if the pair is paused in queue mode or its held
+// messages are being processed, it returns false if the message is to be processed now
+func (e *Engine) hold(msg *rabbitmq.Message) bool {
+	e.heldMutex.Lock()
+	defer e.heldMutex.Unlock()
+
+	if len(e.held) == 0 {
+		return false
+	}
+
+	o := &types.Order{}
+	err := json.Unmarshal(msg.Data, o)
+	if err != nil {
+		return false
+	}
+
+	code, err := o.PairCode()
+	if err != nil {
+		return false
+	}
+
+	if !e.held[code] {
+		return false
+	}
+
+	err = e.rabbitMQConn.PublishHeldOrder(code, msg)
+	if err != nil {
+		logger.Error("Order message of", code, "not held, processing it now:", err)
+		return false
+	}
+
+	if msg.Type == "NEW_ORDER" {
+		e.admission.Drop(code)
+	}
+
+	e.admission.Queue(code)
+	return true
+}
+
+// releaseHeld processes the messages held for the pair in their arrival order, the messages received
+// meanwhile are held behind them. A message is acked once processed so that the messages not processed
+// yet stay in RabbitMQ. It stops if the pair is paused again, the rest of the messages stay held until
+// the next resume
+func (e *Engine) releaseHeld(code string) {
+	e.heldMutex.Lock()
+	if !e.held[code] || e.releasing[code] {
+		e.heldMutex.Unlock()
+		return
+	}
+
+	e.releasing[code] = true
+	e.heldMutex.Unlock()
+
+	count := 0
+	for {
+		status := e.admission.Status(code)
+		if status == types.MarketStatusPaused || status == types.MarketStatusQueueing {
+			break
+		}
+
+		// the last read and the removal of the pair are done under the lock of hold, so that no message
+		// is held once the queue is found empty
+		e.heldMutex.Lock()
+		msg, ack, err := e.rabbitMQConn.NextHeldOrder(code)
+		if err != nil {
+			e.heldMutex.Unlock()
+			logger.Error("Held order messages of", code, "not processed, resume the pair again:", err)
+			break
+		}
+
+		if msg == nil {
+			delete(e.held, code)
+			e.heldMutex.Unlock()
+			break
+		}
+
+		e.heldMutex.Unlock()
+
+		e.AcceptOrder(msg)
+		e.process(msg)
+		ack()
+		count++
+	}
+
+	e.heldMutex.Lock()
+	delete(e.releasing, code)
+	e.heldMutex.Unlock()
+
+	if count > 0 {
+		logger.Infof("%d queued order messages of %s processed", count, code)
+	}
+}
+
+// SuspendRelayer stops the order intake of the pairs of a resigning or expired relayer and broadcasts
+// a warning on the system_status channel, once per status of the relayer
+func (e *Engine) SuspendRelayer(coinbase common.Address, status string) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.suspended[coinbase] == status {
+		return nil
+	}
+
+	pairs, err := e.pairDao.GetActivePairsByCoinbase(coinbase)
+	if err != nil {
+		return err
+	}
+
+	names := []string{}
+	for _, p := range pairs {
+		e.PausePair(p.Code(), types.RelayerPauseReason(coinbase))
+		names = append(names, p.Name())
+	}
+
+	e.suspended[coinbase] = status
+
+	msg := fmt.Sprintf("Relayer %s is %s, the orders of its pairs are rejected", coinbase.Hex(), status)
+	logger.Warning(msg, strings.Join(names, ","))
+	ws.GetSystemStatusSocket().BroadcastMessage(ws.SystemStatusChannel, &types.RelayerWarningEvent{
+		Relayer: coinbase,
+		Status:  status,
+		Pairs:   names,
+		Message: msg,
+	})
+
+	return nil
+}
+
+// ResumeRelayer lifts the pause of the pairs of a suspended relayer which is active again, the pairs paused
+// for another reason stay paused
+func (e *Engine) ResumeRelayer(coinbase common.Address, status string) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if _, ok := e.suspended[coinbase]; !ok {
+		return nil
+	}
+
+	pairs, err := e.pairDao.GetActivePairsByCoinbase(coinbase)
+	if err != nil {
+		return err
+	}
+
+	names := []string{}
+	for _, p := range pairs {
+		e.ResumePair(p.Code(), types.RelayerPauseReason(coinbase))
+		names = append(names, p.Name())
+	}
+
+	delete(e.suspended, coinbase)
+
+	msg := fmt.Sprintf("Relayer %s is %s, the orders of its pairs are accepted", coinbase.Hex(), status)
+	logger.Info(msg)
+	ws.GetSystemStatusSocket().BroadcastMessage(ws.SystemStatusChannel, &types.RelayerWarningEvent{
+		Relayer: coinbase,
+		Status:  status,
+		Pairs:   names,
+		Message: msg,
+	})
+
+	return nil
+}
+
+// GetMarketStatus returns the order intake status of every pair
+func (e *Engine) GetMarketStatus() ([]*types.MarketStatus, error) {
+	pairs, err := e.pairDao.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	res := []*types.MarketStatus{}
+	for i := range pairs {
+		res = append(res, e.admission.MarketStatus(&pairs[i]))
+	}
+
+	return res, nil
+}
+
 func (e *Engine) getObs() (map[string]*OrderBook, error) {
 	pairs, err := e.pairDao.GetAll()
 
@@ -68,9 +372,37 @@ func (e *Engine) getObs() (map[string]*OrderBook, error) {
 	return obs, nil
 }
 
+// AcceptOrder records a new order taken from the order queue, it is pending until matched
+func (e *Engine) AcceptOrder(msg *rabbitmq.Message) {
+	if msg.Type != "NEW_ORDER" {
+		return
+	}
+
+	o := &types.Order{}
+	err := json.Unmarshal(msg.Data, o)
+	if err != nil {
+		return
+	}
+
+	code, err := o.PairCode()
+	if err != nil {
+		return
+	}
+
+	e.admission.Enqueue(code)
+}
+
 // HandleOrders parses incoming rabbitmq order messages and redirects them to the appropriate
-// engine function
+// engine function. The messages of a pair paused in queue mode are held until it is resumed
 func (e *Engine) HandleOrders(msg *rabbitmq.Message) error {
+	if e.hold(msg) {
+		return nil
+	}
+
+	return e.process(msg)
+}
+
+func (e *Engine) process(msg *rabbitmq.Message) error {
 	switch msg.Type {
 	case "NEW_ORDER":
 		err := e.handleNewOrder(msg.Data)
@@ -104,6 +436,12 @@ func (e *Engine) handleNewOrder(bytes []byte) error {
 		logger.Error(err)
 		return err
 	}
+
+	start := time.Now()
+	defer func() {
+		e.admission.End(code, time.Since(start))
+	}()
+
 	obs, err := e.getObs()
 	if err != nil {
 		return errors.New("Orderbook error")
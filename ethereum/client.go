@@ -7,9 +7,11 @@ import (
 	"github.com/tomochain/tomox-sdk/errors"
 	"github.com/tomochain/tomox-sdk/utils/math"
 
+	ethereum "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
+	eth "github.com/ethereum/go-ethereum/core/types"
 )
 
 type SimulatedClient struct {
@@ -46,3 +48,19 @@ func NewSimulatedClientWithGasLimit(accs []common.Address, gasLimit uint64) *Sim
 func NewSimulatedClient(accs []common.Address) *SimulatedClient {
 	return NewSimulatedClientWithGasLimit(accs, 5e6)
 }
+
+func (b *SimulatedClient) HeaderByNumber(ctx context.Context, number *big.Int) (*eth.Header, error) {
+	return nil, errors.New("HeaderByNumber is not implemented on the simulated backend")
+}
+
+func (b *SimulatedClient) BlockByNumber(ctx context.Context, number *big.Int) (*eth.Block, error) {
+	return nil, errors.New("BlockByNumber is not implemented on the simulated backend")
+}
+
+func (b *SimulatedClient) TransactionCount(ctx context.Context, blockHash common.Hash) (uint, error) {
+	return 0, errors.New("TransactionCount is not implemented on the simulated backend")
+}
+
+func (b *SimulatedClient) SubscribeNewHead(ctx context.Context, ch chan<- *eth.Header) (ethereum.Subscription, error) {
+	return nil, errors.New("SubscribeNewHead is not implemented on the simulated backend")
+}
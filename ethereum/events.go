@@ -0,0 +1,164 @@
+package ethereum
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	eth "github.com/ethereum/go-ethereum/core/types"
+	"github.com/tomochain/tomox-sdk/contracts/contractsinterfaces"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// exchangeEvents are the events of the exchange contract decoded into chain events
+var exchangeEvents = map[string]string{
+	"LogTrade":       types.ChainEventTradeSettled,
+	"LogError":       types.ChainEventTradeFailed,
+	"LogCancelOrder": types.ChainEventOrderCancelled,
+}
+
+var exchangeAbi abi.ABI
+
+var tokenAbi abi.ABI
+
+var errMissingTopics = errors.New("Missing indexed topics in the log")
+
+func init() {
+	var err error
+	exchangeAbi, err = abi.JSON(strings.NewReader(contractsinterfaces.ExchangeABI))
+	if err != nil {
+		panic(err)
+	}
+
+	tokenAbi, err = abi.JSON(strings.NewReader(contractsinterfaces.TokenABI))
+	if err != nil {
+		panic(err)
+	}
+}
+
+// ExchangeEventTopics returns the topics of the events of the exchange contract decoded by
+// DecodeExchangeLog, to filter the logs of the contract
+func ExchangeEventTopics() []common.Hash {
+	topics := []common.Hash{}
+	for name := range exchangeEvents {
+		topics = append(topics, exchangeAbi.Events[name].Id())
+	}
+
+	return topics
+}
+
+// DecodeExchangeLog decodes a log of the exchange contract, nil is returned for the events
+// that are not followed
+func DecodeExchangeLog(l eth.Log) (*types.ChainEvent, error) {
+	if len(l.Topics) == 0 {
+		return nil, nil
+	}
+
+	for name, typ := range exchangeEvents {
+		if exchangeAbi.Events[name].Id() != l.Topics[0] {
+			continue
+		}
+
+		ev := &types.ChainEvent{
+			Type:        typ,
+			BlockNumber: l.BlockNumber,
+			TxHash:      l.TxHash,
+			LogIndex:    l.Index,
+		}
+
+		var err error
+		switch name {
+		case "LogTrade":
+			err = decodeLogTrade(l, ev)
+		case "LogError":
+			err = decodeLogError(l, ev)
+		case "LogCancelOrder":
+			err = decodeLogCancelOrder(l, ev)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		return ev, nil
+	}
+
+	return nil, nil
+}
+
+// decodeLogTrade reads the trade and its order from the data of the log, the maker and the taker
+// from its indexed topics
+func decodeLogTrade(l eth.Log, ev *types.ChainEvent) error {
+	if len(l.Topics) < 3 {
+		return errMissingTopics
+	}
+
+	out := &contractsinterfaces.ExchangeLogTrade{}
+	err := exchangeAbi.Unpack(out, "LogTrade", l.Data)
+	if err != nil {
+		return err
+	}
+
+	ev.TradeHash = common.Hash(out.TradeHash)
+	ev.OrderHash = common.Hash(out.OrderHash)
+	ev.Maker = common.BytesToAddress(l.Topics[1].Bytes())
+	ev.Taker = common.BytesToAddress(l.Topics[2].Bytes())
+	return nil
+}
+
+func decodeLogError(l eth.Log, ev *types.ChainEvent) error {
+	out := &contractsinterfaces.ExchangeLogError{}
+	err := exchangeAbi.Unpack(out, "LogError", l.Data)
+	if err != nil {
+		return err
+	}
+
+	ev.ErrorID = out.ErrorId
+	ev.MakerOrderHash = common.Hash(out.MakerOrderHash)
+	ev.TakerOrderHash = common.Hash(out.TakerOrderHash)
+	return nil
+}
+
+func decodeLogCancelOrder(l eth.Log, ev *types.ChainEvent) error {
+	out := &contractsinterfaces.ExchangeLogCancelOrder{}
+	err := exchangeAbi.Unpack(out, "LogCancelOrder", l.Data)
+	if err != nil {
+		return err
+	}
+
+	ev.OrderHash = common.Hash(out.OrderHash)
+	ev.UserAddress = out.UserAddress
+	ev.Amount = out.Amount
+	return nil
+}
+
+// DecodeTransferLogs returns the token transfers of the Transfer logs of a transaction, the other logs
+// are skipped
+func DecodeTransferLogs(logs []*eth.Log) ([]*types.TokenTransfer, error) {
+	transfers := []*types.TokenTransfer{}
+	for _, l := range logs {
+		if len(l.Topics) == 0 || l.Topics[0] != tokenAbi.Events["Transfer"].Id() {
+			continue
+		}
+
+		if len(l.Topics) < 3 {
+			return nil, errMissingTopics
+		}
+
+		out := &contractsinterfaces.TokenTransfer{}
+		err := tokenAbi.Unpack(out, "Transfer", l.Data)
+		if err != nil {
+			return nil, err
+		}
+
+		transfers = append(transfers, &types.TokenTransfer{
+			Token: l.Address,
+			From:  common.BytesToAddress(l.Topics[1].Bytes()),
+			To:    common.BytesToAddress(l.Topics[2].Bytes()),
+			Value: out.Value,
+		})
+	}
+
+	return transfers, nil
+}
@@ -0,0 +1,116 @@
+package ethereum
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	eth "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+func words(values ...[]byte) []byte {
+	data := []byte{}
+	for _, v := range values {
+		data = append(data, common.LeftPadBytes(v, 32)...)
+	}
+
+	return data
+}
+
+func TestDecodeExchangeLog(t *testing.T) {
+	maker := common.HexToAddress("0x1")
+	taker := common.HexToAddress("0x2")
+	orderHash := common.HexToHash("0xa")
+	tradeHash := common.HexToHash("0xb")
+
+	l := eth.Log{
+		Topics: []common.Hash{
+			exchangeAbi.Events["LogTrade"].Id(),
+			common.BytesToHash(maker.Bytes()),
+			common.BytesToHash(taker.Bytes()),
+			common.HexToHash("0xc"),
+		},
+		Data: words(
+			common.HexToAddress("0x3").Bytes(), common.HexToAddress("0x4").Bytes(),
+			big.NewInt(10).Bytes(), big.NewInt(20).Bytes(), big.NewInt(1).Bytes(), big.NewInt(2).Bytes(),
+			orderHash.Bytes(), tradeHash.Bytes(),
+		),
+		BlockNumber: 42,
+		Index:       3,
+	}
+
+	ev, err := DecodeExchangeLog(l)
+	assert.NoError(t, err)
+	assert.Equal(t, types.ChainEventTradeSettled, ev.Type)
+	assert.Equal(t, tradeHash, ev.TradeHash)
+	assert.Equal(t, orderHash, ev.OrderHash)
+	assert.Equal(t, maker, ev.Maker)
+	assert.Equal(t, taker, ev.Taker)
+	assert.Equal(t, uint64(42), ev.BlockNumber)
+	assert.Equal(t, uint(3), ev.LogIndex)
+
+	l = eth.Log{
+		Topics: []common.Hash{exchangeAbi.Events["LogCancelOrder"].Id()},
+		Data: words(
+			orderHash.Bytes(), maker.Bytes(), common.HexToAddress("0x3").Bytes(), common.HexToAddress("0x4").Bytes(),
+			big.NewInt(100).Bytes(), big.NewInt(5).Bytes(), big.NewInt(0).Bytes(),
+		),
+	}
+
+	ev, err = DecodeExchangeLog(l)
+	assert.NoError(t, err)
+	assert.Equal(t, types.ChainEventOrderCancelled, ev.Type)
+	assert.Equal(t, orderHash, ev.OrderHash)
+	assert.Equal(t, maker, ev.UserAddress)
+	assert.Equal(t, big.NewInt(100), ev.Amount)
+
+	l = eth.Log{
+		Topics: []common.Hash{exchangeAbi.Events["LogError"].Id()},
+		Data:   words([]byte{2}, orderHash.Bytes(), tradeHash.Bytes()),
+	}
+
+	ev, err = DecodeExchangeLog(l)
+	assert.NoError(t, err)
+	assert.Equal(t, types.ChainEventTradeFailed, ev.Type)
+	assert.Equal(t, uint8(2), ev.ErrorID)
+	assert.Equal(t, orderHash, ev.MakerOrderHash)
+	assert.Equal(t, tradeHash, ev.TakerOrderHash)
+
+	l = eth.Log{Topics: []common.Hash{exchangeAbi.Events["SetOwner"].Id()}}
+	ev, err = DecodeExchangeLog(l)
+	assert.NoError(t, err)
+	assert.Nil(t, ev)
+
+	l = eth.Log{Topics: []common.Hash{exchangeAbi.Events["LogTrade"].Id()}}
+	_, err = DecodeExchangeLog(l)
+	assert.Error(t, err)
+}
+
+func TestDecodeTransferLogs(t *testing.T) {
+	token := common.HexToAddress("0xa")
+	from := common.HexToAddress("0x1")
+	to := common.HexToAddress("0x2")
+
+	logs := []*eth.Log{
+		{
+			Address: token,
+			Topics: []common.Hash{
+				tokenAbi.Events["Transfer"].Id(),
+				common.BytesToHash(from.Bytes()),
+				common.BytesToHash(to.Bytes()),
+			},
+			Data: words(big.NewInt(100).Bytes()),
+		},
+		{Topics: []common.Hash{exchangeAbi.Events["LogTrade"].Id()}},
+		{},
+	}
+
+	transfers, err := DecodeTransferLogs(logs)
+	assert.NoError(t, err)
+	assert.Equal(t, []*types.TokenTransfer{{Token: token, From: from, To: to, Value: big.NewInt(100)}}, transfers)
+
+	_, err = DecodeTransferLogs([]*eth.Log{{Topics: []common.Hash{tokenAbi.Events["Transfer"].Id()}}})
+	assert.Error(t, err)
+}
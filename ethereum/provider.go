@@ -2,11 +2,15 @@ package ethereum
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"math/big"
 	"time"
 
+	ethereum "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	eth "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
@@ -19,6 +23,8 @@ import (
 type EthereumProvider struct {
 	Client interfaces.EthereumClient
 	Config interfaces.EthereumConfig
+	// RPC is the raw connection of the client, nil for the simulated client
+	RPC *rpc.Client
 }
 
 func NewEthereumProvider(c interfaces.EthereumClient) *EthereumProvider {
@@ -47,6 +53,7 @@ func NewDefaultEthereumProvider() *EthereumProvider {
 	return &EthereumProvider{
 		Client: client,
 		Config: config,
+		RPC:    conn,
 	}
 }
 
@@ -66,6 +73,7 @@ func NewWebsocketProvider() *EthereumProvider {
 	return &EthereumProvider{
 		Client: ethClient,
 		Config: config,
+		RPC:    conn,
 	}
 }
 
@@ -128,6 +136,21 @@ func (e *EthereumProvider) GetPendingNonceAt(a common.Address) (uint64, error) {
 	return nonce, nil
 }
 
+// GetNonceAt returns the number of transactions of an account mined in the latest block
+func (e *EthereumProvider) GetNonceAt(a common.Address) (uint64, error) {
+	return e.Client.NonceAt(context.Background(), a, nil)
+}
+
+// SuggestGasPrice returns the gas price suggested by the node
+func (e *EthereumProvider) SuggestGasPrice() (*big.Int, error) {
+	return e.Client.SuggestGasPrice(context.Background())
+}
+
+// SendTransaction broadcasts a signed transaction
+func (e *EthereumProvider) SendTransaction(tx *eth.Transaction) error {
+	return e.Client.SendTransaction(context.Background(), tx)
+}
+
 func (e *EthereumProvider) Decimals(token common.Address) (uint8, error) {
 	var tokenInterface *contractsinterfaces.ERC20
 	var err error
@@ -221,3 +244,108 @@ func (e *EthereumProvider) Balance(owner common.Address, token common.Address) (
 	}
 	return b, nil
 }
+
+// CodeAt returns the bytecode of a contract at the latest block
+func (e *EthereumProvider) CodeAt(contract common.Address) ([]byte, error) {
+	return e.Client.CodeAt(context.Background(), contract, nil)
+}
+
+// CallContract executes a call against the latest block without sending a transaction
+func (e *EthereumProvider) CallContract(msg ethereum.CallMsg) ([]byte, error) {
+	return e.Client.CallContract(context.Background(), msg, nil)
+}
+
+// EstimateGas returns the gas a call needs, an error is returned if it always fails
+func (e *EthereumProvider) EstimateGas(msg ethereum.CallMsg) (uint64, error) {
+	return e.Client.EstimateGas(context.Background(), msg)
+}
+
+// HeaderByNumber returns the block header at the given number, the latest header if number is nil
+func (e *EthereumProvider) HeaderByNumber(number *big.Int) (*eth.Header, error) {
+	header, err := e.Client.HeaderByNumber(context.Background(), number)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return header, nil
+}
+
+// BlockByNumber returns the block at the given number with its transactions, the latest block if number is nil
+func (e *EthereumProvider) BlockByNumber(number *big.Int) (*eth.Block, error) {
+	return e.Client.BlockByNumber(context.Background(), number)
+}
+
+// TransactionReceipt returns the receipt of a mined transaction
+func (e *EthereumProvider) TransactionReceipt(h common.Hash) (*eth.Receipt, error) {
+	return e.Client.TransactionReceipt(context.Background(), h)
+}
+
+// TransactionReceiptBlock returns the receipt of a mined transaction with the number of the block it was
+// included in, a nil receipt if the transaction is not mined. The receipts of the client do not carry their
+// block, the number is read from the raw receipt
+func (e *EthereumProvider) TransactionReceiptBlock(h common.Hash) (*eth.Receipt, uint64, error) {
+	if e.RPC == nil {
+		return nil, 0, errors.New("Raw RPC connection unavailable")
+	}
+
+	var raw json.RawMessage
+	err := e.RPC.CallContext(context.Background(), &raw, "eth_getTransactionReceipt", h)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, 0, nil
+	}
+
+	receipt := &eth.Receipt{}
+	err = json.Unmarshal(raw, receipt)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	block := struct {
+		BlockNumber *hexutil.Big `json:"blockNumber"`
+	}{}
+
+	err = json.Unmarshal(raw, &block)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// a receipt without block is pending
+	if block.BlockNumber == nil {
+		return nil, 0, nil
+	}
+
+	return receipt, block.BlockNumber.ToInt().Uint64(), nil
+}
+
+// TransactionCount returns the number of transactions in the given block
+func (e *EthereumProvider) TransactionCount(blockHash common.Hash) (uint, error) {
+	count, err := e.Client.TransactionCount(context.Background(), blockHash)
+	if err != nil {
+		logger.Error(err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// SubscribeNewHead subscribes to notifications about new block headers.
+// It requires the provider to be connected through websocket
+func (e *EthereumProvider) SubscribeNewHead(ch chan<- *eth.Header) (ethereum.Subscription, error) {
+	return e.Client.SubscribeNewHead(context.Background(), ch)
+}
+
+// FilterLogs returns the logs matching the query
+func (e *EthereumProvider) FilterLogs(q ethereum.FilterQuery) ([]eth.Log, error) {
+	return e.Client.FilterLogs(context.Background(), q)
+}
+
+// SubscribeFilterLogs subscribes to the logs matching the query.
+// It requires the provider to be connected through websocket
+func (e *EthereumProvider) SubscribeFilterLogs(q ethereum.FilterQuery, ch chan<- eth.Log) (ethereum.Subscription, error) {
+	return e.Client.SubscribeFilterLogs(context.Background(), q, ch)
+}
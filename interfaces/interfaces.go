@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	eth "github.com/ethereum/go-ethereum/core/types"
@@ -21,6 +22,8 @@ import (
 type OrderDao interface {
 	GetCollection() *mgo.Collection
 	Create(o *types.Order) error
+	AssignPublicID(o *types.Order) error
+	AssignPublicIDs() (int, error)
 	Watch() (*mgo.ChangeStream, *mgo.Session, error)
 	Update(id bson.ObjectId, o *types.Order) error
 	Upsert(id bson.ObjectId, o *types.Order) error
@@ -33,6 +36,8 @@ type OrderDao interface {
 	GetByID(id bson.ObjectId) (*types.Order, error)
 	GetByHash(h common.Hash) (*types.Order, error)
 	GetByHashes(hashes []common.Hash) ([]*types.Order, error)
+	GetByStatusAndPeriod(status string, from, to time.Time) ([]*types.Order, error)
+	GetAfterCheckpoint(cp *types.ExportCheckpoint, until time.Time, limit int) ([]*types.Order, error)
 	GetByUserAddress(addr, bt, qt common.Address, from, to int64, limit ...int) ([]*types.Order, error)
 	GetOpenOrdersByUserAddress(addr common.Address) ([]*types.Order, error)
 	GetCurrentByUserAddress(a common.Address, limit ...int) ([]*types.Order, error)
@@ -106,6 +111,8 @@ type ConfigDao interface {
 	ResetBlockCounters() error
 	GetBlockToProcess(chain types.Chain) (uint64, error)
 	SaveLastProcessedBlock(chain types.Chain, block uint64) error
+	GetWatcherBlock(watcher string) (uint64, error)
+	SaveWatcherBlock(watcher string, block uint64) error
 	Drop()
 }
 
@@ -121,6 +128,7 @@ type AssociationDao interface {
 
 type WalletDao interface {
 	Create(wallet *types.Wallet) error
+	Update(wallet *types.Wallet) error
 	GetAll() ([]types.Wallet, error)
 	GetByID(id bson.ObjectId) (*types.Wallet, error)
 	GetByAddress(addr common.Address) (*types.Wallet, error)
@@ -131,13 +139,16 @@ type WalletDao interface {
 type PairDao interface {
 	Create(o *types.Pair) error
 	GetAll() ([]types.Pair, error)
-	GetAllByCoinbase(addr common.Address) ([]types.Pair, error)
+	GetAllByCoinbase(addr common.Address, fields ...string) ([]types.Pair, error)
 	GetActivePairs() ([]*types.Pair, error)
 	GetActivePairsByCoinbase(addr common.Address) ([]*types.Pair, error)
 	GetByID(id bson.ObjectId) (*types.Pair, error)
 	GetByName(name string) (*types.Pair, error)
 	GetByTokenSymbols(baseTokenSymbol, quoteTokenSymbol string) (*types.Pair, error)
 	GetByTokenAddress(baseToken, quoteToken common.Address) (*types.Pair, error)
+	SetActive(baseToken, quoteToken, relayer common.Address, active bool) error
+	UpdateFeesByCoinbase(addr common.Address, prevMakeFee, prevTakeFee, makeFee, takeFee *big.Int) error
+	SetUnverifiedTokens(baseToken, quoteToken common.Address, unverified bool) error
 	DeleteByToken(baseAddress common.Address, quoteAddress common.Address) error
 	DeleteByTokenAndCoinbase(baseAddress common.Address, quoteAddress common.Address, addr common.Address) error
 }
@@ -145,16 +156,24 @@ type PairDao interface {
 type TradeDao interface {
 	GetCollection() *mgo.Collection
 	Create(o ...*types.Trade) error
+	AssignPublicID(t *types.Trade) error
+	AssignPublicIDs() (int, error)
 	Watch() (*mgo.ChangeStream, *mgo.Session, error)
 	Update(t *types.Trade) error
 	UpdateByHash(h common.Hash, t *types.Trade) error
 	GetAll() ([]types.Trade, error)
 	Aggregate(q []bson.M) ([]*types.Tick, error)
+	GetVolumeProfile(bt, qt common.Address, from time.Time, priceStep *big.Int) ([]*types.TradeVolumeLevel, error)
+	CountUniqueTraders(from time.Time) (int, error)
+	GetByPairAndPeriod(bt, qt common.Address, from, to time.Time) ([]*types.Trade, error)
+	GetAfterCheckpoint(cp *types.ExportCheckpoint, until time.Time, limit int) ([]*types.Trade, error)
+	GetByUserAndPeriod(a common.Address, from, to time.Time) ([]*types.Trade, error)
 	GetByPairName(name string) ([]*types.Trade, error)
 	GetByHash(h common.Hash) (*types.Trade, error)
 	GetByMakerOrderHash(h common.Hash) ([]*types.Trade, error)
 	GetByTakerOrderHash(h common.Hash) ([]*types.Trade, error)
 	GetByOrderHashes(hashes []common.Hash) ([]*types.Trade, error)
+	GetByMakerOrTakerOrderHashes(hashes []common.Hash) ([]*types.Trade, error)
 	GetSortedTrades(bt, qt common.Address, from, to int64, n int) ([]*types.Trade, error)
 	GetSortedTradesByUserAddress(a, bt, qt common.Address, from, to int64, limit ...int) ([]*types.Trade, error)
 	GetNTradesByPairAddress(bt, qt common.Address, n int) ([]*types.Trade, error)
@@ -181,6 +200,10 @@ type TokenDao interface {
 	GetByID(id bson.ObjectId) (*types.Token, error)
 	GetByAddress(addr common.Address) (*types.Token, error)
 	GetBySymbol(symbol string) (*types.Token, error)
+	GetAllBySymbol(symbol string) ([]types.Token, error)
+	SetVerified(addr common.Address, verified bool) error
+	SetSourceVerified(addr common.Address, verified bool) error
+	SetSafety(addr common.Address, safety *types.TokenSafety) error
 	GetQuoteTokens() ([]types.Token, error)
 	GetBaseTokens() ([]types.Token, error)
 	UpdateFiatPriceBySymbol(symbol string, price float64) error
@@ -207,12 +230,189 @@ type NotificationDao interface {
 	MarkAllRead(addr common.Address) error
 }
 
+// FinalityDao interface for finality records
+type FinalityDao interface {
+	Create(r *types.FinalityRecord) error
+	GetByHash(h common.Hash) (*types.FinalityRecord, error)
+	GetPending() ([]*types.FinalityRecord, error)
+	UpdateConfirmations(r *types.FinalityRecord) error
+	GetAboveBlock(block uint64) ([]*types.FinalityRecord, error)
+	UpdateBlock(r *types.FinalityRecord) error
+	DeleteByHash(h common.Hash) error
+	Drop()
+}
+
+// SubscriptionProfileDao interface for the websocket subscription profiles
+type SubscriptionProfileDao interface {
+	Save(p *types.SubscriptionProfile) error
+	GetByOwner(owner string) ([]*types.SubscriptionProfile, error)
+	GetByName(owner, name string) (*types.SubscriptionProfile, error)
+	Count(owner string) (int, error)
+	Delete(owner, name string) error
+}
+
+// SettlementDao interface for the settlements of the failed trades
+type SettlementDao interface {
+	Create(s *types.Settlement) error
+	GetByTradeHash(h common.Hash) (*types.Settlement, error)
+	GetByStatus(statuses ...string) ([]*types.Settlement, error)
+	GetDue(now time.Time) ([]*types.Settlement, error)
+	Update(s *types.Settlement) error
+	Drop()
+}
+
+// TokenMigrationDao interface for the reports of the token migrations
+type TokenMigrationDao interface {
+	Create(m *types.TokenMigration) error
+	GetByID(id bson.ObjectId) (*types.TokenMigration, error)
+	GetAll() ([]*types.TokenMigration, error)
+}
+
+// ListingReviewDao interface for the reviews of the new pairs
+type ListingReviewDao interface {
+	Create(l *types.ListingReview) error
+	GetByID(id bson.ObjectId) (*types.ListingReview, error)
+	GetByPair(bt, qt, relayer common.Address) (*types.ListingReview, error)
+	GetByStatus(statuses ...string) ([]*types.ListingReview, error)
+	Update(l *types.ListingReview) error
+	Drop()
+}
+
+// RiskLimitDao interface for the pre-trade risk limits of the accounts
+type RiskLimitDao interface {
+	GetAll() ([]*types.RiskLimit, error)
+	Upsert(l *types.RiskLimit) error
+	Delete(account, bt, qt common.Address) error
+	Drop()
+}
+
+// TokenAliasDao interface for the canonical tokens of the symbols shared by several tokens
+type TokenAliasDao interface {
+	GetAll() ([]*types.TokenAlias, error)
+	GetBySymbol(symbol string) (*types.TokenAlias, error)
+	Upsert(a *types.TokenAlias) error
+	Delete(symbol string) error
+	Drop()
+}
+
+// IncidentDao interface for the incidents of the status page
+type IncidentDao interface {
+	Create(i *types.Incident) error
+	GetByID(id bson.ObjectId) (*types.Incident, error)
+	GetActive() ([]*types.Incident, error)
+	GetResolvedSince(t time.Time) ([]*types.Incident, error)
+	GetAll(offset, limit int) ([]*types.Incident, error)
+	Update(i *types.Incident) error
+	Drop()
+}
+
+// CompositeIndexDao interface for the composite indices defined by the operators
+type CompositeIndexDao interface {
+	Create(i *types.CompositeIndex) error
+	GetByName(name string) (*types.CompositeIndex, error)
+	GetAll() ([]*types.CompositeIndex, error)
+	Update(i *types.CompositeIndex) error
+	DeleteByName(name string) error
+	Drop()
+}
+
+// KillSwitchDao interface for the activation records of the kill switch
+type KillSwitchDao interface {
+	Create(k *types.KillSwitch) error
+	GetByID(id bson.ObjectId) (*types.KillSwitch, error)
+	GetActive() ([]*types.KillSwitch, error)
+	GetAll(offset, limit int) ([]*types.KillSwitch, error)
+	Update(k *types.KillSwitch) error
+	Drop()
+}
+
+// IntegrityReportDao interface for the reports of the integrity job
+type IntegrityReportDao interface {
+	Create(r *types.IntegrityReport) error
+	GetAll(offset, limit int) ([]*types.IntegrityReport, error)
+	Drop()
+}
+
+// DailyStatsDao interface for the materialized daily aggregates of the trades
+type DailyStatsDao interface {
+	Save(a *types.DailyAggregates) error
+	GetUserVolumes(addr common.Address, from, to string) ([]*types.UserDailyVolume, error)
+	GetPairStats(bt, qt common.Address, from, to string) ([]*types.PairDailyStats, error)
+	GetFeeTotals(from, to string) ([]*types.FeeTotal, error)
+	Drop()
+}
+
+// BalanceSnapshotDao interface for the periodic snapshots of the account balances
+type BalanceSnapshotDao interface {
+	Create(s *types.BalanceSnapshot) error
+	GetLastBefore(addr common.Address, t time.Time) (*types.BalanceSnapshot, error)
+	GetFirstAfter(addr common.Address, t time.Time) (*types.BalanceSnapshot, error)
+	Drop()
+}
+
+// LendingRateSnapshotDao interface for the periodic snapshots of the lending utilization and borrow rates
+type LendingRateSnapshotDao interface {
+	Create(snapshots ...*types.LendingRateSnapshot) error
+	GetByTokenAndPeriod(token common.Address, from, to time.Time) ([]*types.LendingRateSnapshot, error)
+	Drop()
+}
+
+// DepthSnapshotDao interface for the hourly snapshots of the top levels of the order books
+type DepthSnapshotDao interface {
+	Create(snapshots ...*types.DepthSnapshot) error
+	GetByPairAndPeriod(baseToken, quoteToken common.Address, from, to time.Time, offset, limit int) ([]*types.DepthSnapshot, int, error)
+	RemoveBefore(before time.Time) error
+	Drop()
+}
+
+// AccessLogDao interface for the sampled access logs of the API
+type AccessLogDao interface {
+	Create(logs ...*types.AccessLog) error
+	GetStats(since time.Time, user string) ([]*types.AccessStats, error)
+	Anonymize(before time.Time) error
+	RemoveBefore(before time.Time) error
+	Drop()
+}
+
+// EngineEventDao interface for the journal of the engine events
+type EngineEventDao interface {
+	Create(e *types.EngineEvent) error
+	GetByOrderHash(h common.Hash, after int64, limit int) ([]*types.EngineEvent, error)
+	GetByPair(bt, qt common.Address, after int64, limit int) ([]*types.EngineEvent, error)
+	Drop()
+}
+
+// JobDao interface for the persisted states of the scheduled jobs
+type JobDao interface {
+	Upsert(j *types.JobState) error
+	GetByName(name string) (*types.JobState, error)
+	GetAll() ([]*types.JobState, error)
+}
+
+// AuditDao interface for the audit logs of the admin APIs
+type AuditDao interface {
+	Create(l *types.AuditLog) error
+	GetAll(offset, limit int) ([]*types.AuditLog, error)
+}
+
 type Engine interface {
 	HandleOrders(msg *rabbitmq.Message) error
 	// RecoverOrders(matches types.Matches) error
 	// CancelOrder(order *types.Order) (*types.EngineResponse, error)
 	// DeleteOrder(o *types.Order) error
 	Provider() EthereumProvider
+	AdmitOrder(code string) (time.Duration, bool)
+	PausePair(code string, reason string)
+	PausePairQueued(code string, limit int)
+	ResumePair(code string, reason string)
+	PauseAllPairs(reason string)
+	ResumeAllPairs(reason string)
+	PauseReasons(code string) []string
+	IsPairPaused(code string) bool
+	IsPairQueueing(code string) bool
+	SuspendRelayer(coinbase common.Address, status string) error
+	ResumeRelayer(coinbase common.Address, status string) error
+	GetMarketStatus() ([]*types.MarketStatus, error)
 }
 
 type WalletService interface {
@@ -229,7 +429,11 @@ type OHLCVService interface {
 	UnsubscribeChannel(c *ws.Client, p *types.SubscriptionPayload)
 	Subscribe(c *ws.Client, p *types.SubscriptionPayload)
 	GetOHLCV(p []types.PairAddresses, duration int64, unit string, timeInterval ...int64) ([]*types.Tick, error)
+	GetOHLCVIn(p []types.PairAddresses, duration int64, unit string, loc *time.Location, from, to int64) ([]*types.Tick, error)
+	TokenTicks(ticks []*types.Tick) ([]*types.TokenTick, error)
+	GetTokenPriceHistory(token common.Address, duration int64, unit string, from, to int64) (*types.TokenPriceHistory, error)
 	Get24hTick(baseToken, quoteToken common.Address) *types.Tick
+	RevertTrades(trades []*types.Trade)
 	GetFiatPriceChart() (map[string][]*types.FiatPriceItem, error)
 	GetLastPriceCurrentByTime(symbol string, createAt time.Time) (*big.Float, error)
 	GetAllTokenPairData() ([]*types.PairData, error)
@@ -256,9 +460,11 @@ type OrderService interface {
 	GetCurrentByUserAddress(a common.Address, limit ...int) ([]*types.Order, error)
 	GetHistoryByUserAddress(a, bt, qt common.Address, from, to int64, limit ...int) ([]*types.Order, error)
 	NewOrder(o *types.Order) error
+	ValidateOrder(o *types.Order) (*types.OrderDryRun, error)
 	CancelOrder(oc *types.OrderCancel) error
 	CancelAllOrder(a common.Address) error
 	HandleEngineResponse(res *types.EngineResponse) error
+	HandleChainEvent(ev *types.ChainEvent) error
 	GetOrders(orderSpec types.OrderSpec, sort []string, offset int, size int) (*types.OrderRes, error)
 	GetOrderNonceByUserAddress(addr common.Address) (interface{}, error)
 	GetBestBid(baseToken, quouteToken common.Address) (*types.PriceVolume, error)
@@ -286,7 +492,8 @@ type PairService interface {
 	GetAllTokenPairData() ([]*types.PairData, error)
 	GetAllTokenPairDataByCoinbase(addr common.Address) ([]*types.PairData, error)
 	GetAll() ([]types.Pair, error)
-	GetAllByCoinbase(addr common.Address) ([]types.Pair, error)
+	GetAllByCoinbase(addr common.Address, fields ...string) ([]types.Pair, error)
+	GetMarketStatus() ([]*types.MarketStatus, error)
 }
 
 type TokenService interface {
@@ -297,6 +504,12 @@ type TokenService interface {
 	GetAllByCoinbase(addr common.Address) ([]types.Token, error)
 	GetQuoteTokens() ([]types.Token, error)
 	GetBaseTokens() ([]types.Token, error)
+	GetAllBySymbol(symbol string) ([]types.Token, error)
+	ResolveSymbol(symbol string) (*types.Token, []types.Token, error)
+	SetVerified(addr common.Address, verified bool, identity string) (*types.Token, error)
+	GetAliases() ([]*types.TokenAlias, error)
+	SetAlias(symbol string, addr common.Address, identity string) (*types.TokenAlias, error)
+	DeleteAlias(symbol, identity string) error
 }
 
 type TradeService interface {
@@ -312,8 +525,13 @@ type TradeService interface {
 	Subscribe(c *ws.Client, bt, qt common.Address)
 	UnsubscribeChannel(c *ws.Client, bt, qt common.Address)
 	Unsubscribe(c *ws.Client)
+	SubscribeRawTrades(c *ws.Client, bt, qt common.Address)
+	UnsubscribeRawTradesChannel(c *ws.Client, bt, qt common.Address)
+	UnsubscribeRawTrades(c *ws.Client)
 	GetTrades(tradeSpec *types.TradeSpec, sortedBy []string, pageOffset int, pageSize int) (*types.TradeRes, error)
 	GetTradesUserHistory(a common.Address, tradeSpec *types.TradeSpec, sortedBy []string, pageOffset int, pageSize int) (*types.TradeRes, error)
+	GetVolumeProfile(bt, qt common.Address, window time.Duration, priceStep *big.Int) (*types.TradeVolumeProfile, error)
+	HandleChainEvent(ev *types.ChainEvent) error
 }
 
 type PriceBoardService interface {
@@ -322,7 +540,238 @@ type PriceBoardService interface {
 	Unsubscribe(c *ws.Client)
 }
 
+// DependencyService interface for the health of the MongoDB and RabbitMQ clients
+type DependencyService interface {
+	GetMetrics() *types.DependencyMetrics
+}
+
+// BlockService interface for chain head status
+type BlockService interface {
+	Subscribe(c *ws.Client)
+	Unsubscribe(c *ws.Client)
+	GetChainStatus() *types.ChainStatus
+	GetHealth() *types.Health
+	RegisterNotify(fn func(*types.BlockHeader))
+}
+
+// AuditService interface for the audit logs of the admin APIs
+type AuditService interface {
+	GetAll(offset, limit int) ([]*types.AuditLog, error)
+}
+
+// FinalityService interface for tracking the confirmations of settled records
+type FinalityService interface {
+	Track(action string, hash common.Hash, txHash common.Hash) error
+	TrackDeposit(d *types.DepositTransaction) error
+	GetByHash(hash common.Hash) (*types.FinalityRecord, error)
+	RegisterNotify(fn func(*types.FinalityRecord))
+}
+
+// SettlementService interface for the recovery of the failed trade settlements
+type SettlementService interface {
+	Track(t *types.Trade) error
+	GetByStatus(statuses ...string) ([]*types.Settlement, error)
+	Retry(hash common.Hash, identity string) (*types.Settlement, error)
+	Abandon(hash common.Hash, identity string) (*types.Settlement, error)
+	PausePair(bt, qt common.Address, reason string) error
+	PausePairQueued(bt, qt common.Address, limit int) error
+	ResumePair(bt, qt common.Address) error
+}
+
+// ListingService interface for the listing requirements of the new pairs
+type ListingService interface {
+	Hold(p *types.Pair) error
+	GetByStatus(statuses ...string) ([]*types.ListingReview, error)
+	Approve(id bson.ObjectId, holders int, commitment *big.Int, identity string) (*types.ListingReview, error)
+	Reject(id bson.ObjectId, reason string, identity string) (*types.ListingReview, error)
+}
+
+// TokenMigrationService interface for the migration of the listed tokens to new contracts
+type TokenMigrationService interface {
+	Migrate(oldToken, newToken common.Address, dryRun bool, identity string) (*types.TokenMigration, error)
+	GetByID(id bson.ObjectId) (*types.TokenMigration, error)
+	GetAll() ([]*types.TokenMigration, error)
+}
+
+// RiskService interface for the pre-trade risk checks of the new orders
+type RiskService interface {
+	Check(o *types.Order, p *types.Pair) error
+	Evaluate(o *types.Order, p *types.Pair) ([]string, error)
+	GetLimits(account *common.Address) ([]*types.RiskLimit, error)
+	SetLimit(l *types.RiskLimit, identity string) (*types.RiskLimit, error)
+	DeleteLimit(account, bt, qt common.Address, identity string) error
+}
+
+// IncidentService interface for the incidents of the status page
+type IncidentService interface {
+	GetStatus() (*types.SystemStatus, error)
+	GetAll(offset, limit int) ([]*types.Incident, error)
+	Create(p *types.IncidentPayload, identity string) (*types.Incident, error)
+	Update(id bson.ObjectId, p *types.IncidentPayload, identity string) (*types.Incident, error)
+	Subscribe(c *ws.Client)
+	Unsubscribe(c *ws.Client)
+}
+
+// KillSwitchService interface for the emergency halts of the order intake
+type KillSwitchService interface {
+	Activate(p *types.KillSwitchPayload, identity string) (*types.KillSwitch, error)
+	Release(id bson.ObjectId, identity string) (*types.KillSwitch, error)
+	VerifySigned(req *types.SignedKillSwitchRequest) (string, error)
+	GetAll(offset, limit int) ([]*types.KillSwitch, error)
+	Restore() error
+}
+
+// AttestationService interface for the signatures of the critical payloads
+type AttestationService interface {
+	Enabled() bool
+	Attest(kind string, payload interface{}) interface{}
+}
+
+// IntegrityService interface for the cross-collection integrity checks
+type IntegrityService interface {
+	Check(from, to time.Time) (*types.IntegrityReport, error)
+	CheckAll() error
+	GetAll(offset, limit int) ([]*types.IntegrityReport, error)
+}
+
+// CompositeIndexService interface for the composite indices and their values
+type CompositeIndexService interface {
+	GetAll() ([]*types.CompositeIndex, error)
+	GetByName(name string) (*types.CompositeIndex, error)
+	GetValue(name string) (*types.IndexValue, error)
+	Create(p *types.CompositeIndexPayload, identity string) (*types.CompositeIndex, error)
+	Update(name string, p *types.CompositeIndexPayload) (*types.CompositeIndex, error)
+	Delete(name string) error
+	Broadcast() error
+	Subscribe(c *ws.Client, name string)
+	Unsubscribe(c *ws.Client)
+	UnsubscribeChannel(c *ws.Client, name string)
+}
+
+// MicrostructureService interface for the order book and trade flow metrics of the pairs
+type MicrostructureService interface {
+	Get(bt, qt common.Address) (*types.Microstructure, error)
+	Broadcast() error
+	Subscribe(c *ws.Client, bt, qt common.Address)
+	Unsubscribe(c *ws.Client)
+	UnsubscribeChannel(c *ws.Client, bt, qt common.Address)
+}
+
+// CanaryService interface for the synthetic order canary
+type CanaryService interface {
+	Enabled() bool
+	Probe() error
+	GetStats(latest int) *types.CanaryStats
+}
+
+// SubscriptionProfileService interface for the websocket subscription profiles
+type SubscriptionProfileService interface {
+	Save(owner string, p *types.SubscriptionProfilePayload) error
+	GetAll(owner string) ([]*types.SubscriptionProfile, error)
+	Delete(owner, name string) error
+	Activate(owner, name string, c *ws.Client) (*types.SubscriptionProfile, error)
+}
+
+// ComplianceGate interface for the KYC checks of the trading actions
+type ComplianceGate interface {
+	CheckOrder(addr common.Address, pairName string) error
+	CheckLendingOrder(addr common.Address) error
+}
+
+// AccessLogService interface for the statistics and the retention of the access logs
+type AccessLogService interface {
+	Enabled() bool
+	GetStats(since time.Time) ([]*types.AccessStats, error)
+	GetUserStats(key string, since time.Time) ([]*types.AccessStats, error)
+	Purge() error
+}
+
+// StatsService interface for the network-wide statistics
+type StatsService interface {
+	GetPublicStats() (*types.PublicStats, error)
+}
+
+// ReportService interface for the reports served from the daily aggregates of the trades
+type ReportService interface {
+	Refresh(day time.Time) error
+	GetUserVolumes(addr common.Address, from, to time.Time) ([]*types.UserDailyVolume, error)
+	GetPairStats(bt, qt common.Address, from, to time.Time) ([]*types.PairDailyStats, error)
+	GetFeeTotals(from, to time.Time) ([]*types.FeeTotal, error)
+}
+
+// BalanceHistoryService interface for the balances of the accounts at a past time
+type BalanceHistoryService interface {
+	Snapshot() error
+	GetBalancesAt(addr common.Address, at time.Time) (*types.HistoricalBalances, error)
+}
+
+// GasOracleService interface for the gas prices suggested from the recent blocks
+type GasOracleService interface {
+	GetGasPrices() (*types.GasPrices, error)
+	GasPrice(speed string) (*big.Int, error)
+}
+
+// NonceManagerService interface for the nonces of the transactions sent by the accounts of the SDK
+type NonceManagerService interface {
+	Register(signer *relayer.Signer)
+	Send(from common.Address, to *common.Address, value *big.Int, gas uint64, data []byte) (*types.PendingTx, error)
+	GetPending() []*types.PendingTx
+	SpeedUp(hash common.Hash) (*types.PendingTx, error)
+	Cancel(hash common.Hash) (*types.PendingTx, error)
+}
+
+// FaucetService interface for the faucet of the test networks
+type FaucetService interface {
+	Enabled() bool
+	Request(addr common.Address) (*types.FaucetRequest, error)
+}
+
+// AccountBatchService interface for the batch account queries of the portfolio trackers
+type AccountBatchService interface {
+	MaxAddresses() int
+	GetBatch(addrs []common.Address) []*types.AccountSummary
+}
+
+// ExportService interface for the export streams of the trades and the orders
+type ExportService interface {
+	Pull(ctx context.Context, stream, checkpoint string, limit int, wait time.Duration) (*types.ExportPage, error)
+}
+
+// LendingRateService interface for the time series of the lending utilization and borrow rates
+type LendingRateService interface {
+	Snapshot() error
+	GetSeries(token common.Address, from, to time.Time, interval string, page, size int) (*types.LendingRateSeries, error)
+}
+
+// DepthSnapshotService interface for the history of the depth of the order books
+type DepthSnapshotService interface {
+	Snapshot() error
+	GetSnapshots(bt, qt common.Address, from, to time.Time, page, size int) (*types.DepthSnapshotPage, error)
+}
+
+// EngineJournalService interface for the journal of the engine events
+type EngineJournalService interface {
+	Record(res *types.EngineResponse) error
+	GetByOrderHash(h common.Hash, after int64, limit int) ([]*types.EngineEvent, error)
+	GetByPair(bt, qt common.Address, after int64, limit int) ([]*types.EngineEvent, error)
+}
+
+// JobScheduler interface for the registry of the scheduled jobs
+type JobScheduler interface {
+	GetJobs() []*types.JobState
+	GetJob(name string) (*types.JobState, error)
+	Trigger(name string) error
+	Pause(name string) error
+	Resume(name string) error
+}
+
+// DisputeService interface for the evidence reports of the customer support disputes
+type DisputeService interface {
+	GetReport(hash common.Hash) (*types.DisputeReport, error)
+}
+
 type MarketsService interface {
+	GetSnapshot(pairName string, duration int64, unit string) (*types.MarketSnapshot, error)
 	Subscribe(c *ws.Client)
 	UnsubscribeChannel(c *ws.Client)
 	Unsubscribe(c *ws.Client)
@@ -338,6 +787,41 @@ type NotificationService interface {
 	MarkRead(id bson.ObjectId) error
 	MarkUnRead(id bson.ObjectId) error
 	MarkAllRead(addr common.Address) error
+	GetPreference(addr common.Address) (*types.NotificationPreference, error)
+	SetPreference(p *types.NotificationPreference) error
+	Notify(n *types.Notification) error
+}
+
+// NotificationPreferenceDao interface for the notification channels of the users
+type NotificationPreferenceDao interface {
+	Save(p *types.NotificationPreference) error
+	GetByAddress(addr common.Address) (*types.NotificationPreference, error)
+}
+
+// LoanMaturityService interface for the maturities of the open loans
+type LoanMaturityService interface {
+	GetCalendar(addr common.Address, days int) ([]*types.MaturityDate, error)
+	SendReminders(from, to time.Time) error
+}
+
+// LendingPositionService interface for the open loans of the accounts
+type LendingPositionService interface {
+	GetPositions(addr common.Address) (*types.LendingPositions, error)
+}
+
+// InterestAccrualDao interface for the daily interest accruals of the lending trades
+type InterestAccrualDao interface {
+	Save(a *types.InterestAccrual) error
+	GetByInvestor(addr common.Address, from, to string) ([]*types.InterestAccrual, error)
+	GetByTradeHash(hash common.Hash) ([]*types.InterestAccrual, error)
+}
+
+// InterestAccrualService interface for the interest accrued by the lending trades
+type InterestAccrualService interface {
+	GetAccrued(hash common.Hash) (*types.InterestAccrual, error)
+	GetAccruals(hash common.Hash) ([]*types.InterestAccrual, error)
+	GetIncome(addr common.Address, from, to time.Time) (*types.InterestIncome, error)
+	Snapshot(day time.Time) error
 }
 
 type TxService interface {
@@ -382,20 +866,45 @@ type EthereumClient interface {
 	EstimateGas(ctx context.Context, call ethereum.CallMsg) (gas uint64, err error)
 	SendTransaction(ctx context.Context, tx *eth.Transaction) error
 	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
 	BalanceAt(ctx context.Context, contract common.Address, blockNumber *big.Int) (*big.Int, error)
 	FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]eth.Log, error)
 	SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- eth.Log) (ethereum.Subscription, error)
 	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*eth.Header, error)
+	BlockByNumber(ctx context.Context, number *big.Int) (*eth.Block, error)
+	TransactionCount(ctx context.Context, blockHash common.Hash) (uint, error)
+	SubscribeNewHead(ctx context.Context, ch chan<- *eth.Header) (ethereum.Subscription, error)
 }
 
 type EthereumProvider interface {
 	WaitMined(h common.Hash) (*eth.Receipt, error)
 	GetBalanceAt(a common.Address) (*big.Int, error)
 	GetPendingNonceAt(a common.Address) (uint64, error)
+	GetNonceAt(a common.Address) (uint64, error)
+	SuggestGasPrice() (*big.Int, error)
+	SendTransaction(tx *eth.Transaction) error
 	BalanceOf(owner common.Address, token common.Address) (*big.Int, error)
 	Decimals(token common.Address) (uint8, error)
 	Symbol(token common.Address) (string, error)
 	Balance(owner common.Address, token common.Address) (*big.Int, error)
+	CodeAt(contract common.Address) ([]byte, error)
+	CallContract(msg ethereum.CallMsg) ([]byte, error)
+	EstimateGas(msg ethereum.CallMsg) (uint64, error)
+	HeaderByNumber(number *big.Int) (*eth.Header, error)
+	BlockByNumber(number *big.Int) (*eth.Block, error)
+	TransactionCount(blockHash common.Hash) (uint, error)
+	TransactionReceipt(h common.Hash) (*eth.Receipt, error)
+	TransactionReceiptBlock(h common.Hash) (*eth.Receipt, uint64, error)
+	SubscribeNewHead(ch chan<- *eth.Header) (ethereum.Subscription, error)
+	FilterLogs(q ethereum.FilterQuery) ([]eth.Log, error)
+	SubscribeFilterLogs(q ethereum.FilterQuery, ch chan<- eth.Log) (ethereum.Subscription, error)
+}
+
+// TokenSafetyService interface for the heuristics detecting the suspicious tokens
+type TokenSafetyService interface {
+	Check(token common.Address) (*types.TokenSafety, error)
+	CheckAll() error
 }
 
 // RelayerService interface for relayer
@@ -405,15 +914,32 @@ type RelayerService interface {
 	UpdateNameByAddress(addr common.Address, name string, url string) error
 	GetRelayerAddress(r *http.Request) common.Address
 	GetByAddress(addr common.Address) (*types.Relayer, error)
+	CreatePair(p *types.Pair) error
 	GetAll() ([]types.Relayer, error)
+	InvalidateTokenInfo(tokens ...common.Address)
+	GetAggregated() (*relayer.AggregatedRInfo, error)
+	GetDeposit(coinbase common.Address) (*relayer.RelayerDeposit, error)
+	GetByOwner(owner common.Address) ([]*relayer.RInfo, error)
+	GetByID(id int64) (*relayer.RInfo, error)
+	GetInfo(coinbase common.Address) (*types.RelayerInfo, error)
+	GetStateAt(coinbase common.Address, block relayer.BlockRef) (*relayer.RelayerSnapshot, error)
 }
 
 // Relayer interface for relayer
 type Relayer interface {
 	GetRelayer(addr common.Address) (*relayer.RInfo, error)
+	GetRelayerAt(addr common.Address, block relayer.BlockRef) (*relayer.RInfo, error)
+	GetFullRelayerSnapshotAt(coinbase common.Address, block relayer.BlockRef) (*relayer.RelayerSnapshot, error)
+	GetRelayerDeposit(coinbase common.Address) (*relayer.RelayerDeposit, error)
+	GetRelayerByID(id int64) (*relayer.RInfo, error)
+	GetRelayersByOwner(owner common.Address) ([]*relayer.RInfo, error)
 	GetLending() (*relayer.LendingRInfo, error)
-	GetRelayers() ([]*relayer.RInfo, error)
+	GetRelayers(coinbases []common.Address) (*relayer.AggregatedRInfo, error)
 	GetLendings() ([]*relayer.LendingRInfo, error)
+	HealthCheck() *relayer.ChainHealth
+	InvalidateTokenInfo(tokens ...common.Address)
+	WatchRelayers(known map[common.Address]*relayer.RInfo, cursor relayer.LogCursor, handler func(*relayer.RInfoDiff)) error
+	CallContract(ctx context.Context, contract common.Address, contractAbi *abi.ABI, method string, out interface{}, args ...interface{}) error
 }
 
 // LendingOrderService for lending
@@ -434,6 +960,7 @@ type LendingOrderService interface {
 // LendingOrderDao dao
 type LendingOrderDao interface {
 	GetByHash(h common.Hash) (*types.LendingOrder, error)
+	GetOpenBySide(side string) ([]*types.LendingOrder, error)
 	Watch() (*mgo.ChangeStream, *mgo.Session, error)
 	GetLendingNonce(addr common.Address) (uint64, error)
 	AddNewLendingOrder(o *types.LendingOrder) error
@@ -466,6 +993,8 @@ type LendingTradeService interface {
 	GetLendingTrades(lendingtradeSpec *types.LendingTradeSpec, sortedBy []string, pageOffset int, pageSize int) (*types.LendingTradeRes, error)
 	RegisterNotify(fn func(*types.LendingTrade))
 	GetLendingTradeByTime(dateFrom, dateTo int64, pageOffset int, pageSize int) ([]*types.LendingTrade, error)
+	SubscribeLiquidations(c *ws.Client)
+	UnsubscribeLiquidations(c *ws.Client)
 }
 
 // LendingTradeDao interface for lending dao
@@ -476,6 +1005,11 @@ type LendingTradeDao interface {
 	GetLendingTradesUserHistory(a common.Address, lendingtradeSpec *types.LendingTradeSpec, sortedBy []string, pageOffset int, pageSize int) (*types.LendingTradeRes, error)
 	GetLendingTrades(lendingtradeSpec *types.LendingTradeSpec, sortedBy []string, pageOffset int, pageSize int) (*types.LendingTradeRes, error)
 	GetByHash(hash common.Hash) (*types.LendingTrade, error)
+	GetOpen() ([]*types.LendingTrade, error)
+	GetRecentLiquidated(n int) ([]*types.LendingTrade, error)
+	GetOpenByUser(addr common.Address) ([]*types.LendingTrade, error)
+	GetOpenMaturing(from, to int64) ([]*types.LendingTrade, error)
+	GetOpenMaturingByUser(addr common.Address, from, to int64) ([]*types.LendingTrade, error)
 }
 
 // LendingOhlcvService interface for lending service
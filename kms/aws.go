@@ -0,0 +1,165 @@
+package kms
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+const awsService = "kms"
+
+// AWSKeyManager wraps data keys with an AWS KMS customer master key
+type AWSKeyManager struct {
+	region          string
+	keyID           string
+	accessKeyID     string
+	secretAccessKey string
+	endpoint        string
+	http            *http.Client
+}
+
+// NewAWSKeyManager returns a new instance of AWSKeyManager
+func NewAWSKeyManager(region, keyID, accessKeyID, secretAccessKey string) (*AWSKeyManager, error) {
+	if region == "" || keyID == "" {
+		return nil, errors.New("kms: aws region and key id are required")
+	}
+
+	if accessKeyID == "" || secretAccessKey == "" {
+		return nil, errors.New("kms: aws credentials are required")
+	}
+
+	return &AWSKeyManager{
+		region:          region,
+		keyID:           keyID,
+		accessKeyID:     accessKeyID,
+		secretAccessKey: secretAccessKey,
+		endpoint:        fmt.Sprintf("https://kms.%s.amazonaws.com/", region),
+		http:            &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// KeyID returns the identifier of the customer master key
+func (m *AWSKeyManager) KeyID() string {
+	return "aws:" + m.keyID
+}
+
+// WrapKey encrypts the data key with the customer master key
+func (m *AWSKeyManager) WrapKey(dataKey []byte) ([]byte, error) {
+	res := struct {
+		CiphertextBlob []byte
+	}{}
+
+	err := m.call("Encrypt", map[string]interface{}{
+		"KeyId":     m.keyID,
+		"Plaintext": dataKey,
+	}, &res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.CiphertextBlob, nil
+}
+
+// UnwrapKey decrypts a data key wrapped with the customer master key
+func (m *AWSKeyManager) UnwrapKey(keyID string, wrapped []byte) ([]byte, error) {
+	if keyID != m.KeyID() {
+		return nil, ErrUnknownKey
+	}
+
+	res := struct {
+		Plaintext []byte
+	}{}
+
+	err := m.call("Decrypt", map[string]interface{}{
+		"KeyId":          m.keyID,
+		"CiphertextBlob": wrapped,
+	}, &res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Plaintext, nil
+}
+
+// call sends a request to the KMS JSON API signed with AWS signature version 4
+func (m *AWSKeyManager) call(action string, payload interface{}, out interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", m.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "TrentService."+action)
+	m.sign(req, body, time.Now().UTC())
+
+	resp, err := m.http.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("kms: aws %s returned %d: %s", action, resp.StatusCode, string(b))
+	}
+
+	return json.Unmarshal(b, out)
+}
+
+func (m *AWSKeyManager) sign(req *http.Request, body []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	signedHeaders := "content-type;host;x-amz-date;x-amz-target"
+	canonicalHeaders := "content-type:" + req.Header.Get("Content-Type") + "\n" +
+		"host:" + req.URL.Host + "\n" +
+		"x-amz-date:" + amzDate + "\n" +
+		"x-amz-target:" + req.Header.Get("X-Amz-Target") + "\n"
+
+	canonicalRequest := "POST\n/\n\n" + canonicalHeaders + "\n" + signedHeaders + "\n" + payloadHash
+
+	scope := date + "/" + m.region + "/" + awsService + "/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+m.secretAccessKey), date)
+	key = hmacSHA256(key, m.region)
+	key = hmacSHA256(key, awsService)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		m.accessKeyID, scope, signedHeaders, signature,
+	))
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -0,0 +1,61 @@
+package kms
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tomochain/tomox-sdk/vault"
+)
+
+// Backends supported by NewCipherFromConfig
+const (
+	BackendLocal = "local"
+	BackendVault = "vault"
+	BackendAWS   = "aws"
+)
+
+// NewCipherFromConfig builds a Cipher from the "kms" section of the configuration.
+// Local passphrases that were rotated out are listed in "previous_passphrases"
+// (comma separated) so that the values they protect can still be decrypted.
+// The passphrase is kept for decryption when moving to another backend
+func NewCipherFromConfig(cfg map[string]string) (*Cipher, error) {
+	passphrases := []string{}
+	if cfg["backend"] != BackendLocal && cfg["backend"] != "" && cfg["passphrase"] != "" {
+		passphrases = append(passphrases, cfg["passphrase"])
+	}
+
+	if cfg["previous_passphrases"] != "" {
+		passphrases = append(passphrases, strings.Split(cfg["previous_passphrases"], ",")...)
+	}
+
+	previous := []KeyManager{}
+	if len(passphrases) > 0 {
+		m, err := NewLocalKeyManager(cfg["salt"], passphrases...)
+		if err != nil {
+			return nil, err
+		}
+
+		previous = append(previous, m)
+	}
+
+	var current KeyManager
+	var err error
+
+	switch cfg["backend"] {
+	case BackendLocal, "":
+		current, err = NewLocalKeyManager(cfg["salt"], cfg["passphrase"])
+	case BackendVault:
+		client := vault.NewClient(cfg["vault_addr"], cfg["vault_token"])
+		current, err = NewVaultKeyManager(client, cfg["vault_key"])
+	case BackendAWS:
+		current, err = NewAWSKeyManager(cfg["aws_region"], cfg["aws_key_id"], cfg["aws_access_key_id"], cfg["aws_secret_access_key"])
+	default:
+		err = fmt.Errorf("kms: unknown backend %s", cfg["backend"])
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewCipher(current, previous...), nil
+}
@@ -0,0 +1,226 @@
+// Package kms implements envelope encryption of the secrets stored by the SDK.
+//
+// Every value is encrypted with a random data key using AES-GCM. The data key
+// is wrapped by a KeyManager (local passphrase, AWS KMS or Vault transit) and
+// stored next to the ciphertext, so that rotating the master key only requires
+// rewrapping the data keys.
+package kms
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+)
+
+// EncryptedPrefix marks the values encrypted by a Cipher
+const EncryptedPrefix = "enc:v1:"
+
+const dataKeySize = 32
+
+var (
+	// ErrUnknownKey is returned by a KeyManager that does not own the key used to wrap a data key
+	ErrUnknownKey = errors.New("kms: unknown key")
+	// ErrNotConfigured is returned when decrypting a value while no cipher is configured
+	ErrNotConfigured = errors.New("kms: encrypted value found but no key manager is configured")
+)
+
+// KeyManager wraps and unwraps data keys with a master key
+type KeyManager interface {
+	// KeyID identifies the master key currently used to wrap data keys
+	KeyID() string
+	WrapKey(dataKey []byte) ([]byte, error)
+	// UnwrapKey returns ErrUnknownKey if keyID is not managed by this KeyManager
+	UnwrapKey(keyID string, wrapped []byte) ([]byte, error)
+}
+
+type envelope struct {
+	KeyID      string `json:"k"`
+	DataKey    []byte `json:"dk"`
+	Nonce      []byte `json:"n"`
+	Ciphertext []byte `json:"c"`
+}
+
+// Cipher encrypts values with the current key manager. Previous key managers
+// are only used to decrypt values that were not rotated yet
+type Cipher struct {
+	current  KeyManager
+	previous []KeyManager
+}
+
+// NewCipher returns a new instance of Cipher
+func NewCipher(current KeyManager, previous ...KeyManager) *Cipher {
+	return &Cipher{
+		current:  current,
+		previous: previous,
+	}
+}
+
+// IsEncrypted returns true if the value was produced by a Cipher
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, EncryptedPrefix)
+}
+
+// Encrypt seals the plaintext with a new data key
+func (c *Cipher) Encrypt(plaintext []byte) (string, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return "", err
+	}
+
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	wrapped, err := c.current.WrapKey(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	e := envelope{
+		KeyID:      c.current.KeyID(),
+		DataKey:    wrapped,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, plaintext, nil),
+	}
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+
+	return EncryptedPrefix + base64.StdEncoding.EncodeToString(b), nil
+}
+
+// Decrypt opens a value produced by Encrypt
+func (c *Cipher) Decrypt(value string) ([]byte, error) {
+	e, err := decodeEnvelope(value)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey, err := c.unwrapKey(e.KeyID, e.DataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return gcm.Open(nil, e.Nonce, e.Ciphertext, nil)
+}
+
+// Rewrap encrypts the value again with the current master key
+func (c *Cipher) Rewrap(value string) (string, error) {
+	plaintext, err := c.Decrypt(value)
+	if err != nil {
+		return "", err
+	}
+
+	return c.Encrypt(plaintext)
+}
+
+func (c *Cipher) unwrapKey(keyID string, wrapped []byte) ([]byte, error) {
+	managers := append([]KeyManager{c.current}, c.previous...)
+	for _, m := range managers {
+		dataKey, err := m.UnwrapKey(keyID, wrapped)
+		if err == ErrUnknownKey {
+			continue
+		}
+
+		return dataKey, err
+	}
+
+	return nil, ErrUnknownKey
+}
+
+func decodeEnvelope(value string) (*envelope, error) {
+	if !IsEncrypted(value) {
+		return nil, errors.New("kms: value is not encrypted")
+	}
+
+	b, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, EncryptedPrefix))
+	if err != nil {
+		return nil, err
+	}
+
+	e := &envelope{}
+	err = json.Unmarshal(b, e)
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+var (
+	defaultCipher *Cipher
+	mutex         sync.RWMutex
+)
+
+// SetDefault sets the cipher used by EncryptString and DecryptString
+func SetDefault(c *Cipher) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	defaultCipher = c
+}
+
+// Default returns the configured cipher, nil if encryption is disabled
+func Default() *Cipher {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return defaultCipher
+}
+
+// EncryptString encrypts the value with the default cipher.
+// The value is returned unchanged if encryption is disabled
+func EncryptString(value string) (string, error) {
+	c := Default()
+	if c == nil {
+		return value, nil
+	}
+
+	return c.Encrypt([]byte(value))
+}
+
+// DecryptString decrypts a value encrypted with the default cipher.
+// Plaintext values are returned unchanged so that existing records keep working
+func DecryptString(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	c := Default()
+	if c == nil {
+		return "", ErrNotConfigured
+	}
+
+	b, err := c.Decrypt(value)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
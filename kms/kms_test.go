@@ -0,0 +1,62 @@
+package kms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCipherEncryptDecrypt(t *testing.T) {
+	m, err := NewLocalKeyManager("", "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewCipher(m)
+	enc, err := c.Encrypt([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.True(t, IsEncrypted(enc))
+
+	dec, err := c.Decrypt(enc)
+	assert.Nil(t, err)
+	assert.Equal(t, "secret", string(dec))
+}
+
+func TestCipherRewrap(t *testing.T) {
+	old, _ := NewLocalKeyManager("", "old")
+	enc, err := NewCipher(old).Encrypt([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	current, _ := NewLocalKeyManager("", "new")
+	_, err = NewCipher(current).Decrypt(enc)
+	assert.Equal(t, ErrUnknownKey, err)
+
+	c := NewCipher(current, old)
+	rewrapped, err := c.Rewrap(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dec, err := NewCipher(current).Decrypt(rewrapped)
+	assert.Nil(t, err)
+	assert.Equal(t, "secret", string(dec))
+}
+
+func TestDecryptStringPlaintext(t *testing.T) {
+	SetDefault(nil)
+
+	v, err := DecryptString("plain")
+	assert.Nil(t, err)
+	assert.Equal(t, "plain", v)
+
+	m, _ := NewLocalKeyManager("", "passphrase")
+	enc, _ := NewCipher(m).Encrypt([]byte("secret"))
+
+	_, err = DecryptString(enc)
+	assert.Equal(t, ErrNotConfigured, err)
+}
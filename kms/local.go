@@ -0,0 +1,93 @@
+package kms
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const defaultLocalSalt = "tomox-sdk-kms"
+
+// LocalKeyManager wraps data keys with master keys derived from passphrases.
+// The first passphrase is used for wrapping, the others allow reading
+// data keys wrapped before a rotation
+type LocalKeyManager struct {
+	keyID string
+	keys  map[string][]byte
+}
+
+// NewLocalKeyManager derives the master keys from the passphrases
+func NewLocalKeyManager(salt string, passphrases ...string) (*LocalKeyManager, error) {
+	if len(passphrases) == 0 || passphrases[0] == "" {
+		return nil, errors.New("kms: passphrase is required")
+	}
+
+	if salt == "" {
+		salt = defaultLocalSalt
+	}
+
+	m := &LocalKeyManager{keys: make(map[string][]byte)}
+	for i, p := range passphrases {
+		if p == "" {
+			continue
+		}
+
+		key, err := scrypt.Key([]byte(p), []byte(salt), 1<<15, 8, 1, dataKeySize)
+		if err != nil {
+			return nil, err
+		}
+
+		sum := sha256.Sum256(key)
+		id := "local:" + hex.EncodeToString(sum[:4])
+		m.keys[id] = key
+		if i == 0 {
+			m.keyID = id
+		}
+	}
+
+	return m, nil
+}
+
+// KeyID returns the identifier of the current passphrase
+func (m *LocalKeyManager) KeyID() string {
+	return m.keyID
+}
+
+// WrapKey encrypts the data key with the current passphrase
+func (m *LocalKeyManager) WrapKey(dataKey []byte) ([]byte, error) {
+	gcm, err := newGCM(m.keys[m.keyID])
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, dataKey, nil), nil
+}
+
+// UnwrapKey decrypts a data key wrapped with one of the passphrases
+func (m *LocalKeyManager) UnwrapKey(keyID string, wrapped []byte) ([]byte, error) {
+	key, ok := m.keys[keyID]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(wrapped) < gcm.NonceSize() {
+		return nil, errors.New("kms: invalid wrapped key")
+	}
+
+	nonce := wrapped[:gcm.NonceSize()]
+	return gcm.Open(nil, nonce, wrapped[gcm.NonceSize():], nil)
+}
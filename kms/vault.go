@@ -0,0 +1,67 @@
+package kms
+
+import (
+	"encoding/base64"
+	"errors"
+
+	"github.com/tomochain/tomox-sdk/vault"
+)
+
+// VaultKeyManager wraps data keys with the Vault transit secrets engine.
+// Rotating the transit key in Vault is picked up by Cipher.Rewrap
+type VaultKeyManager struct {
+	client  *vault.Client
+	keyName string
+}
+
+// NewVaultKeyManager returns a new instance of VaultKeyManager
+func NewVaultKeyManager(client *vault.Client, keyName string) (*VaultKeyManager, error) {
+	if keyName == "" {
+		return nil, errors.New("kms: vault transit key name is required")
+	}
+
+	return &VaultKeyManager{client, keyName}, nil
+}
+
+// KeyID returns the identifier of the transit key
+func (m *VaultKeyManager) KeyID() string {
+	return "vault:" + m.keyName
+}
+
+// WrapKey encrypts the data key with the transit key
+func (m *VaultKeyManager) WrapKey(dataKey []byte) ([]byte, error) {
+	res, err := m.client.Write("transit/encrypt/"+m.keyName, map[string]string{
+		"plaintext": base64.StdEncoding.EncodeToString(dataKey),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext, ok := res.Data["ciphertext"].(string)
+	if !ok {
+		return nil, errors.New("kms: vault returned no ciphertext")
+	}
+
+	return []byte(ciphertext), nil
+}
+
+// UnwrapKey decrypts a data key wrapped with the transit key
+func (m *VaultKeyManager) UnwrapKey(keyID string, wrapped []byte) ([]byte, error) {
+	if keyID != m.KeyID() {
+		return nil, ErrUnknownKey
+	}
+
+	res, err := m.client.Write("transit/decrypt/"+m.keyName, map[string]string{
+		"ciphertext": string(wrapped),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, ok := res.Data["plaintext"].(string)
+	if !ok {
+		return nil, errors.New("kms: vault returned no plaintext")
+	}
+
+	return base64.StdEncoding.DecodeString(plaintext)
+}
@@ -0,0 +1,284 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"mime"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+	"github.com/tomochain/tomox-sdk/utils/math"
+)
+
+// amountFields are the fields holding an amount in the smallest unit of a token, with the side of the
+// pair of the token: the amounts are in base token units and the prices in quote token units
+var amountFields = map[string]string{
+	"amount":       "base",
+	"filledAmount": "base",
+	"volume":       "base",
+	"pricepoint":   "quote",
+	"price":        "quote",
+	"quoteVolume":  "quote",
+}
+
+var integerRegexp = regexp.MustCompile(`^-?[0-9]+$`)
+
+// amountContext are the decimals of the tokens of the pair of an object of a response
+type amountContext struct {
+	base, quote       int
+	hasBase, hasQuote bool
+}
+
+// AmountFormatter serializes the amounts and the prices of the JSON responses in the format asked for
+// by the caller: wei, the integers in the smallest unit of the token as strings, decimal, the values in
+// token units as decimal strings, or float, the values in token units as numbers. The unit of a field
+// is found from the baseToken and quoteToken fields of its object or of a parent object, else from the
+// params of the request. The fields without a known token are left as they are, as the same names hold
+// values already in token units in other responses
+type AmountFormatter struct {
+	tokenDao interfaces.TokenDao
+	mutex    sync.Mutex
+	decimals map[common.Address]int
+}
+
+// NewAmountFormatter returns a new instance of AmountFormatter, the decimals of the tokens are read once
+func NewAmountFormatter(tokenDao interfaces.TokenDao) *AmountFormatter {
+	return &AmountFormatter{
+		tokenDao: tokenDao,
+		decimals: make(map[common.Address]int),
+	}
+}
+
+// NegotiateAmountFormat returns the amount format of a request, from the amountFormat param, the
+// "X-Amount-Format" header or the amounts parameter of the media type of the Accept header
+// (application/json; amounts=decimal), else the amount_format setting. The empty format keeps the
+// responses as they are serialized by the endpoints
+func NegotiateAmountFormat(r *http.Request) (string, error) {
+	format := r.URL.Query().Get("amountFormat")
+	if format == "" {
+		format = r.Header.Get("X-Amount-Format")
+	}
+
+	if format == "" {
+		for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+			_, params, err := mime.ParseMediaType(strings.TrimSpace(accept))
+			if err == nil && params["amounts"] != "" {
+				format = params["amounts"]
+				break
+			}
+		}
+	}
+
+	if format == "" {
+		format = app.Config.AmountFormat
+	}
+
+	format = strings.ToLower(format)
+	if format != "" && !types.IsAmountFormat(format) {
+		return "", fmt.Errorf("Unsupported amount format %s, use one of %s", format, strings.Join(types.AmountFormats, ", "))
+	}
+
+	return format, nil
+}
+
+// Format rewrites the amounts of the JSON responses in the negotiated format, the format is returned in
+// the amounts parameter of the media type of the response. A request for an unsupported format is
+// answered 406. The websocket upgrades are left out
+func (f *AmountFormatter) Format(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		format, err := NegotiateAmountFormat(r)
+		if err != nil {
+			httputils.WriteError(w, http.StatusNotAcceptable, err.Error())
+			return
+		}
+
+		w.Header().Add("Vary", "Accept, X-Amount-Format")
+		if format == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &recorder{header: w.Header(), status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		body := rec.body.Bytes()
+		if strings.HasPrefix(rec.header.Get("Content-Type"), "application/json") {
+			formatted, err := f.formatBody(body, format, r)
+			if err == nil {
+				body = formatted
+				w.Header().Set("Content-Type", "application/json; amounts="+format)
+			}
+		}
+
+		w.WriteHeader(rec.status)
+		w.Write(body)
+	})
+}
+
+// formatBody rewrites the amounts of a JSON body, the pair of the params of the request is the
+// context of the top level objects
+func (f *AmountFormatter) formatBody(body []byte, format string, r *http.Request) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var v interface{}
+	err := decoder.Decode(&v)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := amountContext{}
+	q := r.URL.Query()
+	ctx.base, ctx.hasBase = f.tokenDecimals(q.Get("baseToken"))
+	ctx.quote, ctx.hasQuote = f.tokenDecimals(q.Get("quoteToken"))
+
+	return json.Marshal(f.formatValue(v, format, ctx))
+}
+
+func (f *AmountFormatter) formatValue(v interface{}, format string, ctx amountContext) interface{} {
+	switch value := v.(type) {
+	case []interface{}:
+		for i := range value {
+			value[i] = f.formatValue(value[i], format, ctx)
+		}
+
+		return value
+	case map[string]interface{}:
+		ctx = f.objectContext(value, ctx)
+		for k, field := range value {
+			side, ok := amountFields[k]
+			if !ok {
+				value[k] = f.formatValue(field, format, ctx)
+				continue
+			}
+
+			decimals, known := ctx.base, ctx.hasBase
+			if side == "quote" {
+				decimals, known = ctx.quote, ctx.hasQuote
+			}
+
+			value[k] = FormatAmount(field, format, decimals, known)
+		}
+
+		return value
+	default:
+		return v
+	}
+}
+
+// objectContext returns the decimals of the pair of an object, the context of its parent by default
+func (f *AmountFormatter) objectContext(o map[string]interface{}, ctx amountContext) amountContext {
+	for _, k := range []string{"baseToken", "baseTokenAddress"} {
+		if token, ok := o[k].(string); ok {
+			ctx.base, ctx.hasBase = f.tokenDecimals(token)
+			break
+		}
+	}
+
+	for _, k := range []string{"quoteToken", "quoteTokenAddress"} {
+		if token, ok := o[k].(string); ok {
+			ctx.quote, ctx.hasQuote = f.tokenDecimals(token)
+			break
+		}
+	}
+
+	if d, ok := o["baseTokenDecimals"].(json.Number); ok {
+		if n, err := d.Int64(); err == nil {
+			ctx.base, ctx.hasBase = int(n), true
+		}
+	}
+
+	if d, ok := o["quoteTokenDecimals"].(json.Number); ok {
+		if n, err := d.Int64(); err == nil {
+			ctx.quote, ctx.hasQuote = int(n), true
+		}
+	}
+
+	return ctx
+}
+
+// tokenDecimals returns the decimals of a listed token, false for an unknown token
+func (f *AmountFormatter) tokenDecimals(token string) (int, bool) {
+	if !common.IsHexAddress(token) {
+		return 0, false
+	}
+
+	addr := common.HexToAddress(token)
+	if utils.IsNativeTokenByAddress(addr) {
+		return types.GetNativeCurrency().Decimals, true
+	}
+
+	f.mutex.Lock()
+	d, ok := f.decimals[addr]
+	f.mutex.Unlock()
+	if ok {
+		return d, true
+	}
+
+	if f.tokenDao == nil {
+		return 0, false
+	}
+
+	t, err := f.tokenDao.GetByAddress(addr)
+	if err != nil || t == nil {
+		return 0, false
+	}
+
+	f.mutex.Lock()
+	f.decimals[addr] = t.Decimals
+	f.mutex.Unlock()
+	return t.Decimals, true
+}
+
+// FormatAmount serializes an integer amount, a JSON number or string, in the format. The values which
+// are not integers and the values of an unknown token are returned as they are
+func FormatAmount(v interface{}, format string, decimals int, known bool) interface{} {
+	if !known {
+		return v
+	}
+
+	var s string
+	switch value := v.(type) {
+	case json.Number:
+		s = value.String()
+	case string:
+		s = value
+	default:
+		return v
+	}
+
+	if !integerRegexp.MatchString(s) {
+		return v
+	}
+
+	amount, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return v
+	}
+
+	if format == types.AmountFormatWei {
+		return amount.String()
+	}
+
+	if format == types.AmountFormatFloat {
+		f, _ := math.ToUnits(amount, decimals).Float64()
+		return f
+	}
+
+	return math.FormatUnits(amount, decimals)
+}
@@ -0,0 +1,65 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+)
+
+func TestAmountFormatter(t *testing.T) {
+	f := NewAmountFormatter(nil)
+	handler := f.Format(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		httputils.WriteJSON(w, http.StatusOK, []map[string]interface{}{
+			{
+				"baseToken":  "0x0000000000000000000000000000000000000001",
+				"quoteToken": "0x0000000000000000000000000000000000000001",
+				"amount":     "1500000000000000000",
+				"pricepoint": 2000000000000000000,
+			},
+			{"pair": "unknown", "amount": 12, "price": 1.5},
+		})
+	}))
+
+	get := func(query string, header string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/api/trades"+query, nil)
+		if header != "" {
+			req.Header.Set("Accept", header)
+		}
+
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	// the responses are kept without format
+	w := get("", "")
+	assert.Equal(t, `{"data":[{"amount":"1500000000000000000","baseToken":"0x0000000000000000000000000000000000000001","pricepoint":2000000000000000000,"quoteToken":"0x0000000000000000000000000000000000000001"},{"amount":12,"pair":"unknown","price":1.5}]}`, w.Body.String())
+
+	w = get("?amountFormat=wei", "")
+	assert.Equal(t, "application/json; amounts=wei", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `"pricepoint":"2000000000000000000"`)
+	assert.Contains(t, w.Body.String(), `"amount":12`)
+
+	w = get("", "application/json; amounts=decimal")
+	assert.Contains(t, w.Body.String(), `"amount":"1.5"`)
+	assert.Contains(t, w.Body.String(), `"pricepoint":"2"`)
+
+	w = get("?amountFormat=float", "")
+	assert.Contains(t, w.Body.String(), `"amount":1.5`)
+	assert.Contains(t, w.Body.String(), `"pricepoint":2`)
+	assert.Contains(t, w.Body.String(), `"price":1.5`)
+
+	w = get("?amountFormat=hex", "")
+	assert.Equal(t, http.StatusNotAcceptable, w.Code)
+}
+
+func TestFormatAmount(t *testing.T) {
+	assert.Equal(t, "0.000001", FormatAmount("1000000000000", "decimal", 18, true))
+	assert.Equal(t, "1000000000000", FormatAmount("1000000000000", "wei", 18, true))
+	assert.Equal(t, "1.5", FormatAmount("1.5", "wei", 18, true))
+	assert.Equal(t, "15", FormatAmount("15", "decimal", 18, false))
+	assert.Equal(t, "150", FormatAmount("150", "decimal", 0, true))
+}
@@ -0,0 +1,70 @@
+package middlewares
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+)
+
+// DefaultMaxBodySize is the body size limit of the routes without override
+const DefaultMaxBodySize = int64(1 << 20)
+
+var (
+	bodyLimits     = map[string]int64{}
+	bodyLimitMutex sync.RWMutex
+)
+
+// SetBodyLimit overrides the body size limit of a route, identified by its path template
+func SetBodyLimit(path string, size int64) {
+	bodyLimitMutex.Lock()
+	defer bodyLimitMutex.Unlock()
+	bodyLimits[path] = size
+}
+
+// GetBodyLimit returns the body size limit of a route.
+// Limits set in the "body_limits" config section take precedence
+func GetBodyLimit(path string) int64 {
+	// config keys are lower cased
+	if size, ok := app.Config.BodyLimits[strings.ToLower(path)]; ok && size > 0 {
+		return size
+	}
+
+	bodyLimitMutex.RLock()
+	size, ok := bodyLimits[path]
+	bodyLimitMutex.RUnlock()
+	if ok {
+		return size
+	}
+
+	if app.Config.MaxBodySize > 0 {
+		return app.Config.MaxBodySize
+	}
+
+	return DefaultMaxBodySize
+}
+
+// LimitBody bounds the size of the request bodies, it is registered on the router
+// so that the limit of the matched route applies
+func LimitBody(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		if route := mux.CurrentRoute(r); route != nil {
+			if tpl, err := route.GetPathTemplate(); err == nil {
+				path = tpl
+			}
+		}
+
+		limit := GetBodyLimit(path)
+		if r.ContentLength > limit {
+			httputils.WriteError(w, http.StatusRequestEntityTooLarge, "Request body too large")
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, limit)
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,247 @@
+package middlewares
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/tomochain/tomox-sdk/errors"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+)
+
+// Error codes of the requests denied by the geo policy
+const (
+	GeoErrorIPBlocked         = "IP_BLOCKED"
+	GeoErrorCountryBlocked    = "COUNTRY_BLOCKED"
+	GeoErrorFeatureRestricted = "FEATURE_RESTRICTED"
+)
+
+// GeoIPProvider returns the ISO 3166 country code of an IP, empty if it is unknown
+type GeoIPProvider interface {
+	Country(ip net.IP) (string, error)
+}
+
+// CIDRProvider resolves the countries from a list of networks
+type CIDRProvider struct {
+	networks  []*net.IPNet
+	countries []string
+}
+
+// LoadCIDRProvider reads the networks of a CSV file holding a "cidr,country" line per network,
+// the blank lines and the lines starting with # are skipped. The first matching network wins
+func LoadCIDRProvider(path string) (*CIDRProvider, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	p := &CIDRProvider{}
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.Split(line, ",")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("geo: invalid line %d of %s", n, path)
+		}
+
+		_, network, err := net.ParseCIDR(strings.TrimSpace(parts[0]))
+		if err != nil {
+			return nil, fmt.Errorf("geo: invalid network on line %d of %s", n, path)
+		}
+
+		p.networks = append(p.networks, network)
+		p.countries = append(p.countries, strings.ToUpper(strings.TrimSpace(parts[1])))
+	}
+
+	return p, scanner.Err()
+}
+
+// Country returns the country of the first network holding the IP
+func (p *CIDRProvider) Country(ip net.IP) (string, error) {
+	for i, network := range p.networks {
+		if network.Contains(ip) {
+			return p.countries[i], nil
+		}
+	}
+
+	return "", nil
+}
+
+// GeoPolicy denies the requests of the blocked IPs and countries, and the restricted routes to the
+// restricted countries, with a 451 status and an audit entry. The country is read from the country
+// header set by the proxy in front of the SDK if configured, or resolved by the provider
+type GeoPolicy struct {
+	auditDao            interfaces.AuditDao
+	provider            GeoIPProvider
+	countryHeader       string
+	blockedIPs          []*net.IPNet
+	blockedCountries    map[string]bool
+	restrictedCountries map[string]bool
+	restrictedRoutes    []string
+}
+
+// NewGeoPolicy returns the policy of the "geo_policy" config section: the comma separated blocked_ips
+// (IPs or networks), blocked_countries, restricted_countries and restricted_routes (route templates
+// prefixes, optionally preceded by a method as in "POST /api/orders"), and the country_header
+func NewGeoPolicy(auditDao interfaces.AuditDao, provider GeoIPProvider, cfg map[string]string) (*GeoPolicy, error) {
+	p := &GeoPolicy{
+		auditDao:            auditDao,
+		provider:            provider,
+		countryHeader:       cfg["country_header"],
+		blockedCountries:    countrySet(cfg["blocked_countries"]),
+		restrictedCountries: countrySet(cfg["restricted_countries"]),
+		restrictedRoutes:    splitList(cfg["restricted_routes"]),
+	}
+
+	for _, s := range splitList(cfg["blocked_ips"]) {
+		if !strings.Contains(s, "/") {
+			if ip := net.ParseIP(s); ip != nil && ip.To4() != nil {
+				s += "/32"
+			} else {
+				s += "/128"
+			}
+		}
+
+		_, network, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, fmt.Errorf("geo: invalid blocked ip %s", s)
+		}
+
+		p.blockedIPs = append(p.blockedIPs, network)
+	}
+
+	return p, nil
+}
+
+// Enforce applies the policy, it is registered on the router so that the template of the matched route is known
+func (p *GeoPolicy) Enforce(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		code, country := p.evaluate(r)
+		if code == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		p.audit(r, code, country)
+
+		e := errors.NewHTTPError(http.StatusUnavailableForLegalReasons, code, errors.Params{"country": country})
+		httputils.Write(w, e.Status, e)
+	})
+}
+
+// evaluate returns the error code of the request, empty if it is allowed, with the country of the caller
+func (p *GeoPolicy) evaluate(r *http.Request) (string, string) {
+	ip := net.ParseIP(strings.TrimPrefix(IPKey(r.RemoteAddr), "ip:"))
+
+	for _, network := range p.blockedIPs {
+		if ip != nil && network.Contains(ip) {
+			return GeoErrorIPBlocked, ""
+		}
+	}
+
+	if len(p.blockedCountries) == 0 && len(p.restrictedCountries) == 0 {
+		return "", ""
+	}
+
+	country := p.country(r, ip)
+	if p.blockedCountries[country] {
+		return GeoErrorCountryBlocked, country
+	}
+
+	if p.restrictedCountries[country] && p.isRestrictedRoute(r) {
+		return GeoErrorFeatureRestricted, country
+	}
+
+	return "", country
+}
+
+func (p *GeoPolicy) country(r *http.Request, ip net.IP) string {
+	if p.countryHeader != "" {
+		if c := r.Header.Get(p.countryHeader); c != "" {
+			return strings.ToUpper(c)
+		}
+	}
+
+	if p.provider == nil || ip == nil {
+		return ""
+	}
+
+	c, err := p.provider.Country(ip)
+	if err != nil {
+		logger.Error(err)
+		return ""
+	}
+
+	return strings.ToUpper(c)
+}
+
+func (p *GeoPolicy) isRestrictedRoute(r *http.Request) bool {
+	path := r.URL.Path
+	if route := mux.CurrentRoute(r); route != nil {
+		if tpl, err := route.GetPathTemplate(); err == nil {
+			path = tpl
+		}
+	}
+
+	for _, restricted := range p.restrictedRoutes {
+		method := ""
+		if i := strings.Index(restricted, " "); i > 0 {
+			method, restricted = restricted[:i], strings.TrimSpace(restricted[i+1:])
+		}
+
+		if (method == "" || strings.EqualFold(method, r.Method)) && strings.HasPrefix(path, restricted) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *GeoPolicy) audit(r *http.Request, code, country string) {
+	l := &types.AuditLog{
+		Identity:   "geo:" + country,
+		Action:     "geo." + strings.ToLower(code),
+		HTTPMethod: r.Method,
+		Path:       r.URL.Path,
+		RemoteAddr: r.RemoteAddr,
+		Status:     http.StatusUnavailableForLegalReasons,
+	}
+
+	logger.Infof("Audit: %s %s %s %s -> %d", l.Identity, l.Action, l.HTTPMethod, l.Path, l.Status)
+
+	err := p.auditDao.Create(l)
+	if err != nil {
+		logger.Error(err)
+	}
+}
+
+func countrySet(s string) map[string]bool {
+	res := make(map[string]bool)
+	for _, c := range splitList(s) {
+		res[strings.ToUpper(c)] = true
+	}
+
+	return res
+}
+
+func splitList(s string) []string {
+	res := []string{}
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			res = append(res, v)
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,83 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeoPolicy(t *testing.T) {
+	f, err := ioutil.TempFile("", "geoip")
+	assert.NoError(t, err)
+	defer os.Remove(f.Name())
+
+	f.WriteString("# test networks\n10.1.0.0/16,us\n10.2.0.0/16,KP\n")
+	f.Close()
+
+	provider, err := LoadCIDRProvider(f.Name())
+	assert.NoError(t, err)
+
+	audit := &auditDaoMock{}
+	p, err := NewGeoPolicy(audit, provider, map[string]string{
+		"blocked_ips":          "10.3.0.1",
+		"blocked_countries":    "kp",
+		"restricted_countries": "US",
+		"restricted_routes":    "POST /api/orders,/api/lending",
+		"country_header":       "CF-IPCountry",
+	})
+	assert.NoError(t, err)
+
+	r := mux.NewRouter()
+	r.Use(p.Enforce)
+	r.HandleFunc("/api/orders", func(w http.ResponseWriter, r *http.Request) {}).Methods("GET", "POST")
+	r.HandleFunc("/api/lending/{hash}", func(w http.ResponseWriter, r *http.Request) {}).Methods("GET")
+
+	serve := func(method, path, addr, country string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, nil)
+		req.RemoteAddr = addr + ":1234"
+		if country != "" {
+			req.Header.Set("CF-IPCountry", country)
+		}
+
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	code := func(w *httptest.ResponseRecorder) string {
+		res := map[string]string{}
+		json.Unmarshal(w.Body.Bytes(), &res)
+		return res["error_code"]
+	}
+
+	assert.Equal(t, http.StatusOK, serve("POST", "/api/orders", "10.9.0.1", "").Code)
+
+	w := serve("GET", "/api/orders", "10.3.0.1", "")
+	assert.Equal(t, http.StatusUnavailableForLegalReasons, w.Code)
+	assert.Equal(t, GeoErrorIPBlocked, code(w))
+
+	w = serve("GET", "/api/orders", "10.2.0.1", "")
+	assert.Equal(t, GeoErrorCountryBlocked, code(w))
+
+	// the restricted countries keep the routes not restricted
+	assert.Equal(t, http.StatusOK, serve("GET", "/api/orders", "10.1.0.1", "").Code)
+
+	w = serve("POST", "/api/orders", "10.1.0.1", "")
+	assert.Equal(t, GeoErrorFeatureRestricted, code(w))
+
+	w = serve("GET", "/api/lending/0x1", "10.9.0.1", "us")
+	assert.Equal(t, GeoErrorFeatureRestricted, code(w))
+
+	assert.Len(t, audit.logs, 4)
+	assert.Equal(t, "geo:US", audit.logs[3].Identity)
+	assert.Equal(t, "geo.feature_restricted", audit.logs[3].Action)
+
+	_, err = NewGeoPolicy(audit, nil, map[string]string{"blocked_ips": "10.0.0.300"})
+	assert.Error(t, err)
+}
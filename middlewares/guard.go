@@ -0,0 +1,254 @@
+package middlewares
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+)
+
+// Defaults of the auth guard, overridden by the "auth_guard" config section
+const (
+	defaultMaxAuthFailures = 5
+	defaultFailureWindow   = 5 * time.Minute
+	defaultLockout         = 15 * time.Minute
+	defaultBaseDelay       = 100 * time.Millisecond
+	defaultMaxDelay        = 5 * time.Second
+)
+
+// ErrTooManyAuthFailures is returned to the clients that are locked out
+var ErrTooManyAuthFailures = errors.New("Too many failed authentication attempts")
+
+type authFailures struct {
+	count       int
+	first       time.Time
+	lockedUntil time.Time
+}
+
+// AuthGuard counts the authentication failures per IP and per API key.
+// Each failure delays the next attempts and the key is locked out
+// once too many failures happened within the failure window
+type AuthGuard struct {
+	failures    map[string]*authFailures
+	mutex       sync.Mutex
+	maxFailures int
+	window      time.Duration
+	lockout     time.Duration
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+
+	totalFailures uint64
+	totalLockouts uint64
+	totalRejected uint64
+}
+
+var authGuard *AuthGuard
+var authGuardOnce sync.Once
+
+// GetAuthGuard returns the auth guard configured from app.Config
+func GetAuthGuard() *AuthGuard {
+	authGuardOnce.Do(func() {
+		authGuard = NewAuthGuard(
+			configInt("max_failures", defaultMaxAuthFailures),
+			configDuration("window", time.Second, defaultFailureWindow),
+			configDuration("lockout", time.Second, defaultLockout),
+			configDuration("base_delay_ms", time.Millisecond, defaultBaseDelay),
+			configDuration("max_delay_ms", time.Millisecond, defaultMaxDelay),
+		)
+	})
+
+	return authGuard
+}
+
+// NewAuthGuard returns a new instance of AuthGuard
+func NewAuthGuard(maxFailures int, window, lockout, baseDelay, maxDelay time.Duration) *AuthGuard {
+	return &AuthGuard{
+		failures:    make(map[string]*authFailures),
+		maxFailures: maxFailures,
+		window:      window,
+		lockout:     lockout,
+		baseDelay:   baseDelay,
+		maxDelay:    maxDelay,
+	}
+}
+
+// IPKey returns the guard key of the client IP
+func IPKey(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+
+	return "ip:" + host
+}
+
+// ClientKeys returns the guard keys of a client, its IP and the hash of its API key when it sent one.
+// The failures are never counted against the address of a request as anyone can send badly signed
+// requests for an address
+func ClientKeys(remoteAddr string, apiKey string) []string {
+	keys := []string{IPKey(remoteAddr)}
+	if apiKey != "" {
+		keys = append(keys, APIKeyOwner(apiKey))
+	}
+
+	return keys
+}
+
+// Check returns the delay to apply before processing an authentication attempt.
+// It returns false if one of the keys is locked out
+func (g *AuthGuard) Check(keys ...string) (time.Duration, bool) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	now := time.Now()
+	delay := time.Duration(0)
+	for _, k := range keys {
+		f := g.failures[k]
+		if f == nil {
+			continue
+		}
+
+		if now.Before(f.lockedUntil) {
+			atomic.AddUint64(&g.totalRejected, 1)
+			return 0, false
+		}
+
+		if d := g.delay(f.count); d > delay {
+			delay = d
+		}
+	}
+
+	return delay, true
+}
+
+// Fail records an authentication failure for each key
+func (g *AuthGuard) Fail(keys ...string) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	atomic.AddUint64(&g.totalFailures, 1)
+
+	now := time.Now()
+	for _, k := range keys {
+		f := g.failures[k]
+		if f == nil || now.Sub(f.first) > g.window {
+			f = &authFailures{first: now}
+			g.failures[k] = f
+		}
+
+		f.count++
+		if f.count >= g.maxFailures && now.After(f.lockedUntil) {
+			f.lockedUntil = now.Add(g.lockout)
+			atomic.AddUint64(&g.totalLockouts, 1)
+			logger.Warningf("Authentication locked out for %s after %d failures", k, f.count)
+		}
+	}
+}
+
+// Succeed clears the failures of the keys
+func (g *AuthGuard) Succeed(keys ...string) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	for _, k := range keys {
+		f := g.failures[k]
+		if f != nil && time.Now().After(f.lockedUntil) {
+			delete(g.failures, k)
+		}
+	}
+}
+
+// Unblock removes the lockout of a key, it returns false if the key is not tracked
+func (g *AuthGuard) Unblock(key string) bool {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	_, ok := g.failures[key]
+	delete(g.failures, key)
+	return ok
+}
+
+// GetStatus returns the locked out keys and the guard metrics
+func (g *AuthGuard) GetStatus() *types.AuthGuardStatus {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	now := time.Now()
+	blocked := []*types.BlockedKey{}
+	for k, f := range g.failures {
+		if now.Sub(f.first) > g.window && now.After(f.lockedUntil) {
+			delete(g.failures, k)
+			continue
+		}
+
+		if now.Before(f.lockedUntil) {
+			blocked = append(blocked, &types.BlockedKey{Key: k, Failures: f.count, LockedUntil: f.lockedUntil})
+		}
+	}
+
+	return &types.AuthGuardStatus{
+		Blocked:       blocked,
+		Tracked:       len(g.failures),
+		TotalFailures: atomic.LoadUint64(&g.totalFailures),
+		TotalLockouts: atomic.LoadUint64(&g.totalLockouts),
+		TotalRejected: atomic.LoadUint64(&g.totalRejected),
+	}
+}
+
+func (g *AuthGuard) delay(count int) time.Duration {
+	if count == 0 {
+		return 0
+	}
+
+	d := g.baseDelay << uint(count-1)
+	if d > g.maxDelay || d <= 0 {
+		return g.maxDelay
+	}
+
+	return d
+}
+
+// WaitAuthGuard applies the progressive delay of the keys.
+// It returns ErrTooManyAuthFailures if one of the keys is locked out
+func WaitAuthGuard(keys ...string) error {
+	delay, ok := GetAuthGuard().Check(keys...)
+	if !ok {
+		return ErrTooManyAuthFailures
+	}
+
+	time.Sleep(delay)
+	return nil
+}
+
+// waitAuthGuard writes the error and returns false if the keys are locked out
+func waitAuthGuard(w http.ResponseWriter, keys ...string) bool {
+	err := WaitAuthGuard(keys...)
+	if err != nil {
+		httputils.WriteError(w, http.StatusTooManyRequests, err.Error())
+		return false
+	}
+
+	return true
+}
+
+func configInt(key string, def int) int {
+	if v, ok := app.Config.AuthGuard[key]; ok && v > 0 {
+		return v
+	}
+
+	return def
+}
+
+func configDuration(key string, unit time.Duration, def time.Duration) time.Duration {
+	if v, ok := app.Config.AuthGuard[key]; ok && v > 0 {
+		return time.Duration(v) * unit
+	}
+
+	return def
+}
@@ -0,0 +1,49 @@
+package middlewares
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthGuard(t *testing.T) {
+	g := NewAuthGuard(3, time.Minute, time.Minute, 10*time.Millisecond, 15*time.Millisecond)
+
+	delay, ok := g.Check("ip:1.1.1.1")
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), delay)
+
+	g.Fail("ip:1.1.1.1")
+	delay, ok = g.Check("ip:1.1.1.1")
+	assert.True(t, ok)
+	assert.Equal(t, 10*time.Millisecond, delay)
+
+	g.Fail("ip:1.1.1.1")
+	delay, _ = g.Check("ip:1.1.1.1")
+	assert.Equal(t, 15*time.Millisecond, delay)
+
+	g.Fail("ip:1.1.1.1")
+	_, ok = g.Check("ip:2.2.2.2", "ip:1.1.1.1")
+	assert.False(t, ok)
+
+	status := g.GetStatus()
+	assert.Equal(t, 1, len(status.Blocked))
+	assert.Equal(t, uint64(3), status.TotalFailures)
+	assert.Equal(t, uint64(1), status.TotalLockouts)
+	assert.Equal(t, uint64(1), status.TotalRejected)
+
+	// a success does not lift a lockout
+	g.Succeed("ip:1.1.1.1")
+	_, ok = g.Check("ip:1.1.1.1")
+	assert.False(t, ok)
+
+	assert.True(t, g.Unblock("ip:1.1.1.1"))
+	_, ok = g.Check("ip:1.1.1.1")
+	assert.True(t, ok)
+}
+
+func TestClientKeys(t *testing.T) {
+	assert.Equal(t, []string{"ip:1.1.1.1"}, ClientKeys("1.1.1.1:1234", ""))
+	assert.Equal(t, []string{"ip:1.1.1.1", APIKeyOwner("key")}, ClientKeys("1.1.1.1:1234", "key"))
+}
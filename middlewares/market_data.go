@@ -0,0 +1,203 @@
+package middlewares
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+)
+
+// maxDelayedRequests is the number of distinct requests whose responses are kept for the delayed feed
+const maxDelayedRequests = 5000
+
+// marketDataRoutes are the routes of the public market data, identified by their path template
+var marketDataRoutes = map[string]bool{
+	"/api/trades":                         true,
+	"/api/trades/aggregated":              true,
+	"/api/orderbook":                      true,
+	"/api/orderbook/raw":                  true,
+	"/api/orderbook/db":                   true,
+	"/api/ohlcv":                          true,
+	"/api/tokens/{address}/price-history": true,
+	"/api/pairs/data":                     true,
+	"/api/pair/data":                      true,
+	"/api/market/stats":                   true,
+	"/api/market/stats/all":               true,
+	"/api/market/snapshot":                true,
+	"/api/market/microstructure":          true,
+	"/api/indices":                        true,
+	"/api/indices/{name}":                 true,
+	"/api/market/tickers":                 true,
+	"/api/lending/trades":                 true,
+	"/api/lending/orderbook":              true,
+	"/api/lending/orderbook/db":           true,
+	"/api/lending-ohlcv":                  true,
+	"/api/lending/market/stats":           true,
+	"/api/lending/market/stats/all":       true,
+}
+
+// MarketDataDelay returns the delay of the market data served to the callers without a license
+func MarketDataDelay() time.Duration {
+	return time.Duration(app.Config.MarketData["delay"]) * time.Second
+}
+
+// HasMarketDataLicense returns true if the API key gets the real-time market data
+func HasMarketDataLicense(apiKey string) bool {
+	return types.HasRole(APIKeyRole(apiKey), types.RoleViewer)
+}
+
+// snapshot is a response of a market data route
+type snapshot struct {
+	at          time.Time
+	status      int
+	contentType string
+	body        []byte
+}
+
+// delayedFeed holds the recent responses of each market data request, one every step
+type delayedFeed struct {
+	mutex     sync.Mutex
+	snapshots map[string][]*snapshot
+}
+
+var feed = &delayedFeed{snapshots: make(map[string][]*snapshot)}
+
+// record adds a response of a request, the responses older than the delay are dropped
+// except the newest of them which is the one served
+func (f *delayedFeed) record(key string, s *snapshot, delay time.Duration) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.snapshots[key] == nil && len(f.snapshots) >= maxDelayedRequests {
+		f.prune(s.at, delay)
+		if len(f.snapshots) >= maxDelayedRequests {
+			return
+		}
+	}
+
+	snapshots := append(f.snapshots[key], s)
+	first := 0
+	for i, old := range snapshots {
+		if s.at.Sub(old.at) >= delay {
+			first = i
+		}
+	}
+
+	f.snapshots[key] = snapshots[first:]
+}
+
+// prune drops the requests not asked for twice the delay, the caller holds the lock
+func (f *delayedFeed) prune(now time.Time, delay time.Duration) {
+	for key, snapshots := range f.snapshots {
+		if now.Sub(snapshots[len(snapshots)-1].at) > 2*delay {
+			delete(f.snapshots, key)
+		}
+	}
+}
+
+// get returns the recorded responses of a request, the oldest first
+func (f *delayedFeed) get(key string) []*snapshot {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return f.snapshots[key]
+}
+
+// recorder buffers a response
+type recorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *recorder) Header() http.Header {
+	return r.header
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	return r.body.Write(b)
+}
+
+func (r *recorder) WriteHeader(status int) {
+	r.status = status
+}
+
+// DelayMarketData serves the market data routes to the callers without a viewer API key ("X-Api-Key" header)
+// as they were the "delay" seconds of the "market_data" config section before. The responses are recorded
+// per request at most every tenth of the delay, a request not asked for the delay is answered 503 with
+// the time to wait. It is registered on the router so that the template of the matched route is known
+func DelayMarketData(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delay := MarketDataDelay()
+		if delay <= 0 || r.Method != http.MethodGet || !isMarketDataRoute(r) || HasMarketDataLicense(r.Header.Get("X-Api-Key")) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		step := delay / 10
+		if step < time.Second {
+			step = time.Second
+		}
+
+		key := r.URL.Path + "?" + r.URL.Query().Encode()
+		now := time.Now()
+		snapshots := feed.get(key)
+
+		if len(snapshots) == 0 || now.Sub(snapshots[len(snapshots)-1].at) >= step {
+			rec := &recorder{header: http.Header{}, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			if rec.status == http.StatusOK {
+				feed.record(key, &snapshot{
+					at:          now,
+					status:      rec.status,
+					contentType: rec.header.Get("Content-Type"),
+					body:        rec.body.Bytes(),
+				}, delay)
+			}
+		}
+
+		var res *snapshot
+		for _, s := range snapshots {
+			if now.Sub(s.at) >= delay {
+				res = s
+			}
+		}
+
+		if res == nil {
+			wait := delay
+			if len(snapshots) > 0 {
+				wait -= now.Sub(snapshots[0].at)
+			}
+
+			w.Header().Set("Retry-After", strconv.Itoa(int((wait+time.Second-1)/time.Second)))
+			httputils.WriteError(w, http.StatusServiceUnavailable, "Delayed market data not available yet, real-time data requires an API key")
+			return
+		}
+
+		w.Header().Set("Content-Type", res.contentType)
+		w.Header().Set("X-Data-Delay", strconv.Itoa(int(delay/time.Second)))
+		w.Header().Set("Date", res.at.UTC().Format(http.TimeFormat))
+		w.WriteHeader(res.status)
+		w.Write(res.body)
+	})
+}
+
+// isMarketDataRoute returns true if the request matched a market data route
+func isMarketDataRoute(r *http.Request) bool {
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	if route := mux.CurrentRoute(r); route != nil {
+		if tpl, err := route.GetPathTemplate(); err == nil {
+			path = tpl
+		}
+	}
+
+	return marketDataRoutes[path]
+}
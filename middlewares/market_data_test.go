@@ -0,0 +1,64 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+func TestDelayMarketData(t *testing.T) {
+	app.Config.MarketData = map[string]int{"delay": 60}
+	app.Config.ApiKeys = map[string][]string{types.RoleViewer: {"viewer-key"}}
+	defer func() { app.Config.MarketData = nil }()
+
+	price := "live"
+	r := mux.NewRouter()
+	r.Use(DelayMarketData)
+	r.HandleFunc("/api/ohlcv", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(price))
+	}).Methods("GET")
+
+	get := func(key string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/api/ohlcv?pair=TOMO/USDT", nil)
+		req.Header.Set("X-Api-Key", key)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// nothing recorded a minute ago yet
+	w := get("")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "60", w.Header().Get("Retry-After"))
+
+	// the licensed callers get the real-time data
+	w = get("viewer-key")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "live", w.Body.String())
+
+	key := "/api/ohlcv?pair=TOMO%2FUSDT"
+	feed.mutex.Lock()
+	feed.snapshots[key] = []*snapshot{
+		{at: time.Now().Add(-2 * time.Minute), status: http.StatusOK, body: []byte("older")},
+		{at: time.Now().Add(-61 * time.Second), status: http.StatusOK, body: []byte("delayed")},
+		{at: time.Now().Add(-30 * time.Second), status: http.StatusOK, body: []byte("recent")},
+	}
+	feed.mutex.Unlock()
+
+	w = get("")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "delayed", w.Body.String())
+	assert.Equal(t, "60", w.Header().Get("X-Data-Delay"))
+
+	// the live response was recorded and the responses older than the served one dropped
+	snapshots := feed.get(key)
+	assert.Equal(t, 3, len(snapshots))
+	assert.Equal(t, "delayed", string(snapshots[0].body))
+	assert.Equal(t, "live", string(snapshots[2].body))
+}
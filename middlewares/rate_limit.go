@@ -0,0 +1,78 @@
+package middlewares
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/tomochain/tomox-sdk/usage"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+)
+
+// Defaults of the rate limiters
+const (
+	defaultRateLimit       = 10
+	defaultRateLimitWindow = time.Minute
+)
+
+// RateLimiter limits the requests of each caller, identified by its usage key, to a number per window.
+// The counters of all the callers are reset when the window ends
+type RateLimiter struct {
+	counts      map[string]int
+	limit       int
+	window      time.Duration
+	windowStart time.Time
+	mutex       sync.Mutex
+}
+
+// NewRateLimiter returns a new instance of RateLimiter, the zero limit and window take their defaults
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	if limit <= 0 {
+		limit = defaultRateLimit
+	}
+
+	if window <= 0 {
+		window = defaultRateLimitWindow
+	}
+
+	return &RateLimiter{
+		counts:      make(map[string]int),
+		limit:       limit,
+		window:      window,
+		windowStart: time.Now(),
+	}
+}
+
+// Allow records a request of the key at a time. It returns false once the key made all the requests
+// of the window, with the time until the window ends
+func (l *RateLimiter) Allow(key string, now time.Time) (bool, time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if now.Sub(l.windowStart) >= l.window {
+		l.counts = make(map[string]int)
+		l.windowStart = now
+	}
+
+	if l.counts[key] >= l.limit {
+		return false, l.windowStart.Add(l.window).Sub(now)
+	}
+
+	l.counts[key]++
+	return true, 0
+}
+
+// Limit rejects the requests over the limit of their caller with 429 and a Retry-After header
+func (l *RateLimiter) Limit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ok, retry := l.Allow(usage.Key(r), time.Now())
+		if !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(retry.Seconds())+1))
+			httputils.WriteError(w, http.StatusTooManyRequests, "Too many requests")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
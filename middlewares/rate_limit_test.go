@@ -0,0 +1,46 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	l := NewRateLimiter(2, time.Minute)
+	now := time.Now()
+
+	ok, _ := l.Allow("ip:1.1.1.1", now)
+	assert.True(t, ok)
+	ok, _ = l.Allow("ip:1.1.1.1", now)
+	assert.True(t, ok)
+
+	ok, retry := l.Allow("ip:1.1.1.1", now.Add(10*time.Second))
+	assert.False(t, ok)
+	assert.True(t, retry > 40*time.Second && retry <= 50*time.Second)
+
+	// the callers have their own counters, reset with the window
+	ok, _ = l.Allow("ip:2.2.2.2", now)
+	assert.True(t, ok)
+	ok, _ = l.Allow("ip:1.1.1.1", now.Add(time.Minute))
+	assert.True(t, ok)
+}
+
+func TestRateLimiterLimit(t *testing.T) {
+	h := NewRateLimiter(1, time.Minute).Limit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest("POST", "/api/accounts/batch", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+}
@@ -0,0 +1,195 @@
+package middlewares
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/justinas/alice"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+)
+
+var logger = utils.Logger
+
+type contextKey string
+
+const identityContextKey = contextKey("identity")
+
+// RBAC authenticates the callers of the admin and operator APIs
+// and records every action in the audit log
+type RBAC struct {
+	auditDao interfaces.AuditDao
+}
+
+// NewRBAC returns a new instance of RBAC
+func NewRBAC(auditDao interfaces.AuditDao) *RBAC {
+	return &RBAC{auditDao}
+}
+
+// GetIdentity returns the identity authenticated by the RBAC middleware
+func GetIdentity(r *http.Request) *types.Identity {
+	i, _ := r.Context().Value(identityContextKey).(*types.Identity)
+	return i
+}
+
+// Authenticate resolves the identity of the caller from its API key
+// ("X-Api-Key" header or legacy "authKey" query param) or from the SSO header
+func (a *RBAC) Authenticate(r *http.Request) *types.Identity {
+	key := r.Header.Get("X-Api-Key")
+	if key == "" {
+		key = r.URL.Query().Get("authKey")
+	}
+
+	if key != "" {
+		role := lookupAPIKey(key)
+		if role == "" {
+			return nil
+		}
+
+		sum := sha256.Sum256([]byte(key))
+		return &types.Identity{
+			Name:   "apikey:" + hex.EncodeToString(sum[:4]),
+			Role:   role,
+			Method: types.AuthMethodAPIKey,
+		}
+	}
+
+	if app.Config.SSOHeader != "" {
+		user := r.Header.Get(app.Config.SSOHeader)
+		role := lookupRole(app.Config.SSORoles, user)
+		if user != "" && role != "" {
+			return &types.Identity{
+				Name:   user,
+				Role:   role,
+				Method: types.AuthMethodSSO,
+			}
+		}
+	}
+
+	return nil
+}
+
+// Require only lets through the callers granted the role and audits the action
+func (a *RBAC) Require(role, action string) alice.Constructor {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := IPKey(r.RemoteAddr)
+			if !waitAuthGuard(w, ip) {
+				return
+			}
+
+			identity := a.Authenticate(r)
+			if identity == nil {
+				GetAuthGuard().Fail(ip)
+				a.audit(r, &types.Identity{Name: "anonymous"}, action, http.StatusUnauthorized)
+				httputils.WriteError(w, http.StatusUnauthorized, "Invalid auth key")
+				return
+			}
+
+			if !types.HasRole(identity.Role, role) {
+				a.audit(r, identity, action, http.StatusForbidden)
+				httputils.WriteError(w, http.StatusForbidden, "Permission denied")
+				return
+			}
+
+			GetAuthGuard().Succeed(ip)
+
+			sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+			ctx := context.WithValue(r.Context(), identityContextKey, identity)
+			next.ServeHTTP(sw, r.WithContext(ctx))
+
+			a.audit(r, identity, action, sw.status)
+		})
+	}
+}
+
+// Audit records an action of an identity authenticated outside of the middleware
+func (a *RBAC) Audit(r *http.Request, identity *types.Identity, action string, status int) {
+	a.audit(r, identity, action, status)
+}
+
+func (a *RBAC) audit(r *http.Request, identity *types.Identity, action string, status int) {
+	l := &types.AuditLog{
+		Identity:   identity.Name,
+		Role:       identity.Role,
+		Method:     identity.Method,
+		Action:     action,
+		HTTPMethod: r.Method,
+		Path:       r.URL.Path,
+		RemoteAddr: r.RemoteAddr,
+		Status:     status,
+	}
+
+	logger.Infof("Audit: %s (%s) %s %s %s -> %d", l.Identity, l.Role, l.Action, l.HTTPMethod, l.Path, l.Status)
+
+	err := a.auditDao.Create(l)
+	if err != nil {
+		logger.Error(err)
+	}
+}
+
+// APIKeyRole returns the role granted to an API key, empty if the key is unknown
+func APIKeyRole(key string) string {
+	if key == "" {
+		return ""
+	}
+
+	return lookupAPIKey(key)
+}
+
+// APIKeyOwner returns the owner of the resources saved by the clients of an API key
+func APIKeyOwner(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return "apikey:" + hex.EncodeToString(sum[:])
+}
+
+// lookupAPIKey returns the role of the API key, the legacy api_auth_key is an admin key
+func lookupAPIKey(key string) string {
+	if app.Config.ApiAuthKey != "" && subtle.ConstantTimeCompare([]byte(key), []byte(app.Config.ApiAuthKey)) == 1 {
+		return types.RoleAdmin
+	}
+
+	return lookupRole(app.Config.ApiKeys, key)
+}
+
+// lookupRole returns the highest role granted to the value in a role -> values mapping
+func lookupRole(roles map[string][]string, value string) string {
+	granted := ""
+	for role, values := range roles {
+		if !types.IsValidRole(role) || (granted != "" && types.HasRole(granted, role)) {
+			continue
+		}
+
+		for _, v := range values {
+			if subtle.ConstantTimeCompare([]byte(value), []byte(v)) == 1 {
+				granted = role
+				break
+			}
+		}
+	}
+
+	return granted
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+	return n, err
+}
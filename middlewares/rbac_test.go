@@ -0,0 +1,62 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+type auditDaoMock struct {
+	logs []*types.AuditLog
+}
+
+func (d *auditDaoMock) Create(l *types.AuditLog) error {
+	d.logs = append(d.logs, l)
+	return nil
+}
+
+func (d *auditDaoMock) GetAll(offset, limit int) ([]*types.AuditLog, error) {
+	return d.logs, nil
+}
+
+func TestRBACRequire(t *testing.T) {
+	app.Config.ApiAuthKey = "legacy"
+	app.Config.ApiKeys = map[string][]string{
+		types.RoleViewer:   {"viewer-key"},
+		types.RoleOperator: {"operator-key"},
+	}
+
+	dao := &auditDaoMock{}
+	handler := NewRBAC(dao).Require(types.RoleOperator, "test.action")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NotNil(t, GetIdentity(r))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	cases := []struct {
+		key    string
+		status int
+	}{
+		{"", http.StatusUnauthorized},
+		{"unknown", http.StatusUnauthorized},
+		{"viewer-key", http.StatusForbidden},
+		{"operator-key", http.StatusNoContent},
+		{"legacy", http.StatusNoContent},
+	}
+
+	for _, c := range cases {
+		req := httptest.NewRequest("PUT", "/api/test", nil)
+		req.Header.Set("X-Api-Key", c.key)
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+		assert.Equal(t, c.status, w.Code, c.key)
+	}
+
+	assert.Equal(t, len(cases), len(dao.logs))
+	assert.Equal(t, "test.action", dao.logs[4].Action)
+	assert.Equal(t, types.RoleAdmin, dao.logs[4].Role)
+}
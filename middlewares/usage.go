@@ -0,0 +1,50 @@
+package middlewares
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/usage"
+)
+
+// TrackUsage counts the requests and the errors of each user in the API usage statistics and records
+// them in the access logs, it is registered on the router so that the address and the template of
+// the matched route are known
+func TrackUsage(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := usage.Key(r)
+		start := time.Now()
+
+		// the websocket upgrade needs the original writer, its messages are counted by the ws package
+		if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+			usage.GetTracker().Request(key, http.StatusSwitchingProtocols)
+			usage.GetAccessLogger().Log(types.AccessLogHTTP, routeTemplate(r), r.Method, http.StatusSwitchingProtocols, time.Since(start), 0, key)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(sw, r)
+
+		usage.GetTracker().Request(key, sw.status)
+		usage.GetAccessLogger().Log(types.AccessLogHTTP, routeTemplate(r), r.Method, sw.status, time.Since(start), sw.bytes, key)
+	})
+}
+
+// routeTemplate returns the template of the route of the request, its path could hold addresses
+func routeTemplate(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return "unmatched"
+	}
+
+	tpl, err := route.GetPathTemplate()
+	if err != nil {
+		return "unmatched"
+	}
+
+	return tpl
+}
@@ -10,6 +10,10 @@ import (
 
 func VerifySignature(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip := IPKey(r.RemoteAddr)
+		if !waitAuthGuard(w, ip) {
+			return
+		}
 
 		if r.Header["Signature"] == nil || r.Header["Hash"] == nil || r.Header["Pubkey"] == nil {
 			httputils.WriteError(w, http.StatusUnauthorized, "There is not enough parameters in header")
@@ -24,10 +28,13 @@ func VerifySignature(next http.Handler) http.Handler {
 		verified := crypto.VerifySignature(publicKeyBytes, hash, signatureNoRecoverID)
 
 		if !verified {
+			GetAuthGuard().Fail(ip)
 			httputils.WriteError(w, http.StatusUnauthorized, "Signature Invalid")
 			return
 		}
 
+		GetAuthGuard().Succeed(ip)
+
 		next.ServeHTTP(w, r)
 	})
 }
@@ -31,6 +31,7 @@ func (c *Connection) QueuePoolDepositTransactions() (<-chan *types.DepositTransa
 
 		go func() {
 			for d := range msgs {
+				observeDelivery(q.Name, d)
 				msg := &types.DepositTransaction{}
 				err := json.Unmarshal(d.Body, msg)
 				if err != nil {
@@ -51,6 +52,25 @@ func (c *Connection) QueuePoolDepositTransactions() (<-chan *types.DepositTransa
 	return out, nil
 }
 
+// SubscribeDeposits handles the deposit transactions of the deposit queue one at a time
+func (c *Connection) SubscribeDeposits(fn func(*types.DepositTransaction) error) error {
+	deposits, err := c.QueuePoolDepositTransactions()
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for d := range deposits {
+			err := fn(d)
+			if err != nil {
+				logger.Error("Deposit", d.TransactionID, "not handled:", err)
+			}
+		}
+	}()
+
+	return nil
+}
+
 func (c *Connection) PublishDepositTransaction(transaction *types.DepositTransaction) error {
 	ch := c.GetChannel("depositPublish")
 	q := c.GetQueue(ch, "deposit")
@@ -30,6 +30,7 @@ func (c *Connection) SubscribeQueue(fn func(*types.EngineResponse) error, queue
 
 		go func() {
 			for d := range msgs {
+				observeDelivery(q.Name, d)
 				var res *types.EngineResponse
 				err := json.Unmarshal(d.Body, &res)
 				if err != nil {
@@ -89,6 +90,7 @@ func (c *Connection) SubscribeEngineResponses(fn func(*types.EngineResponse) err
 
 		go func() {
 			for d := range msgs {
+				observeDelivery(q.Name, d)
 				var res *types.EngineResponse
 				err := json.Unmarshal(d.Body, &res)
 				if err != nil {
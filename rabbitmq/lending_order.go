@@ -28,6 +28,7 @@ func (c *Connection) SubscribeLendingOrders(fn func(*Message) error) error {
 
 		go func() {
 			for d := range msgs {
+				observeDelivery(q.Name, d)
 				msg := &Message{}
 				err := json.Unmarshal(d.Body, msg)
 				if err != nil {
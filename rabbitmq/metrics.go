@@ -0,0 +1,118 @@
+package rabbitmq
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/streadway/amqp"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// publishedAtHeader is the header of the messages holding their publish time in nanoseconds, from
+// which the lag of the consumers is measured
+const publishedAtHeader = "publishedAt"
+
+var (
+	metricsMutex sync.Mutex
+	queueMetrics = make(map[string]*types.QueueMetrics)
+)
+
+// metricsOf returns the metrics of a queue, the caller holds the metrics mutex
+func metricsOf(queue string) *types.QueueMetrics {
+	m := queueMetrics[queue]
+	if m == nil {
+		m = &types.QueueMetrics{Name: queue}
+		queueMetrics[queue] = m
+	}
+
+	return m
+}
+
+// watchQueue adds a declared queue to the metrics
+func watchQueue(queue string) {
+	metricsMutex.Lock()
+	defer metricsMutex.Unlock()
+	metricsOf(queue)
+}
+
+// observePublish records the latency of a publish to the queue started at start
+func observePublish(queue string, start time.Time, err error) {
+	d := time.Since(start)
+
+	metricsMutex.Lock()
+	defer metricsMutex.Unlock()
+
+	m := metricsOf(queue)
+	if err != nil {
+		m.PublishErrors++
+		return
+	}
+
+	m.Published.Add(d)
+}
+
+// observeDelivery records the lag of the consumers of the queue, the time since the message was published
+func observeDelivery(queue string, d amqp.Delivery) {
+	publishedAt, ok := d.Headers[publishedAtHeader].(int64)
+	if !ok {
+		return
+	}
+
+	lag := time.Since(time.Unix(0, publishedAt))
+
+	metricsMutex.Lock()
+	defer metricsMutex.Unlock()
+	metricsOf(queue).ConsumerLag.Add(lag)
+}
+
+// GetMetrics returns the metrics of the queues declared by the SDK, with the messages waiting in each
+// queue read from the broker
+func (c *Connection) GetMetrics() *types.RabbitMQMetrics {
+	metricsMutex.Lock()
+	queues := make([]*types.QueueMetrics, 0, len(queueMetrics))
+	for _, m := range queueMetrics {
+		q := *m
+		queues = append(queues, &q)
+	}
+	metricsMutex.Unlock()
+
+	sort.Slice(queues, func(i, j int) bool {
+		return queues[i].Name < queues[j].Name
+	})
+
+	res := &types.RabbitMQMetrics{Queues: queues}
+	if c == nil || c.Conn == nil {
+		return res
+	}
+
+	// a failed inspection closes its channel, which is opened again for the next queue
+	var ch *amqp.Channel
+	for _, q := range queues {
+		if ch == nil {
+			var err error
+			ch, err = c.Conn.Channel()
+			if err != nil {
+				q.Error = err.Error()
+				continue
+			}
+		}
+
+		res.Connected = true
+		state, err := ch.QueueInspect(q.Name)
+		if err != nil {
+			q.Error = err.Error()
+			ch = nil
+			continue
+		}
+
+		q.Messages = state.Messages
+		q.Consumers = state.Consumers
+	}
+
+	if ch != nil {
+		ch.Close()
+	}
+
+	return res
+}
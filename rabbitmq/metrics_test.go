@@ -0,0 +1,35 @@
+package rabbitmq
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueueMetrics(t *testing.T) {
+	watchQueue("metricsB")
+	observePublish("metricsA", time.Now(), nil)
+	observePublish("metricsA", time.Now(), errors.New("channel closed"))
+	observeDelivery("metricsA", amqp.Delivery{Headers: amqp.Table{publishedAtHeader: time.Now().Add(-time.Second).UnixNano()}})
+	observeDelivery("metricsA", amqp.Delivery{})
+
+	var c *Connection
+	m := c.GetMetrics()
+	assert.False(t, m.Connected)
+
+	names := []string{}
+	for _, q := range m.Queues {
+		names = append(names, q.Name)
+	}
+	assert.Equal(t, []string{"metricsA", "metricsB"}, names)
+
+	a := m.Queues[0]
+	assert.Equal(t, int64(1), a.Published.Count)
+	assert.Equal(t, int64(1), a.PublishErrors)
+	assert.Equal(t, int64(1), a.ConsumerLag.Count)
+	assert.True(t, a.ConsumerLag.MeanMs >= 1000)
+	assert.Equal(t, int64(0), m.Queues[1].Published.Count)
+}
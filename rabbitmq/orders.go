@@ -3,12 +3,19 @@ package rabbitmq
 import (
 	"encoding/json"
 	"log"
+	"sync"
 
 	"github.com/tomochain/tomox-sdk/errors"
 	"github.com/tomochain/tomox-sdk/types"
 )
 
-func (c *Connection) SubscribeOrders(fn func(*Message) error) error {
+// heldMutex serializes the accesses to the held order queues
+var heldMutex sync.Mutex
+
+// SubscribeOrders consumes the order messages, accept is called when a message is taken from RabbitMQ and
+// fn by the worker handling it. A message is acked once handled, at most queue_size messages are taken from
+// RabbitMQ meanwhile so that the backlog of a congested engine stays in RabbitMQ
+func (c *Connection) SubscribeOrders(accept func(*Message), fn func(*Message) error) error {
 	ch := c.GetChannel("orderSubscribe")
 	if ch == nil {
 		return errors.New("Fail to open orderSubscribe chanel")
@@ -17,8 +24,28 @@ func (c *Connection) SubscribeOrders(fn func(*Message) error) error {
 	if q == nil {
 		return errors.New("Fail to open order queue")
 	}
+
+	// the messages are handed to a pool of workers through the order queue so that
+	// the cancels are handled before the new orders when the engine is congested
+	orders, workers := newOrderQueueFromConfig()
+	err := ch.Qos(orders.size, 0, false)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			for {
+				msg := orders.Pop()
+				fn(msg)
+				orders.Done(msg)
+				msg.ack()
+			}
+		}()
+	}
+
 	go func() {
-		msgs, err := c.Consume(ch, q)
+		msgs, err := c.ConsumeAfterAck(ch, q)
 		if err != nil {
 			logger.Error(err)
 		}
@@ -27,14 +54,18 @@ func (c *Connection) SubscribeOrders(fn func(*Message) error) error {
 
 		go func() {
 			for d := range msgs {
+				observeDelivery(q.Name, d)
 				msg := &Message{}
 				err := json.Unmarshal(d.Body, msg)
 				if err != nil {
 					logger.Error(err)
+					d.Ack(false)
 					continue
 				}
 
-				go fn(msg)
+				msg.delivery = d
+				accept(msg)
+				orders.Push(msg)
 			}
 		}()
 
@@ -141,3 +172,72 @@ func (c *Connection) PublishOrder(order *Message) error {
 
 	return nil
 }
+
+// heldQueueName returns the name of the queue of the order messages held for a pair paused in queue mode
+func heldQueueName(code string) string {
+	return "orderHeld." + code
+}
+
+// PublishHeldOrder adds an order message of a pair paused in queue mode to the held queue of the pair.
+// The held messages are published and read on the same channel so that a read sees the messages
+// published before it
+func (c *Connection) PublishHeldOrder(code string, msg *Message) error {
+	heldMutex.Lock()
+	defer heldMutex.Unlock()
+
+	ch := c.GetChannel("orderHeld")
+	if ch == nil {
+		return errors.New("Fail to open orderHeld channel")
+	}
+
+	q := c.GetQueue(ch, heldQueueName(code))
+	if q == nil {
+		return errors.New("Fail to open held order queue")
+	}
+
+	bytes, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	return c.Publish(ch, q, bytes)
+}
+
+// NextHeldOrder returns the oldest held order message of a pair and the function acking it once handled,
+// a nil message if the held queue of the pair is empty
+func (c *Connection) NextHeldOrder(code string) (*Message, func(), error) {
+	heldMutex.Lock()
+	defer heldMutex.Unlock()
+
+	ch := c.GetChannel("orderHeld")
+	if ch == nil {
+		return nil, nil, errors.New("Fail to open orderHeld channel")
+	}
+
+	q := c.GetQueue(ch, heldQueueName(code))
+	if q == nil {
+		return nil, nil, errors.New("Fail to open held order queue")
+	}
+
+	for {
+		d, ok, err := ch.Get(q.Name, false)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if !ok {
+			return nil, nil, nil
+		}
+
+		msg := &Message{}
+		err = json.Unmarshal(d.Body, msg)
+		if err != nil {
+			logger.Error(err)
+			d.Ack(false)
+			continue
+		}
+
+		msg.delivery = d
+		return msg, msg.ack, nil
+	}
+}
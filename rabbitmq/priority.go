@@ -0,0 +1,183 @@
+package rabbitmq
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/app"
+)
+
+// Defaults of the order dispatcher, overridden by the "order_priority" config section
+const (
+	defaultOrderWorkers   = 32
+	defaultCancelWeight   = 0
+	defaultOrderQueueSize = 2000
+)
+
+// IsPriorityMessage returns true for the messages that reduce the risk of the users,
+// they are handled before the new orders
+func IsPriorityMessage(msg *Message) bool {
+	switch msg.Type {
+	case "CANCEL_ORDER", "CANCEL_STOP_ORDER":
+		return true
+	default:
+		return false
+	}
+}
+
+// queuedMessage is a message waiting in the order queue with the hash of the order it is about
+type queuedMessage struct {
+	msg  *Message
+	hash common.Hash
+}
+
+// messageHash returns the hash of the order of a message, the zero hash if it has none
+func messageHash(msg *Message) common.Hash {
+	o := struct {
+		Hash common.Hash `json:"hash"`
+	}{}
+
+	json.Unmarshal(msg.Data, &o)
+	return o.Hash
+}
+
+// OrderQueue holds the order messages waiting for a worker in two queues, cancels and new orders.
+// A cancel is served before the new orders of the other orders unless CancelWeight is set, in which case
+// a waiting new order is served after each CancelWeight cancels so that new orders are never starved.
+// A cancel never overtakes a message of its own order, it waits until the messages of its order queued or
+// handled by a worker are done. The queue holds at most size messages, Push blocks while it is full
+type OrderQueue struct {
+	cancels      []*queuedMessage
+	orders       []*queuedMessage
+	waiting      map[common.Hash]int
+	handled      map[common.Hash]int
+	served       int
+	cancelWeight int
+	size         int
+	mutex        sync.Mutex
+	cond         *sync.Cond
+}
+
+// NewOrderQueue returns a new instance of OrderQueue holding at most size messages, a cancelWeight of 0
+// means strict priority
+func NewOrderQueue(cancelWeight int, size int) *OrderQueue {
+	q := &OrderQueue{
+		waiting:      make(map[common.Hash]int),
+		handled:      make(map[common.Hash]int),
+		cancelWeight: cancelWeight,
+		size:         size,
+	}
+
+	q.cond = sync.NewCond(&q.mutex)
+	return q
+}
+
+// Push adds a message to its queue, it blocks while the queue is full
+func (q *OrderQueue) Push(msg *Message) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for len(q.cancels)+len(q.orders) >= q.size {
+		q.cond.Wait()
+	}
+
+	m := &queuedMessage{msg: msg, hash: messageHash(msg)}
+	if IsPriorityMessage(msg) {
+		q.cancels = append(q.cancels, m)
+	} else {
+		q.orders = append(q.orders, m)
+		q.waiting[m.hash]++
+	}
+
+	q.cond.Broadcast()
+}
+
+// Pop returns the next message to handle, it blocks until a message can be handled.
+// Done must be called once the message is handled
+func (q *OrderQueue) Pop() *Message {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for {
+		if m := q.pop(); m != nil {
+			q.handled[m.hash]++
+			q.cond.Broadcast()
+			return m.msg
+		}
+
+		q.cond.Wait()
+	}
+}
+
+func (q *OrderQueue) pop() *queuedMessage {
+	if len(q.orders) == 0 || q.cancelWeight == 0 || q.served < q.cancelWeight {
+		for i, m := range q.cancels {
+			if m.hash != (common.Hash{}) && (q.waiting[m.hash] > 0 || q.handled[m.hash] > 0) {
+				continue
+			}
+
+			q.cancels = append(q.cancels[:i], q.cancels[i+1:]...)
+			q.served++
+			return m
+		}
+	}
+
+	if len(q.orders) == 0 {
+		return nil
+	}
+
+	m := q.orders[0]
+	q.orders[0] = nil
+	q.orders = q.orders[1:]
+	q.served = 0
+
+	q.waiting[m.hash]--
+	if q.waiting[m.hash] == 0 {
+		delete(q.waiting, m.hash)
+	}
+
+	return m
+}
+
+// Done releases the cancels waiting for the order of a message handled by a worker
+func (q *OrderQueue) Done(msg *Message) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	hash := messageHash(msg)
+	q.handled[hash]--
+	if q.handled[hash] <= 0 {
+		delete(q.handled, hash)
+	}
+
+	q.cond.Broadcast()
+}
+
+// Pending returns the number of cancels and new orders waiting for a worker
+func (q *OrderQueue) Pending() (int, int) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return len(q.cancels), len(q.orders)
+}
+
+// newOrderQueueFromConfig returns the order queue and the number of workers configured from app.Config
+func newOrderQueueFromConfig() (*OrderQueue, int) {
+	workers := defaultOrderWorkers
+	if v := app.Config.OrderPriority["workers"]; v > 0 {
+		workers = v
+	}
+
+	cancelWeight := defaultCancelWeight
+	if v := app.Config.OrderPriority["cancel_weight"]; v > 0 {
+		cancelWeight = v
+	}
+
+	size := defaultOrderQueueSize
+	if v := app.Config.OrderPriority["queue_size"]; v > 0 {
+		size = v
+	}
+
+	return NewOrderQueue(cancelWeight, size), workers
+}
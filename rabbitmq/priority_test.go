@@ -0,0 +1,95 @@
+package rabbitmq
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func orderMessage(msgType string, hash string) *Message {
+	return &Message{Type: msgType, Data: []byte(fmt.Sprintf(`{"hash":"%s"}`, common.HexToHash(hash).Hex()))}
+}
+
+func popHashes(q *OrderQueue, n int) []string {
+	res := []string{}
+	for i := 0; i < n; i++ {
+		msg := q.Pop()
+		res = append(res, messageHash(msg).Hex()[65:])
+		q.Done(msg)
+	}
+
+	return res
+}
+
+func TestOrderQueueStrictPriority(t *testing.T) {
+	q := NewOrderQueue(0, 10)
+	q.Push(orderMessage("NEW_ORDER", "0x1"))
+	q.Push(orderMessage("NEW_ORDER", "0x2"))
+	q.Push(orderMessage("CANCEL_ORDER", "0x3"))
+	q.Push(orderMessage("CANCEL_STOP_ORDER", "0x4"))
+
+	cancels, orders := q.Pending()
+	assert.Equal(t, 2, cancels)
+	assert.Equal(t, 2, orders)
+
+	assert.Equal(t, []string{"3", "4", "1", "2"}, popHashes(q, 4))
+}
+
+func TestOrderQueueCancelWeight(t *testing.T) {
+	q := NewOrderQueue(2, 10)
+	for _, h := range []string{"0x1", "0x2"} {
+		q.Push(orderMessage("NEW_ORDER", h))
+	}
+
+	for _, h := range []string{"0x3", "0x4", "0x5"} {
+		q.Push(orderMessage("CANCEL_ORDER", h))
+	}
+
+	assert.Equal(t, []string{"3", "4", "1", "5", "2"}, popHashes(q, 5))
+}
+
+func TestOrderQueueCancelOfOwnOrder(t *testing.T) {
+	q := NewOrderQueue(0, 10)
+	q.Push(orderMessage("NEW_ORDER", "0x1"))
+	q.Push(orderMessage("NEW_ORDER", "0x2"))
+	q.Push(orderMessage("CANCEL_ORDER", "0x2"))
+	q.Push(orderMessage("CANCEL_ORDER", "0x3"))
+
+	// the cancel of 0x2 waits for its new order
+	assert.Equal(t, []string{"3", "1", "2", "2"}, popHashes(q, 4))
+
+	// the cancel of an order handled by a worker waits until it is done
+	q.Push(orderMessage("NEW_ORDER", "0x4"))
+	msg := q.Pop()
+	q.Push(orderMessage("CANCEL_ORDER", "0x4"))
+	q.Push(orderMessage("NEW_ORDER", "0x5"))
+	assert.Equal(t, []string{"5"}, popHashes(q, 1))
+
+	q.Done(msg)
+	assert.Equal(t, []string{"4"}, popHashes(q, 1))
+}
+
+func TestOrderQueueSize(t *testing.T) {
+	q := NewOrderQueue(0, 2)
+	q.Push(orderMessage("NEW_ORDER", "0x1"))
+	q.Push(orderMessage("NEW_ORDER", "0x2"))
+
+	pushed := make(chan bool)
+	go func() {
+		q.Push(orderMessage("NEW_ORDER", "0x3"))
+		close(pushed)
+	}()
+
+	select {
+	case <-pushed:
+		t.Fatal("Push did not block on a full queue")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	assert.Equal(t, []string{"1"}, popHashes(q, 1))
+	<-pushed
+	assert.Equal(t, []string{"2", "3"}, popHashes(q, 2))
+}
@@ -1,6 +1,8 @@
 package rabbitmq
 
 import (
+	"time"
+
 	"github.com/streadway/amqp"
 	"github.com/tomochain/tomox-sdk/utils"
 )
@@ -18,19 +20,44 @@ type Connection struct {
 type Message struct {
 	Type string `json:"type"`
 	Data []byte `json:"data"`
+
+	// delivery is the RabbitMQ delivery of a consumed message, acked once the message is handled
+	delivery amqp.Delivery
+}
+
+// ack acks the delivery of a consumed message
+func (m *Message) ack() {
+	if m.delivery.Acknowledger == nil {
+		return
+	}
+
+	err := m.delivery.Ack(false)
+	if err != nil {
+		logger.Error(err)
+	}
 }
 
 // InitConnection Initializes single rabbitmq connection for whole system
 func InitConnection(address string) *Connection {
+	c, err := Dial(address)
+	if err != nil {
+		panic(err)
+	}
+
+	return c
+}
+
+// Dial initializes the single rabbitmq connection and returns an error if the broker is unreachable
+func Dial(address string) (*Connection, error) {
 	if conn == nil {
 		newConn, err := amqp.Dial(address)
 		if err != nil {
-			panic(err)
+			return nil, err
 		}
 		conn = &Connection{newConn}
 	}
 
-	return conn
+	return conn, nil
 }
 
 func (c *Connection) NewConnection(address string) *amqp.Connection {
@@ -51,6 +78,7 @@ func (c *Connection) GetQueue(ch *amqp.Channel, queue string) *amqp.Queue {
 		}
 
 		queues[queue] = &q
+		watchQueue(queue)
 	}
 
 	return queues[queue]
@@ -65,6 +93,7 @@ func (c *Connection) DeclareQueue(ch *amqp.Channel, name string) error {
 		}
 
 		queues[name] = &q
+		watchQueue(name)
 	}
 
 	return nil
@@ -81,6 +110,7 @@ func (c *Connection) DeclareThrottledQueue(ch *amqp.Channel, name string) error
 		}
 
 		queues[name] = &q
+		watchQueue(name)
 	}
 
 	return nil
@@ -100,8 +130,10 @@ func (c *Connection) GetChannel(id string) *amqp.Channel {
 	return channels[id]
 }
 
-// Publish
+// Publish publishes the message to the queue, stamped with its publish time to measure the lag of the
+// consumers
 func (c *Connection) Publish(ch *amqp.Channel, q *amqp.Queue, bytes []byte) error {
+	start := time.Now()
 	err := ch.Publish(
 		"",
 		q.Name,
@@ -109,10 +141,13 @@ func (c *Connection) Publish(ch *amqp.Channel, q *amqp.Queue, bytes []byte) erro
 		false,
 		amqp.Publishing{
 			ContentType: "text/json",
+			Headers:     amqp.Table{publishedAtHeader: start.UnixNano()},
 			Body:        bytes,
 		},
 	)
 
+	observePublish(q.Name, start, err)
+
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -0,0 +1,56 @@
+package abi
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// Names of the ABIs which can be overridden
+const (
+	RelayerABI = "relayer"
+	LendingABI = "lending"
+	TokenABI   = "token"
+)
+
+// overrides are the ABIs read from the override files by name, the compiled in ABIs are used for the others
+var overrides = map[string]string{}
+
+// LoadOverrides replaces the compiled in ABIs by the JSON files of the paths, keyed by the names of
+// the ABIs, for the contracts deployed with another version. It is called once at startup
+func LoadOverrides(paths map[string]string) error {
+	loaded := map[string]string{}
+	for name, path := range paths {
+		switch name {
+		case RelayerABI, LendingABI, TokenABI:
+		default:
+			return fmt.Errorf("Unknown ABI %s", name)
+		}
+
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		_, err = abi.JSON(bytes.NewReader(b))
+		if err != nil {
+			return fmt.Errorf("Invalid %s ABI %s: %s", name, path, err)
+		}
+
+		loaded[name] = string(b)
+	}
+
+	overrides = loaded
+	return nil
+}
+
+// source returns the JSON of an ABI, its override if loaded
+func source(name, compiled string) string {
+	if s, ok := overrides[name]; ok {
+		return s
+	}
+
+	return compiled
+}
@@ -0,0 +1,40 @@
+package abi
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadOverrides(t *testing.T) {
+	dir, err := ioutil.TempDir("", "abi")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	defer LoadOverrides(nil)
+
+	path := filepath.Join(dir, "token.json")
+	err = ioutil.WriteFile(path, []byte(`[{"constant":true,"inputs":[],"name":"version","outputs":[{"name":"","type":"string"}],"type":"function"}]`), 0644)
+	assert.NoError(t, err)
+
+	assert.NoError(t, LoadOverrides(map[string]string{TokenABI: path}))
+
+	token, err := GetTokenAbi()
+	assert.NoError(t, err)
+	assert.Contains(t, token.Methods, "version")
+	assert.NotContains(t, token.Methods, "decimals")
+
+	relayer, err := GetRelayerAbi()
+	assert.NoError(t, err)
+	assert.Contains(t, relayer.Methods, "RelayerCount")
+
+	assert.Error(t, LoadOverrides(map[string]string{"exchange": path}))
+	assert.Error(t, LoadOverrides(map[string]string{TokenABI: filepath.Join(dir, "missing.json")}))
+
+	assert.NoError(t, LoadOverrides(nil))
+	token, err = GetTokenAbi()
+	assert.NoError(t, err)
+	assert.Contains(t, token.Methods, "decimals")
+}
@@ -137,6 +137,33 @@ const lendingJSON = `[
 	  "stateMutability": "view",
 	  "type": "function"
 	},
+	{
+	  "constant": true,
+	  "inputs": [
+		{
+		  "name": "token",
+		  "type": "address"
+		},
+		{
+		  "name": "lendingToken",
+		  "type": "address"
+		}
+	  ],
+	  "name": "getCollateralPrice",
+	  "outputs": [
+		{
+		  "name": "price",
+		  "type": "uint256"
+		},
+		{
+		  "name": "blockNumber",
+		  "type": "uint256"
+		}
+	  ],
+	  "payable": false,
+	  "stateMutability": "view",
+	  "type": "function"
+	},
 	{
 	  "constant": false,
 	  "inputs": [
@@ -252,10 +279,10 @@ const lendingJSON = `[
 
 // GetRelayerAbi return ABI relayer
 func GetRelayerAbi() (abi.ABI, error) {
-	return abi.JSON(strings.NewReader(abiJSON))
+	return abi.JSON(strings.NewReader(source(RelayerABI, abiJSON)))
 }
 
 // GetLendingAbi return ABI relayer
 func GetLendingAbi() (abi.ABI, error) {
-	return abi.JSON(strings.NewReader(lendingJSON))
+	return abi.JSON(strings.NewReader(source(LendingABI, lendingJSON)))
 }
@@ -367,6 +367,36 @@ const tokenAbi = `
     "type": "event",
     "signature": "0xe94479a9f7e1952cc78f2d6baab678adc1b772d936c6583def489e524cb66692"
   },
+  {
+    "constant": true,
+    "inputs": [],
+    "name": "issuer",
+    "outputs": [
+      {
+        "name": "",
+        "type": "address"
+      }
+    ],
+    "payable": false,
+    "stateMutability": "view",
+    "type": "function",
+    "signature": "0x1d143848"
+  },
+  {
+    "constant": true,
+    "inputs": [],
+    "name": "minFee",
+    "outputs": [
+      {
+        "name": "",
+        "type": "uint256"
+      }
+    ],
+    "payable": false,
+    "stateMutability": "view",
+    "type": "function",
+    "signature": "0x24ec7590"
+  },
   {
     "anonymous": false,
     "inputs": [
@@ -417,5 +447,5 @@ const tokenAbi = `
 
 // GetTokenAbi return token abi
 func GetTokenAbi() (abi.ABI, error) {
-	return abi.JSON(strings.NewReader(tokenAbi))
+	return abi.JSON(strings.NewReader(source(TokenABI, tokenAbi)))
 }
@@ -0,0 +1,113 @@
+package relayer
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// RelayerFee is the fee of a relayer listing a pair
+type RelayerFee struct {
+	Address common.Address `json:"address"`
+	MakeFee uint16         `json:"makeFee"`
+	TakeFee uint16         `json:"takeFee"`
+}
+
+// AggregatedToken is a token listed by one or more of the aggregated relayers
+type AggregatedToken struct {
+	Address  common.Address   `json:"address"`
+	Name     string           `json:"name"`
+	Symbol   string           `json:"symbol"`
+	Decimals uint8            `json:"decimals"`
+	Relayers []common.Address `json:"relayers"`
+}
+
+// AggregatedPair is a pair listed by one or more of the aggregated relayers, with the fee of each of them
+type AggregatedPair struct {
+	BaseToken        common.Address `json:"baseToken"`
+	QuoteToken       common.Address `json:"quoteToken"`
+	BaseTokenSymbol  string         `json:"baseTokenSymbol"`
+	QuoteTokenSymbol string         `json:"quoteTokenSymbol"`
+	Relayers         []*RelayerFee  `json:"relayers"`
+}
+
+// AggregatedRInfo is the merged view of the tokens and the pairs of several relayers, in the order of the relayers
+type AggregatedRInfo struct {
+	Relayers  []*RInfo           `json:"-"`
+	Coinbases []common.Address   `json:"relayers"`
+	Tokens    []*AggregatedToken `json:"tokens"`
+	Pairs     []*AggregatedPair  `json:"pairs"`
+}
+
+// Aggregate merges the tokens and the pairs of the relayers, a token or a pair listed by several
+// relayers appears once with all of them
+func Aggregate(infos []*RInfo) *AggregatedRInfo {
+	res := &AggregatedRInfo{
+		Relayers:  []*RInfo{},
+		Coinbases: []common.Address{},
+		Tokens:    []*AggregatedToken{},
+		Pairs:     []*AggregatedPair{},
+	}
+
+	tokens := make(map[common.Address]*AggregatedToken)
+	pairs := make(map[PairToken]*AggregatedPair)
+	for _, info := range infos {
+		if info == nil {
+			continue
+		}
+
+		res.Relayers = append(res.Relayers, info)
+		res.Coinbases = append(res.Coinbases, info.Address)
+
+		for _, p := range info.Pairs {
+			for _, addr := range []common.Address{p.BaseToken, p.QuoteToken} {
+				t := tokens[addr]
+				if t == nil {
+					t = &AggregatedToken{Address: addr, Relayers: []common.Address{}}
+					if ti := info.Tokens[addr]; ti != nil {
+						t.Name, t.Symbol, t.Decimals = ti.Name, ti.Symbol, ti.Decimals
+					}
+
+					tokens[addr] = t
+					res.Tokens = append(res.Tokens, t)
+				}
+
+				if len(t.Relayers) == 0 || t.Relayers[len(t.Relayers)-1] != info.Address {
+					t.Relayers = append(t.Relayers, info.Address)
+				}
+			}
+
+			ap := pairs[*p]
+			if ap == nil {
+				ap = &AggregatedPair{
+					BaseToken:        p.BaseToken,
+					QuoteToken:       p.QuoteToken,
+					BaseTokenSymbol:  tokens[p.BaseToken].Symbol,
+					QuoteTokenSymbol: tokens[p.QuoteToken].Symbol,
+					Relayers:         []*RelayerFee{},
+				}
+
+				pairs[*p] = ap
+				res.Pairs = append(res.Pairs, ap)
+			}
+
+			ap.Relayers = append(ap.Relayers, &RelayerFee{Address: info.Address, MakeFee: info.MakeFee, TakeFee: info.TakeFee})
+		}
+	}
+
+	return res
+}
+
+// GetRelayersByCoinbase reads the relayers of the coinbases, a relayer not registered has no token and no pair
+func (b *Blockchain) GetRelayersByCoinbase(ctx context.Context, coinbases []common.Address, contractAddress common.Address) ([]*RInfo, error) {
+	res := []*RInfo{}
+	for _, coinbase := range coinbases {
+		info, err := b.GetRelayer(ctx, coinbase, contractAddress)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, info)
+	}
+
+	return res, nil
+}
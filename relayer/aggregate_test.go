@@ -0,0 +1,42 @@
+package relayer
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregate(t *testing.T) {
+	r1 := common.HexToAddress("0x1")
+	r2 := common.HexToAddress("0x2")
+	tomo := common.HexToAddress("0xa")
+	usdt := common.HexToAddress("0xb")
+	btc := common.HexToAddress("0xc")
+
+	tokens := map[common.Address]*TokenInfo{
+		tomo: {Symbol: "TOMO", Decimals: 18},
+		usdt: {Symbol: "USDT", Decimals: 6},
+		btc:  {Symbol: "BTC", Decimals: 8},
+	}
+
+	res := Aggregate([]*RInfo{
+		{Address: r1, MakeFee: 10, TakeFee: 10, Tokens: tokens, Pairs: []*PairToken{{tomo, usdt}}},
+		nil,
+		{Address: r2, MakeFee: 20, TakeFee: 20, Tokens: tokens, Pairs: []*PairToken{{tomo, usdt}, {btc, usdt}}},
+	})
+
+	assert.Equal(t, []common.Address{r1, r2}, res.Coinbases)
+	assert.Len(t, res.Relayers, 2)
+
+	assert.Len(t, res.Tokens, 3)
+	assert.Equal(t, "TOMO", res.Tokens[0].Symbol)
+	assert.Equal(t, []common.Address{r1, r2}, res.Tokens[0].Relayers)
+	assert.Equal(t, []common.Address{r2}, res.Tokens[2].Relayers)
+
+	assert.Len(t, res.Pairs, 2)
+	assert.Equal(t, "TOMO", res.Pairs[0].BaseTokenSymbol)
+	assert.Equal(t, "USDT", res.Pairs[0].QuoteTokenSymbol)
+	assert.Equal(t, []*RelayerFee{{r1, 10, 10}, {r2, 20, 20}}, res.Pairs[0].Relayers)
+	assert.Equal(t, []*RelayerFee{{r2, 20, 20}}, res.Pairs[1].Relayers)
+}
@@ -0,0 +1,129 @@
+package relayer
+
+import (
+	"context"
+
+	ether "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// batchCall executes the calls in JSON-RPC batch requests of up to the batch size of the blockchain, on
+// the block of the context. The results and the errors of the calls are returned in their order, a call
+// reverted by the node fails alone. The error is the failure of a batch request, no result is returned then
+func (b *Blockchain) batchCall(ctx context.Context, calls []contractCall) ([][]byte, []error, error) {
+	size := b.batchSize
+	if size <= 0 {
+		size = len(calls)
+	}
+
+	results := make([][]byte, len(calls))
+	errs := make([]error, len(calls))
+	block := blockFromContext(ctx)
+	for start := 0; start < len(calls); start += size {
+		end := start + size
+		if end > len(calls) {
+			end = len(calls)
+		}
+
+		outputs := make([]hexutil.Bytes, end-start)
+		elems := make([]rpc.BatchElem, end-start)
+		for i, c := range calls[start:end] {
+			target := c.target
+			msg := ether.CallMsg{To: &target, Data: c.data}
+			elems[i] = rpc.BatchElem{
+				Method: "eth_call",
+				Args:   []interface{}{callArg(msg), block.arg()},
+				Result: &outputs[i],
+			}
+		}
+
+		err := b.withBreaker(ctx, func() error {
+			return b.sendBatch(ctx, elems)
+		})
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for i := range elems {
+			results[start+i] = outputs[i]
+			errs[start+i] = elems[i].Error
+		}
+	}
+
+	return results, errs, nil
+}
+
+// sendBatch sends the batch request to the pool of endpoints if set, or the client of the blockchain
+func (b *Blockchain) sendBatch(ctx context.Context, elems []rpc.BatchElem) error {
+	if b.pool != nil {
+		return b.pool.BatchCall(ctx, elems)
+	}
+
+	ctx, cancel := b.withTimeout(ctx)
+	defer cancel()
+
+	return b.client.BatchCallContext(ctx, elems)
+}
+
+// getTokensInfoBatched reads the metadata and the fee parameters of the tokens into res with JSON-RPC
+// batch requests, the tokens not read are returned. The fee parameters revert for a TRC20 token, which
+// keeps its metadata
+func (b *Blockchain) getTokensInfoBatched(ctx context.Context, tokens []common.Address, abi *abi.ABI, res map[common.Address]*TokenInfo) []common.Address {
+	methods := append(append([]string{}, tokenInfoMethods...), trc21InfoMethods...)
+	calls := []contractCall{}
+	for _, t := range tokens {
+		for _, m := range methods {
+			input, err := abi.Pack(m)
+			if err != nil {
+				return tokens
+			}
+
+			calls = append(calls, contractCall{t, input})
+		}
+	}
+
+	results, errs, err := b.batchCall(ctx, calls)
+	if err != nil {
+		logger.Warning("Batch request failed, reading the tokens one by one:", err)
+		return tokens
+	}
+
+	failed := []common.Address{}
+	for i, t := range tokens {
+		first := i * len(methods)
+		info := results[first : first+len(tokenInfoMethods)]
+		trc21 := results[first+len(tokenInfoMethods) : first+len(methods)]
+		if firstError(errs[first:first+len(tokenInfoMethods)]) != nil {
+			failed = append(failed, t)
+			continue
+		}
+
+		tokenInfo, err := unpackTokenInfo(abi, info)
+		if err != nil {
+			failed = append(failed, t)
+			continue
+		}
+
+		if firstError(errs[first+len(tokenInfoMethods):first+len(methods)]) == nil {
+			unpackTRC21Info(abi, trc21, tokenInfo)
+		}
+
+		res[t] = tokenInfo
+	}
+
+	return failed
+}
+
+func firstError(errs []error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package relayer
+
+import (
+	"context"
+	"math/big"
+
+	ether "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// BlockRef is the block whose state the contract calls read, by number or by hash. The zero BlockRef is
+// the latest block. Reading the state of an old block requires an archive node, and reading it by hash
+// a node supporting EIP-1898
+type BlockRef struct {
+	Number *big.Int    `json:"number,omitempty"`
+	Hash   common.Hash `json:"hash,omitempty"`
+}
+
+// AtBlockNumber returns the reference of the block of a number
+func AtBlockNumber(number *big.Int) BlockRef {
+	return BlockRef{Number: number}
+}
+
+// AtBlockHash returns the reference of the block of a hash
+func AtBlockHash(hash common.Hash) BlockRef {
+	return BlockRef{Hash: hash}
+}
+
+// IsLatest returns true if the reference is the latest block
+func (b BlockRef) IsLatest() bool {
+	return b.Number == nil && b.Hash == (common.Hash{})
+}
+
+// arg returns the block parameter of eth_call
+func (b BlockRef) arg() interface{} {
+	if b.Hash != (common.Hash{}) {
+		return map[string]interface{}{"blockHash": b.Hash}
+	}
+
+	if b.Number != nil {
+		return hexutil.EncodeBig(b.Number)
+	}
+
+	return "latest"
+}
+
+type blockRefKey struct{}
+
+// WithBlock returns a context whose contract calls read the state of the block, the methods of
+// Blockchain called with it return the registrations of the relayers at that block
+func WithBlock(ctx context.Context, block BlockRef) context.Context {
+	return context.WithValue(ctx, blockRefKey{}, block)
+}
+
+// blockFromContext returns the block of the contract calls of the context, the latest one if not set
+func blockFromContext(ctx context.Context) BlockRef {
+	block, _ := ctx.Value(blockRefKey{}).(BlockRef)
+	return block
+}
+
+// callAt executes eth_call on the state of the block
+func callAt(ctx context.Context, client *rpc.Client, msg ether.CallMsg, block BlockRef) ([]byte, error) {
+	var res hexutil.Bytes
+	err := client.CallContext(ctx, &res, "eth_call", callArg(msg), block.arg())
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// callArg returns the call parameter of eth_call
+func callArg(msg ether.CallMsg) interface{} {
+	arg := map[string]interface{}{
+		"from": msg.From,
+		"to":   msg.To,
+	}
+
+	if len(msg.Data) > 0 {
+		arg["data"] = hexutil.Bytes(msg.Data)
+	}
+
+	if msg.Value != nil {
+		arg["value"] = (*hexutil.Big)(msg.Value)
+	}
+
+	if msg.Gas != 0 {
+		arg["gas"] = hexutil.Uint64(msg.Gas)
+	}
+
+	if msg.GasPrice != nil {
+		arg["gasPrice"] = (*hexutil.Big)(msg.GasPrice)
+	}
+
+	return arg
+}
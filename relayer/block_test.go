@@ -0,0 +1,47 @@
+package relayer
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	ether "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCallAtBlock(t *testing.T) {
+	var block interface{}
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&req)
+		block = req["params"].([]interface{})[1]
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req["id"], "result": "0x01"})
+	}))
+	defer node.Close()
+
+	to := common.HexToAddress("0x1")
+	msg := ether.CallMsg{To: &to, Data: []byte{1}}
+	p := NewRPCPool([]string{node.URL}, 1, time.Millisecond, time.Millisecond, 0)
+
+	res, err := p.CallContract(context.Background(), msg)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{1}, res)
+	assert.Equal(t, "latest", block)
+
+	res, err = p.CallContract(WithBlock(context.Background(), AtBlockNumber(big.NewInt(255))), msg)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{1}, res)
+	assert.Equal(t, "0xff", block)
+
+	hash := common.HexToHash("0xabc")
+	_, err = p.CallContract(WithBlock(context.Background(), AtBlockHash(hash)), msg)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"blockHash": hash.Hex()}, block)
+}
@@ -0,0 +1,135 @@
+package relayer
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// States of the circuit breaker of the contract calls
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half-open"
+)
+
+// Defaults of the circuit breaker
+const (
+	defaultBreakerFailures = 5
+	defaultBreakerCooldown = 30 * time.Second
+)
+
+// ErrCircuitOpen is returned by the contract calls refused while the nodes are failing
+var ErrCircuitOpen = errors.New("RPC circuit open, the nodes are failing")
+
+// CircuitBreaker stops the contract calls once the nodes failed several calls in a row, so that a failing
+// node is not hammered. The circuit opens after the consecutive failures and refuses the calls for the
+// cooldown, it then half-opens: a single probe call at a time goes through, the circuit closes on its
+// success and opens again on its failure. The errors returned by a node, such as a reverted call, are
+// answers and do not count as failures
+type CircuitBreaker struct {
+	failures int
+	cooldown time.Duration
+	mutex    sync.Mutex
+	state    string
+	count    int
+	openedAt time.Time
+	probing  bool
+	now      func() time.Time
+}
+
+// NewCircuitBreaker returns a closed circuit breaker, the zero failures and cooldown take their defaults
+func NewCircuitBreaker(failures int, cooldown time.Duration) *CircuitBreaker {
+	if failures <= 0 {
+		failures = defaultBreakerFailures
+	}
+
+	if cooldown <= 0 {
+		cooldown = defaultBreakerCooldown
+	}
+
+	return &CircuitBreaker{
+		failures: failures,
+		cooldown: cooldown,
+		state:    BreakerClosed,
+		now:      time.Now,
+	}
+}
+
+// Allow returns true if a call may be sent. Once the cooldown is over the first call is let through
+// as the probe of the half-open circuit, the other ones are refused until it is done
+func (c *CircuitBreaker) Allow() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	switch c.state {
+	case BreakerOpen:
+		if c.now().Sub(c.openedAt) < c.cooldown {
+			return false
+		}
+
+		logger.Info("RPC circuit half-open, probing the nodes")
+		c.state = BreakerHalfOpen
+		c.probing = true
+		return true
+	case BreakerHalfOpen:
+		if c.probing {
+			return false
+		}
+
+		c.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// Success records a call answered by a node, the circuit closes
+func (c *CircuitBreaker) Success() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.state != BreakerClosed {
+		logger.Info("RPC circuit closed, the nodes answer again")
+	}
+
+	c.state = BreakerClosed
+	c.count = 0
+	c.probing = false
+}
+
+// Failure records a call not answered, the circuit opens after the consecutive failures or when the
+// probe of the half-open circuit fails
+func (c *CircuitBreaker) Failure() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.count++
+	c.probing = false
+	if c.state == BreakerHalfOpen || (c.state == BreakerClosed && c.count >= c.failures) {
+		logger.Warningf("RPC circuit open after %d failures, calls refused for %s", c.count, c.cooldown)
+		c.state = BreakerOpen
+		c.openedAt = c.now()
+	}
+}
+
+// release ends a call cancelled by its caller without an answer, it counts neither as a success nor as
+// a failure and lets another probe through a half-open circuit
+func (c *CircuitBreaker) release() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.probing = false
+}
+
+// State returns the state of the circuit, an open circuit whose cooldown is over is half-open
+func (c *CircuitBreaker) State() string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.state == BreakerOpen && c.now().Sub(c.openedAt) >= c.cooldown {
+		return BreakerHalfOpen
+	}
+
+	return c.state
+}
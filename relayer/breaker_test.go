@@ -0,0 +1,97 @@
+package relayer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ether "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Unix(1000, 0)
+	c := NewCircuitBreaker(2, time.Minute)
+	c.now = func() time.Time { return now }
+
+	c.Failure()
+	assert.True(t, c.Allow())
+	c.Success()
+	c.Failure()
+	assert.Equal(t, BreakerClosed, c.State())
+
+	// the circuit opens after the failures in a row
+	c.Failure()
+	assert.Equal(t, BreakerOpen, c.State())
+	assert.False(t, c.Allow())
+
+	// a single probe goes through once the cooldown is over, its failure opens the circuit again
+	now = now.Add(time.Minute)
+	assert.Equal(t, BreakerHalfOpen, c.State())
+	assert.True(t, c.Allow())
+	assert.False(t, c.Allow())
+	c.Failure()
+	assert.Equal(t, BreakerOpen, c.State())
+	assert.False(t, c.Allow())
+
+	// the success of the probe closes the circuit
+	now = now.Add(time.Minute)
+	assert.True(t, c.Allow())
+	c.Success()
+	assert.Equal(t, BreakerClosed, c.State())
+	assert.True(t, c.Allow())
+}
+
+func TestCircuitBreakerCalls(t *testing.T) {
+	var downCalls, revertCalls int32
+	down := downServer(&downCalls)
+	defer down.Close()
+	revert := rpcServer("", "execution reverted", &revertCalls)
+	defer revert.Close()
+
+	to := common.HexToAddress("0x1")
+	msg := ether.CallMsg{To: &to}
+
+	// the errors of a node answering do not open the circuit
+	bc := NewBlockchain(nil, nil, nil)
+	bc.pool = NewRPCPool([]string{revert.URL}, 1, time.Millisecond, time.Millisecond, 0)
+	bc.breaker = NewCircuitBreaker(2, time.Minute)
+	for i := 0; i < 3; i++ {
+		_, err := bc.callContract(context.Background(), msg)
+		assert.EqualError(t, err, "execution reverted")
+	}
+
+	assert.Equal(t, BreakerClosed, bc.breaker.State())
+
+	// the node failing is not called once the circuit is open
+	bc.pool = NewRPCPool([]string{down.URL}, 1, time.Millisecond, time.Millisecond, 0)
+	for i := 0; i < 3; i++ {
+		bc.callContract(context.Background(), msg)
+	}
+
+	_, err := bc.callContract(context.Background(), msg)
+	assert.Equal(t, ErrCircuitOpen, err)
+	assert.Equal(t, int32(2), downCalls)
+}
+
+func TestRelayerCachedRelayers(t *testing.T) {
+	r := NewRelayer(nil, "", common.Address{}, common.Address{}, common.Address{}, common.Address{}, 0, 0, 0)
+	a := &RInfo{RID: 2, Address: common.HexToAddress("0xa")}
+	b := &RInfo{RID: 1, Address: common.HexToAddress("0xb")}
+
+	_, ok := r.cachedRelayers(nil)
+	assert.False(t, ok)
+
+	r.keepRelayers(a, nil, b)
+	infos, ok := r.cachedRelayers(nil)
+	assert.True(t, ok)
+	assert.Equal(t, []*RInfo{b, a}, infos)
+
+	infos, ok = r.cachedRelayers([]common.Address{a.Address})
+	assert.True(t, ok)
+	assert.Equal(t, []*RInfo{a}, infos)
+
+	_, ok = r.cachedRelayers([]common.Address{a.Address, common.HexToAddress("0xc")})
+	assert.False(t, ok)
+}
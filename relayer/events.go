@@ -0,0 +1,288 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	ether "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	relayerAbi "github.com/tomochain/tomox-sdk/relayer/abi"
+)
+
+// relayerEvents are the events of the registration contract changing the tokens or the pairs of a relayer
+var relayerEvents = []string{"RegisterEvent", "UpdateEvent", "TransferEvent", "ResignEvent", "RefundEvent"}
+
+var errNoCoinbase = errors.New("No relayer coinbase in the transaction")
+
+const (
+	// logBackfillRange is the number of blocks whose logs are read by a call when catching up
+	logBackfillRange = 5000
+	// maxSeenLogs is the number of delivered logs remembered to suppress the duplicates
+	maxSeenLogs = 10000
+)
+
+// LogCursor persists the last block whose logs were processed by a watcher, so that the logs missed
+// while it is disconnected are read again when it reconnects. 0 is returned when none was processed
+type LogCursor interface {
+	LastProcessedBlock() (uint64, error)
+	SaveProcessedBlock(block uint64) error
+}
+
+// logKey identifies a log, a log is delivered by the subscription and by the backfill of the same block
+type logKey struct {
+	tx    common.Hash
+	index uint
+}
+
+// seenLogs remembers the logs delivered by a watcher, the ones of the oldest blocks are forgotten first
+type seenLogs map[logKey]uint64
+
+// add records a log, false is returned if it was already delivered
+func (s seenLogs) add(l types.Log) bool {
+	k := logKey{l.TxHash, l.Index}
+	if _, ok := s[k]; ok {
+		return false
+	}
+
+	if len(s) >= maxSeenLogs {
+		oldest := l.BlockNumber
+		for _, b := range s {
+			if b < oldest {
+				oldest = b
+			}
+		}
+
+		for k, b := range s {
+			if b == oldest {
+				delete(s, k)
+			}
+		}
+	}
+
+	s[k] = l.BlockNumber
+	return true
+}
+
+// RInfoDiff is the change of a relayer found from an event of the registration contract,
+// Relayer is the state of the relayer after the change
+type RInfoDiff struct {
+	Address       common.Address
+	Relayer       *RInfo
+	AddedTokens   []common.Address
+	RemovedTokens []common.Address
+	AddedPairs    []*PairToken
+	RemovedPairs  []*PairToken
+	StatusChanged bool
+	FeesChanged   bool
+}
+
+// Empty returns true if the tokens, the pairs, the status and the fees of the relayer did not change
+func (d *RInfoDiff) Empty() bool {
+	return len(d.AddedTokens) == 0 && len(d.RemovedTokens) == 0 && len(d.AddedPairs) == 0 && len(d.RemovedPairs) == 0 &&
+		!d.StatusChanged && !d.FeesChanged
+}
+
+// DiffRInfo returns the tokens and the pairs added and removed from the previous state of a relayer,
+// a nil previous state is a new relayer
+func DiffRInfo(prev, cur *RInfo) *RInfoDiff {
+	d := &RInfoDiff{Address: cur.Address, Relayer: cur}
+	if prev == nil {
+		prev = &RInfo{}
+	}
+
+	for t := range cur.Tokens {
+		if _, ok := prev.Tokens[t]; !ok {
+			d.AddedTokens = append(d.AddedTokens, t)
+		}
+	}
+
+	for t := range prev.Tokens {
+		if _, ok := cur.Tokens[t]; !ok {
+			d.RemovedTokens = append(d.RemovedTokens, t)
+		}
+	}
+
+	d.AddedPairs = missingPairs(cur.Pairs, prev.Pairs)
+	d.RemovedPairs = missingPairs(prev.Pairs, cur.Pairs)
+	d.StatusChanged = prev.Status != cur.Status
+	d.FeesChanged = prev.MakeFee != cur.MakeFee || prev.TakeFee != cur.TakeFee
+	return d
+}
+
+// missingPairs returns the pairs of a not in b
+func missingPairs(a, b []*PairToken) []*PairToken {
+	res := []*PairToken{}
+	for _, p := range a {
+		found := false
+		for _, q := range b {
+			if p.BaseToken == q.BaseToken && p.QuoteToken == q.QuoteToken {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			res = append(res, p)
+		}
+	}
+
+	return res
+}
+
+// WatchRelayers subscribes to the events of the registration contract and calls the handler with the
+// changes of the relayer of each event. The events carry no coinbase, it is read from the call of the
+// contract emitting them and the relayer is read again. The events emitted from the block from, if not 0,
+// are read first, the subscription is opened before so that none is missed and the events delivered twice
+// are skipped. The block of each event processed is saved to the cursor if set. The known relayers are
+// updated, the call returns when the subscription fails
+func (b *Blockchain) WatchRelayers(ctx context.Context, contractAddress common.Address, known map[common.Address]*RInfo, from uint64, cursor LogCursor, handler func(*RInfoDiff)) error {
+	abiRelayer, err := relayerAbi.GetRelayerAbi()
+	if err != nil {
+		return err
+	}
+
+	topics := []common.Hash{}
+	for _, name := range relayerEvents {
+		topics = append(topics, abiRelayer.Events[name].Id())
+	}
+
+	q := ether.FilterQuery{
+		Addresses: []common.Address{contractAddress},
+		Topics:    [][]common.Hash{topics},
+	}
+
+	logs := make(chan types.Log, 128)
+	sub, err := b.ethclient.SubscribeFilterLogs(ctx, q, logs)
+	if err != nil {
+		return err
+	}
+
+	defer sub.Unsubscribe()
+
+	seen := seenLogs{}
+	process := func(l types.Log) {
+		if l.Removed || !seen.add(l) {
+			return
+		}
+
+		defer saveProcessedBlock(cursor, l.BlockNumber)
+
+		coinbase, err := b.eventCoinbase(ctx, &abiRelayer, l.TxHash)
+		if err != nil {
+			logger.Warning("Relayer event ignored:", l.TxHash.Hex(), err)
+			return
+		}
+
+		info, err := b.GetRelayer(ctx, coinbase, contractAddress)
+		if err != nil {
+			logger.Error("Relayer not read after its event:", coinbase.Hex(), err)
+			return
+		}
+
+		d := DiffRInfo(known[coinbase], info)
+		known[coinbase] = info
+		if !d.Empty() {
+			handler(d)
+		}
+	}
+
+	if from > 0 {
+		err = b.backfillLogs(ctx, q, from, process)
+		if err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-sub.Err():
+			return err
+		case l := <-logs:
+			process(l)
+		}
+	}
+}
+
+// backfillLogs calls process with the logs of the query emitted from a block up to the head of the chain,
+// oldest first, read by ranges of blocks
+func (b *Blockchain) backfillLogs(ctx context.Context, q ether.FilterQuery, from uint64, process func(types.Log)) error {
+	head, err := b.ethclient.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	to := head.Number.Uint64()
+	if from > to {
+		return nil
+	}
+
+	logger.Infof("Reading the relayer events of the blocks %d to %d", from, to)
+	for start := from; start <= to; start += logBackfillRange {
+		end := start + logBackfillRange - 1
+		if end > to {
+			end = to
+		}
+
+		q.FromBlock = new(big.Int).SetUint64(start)
+		q.ToBlock = new(big.Int).SetUint64(end)
+		logs, err := b.ethclient.FilterLogs(ctx, q)
+		if err != nil {
+			return err
+		}
+
+		for _, l := range logs {
+			process(l)
+		}
+	}
+
+	return nil
+}
+
+// saveProcessedBlock moves the cursor to a block, the failures are logged as the block is saved again
+// with the next event
+func saveProcessedBlock(cursor LogCursor, block uint64) {
+	if cursor == nil {
+		return
+	}
+
+	err := cursor.SaveProcessedBlock(block)
+	if err != nil {
+		logger.Warning("Last processed block not saved:", block, err)
+	}
+}
+
+// eventCoinbase returns the coinbase of the relayer changed by a transaction, the first
+// argument of all the methods of the registration contract emitting the relayer events
+func (b *Blockchain) eventCoinbase(ctx context.Context, abiRelayer *abi.ABI, hash common.Hash) (common.Address, error) {
+	ctx, cancel := b.withTimeout(ctx)
+	defer cancel()
+
+	tx, _, err := b.ethclient.TransactionByHash(ctx, hash)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	return coinbaseFromInput(abiRelayer, tx.Data())
+}
+
+func coinbaseFromInput(abiRelayer *abi.ABI, data []byte) (common.Address, error) {
+	if len(data) < 4+32 {
+		return common.Address{}, errNoCoinbase
+	}
+
+	method, err := abiRelayer.MethodById(data[:4])
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	if len(method.Inputs) == 0 || method.Inputs[0].Name != "coinbase" {
+		return common.Address{}, errNoCoinbase
+	}
+
+	return common.BytesToAddress(data[4:36]), nil
+}
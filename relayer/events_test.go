@@ -0,0 +1,101 @@
+package relayer
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	relayerAbi "github.com/tomochain/tomox-sdk/relayer/abi"
+)
+
+func TestDiffRInfo(t *testing.T) {
+	coinbase := common.HexToAddress("0x1")
+	a := common.HexToAddress("0xa")
+	b := common.HexToAddress("0xb")
+	c := common.HexToAddress("0xc")
+
+	prev := &RInfo{
+		Address: coinbase,
+		Tokens:  map[common.Address]*TokenInfo{a: {}, b: {}},
+		Pairs:   []*PairToken{{a, b}},
+	}
+
+	d := DiffRInfo(nil, prev)
+	assert.ElementsMatch(t, []common.Address{a, b}, d.AddedTokens)
+	assert.Equal(t, []*PairToken{{a, b}}, d.AddedPairs)
+
+	assert.True(t, DiffRInfo(prev, prev).Empty())
+
+	cur := &RInfo{
+		Address: coinbase,
+		Tokens:  map[common.Address]*TokenInfo{a: {}, c: {}},
+		Pairs:   []*PairToken{{c, a}},
+	}
+
+	d = DiffRInfo(prev, cur)
+	assert.Equal(t, coinbase, d.Address)
+	assert.Equal(t, []common.Address{c}, d.AddedTokens)
+	assert.Equal(t, []common.Address{b}, d.RemovedTokens)
+	assert.Equal(t, []*PairToken{{c, a}}, d.AddedPairs)
+	assert.Equal(t, []*PairToken{{a, b}}, d.RemovedPairs)
+	assert.False(t, d.StatusChanged)
+
+	resigned := &RInfo{Address: coinbase, Tokens: prev.Tokens, Pairs: prev.Pairs, Status: RelayerStatusResigning}
+	d = DiffRInfo(prev, resigned)
+	assert.True(t, d.StatusChanged)
+	assert.False(t, d.Empty())
+
+	repriced := &RInfo{Address: coinbase, Tokens: prev.Tokens, Pairs: prev.Pairs, MakeFee: 10, TakeFee: 20}
+	d = DiffRInfo(prev, repriced)
+	assert.True(t, d.FeesChanged)
+	assert.False(t, d.StatusChanged)
+	assert.False(t, d.Empty())
+}
+
+func TestRelayerStatus(t *testing.T) {
+	now := time.Now()
+
+	assert.Equal(t, RelayerStatusActive, RelayerStatus(0, now))
+	assert.Equal(t, RelayerStatusResigning, RelayerStatus(now.Add(time.Hour).Unix(), now))
+	assert.Equal(t, RelayerStatusExpired, RelayerStatus(now.Add(-time.Hour).Unix(), now))
+}
+
+func TestCoinbaseFromInput(t *testing.T) {
+	abiRelayer, err := relayerAbi.GetRelayerAbi()
+	assert.Nil(t, err)
+
+	coinbase := common.HexToAddress("0x1")
+	input, err := abiRelayer.Pack("update", coinbase, uint16(10), []common.Address{}, []common.Address{})
+	assert.Nil(t, err)
+
+	res, err := coinbaseFromInput(&abiRelayer, input)
+	assert.Nil(t, err)
+	assert.Equal(t, coinbase, res)
+
+	input, err = abiRelayer.Pack("reconfigure", common.Big1, common.Big1, common.Big1)
+	assert.Nil(t, err)
+
+	_, err = coinbaseFromInput(&abiRelayer, input)
+	assert.Equal(t, errNoCoinbase, err)
+}
+
+func TestSeenLogs(t *testing.T) {
+	seen := seenLogs{}
+	l := types.Log{TxHash: common.HexToHash("0x1"), Index: 0, BlockNumber: 10}
+
+	assert.True(t, seen.add(l))
+	assert.False(t, seen.add(l))
+
+	l.Index = 1
+	assert.True(t, seen.add(l))
+
+	for i := 0; i < maxSeenLogs; i++ {
+		seen.add(types.Log{TxHash: common.BigToHash(big.NewInt(int64(i + 2))), BlockNumber: 11})
+	}
+
+	assert.True(t, len(seen) <= maxSeenLogs)
+	assert.True(t, seen.add(types.Log{TxHash: common.HexToHash("0x1"), Index: 0, BlockNumber: 10}))
+}
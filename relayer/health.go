@@ -0,0 +1,135 @@
+package relayer
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ChainHealth is the status of the connection of the SDK to the chain: the sync status, the chain ID and
+// the latest block of the node, whether the registration contract answers a call and the state of the
+// circuit breaker of the calls. The checks which
+// failed are listed in Errors, their fields keep their zero values
+type ChainHealth struct {
+	Endpoint          string   `json:"endpoint"`
+	ChainID           *big.Int `json:"chainId"`
+	Syncing           bool     `json:"syncing"`
+	CurrentBlock      uint64   `json:"currentBlock"`
+	HighestBlock      uint64   `json:"highestBlock"`
+	LatestBlock       uint64   `json:"latestBlock"`
+	LatestBlockTime   int64    `json:"latestBlockTime"`
+	LatestBlockAge    int64    `json:"latestBlockAge"`
+	ContractReachable bool     `json:"contractReachable"`
+	RelayerCount      *big.Int `json:"relayerCount"`
+	Breaker           string   `json:"breaker,omitempty"`
+	Errors            []string `json:"errors,omitempty"`
+}
+
+// IsHealthy returns true if all the checks passed, the node is synced and its latest block is at most
+// maxAge seconds old
+func (h *ChainHealth) IsHealthy(maxAge int64) bool {
+	return len(h.Errors) == 0 && !h.Syncing && h.ContractReachable && h.LatestBlockAge <= maxAge
+}
+
+// HealthCheck checks the node answering the calls of the blockchain, the endpoint which answered last
+// for a pool, and calls RelayerCount on the registration contract. Every request is bounded by the
+// timeout of the blockchain
+func (b *Blockchain) HealthCheck(ctx context.Context, contractAddress common.Address) *ChainHealth {
+	h := &ChainHealth{}
+	fail := func(err error) {
+		h.Errors = append(h.Errors, err.Error())
+	}
+
+	client := b.client
+	if b.pool != nil {
+		var err error
+		h.Endpoint, client, err = b.pool.Endpoint()
+		if err != nil {
+			fail(err)
+		}
+	}
+
+	if client != nil {
+		b.checkNode(ctx, client, h, fail)
+	}
+
+	if b.breaker != nil {
+		h.Breaker = b.breaker.State()
+	}
+
+	count, err := b.GetRelayerCount(ctx, contractAddress)
+	if err != nil {
+		fail(err)
+	} else {
+		h.ContractReachable = true
+		h.RelayerCount = count
+	}
+
+	return h
+}
+
+// checkNode reads the chain ID, the sync status and the latest block of the node
+func (b *Blockchain) checkNode(ctx context.Context, client *rpc.Client, h *ChainHealth, fail func(error)) {
+	eth := ethclient.NewClient(client)
+
+	chainID, err := b.chainID(ctx, client, eth)
+	if err != nil {
+		fail(err)
+	} else {
+		h.ChainID = chainID
+	}
+
+	syncCtx, cancel := b.withTimeout(ctx)
+	progress, err := eth.SyncProgress(syncCtx)
+	cancel()
+	if err != nil {
+		fail(err)
+	} else if progress != nil {
+		h.Syncing = true
+		h.CurrentBlock = progress.CurrentBlock
+		h.HighestBlock = progress.HighestBlock
+	}
+
+	headerCtx, cancel := b.withTimeout(ctx)
+	header, err := eth.HeaderByNumber(headerCtx, nil)
+	cancel()
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	h.LatestBlock = header.Number.Uint64()
+	h.LatestBlockTime = header.Time.Int64()
+	h.LatestBlockAge = time.Now().Unix() - h.LatestBlockTime
+	if h.LatestBlockAge < 0 {
+		h.LatestBlockAge = 0
+	}
+
+	if !h.Syncing {
+		h.CurrentBlock = h.LatestBlock
+		h.HighestBlock = h.LatestBlock
+	}
+}
+
+// chainID returns the result of eth_chainId, or the network ID for the nodes without this method
+func (b *Blockchain) chainID(ctx context.Context, client *rpc.Client, eth *ethclient.Client) (*big.Int, error) {
+	ctx, cancel := b.withTimeout(ctx)
+	defer cancel()
+
+	var res hexutil.Big
+	err := client.CallContext(ctx, &res, "eth_chainId")
+	if err == nil {
+		return (*big.Int)(&res), nil
+	}
+
+	if _, ok := err.(rpc.Error); !ok {
+		return nil, err
+	}
+
+	return eth.NetworkID(ctx)
+}
@@ -0,0 +1,114 @@
+package relayer
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// nodeServer answers the requests of the health check, the methods missing from the results are not
+// supported by the node
+func nodeServer(results map[string]interface{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}{}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		res := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		if result, ok := results[req.Method]; ok {
+			res["result"] = result
+		} else {
+			res["error"] = map[string]interface{}{"code": -32601, "message": "the method " + req.Method + " does not exist"}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(res)
+	}))
+}
+
+func nodeHeader(number int64, timestamp int64) map[string]interface{} {
+	zero := common.Hash{}.Hex()
+	return map[string]interface{}{
+		"parentHash":       zero,
+		"sha3Uncles":       zero,
+		"miner":            common.Address{}.Hex(),
+		"stateRoot":        zero,
+		"transactionsRoot": zero,
+		"receiptsRoot":     zero,
+		"logsBloom":        hexutil.Encode(make([]byte, 256)),
+		"difficulty":       "0x1",
+		"number":           hexutil.EncodeBig(big.NewInt(number)),
+		"gasLimit":         "0x1",
+		"gasUsed":          "0x0",
+		"timestamp":        hexutil.EncodeBig(big.NewInt(timestamp)),
+		"extraData":        "0x",
+		"mixHash":          zero,
+		"nonce":            "0x0000000000000000",
+	}
+}
+
+func TestHealthCheck(t *testing.T) {
+	now := time.Now().Unix()
+	server := nodeServer(map[string]interface{}{
+		"eth_chainId":          "0x58",
+		"eth_syncing":          false,
+		"eth_getBlockByNumber": nodeHeader(100, now-2),
+		"eth_call":             hexutil.Encode(word(3)),
+	})
+	defer server.Close()
+
+	bc := NewBlockchain(nil, nil, nil)
+	bc.pool = NewRPCPool([]string{server.URL}, 1, time.Millisecond, time.Millisecond, 0)
+
+	h := bc.HealthCheck(context.Background(), common.HexToAddress("0x1"))
+	assert.Empty(t, h.Errors)
+	assert.Equal(t, server.URL, h.Endpoint)
+	assert.Equal(t, big.NewInt(88), h.ChainID)
+	assert.False(t, h.Syncing)
+	assert.Equal(t, uint64(100), h.LatestBlock)
+	assert.Equal(t, uint64(100), h.HighestBlock)
+	assert.True(t, h.LatestBlockAge >= 2)
+	assert.True(t, h.ContractReachable)
+	assert.Equal(t, big.NewInt(3), h.RelayerCount)
+	assert.True(t, h.IsHealthy(60))
+	assert.False(t, h.IsHealthy(1))
+}
+
+func TestHealthCheckSyncingNode(t *testing.T) {
+	now := time.Now().Unix()
+	server := nodeServer(map[string]interface{}{
+		"net_version": "89",
+		"eth_syncing": map[string]interface{}{
+			"startingBlock": "0x0",
+			"currentBlock":  "0x64",
+			"highestBlock":  "0xc8",
+			"pulledStates":  "0x0",
+			"knownStates":   "0x0",
+		},
+		"eth_getBlockByNumber": nodeHeader(100, now),
+	})
+	defer server.Close()
+
+	bc := NewBlockchain(nil, nil, nil)
+	bc.pool = NewRPCPool([]string{server.URL}, 1, time.Millisecond, time.Millisecond, 0)
+
+	// the network ID is reported for the nodes without eth_chainId, the contract call fails
+	h := bc.HealthCheck(context.Background(), common.HexToAddress("0x1"))
+	assert.Equal(t, big.NewInt(89), h.ChainID)
+	assert.True(t, h.Syncing)
+	assert.Equal(t, uint64(100), h.CurrentBlock)
+	assert.Equal(t, uint64(200), h.HighestBlock)
+	assert.False(t, h.ContractReachable)
+	assert.Len(t, h.Errors, 1)
+	assert.False(t, h.IsHealthy(60))
+}
@@ -0,0 +1,346 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+
+	ether "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tomochain/tomox-sdk/utils"
+)
+
+// aggregateSelector is the selector of aggregate((address,bytes)[]) of the Multicall contract.
+// The ABI package does not handle tuples so the call is encoded here
+var aggregateSelector = crypto.Keccak256([]byte("aggregate((address,bytes)[])"))[:4]
+
+var errInvalidAggregate = errors.New("Invalid multicall result")
+
+// defaultTokenInfoConcurrency is the number of tokens whose metadata is read at the same time without multicall
+const defaultTokenInfoConcurrency = 8
+
+// tokenInfoMethods are the reads of the metadata of a token, in the order of their results
+var tokenInfoMethods = []string{"name", "symbol", "decimals"}
+
+// trc21InfoMethods are the reads of the fee parameters of a TRC21 token, which revert for a TRC20 token
+var trc21InfoMethods = []string{"issuer", "minFee"}
+
+// contractCall is a read call of a contract aggregated by the Multicall contract
+type contractCall struct {
+	target common.Address
+	data   []byte
+}
+
+// packAggregate encodes the call of aggregate with the calls
+func packAggregate(calls []contractCall) []byte {
+	tuples := [][]byte{}
+	for _, c := range calls {
+		t := common.LeftPadBytes(c.target.Bytes(), 32)
+		t = append(t, word(64)...)
+		t = append(t, word(len(c.data))...)
+		t = append(t, common.RightPadBytes(c.data, (len(c.data)+31)/32*32)...)
+		tuples = append(tuples, t)
+	}
+
+	input := append([]byte{}, aggregateSelector...)
+	input = append(input, word(32)...)
+	input = append(input, word(len(calls))...)
+
+	offset := 32 * len(calls)
+	for _, t := range tuples {
+		input = append(input, word(offset)...)
+		offset += len(t)
+	}
+
+	for _, t := range tuples {
+		input = append(input, t...)
+	}
+
+	return input
+}
+
+// unpackAggregate decodes the results of aggregate, (uint256 blockNumber, bytes[] returnData)
+func unpackAggregate(output []byte, n int) ([][]byte, error) {
+	array, ok := readWord(output, 32)
+	if !ok {
+		return nil, errInvalidAggregate
+	}
+
+	length, ok := readWord(output, array)
+	if !ok || length != n {
+		return nil, errInvalidAggregate
+	}
+
+	start := array + 32
+	res := [][]byte{}
+	for i := 0; i < n; i++ {
+		offset, ok := readWord(output, start+32*i)
+		if !ok {
+			return nil, errInvalidAggregate
+		}
+
+		size, ok := readWord(output, start+offset)
+		if !ok || start+offset+32+size > len(output) {
+			return nil, errInvalidAggregate
+		}
+
+		res = append(res, output[start+offset+32:start+offset+32+size])
+	}
+
+	return res, nil
+}
+
+func word(v int) []byte {
+	return common.LeftPadBytes(big.NewInt(int64(v)).Bytes(), 32)
+}
+
+// readWord reads a word at a position as an int, false is returned if it is out of the output
+// or too large to be a length or an offset of it
+func readWord(output []byte, pos int) (int, bool) {
+	if pos < 0 || pos+32 > len(output) {
+		return 0, false
+	}
+
+	v := new(big.Int).SetBytes(output[pos : pos+32])
+	if !v.IsInt64() || v.Int64() > int64(len(output)) {
+		return 0, false
+	}
+
+	return int(v.Int64()), true
+}
+
+// aggregate runs the calls in a single call of the Multicall contract, which fails if any call fails
+func (b *Blockchain) aggregate(ctx context.Context, calls []contractCall) ([][]byte, error) {
+	msg := ether.CallMsg{To: &b.multicall, Data: packAggregate(calls)}
+	output, err := b.callContract(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return unpackAggregate(output, len(calls))
+}
+
+// GetTokensInfo returns the info of the tokens, from the cache if set. The metadata of all the tokens
+// is read in a single call when a Multicall contract is set, else in JSON-RPC batch requests when a batch
+// size is set. The tokens they fail to read fall back to a call per method, with up to the concurrency
+// of the blockchain tokens read at the same time
+func (b *Blockchain) GetTokensInfo(ctx context.Context, tokens []common.Address, abi *abi.ABI) (map[common.Address]*TokenInfo, error) {
+	res := make(map[common.Address]*TokenInfo)
+	seen := make(map[common.Address]bool)
+	pending := []common.Address{}
+	for _, t := range tokens {
+		if seen[t] {
+			continue
+		}
+
+		seen[t] = true
+		if utils.IsNativeTokenByAddress(t) {
+			res[t] = b.setBaseTokenInfo()
+		} else if info, ok := b.cachedTokenInfo(t); ok {
+			res[t] = info
+		} else {
+			pending = append(pending, t)
+		}
+	}
+
+	fetched := make(map[common.Address]*TokenInfo)
+	if b.multicall != (common.Address{}) && len(pending) > 0 {
+		pending = b.getTokensInfoAggregated(ctx, pending, abi, fetched)
+	}
+
+	if b.batchSize > 0 && len(pending) > 0 {
+		pending = b.getTokensInfoBatched(ctx, pending, abi, fetched)
+	}
+
+	err := b.getTokensInfoConcurrently(ctx, pending, abi, fetched)
+	if err != nil {
+		return nil, err
+	}
+
+	for t, tokenInfo := range fetched {
+		if b.cache != nil {
+			b.cache.Set(t, tokenInfo)
+		}
+
+		res[t] = tokenInfo
+	}
+
+	return res, nil
+}
+
+// getTokensInfoConcurrently reads the metadata of the tokens into res with a call per method, the first
+// error is returned once all the running reads are done
+func (b *Blockchain) getTokensInfoConcurrently(ctx context.Context, tokens []common.Address, abi *abi.ABI, res map[common.Address]*TokenInfo) error {
+	concurrency := b.concurrency
+	if concurrency <= 0 {
+		concurrency = defaultTokenInfoConcurrency
+	}
+
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	var firstErr error
+	sem := make(chan struct{}, concurrency)
+
+	for _, t := range tokens {
+		mutex.Lock()
+		failed := firstErr != nil
+		mutex.Unlock()
+		if failed {
+			break
+		}
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(t common.Address) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			tokenInfo, err := b.GetTokenInfo(ctx, t, abi)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+
+			res[t] = tokenInfo
+		}(t)
+	}
+
+	wg.Wait()
+	return firstErr
+}
+
+func (b *Blockchain) cachedTokenInfo(token common.Address) (*TokenInfo, bool) {
+	if b.cache == nil {
+		return nil, false
+	}
+
+	return b.cache.Get(token)
+}
+
+// getTokensInfoAggregated reads the metadata of the tokens with the Multicall contract into res,
+// the tokens not read are returned
+func (b *Blockchain) getTokensInfoAggregated(ctx context.Context, tokens []common.Address, abi *abi.ABI, res map[common.Address]*TokenInfo) []common.Address {
+	calls := []contractCall{}
+	for _, t := range tokens {
+		for _, m := range tokenInfoMethods {
+			input, err := abi.Pack(m)
+			if err != nil {
+				return tokens
+			}
+
+			calls = append(calls, contractCall{t, input})
+		}
+	}
+
+	results, err := b.aggregate(ctx, calls)
+	if err != nil {
+		logger.Warning("Multicall failed, reading the tokens one by one:", err)
+		return tokens
+	}
+
+	failed := []common.Address{}
+	read := []common.Address{}
+	for i, t := range tokens {
+		tokenInfo, err := unpackTokenInfo(abi, results[i*len(tokenInfoMethods):(i+1)*len(tokenInfoMethods)])
+		if err != nil {
+			failed = append(failed, t)
+			continue
+		}
+
+		res[t] = tokenInfo
+		read = append(read, t)
+	}
+
+	b.setTRC21InfoAggregated(ctx, read, abi, res)
+	return failed
+}
+
+// setTRC21InfoAggregated reads the fee parameters of the tokens with the Multicall contract. A TRC20
+// token fails the whole call, the tokens are then read one by one
+func (b *Blockchain) setTRC21InfoAggregated(ctx context.Context, tokens []common.Address, abi *abi.ABI, res map[common.Address]*TokenInfo) {
+	calls := []contractCall{}
+	for _, t := range tokens {
+		for _, m := range trc21InfoMethods {
+			input, err := abi.Pack(m)
+			if err != nil {
+				return
+			}
+
+			calls = append(calls, contractCall{t, input})
+		}
+	}
+
+	if len(calls) == 0 {
+		return
+	}
+
+	results, err := b.aggregate(ctx, calls)
+	if err != nil {
+		for _, t := range tokens {
+			b.setTRC21Info(ctx, t, abi, res[t])
+		}
+
+		return
+	}
+
+	for i, t := range tokens {
+		unpackTRC21Info(abi, results[i*len(trc21InfoMethods):(i+1)*len(trc21InfoMethods)], res[t])
+	}
+}
+
+// unpackTokenInfo decodes the results of the name, symbol and decimals reads of a token
+func unpackTokenInfo(abi *abi.ABI, results [][]byte) (*TokenInfo, error) {
+	values := []interface{}{}
+	for i, m := range tokenInfoMethods {
+		var v interface{}
+		err := abi.Unpack(&v, m, results[i])
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, v)
+	}
+
+	name, ok := values[0].(string)
+	symbol, ok2 := values[1].(string)
+	decimals, ok3 := values[2].(uint8)
+	if !ok || !ok2 || !ok3 {
+		return nil, errors.New("Invalid token info")
+	}
+
+	return &TokenInfo{Name: name, Symbol: symbol, Decimals: decimals}, nil
+}
+
+// unpackTRC21Info decodes the results of the issuer and minFee reads of a token into its info
+func unpackTRC21Info(abi *abi.ABI, results [][]byte, tokenInfo *TokenInfo) {
+	values := []interface{}{}
+	for i, m := range trc21InfoMethods {
+		var v interface{}
+		err := abi.Unpack(&v, m, results[i])
+		if err != nil {
+			return
+		}
+
+		values = append(values, v)
+	}
+
+	issuer, ok := values[0].(common.Address)
+	minFee, ok2 := values[1].(*big.Int)
+	if !ok || !ok2 {
+		return
+	}
+
+	tokenInfo.TRC21 = true
+	tokenInfo.Issuer = issuer
+	tokenInfo.MinFee = minFee
+}
@@ -0,0 +1,183 @@
+package relayer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	relayerAbi "github.com/tomochain/tomox-sdk/relayer/abi"
+)
+
+func TestPackAggregate(t *testing.T) {
+	token := common.HexToAddress("0x2")
+	input := packAggregate([]contractCall{{token, []byte{0x06, 0xfd, 0xde, 0x03}}})
+
+	assert.Equal(t, []byte{0x25, 0x2d, 0xba, 0x42}, input[:4])
+	// offset of the array, its length, the offset of the tuple and the tuple of 4 words
+	assert.Equal(t, 4+32*7, len(input))
+	assert.Equal(t, word(32), input[4:36])
+	assert.Equal(t, word(1), input[36:68])
+	assert.Equal(t, word(32), input[68:100])
+	assert.Equal(t, common.LeftPadBytes(token.Bytes(), 32), input[100:132])
+	assert.Equal(t, word(4), input[164:196])
+	assert.Equal(t, []byte{0x06, 0xfd, 0xde, 0x03}, input[196:200])
+}
+
+func TestUnpackAggregate(t *testing.T) {
+	first := []byte("TOMO")
+	second := make([]byte, 40)
+	second[39] = 0x12
+
+	output := word(100)
+	output = append(output, word(64)...)
+	output = append(output, word(2)...)
+	output = append(output, word(64)...)
+	output = append(output, word(128)...)
+	output = append(output, word(len(first))...)
+	output = append(output, common.RightPadBytes(first, 32)...)
+	output = append(output, word(len(second))...)
+	output = append(output, common.RightPadBytes(second, 64)...)
+
+	res, err := unpackAggregate(output, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{first, second}, res)
+
+	_, err = unpackAggregate(output, 3)
+	assert.Error(t, err)
+
+	_, err = unpackAggregate(output[:200], 2)
+	assert.Error(t, err)
+}
+
+func TestGetTokensInfoConcurrently(t *testing.T) {
+	tokenAbi, err := relayerAbi.GetTokenAbi()
+	assert.NoError(t, err)
+
+	symbol := append(word(32), word(3)...)
+	symbol = append(symbol, common.RightPadBytes([]byte("BTC"), 32)...)
+
+	// counts the calls running at the same time
+	var mutex sync.Mutex
+	var inFlight, maxInFlight int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mutex.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mutex.Lock()
+		inFlight--
+		mutex.Unlock()
+
+		req := struct {
+			ID     json.RawMessage          `json:"id"`
+			Params []map[string]interface{} `json:"params"`
+		}{}
+		json.NewDecoder(r.Body).Decode(&req)
+		input, _ := hexutil.Decode(req.Params[0]["data"].(string))
+
+		output := symbol
+		if bytes.Equal(input[:4], tokenAbi.Methods["decimals"].Id()) {
+			output = word(8)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": hexutil.Encode(output)})
+	}))
+	defer server.Close()
+
+	bc := NewBlockchain(nil, nil, nil)
+	bc.pool = NewRPCPool([]string{server.URL}, 1, time.Millisecond, time.Millisecond, 0)
+	bc.concurrency = 2
+
+	tokens := []common.Address{}
+	for i := 1; i <= 6; i++ {
+		tokens = append(tokens, common.BigToAddress(big.NewInt(int64(i))))
+	}
+
+	res, err := bc.GetTokensInfo(context.Background(), tokens, &tokenAbi)
+	assert.NoError(t, err)
+	assert.Len(t, res, 6)
+	assert.Equal(t, "BTC", res[tokens[5]].Symbol)
+	assert.Equal(t, uint8(8), res[tokens[5]].Decimals)
+	assert.Equal(t, 2, maxInFlight)
+}
+
+func TestGetTokensInfoBatched(t *testing.T) {
+	tokenAbi, err := relayerAbi.GetTokenAbi()
+	assert.NoError(t, err)
+
+	symbol := append(word(32), word(3)...)
+	symbol = append(symbol, common.RightPadBytes([]byte("BTC"), 32)...)
+	trc20 := common.HexToAddress("0x3")
+
+	// answers the batch requests, the fee parameters of the TRC20 token revert
+	var mutex sync.Mutex
+	batches := []int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqs := []struct {
+			ID     json.RawMessage          `json:"id"`
+			Params []map[string]interface{} `json:"params"`
+		}{}
+		json.NewDecoder(r.Body).Decode(&reqs)
+
+		mutex.Lock()
+		batches = append(batches, len(reqs))
+		mutex.Unlock()
+
+		res := []map[string]interface{}{}
+		for _, req := range reqs {
+			input, _ := hexutil.Decode(req.Params[0]["data"].(string))
+			to := common.HexToAddress(req.Params[0]["to"].(string))
+
+			output := symbol
+			switch {
+			case bytes.Equal(input[:4], tokenAbi.Methods["decimals"].Id()):
+				output = word(8)
+			case bytes.Equal(input[:4], tokenAbi.Methods["issuer"].Id()):
+				output = common.LeftPadBytes([]byte{0x9}, 32)
+			case bytes.Equal(input[:4], tokenAbi.Methods["minFee"].Id()):
+				output = word(100)
+			}
+
+			if to == trc20 && !bytes.Equal(output, symbol) && !bytes.Equal(output, word(8)) {
+				res = append(res, map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "error": map[string]interface{}{"code": -32000, "message": "execution reverted"}})
+				continue
+			}
+
+			res = append(res, map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": hexutil.Encode(output)})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(res)
+	}))
+	defer server.Close()
+
+	bc := NewBlockchain(nil, nil, nil)
+	bc.pool = NewRPCPool([]string{server.URL}, 1, time.Millisecond, time.Millisecond, 0)
+	bc.batchSize = 4
+
+	trc21 := common.HexToAddress("0x4")
+	res, err := bc.GetTokensInfo(context.Background(), []common.Address{trc20, trc21}, &tokenAbi)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{4, 4, 2}, batches)
+	assert.Equal(t, "BTC", res[trc20].Symbol)
+	assert.Equal(t, uint8(8), res[trc20].Decimals)
+	assert.False(t, res[trc20].TRC21)
+	assert.True(t, res[trc21].TRC21)
+	assert.Equal(t, common.HexToAddress("0x9"), res[trc21].Issuer)
+	assert.Equal(t, big.NewInt(100), res[trc21].MinFee)
+}
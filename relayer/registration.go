@@ -0,0 +1,143 @@
+package relayer
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Revisions of the registration contract, from the outputs of getRelayerByCoinbase
+const (
+	// RegistrationV1 returns the index, the owner, the deposit, the trade fee and the from and to tokens
+	RegistrationV1 = 1
+	// RegistrationV2 returns the index, the owner, the deposit, the make and take fees and the from and to tokens
+	RegistrationV2 = 2
+)
+
+var errUnknownRegistration = errors.New("Unknown registration contract revision")
+
+// relayerRecord is the relayer returned by getRelayerByCoinbase, whatever the revision of the contract
+type relayerRecord struct {
+	Index      *big.Int
+	Owner      common.Address
+	Deposit    *big.Int
+	MakeFee    uint16
+	TakeFee    uint16
+	FromTokens []common.Address
+	ToTokens   []common.Address
+}
+
+// registrationDecoder decodes the outputs of getRelayerByCoinbase of a revision of the contract
+type registrationDecoder struct {
+	version int
+	outputs abi.Arguments
+	decode  func(values []interface{}) *relayerRecord
+}
+
+var registrationDecoders = []*registrationDecoder{
+	{
+		version: RegistrationV1,
+		outputs: arguments("uint256", "address", "uint256", "uint16", "address[]", "address[]"),
+		decode: func(v []interface{}) *relayerRecord {
+			return &relayerRecord{
+				Index:      v[0].(*big.Int),
+				Owner:      v[1].(common.Address),
+				Deposit:    v[2].(*big.Int),
+				MakeFee:    v[3].(uint16),
+				TakeFee:    v[3].(uint16),
+				FromTokens: v[4].([]common.Address),
+				ToTokens:   v[5].([]common.Address),
+			}
+		},
+	},
+	{
+		version: RegistrationV2,
+		outputs: arguments("uint256", "address", "uint256", "uint16", "uint16", "address[]", "address[]"),
+		decode: func(v []interface{}) *relayerRecord {
+			return &relayerRecord{
+				Index:      v[0].(*big.Int),
+				Owner:      v[1].(common.Address),
+				Deposit:    v[2].(*big.Int),
+				MakeFee:    v[3].(uint16),
+				TakeFee:    v[4].(uint16),
+				FromTokens: v[5].([]common.Address),
+				ToTokens:   v[6].([]common.Address),
+			}
+		},
+	},
+}
+
+func arguments(types ...string) abi.Arguments {
+	res := abi.Arguments{}
+	for _, t := range types {
+		typ, err := abi.NewType(t)
+		if err != nil {
+			panic(err)
+		}
+
+		res = append(res, abi.Argument{Type: typ})
+	}
+
+	return res
+}
+
+// RegistrationVersion detects the revision of the registration contract from the output of
+// getRelayerByCoinbase. The token lists are the last two outputs of every revision, the offset of
+// the first one is the size of the head of the output, which gives the number of outputs
+func RegistrationVersion(output []byte) (int, error) {
+	for _, d := range registrationDecoders {
+		if hasTokenLists(output, len(d.outputs)) {
+			return d.version, nil
+		}
+	}
+
+	return 0, errUnknownRegistration
+}
+
+// hasTokenLists returns true if the output is made of a head of n words ending with the offsets of
+// the two token lists, followed by the lists
+func hasTokenLists(output []byte, n int) bool {
+	from, ok := readWord(output, (n-2)*32)
+	if !ok || from != n*32 {
+		return false
+	}
+
+	fromLen, ok := readWord(output, from)
+	if !ok {
+		return false
+	}
+
+	to, ok := readWord(output, (n-1)*32)
+	if !ok || to != from+32+fromLen*32 {
+		return false
+	}
+
+	toLen, ok := readWord(output, to)
+	return ok && len(output) == to+32+toLen*32
+}
+
+// decodeRelayerRecord decodes the output of getRelayerByCoinbase with the decoder of the revision
+// of the contract which returned it
+func decodeRelayerRecord(output []byte) (*relayerRecord, error) {
+	version, err := RegistrationVersion(output)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, d := range registrationDecoders {
+		if d.version != version {
+			continue
+		}
+
+		values, err := d.outputs.UnpackValues(output)
+		if err != nil {
+			return nil, err
+		}
+
+		return d.decode(values), nil
+	}
+
+	return nil, errUnknownRegistration
+}
@@ -0,0 +1,52 @@
+package relayer
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeRelayerRecord(t *testing.T) {
+	owner := common.HexToAddress("0x1")
+	from := []common.Address{common.HexToAddress("0x2"), common.HexToAddress("0x3")}
+	to := []common.Address{common.HexToAddress("0x4"), common.HexToAddress("0x4")}
+
+	v1, err := registrationDecoders[0].outputs.Pack(big.NewInt(5), owner, big.NewInt(100), uint16(10), from, to)
+	assert.Nil(t, err)
+
+	version, err := RegistrationVersion(v1)
+	assert.Nil(t, err)
+	assert.Equal(t, RegistrationV1, version)
+
+	r, err := decodeRelayerRecord(v1)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5), r.Index.Int64())
+	assert.Equal(t, owner, r.Owner)
+	assert.Equal(t, uint16(10), r.MakeFee)
+	assert.Equal(t, uint16(10), r.TakeFee)
+	assert.Equal(t, from, r.FromTokens)
+	assert.Equal(t, to, r.ToTokens)
+
+	// a take fee equal to the head size of the first revision is not mistaken for an offset
+	v2, err := registrationDecoders[1].outputs.Pack(big.NewInt(5), owner, big.NewInt(100), uint16(10), uint16(192), from, to)
+	assert.Nil(t, err)
+
+	version, err = RegistrationVersion(v2)
+	assert.Nil(t, err)
+	assert.Equal(t, RegistrationV2, version)
+
+	r, err = decodeRelayerRecord(v2)
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(10), r.MakeFee)
+	assert.Equal(t, uint16(192), r.TakeFee)
+	assert.Equal(t, from, r.FromTokens)
+	assert.Equal(t, to, r.ToTokens)
+
+	_, err = decodeRelayerRecord(v1[:len(v1)-32])
+	assert.Equal(t, errUnknownRegistration, err)
+
+	_, err = decodeRelayerRecord(nil)
+	assert.Equal(t, errUnknownRegistration, err)
+}
@@ -1,8 +1,12 @@
 package relayer
 
 import (
-	"fmt"
+	"context"
+	"sort"
+	"sync"
+	"time"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
@@ -10,70 +14,302 @@ import (
 
 // Relayer get token
 type Relayer struct {
-	rpcURL                string
+	rpc                   *RPCPool
+	wsURL                 string
 	coinBase              common.Address
 	relayerAddress        common.Address
 	lendingRelayerAddress common.Address
+	multicallAddress      common.Address
+	tokenCache            *TokenInfoCache
+	tokenConcurrency      int
+	tokenBatchSize        int
+	signer                *Signer
+	breaker               *CircuitBreaker
+	lastMutex             sync.RWMutex
+	lastRelayers          map[common.Address]*RInfo
+	lastLending           *LendingRInfo
 }
 
-// NewRelayer init relayer
-func NewRelayer(rpcURL string,
+// NewRelayer init relayer, the contracts are called through the pool of http endpoints
+func NewRelayer(rpc *RPCPool,
+	wsURL string,
 	coinBase common.Address,
 	relayerAddress common.Address,
 	lendingRelayerAddress common.Address,
+	multicallAddress common.Address,
+	tokenInfoTTL time.Duration,
+	tokenConcurrency int,
+	tokenBatchSize int,
 ) *Relayer {
 
 	return &Relayer{
-		rpcURL:                rpcURL,
+		rpc:                   rpc,
+		wsURL:                 wsURL,
 		coinBase:              coinBase,
 		relayerAddress:        relayerAddress,
 		lendingRelayerAddress: lendingRelayerAddress,
+		multicallAddress:      multicallAddress,
+		tokenCache:            NewTokenInfoCache(tokenInfoTTL),
+		tokenConcurrency:      tokenConcurrency,
+		tokenBatchSize:        tokenBatchSize,
+		lastRelayers:          make(map[common.Address]*RInfo),
 	}
 }
 
-// GetRelayer get relayer information
+// SetSigner sets the signer of the transactions of the relayer, opened once as a hardware wallet
+// or a keystore is slow to open
+func (r *Relayer) SetSigner(signer *Signer) {
+	r.signer = signer
+}
+
+// SetCircuitBreaker sets the circuit breaker of the contract calls. While it is open the relayers and
+// the lending relayer last read are served instead of an error
+func (r *Relayer) SetCircuitBreaker(breaker *CircuitBreaker) {
+	r.breaker = breaker
+}
+
+// BreakerState returns the state of the circuit breaker of the contract calls, closed if none is set
+func (r *Relayer) BreakerState() string {
+	if r.breaker == nil {
+		return BreakerClosed
+	}
+
+	return r.breaker.State()
+}
+
+// blockchain calls the contracts through the pool of endpoints of the relayer, the token metadata
+// is read from the cache of the relayer or with the Multicall contract if set, else in batch requests
+// if a batch size is set, else concurrently
+func (r *Relayer) blockchain() *Blockchain {
+	bc := NewBlockchain(nil, nil, r.signer)
+	bc.pool = r.rpc
+	bc.multicall = r.multicallAddress
+	bc.cache = r.tokenCache
+	bc.breaker = r.breaker
+	bc.concurrency = r.tokenConcurrency
+	bc.batchSize = r.tokenBatchSize
+	return bc
+}
+
+// keepRelayers records the relayers read from the contract, served while the circuit is open
+func (r *Relayer) keepRelayers(infos ...*RInfo) {
+	r.lastMutex.Lock()
+	defer r.lastMutex.Unlock()
+
+	for _, info := range infos {
+		if info != nil {
+			r.lastRelayers[info.Address] = info
+		}
+	}
+}
+
+// cachedRelayers returns the relayers of the coinbases last read from the contract, all of them in the
+// order of the contract if none is given. It returns false if one of the coinbases was never read
+func (r *Relayer) cachedRelayers(coinbases []common.Address) ([]*RInfo, bool) {
+	r.lastMutex.RLock()
+	defer r.lastMutex.RUnlock()
+
+	if len(coinbases) == 0 {
+		if len(r.lastRelayers) == 0 {
+			return nil, false
+		}
+
+		infos := make([]*RInfo, 0, len(r.lastRelayers))
+		for _, info := range r.lastRelayers {
+			infos = append(infos, info)
+		}
+
+		sort.Slice(infos, func(i, j int) bool {
+			return infos[i].RID < infos[j].RID
+		})
+
+		return infos, true
+	}
+
+	infos := make([]*RInfo, 0, len(coinbases))
+	for _, coinbase := range coinbases {
+		info, ok := r.lastRelayers[coinbase]
+		if !ok {
+			return nil, false
+		}
+
+		infos = append(infos, info)
+	}
+
+	return infos, true
+}
+
+// GetRelayer get relayer information, the one last read while the circuit is open
 func (r *Relayer) GetRelayer(coinbase common.Address) (*RInfo, error) {
-	signer := NewSigner()
-	client, err := rpc.Dial(r.rpcURL)
+	info, err := r.blockchain().GetRelayer(context.Background(), coinbase, r.relayerAddress)
+	if err == ErrCircuitOpen {
+		if infos, ok := r.cachedRelayers([]common.Address{coinbase}); ok {
+			return infos[0], nil
+		}
+	}
+
 	if err != nil {
-		fmt.Println(err)
+		return nil, err
 	}
-	ethclient := ethclient.NewClient(client)
-	bc := NewBlockchain(client, ethclient, signer)
-	return bc.GetRelayer(coinbase, r.relayerAddress)
+
+	r.keepRelayers(info)
+	return info, nil
 }
 
-func (r *Relayer) GetRelayers() ([]*RInfo, error) {
-	signer := NewSigner()
-	client, err := rpc.Dial(r.rpcURL)
+// GetRelayerByID get the relayer at an index of the registration contract
+func (r *Relayer) GetRelayerByID(id int64) (*RInfo, error) {
+	return r.blockchain().GetRelayerByID(context.Background(), id, r.relayerAddress)
+}
+
+// GetRelayersByOwner get the relayers of an owner
+func (r *Relayer) GetRelayersByOwner(owner common.Address) ([]*RInfo, error) {
+	return r.blockchain().GetRelayersByOwner(context.Background(), owner, r.relayerAddress)
+}
+
+// GetRelayerDeposit get the deposit of a relayer locked on the registration contract
+func (r *Relayer) GetRelayerDeposit(coinbase common.Address) (*RelayerDeposit, error) {
+	return r.blockchain().GetRelayerDeposit(context.Background(), coinbase, r.relayerAddress)
+}
+
+// HealthCheck checks the endpoint of the pool which answered last and the registration contract
+func (r *Relayer) HealthCheck() *ChainHealth {
+	bc := r.blockchain()
+	bc.timeout = r.rpc.Timeout()
+	return bc.HealthCheck(context.Background(), r.relayerAddress)
+}
+
+// GetRelayers returns the merged view of the tokens and the pairs of the relayers of the coinbases,
+// with the fees of each relayer. All the relayers registered on the contract are read if none is given.
+// The relayers last read are merged while the circuit is open
+func (r *Relayer) GetRelayers(coinbases []common.Address) (*AggregatedRInfo, error) {
+	bc := r.blockchain()
+
+	var infos []*RInfo
+	var err error
+	if len(coinbases) == 0 {
+		infos, err = bc.GetRelayers(context.Background(), r.relayerAddress)
+	} else {
+		infos, err = bc.GetRelayersByCoinbase(context.Background(), coinbases, r.relayerAddress)
+	}
+
+	if err == ErrCircuitOpen {
+		if cached, ok := r.cachedRelayers(coinbases); ok {
+			return Aggregate(cached), nil
+		}
+	}
+
 	if err != nil {
-		fmt.Println(err)
+		return nil, err
 	}
-	ethclient := ethclient.NewClient(client)
-	bc := NewBlockchain(client, ethclient, signer)
-	return bc.GetRelayers(r.relayerAddress)
+
+	r.keepRelayers(infos...)
+	return Aggregate(infos), nil
 }
 
-// GetLending get relayer information
+// GetLending get relayer information, the one last read while the circuit is open
 func (r *Relayer) GetLending() (*LendingRInfo, error) {
-	signer := NewSigner()
-	client, err := rpc.Dial(r.rpcURL)
+	info, err := r.blockchain().GetLendingRelayer(context.Background(), r.coinBase, r.lendingRelayerAddress)
+	r.lastMutex.Lock()
+	defer r.lastMutex.Unlock()
+
+	if err == ErrCircuitOpen && r.lastLending != nil {
+		return r.lastLending, nil
+	}
+
 	if err != nil {
-		fmt.Println(err)
+		return nil, err
 	}
-	ethclient := ethclient.NewClient(client)
-	bc := NewBlockchain(client, ethclient, signer)
-	return bc.GetLendingRelayer(r.coinBase, r.lendingRelayerAddress)
 
+	r.lastLending = info
+	return info, nil
 }
 
 func (r *Relayer) GetLendings() ([]*LendingRInfo, error) {
-	signer := NewSigner()
-	client, err := rpc.Dial(r.rpcURL)
+	return r.blockchain().GetLendingRelayers(context.Background(), r.relayerAddress, r.lendingRelayerAddress)
+}
+
+// GetFullRelayerSnapshot get the spot and lending information of a relayer with the hash of their state
+func (r *Relayer) GetFullRelayerSnapshot(coinbase common.Address) (*RelayerSnapshot, error) {
+	return r.blockchain().GetFullRelayerSnapshot(context.Background(), coinbase, r.relayerAddress, r.lendingRelayerAddress)
+}
+
+// GetRelayerAt get relayer information at a past block
+func (r *Relayer) GetRelayerAt(coinbase common.Address, block BlockRef) (*RInfo, error) {
+	return r.blockchain().GetRelayer(WithBlock(context.Background(), block), coinbase, r.relayerAddress)
+}
+
+// GetFullRelayerSnapshotAt get the spot and lending information of a relayer at a past block
+func (r *Relayer) GetFullRelayerSnapshotAt(coinbase common.Address, block BlockRef) (*RelayerSnapshot, error) {
+	ctx := WithBlock(context.Background(), block)
+	return r.blockchain().GetFullRelayerSnapshot(ctx, coinbase, r.relayerAddress, r.lendingRelayerAddress)
+}
+
+// WatchRelayers calls the handler with the changes of the relayers from the events of the registration
+// contract, through the websocket endpoint of the node. The call returns when the subscription fails, the
+// events missed until it is started again are read by the next call from the last processed block of the
+// cursor, the events of that block are read again and give no change. The relayers are read first when
+// none is known yet or the cursor holds no block
+func (r *Relayer) WatchRelayers(known map[common.Address]*RInfo, cursor LogCursor, handler func(*RInfoDiff)) error {
+	client, err := rpc.Dial(r.wsURL)
+	if err != nil {
+		return err
+	}
+
+	defer client.Close()
+
+	bc := NewBlockchain(client, ethclient.NewClient(client), r.signer)
+	bc.multicall = r.multicallAddress
+	bc.cache = r.tokenCache
+	bc.batchSize = r.tokenBatchSize
+	bc.timeout = r.rpc.Timeout()
+
+	ctx := context.Background()
+	var from uint64
+	if cursor != nil && len(known) > 0 {
+		from, err = cursor.LastProcessedBlock()
+		if err != nil {
+			return err
+		}
+	}
+
+	if from > 0 {
+		return bc.WatchRelayers(ctx, r.relayerAddress, known, from, cursor, handler)
+	}
+
+	head, err := bc.ethclient.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	relayers, err := bc.GetRelayers(ctx, r.relayerAddress)
 	if err != nil {
-		fmt.Println(err)
+		return err
 	}
-	ethclient := ethclient.NewClient(client)
-	bc := NewBlockchain(client, ethclient, signer)
-	return bc.GetLendingRelayers(r.relayerAddress, r.lendingRelayerAddress)
+
+	for _, info := range relayers {
+		if info == nil {
+			continue
+		}
+
+		d := DiffRInfo(known[info.Address], info)
+		known[info.Address] = info
+		if !d.Empty() {
+			handler(d)
+		}
+	}
+
+	saveProcessedBlock(cursor, head.Number.Uint64())
+	return bc.WatchRelayers(ctx, r.relayerAddress, known, head.Number.Uint64(), cursor, handler)
+}
+
+// CallContract calls a view method of a contract with the arguments and unpacks its result into out,
+// see Blockchain.CallContract
+func (r *Relayer) CallContract(ctx context.Context, contract common.Address, contractAbi *abi.ABI, method string, out interface{}, args ...interface{}) error {
+	return r.blockchain().CallContract(ctx, contract, contractAbi, method, out, args...)
+}
+
+// InvalidateTokenInfo drops the cached metadata of the tokens, of all of them if none is given,
+// it is read again from the chain at the next refresh
+func (r *Relayer) InvalidateTokenInfo(tokens ...common.Address) {
+	r.tokenCache.Invalidate(tokens...)
 }
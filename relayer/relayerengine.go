@@ -4,8 +4,8 @@ import (
 	"context"
 	"errors"
 	"math/big"
-	"os"
 	"strconv"
+	"time"
 
 	ether "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
@@ -20,9 +20,16 @@ var logger = utils.Logger
 
 // Blockchain struct
 type Blockchain struct {
-	client    *rpc.Client
-	ethclient *ethclient.Client
-	signer    *Signer
+	client      *rpc.Client
+	ethclient   *ethclient.Client
+	signer      *Signer
+	multicall   common.Address
+	cache       *TokenInfoCache
+	pool        *RPCPool
+	breaker     *CircuitBreaker
+	concurrency int
+	batchSize   int
+	timeout     time.Duration
 }
 
 // PairToken pare token
@@ -37,14 +44,25 @@ type LendingPairToken struct {
 	LendingToken common.Address
 }
 
-// TokenInfo token info
+// TokenInfo token info. TRC21 tokens pay the gas of their transactions in the token, at least MinFee,
+// to their issuer
 type TokenInfo struct {
 	Name     string
 	Symbol   string
 	Decimals uint8
+	TRC21    bool
+	Issuer   common.Address
+	MinFee   *big.Int
 	address  common.Address
 }
 
+// Lifecycle statuses of a relayer, from its resign request on the registration contract
+const (
+	RelayerStatusActive    = "active"
+	RelayerStatusResigning = "resigning"
+	RelayerStatusExpired   = "expired"
+)
+
 // RInfo struct
 type RInfo struct {
 	RID      int
@@ -55,6 +73,7 @@ type RInfo struct {
 	Pairs    []*PairToken
 	Resign   bool
 	LockTime int
+	Status   string
 	MakeFee  uint16
 	TakeFee  uint16
 }
@@ -63,10 +82,35 @@ type RInfo struct {
 type LendingRInfo struct {
 	Address         common.Address
 	ColateralTokens map[common.Address]*TokenInfo
+	Collaterals     map[common.Address]*CollateralInfo
 	LendingTokens   map[common.Address]*TokenInfo
 	LendingPairs    []*LendingPairToken
 	Fee             uint16
 }
+
+// CollateralInfo are the lending parameters of a collateral token, the deposit and liquidation
+// rates in percent of the loan and its prices in each lending token. Price is the price of the
+// collateral list, used for the lending tokens missing from the price feed
+type CollateralInfo struct {
+	DepositRate     *big.Int
+	LiquidationRate *big.Int
+	Price           *big.Int
+	Prices          map[common.Address]*big.Int
+}
+
+// RelayerDeposit is the TOMO deposit locked by a relayer on the registration contract with the minimum
+// required by the contract. The deposit of a resigned relayer is locked until ReleaseTime, then refunded
+type RelayerDeposit struct {
+	Coinbase       common.Address `json:"coinbase"`
+	Owner          common.Address `json:"owner"`
+	Deposit        *big.Int       `json:"deposit"`
+	MinimumDeposit *big.Int       `json:"minimumDeposit"`
+	BelowMinimum   bool           `json:"belowMinimum"`
+	Resigned       bool           `json:"resigned"`
+	ReleaseTime    int64          `json:"releaseTime"`
+	Locked         bool           `json:"locked"`
+}
+
 type Corrateral struct {
 	Name    string         `json:"name"`
 	Address common.Address `json:"address"`
@@ -84,71 +128,165 @@ func NewBlockchain(client *rpc.Client,
 	}
 }
 
-func (b *Blockchain) abiFrom(abiPath string) (*abi.ABI, error) {
-	file, err := os.Open(abiPath)
-	if err != nil {
-		return nil, err
+// callContract executes the call through the pool of endpoints if set, or the client of the blockchain.
+// The call is refused with ErrCircuitOpen while the circuit breaker, if set, is open
+func (b *Blockchain) callContract(ctx context.Context, msg ether.CallMsg) ([]byte, error) {
+	var out []byte
+	err := b.withBreaker(ctx, func() error {
+		var err error
+		out, err = b.sendCall(ctx, msg)
+		return err
+	})
+
+	return out, err
+}
+
+// withBreaker sends the request unless the circuit breaker, if set, is open and records its outcome
+func (b *Blockchain) withBreaker(ctx context.Context, request func() error) error {
+	if b.breaker == nil {
+		return request()
+	}
+
+	if !b.breaker.Allow() {
+		return ErrCircuitOpen
+	}
+
+	err := request()
+	if _, ok := err.(rpc.Error); err == nil || ok {
+		b.breaker.Success()
+	} else if ctx.Err() != nil {
+		b.breaker.release()
+	} else {
+		b.breaker.Failure()
+	}
+
+	return err
+}
+
+// sendCall sends the call to the pool of endpoints if set, or the client of the blockchain, on the block
+// of the context
+func (b *Blockchain) sendCall(ctx context.Context, msg ether.CallMsg) ([]byte, error) {
+	if b.pool != nil {
+		return b.pool.CallContract(ctx, msg)
+	}
+
+	ctx, cancel := b.withTimeout(ctx)
+	defer cancel()
+
+	block := blockFromContext(ctx)
+	if block.IsLatest() {
+		return b.ethclient.CallContract(ctx, msg, nil)
+	}
+
+	return callAt(ctx, b.client, msg, block)
+}
+
+// withTimeout bounds a request sent with the client of the blockchain by its timeout, if set
+func (b *Blockchain) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if b.timeout <= 0 {
+		return context.WithCancel(ctx)
 	}
-	parsed, err := abi.JSON(file)
+
+	return context.WithTimeout(ctx, b.timeout)
+}
+
+// RunContractAt runs a view method of the contract on the state of the block
+func (b *Blockchain) RunContractAt(ctx context.Context, block BlockRef, contractAddr common.Address, abi *abi.ABI, method string, args ...interface{}) (interface{}, error) {
+	return b.RunContract(WithBlock(ctx, block), contractAddr, abi, method, args...)
+}
+
+// CallContractAt calls a view method of the contract on the state of the block, see CallContract
+func (b *Blockchain) CallContractAt(ctx context.Context, block BlockRef, contractAddr common.Address, abi *abi.ABI, method string, out interface{}, args ...interface{}) error {
+	return b.CallContract(WithBlock(ctx, block), contractAddr, abi, method, out, args...)
+}
+
+// RunContract run smart contract, on the block of the context set with WithBlock or the latest one
+func (b *Blockchain) RunContract(ctx context.Context, contractAddr common.Address, abi *abi.ABI, method string, args ...interface{}) (interface{}, error) {
+	var unpackResult interface{}
+	err := b.CallContract(ctx, contractAddr, abi, method, &unpackResult, args...)
 	if err != nil {
 		return nil, err
 	}
-	return &parsed, nil
+	return unpackResult, nil
 }
 
-// RunContract run smart contract
-func (b *Blockchain) RunContract(contractAddr common.Address, abi *abi.ABI, method string, args ...interface{}) (interface{}, error) {
-	input, err := abi.Pack(method)
+// CallContract calls a view method of the contract with the arguments and unpacks its result into out,
+// a pointer to a value of the type of the output for a method returning one value, or to a struct with
+// a field per output, named after it or tagged abi:"name", for a method returning several
+func (b *Blockchain) CallContract(ctx context.Context, contractAddr common.Address, abi *abi.ABI, method string, out interface{}, args ...interface{}) error {
+	input, err := abi.Pack(method, args...)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	msg := ether.CallMsg{To: &contractAddr, Data: input}
-	result, err := b.ethclient.CallContract(context.Background(), msg, nil)
+	result, err := b.callContract(ctx, msg)
 	if err != nil {
 		logger.Error(err)
+		return err
 	}
-	var unpackResult interface{}
-	err = abi.Unpack(&unpackResult, method, result)
-	if err != nil {
-		return nil, err
-	}
-	return unpackResult, nil
+
+	return abi.Unpack(out, method, result)
 }
 
-// GetTokenInfoEx return token info
-func (b *Blockchain) GetTokenInfoEx(token common.Address, abiPath string) (*TokenInfo, error) {
-	abi, err := b.abiFrom(abiPath)
+// GetTokenInfoEx return token info read with the token ABI
+func (b *Blockchain) GetTokenInfoEx(ctx context.Context, token common.Address) (*TokenInfo, error) {
+	abi, err := relayerAbi.GetTokenAbi()
 	if err != nil {
 		return nil, err
 	}
-	return b.GetTokenInfo(token, abi)
+	return b.GetTokenInfo(ctx, token, &abi)
 }
 
 // GetTokenInfo return token info
-func (b *Blockchain) GetTokenInfo(token common.Address, abi *abi.ABI) (*TokenInfo, error) {
+func (b *Blockchain) GetTokenInfo(ctx context.Context, token common.Address, abi *abi.ABI) (*TokenInfo, error) {
 
-	result, err := b.RunContract(token, abi, "name")
+	result, err := b.RunContract(ctx, token, abi, "name")
 	if err != nil {
 		return nil, err
 	}
 	name := result.(string)
-	result, err = b.RunContract(token, abi, "symbol")
+	result, err = b.RunContract(ctx, token, abi, "symbol")
 	if err != nil {
 		return nil, err
 	}
 	symbol := result.(string)
-	result, err = b.RunContract(token, abi, "decimals")
+	result, err = b.RunContract(ctx, token, abi, "decimals")
 	if err != nil {
 		return nil, err
 	}
 	decimals := result.(uint8)
 
-	return &TokenInfo{
+	tokenInfo := &TokenInfo{
 		Name:     name,
 		Symbol:   symbol,
 		Decimals: decimals,
-	}, nil
+	}
+
+	b.setTRC21Info(ctx, token, abi, tokenInfo)
+	return tokenInfo, nil
+}
+
+// setTRC21Info reads the issuer and the minimum fee of a TRC21 token into its info. The reads of a
+// TRC20 token revert, it is kept as a token whose fees are paid in TOMO. The reads are not logged
+// as their failures are expected
+func (b *Blockchain) setTRC21Info(ctx context.Context, token common.Address, abi *abi.ABI, tokenInfo *TokenInfo) {
+	results := [][]byte{}
+	for _, m := range trc21InfoMethods {
+		input, err := abi.Pack(m)
+		if err != nil {
+			return
+		}
+
+		output, err := b.callContract(ctx, ether.CallMsg{To: &token, Data: input})
+		if err != nil {
+			return
+		}
+
+		results = append(results, output)
+	}
+
+	unpackTRC21Info(abi, results, tokenInfo)
 }
 
 func (b *Blockchain) setBaseTokenInfo() *TokenInfo {
@@ -159,105 +297,164 @@ func (b *Blockchain) setBaseTokenInfo() *TokenInfo {
 	}
 }
 
-func (b *Blockchain) GetRelayers(contractAddress common.Address) ([]*RInfo, error) {
-	count, _ := b.GetRelayerCount(contractAddress)
+func (b *Blockchain) GetRelayers(ctx context.Context, contractAddress common.Address) ([]*RInfo, error) {
+	count, _ := b.GetRelayerCount(ctx, contractAddress)
 
 	var rInfos []*RInfo
 	logger.Debug("Relayer count", count.String())
 	for i := int64(0); i < int64(count.Uint64()); i++ {
-		coinbase, _ := b.GetRelayerCoinBaseByIndex(i, contractAddress)
-		rInfo, _ := b.GetRelayer(coinbase, contractAddress)
+		coinbase, _ := b.GetRelayerCoinBaseByIndex(ctx, i, contractAddress)
+		rInfo, _ := b.GetRelayer(ctx, coinbase, contractAddress)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
 		rInfos = append(rInfos, rInfo)
 	}
 
 	return rInfos, nil
 }
 
-func (b *Blockchain) GetRelayerCoinBaseByIndex(idx int64, contractAddress common.Address) (common.Address, error) {
+func (b *Blockchain) GetRelayerCoinBaseByIndex(ctx context.Context, idx int64, contractAddress common.Address) (common.Address, error) {
 	abiRelayer, err := relayerAbi.GetRelayerAbi()
 	if err != nil {
 		return common.Address{}, err
 	}
-	input, err := abiRelayer.Pack("RELAYER_COINBASES", big.NewInt(idx))
+
+	var coinbase common.Address
+	err = b.CallContract(ctx, contractAddress, &abiRelayer, "RELAYER_COINBASES", &coinbase, big.NewInt(idx))
 	if err != nil {
 		return common.Address{}, err
 	}
 
-	msg := ether.CallMsg{To: &contractAddress, Data: input}
-	result, err := b.ethclient.CallContract(context.Background(), msg, nil)
+	return coinbase, nil
+}
+
+func (b *Blockchain) GetRelayerCount(ctx context.Context, contractAddress common.Address) (*big.Int, error) {
+	abiRelayer, err := relayerAbi.GetRelayerAbi()
 	if err != nil {
-		logger.Error(err)
-		return common.Address{}, err
+		return nil, err
 	}
 
-	if method, ok := abiRelayer.Methods["RELAYER_COINBASES"]; ok {
-		contractData, _ := method.Outputs.UnpackValues(result)
-		return contractData[0].(common.Address), nil
-	} else {
-		return common.Address{}, errors.New("Can not get coinbase")
+	var count *big.Int
+	err = b.CallContract(ctx, contractAddress, &abiRelayer, "RelayerCount", &count)
+	if err != nil {
+		return nil, err
 	}
 
-	return common.Address{}, nil
+	return count, nil
 }
 
-func (b *Blockchain) GetRelayerCount(contractAddress common.Address) (*big.Int, error) {
+// ErrRelayerNotFound is returned for an index out of the relayers of the registration contract
+var ErrRelayerNotFound = errors.New("Relayer not found")
+
+// relayerListEntry is the entry of a relayer in the RELAYER_LIST of the registration contract
+type relayerListEntry struct {
+	Deposit *big.Int       `abi:"_deposit"`
+	Fee     uint16         `abi:"_tradeFee"`
+	Index   *big.Int       `abi:"_index"`
+	Owner   common.Address `abi:"_owner"`
+}
+
+func (b *Blockchain) getRelayerListEntry(ctx context.Context, coinbase common.Address, contractAddress common.Address) (*relayerListEntry, error) {
 	abiRelayer, err := relayerAbi.GetRelayerAbi()
 	if err != nil {
 		return nil, err
 	}
 
-	input, err := abiRelayer.Pack("RelayerCount")
+	var entry relayerListEntry
+	err = b.CallContract(ctx, contractAddress, &abiRelayer, "RELAYER_LIST", &entry, coinbase)
 	if err != nil {
 		return nil, err
 	}
 
-	msg := ether.CallMsg{To: &contractAddress, Data: input}
-	result, err := b.ethclient.CallContract(context.Background(), msg, nil)
+	return &entry, nil
+}
+
+// GetRelayerByID returns the relayer at an index of the registration contract, its RID
+func (b *Blockchain) GetRelayerByID(ctx context.Context, id int64, contractAddress common.Address) (*RInfo, error) {
+	count, err := b.GetRelayerCount(ctx, contractAddress)
 	if err != nil {
-		logger.Error(err)
 		return nil, err
 	}
 
-	if method, ok := abiRelayer.Methods["RelayerCount"]; ok {
-		contractData, _ := method.Outputs.UnpackValues(result)
-		return contractData[0].(*big.Int), nil
-	} else {
-		return nil, errors.New("Can not get relayer information")
+	if id < 0 || id >= count.Int64() {
+		return nil, ErrRelayerNotFound
+	}
+
+	coinbase, err := b.GetRelayerCoinBaseByIndex(ctx, id, contractAddress)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, nil
+	return b.GetRelayer(ctx, coinbase, contractAddress)
 }
 
-func (b *Blockchain) GetRelayerResignStatus(contractAddress common.Address, coinbase common.Address) (*big.Int, error) {
-	abiRelayer, err := relayerAbi.GetRelayerAbi()
+// GetRelayersByOwner enumerates the relayers of the registration contract and returns the ones of an owner,
+// only their entries are read for the other relayers
+func (b *Blockchain) GetRelayersByOwner(ctx context.Context, owner common.Address, contractAddress common.Address) ([]*RInfo, error) {
+	count, err := b.GetRelayerCount(ctx, contractAddress)
 	if err != nil {
 		return nil, err
 	}
 
-	input, err := abiRelayer.Pack("RESIGN_REQUESTS", coinbase)
+	res := []*RInfo{}
+	for i := int64(0); i < count.Int64(); i++ {
+		coinbase, err := b.GetRelayerCoinBaseByIndex(ctx, i, contractAddress)
+		if err != nil {
+			return nil, err
+		}
+
+		entry, err := b.getRelayerListEntry(ctx, coinbase, contractAddress)
+		if err != nil {
+			return nil, err
+		}
+
+		if entry.Owner != owner {
+			continue
+		}
+
+		info, err := b.GetRelayer(ctx, coinbase, contractAddress)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, info)
+	}
+
+	return res, nil
+}
+
+func (b *Blockchain) GetRelayerResignStatus(ctx context.Context, contractAddress common.Address, coinbase common.Address) (*big.Int, error) {
+	abiRelayer, err := relayerAbi.GetRelayerAbi()
 	if err != nil {
 		return nil, err
 	}
 
-	msg := ether.CallMsg{To: &contractAddress, Data: input}
-	result, err := b.ethclient.CallContract(context.Background(), msg, nil)
+	var lockTime *big.Int
+	err = b.CallContract(ctx, contractAddress, &abiRelayer, "RESIGN_REQUESTS", &lockTime, coinbase)
 	if err != nil {
-		logger.Error(err)
 		return nil, err
 	}
 
-	if method, ok := abiRelayer.Methods["RESIGN_REQUESTS"]; ok {
-		contractData, _ := method.Outputs.UnpackValues(result)
-		return contractData[0].(*big.Int), nil
-	} else {
-		return nil, errors.New("Can not get relayer information")
-	}
+	return lockTime, nil
+}
 
-	return nil, nil
+// RelayerStatus returns the status of a relayer from the release time of the deposit of its resign request,
+// 0 if it did not resign. A relayer is resigning until the release time and expired after it
+func RelayerStatus(lockTime int64, now time.Time) string {
+	switch {
+	case lockTime <= 0:
+		return RelayerStatusActive
+	case now.Unix() < lockTime:
+		return RelayerStatusResigning
+	default:
+		return RelayerStatusExpired
+	}
 }
 
 // GetRelayer return all tokens in smart contract
-func (b *Blockchain) GetRelayer(coinAddress common.Address, contractAddress common.Address) (*RInfo, error) {
+func (b *Blockchain) GetRelayer(ctx context.Context, coinAddress common.Address, contractAddress common.Address) (*RInfo, error) {
 	abiRelayer, err := relayerAbi.GetRelayerAbi()
 	if err != nil {
 		return nil, err
@@ -272,7 +469,7 @@ func (b *Blockchain) GetRelayer(coinAddress common.Address, contractAddress comm
 	}
 
 	msg := ether.CallMsg{To: &contractAddress, Data: input}
-	result, err := b.ethclient.CallContract(context.Background(), msg, nil)
+	result, err := b.callContract(ctx, msg)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -283,68 +480,60 @@ func (b *Blockchain) GetRelayer(coinAddress common.Address, contractAddress comm
 		Tokens:  make(map[common.Address]*TokenInfo),
 		Address: coinAddress,
 	}
-	if method, ok := abiRelayer.Methods["getRelayerByCoinbase"]; ok {
-		contractData, err := method.Outputs.UnpackValues(result)
-		if err == nil {
-			if len(contractData) == 6 {
-				idx := contractData[0].(*big.Int)
-				relayerInfo.RID, _ = strconv.Atoi(idx.String())
-				relayerInfo.Owner = contractData[1].(common.Address)
-				relayerInfo.Deposit = contractData[2].(*big.Int)
-				relayerInfo.MakeFee = contractData[3].(uint16)
-				relayerInfo.TakeFee = contractData[3].(uint16)
-				fromTokens := contractData[4].([]common.Address)
-				toTokens := contractData[5].([]common.Address)
-				setToken := utils.Union(fromTokens, toTokens)
-				lockTime, _ := b.GetRelayerResignStatus(contractAddress, coinAddress)
-				relayerInfo.LockTime, _ = strconv.Atoi(lockTime.String())
-				if relayerInfo.Resign = false; relayerInfo.LockTime > 0 {
-					relayerInfo.Resign = true
-				}
-				for _, t := range setToken {
-					if utils.IsNativeTokenByAddress(t) {
-						tokenInfo := b.setBaseTokenInfo()
-						relayerInfo.Tokens[t] = tokenInfo
-					} else {
-						tokenInfo, err := b.GetTokenInfo(t, &abiToken)
-						if err != nil {
-							return nil, err
-						}
-						relayerInfo.Tokens[t] = tokenInfo
-						logger.Debug("Token data:", tokenInfo.Name, tokenInfo.Symbol)
-					}
+	record, err := decodeRelayerRecord(result)
+	if err != nil {
+		logger.Error("Can not decode relayer", coinAddress.Hex(), err)
+		return nil, err
+	}
 
-				}
-				if len(fromTokens) == len(toTokens) {
-					for i, v := range fromTokens {
-						base := v
-						quote := toTokens[i]
-
-						pairToken := &PairToken{
-							BaseToken:  base,
-							QuoteToken: quote,
-						}
-						relayerInfo.Pairs = append(relayerInfo.Pairs, pairToken)
-					}
-				}
+	relayerInfo.RID, _ = strconv.Atoi(record.Index.String())
+	relayerInfo.Owner = record.Owner
+	relayerInfo.Deposit = record.Deposit
+	relayerInfo.MakeFee = record.MakeFee
+	relayerInfo.TakeFee = record.TakeFee
+	setToken := utils.Union(record.FromTokens, record.ToTokens)
+	lockTime, _ := b.GetRelayerResignStatus(ctx, contractAddress, coinAddress)
+	relayerInfo.LockTime, _ = strconv.Atoi(lockTime.String())
+	if relayerInfo.Resign = false; relayerInfo.LockTime > 0 {
+		relayerInfo.Resign = true
+	}
+	relayerInfo.Status = RelayerStatus(int64(relayerInfo.LockTime), time.Now())
+	tokens, err := b.GetTokensInfo(ctx, setToken, &abiToken)
+	if err != nil {
+		return nil, err
+	}
+	for t, tokenInfo := range tokens {
+		relayerInfo.Tokens[t] = tokenInfo
+		logger.Debug("Token data:", tokenInfo.Name, tokenInfo.Symbol)
+	}
+	if len(record.FromTokens) == len(record.ToTokens) {
+		for i, v := range record.FromTokens {
+			base := v
+			quote := record.ToTokens[i]
 
+			pairToken := &PairToken{
+				BaseToken:  base,
+				QuoteToken: quote,
 			}
+			relayerInfo.Pairs = append(relayerInfo.Pairs, pairToken)
 		}
-	} else {
-		return &relayerInfo, errors.New("Can not get relayer information")
 	}
 
 	return &relayerInfo, nil
 }
 
-func (b *Blockchain) GetLendingRelayers(registrationAddress common.Address, lendingAddress common.Address) ([]*LendingRInfo, error) {
-	count, _ := b.GetRelayerCount(registrationAddress)
+func (b *Blockchain) GetLendingRelayers(ctx context.Context, registrationAddress common.Address, lendingAddress common.Address) ([]*LendingRInfo, error) {
+	count, _ := b.GetRelayerCount(ctx, registrationAddress)
 
 	var rLInfos []*LendingRInfo
 	logger.Debug("Lending Relayer count", count.String())
 	for i := int64(0); i < int64(count.Uint64()); i++ {
-		coinbase, _ := b.GetRelayerCoinBaseByIndex(i, registrationAddress)
-		rLInfo, _ := b.GetLendingRelayer(coinbase, lendingAddress)
+		coinbase, _ := b.GetRelayerCoinBaseByIndex(ctx, i, registrationAddress)
+		rLInfo, _ := b.GetLendingRelayer(ctx, coinbase, lendingAddress)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
 		rLInfos = append(rLInfos, rLInfo)
 	}
 
@@ -352,7 +541,7 @@ func (b *Blockchain) GetLendingRelayers(registrationAddress common.Address, lend
 }
 
 // GetLendingRelayer return all lending pair in smart contract
-func (b *Blockchain) GetLendingRelayer(coinAddress common.Address, contractAddress common.Address) (*LendingRInfo, error) {
+func (b *Blockchain) GetLendingRelayer(ctx context.Context, coinAddress common.Address, contractAddress common.Address) (*LendingRInfo, error) {
 	logger.Debug("GetLendingRelayer:", coinAddress.Hex(), contractAddress.Hex())
 	abiRelayer, err := relayerAbi.GetLendingAbi()
 	if err != nil {
@@ -368,7 +557,7 @@ func (b *Blockchain) GetLendingRelayer(coinAddress common.Address, contractAddre
 		return nil, err
 	}
 	msg := ether.CallMsg{To: &contractAddress, Data: input}
-	result, err := b.ethclient.CallContract(context.Background(), msg, nil)
+	result, err := b.callContract(ctx, msg)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -377,9 +566,11 @@ func (b *Blockchain) GetLendingRelayer(coinAddress common.Address, contractAddre
 
 	lendingRInfo := LendingRInfo{
 		ColateralTokens: make(map[common.Address]*TokenInfo),
+		Collaterals:     make(map[common.Address]*CollateralInfo),
 		LendingTokens:   make(map[common.Address]*TokenInfo),
 		Address:         coinAddress,
 	}
+	collaterals := []common.Address{}
 
 	if method, ok := abiRelayer.Methods["getLendingRelayerByCoinbase"]; ok {
 		contractData, err := method.Outputs.UnpackValues(result)
@@ -388,20 +579,15 @@ func (b *Blockchain) GetLendingRelayer(coinAddress common.Address, contractAddre
 				lendingRInfo.Fee = contractData[0].(uint16)
 				termList := contractData[2].([]*big.Int)
 				lendingTokenList := contractData[1].([]common.Address)
+				collaterals = contractData[3].([]common.Address)
 				setLendingToken := utils.Union(lendingTokenList, lendingTokenList)
-				for _, t := range setLendingToken {
-					if utils.IsNativeTokenByAddress(t) {
-						tokenInfo := b.setBaseTokenInfo()
-						lendingRInfo.LendingTokens[t] = tokenInfo
-					} else {
-						tokenInfo, err := b.GetTokenInfo(t, &abiToken)
-						if err != nil {
-							return nil, err
-						}
-						lendingRInfo.LendingTokens[t] = tokenInfo
-						logger.Debug("Token data:", tokenInfo.Name, tokenInfo.Symbol)
-					}
-
+				tokens, err := b.GetTokensInfo(ctx, setLendingToken, &abiToken)
+				if err != nil {
+					return nil, err
+				}
+				for t, tokenInfo := range tokens {
+					lendingRInfo.LendingTokens[t] = tokenInfo
+					logger.Debug("Token data:", tokenInfo.Name, tokenInfo.Symbol)
 				}
 				if len(termList) == len(lendingTokenList) {
 					for i, v := range termList {
@@ -423,34 +609,133 @@ func (b *Blockchain) GetLendingRelayer(coinAddress common.Address, contractAddre
 		return &lendingRInfo, errors.New("Can not get relayer information")
 	}
 
-	for i := 0; i < len(lendingRInfo.LendingPairs); i++ {
-		input, err = abiRelayer.Pack("COLLATERALS", big.NewInt(int64(i)))
-		if err != nil {
-			return nil, err
+	// the relayers registered without collaterals use the collaterals of the lending contract
+	if len(collaterals) == 0 {
+		for i := 0; i < len(lendingRInfo.LendingPairs); i++ {
+			input, err = abiRelayer.Pack("COLLATERALS", big.NewInt(int64(i)))
+			if err != nil {
+				return nil, err
+			}
+
+			msg = ether.CallMsg{To: &contractAddress, Data: input}
+			result, err = b.callContract(ctx, msg)
+			if err != nil {
+				logger.Error(err)
+				return nil, err
+			}
+			var unpackResult interface{}
+			err = abiRelayer.Unpack(&unpackResult, "COLLATERALS", result)
+			if err == nil {
+				collaterals = append(collaterals, unpackResult.(common.Address))
+			}
+
 		}
+	}
 
-		msg = ether.CallMsg{To: &contractAddress, Data: input}
-		result, err = b.ethclient.CallContract(context.Background(), msg, nil)
+	tokens, err := b.GetTokensInfo(ctx, collaterals, &abiToken)
+	if err != nil {
+		return nil, err
+	}
+	for t, tokenInfo := range tokens {
+		lendingRInfo.ColateralTokens[t] = tokenInfo
+	}
+
+	lendingTokens := []common.Address{}
+	for t := range lendingRInfo.LendingTokens {
+		lendingTokens = append(lendingTokens, t)
+	}
+
+	for t := range lendingRInfo.ColateralTokens {
+		info, err := b.GetCollateralInfo(ctx, t, lendingTokens, contractAddress)
 		if err != nil {
-			logger.Error(err)
 			return nil, err
 		}
-		var unpackResult interface{}
-		err = abiRelayer.Unpack(&unpackResult, "COLLATERALS", result)
-		if err == nil {
-			t := unpackResult.(common.Address)
-			if utils.IsNativeTokenByAddress(t) {
-				tokenInfo := b.setBaseTokenInfo()
-				lendingRInfo.ColateralTokens[t] = tokenInfo
-			} else {
-				tokenInfo, err := b.GetTokenInfo(t, &abiToken)
-				if err != nil {
-					return nil, err
-				}
-				lendingRInfo.ColateralTokens[t] = tokenInfo
-			}
-		}
 
+		lendingRInfo.Collaterals[t] = info
 	}
+
 	return &lendingRInfo, nil
 }
+
+// GetCollateralInfo reads the rates of a collateral token and its prices in the lending tokens. A lending
+// token without price in the price feed, or a contract without price feed, takes the price of the collateral list
+func (b *Blockchain) GetCollateralInfo(ctx context.Context, token common.Address, lendingTokens []common.Address, contractAddress common.Address) (*CollateralInfo, error) {
+	abiLending, err := relayerAbi.GetLendingAbi()
+	if err != nil {
+		return nil, err
+	}
+
+	var params struct {
+		DepositRate     *big.Int `abi:"_depositRate"`
+		LiquidationRate *big.Int `abi:"_liquidationRate"`
+		Price           *big.Int `abi:"_price"`
+	}
+
+	err = b.CallContract(ctx, contractAddress, &abiLending, "COLLATERAL_LIST", &params, token)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &CollateralInfo{
+		DepositRate:     params.DepositRate,
+		LiquidationRate: params.LiquidationRate,
+		Price:           params.Price,
+		Prices:          make(map[common.Address]*big.Int),
+	}
+
+	for _, lendingToken := range lendingTokens {
+		var feed struct {
+			Price       *big.Int `abi:"price"`
+			BlockNumber *big.Int `abi:"blockNumber"`
+		}
+
+		err = b.CallContract(ctx, contractAddress, &abiLending, "getCollateralPrice", &feed, token, lendingToken)
+		if err != nil || feed.Price == nil || feed.Price.Sign() == 0 {
+			logger.Debug("No feed price of collateral:", token.Hex(), lendingToken.Hex(), err)
+			info.Prices[lendingToken] = info.Price
+			continue
+		}
+
+		info.Prices[lendingToken] = feed.Price
+	}
+
+	return info, nil
+}
+
+// GetRelayerDeposit reads the deposit of a relayer, the minimum deposit and the resign request of the relayer
+// from the registration contract. The owner is empty if the coinbase is not registered
+func (b *Blockchain) GetRelayerDeposit(ctx context.Context, coinbase common.Address, contractAddress common.Address) (*RelayerDeposit, error) {
+	abiRelayer, err := relayerAbi.GetRelayerAbi()
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := b.getRelayerListEntry(ctx, coinbase, contractAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	var minimum *big.Int
+	err = b.CallContract(ctx, contractAddress, &abiRelayer, "MinimumDeposit", &minimum)
+	if err != nil {
+		return nil, err
+	}
+
+	releaseTime, err := b.GetRelayerResignStatus(ctx, contractAddress, coinbase)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &RelayerDeposit{
+		Coinbase:       coinbase,
+		Owner:          info.Owner,
+		Deposit:        info.Deposit,
+		MinimumDeposit: minimum,
+		BelowMinimum:   info.Deposit.Cmp(minimum) < 0,
+		Resigned:       releaseTime.Sign() > 0,
+		ReleaseTime:    releaseTime.Int64(),
+	}
+
+	d.Locked = !d.Resigned || time.Now().Unix() < d.ReleaseTime
+	return d, nil
+}
@@ -0,0 +1,274 @@
+package relayer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	relayerAbi "github.com/tomochain/tomox-sdk/relayer/abi"
+)
+
+const quoteAbi = `[{"constant":true,"inputs":[{"name":"amount","type":"uint256"}],"name":"quote","outputs":[{"name":"price","type":"uint256"},{"name":"token","type":"address"}],"payable":false,"stateMutability":"view","type":"function"}]`
+
+func TestCallContract(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(quoteAbi))
+	assert.Nil(t, err)
+
+	token := common.HexToAddress("0x2")
+	output := append(word(5), common.LeftPadBytes(token.Bytes(), 32)...)
+
+	var input []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := struct {
+			ID     json.RawMessage          `json:"id"`
+			Params []map[string]interface{} `json:"params"`
+		}{}
+		json.NewDecoder(r.Body).Decode(&req)
+		input, _ = hexutil.Decode(req.Params[0]["data"].(string))
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": hexutil.Encode(output)})
+	}))
+	defer server.Close()
+
+	bc := NewBlockchain(nil, nil, nil)
+	bc.pool = NewRPCPool([]string{server.URL}, 1, time.Millisecond, time.Millisecond, 0)
+
+	res := struct {
+		Price *big.Int
+		Token common.Address
+	}{}
+	err = bc.CallContract(context.Background(), common.HexToAddress("0x1"), &parsed, "quote", &res, big.NewInt(7))
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(5), res.Price)
+	assert.Equal(t, token, res.Token)
+
+	// the arguments are packed after the selector of the method
+	assert.Equal(t, parsed.Methods["quote"].Id(), input[:4])
+	assert.Equal(t, word(7), input[4:])
+
+	err = bc.CallContract(context.Background(), common.HexToAddress("0x1"), &parsed, "quote", &res, "7")
+	assert.NotNil(t, err)
+}
+
+func TestGetCollateralInfo(t *testing.T) {
+	lendingAbi, err := relayerAbi.GetLendingAbi()
+	assert.Nil(t, err)
+
+	usdt := common.HexToAddress("0x10")
+	btc := common.HexToAddress("0x11")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := struct {
+			ID     json.RawMessage          `json:"id"`
+			Params []map[string]interface{} `json:"params"`
+		}{}
+		json.NewDecoder(r.Body).Decode(&req)
+		input, _ := hexutil.Decode(req.Params[0]["data"].(string))
+
+		res := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		switch {
+		case bytes.Equal(input[:4], lendingAbi.Methods["COLLATERAL_LIST"].Id()):
+			res["result"] = hexutil.Encode(append(append(word(150), word(110)...), word(7)...))
+		case bytes.Equal(input[4+32:], common.LeftPadBytes(usdt.Bytes(), 32)):
+			res["result"] = hexutil.Encode(append(word(9), word(100)...))
+		default:
+			res["error"] = map[string]interface{}{"code": -32000, "message": "execution reverted"}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(res)
+	}))
+	defer server.Close()
+
+	bc := NewBlockchain(nil, nil, nil)
+	bc.pool = NewRPCPool([]string{server.URL}, 1, time.Millisecond, time.Millisecond, 0)
+
+	info, err := bc.GetCollateralInfo(context.Background(), common.HexToAddress("0x2"), []common.Address{usdt, btc}, common.HexToAddress("0x1"))
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(150), info.DepositRate)
+	assert.Equal(t, big.NewInt(110), info.LiquidationRate)
+	assert.Equal(t, big.NewInt(9), info.Prices[usdt])
+
+	// the lending tokens missing from the price feed take the price of the collateral list
+	assert.Equal(t, big.NewInt(7), info.Prices[btc])
+}
+
+func TestGetRelayerDeposit(t *testing.T) {
+	relayerAbi, err := relayerAbi.GetRelayerAbi()
+	assert.Nil(t, err)
+
+	owner := common.HexToAddress("0x20")
+	releaseTime := time.Now().Add(time.Hour).Unix()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := struct {
+			ID     json.RawMessage          `json:"id"`
+			Params []map[string]interface{} `json:"params"`
+		}{}
+		json.NewDecoder(r.Body).Decode(&req)
+		input, _ := hexutil.Decode(req.Params[0]["data"].(string))
+
+		res := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		switch {
+		case bytes.Equal(input[:4], relayerAbi.Methods["RELAYER_LIST"].Id()):
+			res["result"] = hexutil.Encode(append(append(append(word(15000), word(10)...), word(1)...), common.LeftPadBytes(owner.Bytes(), 32)...))
+		case bytes.Equal(input[:4], relayerAbi.Methods["MinimumDeposit"].Id()):
+			res["result"] = hexutil.Encode(word(25000))
+		case bytes.Equal(input[:4], relayerAbi.Methods["RESIGN_REQUESTS"].Id()):
+			res["result"] = hexutil.Encode(word(int(releaseTime)))
+		default:
+			res["error"] = map[string]interface{}{"code": -32000, "message": "execution reverted"}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(res)
+	}))
+	defer server.Close()
+
+	bc := NewBlockchain(nil, nil, nil)
+	bc.pool = NewRPCPool([]string{server.URL}, 1, time.Millisecond, time.Millisecond, 0)
+
+	d, err := bc.GetRelayerDeposit(context.Background(), common.HexToAddress("0x3"), common.HexToAddress("0x1"))
+	assert.Nil(t, err)
+	assert.Equal(t, owner, d.Owner)
+	assert.Equal(t, big.NewInt(15000), d.Deposit)
+	assert.Equal(t, big.NewInt(25000), d.MinimumDeposit)
+	assert.True(t, d.BelowMinimum)
+	assert.True(t, d.Resigned)
+
+	// the deposit of a resigned relayer stays locked until the release time
+	assert.True(t, d.Locked)
+}
+
+func TestGetRelayersByOwnerAndID(t *testing.T) {
+	relayerAbi, err := relayerAbi.GetRelayerAbi()
+	assert.Nil(t, err)
+
+	operator := common.HexToAddress("0x20")
+	other := common.HexToAddress("0x21")
+	coinbases := []common.Address{common.HexToAddress("0x10"), common.HexToAddress("0x11"), common.HexToAddress("0x12")}
+	owners := map[common.Address]common.Address{coinbases[0]: operator, coinbases[1]: other, coinbases[2]: operator}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := struct {
+			ID     json.RawMessage          `json:"id"`
+			Params []map[string]interface{} `json:"params"`
+		}{}
+		json.NewDecoder(r.Body).Decode(&req)
+		input, _ := hexutil.Decode(req.Params[0]["data"].(string))
+
+		res := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		switch {
+		case bytes.Equal(input[:4], relayerAbi.Methods["RelayerCount"].Id()):
+			res["result"] = hexutil.Encode(word(len(coinbases)))
+		case bytes.Equal(input[:4], relayerAbi.Methods["RELAYER_COINBASES"].Id()):
+			i := new(big.Int).SetBytes(input[4:36]).Int64()
+			res["result"] = hexutil.Encode(common.LeftPadBytes(coinbases[i].Bytes(), 32))
+		case bytes.Equal(input[:4], relayerAbi.Methods["RELAYER_LIST"].Id()):
+			owner := owners[common.BytesToAddress(input[4:36])]
+			res["result"] = hexutil.Encode(append(append(append(word(15000), word(10)...), word(1)...), common.LeftPadBytes(owner.Bytes(), 32)...))
+		case bytes.Equal(input[:4], relayerAbi.Methods["getRelayerByCoinbase"].Id()):
+			owner := owners[common.BytesToAddress(input[4:36])]
+			output, _ := registrationDecoders[0].outputs.Pack(big.NewInt(1), owner, big.NewInt(15000), uint16(10), []common.Address{}, []common.Address{})
+			res["result"] = hexutil.Encode(output)
+		case bytes.Equal(input[:4], relayerAbi.Methods["RESIGN_REQUESTS"].Id()):
+			res["result"] = hexutil.Encode(word(0))
+		default:
+			res["error"] = map[string]interface{}{"code": -32000, "message": "execution reverted"}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(res)
+	}))
+	defer server.Close()
+
+	bc := NewBlockchain(nil, nil, nil)
+	bc.pool = NewRPCPool([]string{server.URL}, 1, time.Millisecond, time.Millisecond, 0)
+
+	infos, err := bc.GetRelayersByOwner(context.Background(), operator, common.HexToAddress("0x1"))
+	assert.Nil(t, err)
+	assert.Len(t, infos, 2)
+	assert.Equal(t, coinbases[0], infos[0].Address)
+	assert.Equal(t, coinbases[2], infos[1].Address)
+	assert.Equal(t, operator, infos[1].Owner)
+
+	infos, err = bc.GetRelayersByOwner(context.Background(), common.HexToAddress("0x22"), common.HexToAddress("0x1"))
+	assert.Nil(t, err)
+	assert.Len(t, infos, 0)
+
+	info, err := bc.GetRelayerByID(context.Background(), 1, common.HexToAddress("0x1"))
+	assert.Nil(t, err)
+	assert.Equal(t, coinbases[1], info.Address)
+	assert.Equal(t, other, info.Owner)
+
+	_, err = bc.GetRelayerByID(context.Background(), 3, common.HexToAddress("0x1"))
+	assert.Equal(t, ErrRelayerNotFound, err)
+}
+
+func TestGetTokenInfoTRC21(t *testing.T) {
+	tokenAbi, err := relayerAbi.GetTokenAbi()
+	assert.Nil(t, err)
+
+	trc21 := common.HexToAddress("0x2")
+	trc20 := common.HexToAddress("0x3")
+	issuer := common.HexToAddress("0x4")
+
+	symbol := append(word(32), word(3)...)
+	symbol = append(symbol, common.RightPadBytes([]byte("BTC"), 32)...)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := struct {
+			ID     json.RawMessage          `json:"id"`
+			Params []map[string]interface{} `json:"params"`
+		}{}
+		json.NewDecoder(r.Body).Decode(&req)
+		to := common.HexToAddress(req.Params[0]["to"].(string))
+		input, _ := hexutil.Decode(req.Params[0]["data"].(string))
+
+		res := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		switch {
+		case bytes.Equal(input[:4], tokenAbi.Methods["decimals"].Id()):
+			res["result"] = hexutil.Encode(word(8))
+		case bytes.Equal(input[:4], tokenAbi.Methods["issuer"].Id()) && to == trc21:
+			res["result"] = hexutil.Encode(common.LeftPadBytes(issuer.Bytes(), 32))
+		case bytes.Equal(input[:4], tokenAbi.Methods["minFee"].Id()) && to == trc21:
+			res["result"] = hexutil.Encode(word(1000))
+		case bytes.Equal(input[:4], tokenAbi.Methods["issuer"].Id()), bytes.Equal(input[:4], tokenAbi.Methods["minFee"].Id()):
+			res["error"] = map[string]interface{}{"code": -32000, "message": "execution reverted"}
+		default:
+			res["result"] = hexutil.Encode(symbol)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(res)
+	}))
+	defer server.Close()
+
+	bc := NewBlockchain(nil, nil, nil)
+	bc.pool = NewRPCPool([]string{server.URL}, 1, time.Millisecond, time.Millisecond, 0)
+
+	info, err := bc.GetTokenInfo(context.Background(), trc21, &tokenAbi)
+	assert.Nil(t, err)
+	assert.Equal(t, "BTC", info.Symbol)
+	assert.True(t, info.TRC21)
+	assert.Equal(t, issuer, info.Issuer)
+	assert.Equal(t, big.NewInt(1000), info.MinFee)
+
+	// a TRC20 token is read without its fee parameters
+	info, err = bc.GetTokenInfo(context.Background(), trc20, &tokenAbi)
+	assert.Nil(t, err)
+	assert.Equal(t, uint8(8), info.Decimals)
+	assert.False(t, info.TRC21)
+	assert.Nil(t, info.MinFee)
+}
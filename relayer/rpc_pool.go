@@ -0,0 +1,218 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	ether "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Defaults of the retries of the contract calls
+const (
+	defaultRPCAttempts   = 3
+	defaultRPCBackoff    = 500 * time.Millisecond
+	defaultRPCMaxBackoff = 10 * time.Second
+	defaultRPCTimeout    = 10 * time.Second
+)
+
+var errNoRPCEndpoint = errors.New("No RPC endpoint")
+
+// RPCPool sends the contract calls to the http endpoints of several nodes. A call goes to the endpoint
+// which answered last and fails over to the next ones, when all of them fail they are tried again after
+// an exponential backoff until the attempts are exhausted. An error returned by a node, such as a
+// reverted call, is the answer of the call and is not retried. Each request to an endpoint is aborted
+// after the timeout, the call stops failing over when its context is done
+type RPCPool struct {
+	urls       []string
+	clients    []*ethclient.Client
+	rpcClients []*rpc.Client
+	current    int
+	attempts   int
+	backoff    time.Duration
+	maxBackoff time.Duration
+	timeout    time.Duration
+	mutex      sync.Mutex
+}
+
+// NewRPCPool returns the pool of the endpoints, the first one is used until it fails.
+// The zero attempts, backoffs and timeout take their defaults
+func NewRPCPool(urls []string, attempts int, backoff, maxBackoff, timeout time.Duration) *RPCPool {
+	if attempts <= 0 {
+		attempts = defaultRPCAttempts
+	}
+
+	if backoff <= 0 {
+		backoff = defaultRPCBackoff
+	}
+
+	if maxBackoff <= 0 {
+		maxBackoff = defaultRPCMaxBackoff
+	}
+
+	if timeout <= 0 {
+		timeout = defaultRPCTimeout
+	}
+
+	return &RPCPool{
+		urls:       urls,
+		clients:    make([]*ethclient.Client, len(urls)),
+		rpcClients: make([]*rpc.Client, len(urls)),
+		attempts:   attempts,
+		backoff:    backoff,
+		maxBackoff: maxBackoff,
+		timeout:    timeout,
+	}
+}
+
+// CallContract executes the call on the first endpoint answering it, on the block of the context set with
+// WithBlock or the latest one
+func (p *RPCPool) CallContract(ctx context.Context, msg ether.CallMsg) ([]byte, error) {
+	var res []byte
+	err := p.do(ctx, func(ctx context.Context, i int, client *ethclient.Client) error {
+		var err error
+		res, err = p.call(ctx, i, client, msg)
+		return err
+	})
+
+	return res, err
+}
+
+// BatchCall sends the requests in a single JSON-RPC batch request to the first endpoint answering it,
+// the error of each request is set in its element
+func (p *RPCPool) BatchCall(ctx context.Context, elems []rpc.BatchElem) error {
+	return p.do(ctx, func(ctx context.Context, i int, client *ethclient.Client) error {
+		return p.rpcClient(i).BatchCallContext(ctx, elems)
+	})
+}
+
+// do sends the request to the endpoints until one answers it, see RPCPool
+func (p *RPCPool) do(ctx context.Context, request func(ctx context.Context, i int, client *ethclient.Client) error) error {
+	if len(p.urls) == 0 {
+		return errNoRPCEndpoint
+	}
+
+	err := errNoRPCEndpoint
+	delay := p.backoff
+	for attempt := 1; ; attempt++ {
+		start := p.currentIndex()
+		for n := 0; n < len(p.urls); n++ {
+			i := (start + n) % len(p.urls)
+			client, dialErr := p.client(i)
+			if dialErr != nil {
+				err = dialErr
+				logger.Warning("RPC endpoint not reachable:", p.urls[i], err)
+				continue
+			}
+
+			err = p.send(ctx, i, client, request)
+			if _, ok := err.(rpc.Error); err == nil || ok {
+				p.setCurrent(i)
+				return err
+			}
+
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			logger.Warning("RPC endpoint failed:", p.urls[i], err)
+		}
+
+		if attempt >= p.attempts {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+		if delay > p.maxBackoff {
+			delay = p.maxBackoff
+		}
+	}
+}
+
+// Timeout returns the timeout of the requests to the endpoints
+func (p *RPCPool) Timeout() time.Duration {
+	return p.timeout
+}
+
+// Endpoint returns the url and the client of the endpoint which answered last
+func (p *RPCPool) Endpoint() (string, *rpc.Client, error) {
+	if len(p.urls) == 0 {
+		return "", nil, errNoRPCEndpoint
+	}
+
+	i := p.currentIndex()
+	if _, err := p.client(i); err != nil {
+		return p.urls[i], nil, err
+	}
+
+	return p.urls[i], p.rpcClient(i), nil
+}
+
+// send sends the request to an endpoint, aborted after the timeout of the pool
+func (p *RPCPool) send(ctx context.Context, i int, client *ethclient.Client, request func(ctx context.Context, i int, client *ethclient.Client) error) error {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	return request(ctx, i, client)
+}
+
+// call executes the call on an endpoint, on the block of the context
+func (p *RPCPool) call(ctx context.Context, i int, client *ethclient.Client, msg ether.CallMsg) ([]byte, error) {
+	block := blockFromContext(ctx)
+	if block.IsLatest() {
+		return client.CallContract(ctx, msg, nil)
+	}
+
+	return callAt(ctx, p.rpcClient(i), msg, block)
+}
+
+// rpcClient returns the client of an endpoint already dialed
+func (p *RPCPool) rpcClient(i int) *rpc.Client {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.rpcClients[i]
+}
+
+func (p *RPCPool) currentIndex() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.current
+}
+
+func (p *RPCPool) setCurrent(i int) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.current != i {
+		logger.Infof("RPC calls failed over to %s", p.urls[i])
+		p.current = i
+	}
+}
+
+// client returns the client of the endpoint, dialed at its first use
+func (p *RPCPool) client(i int) (*ethclient.Client, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.clients[i] != nil {
+		return p.clients[i], nil
+	}
+
+	c, err := rpc.Dial(p.urls[i])
+	if err != nil {
+		return nil, err
+	}
+
+	p.rpcClients[i] = c
+	p.clients[i] = ethclient.NewClient(c)
+	return p.clients[i], nil
+}
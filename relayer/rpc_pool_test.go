@@ -0,0 +1,144 @@
+package relayer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	ether "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// rpcServer answers the eth_call requests with the result, or with the error of the node if set,
+// and counts them
+func rpcServer(result string, rpcError string, calls *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		req := map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		res := map[string]interface{}{"jsonrpc": "2.0", "id": req["id"]}
+		if rpcError != "" {
+			res["error"] = map[string]interface{}{"code": -32000, "message": rpcError}
+		} else {
+			res["result"] = result
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(res)
+	}))
+}
+
+func downServer(calls *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+}
+
+func TestRPCPoolFailover(t *testing.T) {
+	var downCalls, upCalls int32
+	down := downServer(&downCalls)
+	defer down.Close()
+	up := rpcServer("0x01", "", &upCalls)
+	defer up.Close()
+
+	to := common.HexToAddress("0x1")
+	msg := ether.CallMsg{To: &to}
+
+	p := NewRPCPool([]string{down.URL, up.URL}, 2, time.Millisecond, time.Millisecond, 0)
+	res, err := p.CallContract(context.Background(), msg)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{1}, res)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&downCalls))
+
+	// the endpoint which answered is called first
+	_, err = p.CallContract(context.Background(), msg)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&downCalls))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&upCalls))
+}
+
+func TestRPCPoolRetries(t *testing.T) {
+	var calls int32
+	down := downServer(&calls)
+	defer down.Close()
+
+	to := common.HexToAddress("0x1")
+	p := NewRPCPool([]string{down.URL}, 3, time.Millisecond, 2*time.Millisecond, 0)
+	_, err := p.CallContract(context.Background(), ether.CallMsg{To: &to})
+	assert.NotNil(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	_, err = NewRPCPool(nil, 0, 0, 0, 0).CallContract(context.Background(), ether.CallMsg{To: &to})
+	assert.Equal(t, errNoRPCEndpoint, err)
+}
+
+func TestRPCPoolNodeError(t *testing.T) {
+	var revertCalls, upCalls int32
+	revert := rpcServer("", "execution reverted", &revertCalls)
+	defer revert.Close()
+	up := rpcServer("0x01", "", &upCalls)
+	defer up.Close()
+
+	// the error of a node answering is the result of the call, the other endpoints are not tried
+	to := common.HexToAddress("0x1")
+	p := NewRPCPool([]string{revert.URL, up.URL}, 3, time.Millisecond, time.Millisecond, 0)
+	_, err := p.CallContract(context.Background(), ether.CallMsg{To: &to})
+	assert.EqualError(t, err, "execution reverted")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&revertCalls))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&upCalls))
+}
+
+func TestRPCPoolTimeout(t *testing.T) {
+	var slowCalls, upCalls int32
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&slowCalls, 1)
+		<-release
+	}))
+	defer slow.Close()
+	defer close(release)
+	up := rpcServer("0x01", "", &upCalls)
+	defer up.Close()
+
+	// the request to the slow endpoint is aborted after the timeout and the call fails over
+	to := common.HexToAddress("0x1")
+	p := NewRPCPool([]string{slow.URL, up.URL}, 1, time.Millisecond, time.Millisecond, 20*time.Millisecond)
+	res, err := p.CallContract(context.Background(), ether.CallMsg{To: &to})
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{1}, res)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&slowCalls))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&upCalls))
+}
+
+func TestRPCPoolCancel(t *testing.T) {
+	var slowCalls, upCalls int32
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&slowCalls, 1)
+		<-release
+	}))
+	defer slow.Close()
+	defer close(release)
+	up := rpcServer("0x01", "", &upCalls)
+	defer up.Close()
+
+	// the cancellation aborts the request in flight and the other endpoints are not tried
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	to := common.HexToAddress("0x1")
+	p := NewRPCPool([]string{slow.URL, up.URL}, 3, time.Millisecond, time.Millisecond, time.Minute)
+	start := time.Now()
+	_, err := p.CallContract(ctx, ether.CallMsg{To: &to})
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.True(t, time.Since(start) < time.Second)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&slowCalls))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&upCalls))
+}
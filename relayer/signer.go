@@ -1,33 +1,200 @@
 package relayer
 
 import (
+	"crypto/ecdsa"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"io/ioutil"
 
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/accounts/usbwallet"
 	ethereum "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tomochain/tomox-sdk/secrets"
 )
 
+// Backends of the relayer signer
+const (
+	SignerKey      = "key"
+	SignerKeystore = "keystore"
+	SignerLedger   = "ledger"
+	SignerTrezor   = "trezor"
+)
+
+var errNoHardwareWallet = errors.New("No hardware wallet found")
+
+// ErrUnknownSignerAccount is returned when signing for an account the signer does not hold
+var ErrUnknownSignerAccount = errors.New("Account not managed by the relayer signer")
+
+// SignerBackend holds the key of an account of the relayer and signs its transactions, with the
+// EIP-155 signer of the chain ID or the homestead signer without chain ID
+type SignerBackend interface {
+	Address() ethereum.Address
+	SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// Signer signs the transactions of the accounts of the relayers operated by the SDK, one per relayer
+// coinbase. The first account is the default one. The transactions are signed for the chain ID of the
+// signer, with the homestead signer when it is not set
 type Signer struct {
 	Keystore   string `json:"keystore_path"`
 	Passphrase string `json:"passphrase"`
-	opts       *bind.TransactOpts
-}
-
-func (self *Signer) GetTransactOpts() *bind.TransactOpts {
-	return self.opts
+	backend    SignerBackend
+	accounts   map[ethereum.Address]SignerBackend
+	addresses  []ethereum.Address
+	chainID    *big.Int
 }
 
 func (self *Signer) GetAddress() ethereum.Address {
-	return self.opts.From
+	return self.backend.Address()
 }
 
+// Sign signs the transaction with the default account for the chain ID of the signer
 func (self *Signer) Sign(tx *types.Transaction) (*types.Transaction, error) {
-	return self.opts.Signer(types.HomesteadSigner{}, self.GetAddress(), tx)
+	return self.backend.SignTx(tx, self.chainID)
+}
+
+// SignTx signs the transaction with the default account for a chain
+func (self *Signer) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return self.backend.SignTx(tx, chainID)
+}
+
+// SignFor signs the transaction with the account of a relayer coinbase for the chain ID of the signer
+func (self *Signer) SignFor(coinbase ethereum.Address, tx *types.Transaction) (*types.Transaction, error) {
+	backend, ok := self.accounts[coinbase]
+	if !ok {
+		return nil, ErrUnknownSignerAccount
+	}
+
+	return backend.SignTx(tx, self.chainID)
+}
+
+// Accounts returns the addresses of the accounts of the signer, the default one first
+func (self *Signer) Accounts() []ethereum.Address {
+	return append([]ethereum.Address{}, self.addresses...)
+}
+
+// HasAccount returns true if the signer holds the key of the address
+func (self *Signer) HasAccount(address ethereum.Address) bool {
+	_, ok := self.accounts[address]
+	return ok
+}
+
+// ChainID returns the chain ID the transactions are signed for, nil for the homestead signer
+func (self *Signer) ChainID() *big.Int {
+	return self.chainID
+}
+
+// NewSignerWithBackend returns a signer of a single account whose transactions are signed by the backend
+// with the homestead signer
+func NewSignerWithBackend(backend SignerBackend) *Signer {
+	signer, _ := NewSignerWithBackends([]SignerBackend{backend}, nil)
+	return signer
+}
+
+// NewSignerWithBackends returns a signer of the accounts of the backends, the first one is the default
+// account. An account held by two backends is refused
+func NewSignerWithBackends(backends []SignerBackend, chainID *big.Int) (*Signer, error) {
+	if len(backends) == 0 {
+		return nil, errors.New("No relayer signer account")
+	}
+
+	signer := &Signer{
+		backend:  backends[0],
+		accounts: make(map[ethereum.Address]SignerBackend),
+		chainID:  chainID,
+	}
+
+	for _, b := range backends {
+		if signer.HasAccount(b.Address()) {
+			return nil, fmt.Errorf("Duplicate relayer signer account %s", b.Address().Hex())
+		}
+
+		signer.accounts[b.Address()] = b
+		signer.addresses = append(signer.addresses, b.Address())
+		logger.Debug("auth: ", b.Address().Hex())
+	}
+
+	return signer, nil
+}
+
+// NewSignerFromConfig returns the signer of the "relayer_signer" config section. The key backend,
+// the default, uses the keystore of the secrets provider or the compiled in one, the keystore backend
+// the keystore files (UTC JSON) of the comma separated list and the ledger and trezor backends the
+// accounts of the first device connected at the comma separated derivation paths, the default one
+// without them. The passphrase of the keystores is read from the secrets provider, else from the
+// section. The transactions are signed for the chain_id of the section when set
+func NewSignerFromConfig(config map[string]string) (*Signer, error) {
+	var chainID *big.Int
+	if config["chain_id"] != "" {
+		id, ok := new(big.Int).SetString(config["chain_id"], 10)
+		if !ok || id.Sign() <= 0 {
+			return nil, fmt.Errorf("Invalid relayer signer chain ID %s", config["chain_id"])
+		}
+
+		chainID = id
+	}
+
+	passphrase, err := secrets.Get(secrets.RelayerPassphrase)
+	if err != nil {
+		passphrase = config["passphrase"]
+	}
+
+	backends := []SignerBackend{}
+	switch config["backend"] {
+	case "", SignerKey:
+		passParser, keyio := GetKeyStore()
+		backend, err := NewKeyBackend(keyio, passParser)
+		if err != nil {
+			return nil, err
+		}
+
+		backends = append(backends, backend)
+	case SignerKeystore:
+		paths := splitList(config["keystore"])
+		if len(paths) == 0 {
+			return nil, errors.New("Keystore file not set")
+		}
+
+		for _, path := range paths {
+			backend, err := NewKeystoreBackend(path, passphrase)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s", path, err)
+			}
+
+			backends = append(backends, backend)
+		}
+	case SignerLedger, SignerTrezor:
+		backends, err = NewUSBBackends(config["backend"], splitList(config["derivation_path"]))
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("Unknown relayer signer backend %s", config["backend"])
+	}
+
+	return NewSignerWithBackends(backends, chainID)
+}
+
+// splitList returns the trimmed values of a comma separated list
+func splitList(list string) []string {
+	res := []string{}
+	for _, v := range strings.Split(list, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			res = append(res, v)
+		}
+	}
+
+	return res
 }
 
 func NewSignerFile(file string, fileLocation string) *Signer {
@@ -46,27 +213,146 @@ func NewSignerFile(file string, fileLocation string) *Signer {
 		panic(err)
 	}
 	logger.Debug("keyio: ", keyio)
-	auth, err := bind.NewTransactor(keyio, signer.Passphrase)
+	backend, err := NewKeyBackend(keyio, signer.Passphrase)
 	if err != nil {
 		panic(err)
 	}
-	logger.Debug("auth: ", auth.From.Hex())
-	signer.opts = auth
 
-	return signer
+	s := NewSignerWithBackend(backend)
+	s.Keystore = signer.Keystore
+	s.Passphrase = signer.Passphrase
+	return s
 }
 
 func NewSigner() *Signer {
-	signer := &Signer{}
 	passParser, keyio := GetKeyStore()
-	signer.Passphrase = passParser
-
-	auth, err := bind.NewTransactor(keyio, signer.Passphrase)
+	backend, err := NewKeyBackend(keyio, passParser)
 	if err != nil {
 		panic(err)
 	}
-	logger.Debug("auth: ", auth.From.Hex())
-	signer.opts = auth
 
+	signer := NewSignerWithBackend(backend)
+	signer.Passphrase = passParser
 	return signer
 }
+
+// keyBackend signs with a private key held in memory
+type keyBackend struct {
+	key *ecdsa.PrivateKey
+}
+
+// NewKeyBackend decrypts an encrypted key (UTC JSON) with its passphrase
+func NewKeyBackend(keyio io.Reader, passphrase string) (SignerBackend, error) {
+	keyjson, err := ioutil.ReadAll(keyio)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := keystore.DecryptKey(keyjson, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return &keyBackend{key.PrivateKey}, nil
+}
+
+// NewKeystoreBackend decrypts the key of a keystore file with its passphrase
+func NewKeystoreBackend(path string, passphrase string) (SignerBackend, error) {
+	if path == "" {
+		return nil, errors.New("Keystore file not set")
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+	return NewKeyBackend(f, passphrase)
+}
+
+func (b *keyBackend) Address() ethereum.Address {
+	return crypto.PubkeyToAddress(b.key.PublicKey)
+}
+
+func (b *keyBackend) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	var signer types.Signer = types.HomesteadSigner{}
+	if chainID != nil {
+		signer = types.NewEIP155Signer(chainID)
+	}
+
+	return types.SignTx(tx, signer, b.key)
+}
+
+// usbBackend signs on a Ledger or a Trezor, the key never leaves the device
+type usbBackend struct {
+	wallet  accounts.Wallet
+	account accounts.Account
+}
+
+// NewUSBBackends opens the first Ledger or Trezor connected and derives the accounts at the derivation
+// paths, m/44'/60'/0'/0/0 without them. A Trezor must be unlocked beforehand as its PIN is entered on
+// the device
+func NewUSBBackends(kind string, paths []string) ([]SignerBackend, error) {
+	derivationPaths := []accounts.DerivationPath{}
+	for _, path := range paths {
+		p, err := accounts.ParseDerivationPath(path)
+		if err != nil {
+			return nil, err
+		}
+
+		derivationPaths = append(derivationPaths, p)
+	}
+
+	if len(derivationPaths) == 0 {
+		derivationPaths = append(derivationPaths, accounts.DefaultBaseDerivationPath)
+	}
+
+	var hub *usbwallet.Hub
+	var err error
+	switch kind {
+	case SignerLedger:
+		hub, err = usbwallet.NewLedgerHub()
+	case SignerTrezor:
+		hub, err = usbwallet.NewTrezorHub()
+	default:
+		err = fmt.Errorf("Unknown hardware wallet %s", kind)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	wallets := hub.Wallets()
+	if len(wallets) == 0 {
+		return nil, errNoHardwareWallet
+	}
+
+	wallet := wallets[0]
+	err = wallet.Open("")
+	if err != nil {
+		return nil, err
+	}
+
+	backends := []SignerBackend{}
+	for _, p := range derivationPaths {
+		account, err := wallet.Derive(p, true)
+		if err != nil {
+			wallet.Close()
+			return nil, err
+		}
+
+		logger.Info("Relayer signer on", wallet.URL(), "at", p.String())
+		backends = append(backends, &usbBackend{wallet, account})
+	}
+
+	return backends, nil
+}
+
+func (b *usbBackend) Address() ethereum.Address {
+	return b.account.Address
+}
+
+func (b *usbBackend) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return b.wallet.SignTx(b.account, tx, chainID)
+}
@@ -0,0 +1,110 @@
+package relayer
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeKeystore(t *testing.T, dir string, passphrase string) (string, common.Address) {
+	privateKey, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+
+	key := &keystore.Key{
+		Address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+		PrivateKey: privateKey,
+	}
+
+	keyjson, err := keystore.EncryptKey(key, passphrase, keystore.LightScryptN, keystore.LightScryptP)
+	assert.NoError(t, err)
+
+	path := filepath.Join(dir, "UTC--"+key.Address.Hex())
+	assert.NoError(t, ioutil.WriteFile(path, keyjson, 0600))
+	return path, key.Address
+}
+
+func TestKeystoreSigner(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signer")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path, address := writeKeystore(t, dir, "secret")
+
+	signer, err := NewSignerFromConfig(map[string]string{"backend": SignerKeystore, "keystore": path, "passphrase": "secret"})
+	assert.NoError(t, err)
+	assert.Equal(t, address, signer.GetAddress())
+
+	tx := types.NewTransaction(0, common.HexToAddress("0x2"), big.NewInt(1), 21000, big.NewInt(1), nil)
+	signed, err := signer.Sign(tx)
+	assert.NoError(t, err)
+
+	sender, err := types.Sender(types.HomesteadSigner{}, signed)
+	assert.NoError(t, err)
+	assert.Equal(t, address, sender)
+
+	signed, err = signer.SignTx(tx, big.NewInt(88))
+	assert.NoError(t, err)
+
+	sender, err = types.Sender(types.NewEIP155Signer(big.NewInt(88)), signed)
+	assert.NoError(t, err)
+	assert.Equal(t, address, sender)
+
+	_, err = NewSignerFromConfig(map[string]string{"backend": SignerKeystore, "keystore": path, "passphrase": "wrong"})
+	assert.Error(t, err)
+
+	_, err = NewSignerFromConfig(map[string]string{"backend": SignerKeystore})
+	assert.Error(t, err)
+
+	_, err = NewSignerFromConfig(map[string]string{"backend": "hsm"})
+	assert.Error(t, err)
+}
+
+func TestSignerAccounts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signer")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	first, firstAddress := writeKeystore(t, dir, "secret")
+	second, secondAddress := writeKeystore(t, dir, "secret")
+
+	signer, err := NewSignerFromConfig(map[string]string{
+		"backend":    SignerKeystore,
+		"keystore":   first + ", " + second,
+		"passphrase": "secret",
+		"chain_id":   "88",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []common.Address{firstAddress, secondAddress}, signer.Accounts())
+	assert.Equal(t, firstAddress, signer.GetAddress())
+	assert.Equal(t, big.NewInt(88), signer.ChainID())
+
+	// the transactions of each coinbase are signed with its key for the chain ID
+	tx := types.NewTransaction(0, common.HexToAddress("0x2"), big.NewInt(1), 21000, big.NewInt(1), nil)
+	signed, err := signer.SignFor(secondAddress, tx)
+	assert.NoError(t, err)
+
+	sender, err := types.Sender(types.NewEIP155Signer(big.NewInt(88)), signed)
+	assert.NoError(t, err)
+	assert.Equal(t, secondAddress, sender)
+
+	assert.True(t, signed.Protected())
+
+	_, err = signer.SignFor(common.HexToAddress("0x3"), tx)
+	assert.Equal(t, ErrUnknownSignerAccount, err)
+
+	// an account is held once
+	_, err = NewSignerFromConfig(map[string]string{"backend": SignerKeystore, "keystore": first + "," + first, "passphrase": "secret"})
+	assert.Error(t, err)
+
+	_, err = NewSignerFromConfig(map[string]string{"backend": SignerKeystore, "keystore": first, "passphrase": "secret", "chain_id": "-1"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,72 @@
+package relayer
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// RelayerSnapshot is the spot and the lending registration of a relayer read together. Hash identifies
+// the state of both, it changes with any of their tokens, pairs, fees, status or collateral prices
+type RelayerSnapshot struct {
+	Coinbase common.Address `json:"coinbase"`
+	Spot     *RInfo         `json:"spot"`
+	Lending  *LendingRInfo  `json:"lending"`
+	Hash     common.Hash    `json:"hash"`
+}
+
+// Changed returns true if the state of the snapshot differs from a previous one, a nil previous
+// snapshot is a change
+func (s *RelayerSnapshot) Changed(prev *RelayerSnapshot) bool {
+	return prev == nil || prev.Hash != s.Hash
+}
+
+// GetFullRelayerSnapshot reads the spot and the lending registrations of a relayer. The metadata of the
+// tokens is cached for the duration of the snapshot at least, so that a token listed on both sides is
+// read once
+func (b *Blockchain) GetFullRelayerSnapshot(ctx context.Context, coinbase common.Address, relayerAddress common.Address, lendingAddress common.Address) (*RelayerSnapshot, error) {
+	sb := *b
+	if sb.cache == nil {
+		sb.cache = NewTokenInfoCache(0)
+	}
+
+	spot, err := sb.GetRelayer(ctx, coinbase, relayerAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	lending, err := sb.GetLendingRelayer(ctx, coinbase, lendingAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := SnapshotHash(spot, lending)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RelayerSnapshot{
+		Coinbase: coinbase,
+		Spot:     spot,
+		Lending:  lending,
+		Hash:     hash,
+	}, nil
+}
+
+// SnapshotHash returns the hash of the spot and the lending registrations of a relayer. The maps are
+// encoded in the order of their keys, so the same state always gives the same hash
+func SnapshotHash(spot *RInfo, lending *LendingRInfo) (common.Hash, error) {
+	s, err := json.Marshal(spot)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	l, err := json.Marshal(lending)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	return crypto.Keccak256Hash(s, l), nil
+}
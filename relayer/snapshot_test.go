@@ -0,0 +1,55 @@
+package relayer
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotHash(t *testing.T) {
+	coinbase := common.HexToAddress("0x1")
+	a := common.HexToAddress("0xa")
+	b := common.HexToAddress("0xb")
+
+	spot := func() *RInfo {
+		return &RInfo{
+			Address: coinbase,
+			Tokens:  map[common.Address]*TokenInfo{a: {Symbol: "A"}, b: {Symbol: "B"}},
+			Pairs:   []*PairToken{{a, b}},
+			MakeFee: 10,
+			TakeFee: 10,
+		}
+	}
+
+	lending := &LendingRInfo{
+		Address:       coinbase,
+		LendingTokens: map[common.Address]*TokenInfo{b: {Symbol: "B"}},
+		Collaterals:   map[common.Address]*CollateralInfo{a: {Price: big.NewInt(100), Prices: map[common.Address]*big.Int{b: big.NewInt(100)}}},
+		Fee:           5,
+	}
+
+	h, err := SnapshotHash(spot(), lending)
+	assert.Nil(t, err)
+
+	same, err := SnapshotHash(spot(), lending)
+	assert.Nil(t, err)
+	assert.Equal(t, h, same)
+
+	repriced := spot()
+	repriced.TakeFee = 20
+	changed, err := SnapshotHash(repriced, lending)
+	assert.Nil(t, err)
+	assert.NotEqual(t, h, changed)
+
+	lending.Collaterals[a].Prices[b] = big.NewInt(90)
+	changed, err = SnapshotHash(spot(), lending)
+	assert.Nil(t, err)
+	assert.NotEqual(t, h, changed)
+
+	s := &RelayerSnapshot{Hash: h}
+	assert.True(t, s.Changed(nil))
+	assert.False(t, s.Changed(&RelayerSnapshot{Hash: same}))
+	assert.True(t, s.Changed(&RelayerSnapshot{Hash: changed}))
+}
@@ -0,0 +1,74 @@
+package relayer
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultTokenInfoTTL is how long the metadata of a token is cached when no TTL is configured,
+// the name, symbol and decimals of a deployed token do not change
+const DefaultTokenInfoTTL = 24 * time.Hour
+
+// cachedTokenInfo is the metadata of a token with the time it expires
+type cachedTokenInfo struct {
+	info      *TokenInfo
+	expiresAt time.Time
+}
+
+// TokenInfoCache holds the metadata of the tokens read from the chain so that the relayer refreshes
+// do not read it again until it expires
+type TokenInfoCache struct {
+	mutex   sync.RWMutex
+	ttl     time.Duration
+	entries map[common.Address]*cachedTokenInfo
+}
+
+// NewTokenInfoCache returns a cache keeping the metadata of the tokens for the TTL
+func NewTokenInfoCache(ttl time.Duration) *TokenInfoCache {
+	if ttl <= 0 {
+		ttl = DefaultTokenInfoTTL
+	}
+
+	return &TokenInfoCache{
+		ttl:     ttl,
+		entries: make(map[common.Address]*cachedTokenInfo),
+	}
+}
+
+// Get returns the metadata of a token if it is cached and not expired
+func (c *TokenInfoCache) Get(token common.Address) (*TokenInfo, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	e := c.entries[token]
+	if e == nil || time.Now().After(e.expiresAt) {
+		return nil, false
+	}
+
+	return e.info, true
+}
+
+// Set caches the metadata of a token
+func (c *TokenInfoCache) Set(token common.Address, info *TokenInfo) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.entries[token] = &cachedTokenInfo{info: info, expiresAt: time.Now().Add(c.ttl)}
+}
+
+// Invalidate drops the metadata of the tokens, of all of them if none is given
+func (c *TokenInfoCache) Invalidate(tokens ...common.Address) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if len(tokens) == 0 {
+		c.entries = make(map[common.Address]*cachedTokenInfo)
+		return
+	}
+
+	for _, t := range tokens {
+		delete(c.entries, t)
+	}
+}
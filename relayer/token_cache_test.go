@@ -0,0 +1,38 @@
+package relayer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenInfoCache(t *testing.T) {
+	usdt := common.HexToAddress("0x1")
+	btc := common.HexToAddress("0x2")
+
+	c := NewTokenInfoCache(time.Hour)
+	c.Set(usdt, &TokenInfo{Symbol: "USDT"})
+	c.Set(btc, &TokenInfo{Symbol: "BTC"})
+
+	info, ok := c.Get(usdt)
+	assert.True(t, ok)
+	assert.Equal(t, "USDT", info.Symbol)
+
+	c.Invalidate(usdt)
+	_, ok = c.Get(usdt)
+	assert.False(t, ok)
+	_, ok = c.Get(btc)
+	assert.True(t, ok)
+
+	c.Invalidate()
+	_, ok = c.Get(btc)
+	assert.False(t, ok)
+
+	c = NewTokenInfoCache(time.Nanosecond)
+	c.Set(usdt, &TokenInfo{Symbol: "USDT"})
+	time.Sleep(time.Millisecond)
+	_, ok = c.Get(usdt)
+	assert.False(t, ok)
+}
@@ -0,0 +1,80 @@
+// Package secrets provides the credentials used by the SDK at runtime
+// so that they do not have to be stored in the configuration files
+package secrets
+
+import (
+	"errors"
+	"sync"
+)
+
+// Names of the secrets read by the SDK
+const (
+	MongoUsername     = "mongo_username"
+	MongoPassword     = "mongo_password"
+	RabbitMQURL       = "rabbitmq_url"
+	RelayerKeystore   = "relayer_keystore"
+	RelayerPassphrase = "relayer_passphrase"
+	FaucetPassphrase  = "faucet_passphrase"
+)
+
+// ErrNotFound is returned when a secret is not provided
+var ErrNotFound = errors.New("secrets: not found")
+
+// Provider returns the current value of a secret
+type Provider interface {
+	Get(name string) (string, error)
+	// OnChange registers a function called with the secrets that changed after a refresh
+	OnChange(fn func(changed map[string]string))
+}
+
+// StaticProvider serves secrets that never change, e.g. values from the config file
+type StaticProvider struct {
+	values map[string]string
+}
+
+// NewStaticProvider returns a new instance of StaticProvider
+func NewStaticProvider(values map[string]string) *StaticProvider {
+	return &StaticProvider{values}
+}
+
+// Get returns the value of a secret
+func (p *StaticProvider) Get(name string) (string, error) {
+	v, ok := p.values[name]
+	if !ok || v == "" {
+		return "", ErrNotFound
+	}
+
+	return v, nil
+}
+
+// OnChange is a no-op since static secrets are never refreshed
+func (p *StaticProvider) OnChange(fn func(changed map[string]string)) {}
+
+var (
+	defaultProvider Provider
+	mutex           sync.RWMutex
+)
+
+// SetDefault sets the provider returned by Default
+func SetDefault(p Provider) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	defaultProvider = p
+}
+
+// Default returns the configured provider, nil if none is configured
+func Default() Provider {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return defaultProvider
+}
+
+// Get returns a secret from the default provider
+func Get(name string) (string, error) {
+	p := Default()
+	if p == nil {
+		return "", ErrNotFound
+	}
+
+	return p.Get(name)
+}
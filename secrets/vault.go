@@ -0,0 +1,133 @@
+package secrets
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tomochain/tomox-sdk/utils"
+	"github.com/tomochain/tomox-sdk/vault"
+)
+
+var logger = utils.Logger
+
+const defaultRefreshInterval = 5 * time.Minute
+
+// VaultProvider reads the secrets from a Vault key/value path
+// and keeps them up to date, renewing its token on every refresh
+type VaultProvider struct {
+	client          *vault.Client
+	path            string
+	refreshInterval time.Duration
+	values          map[string]string
+	callbacks       []func(changed map[string]string)
+	mutex           sync.RWMutex
+}
+
+// NewVaultProvider returns a new instance of VaultProvider.
+// For the KV version 2 engine the path includes "data", e.g. "secret/data/tomox-sdk"
+func NewVaultProvider(client *vault.Client, path string, refreshInterval time.Duration) *VaultProvider {
+	if refreshInterval <= 0 {
+		refreshInterval = defaultRefreshInterval
+	}
+
+	return &VaultProvider{
+		client:          client,
+		path:            path,
+		refreshInterval: refreshInterval,
+		values:          make(map[string]string),
+	}
+}
+
+// Get returns the last value read from Vault
+func (p *VaultProvider) Get(name string) (string, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	v, ok := p.values[name]
+	if !ok || v == "" {
+		return "", ErrNotFound
+	}
+
+	return v, nil
+}
+
+// OnChange registers a function called with the secrets that changed after a refresh
+func (p *VaultProvider) OnChange(fn func(changed map[string]string)) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.callbacks = append(p.callbacks, fn)
+}
+
+// Load reads the secrets from Vault
+func (p *VaultProvider) Load() error {
+	res, err := p.client.Read(p.path)
+	if err != nil {
+		return err
+	}
+
+	data := res.Data
+	// the KV version 2 engine nests the secrets in data.data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		data = nested
+	}
+
+	values := make(map[string]string)
+	for k, v := range data {
+		values[k] = fmt.Sprint(v)
+	}
+
+	p.mutex.Lock()
+	changed := make(map[string]string)
+	for k, v := range values {
+		if p.values[k] != v {
+			changed[k] = v
+		}
+	}
+
+	initial := len(p.values) == 0
+	p.values = values
+	callbacks := p.callbacks
+	p.mutex.Unlock()
+
+	if initial || len(changed) == 0 {
+		return nil
+	}
+
+	for _, fn := range callbacks {
+		fn(changed)
+	}
+
+	return nil
+}
+
+// Run renews the Vault token and refreshes the secrets periodically
+func (p *VaultProvider) Run() {
+	ticker := time.NewTicker(p.refreshInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		err := p.renewToken()
+		if err != nil {
+			logger.Error("Vault token renewal failed:", err)
+		}
+
+		err = p.Load()
+		if err != nil {
+			logger.Error("Vault secrets refresh failed:", err)
+		}
+	}
+}
+
+func (p *VaultProvider) renewToken() error {
+	res, err := p.client.Write("auth/token/renew-self", map[string]string{})
+	if err != nil {
+		return err
+	}
+
+	if res.Auth != nil && res.Auth.ClientToken != "" {
+		p.client.SetToken(res.Auth.ClientToken)
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package secrets
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tomochain/tomox-sdk/vault"
+)
+
+func TestVaultProviderLoad(t *testing.T) {
+	password := "first"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/secret/data/tomox-sdk", r.URL.Path)
+		assert.Equal(t, "token", r.Header.Get("X-Vault-Token"))
+		w.Write([]byte(`{"data":{"data":{"mongo_username":"tomox","mongo_password":"` + password + `"}}}`))
+	}))
+	defer server.Close()
+
+	p := NewVaultProvider(vault.NewClient(server.URL, "token"), "secret/data/tomox-sdk", 0)
+
+	changes := []map[string]string{}
+	p.OnChange(func(changed map[string]string) {
+		changes = append(changes, changed)
+	})
+
+	err := p.Load()
+	assert.Nil(t, err)
+
+	v, err := p.Get(MongoPassword)
+	assert.Nil(t, err)
+	assert.Equal(t, "first", v)
+
+	_, err = p.Get(RabbitMQURL)
+	assert.Equal(t, ErrNotFound, err)
+
+	password = "second"
+	err = p.Load()
+	assert.Nil(t, err)
+	assert.Equal(t, []map[string]string{{MongoPassword: "second"}}, changes)
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/tomochain/tomox-sdk/app"
+)
+
+// Boot steps, run in this order by Start and NewRouter
+const (
+	bootMongo     = "mongo"
+	bootRabbitMQ  = "rabbitmq"
+	bootChain     = "chain"
+	bootPublicIDs = "public_ids"
+	bootRelayer   = "relayer"
+	bootEngine    = "engine"
+	bootHTTP      = "http"
+)
+
+// retryPolicy is the number of attempts of a boot step and the delay between them,
+// doubled after each failed attempt up to maxDelay
+type retryPolicy struct {
+	attempts int
+	delay    time.Duration
+	maxDelay time.Duration
+}
+
+// bootPolicies are the default retry policies of the boot steps, the attempts are overridden
+// per step by the "boot" config section. The engine subscriptions are not retried since a
+// partial failure would leave duplicate consumers
+var bootPolicies = map[string]retryPolicy{
+	bootMongo:     {attempts: 10, delay: time.Second, maxDelay: 30 * time.Second},
+	bootRabbitMQ:  {attempts: 10, delay: time.Second, maxDelay: 30 * time.Second},
+	bootChain:     {attempts: 10, delay: 2 * time.Second, maxDelay: 30 * time.Second},
+	bootPublicIDs: {attempts: 5, delay: 5 * time.Second, maxDelay: time.Minute},
+	bootRelayer:   {attempts: 5, delay: 5 * time.Second, maxDelay: time.Minute},
+	bootEngine:    {attempts: 1},
+	bootHTTP:      {attempts: 5, delay: time.Second, maxDelay: 10 * time.Second},
+}
+
+// bootReady lists the steps completed so far, it is reported when a step fails
+var bootReady []string
+
+// runBootStep runs a boot step until it succeeds or its attempts are exhausted,
+// the returned error names the step and the steps that were ready
+func runBootStep(name string, fn func() error) error {
+	p := bootPolicies[name]
+	if v := app.Config.Boot[name]; v > 0 {
+		p.attempts = v
+	}
+
+	if p.attempts < 1 {
+		p.attempts = 1
+	}
+
+	start := time.Now()
+	delay := p.delay
+
+	var err error
+	for attempt := 1; attempt <= p.attempts; attempt++ {
+		err = fn()
+		if err == nil {
+			log.Printf("boot: %s ready in %s (attempt %d/%d)", name, time.Since(start).Round(time.Millisecond), attempt, p.attempts)
+			bootReady = append(bootReady, name)
+			return nil
+		}
+
+		if attempt == p.attempts {
+			break
+		}
+
+		log.Printf("boot: %s attempt %d/%d failed, retrying in %s: %s", name, attempt, p.attempts, delay, err)
+		time.Sleep(delay)
+
+		delay *= 2
+		if delay > p.maxDelay {
+			delay = p.maxDelay
+		}
+	}
+
+	ready := "none"
+	if len(bootReady) > 0 {
+		ready = strings.Join(bootReady, ", ")
+	}
+
+	return fmt.Errorf("boot: %s failed after %d attempts (ready: %s): %s", name, p.attempts, ready, err)
+}
@@ -1,12 +1,15 @@
 package server
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"runtime"
 	"strconv"
+	"time"
 
 	"runtime/pprof"
 
@@ -14,16 +17,24 @@ import (
 	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
 	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/compliance"
 	"github.com/tomochain/tomox-sdk/crons"
 	"github.com/tomochain/tomox-sdk/daos"
 	"github.com/tomochain/tomox-sdk/endpoints"
 	"github.com/tomochain/tomox-sdk/engine"
 	"github.com/tomochain/tomox-sdk/errors"
 	"github.com/tomochain/tomox-sdk/ethereum"
+	"github.com/tomochain/tomox-sdk/kms"
+	"github.com/tomochain/tomox-sdk/middlewares"
 	"github.com/tomochain/tomox-sdk/rabbitmq"
 	"github.com/tomochain/tomox-sdk/relayer"
+	relayerAbi "github.com/tomochain/tomox-sdk/relayer/abi"
+	"github.com/tomochain/tomox-sdk/secrets"
 	"github.com/tomochain/tomox-sdk/services"
+	"github.com/tomochain/tomox-sdk/usage"
 	"github.com/tomochain/tomox-sdk/utils"
+	"github.com/tomochain/tomox-sdk/utils/snowflake"
+	"github.com/tomochain/tomox-sdk/vault"
 	"github.com/tomochain/tomox-sdk/ws"
 )
 
@@ -46,29 +57,77 @@ func Start() {
 		panic(err)
 	}
 
-	logger.Infof("Server port: %v", app.Config.ServerPort)
-	logger.Infof("Tomochain node HTTP url: %v", app.Config.Tomochain["http_url"])
-	logger.Infof("Tomochain node WS url: %v", app.Config.Tomochain["ws_url"])
-	logger.Infof("MongoDB url: %v", app.Config.MongoURL)
-	logger.Infof("RabbitMQ url: %v", app.Config.RabbitMQURL)
-	logger.Infof("Exchange contract address: %v", app.Config.Tomochain["exchange_address"])
-	logger.Infof("Env: %v", app.Config.Env)
+	if err := initSecrets(); err != nil {
+		panic(err)
+	}
 
-	_, err := daos.InitSession(nil)
+	if err := initKMS(); err != nil {
+		panic(err)
+	}
+
+	log.Printf("Effective configuration:\n%s\n", app.Config.Report())
+
+	if err := snowflake.Init(app.Config.NodeID); err != nil {
+		panic(err)
+	}
+
+	if err := relayerAbi.LoadOverrides(app.Config.ABIOverrides); err != nil {
+		panic(err)
+	}
+
+	// the dependencies are brought up in order, each step is retried according to its policy
+	err := runBootStep(bootMongo, func() error {
+		_, err := daos.InitSession(nil)
+		return err
+	})
 	if err != nil {
 		panic(err)
 	}
 
-	rabbitConn := rabbitmq.InitConnection(app.Config.RabbitMQURL)
+	var rabbitConn *rabbitmq.Connection
+	err = runBootStep(bootRabbitMQ, func() error {
+		var err error
+		rabbitConn, err = rabbitmq.Dial(app.Config.RabbitMQURL)
+		return err
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	var provider *ethereum.EthereumProvider
+	err = runBootStep(bootChain, func() error {
+		provider = ethereum.NewWebsocketProvider()
+		if provider == nil {
+			return fmt.Errorf("cannot dial %s", app.Config.Tomochain["ws_url"])
+		}
+
+		_, err := provider.Client.HeaderByNumber(context.Background(), nil)
+		return err
+	})
+	if err != nil {
+		panic(err)
+	}
 
-	provider := ethereum.NewWebsocketProvider()
+	router, err := NewRouter(provider, rabbitConn)
+	if err != nil {
+		panic(err)
+	}
 
-	router := NewRouter(provider, rabbitConn)
 	// http.Handle("/", router)
 	router.HandleFunc("/socket", ws.ConnectionEndpoint)
 
-	// start the server
 	address := fmt.Sprintf(":%v", app.Config.ServerPort)
+	var listener net.Listener
+	err = runBootStep(bootHTTP, func() error {
+		var err error
+		listener, err = net.Listen("tcp", address)
+		return err
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	// start the server
 	log.Printf("server %v is started at %v\n", app.Version, address)
 
 	allowedHeaders := handlers.AllowedHeaders([]string{"Content-Type", "Accept", "Authorization", "Access-Control-Allow-Origin"})
@@ -76,8 +135,86 @@ func Start() {
 	allowedMethods := handlers.AllowedMethods([]string{"GET", "HEAD", "POST", "PUT", "DELETE", "OPTIONS"})
 
 	router.HandleFunc("/heap", handleHeap).Methods("GET")
-	panic(http.ListenAndServe(address, handlers.CORS(allowedHeaders, allowedOrigins, allowedMethods)(router)))
+	panic(http.Serve(listener, handlers.CORS(allowedHeaders, allowedOrigins, allowedMethods)(router)))
 }
+
+// initSecrets reads the credentials from the configured secrets provider
+// and keeps the database credentials up to date when they are rotated
+func initSecrets() error {
+	if app.Config.Secrets["backend"] != "vault" {
+		return nil
+	}
+
+	interval, _ := strconv.Atoi(app.Config.Secrets["refresh_interval"])
+	client := vault.NewClient(app.Config.Secrets["vault_addr"], app.Config.Secrets["vault_token"])
+	provider := secrets.NewVaultProvider(client, app.Config.Secrets["vault_path"], time.Duration(interval)*time.Second)
+
+	err := provider.Load()
+	if err != nil {
+		return err
+	}
+
+	secrets.SetDefault(provider)
+	applySecrets(provider)
+
+	provider.OnChange(func(changed map[string]string) {
+		_, user := changed[secrets.MongoUsername]
+		_, password := changed[secrets.MongoPassword]
+		if user || password {
+			err := daos.Relogin(
+				currentSecret(provider, secrets.MongoUsername, app.Config.MongoDBUsername),
+				currentSecret(provider, secrets.MongoPassword, app.Config.MongoDBPassword),
+			)
+
+			if err != nil {
+				logger.Error("MongoDB login with rotated credentials failed:", err)
+			}
+		}
+
+		if _, ok := changed[secrets.RabbitMQURL]; ok {
+			logger.Warning("RabbitMQ URL rotated, the SDK must be restarted to connect to it")
+		}
+	})
+
+	go provider.Run()
+	return nil
+}
+
+// applySecrets copies the secrets into the config at startup, before the config is read by other goroutines.
+// The config is never written afterwards, the rotated secrets are read from the provider
+func applySecrets(p secrets.Provider) {
+	app.Config.MongoDBUsername = currentSecret(p, secrets.MongoUsername, app.Config.MongoDBUsername)
+	app.Config.MongoDBPassword = currentSecret(p, secrets.MongoPassword, app.Config.MongoDBPassword)
+	app.Config.RabbitMQURL = currentSecret(p, secrets.RabbitMQURL, app.Config.RabbitMQURL)
+}
+
+// currentSecret returns the current value of a secret, the value of the config file if it is not provided
+func currentSecret(p secrets.Provider, name string, def string) string {
+	v, err := p.Get(name)
+	if err != nil {
+		return def
+	}
+
+	return v
+}
+
+// initKMS configures the encryption of the stored secrets and decrypts the encrypted config values
+func initKMS() error {
+	if len(app.Config.KMS) == 0 {
+		return nil
+	}
+
+	c, err := kms.NewCipherFromConfig(app.Config.KMS)
+	if err != nil {
+		return err
+	}
+
+	kms.SetDefault(c)
+
+	app.Config.ApiAuthKey, err = kms.DecryptString(app.Config.ApiAuthKey)
+	return err
+}
+
 func handleHeap(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("X-Content-Type-Options", "nosniff")
 	p := pprof.Lookup(string("heap"))
@@ -98,22 +235,33 @@ func handleHeap(w http.ResponseWriter, r *http.Request) {
 	p.WriteTo(w, debug)
 }
 
+// NewRouter builds the services and their routes, it bootstraps the relayer data
+// then starts the engine subscriptions, an error is returned if one of them fails
 func NewRouter(
 	provider *ethereum.EthereumProvider,
 	rabbitConn *rabbitmq.Connection,
-) *mux.Router {
+) (*mux.Router, error) {
 
 	r := mux.NewRouter()
+	r.Use(middlewares.LimitBody)
+	r.Use(middlewares.TrackUsage)
+	ws.SetMarketDataDelay(middlewares.MarketDataDelay(), middlewares.HasMarketDataLicense)
 
 	// get daos for dependency injection
 	orderDao := daos.NewOrderDao()
 	tokenDao := daos.NewTokenDao()
+	tokenAliasDao := daos.NewTokenAliasDao()
 
 	pairDao := daos.NewPairDao()
 	tradeDao := daos.NewTradeDao()
 	accountDao := daos.NewAccountDao()
 	walletDao := daos.NewWalletDao()
 	notificationDao := daos.NewNotificationDao()
+	notificationPreferenceDao := daos.NewNotificationPreferenceDao()
+	interestAccrualDao := daos.NewInterestAccrualDao()
+	lendingRateSnapshotDao := daos.NewLendingRateSnapshotDao()
+	depthSnapshotDao := daos.NewDepthSnapshotDao()
+	configDao := daos.NewConfigDao()
 
 	// Lending Dao
 	tokenLendingDao := daos.NewLendingTokenDao()
@@ -125,6 +273,22 @@ func NewRouter(
 	lendingTradeDao := daos.NewLendingTradeDao()
 	lengdingPairDao := daos.NewLendingPairDao()
 	relayerDao := daos.NewRelayerDao()
+	finalityDao := daos.NewFinalityDao()
+	auditDao := daos.NewAuditDao()
+	settlementDao := daos.NewSettlementDao()
+	listingReviewDao := daos.NewListingReviewDao()
+	tokenMigrationDao := daos.NewTokenMigrationDao()
+	riskLimitDao := daos.NewRiskLimitDao()
+	incidentDao := daos.NewIncidentDao()
+	compositeIndexDao := daos.NewCompositeIndexDao()
+	dailyStatsDao := daos.NewDailyStatsDao()
+	balanceSnapshotDao := daos.NewBalanceSnapshotDao()
+	engineEventDao := daos.NewEngineEventDao()
+	jobDao := daos.NewJobDao()
+	subscriptionProfileDao := daos.NewSubscriptionProfileDao()
+	accessLogDao := daos.NewAccessLogDao()
+	killSwitchDao := daos.NewKillSwitchDao()
+	integrityReportDao := daos.NewIntegrityReportDao()
 	// instantiate engine
 	eng := engine.NewEngine(rabbitConn, orderDao, tradeDao, pairDao, provider)
 
@@ -133,27 +297,130 @@ func NewRouter(
 	ohlcvService.Init()
 
 	accountService := services.NewAccountService(accountDao, tokenDao, pairDao, orderDao, lendingOrderDao, provider, ohlcvService)
-	tokenService := services.NewTokenService(tokenDao)
+	accountBatchService := services.NewAccountBatchService(accountService, orderDao, tradeDao, pairDao)
+	exportService := services.NewExportService(tradeDao, orderDao)
+	tokenService := services.NewTokenService(tokenDao, tokenAliasDao)
 	validatorService := services.NewValidatorService(provider, accountDao, orderDao, lendingOrderDao, pairDao, tokenDao)
 	pairService := services.NewPairService(pairDao, tokenDao, tradeDao, orderDao, ohlcvService, eng, provider)
 
-	orderService := services.NewOrderService(orderDao, tokenDao, pairDao, accountDao, tradeDao, notificationDao, eng, validatorService, rabbitConn)
+	engineJournalService := services.NewEngineJournalService(engineEventDao)
+	riskService := services.NewRiskService(riskLimitDao, orderDao)
+	complianceGate, err := compliance.NewGateFromConfig(app.Config.Compliance)
+	if err != nil {
+		return nil, err
+	}
+
+	attestationService, err := services.NewAttestationServiceFromConfig(app.Config.ResponseSigning, walletDao)
+	if err != nil {
+		return nil, err
+	}
+
+	orderService := services.NewOrderService(orderDao, tokenDao, pairDao, accountDao, tradeDao, notificationDao, eng, validatorService, rabbitConn, engineJournalService, riskService, complianceGate, attestationService, walletDao)
 	orderService.LoadCache()
 	orderBookService := services.NewOrderBookService(pairDao, tokenDao, orderDao, eng)
-	tradeService := services.NewTradeService(orderDao, tradeDao, ohlcvService, notificationDao, rabbitConn)
+	exchangeAddress := common.HexToAddress(app.Config.Tomochain["exchange_address"])
+	contractAddress := common.HexToAddress(app.Config.Tomochain["exchange_contract_address"])
+	lendingContractAddress := common.HexToAddress(app.Config.Tomochain["lending_contract_address"])
+	multicallAddress := common.HexToAddress(app.Config.Tomochain["multicall_address"])
+	rpcPool := relayer.NewRPCPool(
+		append([]string{app.Config.Tomochain["http_url"]}, app.Config.RPCFallbackURLs...),
+		app.Config.RPCRetry["attempts"],
+		time.Duration(app.Config.RPCRetry["backoff_ms"])*time.Millisecond,
+		time.Duration(app.Config.RPCRetry["max_backoff_ms"])*time.Millisecond,
+		time.Duration(app.Config.RPCRetry["timeout_ms"])*time.Millisecond,
+	)
+	relayerEngine := relayer.NewRelayer(rpcPool, app.Config.Tomochain["ws_url"], exchangeAddress, contractAddress, lendingContractAddress, multicallAddress, time.Duration(app.Config.TokenInfoTTL)*time.Second, app.Config.TokenInfoConcurrency, app.Config.TokenInfoBatchSize)
+	relayerSigner, err := relayer.NewSignerFromConfig(app.Config.RelayerSigner)
+	if err != nil {
+		return nil, err
+	}
+
+	relayerEngine.SetSigner(relayerSigner)
+	relayerEngine.SetCircuitBreaker(relayer.NewCircuitBreaker(
+		app.Config.RPCRetry["breaker_failures"],
+		time.Duration(app.Config.RPCRetry["breaker_cooldown_ms"])*time.Millisecond,
+	))
+	dependencyService := services.NewDependencyService(rabbitConn)
+	blockService := services.NewBlockService(provider, relayerEngine, dependencyService)
+	finalityService := services.NewFinalityService(finalityDao, blockService, provider)
+	settlementService := services.NewSettlementService(settlementDao, tradeDao, pairDao, eng, blockService)
+	tradeService := services.NewTradeService(orderDao, tradeDao, ohlcvService, notificationDao, finalityService, settlementService, rabbitConn, attestationService)
+	gasOracleService := services.NewGasOracleService(blockService, provider)
+	nonceManagerService := services.NewNonceManagerService(blockService, provider, gasOracleService)
+	nonceManagerService.Register(relayerSigner)
+	chainListenerService := services.NewChainListenerService(provider, orderService, tradeService, configDao)
+
+	// roll back the trades of the blocks replaced by a chain reorganization
+	services.NewReorgService(blockService, provider, finalityDao, tradeDao, orderDao, pairDao, accountDao, ohlcvService)
 
 	walletService := services.NewWalletService(walletDao)
+	if app.Config.KMS["rotate_on_start"] == "true" {
+		n, err := walletService.RotateKeys()
+		if err != nil {
+			logger.Error("Wallet keys rotation failed:", err)
+		}
+
+		logger.Infof("Rotated %d wallet keys", n)
+	}
 
 	priceBoardService := services.NewPriceBoardService(tokenDao, tradeDao, ohlcvService)
-	marketsService := services.NewMarketsService(pairDao, orderDao, tradeDao, ohlcvService, pairService)
-	notificationService := services.NewNotificationService(notificationDao)
+	marketsService := services.NewMarketsService(pairDao, orderDao, tradeDao, ohlcvService, pairService, orderBookService)
+	microstructureService := services.NewMicrostructureService(pairDao, orderDao, tradeDao)
+	compositeIndexService := services.NewCompositeIndexService(compositeIndexDao, tokenDao, ohlcvService)
+	notificationService := services.NewNotificationService(notificationDao, notificationPreferenceDao)
+	statsService := services.NewStatsService(pairDao, tradeDao, ohlcvService)
+	reportService := services.NewReportService(tradeDao, pairDao, dailyStatsDao)
+	balanceHistoryService := services.NewBalanceHistoryService(balanceSnapshotDao, accountDao, tokenDao, pairDao, tradeDao, provider)
+	subscriptionProfileService := services.NewSubscriptionProfileService(subscriptionProfileDao)
+	auditService := services.NewAuditService(auditDao)
+	incidentService := services.NewIncidentService(incidentDao)
+	canaryService := services.NewCanaryService(orderService, walletDao, pairDao, incidentService)
+	contractVerificationService := services.NewContractVerificationService(tokenDao, pairDao)
+	disputeService := services.NewDisputeService(orderDao, tradeDao, pairDao, settlementDao, provider)
+	accessLogService := services.NewAccessLogService(accessLogDao)
+	integrityService := services.NewIntegrityService(orderDao, tradeDao, pairDao, ohlcvService, integrityReportDao, notificationService)
+	killSwitchService := services.NewKillSwitchService(killSwitchDao, pairDao, orderDao, eng, rabbitConn, app.Config.KillSwitchSigners)
+	err = killSwitchService.Restore()
+	if err != nil {
+		return nil, err
+	}
 
-	// LEDNDING SERVICE
-	tokenLendingService := services.NewTokenService(tokenLendingDao)
-	tokenCollateralService := services.NewTokenService(tokenCollateralDao)
+	if accessLogService.Enabled() {
+		usage.StartAccessLog(accessLogDao)
+	}
+
+	rbac := middlewares.NewRBAC(auditDao)
 
-	lendingOrderService := services.NewLendingOrderService(lendingOrderDao, lendingTopupDao, lendingRepayDao, lendingRecallDao, tokenCollateralDao, tokenLendingDao, notificationDao, lendingTradeDao, validatorService, eng, rabbitConn)
-	lendingTradeService := services.NewLendingTradeService(lendingOrderDao, lendingTradeDao, notificationDao, rabbitConn)
+	var geoIP middlewares.GeoIPProvider
+	if path := app.Config.GeoPolicy["cidr_file"]; path != "" {
+		geoIP, err = middlewares.LoadCIDRProvider(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	geoPolicy, err := middlewares.NewGeoPolicy(auditDao, geoIP, app.Config.GeoPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	// registered before the delayed market data which answers without calling the next handlers
+	r.Use(geoPolicy.Enforce)
+	r.Use(middlewares.NewAmountFormatter(tokenDao).Format)
+	r.Use(middlewares.DelayMarketData)
+
+	// LEDNDING SERVICE
+	tokenLendingService := services.NewTokenService(tokenLendingDao, tokenAliasDao)
+	tokenCollateralService := services.NewTokenService(tokenCollateralDao, tokenAliasDao)
+
+	lendingOrderService := services.NewLendingOrderService(lendingOrderDao, lendingTopupDao, lendingRepayDao, lendingRecallDao, tokenCollateralDao, tokenLendingDao, notificationDao, lendingTradeDao, validatorService, eng, rabbitConn, complianceGate)
+	lendingTradeService := services.NewLendingTradeService(lendingOrderDao, lendingTradeDao, notificationDao, finalityService, provider, rabbitConn)
+	loanMaturityService := services.NewLoanMaturityService(lendingTradeDao, notificationService)
+	lendingPositionService := services.NewLendingPositionService(lendingTradeDao, lendingOrderDao, tokenCollateralDao, tokenLendingDao)
+	interestAccrualService := services.NewInterestAccrualService(lendingTradeDao, interestAccrualDao)
+	lendingRateService := services.NewLendingRateService(lendingRateSnapshotDao, lendingTradeDao, lendingOrderDao)
+	depthSnapshotService := services.NewDepthSnapshotService(depthSnapshotDao, pairDao, orderDao)
+	collateralMonitor := services.NewCollateralMonitorService(lendingTradeDao, orderBookService, notificationService)
 	lendingOhlcvService := services.NewLendingOhlcvService(lendingTradeService, ohlcvService, lengdingPairDao)
 	lendingOhlcvService.Init()
 
@@ -162,26 +429,53 @@ func NewRouter(
 	lendingPairService := services.NewLendingPairService(lengdingPairDao)
 	lendingPriceboardService := services.NewLendingPriceBoardService(lendingPairService, lendingOhlcvService)
 
-	exchangeAddress := common.HexToAddress(app.Config.Tomochain["exchange_address"])
-	contractAddress := common.HexToAddress(app.Config.Tomochain["exchange_contract_address"])
-	lendingContractAddress := common.HexToAddress(app.Config.Tomochain["lending_contract_address"])
-	relayerEngine := relayer.NewRelayer(app.Config.Tomochain["http_url"], exchangeAddress, contractAddress, lendingContractAddress)
-	relayerService := services.NewRelayerService(relayerEngine, tokenDao, tokenCollateralDao, tokenLendingDao, pairDao, lengdingPairDao, relayerDao)
+	listingService := services.NewListingService(listingReviewDao, pairDao)
+	tokenSafetyService := services.NewTokenSafetyService(tokenDao, provider)
+	relayerService := services.NewRelayerService(relayerEngine, tokenDao, tokenCollateralDao, tokenLendingDao, pairDao, lengdingPairDao, relayerDao, listingService, tokenSafetyService, eng, configDao)
+	relayerService.OnFeesChanged(orderService.ApplyRelayerFees)
+	tokenMigrationService := services.NewTokenMigrationService(tokenMigrationDao, tokenDao, tokenAliasDao, pairDao, orderDao, settlementService, relayerService)
+	scheduler := crons.NewScheduler(jobDao)
+	faucetService, err := services.NewFaucetServiceFromConfig(app.Config.Faucet, provider, nonceManagerService)
+	if err != nil {
+		return nil, err
+	}
 
 	// deploy http and ws endpoints
 	endpoints.ServeInfoResource(r, walletService, tokenService, relayerService)
-	endpoints.ServeAccountResource(r, accountService)
-	endpoints.ServeTokenResource(r, tokenService, relayerService)
-	endpoints.ServePairResource(r, pairService, relayerService)
+	endpoints.ServeAccountResource(r, accountService, balanceHistoryService, accessLogService)
+	endpoints.ServeAccountBatchResource(r, accountBatchService, middlewares.NewRateLimiter(
+		app.Config.AccountBatch["requests"],
+		time.Duration(app.Config.AccountBatch["window"])*time.Second,
+	))
+	faucetRequests, _ := strconv.Atoi(app.Config.Faucet["requests"])
+	faucetWindow, _ := strconv.Atoi(app.Config.Faucet["window"])
+	endpoints.ServeFaucetResource(r, faucetService, middlewares.NewRateLimiter(
+		faucetRequests,
+		time.Duration(faucetWindow)*time.Second,
+	))
+	endpoints.ServeTransactionResource(r, nonceManagerService, rbac)
+	endpoints.ServeTokenResource(r, tokenService, relayerService, rbac)
+	endpoints.ServeTokenMigrationResource(r, tokenMigrationService, rbac)
+	endpoints.ServePairResource(r, pairService, tokenService, relayerService, rbac)
 	endpoints.ServeOrderBookResource(r, orderBookService)
 	endpoints.ServeOHLCVResource(r, ohlcvService)
 
 	endpoints.ServeTradeResource(r, tradeService, relayerService)
-	endpoints.ServeOrderResource(r, orderService, accountService, relayerService)
+	endpoints.ServeOrderResource(r, orderService, accountService, relayerService, attestationService)
 
 	endpoints.ServePriceBoardResource(r, priceBoardService)
-	endpoints.ServeMarketsResource(r, marketsService, pairService, relayerService)
+	endpoints.ServeMarketsResource(r, marketsService, pairService, relayerService, gasOracleService)
+	endpoints.ServeMicrostructureResource(r, microstructureService)
+	endpoints.ServeCompositeIndexResource(r, compositeIndexService, rbac)
 	endpoints.ServeNotificationResource(r, notificationService)
+	endpoints.ServeBlockResource(r, blockService, finalityService)
+	endpoints.ServeDependencyResource(r, dependencyService, rbac)
+	endpoints.ServeStatsResource(r, statsService)
+	endpoints.ServeReportResource(r, reportService, rbac)
+	endpoints.ServeEngineJournalResource(r, engineJournalService, rbac)
+	endpoints.ServeJobResource(r, scheduler, rbac)
+	endpoints.ServeSubscriptionProfileResource(subscriptionProfileService)
+	endpoints.ServePreferencesResource(r)
 
 	// Endpoint for lending
 
@@ -190,32 +484,90 @@ func NewRouter(
 	endpoints.ServeLendingPairResource(r, lendingPairService, relayerService)
 	endpoints.ServeLendingOrderBookResource(r, lendingOrderbookService)
 	endpoints.ServeLendingTradeResource(r, lendingTradeService, relayerService)
+	endpoints.ServeLoanMaturityResource(r, loanMaturityService)
+	endpoints.ServeLendingPositionResource(r, lendingPositionService)
+	endpoints.ServeInterestAccrualResource(r, interestAccrualService)
+	endpoints.ServeLendingRateResource(r, lendingRateService)
+	endpoints.ServeDepthSnapshotResource(r, depthSnapshotService)
 	endpoints.ServeLendingOrderResource(r, lendingOrderService, relayerService)
 	endpoints.ServeLendingOhlcvResource(r, lendingOhlcvService)
 	endpoints.ServeLendingMarketsResource(r, lendingMarketService, lendingOhlcvService)
 	endpoints.ServeLendingPriceBoardResource(r, lendingPriceboardService)
 
-	endpoints.ServeRelayerResource(r, relayerService, ohlcvService, lendingOhlcvService)
+	endpoints.ServeRelayerResource(r, relayerService, ohlcvService, lendingOhlcvService, rbac)
+	endpoints.ServeAdminResource(r, auditService, settlementService, disputeService, listingService, accessLogService, rbac)
+	endpoints.ServeRiskResource(r, riskService, rbac)
+	endpoints.ServeIncidentResource(r, incidentService, rbac)
+	endpoints.ServeCanaryResource(r, canaryService, rbac)
+	endpoints.ServeKillSwitchResource(r, killSwitchService, rbac)
+	endpoints.ServeIntegrityResource(r, integrityService, rbac)
+	endpoints.ServeExportResource(r, exportService, rbac)
 
 	// Swagger UI
 	sh := http.StripPrefix(swaggerUIDir, http.FileServer(http.Dir("."+swaggerUIDir)))
 	r.PathPrefix(swaggerUIDir).Handler(sh)
 
+	// the orders and trades stored while the SDK was down get their public ids before the new ones
+	err = runBootStep(bootPublicIDs, func() error {
+		orders, err := orderDao.AssignPublicIDs()
+		if err != nil {
+			return err
+		}
+
+		trades, err := tradeDao.AssignPublicIDs()
+		if err != nil {
+			return err
+		}
+
+		log.Printf("boot: assigned public ids to %d orders and %d trades", orders, trades)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = runBootStep(bootRelayer, relayerService.UpdateRelayers)
+	if err != nil {
+		return nil, err
+	}
+
 	//initialize rabbitmq subscriptions
-	rabbitConn.SubscribeOrders(eng.HandleOrders)
-	rabbitConn.SubscribeEngineResponses(orderService.HandleEngineResponse)
-
-	rabbitConn.SubscribeOrderResponses(orderService.HandleEngineResponse)
-	rabbitConn.SubscribeTradeResponses(tradeService.HandleTradeResponse)
-
-	// Subscribe lending
-	// for create/cancel order
-	rabbitConn.SubscribeLendingOrders(lendingOrderService.HandleLendingOrdersCreateCancel)
-	// for database changing response
-	rabbitConn.SubscribeLendingOrderResponses(lendingOrderService.HandleLendingOrderResponse)
-	rabbitConn.SubscribeLendingTradeResponses(lendingTradeService.HandleLendingTradeResponse)
+	err = runBootStep(bootEngine, func() error {
+		subscriptions := []func() error{
+			eng.ReleaseHeldOrders,
+			func() error { return rabbitConn.SubscribeOrders(eng.AcceptOrder, eng.HandleOrders) },
+			func() error { return rabbitConn.SubscribeEngineResponses(orderService.HandleEngineResponse) },
+			func() error { return rabbitConn.SubscribeOrderResponses(orderService.HandleEngineResponse) },
+			func() error { return rabbitConn.SubscribeTradeResponses(tradeService.HandleTradeResponse) },
+			func() error { return rabbitConn.SubscribeDeposits(finalityService.TrackDeposit) },
+			// Subscribe lending
+			// for create/cancel order
+			func() error {
+				return rabbitConn.SubscribeLendingOrders(lendingOrderService.HandleLendingOrdersCreateCancel)
+			},
+			// for database changing response
+			func() error {
+				return rabbitConn.SubscribeLendingOrderResponses(lendingOrderService.HandleLendingOrderResponse)
+			},
+			func() error {
+				return rabbitConn.SubscribeLendingTradeResponses(lendingTradeService.HandleLendingTradeResponse)
+			},
+		}
+
+		for _, subscribe := range subscriptions {
+			if err := subscribe(); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	// start cron service
-	cronService := crons.NewCronService(ohlcvService, priceBoardService, pairService, relayerService, eng, lendingPriceboardService, lendingPairService, lendingOhlcvService)
+	cronService := crons.NewCronService(ohlcvService, priceBoardService, pairService, relayerService, eng, lendingPriceboardService, lendingPairService, lendingOhlcvService, loanMaturityService, interestAccrualService, collateralMonitor, reportService, balanceHistoryService, canaryService, contractVerificationService, tokenSafetyService, accessLogService, integrityService, microstructureService, compositeIndexService, lendingRateService, depthSnapshotService, scheduler)
 	// initialize MongoDB Change Streams
 	go orderService.WatchChanges()
 	go tradeService.WatchChanges()
@@ -223,6 +575,19 @@ func NewRouter(
 	// lending mongo watch change
 	go lendingOrderService.WatchChanges()
 	go lendingTradeService.WatchChanges()
+
+	// follow the chain head of the connected node
+	go blockService.WatchChainHead()
+
+	if relayerService.EventsEnabled() {
+		go relayerService.WatchRelayers()
+	}
+
+	// apply the trades and the cancels of the exchange contract from its logs
+	if chainListenerService.Enabled() {
+		go chainListenerService.Watch()
+	}
+
 	cronService.InitCrons()
-	return r
+	return r, nil
 }
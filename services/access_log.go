@@ -0,0 +1,65 @@
+package services
+
+import (
+	"time"
+
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/usage"
+)
+
+// Defaults of the retention of the access logs, in days
+const (
+	defaultAccessLogAnonymizeDays = 7
+	defaultAccessLogRetentionDays = 30
+)
+
+// AccessLogService reads the statistics of the sampled access logs and applies their retention,
+// the hash of the callers is removed first and the logs themselves later
+type AccessLogService struct {
+	accessLogDao interfaces.AccessLogDao
+}
+
+// NewAccessLogService returns a new instance of AccessLogService
+func NewAccessLogService(accessLogDao interfaces.AccessLogDao) *AccessLogService {
+	return &AccessLogService{accessLogDao}
+}
+
+// Enabled tells if the access logs are recorded
+func (s *AccessLogService) Enabled() bool {
+	return app.Config.AccessLog["enabled"] > 0
+}
+
+// GetStats returns the estimated calls of each route since a time
+func (s *AccessLogService) GetStats(since time.Time) ([]*types.AccessStats, error) {
+	return s.accessLogDao.GetStats(since, "")
+}
+
+// GetUserStats returns the estimated calls of each route by a usage key since a time,
+// the calls older than the anonymization are not counted
+func (s *AccessLogService) GetUserStats(key string, since time.Time) ([]*types.AccessStats, error) {
+	return s.accessLogDao.GetStats(since, usage.HashKey(key))
+}
+
+// Purge anonymizes and removes the expired access logs
+func (s *AccessLogService) Purge() error {
+	now := time.Now()
+
+	days := accessLogConfig("anonymize_days", defaultAccessLogAnonymizeDays)
+	err := s.accessLogDao.Anonymize(now.AddDate(0, 0, -days))
+	if err != nil {
+		return err
+	}
+
+	days = accessLogConfig("retention_days", defaultAccessLogRetentionDays)
+	return s.accessLogDao.RemoveBefore(now.AddDate(0, 0, -days))
+}
+
+func accessLogConfig(key string, def int) int {
+	if v, ok := app.Config.AccessLog[key]; ok && v > 0 {
+		return v
+	}
+
+	return def
+}
@@ -1,7 +1,6 @@
 package services
 
 import (
-	math2 "math"
 	"math/big"
 	"time"
 
@@ -251,7 +250,7 @@ func (s *AccountService) GetTokenBalanceProvidor(owner common.Address, tokenAddr
 
 	if tokenBalance != nil && price != nil {
 		inUsdBalance := new(big.Float).Mul(price, new(big.Float).SetInt(tokenBalance.Balance))
-		inUsdBalance = new(big.Float).Quo(inUsdBalance, new(big.Float).SetInt(big.NewInt(int64(math2.Pow10(tokenBalance.Decimals)))))
+		inUsdBalance = new(big.Float).Quo(inUsdBalance, new(big.Float).SetInt(math.Pow10(tokenBalance.Decimals)))
 		tokenBalance.InUsdBalance = inUsdBalance
 	}
 
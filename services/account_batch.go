@@ -0,0 +1,107 @@
+package services
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// defaultAccountBatchMaxAddresses is the number of addresses of a batch account query without the
+// "max_addresses" setting of the "account_batch" config section
+const defaultAccountBatchMaxAddresses = 20
+
+// AccountBatchService returns the balances, the number of open orders and the volumes of the last
+// 24 hours of several addresses in one query, for the portfolio trackers
+type AccountBatchService struct {
+	accountService interfaces.AccountService
+	orderDao       interfaces.OrderDao
+	tradeDao       interfaces.TradeDao
+	pairDao        interfaces.PairDao
+}
+
+// NewAccountBatchService returns a new instance of AccountBatchService
+func NewAccountBatchService(
+	accountService interfaces.AccountService,
+	orderDao interfaces.OrderDao,
+	tradeDao interfaces.TradeDao,
+	pairDao interfaces.PairDao,
+) *AccountBatchService {
+	return &AccountBatchService{
+		accountService: accountService,
+		orderDao:       orderDao,
+		tradeDao:       tradeDao,
+		pairDao:        pairDao,
+	}
+}
+
+// MaxAddresses returns the number of addresses of a batch query
+func (s *AccountBatchService) MaxAddresses() int {
+	if v := app.Config.AccountBatch["max_addresses"]; v > 0 {
+		return v
+	}
+
+	return defaultAccountBatchMaxAddresses
+}
+
+// GetBatch returns the summaries of the addresses in their order. The failure of an address is
+// reported in its summary and does not fail the others
+func (s *AccountBatchService) GetBatch(addrs []common.Address) []*types.AccountSummary {
+	to := time.Now()
+	from := to.Add(-24 * time.Hour)
+	pairs := make(map[string]*types.Pair)
+
+	res := []*types.AccountSummary{}
+	for _, addr := range addrs {
+		summary, err := s.getSummary(addr, from, to, pairs)
+		if err != nil {
+			logger.Error(err)
+			summary = &types.AccountSummary{Address: addr, Error: "Account not available"}
+		}
+
+		res = append(res, summary)
+	}
+
+	return res
+}
+
+// getSummary reads the summary of an address, the pairs of its trades are added to the pairs read
+func (s *AccountBatchService) getSummary(addr common.Address, from, to time.Time, pairs map[string]*types.Pair) (*types.AccountSummary, error) {
+	account, err := s.accountService.GetByAddress(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	orders, err := s.orderDao.GetOpenOrdersByUserAddress(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	trades, err := s.tradeDao.GetByUserAndPeriod(addr, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range trades {
+		code := t.BaseToken.Hex() + "::" + t.QuoteToken.Hex()
+		if _, ok := pairs[code]; ok {
+			continue
+		}
+
+		p, err := s.pairDao.GetByTokenAddress(t.BaseToken, t.QuoteToken)
+		if err != nil {
+			return nil, err
+		}
+
+		pairs[code] = p
+	}
+
+	return &types.AccountSummary{
+		Address:       addr,
+		TokenBalances: account.TokenBalances,
+		OpenOrders:    len(orders),
+		Volumes:       types.NewAccountPairVolumes(trades, pairs),
+	}, nil
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"github.com/tomochain/tomox-sdk/errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// AttestationService signs the critical payloads reported to the clients, the order acks and the
+// trade confirmations, with the key of the relayer. The signature is added to the payload in its
+// "attestation" field, a nil service or a service without wallet leaves the payloads unchanged
+type AttestationService struct {
+	wallet *types.Wallet
+}
+
+// NewAttestationService returns a new instance of AttestationService signing with the wallet, nil disables the signatures
+func NewAttestationService(wallet *types.Wallet) *AttestationService {
+	return &AttestationService{wallet}
+}
+
+// NewAttestationServiceFromConfig returns the AttestationService of the response_signing settings, signing with
+// their wallet or the default admin wallet, the service does not sign unless enabled is true
+func NewAttestationServiceFromConfig(c map[string]string, walletDao interfaces.WalletDao) (*AttestationService, error) {
+	if c["enabled"] != "true" {
+		return NewAttestationService(nil), nil
+	}
+
+	var wallet *types.Wallet
+	var err error
+	if c["wallet"] != "" {
+		wallet, err = walletDao.GetByAddress(common.HexToAddress(c["wallet"]))
+	} else {
+		wallet, err = walletDao.GetDefaultAdminWallet()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if wallet == nil || wallet.PrivateKey == nil {
+		return nil, errors.New("Response signing wallet not found")
+	}
+
+	return NewAttestationService(wallet), nil
+}
+
+// Enabled returns true if the payloads are signed
+func (s *AttestationService) Enabled() bool {
+	return s != nil && s.wallet != nil
+}
+
+// Attest returns the payload with its attestation, or the payload itself if the signatures are
+// disabled or the payload can not be signed
+func (s *AttestationService) Attest(kind string, payload interface{}) interface{} {
+	if !s.Enabled() {
+		return payload
+	}
+
+	a, err := types.NewAttestation(kind, payload, time.Now().Unix())
+	if err != nil {
+		logger.Error(err)
+		return payload
+	}
+
+	err = a.Sign(s.wallet)
+	if err != nil {
+		logger.Error(err)
+		return payload
+	}
+
+	res, err := a.AttachTo()
+	if err != nil {
+		logger.Error(err)
+		return payload
+	}
+
+	return res
+}
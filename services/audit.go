@@ -0,0 +1,21 @@
+package services
+
+import (
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// AuditService exposes the audit logs of the admin and operator APIs
+type AuditService struct {
+	auditDao interfaces.AuditDao
+}
+
+// NewAuditService returns a new instance of AuditService
+func NewAuditService(auditDao interfaces.AuditDao) *AuditService {
+	return &AuditService{auditDao}
+}
+
+// GetAll returns the audit logs, most recent first
+func (s *AuditService) GetAll(offset, limit int) ([]*types.AuditLog, error) {
+	return s.auditDao.GetAll(offset, limit)
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// BalanceHistoryService takes periodic snapshots of the token balances of the accounts and
+// reconstructs their balances at a past time from the closest snapshot and the trades made since
+type BalanceHistoryService struct {
+	snapshotDao interfaces.BalanceSnapshotDao
+	accountDao  interfaces.AccountDao
+	tokenDao    interfaces.TokenDao
+	pairDao     interfaces.PairDao
+	tradeDao    interfaces.TradeDao
+	provider    interfaces.EthereumProvider
+}
+
+// NewBalanceHistoryService returns a new instance of BalanceHistoryService
+func NewBalanceHistoryService(
+	snapshotDao interfaces.BalanceSnapshotDao,
+	accountDao interfaces.AccountDao,
+	tokenDao interfaces.TokenDao,
+	pairDao interfaces.PairDao,
+	tradeDao interfaces.TradeDao,
+	provider interfaces.EthereumProvider,
+) *BalanceHistoryService {
+	return &BalanceHistoryService{
+		snapshotDao: snapshotDao,
+		accountDao:  accountDao,
+		tokenDao:    tokenDao,
+		pairDao:     pairDao,
+		tradeDao:    tradeDao,
+		provider:    provider,
+	}
+}
+
+// Snapshot stores the balances of the listed tokens of all the accounts
+func (s *BalanceHistoryService) Snapshot() error {
+	accounts, err := s.accountDao.GetAll()
+	if err != nil {
+		return err
+	}
+
+	tokens, err := s.tokenDao.GetAll()
+	if err != nil {
+		return err
+	}
+
+	for _, a := range accounts {
+		snapshot := &types.BalanceSnapshot{
+			Address:   a.Address,
+			Balances:  make(map[common.Address]*big.Int),
+			CreatedAt: time.Now(),
+		}
+
+		for _, t := range tokens {
+			b, err := s.provider.Balance(a.Address, t.ContractAddress)
+			if err != nil {
+				logger.Error(err)
+				continue
+			}
+
+			snapshot.Balances[t.ContractAddress] = b
+		}
+
+		err := s.snapshotDao.Create(snapshot)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetBalancesAt returns the approximate balances of an address at a past time.
+// The last snapshot before the time is used, the first one after it if there is none
+func (s *BalanceHistoryService) GetBalancesAt(addr common.Address, at time.Time) (*types.HistoricalBalances, error) {
+	snapshot, err := s.snapshotDao.GetLastBefore(addr, at)
+	if err != nil {
+		return nil, err
+	}
+
+	from, to := at, at
+	if snapshot != nil {
+		from = snapshot.CreatedAt
+	} else {
+		snapshot, err = s.snapshotDao.GetFirstAfter(addr, at)
+		if err != nil {
+			return nil, err
+		}
+
+		if snapshot == nil {
+			return nil, ErrBalanceSnapshotNotFound
+		}
+
+		to = snapshot.CreatedAt
+	}
+
+	trades, err := s.tradeDao.GetByUserAndPeriod(addr, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	pairs := make(map[string]*types.Pair)
+	for _, t := range trades {
+		code := t.BaseToken.Hex() + "::" + t.QuoteToken.Hex()
+		if _, ok := pairs[code]; ok {
+			continue
+		}
+
+		p, err := s.pairDao.GetByTokenAddress(t.BaseToken, t.QuoteToken)
+		if err != nil {
+			return nil, err
+		}
+
+		pairs[code] = p
+	}
+
+	return types.NewHistoricalBalances(snapshot, at, trades, pairs), nil
+}
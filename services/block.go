@@ -0,0 +1,171 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	eth "github.com/ethereum/go-ethereum/core/types"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils"
+	"github.com/tomochain/tomox-sdk/ws"
+)
+
+const (
+	// blockWatchRetryInterval is the delay before resubscribing to new heads after a failure
+	blockWatchRetryInterval = 5 * time.Second
+	// defaultMaxChainLag is the number of seconds the latest block may be behind before the node is considered lagging
+	defaultMaxChainLag = int64(60)
+)
+
+// BlockService follows the chain head of the node connected to the SDK
+// and streams new block headers over the blocks channel
+type BlockService struct {
+	provider    interfaces.EthereumProvider
+	relayer     interfaces.Relayer
+	deps        interfaces.DependencyService
+	latestBlock *types.BlockHeader
+	mutex       sync.RWMutex
+	callbacks   []func(*types.BlockHeader)
+}
+
+// NewBlockService returns a new instance of BlockService
+func NewBlockService(provider interfaces.EthereumProvider, relayer interfaces.Relayer, deps interfaces.DependencyService) *BlockService {
+	return &BlockService{
+		provider: provider,
+		relayer:  relayer,
+		deps:     deps,
+	}
+}
+
+// RegisterNotify registers a function called on every new block header
+func (s *BlockService) RegisterNotify(fn func(*types.BlockHeader)) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.callbacks = append(s.callbacks, fn)
+}
+
+// Subscribe sends the latest known block header and registers the client for new blocks
+func (s *BlockService) Subscribe(c *ws.Client) {
+	socket := ws.GetBlockSocket()
+
+	id := utils.GetBlockChannelID(ws.BlockChannel)
+	err := socket.Subscribe(id, c)
+	if err != nil {
+		logger.Error(err)
+		socket.SendErrorMessage(c, err.Error())
+		return
+	}
+
+	ws.RegisterConnectionUnsubscribeHandler(c, socket.UnsubscribeChannelHandler(id))
+	socket.SendInitMessage(c, s.getLatestBlock())
+}
+
+// Unsubscribe removes the client from the blocks channel
+func (s *BlockService) Unsubscribe(c *ws.Client) {
+	socket := ws.GetBlockSocket()
+	socket.Unsubscribe(c)
+}
+
+// GetChainStatus returns the chain lag computed from the latest received block header
+func (s *BlockService) GetChainStatus() *types.ChainStatus {
+	maxLag := app.Config.MaxChainLag
+	if maxLag <= 0 {
+		maxLag = defaultMaxChainLag
+	}
+
+	return types.NewChainStatus(s.getLatestBlock(), time.Now(), maxLag)
+}
+
+// GetHealth returns the chain status with the health check of the node answering the contract calls,
+// whose latest block may be as old as the max chain lag, and the metrics of the MongoDB and RabbitMQ clients
+func (s *BlockService) GetHealth() *types.Health {
+	status := s.GetChainStatus()
+	node := s.relayer.HealthCheck()
+
+	health := &types.Health{
+		ChainStatus: status,
+		Ready:       status.IsHealthy() && node.IsHealthy(status.MaxLag),
+		Node:        node,
+	}
+
+	if s.deps != nil {
+		health.Dependencies = s.deps.GetMetrics()
+	}
+
+	return health
+}
+
+// WatchChainHead subscribes to new block headers of the connected node.
+// The subscription is restored whenever it fails
+func (s *BlockService) WatchChainHead() {
+	header, err := s.provider.HeaderByNumber(nil)
+	if err == nil {
+		s.handleNewHead(header)
+	}
+
+	for {
+		err := s.watchNewHeads()
+		if err != nil {
+			logger.Error("Chain head subscription failed:", err)
+		}
+
+		time.Sleep(blockWatchRetryInterval)
+	}
+}
+
+func (s *BlockService) watchNewHeads() error {
+	headers := make(chan *eth.Header)
+	sub, err := s.provider.SubscribeNewHead(headers)
+	if err != nil {
+		return err
+	}
+
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case err := <-sub.Err():
+			return err
+		case header := <-headers:
+			s.handleNewHead(header)
+		}
+	}
+}
+
+func (s *BlockService) handleNewHead(header *eth.Header) {
+	if header == nil {
+		return
+	}
+
+	block := &types.BlockHeader{
+		Number:     header.Number.Uint64(),
+		Hash:       header.Hash(),
+		ParentHash: header.ParentHash,
+		Timestamp:  header.Time.Int64(),
+	}
+
+	txCount, err := s.provider.TransactionCount(block.Hash)
+	if err == nil {
+		block.TxCount = txCount
+	}
+
+	s.mutex.Lock()
+	s.latestBlock = block
+	callbacks := s.callbacks
+	s.mutex.Unlock()
+
+	id := utils.GetBlockChannelID(ws.BlockChannel)
+	ws.GetBlockSocket().BroadcastMessage(id, block)
+
+	for _, fn := range callbacks {
+		fn(block)
+	}
+}
+
+func (s *BlockService) getLatestBlock() *types.BlockHeader {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.latestBlock
+}
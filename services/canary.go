@@ -0,0 +1,376 @@
+package services
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/errors"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/ws"
+)
+
+const (
+	// canaryHistory is the number of probes kept for the stats
+	canaryHistory = 1000
+
+	defaultCanaryInterval = 60
+	defaultCanaryTimeout  = 30
+)
+
+// CanaryService submits a synthetic order and a matching order from a dedicated wallet on a test pair
+// at every run and times the order flow up to the websocket broadcast of their trade. The orders
+// are tagged by the canary wallet. The probes are kept in memory, a probe breaching a threshold is
+// logged and opens an incident when configured, which is resolved by the next successful probe
+type CanaryService struct {
+	orderService    interfaces.OrderService
+	walletDao       interfaces.WalletDao
+	pairDao         interfaces.PairDao
+	incidentService interfaces.IncidentService
+	wallet          common.Address
+	baseToken       common.Address
+	quoteToken      common.Address
+	price           *big.Int
+	amount          *big.Int
+	timeout         time.Duration
+	thresholds      map[string]time.Duration
+	incidents       bool
+	mutex           sync.RWMutex
+	probes          []*types.CanaryProbe
+	incident        bson.ObjectId
+}
+
+// NewCanaryService returns a new instance of CanaryService configured by the "canary" config section
+func NewCanaryService(
+	orderService interfaces.OrderService,
+	walletDao interfaces.WalletDao,
+	pairDao interfaces.PairDao,
+	incidentService interfaces.IncidentService,
+) *CanaryService {
+	c := app.Config.Canary
+	price, _ := new(big.Int).SetString(c["price"], 10)
+	amount, _ := new(big.Int).SetString(c["amount"], 10)
+
+	return &CanaryService{
+		orderService:    orderService,
+		walletDao:       walletDao,
+		pairDao:         pairDao,
+		incidentService: incidentService,
+		wallet:          common.HexToAddress(c["wallet"]),
+		baseToken:       common.HexToAddress(c["base_token"]),
+		quoteToken:      common.HexToAddress(c["quote_token"]),
+		price:           price,
+		amount:          amount,
+		timeout:         time.Duration(canarySetting("timeout", defaultCanaryTimeout)) * time.Second,
+		thresholds: map[string]time.Duration{
+			types.CanaryStageSubmit:    time.Duration(canarySetting("submit_ms", 0)) * time.Millisecond,
+			types.CanaryStageAck:       time.Duration(canarySetting("ack_ms", 0)) * time.Millisecond,
+			types.CanaryStageMatch:     time.Duration(canarySetting("match_ms", 0)) * time.Millisecond,
+			types.CanaryStageBroadcast: time.Duration(canarySetting("broadcast_ms", 0)) * time.Millisecond,
+		},
+		incidents: c["incidents"] == "true",
+	}
+}
+
+func canarySetting(key string, def int) int {
+	v, err := strconv.Atoi(app.Config.Canary[key])
+	if err != nil || v <= 0 {
+		return def
+	}
+
+	return v
+}
+
+// Enabled returns true if a canary wallet is configured
+func (s *CanaryService) Enabled() bool {
+	return s.wallet != (common.Address{})
+}
+
+// Interval returns the time between two probes
+func (s *CanaryService) Interval() time.Duration {
+	return time.Duration(canarySetting("interval", defaultCanaryInterval)) * time.Second
+}
+
+// Probe runs a probe and records it, an error is returned if the probe breached a threshold
+func (s *CanaryService) Probe() error {
+	if !s.Enabled() {
+		return nil
+	}
+
+	p, err := s.run()
+	if err != nil {
+		p.Error = err.Error()
+	}
+
+	breaches := p.Evaluate(s.thresholds)
+	s.record(p)
+	s.alert(p)
+
+	if len(breaches) > 0 {
+		return errors.New("Canary thresholds breached: " + strings.Join(breaches, ", "))
+	}
+
+	return nil
+}
+
+// GetStats returns the summary of the recent probes with the latest ones
+func (s *CanaryService) GetStats(latest int) *types.CanaryStats {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return types.NewCanaryStats(s.probes, latest)
+}
+
+// canaryEvent is the time a stage of a probe was reached
+type canaryEvent struct {
+	stage string
+	at    time.Time
+}
+
+// run submits the maker order, waits for it to be added then submits the taker order and waits
+// for their trade to be sent to the canary and published on the trades channel
+func (s *CanaryService) run() (*types.CanaryProbe, error) {
+	pair, err := s.pairDao.GetByTokenAddress(s.baseToken, s.quoteToken)
+	if err != nil {
+		return types.NewCanaryProbe("", time.Now()), err
+	}
+
+	if pair == nil {
+		return types.NewCanaryProbe("", time.Now()), ErrPairNotFound
+	}
+
+	p := types.NewCanaryProbe(pair.Name(), time.Now())
+
+	wallet, err := s.walletDao.GetByAddress(s.wallet)
+	if err != nil {
+		return p, err
+	}
+
+	if wallet == nil {
+		return p, errors.New("Canary wallet not found")
+	}
+
+	nonce, err := s.nonce()
+	if err != nil {
+		return p, err
+	}
+
+	maker, err := s.newOrder(wallet, types.SELL, nonce)
+	if err != nil {
+		return p, err
+	}
+
+	taker, err := s.newOrder(wallet, types.BUY, new(big.Int).Add(nonce, big.NewInt(1)))
+	if err != nil {
+		return p, err
+	}
+
+	p.MakerHash = maker.Hash
+	p.TakerHash = taker.Hash
+
+	events := make(chan canaryEvent, 8)
+	ws.SetProbe(s.wallet, canaryProbeFunc(maker.Hash, taker.Hash, events))
+	defer ws.RemoveProbe(s.wallet)
+
+	deadline := time.After(s.timeout)
+
+	start := time.Now()
+	err = s.orderService.NewOrder(maker)
+	if err != nil {
+		return p, err
+	}
+
+	p.Record(types.CanaryStageSubmit, time.Since(start))
+
+	if !s.wait(events, deadline, start, p, types.CanaryStageAck) {
+		s.cancel(wallet, maker, new(big.Int).Add(nonce, big.NewInt(2)))
+		return p, nil
+	}
+
+	start = time.Now()
+	err = s.orderService.NewOrder(taker)
+	if err != nil {
+		s.cancel(wallet, maker, new(big.Int).Add(nonce, big.NewInt(2)))
+		return p, err
+	}
+
+	if !s.wait(events, deadline, start, p, types.CanaryStageMatch, types.CanaryStageBroadcast) {
+		s.cancel(wallet, maker, new(big.Int).Add(nonce, big.NewInt(2)))
+		s.cancel(wallet, taker, new(big.Int).Add(nonce, big.NewInt(3)))
+	}
+
+	return p, nil
+}
+
+// wait records the latencies of the stages from the start until all of them are reached,
+// false is returned at the deadline
+func (s *CanaryService) wait(events chan canaryEvent, deadline <-chan time.Time, start time.Time, p *types.CanaryProbe, stages ...string) bool {
+	pending := make(map[string]bool)
+	for _, stage := range stages {
+		pending[stage] = true
+	}
+
+	for len(pending) > 0 {
+		select {
+		case ev := <-events:
+			if pending[ev.stage] {
+				p.Record(ev.stage, ev.at.Sub(start))
+				delete(pending, ev.stage)
+			}
+		case <-deadline:
+			return false
+		}
+	}
+
+	return true
+}
+
+// canaryProbeFunc returns the probe of the canary wallet, which times the stages of the orders of a probe
+func canaryProbeFunc(maker, taker common.Hash, events chan canaryEvent) ws.ProbeFunc {
+	send := func(stage string) {
+		select {
+		case events <- canaryEvent{stage, time.Now()}:
+		default:
+		}
+	}
+
+	return func(channel string, msgType types.SubscriptionEvent, payload interface{}) {
+		switch {
+		case channel == ws.OrderChannel && msgType == types.ORDER_ADDED:
+			if o, ok := payload.(*types.Order); ok && o.Hash == maker {
+				send(types.CanaryStageAck)
+			}
+		case channel == ws.OrderChannel && msgType == "ORDER_SUCCESS":
+			if m, ok := payload.(types.OrderSuccessPayload); ok && m.Matches != nil && hasTakerTrade(m.Matches.Trades, taker) {
+				send(types.CanaryStageMatch)
+			}
+		case channel == ws.TradeChannel:
+			if trades, ok := payload.([]*types.Trade); ok && hasTakerTrade(trades, taker) {
+				send(types.CanaryStageBroadcast)
+			}
+		}
+	}
+}
+
+func hasTakerTrade(trades []*types.Trade, taker common.Hash) bool {
+	for _, t := range trades {
+		if t.TakerOrderHash == taker {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newOrder returns a signed limit order of the canary at the configured price and amount
+func (s *CanaryService) newOrder(wallet *types.Wallet, side string, nonce *big.Int) (*types.Order, error) {
+	o := &types.Order{
+		UserAddress:     s.wallet,
+		ExchangeAddress: common.HexToAddress(app.Config.Tomochain["exchange_address"]),
+		BaseToken:       s.baseToken,
+		QuoteToken:      s.quoteToken,
+		Side:            side,
+		Type:            types.TypeLimitOrder,
+		Status:          "NEW",
+		PricePoint:      s.price,
+		Amount:          s.amount,
+		Nonce:           nonce,
+	}
+
+	err := wallet.SignOrder(o)
+	if err != nil {
+		return nil, err
+	}
+
+	return o, nil
+}
+
+// cancel cancels an order of the canary left in the book by a failed probe
+func (s *CanaryService) cancel(wallet *types.Wallet, o *types.Order, nonce *big.Int) {
+	stored, err := s.orderService.GetByHash(o.Hash)
+	if err != nil || stored == nil || !types.CanTransitionOrderStatus(stored.Status, types.OrderStatusCancelled) {
+		return
+	}
+
+	oc := &types.OrderCancel{
+		OrderHash:       o.Hash,
+		Nonce:           nonce,
+		OrderID:         stored.OrderID,
+		Status:          types.OrderStatusCancelled,
+		UserAddress:     o.UserAddress,
+		ExchangeAddress: o.ExchangeAddress,
+	}
+
+	err = oc.Sign(wallet)
+	if err == nil {
+		err = s.orderService.CancelOrder(oc)
+	}
+
+	if err != nil {
+		logger.Error("Canary order not cancelled", o.Hash.Hex(), err)
+	}
+}
+
+// nonce returns the next order nonce of the canary wallet
+func (s *CanaryService) nonce() (*big.Int, error) {
+	res, err := s.orderService.GetOrderNonceByUserAddress(s.wallet)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeOrderNonce(res)
+}
+
+func (s *CanaryService) record(p *types.CanaryProbe) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.probes = append(s.probes, p)
+	if len(s.probes) > canaryHistory {
+		s.probes = s.probes[len(s.probes)-canaryHistory:]
+	}
+}
+
+// alert logs the breaches of a probe and opens an incident, the incident is resolved by the next successful probe
+func (s *CanaryService) alert(p *types.CanaryProbe) {
+	if len(p.Breaches) == 0 {
+		if s.incident != "" {
+			_, err := s.incidentService.Update(s.incident, &types.IncidentPayload{
+				Status:  types.IncidentStatusResolved,
+				Message: "The order flow is back to normal",
+			}, "canary")
+			if err != nil && err != ErrIncidentResolved {
+				logger.Error(err)
+				return
+			}
+
+			s.incident = ""
+		}
+
+		return
+	}
+
+	logger.Errorf("Canary probe on %s breached: %s", p.PairName, strings.Join(p.Breaches, ", "))
+	if !s.incidents || s.incident != "" {
+		return
+	}
+
+	i, err := s.incidentService.Create(&types.IncidentPayload{
+		Title:      "Degraded order processing",
+		Impact:     types.IncidentImpactMinor,
+		Components: []string{types.ComponentMatching},
+		Message:    "Orders are processed slower than usual: " + strings.Join(p.Breaches, ", "),
+	}, "canary")
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	s.incident = i.ID
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"math/big"
+	"time"
+
+	ether "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	eth "github.com/ethereum/go-ethereum/core/types"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/ethereum"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// chainListenerRetryInterval is the default delay before subscribing again to the chain after a failure
+const chainListenerRetryInterval = 10 * time.Second
+
+// chainListenerWatcher is the name of the last processed block of the exchange logs in the config collection
+const chainListenerWatcher = "chain_listener"
+
+// chainLogBackfillRange is the number of blocks whose logs are read by a call when catching up
+const chainLogBackfillRange = 5000
+
+// ChainListenerService follows the new block headers and the logs of the exchange contract over the
+// websocket endpoint of the node. The settled and failed trades and the cancelled orders are fed to
+// the trade and the order services as soon as their block is imported, without waiting for the
+// TomoX node to update the database
+type ChainListenerService struct {
+	provider     interfaces.EthereumProvider
+	orderService interfaces.OrderService
+	tradeService interfaces.TradeService
+	cursor       *watcherCursor
+	exchange     common.Address
+}
+
+// NewChainListenerService returns a new instance of ChainListenerService
+func NewChainListenerService(
+	provider interfaces.EthereumProvider,
+	orderService interfaces.OrderService,
+	tradeService interfaces.TradeService,
+	configDao interfaces.ConfigDao,
+) *ChainListenerService {
+	return &ChainListenerService{
+		provider:     provider,
+		orderService: orderService,
+		tradeService: tradeService,
+		cursor:       &watcherCursor{configDao, chainListenerWatcher},
+		exchange:     common.HexToAddress(app.Config.Tomochain["exchange_address"]),
+	}
+}
+
+// Enabled returns true if the chain listener is enabled in the config
+func (s *ChainListenerService) Enabled() bool {
+	return app.Config.ChainListener["enabled"] > 0
+}
+
+// Watch subscribes to the new block headers and the logs of the exchange contract. The subscriptions
+// are restored whenever they fail, the logs emitted meanwhile are read from the last processed block
+// saved in the config collection
+func (s *ChainListenerService) Watch() {
+	retry := time.Duration(app.Config.ChainListener["retry"]) * time.Second
+	if retry <= 0 {
+		retry = chainListenerRetryInterval
+	}
+
+	for {
+		err := s.watch()
+		if err != nil {
+			logger.Error("Chain listener subscription failed:", err)
+		}
+
+		time.Sleep(retry)
+	}
+}
+
+// watch processes the logs until a subscription fails. The subscriptions are opened before the missed
+// logs are read so that none is lost, a log delivered twice is applied once by the services. Each new
+// header saves its parent as processed, the logs of a block being delivered before the next header
+func (s *ChainListenerService) watch() error {
+	q := ether.FilterQuery{
+		Addresses: []common.Address{s.exchange},
+		Topics:    [][]common.Hash{ethereum.ExchangeEventTopics()},
+	}
+
+	headers := make(chan *eth.Header, 16)
+	headSub, err := s.provider.SubscribeNewHead(headers)
+	if err != nil {
+		return err
+	}
+
+	defer headSub.Unsubscribe()
+
+	logs := make(chan eth.Log, 128)
+	logSub, err := s.provider.SubscribeFilterLogs(q, logs)
+	if err != nil {
+		return err
+	}
+
+	defer logSub.Unsubscribe()
+
+	from, err := s.cursor.LastProcessedBlock()
+	if err != nil {
+		return err
+	}
+
+	if from > 0 {
+		err = s.backfill(q, from)
+		if err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case err := <-headSub.Err():
+			return err
+		case err := <-logSub.Err():
+			return err
+		case h := <-headers:
+			if h != nil && h.Number.Uint64() > 0 {
+				s.saveProcessedBlock(h.Number.Uint64() - 1)
+			}
+		case l := <-logs:
+			s.handleLog(l)
+		}
+	}
+}
+
+// backfill processes the logs emitted from a block up to the head of the chain, read by ranges of blocks
+func (s *ChainListenerService) backfill(q ether.FilterQuery, from uint64) error {
+	head, err := s.provider.HeaderByNumber(nil)
+	if err != nil {
+		return err
+	}
+
+	to := head.Number.Uint64()
+	if from > to {
+		return nil
+	}
+
+	logger.Infof("Reading the exchange logs of the blocks %d to %d", from, to)
+	for start := from; start <= to; start += chainLogBackfillRange {
+		end := start + chainLogBackfillRange - 1
+		if end > to {
+			end = to
+		}
+
+		q.FromBlock = new(big.Int).SetUint64(start)
+		q.ToBlock = new(big.Int).SetUint64(end)
+		logs, err := s.provider.FilterLogs(q)
+		if err != nil {
+			return err
+		}
+
+		for _, l := range logs {
+			s.handleLog(l)
+		}
+
+		s.saveProcessedBlock(end)
+	}
+
+	return nil
+}
+
+// handleLog decodes a log of the exchange contract and feeds it to the service it concerns. The logs
+// removed by a reorganization are skipped, the reorg service rolls back the trades they concerned
+func (s *ChainListenerService) handleLog(l eth.Log) {
+	if l.Removed {
+		return
+	}
+
+	ev, err := ethereum.DecodeExchangeLog(l)
+	if err != nil {
+		logger.Warning("Exchange log ignored:", l.TxHash.Hex(), err)
+		return
+	}
+
+	if ev == nil {
+		return
+	}
+
+	switch ev.Type {
+	case types.ChainEventTradeSettled, types.ChainEventTradeFailed:
+		err = s.tradeService.HandleChainEvent(ev)
+	case types.ChainEventOrderCancelled:
+		err = s.orderService.HandleChainEvent(ev)
+	}
+
+	if err != nil {
+		logger.Error("Exchange event not applied:", ev.Type, ev.TxHash.Hex(), err)
+	}
+}
+
+// saveProcessedBlock moves the cursor to a block, the failures are logged as the block is saved again
+// with the next header
+func (s *ChainListenerService) saveProcessedBlock(block uint64) {
+	err := s.cursor.SaveProcessedBlock(block)
+	if err != nil {
+		logger.Warning("Last processed block not saved:", block, err)
+	}
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/math"
+)
+
+// Defaults of the collateral liquidity monitoring, overridden by the "illiquid_collateral" config section
+const (
+	defaultDepthBandBps   = 200
+	defaultMinCoveragePct = 100
+)
+
+// CollateralMonitorService watches the liquidity of the collateral of the open loans. The loans of a collateral
+// are flagged when the bids within the depth band of its market with the lending token no longer cover the
+// collateral they lock, their lenders are notified once that recalling them may be prudent
+type CollateralMonitorService struct {
+	lendingTradeDao     interfaces.LendingTradeDao
+	orderBookService    interfaces.OrderBookService
+	notificationService interfaces.NotificationService
+	alerted             map[common.Hash]bool
+	mutex               sync.Mutex
+}
+
+// NewCollateralMonitorService returns a new instance of CollateralMonitorService
+func NewCollateralMonitorService(
+	lendingTradeDao interfaces.LendingTradeDao,
+	orderBookService interfaces.OrderBookService,
+	notificationService interfaces.NotificationService,
+) *CollateralMonitorService {
+	return &CollateralMonitorService{
+		lendingTradeDao:     lendingTradeDao,
+		orderBookService:    orderBookService,
+		notificationService: notificationService,
+		alerted:             make(map[common.Hash]bool),
+	}
+}
+
+// market is the open loans of a collateral token and lending token
+type market struct {
+	collateralToken common.Address
+	lendingToken    common.Address
+	locked          *big.Int
+	trades          []*types.LendingTrade
+}
+
+// Check notifies the lenders of the loans whose collateral became illiquid since the previous check.
+// A loan is alerted again only after the liquidity of its collateral recovered
+func (s *CollateralMonitorService) Check() error {
+	trades, err := s.lendingTradeDao.GetOpen()
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	markets := make(map[string]*market)
+	for _, t := range trades {
+		key := t.CollateralToken.Hex() + t.LendingToken.Hex()
+		m := markets[key]
+		if m == nil {
+			m = &market{collateralToken: t.CollateralToken, lendingToken: t.LendingToken, locked: big.NewInt(0)}
+			markets[key] = m
+		}
+
+		if t.CollateralLockedAmount != nil {
+			m.locked = math.Add(m.locked, t.CollateralLockedAmount)
+		}
+
+		m.trades = append(m.trades, t)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	open := make(map[common.Hash]bool)
+	for _, m := range markets {
+		ob, err := s.orderBookService.GetOrderBook(m.collateralToken, m.lendingToken)
+		if err != nil {
+			logger.Warningf("No order book for collateral %s, liquidity not checked", m.collateralToken.Hex())
+			continue
+		}
+
+		depth := types.BidDepth(ob.Bids, collateralConfig("band_bps", defaultDepthBandBps))
+		required := math.Div(math.Mul(m.locked, big.NewInt(int64(collateralConfig("min_coverage_pct", defaultMinCoveragePct)))), big.NewInt(100))
+		illiquid := math.IsStrictlySmallerThan(depth, required)
+
+		for _, t := range m.trades {
+			open[t.Hash] = true
+
+			if !illiquid {
+				delete(s.alerted, t.Hash)
+				continue
+			}
+
+			if s.alerted[t.Hash] {
+				continue
+			}
+
+			s.alerted[t.Hash] = true
+			s.notifyLender(t, ob.PairName, depth, m.locked)
+		}
+	}
+
+	for h := range s.alerted {
+		if !open[h] {
+			delete(s.alerted, h)
+		}
+	}
+
+	return nil
+}
+
+func (s *CollateralMonitorService) notifyLender(t *types.LendingTrade, pairName string, depth, locked *big.Int) {
+	err := s.notificationService.Notify(&types.Notification{
+		Recipient: t.Investor,
+		Message: types.Message{
+			MessageType: types.IlliquidCollateralMessage,
+			Description: fmt.Sprintf(
+				"The collateral of your loan %s is illiquid: %s bid on %s near the best price for %s locked by the open loans, recalling it may be prudent",
+				t.Hash.Hex(),
+				depth,
+				pairName,
+				locked,
+			),
+		},
+		Type:   types.TypeAlert,
+		Status: types.StatusUnread,
+	})
+
+	if err != nil {
+		logger.Error(err)
+	}
+}
+
+func collateralConfig(key string, def int) int {
+	if v, ok := app.Config.IlliquidCollateral[key]; ok && v > 0 {
+		return v
+	}
+
+	return def
+}
@@ -0,0 +1,259 @@
+package services
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/ws"
+)
+
+// CompositeIndexService manages the composite indices defined by the operators and computes their
+// values from the fiat prices of the price board. The values are streamed on the indices channel to the
+// subscriptions of each index. Changing the components of an index rebases it at its current value, so
+// the value of the index stays continuous
+type CompositeIndexService struct {
+	indexDao     interfaces.CompositeIndexDao
+	tokenDao     interfaces.TokenDao
+	ohlcvService interfaces.OHLCVService
+}
+
+// NewCompositeIndexService returns a new instance of CompositeIndexService
+func NewCompositeIndexService(
+	indexDao interfaces.CompositeIndexDao,
+	tokenDao interfaces.TokenDao,
+	ohlcvService interfaces.OHLCVService,
+) *CompositeIndexService {
+	return &CompositeIndexService{
+		indexDao:     indexDao,
+		tokenDao:     tokenDao,
+		ohlcvService: ohlcvService,
+	}
+}
+
+// GetAll returns the definitions of the indices
+func (s *CompositeIndexService) GetAll() ([]*types.CompositeIndex, error) {
+	return s.indexDao.GetAll()
+}
+
+// GetByName returns the definition of an index
+func (s *CompositeIndexService) GetByName(name string) (*types.CompositeIndex, error) {
+	i, err := s.indexDao.GetByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if i == nil {
+		return nil, ErrIndexNotFound
+	}
+
+	return i, nil
+}
+
+// GetValue returns the current value of an index
+func (s *CompositeIndexService) GetValue(name string) (*types.IndexValue, error) {
+	i, err := s.GetByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.value(i, map[common.Address]float64{})
+}
+
+// Create defines a new index, its components are priced at the current prices and it starts at its
+// base value, 1000 by default
+func (s *CompositeIndexService) Create(p *types.CompositeIndexPayload, identity string) (*types.CompositeIndex, error) {
+	existing, err := s.indexDao.GetByName(p.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing != nil {
+		return nil, ErrIndexExists
+	}
+
+	symbols, prices, err := s.componentPrices(p.Components)
+	if err != nil {
+		return nil, err
+	}
+
+	baseValue := p.BaseValue
+	if baseValue == 0 {
+		baseValue = types.DefaultIndexBaseValue
+	}
+
+	i := &types.CompositeIndex{
+		Name:        p.Name,
+		Description: p.Description,
+		CreatedBy:   identity,
+	}
+
+	i.Rebase(p.Components, symbols, prices, baseValue, time.Now())
+	err = s.indexDao.Create(i)
+	if err != nil {
+		return nil, err
+	}
+
+	return i, nil
+}
+
+// Update changes the description and the components of an index, which is rebased at its current value
+// or at the base value of the payload if set
+func (s *CompositeIndexService) Update(name string, p *types.CompositeIndexPayload) (*types.CompositeIndex, error) {
+	i, err := s.GetByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	symbols, prices, err := s.componentPrices(p.Components)
+	if err != nil {
+		return nil, err
+	}
+
+	baseValue := p.BaseValue
+	if baseValue == 0 {
+		v, err := s.value(i, prices)
+		if err != nil {
+			return nil, err
+		}
+
+		baseValue = v.Value
+	}
+
+	i.Description = p.Description
+	i.Rebase(p.Components, symbols, prices, baseValue, time.Now())
+	err = s.indexDao.Update(i)
+	if err != nil {
+		return nil, err
+	}
+
+	return i, nil
+}
+
+// Delete removes an index
+func (s *CompositeIndexService) Delete(name string) error {
+	_, err := s.GetByName(name)
+	if err != nil {
+		return err
+	}
+
+	return s.indexDao.DeleteByName(name)
+}
+
+// Broadcast sends the values of the indices to their subscriptions, the prices are read once per run
+func (s *CompositeIndexService) Broadcast() error {
+	socket := ws.GetCompositeIndexSocket()
+	indices, err := s.indexDao.GetAll()
+	if err != nil {
+		return err
+	}
+
+	prices := map[common.Address]float64{}
+	for _, i := range indices {
+		if !socket.Subscribed(i.Name) {
+			continue
+		}
+
+		v, err := s.value(i, prices)
+		if err != nil {
+			logger.Warning("Composite index", i.Name, err)
+			continue
+		}
+
+		socket.BroadcastMessage(i.Name, v)
+	}
+
+	return nil
+}
+
+// Subscribe sends the current value of the index to the connection and streams its updates
+func (s *CompositeIndexService) Subscribe(c *ws.Client, name string) {
+	socket := ws.GetCompositeIndexSocket()
+
+	v, err := s.GetValue(name)
+	if err != nil {
+		socket.SendErrorMessage(c, err.Error())
+		return
+	}
+
+	err = socket.Subscribe(name, c)
+	if err != nil {
+		msg := map[string]string{"Message": err.Error()}
+		socket.SendErrorMessage(c, msg)
+		return
+	}
+
+	ws.RegisterConnectionUnsubscribeHandler(c, socket.UnsubscribeChannelHandler(name))
+	socket.SendInitMessage(c, v)
+}
+
+// Unsubscribe removes the connection from all the indices
+func (s *CompositeIndexService) Unsubscribe(c *ws.Client) {
+	ws.GetCompositeIndexSocket().Unsubscribe(c)
+}
+
+// UnsubscribeChannel removes the connection from an index
+func (s *CompositeIndexService) UnsubscribeChannel(c *ws.Client, name string) {
+	ws.GetCompositeIndexSocket().UnsubscribeChannel(name, c)
+}
+
+// value computes the value of the index, the prices read are added to the prices given
+func (s *CompositeIndexService) value(i *types.CompositeIndex, prices map[common.Address]float64) (*types.IndexValue, error) {
+	for _, c := range i.Components {
+		if _, ok := prices[c.Token]; ok {
+			continue
+		}
+
+		price, err := s.price(c.Symbol)
+		if err != nil {
+			return nil, err
+		}
+
+		prices[c.Token] = price
+	}
+
+	return i.Value(prices, time.Now())
+}
+
+// componentPrices returns the symbols and the current prices of the tokens of the components,
+// which must be listed and priced
+func (s *CompositeIndexService) componentPrices(components []*types.IndexComponentPayload) (map[common.Address]string, map[common.Address]float64, error) {
+	symbols := map[common.Address]string{}
+	prices := map[common.Address]float64{}
+	for _, c := range components {
+		t, err := s.tokenDao.GetByAddress(c.Token)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if t == nil {
+			return nil, nil, ErrTokenNotFound
+		}
+
+		price, err := s.price(t.Symbol)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		symbols[c.Token] = t.Symbol
+		prices[c.Token] = price
+	}
+
+	return symbols, prices, nil
+}
+
+// price returns the fiat price of a token of the price board
+func (s *CompositeIndexService) price(symbol string) (float64, error) {
+	p, err := s.ohlcvService.GetLastPriceCurrentByTime(symbol, time.Now())
+	if err != nil || p == nil {
+		return 0, ErrIndexPriceNotFound
+	}
+
+	f, _ := p.Float64()
+	if f <= 0 {
+		return 0, ErrIndexPriceNotFound
+	}
+
+	return f, nil
+}
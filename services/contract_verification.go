@@ -0,0 +1,149 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/tomoscan"
+	"github.com/tomochain/tomox-sdk/utils"
+)
+
+// contractVerificationTTL is how long the verification status of a contract is cached,
+// a verified contract stays verified so it is not checked again
+const contractVerificationTTL = 24 * time.Hour
+
+// contractVerification is the cached verification status of a contract
+type contractVerification struct {
+	verified  bool
+	checkedAt time.Time
+}
+
+// ContractVerificationService checks on Tomoscan whether the source of the contracts of the listed
+// tokens is verified. The status is recorded on the tokens and the pairs with an unverified token
+// are flagged
+type ContractVerificationService struct {
+	tokenDao interfaces.TokenDao
+	pairDao  interfaces.PairDao
+	client   *tomoscan.Client
+	mutex    sync.Mutex
+	cache    map[common.Address]*contractVerification
+}
+
+// NewContractVerificationService returns a new instance of ContractVerificationService using the
+// Tomoscan API of the "tomoscan_url" setting of the tomochain config section
+func NewContractVerificationService(tokenDao interfaces.TokenDao, pairDao interfaces.PairDao) *ContractVerificationService {
+	s := &ContractVerificationService{
+		tokenDao: tokenDao,
+		pairDao:  pairDao,
+		cache:    make(map[common.Address]*contractVerification),
+	}
+
+	if url := app.Config.Tomochain["tomoscan_url"]; url != "" {
+		s.client = tomoscan.NewClient(url)
+	}
+
+	return s
+}
+
+// Enabled returns true if a Tomoscan API is configured
+func (s *ContractVerificationService) Enabled() bool {
+	return s.client != nil
+}
+
+// Refresh checks the tokens not checked recently and updates the flags of the tokens and the pairs,
+// a token Tomoscan fails to answer for keeps its previous status
+func (s *ContractVerificationService) Refresh() error {
+	if !s.Enabled() {
+		return nil
+	}
+
+	tokens, err := s.tokenDao.GetAll()
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, t := range tokens {
+		if utils.IsNativeTokenByAddress(t.ContractAddress) {
+			continue
+		}
+
+		verified, ok := s.check(t.ContractAddress)
+		if !ok || (t.SourceVerified != nil && *t.SourceVerified == verified) {
+			continue
+		}
+
+		logger.Infof("Contract source of token %s (%s) verified: %t", t.Symbol, t.ContractAddress.Hex(), verified)
+		err = s.tokenDao.SetSourceVerified(t.ContractAddress, verified)
+		if err != nil {
+			return err
+		}
+	}
+
+	pairs, err := s.pairDao.GetAll()
+	if err != nil {
+		return err
+	}
+
+	for _, p := range pairs {
+		base, ok := s.status(p.BaseTokenAddress)
+		quote, ok2 := s.status(p.QuoteTokenAddress)
+		if !ok || !ok2 {
+			continue
+		}
+
+		unverified := !base || !quote
+		if unverified == p.UnverifiedTokens {
+			continue
+		}
+
+		err = s.pairDao.SetUnverifiedTokens(p.BaseTokenAddress, p.QuoteTokenAddress, unverified)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// check returns the verification status of a contract from the cache or from Tomoscan,
+// false is returned as second value if it is unknown. The caller holds the lock
+func (s *ContractVerificationService) check(addr common.Address) (bool, bool) {
+	c := s.cache[addr]
+	if c != nil && (c.verified || time.Since(c.checkedAt) < contractVerificationTTL) {
+		return c.verified, true
+	}
+
+	verified, err := s.client.IsVerified(addr)
+	if err != nil {
+		logger.Warning("Contract verification failed:", addr.Hex(), err)
+		if c != nil {
+			return c.verified, true
+		}
+
+		return false, false
+	}
+
+	s.cache[addr] = &contractVerification{verified: verified, checkedAt: time.Now()}
+	return verified, true
+}
+
+// status returns the cached verification status of a token, a native token is verified.
+// False is returned as second value if it is unknown, the caller holds the lock
+func (s *ContractVerificationService) status(addr common.Address) (bool, bool) {
+	if utils.IsNativeTokenByAddress(addr) {
+		return true, true
+	}
+
+	c := s.cache[addr]
+	if c == nil {
+		return false, false
+	}
+
+	return c.verified, true
+}
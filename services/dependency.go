@@ -0,0 +1,27 @@
+package services
+
+import (
+	"github.com/tomochain/tomox-sdk/daos"
+	"github.com/tomochain/tomox-sdk/rabbitmq"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// DependencyService reports the health of the clients of MongoDB and RabbitMQ, the usual source of the
+// production incidents
+type DependencyService struct {
+	broker *rabbitmq.Connection
+}
+
+// NewDependencyService returns a new instance of DependencyService
+func NewDependencyService(broker *rabbitmq.Connection) *DependencyService {
+	return &DependencyService{broker}
+}
+
+// GetMetrics returns the connection pool, the ping and the slow queries of MongoDB with the publish latency,
+// the waiting messages and the consumer lag of the RabbitMQ queues
+func (s *DependencyService) GetMetrics() *types.DependencyMetrics {
+	return &types.DependencyMetrics{
+		Mongo:    daos.GetMongoMetrics(),
+		RabbitMQ: s.broker.GetMetrics(),
+	}
+}
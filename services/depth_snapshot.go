@@ -0,0 +1,121 @@
+package services
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// Defaults of the "depth_snapshots" config section and limits of the pages of snapshots
+const (
+	defaultDepthSnapshotLevels        = 20
+	defaultDepthSnapshotRetentionDays = 365
+	defaultDepthSnapshotPageSize      = 100
+	maxDepthSnapshotPageSize          = 500
+	maxDepthSnapshotDays              = 366
+)
+
+// DepthSnapshotService records the top levels of the order books of the active pairs every hour, so that
+// the liquidity of the pairs can be analyzed over time, and serves them by pages
+type DepthSnapshotService struct {
+	snapshotDao interfaces.DepthSnapshotDao
+	pairDao     interfaces.PairDao
+	orderDao    interfaces.OrderDao
+}
+
+// NewDepthSnapshotService returns a new instance of DepthSnapshotService
+func NewDepthSnapshotService(
+	snapshotDao interfaces.DepthSnapshotDao,
+	pairDao interfaces.PairDao,
+	orderDao interfaces.OrderDao,
+) *DepthSnapshotService {
+	return &DepthSnapshotService{
+		snapshotDao: snapshotDao,
+		pairDao:     pairDao,
+		orderDao:    orderDao,
+	}
+}
+
+func depthSnapshotSetting(key string, def int) int {
+	if v := app.Config.DepthSnapshots[key]; v > 0 {
+		return v
+	}
+
+	return def
+}
+
+// Snapshot stores the top levels of the order books of the active pairs and removes the expired snapshots.
+// A pair whose book cannot be read is skipped
+func (s *DepthSnapshotService) Snapshot() error {
+	pairs, err := s.pairDao.GetActivePairs()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	levels := depthSnapshotSetting("levels", defaultDepthSnapshotLevels)
+	snapshots := []*types.DepthSnapshot{}
+	for _, p := range pairs {
+		bids, asks, err := s.orderDao.GetOrderBook(p)
+		if err != nil {
+			logger.Error(err)
+			continue
+		}
+
+		snapshots = append(snapshots, types.NewDepthSnapshot(p, bids, asks, levels, now))
+	}
+
+	err = s.snapshotDao.Create(snapshots...)
+	if err != nil {
+		return err
+	}
+
+	days := depthSnapshotSetting("retention_days", defaultDepthSnapshotRetentionDays)
+	return s.snapshotDao.RemoveBefore(now.AddDate(0, 0, -days))
+}
+
+// GetSnapshots returns a page of the snapshots of a pair in [from, to), at most one year, oldest first,
+// pages of 100 snapshots by default
+func (s *DepthSnapshotService) GetSnapshots(bt, qt common.Address, from, to time.Time, page, size int) (*types.DepthSnapshotPage, error) {
+	if !from.Before(to) || to.Sub(from) > maxDepthSnapshotDays*24*time.Hour {
+		return nil, ErrInvalidReportPeriod
+	}
+
+	p, err := s.pairDao.GetByTokenAddress(bt, qt)
+	if err != nil {
+		return nil, err
+	}
+
+	if p == nil {
+		return nil, ErrPairNotFound
+	}
+
+	if size <= 0 {
+		size = defaultDepthSnapshotPageSize
+	}
+
+	if size > maxDepthSnapshotPageSize {
+		size = maxDepthSnapshotPageSize
+	}
+
+	if page < 0 {
+		page = 0
+	}
+
+	snapshots, total, err := s.snapshotDao.GetByPairAndPeriod(bt, qt, from, to, page*size, size)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.DepthSnapshotPage{
+		BaseToken:  bt,
+		QuoteToken: qt,
+		From:       from.Unix(),
+		To:         to.Unix(),
+		Total:      total,
+		Snapshots:  snapshots,
+	}, nil
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// DisputeService assembles the evidence of an order or a trade for the customer support disputes
+type DisputeService struct {
+	orderDao      interfaces.OrderDao
+	tradeDao      interfaces.TradeDao
+	pairDao       interfaces.PairDao
+	settlementDao interfaces.SettlementDao
+	provider      interfaces.EthereumProvider
+}
+
+// NewDisputeService returns a new instance of DisputeService
+func NewDisputeService(
+	orderDao interfaces.OrderDao,
+	tradeDao interfaces.TradeDao,
+	pairDao interfaces.PairDao,
+	settlementDao interfaces.SettlementDao,
+	provider interfaces.EthereumProvider,
+) *DisputeService {
+	return &DisputeService{
+		orderDao:      orderDao,
+		tradeDao:      tradeDao,
+		pairDao:       pairDao,
+		settlementDao: settlementDao,
+		provider:      provider,
+	}
+}
+
+// GetReport returns the evidence report of an order hash or a trade hash.
+// The report of an order includes all its trades, the report of a trade includes its maker and taker orders
+func (s *DisputeService) GetReport(hash common.Hash) (*types.DisputeReport, error) {
+	r := &types.DisputeReport{
+		Hash:        hash,
+		GeneratedAt: time.Now(),
+		Validations: []*types.OrderValidation{},
+		Settlements: []*types.SettlementEvidence{},
+		Balances:    []*types.BalanceEvidence{},
+	}
+
+	o, err := s.orderDao.GetByHash(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	if o != nil {
+		r.Kind = types.DisputeKindOrder
+		r.Orders = []*types.Order{o}
+
+		r.Trades, err = s.getOrderTrades(hash)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		t, err := s.tradeDao.GetByHash(hash)
+		if err != nil {
+			return nil, err
+		}
+
+		if t == nil {
+			return nil, ErrDisputeHashNotFound
+		}
+
+		r.Kind = types.DisputeKindTrade
+		r.Trades = []*types.Trade{t}
+
+		r.Orders, err = s.orderDao.GetByHashes([]common.Hash{t.MakerOrderHash, t.TakerOrderHash})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, o := range r.Orders {
+		r.Validations = append(r.Validations, types.ValidateOrder(o))
+	}
+
+	for _, t := range r.Trades {
+		r.Settlements = append(r.Settlements, s.getSettlement(t))
+
+		p, err := s.pairDao.GetByTokenAddress(t.BaseToken, t.QuoteToken)
+		if err != nil {
+			return nil, err
+		}
+
+		if p == nil {
+			logger.Warningf("Pair of trade %s not found, balance changes skipped", t.Hash.Hex())
+			continue
+		}
+
+		r.Balances = types.AddTradeBalanceChanges(r.Balances, t, p)
+	}
+
+	for _, b := range r.Balances {
+		b.Current, err = s.provider.Balance(b.Address, b.Token)
+		if err != nil {
+			b.Error = err.Error()
+		}
+	}
+
+	return r, nil
+}
+
+func (s *DisputeService) getOrderTrades(hash common.Hash) ([]*types.Trade, error) {
+	maker, err := s.tradeDao.GetByMakerOrderHash(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	taker, err := s.tradeDao.GetByTakerOrderHash(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(maker, taker...), nil
+}
+
+// getSettlement returns the on-chain receipt of the trade settlement and its recovery if it failed
+func (s *DisputeService) getSettlement(t *types.Trade) *types.SettlementEvidence {
+	e := &types.SettlementEvidence{
+		TradeHash:   t.Hash,
+		TxHash:      t.TxHash,
+		TradeStatus: t.Status,
+	}
+
+	if (t.TxHash != common.Hash{}) {
+		receipt, err := s.provider.TransactionReceipt(t.TxHash)
+		if err != nil {
+			e.Error = err.Error()
+		}
+
+		if receipt != nil {
+			e.ReceiptFound = true
+			e.ReceiptStatus = receipt.Status
+			e.GasUsed = receipt.GasUsed
+		}
+	}
+
+	recovery, err := s.settlementDao.GetByTradeHash(t.Hash)
+	if err != nil {
+		logger.Error(err)
+	}
+
+	e.Recovery = recovery
+	return e
+}
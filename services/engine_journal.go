@@ -0,0 +1,56 @@
+package services
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// Page sizes of the engine journal queries
+const (
+	defaultEngineEventLimit = 100
+	maxEngineEventLimit     = 1000
+)
+
+// EngineJournalService records the engine responses as sequenced events so that support
+// and the clients recovering from an outage can replay what happened to the orders
+type EngineJournalService struct {
+	engineEventDao interfaces.EngineEventDao
+}
+
+// NewEngineJournalService returns a new instance of EngineJournalService
+func NewEngineJournalService(engineEventDao interfaces.EngineEventDao) *EngineJournalService {
+	return &EngineJournalService{engineEventDao}
+}
+
+// Record appends the event of an engine response to the journal, the responses without an order are ignored
+func (s *EngineJournalService) Record(res *types.EngineResponse) error {
+	e := types.NewEngineEvent(res)
+	if e == nil {
+		return nil
+	}
+
+	return s.engineEventDao.Create(e)
+}
+
+// GetByOrderHash returns the events of an order following the after sequence number
+func (s *EngineJournalService) GetByOrderHash(h common.Hash, after int64, limit int) ([]*types.EngineEvent, error) {
+	return s.engineEventDao.GetByOrderHash(h, after, engineEventLimit(limit))
+}
+
+// GetByPair returns the events of a pair following the after sequence number
+func (s *EngineJournalService) GetByPair(bt, qt common.Address, after int64, limit int) ([]*types.EngineEvent, error) {
+	return s.engineEventDao.GetByPair(bt, qt, after, engineEventLimit(limit))
+}
+
+func engineEventLimit(limit int) int {
+	if limit <= 0 {
+		return defaultEngineEventLimit
+	}
+
+	if limit > maxEngineEventLimit {
+		return maxEngineEventLimit
+	}
+
+	return limit
+}
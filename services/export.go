@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// Limits of the export streams
+const (
+	defaultExportLimit = 100
+	maxExportLimit     = 1000
+	maxExportWait      = 30 * time.Second
+	exportPollInterval = time.Second
+	// exportSettleDelay holds back the documents updated in the last seconds, so that a write
+	// committed after a later one is not skipped by a checkpoint taken in between
+	exportSettleDelay = 2 * time.Second
+)
+
+// ExportService streams the trades and the orders to the data consumers in the order of their last
+// update. A consumer pulls the documents changed after its checkpoint and persists the checkpoint of
+// the batch once it is processed, a batch pulled again after a reconnect is delivered again, so every
+// change is delivered at least once. The pulls wait for new documents up to the wait of the request
+type ExportService struct {
+	tradeDao interfaces.TradeDao
+	orderDao interfaces.OrderDao
+}
+
+// NewExportService returns a new instance of ExportService
+func NewExportService(tradeDao interfaces.TradeDao, orderDao interfaces.OrderDao) *ExportService {
+	return &ExportService{
+		tradeDao: tradeDao,
+		orderDao: orderDao,
+	}
+}
+
+// Pull returns up to limit documents of the stream changed after the checkpoint, 100 by default and
+// 1000 at most. Without new documents it polls until the wait, up to 30 seconds, or the end of the
+// context and returns an empty batch with the same checkpoint
+func (s *ExportService) Pull(ctx context.Context, stream, checkpoint string, limit int, wait time.Duration) (*types.ExportPage, error) {
+	if stream != types.ExportTrades && stream != types.ExportOrders {
+		return nil, ErrInvalidExportStream
+	}
+
+	cp, err := types.ParseExportCheckpoint(checkpoint)
+	if err != nil {
+		return nil, ErrInvalidCheckpoint
+	}
+
+	if limit <= 0 {
+		limit = defaultExportLimit
+	}
+
+	if limit > maxExportLimit {
+		limit = maxExportLimit
+	}
+
+	if wait > maxExportWait {
+		wait = maxExportWait
+	}
+
+	deadline := time.Now().Add(wait)
+	for {
+		page, err := s.read(stream, cp, limit)
+		if err != nil {
+			return nil, err
+		}
+
+		if page.Count > 0 || !time.Now().Before(deadline) {
+			return page, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return page, nil
+		case <-time.After(exportPollInterval):
+		}
+	}
+}
+
+// read returns the documents settled after the checkpoint, one more is read to tell if more are ready
+func (s *ExportService) read(stream string, cp *types.ExportCheckpoint, limit int) (*types.ExportPage, error) {
+	until := time.Now().Add(-exportSettleDelay)
+	page := &types.ExportPage{Stream: stream, Checkpoint: cp.Encode()}
+	last := cp
+
+	switch stream {
+	case types.ExportTrades:
+		trades, err := s.tradeDao.GetAfterCheckpoint(cp, until, limit+1)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(trades) > limit {
+			trades = trades[:limit]
+			page.HasMore = true
+		}
+
+		if len(trades) > 0 {
+			t := trades[len(trades)-1]
+			last = &types.ExportCheckpoint{UpdatedAt: t.UpdatedAt, ID: t.ID}
+		}
+
+		page.Items = trades
+		page.Count = len(trades)
+	case types.ExportOrders:
+		orders, err := s.orderDao.GetAfterCheckpoint(cp, until, limit+1)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(orders) > limit {
+			orders = orders[:limit]
+			page.HasMore = true
+		}
+
+		if len(orders) > 0 {
+			o := orders[len(orders)-1]
+			last = &types.ExportCheckpoint{UpdatedAt: o.UpdatedAt, ID: o.ID}
+		}
+
+		page.Items = orders
+		page.Count = len(orders)
+	}
+
+	page.Checkpoint = last.Encode()
+	return page, nil
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	ether "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/contracts/contractsinterfaces"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/relayer"
+	"github.com/tomochain/tomox-sdk/secrets"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils"
+)
+
+// defaultFaucetInterval is the default delay between two requests of an address to the faucet
+const defaultFaucetInterval = 24 * time.Hour
+
+// nativeTransferGas is the gas of a transfer of TOMO
+const nativeTransferGas = 21000
+
+// FaucetService sends test TOMO and test tokens from the account of the faucet to the addresses of the
+// integration developers on the test networks. An address is funded once per interval, the time of its
+// last request is kept in memory
+type FaucetService struct {
+	provider  interfaces.EthereumProvider
+	nonces    interfaces.NonceManagerService
+	signer    *relayer.Signer
+	transfers []*types.FaucetTransfer
+	interval  time.Duration
+	tokenAbi  abi.ABI
+	requests  map[common.Address]time.Time
+	mutex     sync.Mutex
+}
+
+// NewFaucetService returns a new instance of FaucetService sending the transfers from the default account
+// of the signer through the nonce manager, a disabled faucet without signer
+func NewFaucetService(
+	provider interfaces.EthereumProvider,
+	nonces interfaces.NonceManagerService,
+	signer *relayer.Signer,
+	transfers []*types.FaucetTransfer,
+	interval time.Duration,
+) *FaucetService {
+	if interval <= 0 {
+		interval = defaultFaucetInterval
+	}
+
+	tokenAbi, err := abi.JSON(strings.NewReader(contractsinterfaces.TokenABI))
+	if err != nil {
+		panic(err)
+	}
+
+	if signer != nil {
+		nonces.Register(signer)
+	}
+
+	return &FaucetService{
+		provider:  provider,
+		nonces:    nonces,
+		signer:    signer,
+		transfers: transfers,
+		interval:  interval,
+		tokenAbi:  tokenAbi,
+		requests:  make(map[common.Address]time.Time),
+	}
+}
+
+// NewFaucetServiceFromConfig returns the FaucetService of the faucet settings. The faucet is disabled without
+// keystore, its key is decrypted with the faucet_passphrase secret or the passphrase of the settings
+func NewFaucetServiceFromConfig(
+	c map[string]string,
+	provider interfaces.EthereumProvider,
+	nonces interfaces.NonceManagerService,
+) (*FaucetService, error) {
+	if c["keystore"] == "" {
+		return NewFaucetService(provider, nonces, nil, nil, 0), nil
+	}
+
+	transfers, err := types.ParseFaucetTokens(c["tokens"])
+	if err != nil {
+		return nil, err
+	}
+
+	passphrase, err := secrets.Get(secrets.FaucetPassphrase)
+	if err != nil {
+		passphrase = c["passphrase"]
+	}
+
+	backend, err := relayer.NewKeystoreBackend(c["keystore"], passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("faucet keystore: %s", err)
+	}
+
+	var chainID *big.Int
+	if c["chain_id"] != "" {
+		id, ok := new(big.Int).SetString(c["chain_id"], 10)
+		if !ok || id.Sign() <= 0 {
+			return nil, fmt.Errorf("Invalid faucet chain ID %s", c["chain_id"])
+		}
+
+		chainID = id
+	}
+
+	signer, err := relayer.NewSignerWithBackends([]relayer.SignerBackend{backend}, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	interval, _ := strconv.Atoi(c["interval"])
+	return NewFaucetService(provider, nonces, signer, transfers, time.Duration(interval)*time.Second), nil
+}
+
+// Enabled returns true if the faucet has an account to send the transfers from
+func (s *FaucetService) Enabled() bool {
+	return s != nil && s.signer != nil
+}
+
+// Request sends the transfers of the faucet to an address. ErrFaucetTooSoon is returned with the time of
+// the next request if the address was funded within the interval, ErrFaucetFailed if no transfer was
+// sent
+func (s *FaucetService) Request(addr common.Address) (*types.FaucetRequest, error) {
+	if !s.Enabled() {
+		return nil, errors.New("Faucet disabled")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	if last, ok := s.requests[addr]; ok && now.Sub(last) < s.interval {
+		return &types.FaucetRequest{
+			Address:       addr,
+			Transfers:     []*types.FaucetTransfer{},
+			NextRequestAt: last.Add(s.interval),
+		}, ErrFaucetTooSoon
+	}
+
+	from := s.signer.GetAddress()
+	res := &types.FaucetRequest{Address: addr, Transfers: []*types.FaucetTransfer{}}
+	sent := 0
+	for _, t := range s.transfers {
+		transfer := &types.FaucetTransfer{Token: t.Token, Amount: t.Amount}
+		tx, err := s.send(from, addr, t)
+		if err != nil {
+			logger.Error("Faucet transfer failed:", t.Token.Hex(), addr.Hex(), err)
+			transfer.Error = err.Error()
+		} else {
+			transfer.TxHash = tx.Hash()
+			sent++
+		}
+
+		res.Transfers = append(res.Transfers, transfer)
+	}
+
+	if sent == 0 {
+		return res, ErrFaucetFailed
+	}
+
+	s.requests[addr] = now
+	res.NextRequestAt = now.Add(s.interval)
+	s.prune(now)
+	return res, nil
+}
+
+// send sends the transaction of a transfer, a call to transfer of the token contract unless it is TOMO
+func (s *FaucetService) send(from common.Address, to common.Address, t *types.FaucetTransfer) (*types.PendingTx, error) {
+	if utils.IsNativeTokenByAddress(t.Token) {
+		return s.nonces.Send(from, &to, t.Amount, nativeTransferGas, nil)
+	}
+
+	data, err := s.tokenAbi.Pack("transfer", to, t.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	gas, err := s.provider.EstimateGas(ether.CallMsg{From: from, To: &t.Token, Data: data})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.nonces.Send(from, &t.Token, big.NewInt(0), gas, data)
+}
+
+// prune forgets the addresses which can request again
+func (s *FaucetService) prune(now time.Time) {
+	for addr, last := range s.requests {
+		if now.Sub(last) >= s.interval {
+			delete(s.requests, addr)
+		}
+	}
+}
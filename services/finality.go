@@ -0,0 +1,168 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	eth "github.com/ethereum/go-ethereum/core/types"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/errors"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// FinalityService tracks the confirmations of settled records and
+// marks them as final once the configured confirmation depth is reached.
+// The confirmations are counted from the block of the receipt of the settlement transaction,
+// a record stays pending without confirmations until its transaction is mined
+type FinalityService struct {
+	finalityDao  interfaces.FinalityDao
+	blockService interfaces.BlockService
+	provider     interfaces.EthereumProvider
+	policy       types.FinalityPolicy
+	callbacks    []func(*types.FinalityRecord)
+	mutex        sync.RWMutex
+}
+
+// NewFinalityService returns a new instance of FinalityService
+func NewFinalityService(
+	finalityDao interfaces.FinalityDao,
+	blockService interfaces.BlockService,
+	provider interfaces.EthereumProvider,
+) *FinalityService {
+	s := &FinalityService{
+		finalityDao:  finalityDao,
+		blockService: blockService,
+		provider:     provider,
+		policy:       types.FinalityPolicy(app.Config.Confirmations),
+	}
+
+	blockService.RegisterNotify(s.HandleNewBlock)
+	return s
+}
+
+// RegisterNotify registers a function called when a record becomes final
+func (s *FinalityService) RegisterNotify(fn func(*types.FinalityRecord)) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.callbacks = append(s.callbacks, fn)
+}
+
+// Track starts following the confirmations of a record settled by a transaction. The transaction of a
+// record tracked before without it is set
+func (s *FinalityService) Track(action string, hash common.Hash, txHash common.Hash) error {
+	latest := s.blockService.GetChainStatus().LatestBlock
+	if latest == nil {
+		return errors.New("Chain head is unknown")
+	}
+
+	existing, err := s.finalityDao.GetByHash(hash)
+	if err != nil {
+		return err
+	}
+
+	if existing != nil {
+		if (existing.TxHash != common.Hash{} || txHash == common.Hash{}) {
+			return nil
+		}
+
+		existing.TxHash = txHash
+		s.include(existing, latest.Number)
+		return s.finalityDao.UpdateBlock(existing)
+	}
+
+	r := types.NewFinalityRecord(action, hash, txHash, s.policy.RequiredConfirmations(action))
+	finalized := s.include(r, latest.Number)
+
+	err = s.finalityDao.Create(r)
+	if err != nil {
+		return err
+	}
+
+	if finalized {
+		s.notify(r)
+	}
+
+	return nil
+}
+
+// TrackDeposit starts following the confirmations of a deposit of the deposit queue, its TransactionID is
+// the hash of the transaction crediting it
+func (s *FinalityService) TrackDeposit(d *types.DepositTransaction) error {
+	if len(common.FromHex(d.TransactionID)) != common.HashLength {
+		return fmt.Errorf("Invalid deposit transaction %s", d.TransactionID)
+	}
+
+	h := common.HexToHash(d.TransactionID)
+	return s.Track(types.FinalityActionDeposit, h, h)
+}
+
+// GetByHash returns the finality record of a trade or a deposit
+func (s *FinalityService) GetByHash(hash common.Hash) (*types.FinalityRecord, error) {
+	return s.finalityDao.GetByHash(hash)
+}
+
+// HandleNewBlock looks for the receipts of the records not mined yet and upgrades the confirmations of
+// the other pending records
+func (s *FinalityService) HandleNewBlock(block *types.BlockHeader) {
+	records, err := s.finalityDao.GetPending()
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	for _, r := range records {
+		var finalized bool
+		if r.IsIncluded() {
+			finalized = r.Confirm(block.Number)
+			err = s.finalityDao.UpdateConfirmations(r)
+		} else {
+			finalized = s.include(r, block.Number)
+			if !r.IsIncluded() {
+				continue
+			}
+
+			err = s.finalityDao.UpdateBlock(r)
+		}
+
+		if err != nil {
+			logger.Error(err)
+			continue
+		}
+
+		if finalized {
+			s.notify(r)
+		}
+	}
+}
+
+// include sets the block of the record from the receipt of its settlement transaction, a reverted
+// transaction leaves it pending. It returns true if the record is final at the head
+func (s *FinalityService) include(r *types.FinalityRecord, head uint64) bool {
+	if (r.TxHash == common.Hash{}) {
+		return false
+	}
+
+	receipt, block, err := s.provider.TransactionReceiptBlock(r.TxHash)
+	if err != nil {
+		logger.Warning("Receipt of", r.TxHash.Hex(), "not read:", err)
+		return false
+	}
+
+	if receipt == nil || receipt.Status != eth.ReceiptStatusSuccessful {
+		return false
+	}
+
+	return r.Include(block, head)
+}
+
+func (s *FinalityService) notify(r *types.FinalityRecord) {
+	s.mutex.RLock()
+	callbacks := s.callbacks
+	s.mutex.RUnlock()
+
+	for _, fn := range callbacks {
+		fn(r)
+	}
+}
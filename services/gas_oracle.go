@@ -0,0 +1,149 @@
+package services
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/math"
+)
+
+// defaultGasOracleBlocks is the default number of recent blocks sampled by the gas oracle
+const defaultGasOracleBlocks = 20
+
+// GasOracleService suggests the gas prices of the transactions sent by the SDK from the prices paid in the
+// recent blocks. A block is sampled when it becomes the head, the prices are the percentiles of the prices
+// of the sampled transactions (safe, standard and fast). The price suggested by the node is used until a
+// block with paid transactions is sampled
+type GasOracleService struct {
+	provider    interfaces.EthereumProvider
+	blocks      uint64
+	percentiles [3]int
+	floor       *big.Int
+	samples     map[uint64]*types.GasSample
+	prices      *types.GasPrices
+	mutex       sync.RWMutex
+}
+
+// NewGasOracleService returns a new instance of GasOracleService sampling the heads of the block service
+func NewGasOracleService(blockService interfaces.BlockService, provider interfaces.EthereumProvider) *GasOracleService {
+	blocks := app.Config.GasOracle["blocks"]
+	if blocks <= 0 {
+		blocks = defaultGasOracleBlocks
+	}
+
+	percentiles := [3]int{
+		types.DefaultGasSafePercentile,
+		types.DefaultGasStandardPercentile,
+		types.DefaultGasFastPercentile,
+	}
+
+	for i, k := range []string{types.GasSpeedSafe, types.GasSpeedStandard, types.GasSpeedFast} {
+		if p := app.Config.GasOracle[k]; p > 0 && p <= 100 {
+			percentiles[i] = p
+		}
+	}
+
+	s := &GasOracleService{
+		provider:    provider,
+		blocks:      uint64(blocks),
+		percentiles: percentiles,
+		floor:       big.NewInt(int64(app.Config.GasOracle["min_price"])),
+		samples:     make(map[uint64]*types.GasSample),
+	}
+
+	blockService.RegisterNotify(s.HandleNewBlock)
+	return s
+}
+
+// HandleNewBlock samples a new head, with the blocks below it when the oracle starts. The samples of the
+// blocks above it, replaced by a reorganization, and of the blocks out of the window are dropped
+func (s *GasOracleService) HandleNewBlock(block *types.BlockHeader) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	from := block.Number
+	if len(s.samples) == 0 {
+		from = 0
+		if block.Number >= s.blocks {
+			from = block.Number - s.blocks + 1
+		}
+	}
+
+	for n := from; n <= block.Number; n++ {
+		sample, err := s.sample(n)
+		if err != nil {
+			logger.Warning("Gas prices of block not sampled:", n, err)
+			continue
+		}
+
+		s.samples[n] = sample
+	}
+
+	samples := []*types.GasSample{}
+	for n, sample := range s.samples {
+		if n > block.Number || n+s.blocks <= block.Number {
+			delete(s.samples, n)
+			continue
+		}
+
+		if len(sample.Prices) > 0 {
+			samples = append(samples, sample)
+		}
+	}
+
+	if len(samples) == 0 {
+		return
+	}
+
+	s.prices = types.NewGasPrices(samples, s.percentiles, s.floor)
+}
+
+// sample returns the gas prices paid by the transactions of a block, the free system transactions of the
+// masternodes excluded
+func (s *GasOracleService) sample(number uint64) (*types.GasSample, error) {
+	b, err := s.provider.BlockByNumber(new(big.Int).SetUint64(number))
+	if err != nil {
+		return nil, err
+	}
+
+	sample := &types.GasSample{BlockNumber: number, Prices: []*big.Int{}}
+	for _, tx := range b.Transactions() {
+		if tx.GasPrice().Sign() > 0 {
+			sample.Prices = append(sample.Prices, tx.GasPrice())
+		}
+	}
+
+	return sample, nil
+}
+
+// GetGasPrices returns the suggested gas prices, from the price suggested by the node until a block with
+// paid transactions is sampled
+func (s *GasOracleService) GetGasPrices() (*types.GasPrices, error) {
+	s.mutex.RLock()
+	prices := s.prices
+	s.mutex.RUnlock()
+
+	if prices != nil {
+		return prices, nil
+	}
+
+	price, err := s.provider.SuggestGasPrice()
+	if err != nil {
+		return nil, err
+	}
+
+	return types.NewGasPrices(nil, s.percentiles, math.Max(s.floor, price)), nil
+}
+
+// GasPrice returns the suggested gas price of a speed, standard if empty
+func (s *GasOracleService) GasPrice(speed string) (*big.Int, error) {
+	prices, err := s.GetGasPrices()
+	if err != nil {
+		return nil, err
+	}
+
+	return prices.Price(speed)
+}
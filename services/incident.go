@@ -0,0 +1,133 @@
+package services
+
+import (
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/ws"
+)
+
+// resolvedIncidentsWindow is how long a resolved incident stays on the status page
+const resolvedIncidentsWindow = 7 * 24 * time.Hour
+
+// IncidentService manages the incidents posted by the operators. The status page is served at /status
+// and every created or updated incident is streamed on the system_status channel with the new status
+type IncidentService struct {
+	incidentDao interfaces.IncidentDao
+}
+
+// NewIncidentService returns a new instance of IncidentService
+func NewIncidentService(incidentDao interfaces.IncidentDao) *IncidentService {
+	return &IncidentService{incidentDao}
+}
+
+// GetStatus returns the status of the system with the active incidents and the ones resolved last week
+func (s *IncidentService) GetStatus() (*types.SystemStatus, error) {
+	now := time.Now()
+
+	active, err := s.incidentDao.GetActive()
+	if err != nil {
+		return nil, err
+	}
+
+	resolved, err := s.incidentDao.GetResolvedSince(now.Add(-resolvedIncidentsWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	return types.NewSystemStatus(active, resolved, now), nil
+}
+
+// GetAll returns a page of the incidents, newest first
+func (s *IncidentService) GetAll(offset, limit int) ([]*types.Incident, error) {
+	return s.incidentDao.GetAll(offset, limit)
+}
+
+// Create opens an incident and broadcasts it
+func (s *IncidentService) Create(p *types.IncidentPayload, identity string) (*types.Incident, error) {
+	i, err := types.NewIncident(p, identity, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.incidentDao.Create(i)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("Incident %s (%s) opened by %s", i.ID.Hex(), i.Title, identity)
+	s.broadcast(i)
+	return i, nil
+}
+
+// Update changes an incident, posts the message of the payload and broadcasts it.
+// A resolved incident can not be updated
+func (s *IncidentService) Update(id bson.ObjectId, p *types.IncidentPayload, identity string) (*types.Incident, error) {
+	err := p.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	i, err := s.incidentDao.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if i == nil {
+		return nil, ErrIncidentNotFound
+	}
+
+	if i.IsResolved() {
+		return nil, ErrIncidentResolved
+	}
+
+	i.Apply(p, identity, time.Now())
+	err = s.incidentDao.Update(i)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("Incident %s %s by %s", i.ID.Hex(), i.Status, identity)
+	s.broadcast(i)
+	return i, nil
+}
+
+// broadcast sends the incident with the new status of the system to the subscribers of the system_status channel
+func (s *IncidentService) broadcast(i *types.Incident) {
+	status, err := s.GetStatus()
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	ws.GetSystemStatusSocket().BroadcastMessage(ws.SystemStatusChannel, &types.SystemStatusEvent{Incident: i, Status: status})
+}
+
+// Subscribe sends the status of the system and registers the client for the incidents updates
+func (s *IncidentService) Subscribe(c *ws.Client) {
+	socket := ws.GetSystemStatusSocket()
+
+	status, err := s.GetStatus()
+	if err != nil {
+		logger.Error(err)
+		socket.SendErrorMessage(c, err.Error())
+		return
+	}
+
+	err = socket.Subscribe(ws.SystemStatusChannel, c)
+	if err != nil {
+		logger.Error(err)
+		socket.SendErrorMessage(c, err.Error())
+		return
+	}
+
+	ws.RegisterConnectionUnsubscribeHandler(c, socket.UnsubscribeChannelHandler(ws.SystemStatusChannel))
+	socket.SendInitMessage(c, status)
+}
+
+// Unsubscribe removes the client from the system_status channel
+func (s *IncidentService) Unsubscribe(c *ws.Client) {
+	ws.GetSystemStatusSocket().Unsubscribe(c)
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// Buckets of the OHLCV volumes cross-checked with the trades
+const (
+	integrityTickDuration = 1
+	integrityTickUnit     = "hour"
+)
+
+// IntegrityService cross-checks the invariants between the orders, the trades and the OHLCV ticks of a
+// period: the filled orders have trades for their amount, the volume of the hourly ticks is the sum of
+// the trades of their bucket and the trades have their maker and taker orders. Every run is recorded,
+// the integrity_recipients are notified of the runs finding violations
+type IntegrityService struct {
+	orderDao            interfaces.OrderDao
+	tradeDao            interfaces.TradeDao
+	pairDao             interfaces.PairDao
+	ohlcvService        interfaces.OHLCVService
+	integrityReportDao  interfaces.IntegrityReportDao
+	notificationService interfaces.NotificationService
+}
+
+// NewIntegrityService returns a new instance of IntegrityService
+func NewIntegrityService(
+	orderDao interfaces.OrderDao,
+	tradeDao interfaces.TradeDao,
+	pairDao interfaces.PairDao,
+	ohlcvService interfaces.OHLCVService,
+	integrityReportDao interfaces.IntegrityReportDao,
+	notificationService interfaces.NotificationService,
+) *IntegrityService {
+	return &IntegrityService{
+		orderDao:            orderDao,
+		tradeDao:            tradeDao,
+		pairDao:             pairDao,
+		ohlcvService:        ohlcvService,
+		integrityReportDao:  integrityReportDao,
+		notificationService: notificationService,
+	}
+}
+
+// GetAll returns a page of the reports, newest first
+func (s *IntegrityService) GetAll(offset, limit int) ([]*types.IntegrityReport, error) {
+	return s.integrityReportDao.GetAll(offset, limit)
+}
+
+// CheckAll checks the records of the previous day (UTC)
+func (s *IntegrityService) CheckAll() error {
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	_, err := s.Check(to.AddDate(0, 0, -1), to)
+	return err
+}
+
+// Check runs the checks on the records of [from, to), records the report and notifies the admins
+// if violations are found
+func (s *IntegrityService) Check(from, to time.Time) (*types.IntegrityReport, error) {
+	r := types.NewIntegrityReport(from, to)
+
+	filled, err := s.orderDao.GetByStatusAndPeriod(types.OrderStatusFilled, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.checkFilledOrders(r, filled)
+	if err != nil {
+		return nil, err
+	}
+
+	trades, err := s.tradeDao.GetTradeByTime(from.Unix(), to.Unix(), 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.checkOrphanedTrades(r, trades)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.checkOHLCVVolumes(r, trades, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.integrityReportDao.Create(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.HasViolations() {
+		logger.Warning(r.Summary())
+		s.notify(r)
+	}
+
+	return r, nil
+}
+
+func (s *IntegrityService) checkFilledOrders(r *types.IntegrityReport, orders []*types.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	hashes := []common.Hash{}
+	for _, o := range orders {
+		hashes = append(hashes, o.Hash)
+	}
+
+	trades, err := s.tradeDao.GetByMakerOrTakerOrderHashes(hashes)
+	if err != nil {
+		return err
+	}
+
+	r.Add(types.IntegrityCheckFilledOrders, len(orders), types.CheckFilledOrders(orders, trades))
+	return nil
+}
+
+func (s *IntegrityService) checkOrphanedTrades(r *types.IntegrityReport, trades []*types.Trade) error {
+	if len(trades) == 0 {
+		return nil
+	}
+
+	hashes := []common.Hash{}
+	for _, t := range trades {
+		hashes = append(hashes, t.MakerOrderHash, t.TakerOrderHash)
+	}
+
+	orders, err := s.orderDao.GetByHashes(hashes)
+	if err != nil {
+		return err
+	}
+
+	r.Add(types.IntegrityCheckOrphanedTrades, len(trades), types.CheckOrphanedTrades(trades, orders))
+	return nil
+}
+
+func (s *IntegrityService) checkOHLCVVolumes(r *types.IntegrityReport, trades []*types.Trade, from, to time.Time) error {
+	pairs, err := s.pairDao.GetActivePairs()
+	if err != nil {
+		return err
+	}
+
+	byPair := make(map[string][]*types.Trade)
+	for _, t := range trades {
+		key := t.BaseToken.Hex() + t.QuoteToken.Hex()
+		byPair[key] = append(byPair[key], t)
+	}
+
+	for _, p := range pairs {
+		addresses := []types.PairAddresses{{BaseToken: p.BaseTokenAddress, QuoteToken: p.QuoteTokenAddress}}
+		ticks, err := s.ohlcvService.GetOHLCV(addresses, integrityTickDuration, integrityTickUnit, from.Unix(), to.Unix()-1)
+		if err != nil {
+			return err
+		}
+
+		pairTrades := byPair[p.BaseTokenAddress.Hex()+p.QuoteTokenAddress.Hex()]
+		violations := types.CheckOHLCVVolumes(p.Name(), ticks, pairTrades, integrityTickDuration, integrityTickUnit)
+		r.Add(types.IntegrityCheckOHLCVVolume, len(ticks), violations)
+	}
+
+	return nil
+}
+
+func (s *IntegrityService) notify(r *types.IntegrityReport) {
+	for _, a := range app.Config.IntegrityRecipients {
+		err := s.notificationService.Notify(&types.Notification{
+			Recipient: common.HexToAddress(a),
+			Message: types.Message{
+				MessageType: types.IntegrityMessage,
+				Description: r.Summary(),
+			},
+			Type:   types.TypeAlert,
+			Status: types.StatusUnread,
+		})
+
+		if err != nil {
+			logger.Error(err)
+		}
+	}
+}
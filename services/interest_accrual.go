@@ -0,0 +1,89 @@
+package services
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// maxIncomeDays limits the period of the interest income reports
+const maxIncomeDays = 366
+
+// InterestAccrualService computes the interest accrued by the open lending trades and stores their daily accruals
+type InterestAccrualService struct {
+	lendingTradeDao interfaces.LendingTradeDao
+	accrualDao      interfaces.InterestAccrualDao
+}
+
+// NewInterestAccrualService returns a new instance of InterestAccrualService
+func NewInterestAccrualService(
+	lendingTradeDao interfaces.LendingTradeDao,
+	accrualDao interfaces.InterestAccrualDao,
+) *InterestAccrualService {
+	return &InterestAccrualService{
+		lendingTradeDao: lendingTradeDao,
+		accrualDao:      accrualDao,
+	}
+}
+
+// GetAccrued returns the interest accrued by a lending trade to date
+func (s *InterestAccrualService) GetAccrued(hash common.Hash) (*types.InterestAccrual, error) {
+	t, err := s.lendingTradeDao.GetByHash(hash)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if t == nil {
+		return nil, ErrLendingTradeNotFound
+	}
+
+	return types.CurrentInterestAccrual(t, time.Now()), nil
+}
+
+// GetAccruals returns the stored daily accruals of a lending trade
+func (s *InterestAccrualService) GetAccruals(hash common.Hash) ([]*types.InterestAccrual, error) {
+	return s.accrualDao.GetByTradeHash(hash)
+}
+
+// GetIncome returns the daily accruals of the lends of an investor between two days included,
+// with the total income per lending token
+func (s *InterestAccrualService) GetIncome(addr common.Address, from, to time.Time) (*types.InterestIncome, error) {
+	if to.Before(from) || to.Sub(from) > maxIncomeDays*24*time.Hour {
+		return nil, ErrInvalidIncomePeriod
+	}
+
+	f := from.UTC().Format(types.AccrualDateLayout)
+	t := to.UTC().Format(types.AccrualDateLayout)
+
+	accruals, err := s.accrualDao.GetByInvestor(addr, f, t)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return types.NewInterestIncome(addr, f, t, accruals), nil
+}
+
+// Snapshot stores the accruals of the open lending trades for the UTC day of a time.
+// It is idempotent, the accruals of a day are replaced when it runs again
+func (s *InterestAccrualService) Snapshot(day time.Time) error {
+	trades, err := s.lendingTradeDao.GetOpen()
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	for _, t := range trades {
+		err := s.accrualDao.Save(types.NewInterestAccrual(t, day))
+		if err != nil {
+			logger.Error(err)
+			return err
+		}
+	}
+
+	logger.Infof("Interest accruals of %d lending trades saved for %s", len(trades), day.UTC().Format(types.AccrualDateLayout))
+	return nil
+}
@@ -0,0 +1,275 @@
+package services
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/rabbitmq"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/ws"
+)
+
+// KillSwitchService halts the order intake of all the pairs or of one pair in one action: the pairs
+// are paused in the engine with the reason of the kill switch, their resting orders cancelled if requested
+// and the halt is broadcast on the system_status channel. A global kill switch also halts the pairs created
+// while it is active. Every activation is recorded with its operator, the engine pauses are restored from
+// the active records at startup
+type KillSwitchService struct {
+	killSwitchDao interfaces.KillSwitchDao
+	pairDao       interfaces.PairDao
+	orderDao      interfaces.OrderDao
+	engine        interfaces.Engine
+	broker        *rabbitmq.Connection
+	signers       map[common.Address]bool
+	executed      map[common.Hash]time.Time
+	mutex         sync.Mutex
+}
+
+// NewKillSwitchService returns a new instance of KillSwitchService accepting the signed requests of the signers
+func NewKillSwitchService(
+	killSwitchDao interfaces.KillSwitchDao,
+	pairDao interfaces.PairDao,
+	orderDao interfaces.OrderDao,
+	engine interfaces.Engine,
+	broker *rabbitmq.Connection,
+	signers []string,
+) *KillSwitchService {
+	s := &KillSwitchService{
+		killSwitchDao: killSwitchDao,
+		pairDao:       pairDao,
+		orderDao:      orderDao,
+		engine:        engine,
+		broker:        broker,
+		signers:       make(map[common.Address]bool),
+		executed:      make(map[common.Hash]time.Time),
+	}
+
+	for _, a := range signers {
+		s.signers[common.HexToAddress(a)] = true
+	}
+
+	return s
+}
+
+// GetAll returns a page of the kill switches, newest first
+func (s *KillSwitchService) GetAll(offset, limit int) ([]*types.KillSwitch, error) {
+	return s.killSwitchDao.GetAll(offset, limit)
+}
+
+// Activate records the activation, pauses the pairs of the payload, cancels their resting orders if
+// requested and broadcasts the halt
+func (s *KillSwitchService) Activate(p *types.KillSwitchPayload, identity string) (*types.KillSwitch, error) {
+	err := p.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	pairs, err := s.getPairs(p)
+	if err != nil {
+		return nil, err
+	}
+
+	k := &types.KillSwitch{
+		Scope:        types.KillSwitchScopeGlobal,
+		Pairs:        []string{},
+		PairCodes:    []string{},
+		CancelOrders: p.CancelOrders,
+		Reason:       p.Reason,
+		Status:       types.KillSwitchStatusActive,
+		ActivatedBy:  identity,
+	}
+
+	if !p.IsGlobal() {
+		k.Scope = types.KillSwitchScopePair
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, pair := range pairs {
+		k.Pairs = append(k.Pairs, pair.Name())
+		k.PairCodes = append(k.PairCodes, pair.Code())
+	}
+
+	// the kill switch is recorded before the pairs are paused so that every halt can be released
+	err = s.killSwitchDao.Create(k)
+	if err != nil {
+		return nil, err
+	}
+
+	s.pause(k)
+	logger.Warningf("Kill switch activated by %s on %s: %s", identity, strings.Join(k.Pairs, ","), k.Reason)
+
+	if p.CancelOrders {
+		k.CancelledOrders = s.cancelOrders(k.PairCodes)
+
+		err = s.killSwitchDao.Update(k)
+		if err != nil {
+			logger.Error(err)
+		}
+	}
+
+	s.broadcast(k)
+	return k, nil
+}
+
+// Release lifts the pause of the pairs of an active kill switch and broadcasts it, the pairs stay paused
+// while another kill switch or pause source holds them
+func (s *KillSwitchService) Release(id bson.ObjectId, identity string) (*types.KillSwitch, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	k, err := s.killSwitchDao.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if k == nil {
+		return nil, ErrKillSwitchNotFound
+	}
+
+	if !k.IsActive() {
+		return nil, ErrKillSwitchReleased
+	}
+
+	now := time.Now()
+	k.Status = types.KillSwitchStatusReleased
+	k.ReleasedBy = identity
+	k.ReleasedAt = &now
+
+	err = s.killSwitchDao.Update(k)
+	if err != nil {
+		return nil, err
+	}
+
+	reason := types.KillSwitchPauseReason(k.ID)
+	if k.Scope == types.KillSwitchScopeGlobal {
+		s.engine.ResumeAllPairs(reason)
+	}
+
+	for _, code := range k.PairCodes {
+		s.engine.ResumePair(code, reason)
+	}
+
+	logger.Warningf("Kill switch %s released by %s", k.ID.Hex(), identity)
+	s.broadcast(k)
+	return k, nil
+}
+
+// VerifySigned checks a signed request comes from one of the signers and was not executed yet,
+// and returns the identity of its signer
+func (s *KillSwitchService) VerifySigned(req *types.SignedKillSwitchRequest) (string, error) {
+	now := time.Now()
+
+	signer, err := req.Verify(now)
+	if err != nil {
+		return "", err
+	}
+
+	if !s.signers[signer] {
+		return "", ErrKillSwitchSignerUnknown
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for h, t := range s.executed {
+		if now.Sub(t) > 2*types.KillSwitchRequestWindow {
+			delete(s.executed, h)
+		}
+	}
+
+	if _, ok := s.executed[req.Hash]; ok {
+		return "", ErrKillSwitchReplayed
+	}
+
+	s.executed[req.Hash] = now
+	return "signer:" + signer.Hex(), nil
+}
+
+// Restore pauses again the pairs of the active kill switches, the pauses of the engine are not persisted
+func (s *KillSwitchService) Restore() error {
+	active, err := s.killSwitchDao.GetActive()
+	if err != nil {
+		return err
+	}
+
+	for _, k := range active {
+		s.pause(k)
+		logger.Warningf("Kill switch %s still active on %s", k.ID.Hex(), strings.Join(k.Pairs, ","))
+	}
+
+	return nil
+}
+
+// pause pauses the pairs of a kill switch with its own reason, a global kill switch also pauses the pairs
+// created while it is active
+func (s *KillSwitchService) pause(k *types.KillSwitch) {
+	reason := types.KillSwitchPauseReason(k.ID)
+	if k.Scope == types.KillSwitchScopeGlobal {
+		s.engine.PauseAllPairs(reason)
+	}
+
+	for _, code := range k.PairCodes {
+		s.engine.PausePair(code, reason)
+	}
+}
+
+// getPairs returns the pair of the payload, or all the active pairs for a global kill switch
+func (s *KillSwitchService) getPairs(p *types.KillSwitchPayload) ([]*types.Pair, error) {
+	if p.IsGlobal() {
+		return s.pairDao.GetActivePairs()
+	}
+
+	pair, err := s.pairDao.GetByTokenAddress(p.BaseToken, p.QuoteToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if pair == nil {
+		return nil, ErrPairNotFound
+	}
+
+	return []*types.Pair{pair}, nil
+}
+
+// cancelOrders sends the cancellation of the open orders of the pairs to the engine,
+// and returns the number of orders cancelled
+func (s *KillSwitchService) cancelOrders(codes []string) int {
+	halted := make(map[string]bool)
+	for _, code := range codes {
+		halted[code] = true
+	}
+
+	orders, err := s.orderDao.GetOpenOrders()
+	if err != nil {
+		logger.Error(err)
+		return 0
+	}
+
+	count := 0
+	for _, o := range orders {
+		if !halted[o.BaseToken.Hex()+"::"+o.QuoteToken.Hex()] {
+			continue
+		}
+
+		err = s.broker.PublishCancelOrderMessage(o)
+		if err != nil {
+			logger.Error(err)
+			continue
+		}
+
+		count++
+	}
+
+	return count
+}
+
+// broadcast sends the kill switch to the subscribers of the system_status channel
+func (s *KillSwitchService) broadcast(k *types.KillSwitch) {
+	ws.GetSystemStatusSocket().BroadcastMessage(ws.SystemStatusChannel, &types.KillSwitchEvent{KillSwitch: k, Halted: k.IsActive()})
+}
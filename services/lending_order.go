@@ -3,18 +3,19 @@ package services
 import (
 	"context"
 	"encoding/json"
-	"math"
 	"math/big"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/tomochain/tomox-sdk/errors"
+	"github.com/tomochain/tomox-sdk/app"
 	"github.com/tomochain/tomox-sdk/interfaces"
 	"github.com/tomochain/tomox-sdk/rabbitmq"
 	"github.com/tomochain/tomox-sdk/types"
 	"github.com/tomochain/tomox-sdk/utils"
+	"github.com/tomochain/tomox-sdk/utils/math"
 	"github.com/tomochain/tomox-sdk/ws"
 )
 
@@ -25,6 +26,9 @@ const (
 	RECALL_EVENT  = "RECALL"
 )
 
+// number of recent lending trades the interest collar is computed from
+const lendingCollarTrades = 20
+
 // LendingOrderService struct
 type LendingOrderService struct {
 	lendingDao         interfaces.LendingOrderDao
@@ -38,6 +42,7 @@ type LendingOrderService struct {
 	validator          interfaces.ValidatorService
 	engine             interfaces.Engine
 	broker             *rabbitmq.Connection
+	compliance         interfaces.ComplianceGate
 	mutext             sync.RWMutex
 	bulkLendingOrders  map[string]map[common.Hash]*types.LendingOrder
 }
@@ -55,6 +60,7 @@ func NewLendingOrderService(
 	validator interfaces.ValidatorService,
 	engine interfaces.Engine,
 	broker *rabbitmq.Connection,
+	compliance interfaces.ComplianceGate,
 ) *LendingOrderService {
 	bulkLendingOrders := make(map[string]map[common.Hash]*types.LendingOrder)
 	return &LendingOrderService{
@@ -69,6 +75,7 @@ func NewLendingOrderService(
 		validator,
 		engine,
 		broker,
+		compliance,
 		sync.RWMutex{},
 		bulkLendingOrders,
 	}
@@ -95,10 +102,20 @@ func (s *LendingOrderService) NewLendingOrder(o *types.LendingOrder) error {
 	}
 
 	if !ok {
-		return errors.New("Invalid Signature")
+		return ErrInvalidSignature
+	}
+
+	err = s.compliance.CheckLendingOrder(o.UserAddress)
+	if err != nil {
+		return err
 	}
 
 	if o.Type == types.TypeLimitOrder {
+		err = s.checkInterestCollar(o)
+		if err != nil {
+			return err
+		}
+
 		err = s.validator.ValidateAvailablLendingBalance(o)
 		if err != nil {
 			logger.Error(err)
@@ -115,6 +132,40 @@ func (s *LendingOrderService) NewLendingOrder(o *types.LendingOrder) error {
 	return nil
 }
 
+// checkInterestCollar rejects the lending orders whose interest is outside the collar of their lending token and term.
+// The orders are accepted when no collar is configured or there is no recent trade to compute it from
+func (s *LendingOrderService) checkInterestCollar(o *types.LendingOrder) error {
+	band := lendingCollarBand(o.LendingToken, o.Term)
+	if band <= 0 {
+		return nil
+	}
+
+	trades, err := s.lendingTradeDao.GetLendingTradeByOrderBook(o.Term, o.LendingToken, 0, 0, lendingCollarTrades)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	c := types.NewInterestCollar(trades, band)
+	if c == nil || c.Contains(o.Interest) {
+		return nil
+	}
+
+	logger.Warningf("Lending order %s rejected, interest %d outside of [%d, %d]", o.Hash.Hex(), o.Interest, c.Min, c.Max)
+	return ErrInterestOutOfCollar
+}
+
+// lendingCollarBand returns the collar width in basis points of a lending token and term,
+// configured by "<lower cased lending token address>/<term>" or "default"
+func lendingCollarBand(lendingToken common.Address, term uint64) int {
+	key := strings.ToLower(lendingToken.Hex()) + "/" + strconv.FormatUint(term, 10)
+	if v, ok := app.Config.LendingCollars[key]; ok {
+		return v
+	}
+
+	return app.Config.LendingCollars["default"]
+}
+
 // CancelLendingOrder handles the cancellation order requests.
 // Only Orders which are OPEN or NEW i.e. Not yet filled/partially filled
 // can be cancelled
@@ -631,7 +682,7 @@ func (s *LendingOrderService) EstimateCollateral(collateralToken common.Address,
 		return nil, nil, err
 	}
 
-	lendingDecimals := big.NewInt(int64(math.Pow10(lendingTokenInfo.Decimals)))
+	lendingDecimals := math.Pow10(lendingTokenInfo.Decimals)
 	x := new(big.Float).Quo(new(big.Float).SetInt(collateralPrice), new(big.Float).SetInt(lendingDecimals))
 	a := new(big.Float).Mul(lendingAmount, new(big.Float).SetInt(lendingDecimals))
 	collateralAmount := new(big.Float).Quo(a, new(big.Float).SetInt(collateralPrice))
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// LendingPositionService aggregates the open loans of an account with their interest and collateral health
+type LendingPositionService struct {
+	lendingTradeDao    interfaces.LendingTradeDao
+	lendingOrderDao    interfaces.LendingOrderDao
+	collateralTokenDao interfaces.TokenDao
+	lendingTokenDao    interfaces.TokenDao
+}
+
+// NewLendingPositionService returns a new instance of LendingPositionService
+func NewLendingPositionService(
+	lendingTradeDao interfaces.LendingTradeDao,
+	lendingOrderDao interfaces.LendingOrderDao,
+	collateralTokenDao interfaces.TokenDao,
+	lendingTokenDao interfaces.TokenDao,
+) *LendingPositionService {
+	return &LendingPositionService{
+		lendingTradeDao:    lendingTradeDao,
+		lendingOrderDao:    lendingOrderDao,
+		collateralTokenDao: collateralTokenDao,
+		lendingTokenDao:    lendingTokenDao,
+	}
+}
+
+// GetPositions returns the open lends and borrows of an account
+func (s *LendingPositionService) GetPositions(addr common.Address) (*types.LendingPositions, error) {
+	trades, err := s.lendingTradeDao.GetOpenByUser(addr)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	res := &types.LendingPositions{
+		Address: addr,
+		Lends:   []*types.LendingPosition{},
+		Borrows: []*types.LendingPosition{},
+	}
+
+	now := time.Now()
+	prices := make(map[string]*big.Int)
+	for _, t := range trades {
+		key := t.CollateralToken.Hex() + t.LendingToken.Hex()
+		price, ok := prices[key]
+		if !ok {
+			price = s.getCollateralPrice(t.CollateralToken, t.LendingToken)
+			prices[key] = price
+		}
+
+		p := types.NewLendingPosition(t, addr, price, now)
+		if p.Role == types.LoanRoleBorrower {
+			res.Borrows = append(res.Borrows, p)
+		} else {
+			res.Lends = append(res.Lends, p)
+		}
+	}
+
+	return res, nil
+}
+
+// getCollateralPrice returns the last price of the collateral token in lending token, nil if it is unknown
+func (s *LendingPositionService) getCollateralPrice(collateralToken, lendingToken common.Address) *big.Int {
+	lt, err := s.lendingTokenDao.GetByAddress(lendingToken)
+	if err != nil || lt == nil {
+		logger.Warningf("Lending token %s not found", lendingToken.Hex())
+		return nil
+	}
+
+	ct, err := s.collateralTokenDao.GetByAddress(collateralToken)
+	if err != nil || ct == nil {
+		logger.Warningf("Collateral token %s not found", collateralToken.Hex())
+		return nil
+	}
+
+	price, err := s.lendingOrderDao.GetLastTokenPrice(collateralToken, lendingToken, ct.Decimals, lt.Decimals)
+	if err != nil {
+		logger.Error(err)
+		return nil
+	}
+
+	return price
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// Limits of the pages of the lending rate series
+const (
+	defaultLendingRatePageSize = 500
+	maxLendingRatePageSize     = 1000
+	maxLendingRateDays         = 366
+)
+
+// LendingRateService takes periodic snapshots of the utilization and the borrow rate of the lending
+// tokens, from the open loans and lend orders, and serves their time series downsampled to an interval
+type LendingRateService struct {
+	snapshotDao     interfaces.LendingRateSnapshotDao
+	lendingTradeDao interfaces.LendingTradeDao
+	lendingOrderDao interfaces.LendingOrderDao
+}
+
+// NewLendingRateService returns a new instance of LendingRateService
+func NewLendingRateService(
+	snapshotDao interfaces.LendingRateSnapshotDao,
+	lendingTradeDao interfaces.LendingTradeDao,
+	lendingOrderDao interfaces.LendingOrderDao,
+) *LendingRateService {
+	return &LendingRateService{
+		snapshotDao:     snapshotDao,
+		lendingTradeDao: lendingTradeDao,
+		lendingOrderDao: lendingOrderDao,
+	}
+}
+
+// Snapshot stores the utilization and the borrow rate of the lending tokens with open loans or lend orders
+func (s *LendingRateService) Snapshot() error {
+	loans, err := s.lendingTradeDao.GetOpen()
+	if err != nil {
+		return err
+	}
+
+	lends, err := s.lendingOrderDao.GetOpenBySide(types.LEND)
+	if err != nil {
+		return err
+	}
+
+	return s.snapshotDao.Create(types.NewLendingRateSnapshots(loans, lends, time.Now())...)
+}
+
+// GetSeries returns a page of the points of a lending token in [from, to), at most one year, downsampled
+// to the interval, pages of 500 points by default. From is moved to the start of its interval so that the
+// first point is a full one
+func (s *LendingRateService) GetSeries(token common.Address, from, to time.Time, interval string, page, size int) (*types.LendingRateSeries, error) {
+	seconds, ok := types.LendingRateIntervals[interval]
+	if !ok {
+		return nil, ErrInvalidLendingRateInterval
+	}
+
+	if !from.Before(to) || to.Sub(from) > maxLendingRateDays*24*time.Hour {
+		return nil, ErrInvalidReportPeriod
+	}
+
+	if seconds > 0 {
+		from = time.Unix(from.Unix()-from.Unix()%seconds, 0)
+	}
+
+	if size <= 0 {
+		size = defaultLendingRatePageSize
+	}
+
+	if size > maxLendingRatePageSize {
+		size = maxLendingRatePageSize
+	}
+
+	if page < 0 {
+		page = 0
+	}
+
+	snapshots, err := s.snapshotDao.GetByTokenAndPeriod(token, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	points := types.DownsampleLendingRates(snapshots, seconds)
+	res := &types.LendingRateSeries{
+		LendingToken: token,
+		Interval:     interval,
+		From:         from.Unix(),
+		To:           to.Unix(),
+		Total:        len(points),
+		Points:       []*types.LendingRatePoint{},
+	}
+
+	offset := page * size
+	if offset < len(points) {
+		end := offset + size
+		if end > len(points) {
+			end = len(points)
+		}
+
+		res.Points = points[offset:end]
+	}
+
+	return res, nil
+}
@@ -6,7 +6,9 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	eth "github.com/ethereum/go-ethereum/core/types"
 	"github.com/tomochain/tomox-sdk/errors"
+	"github.com/tomochain/tomox-sdk/ethereum"
 	"github.com/tomochain/tomox-sdk/interfaces"
 	"github.com/tomochain/tomox-sdk/rabbitmq"
 	"github.com/tomochain/tomox-sdk/types"
@@ -20,29 +22,43 @@ type LendingTradeService struct {
 	lendingDao          interfaces.LendingOrderDao
 	lendingTradeDao     interfaces.LendingTradeDao
 	notificationDao     interfaces.NotificationDao
+	finalityService     interfaces.FinalityService
+	provider            interfaces.EthereumProvider
 	broker              *rabbitmq.Connection
 	bulkLendingTrades   map[string][]*types.LendingTrade
 	mutext              sync.RWMutex
 	tradeNotifyCallback func(*types.LendingTrade)
+	liquidations        []*types.LendingLiquidation
 }
 
+// number of liquidations sent to the clients subscribing to the lending liquidations channel,
+// also the number of liquidations cached to read each liquidation transaction and broadcast it once
+const recentLiquidations = 50
+
 // NewLendingTradeService returns a new instance of LendingTradeService
 func NewLendingTradeService(
 	lendingdao interfaces.LendingOrderDao,
 	lendingTradeDao interfaces.LendingTradeDao,
 	notificationDao interfaces.NotificationDao,
+	finalityService interfaces.FinalityService,
+	provider interfaces.EthereumProvider,
 	broker *rabbitmq.Connection,
 ) *LendingTradeService {
 	bulkLendingTrades := make(map[string][]*types.LendingTrade)
-	return &LendingTradeService{
+	s := &LendingTradeService{
 		lendingDao:          lendingdao,
 		lendingTradeDao:     lendingTradeDao,
 		notificationDao:     notificationDao,
+		finalityService:     finalityService,
+		provider:            provider,
 		broker:              broker,
 		bulkLendingTrades:   bulkLendingTrades,
 		mutext:              sync.RWMutex{},
 		tradeNotifyCallback: nil,
 	}
+
+	finalityService.RegisterNotify(s.HandleTradeFinalized)
+	return s
 }
 
 // RegisterNotify register a only trade notify function
@@ -87,6 +103,116 @@ func (s *LendingTradeService) Unsubscribe(c *ws.Client) {
 	socket.Unsubscribe(c)
 }
 
+// SubscribeLiquidations sends the recent liquidations and registers the client for the new ones
+func (s *LendingTradeService) SubscribeLiquidations(c *ws.Client) {
+	socket := ws.GetLendingLiquidationSocket()
+
+	trades, err := s.lendingTradeDao.GetRecentLiquidated(recentLiquidations)
+	if err != nil {
+		logger.Error(err)
+		socket.SendErrorMessage(c, err.Error())
+		return
+	}
+
+	id := utils.GetLendingLiquidationChannelID(ws.LendingLiquidationChannel)
+	err = socket.Subscribe(id, c)
+	if err != nil {
+		logger.Error(err)
+		socket.SendErrorMessage(c, err.Error())
+		return
+	}
+
+	liquidations := []*types.LendingLiquidation{}
+	for _, t := range trades {
+		l, _, err := s.liquidation(t)
+		if err != nil {
+			logger.Warning("Liquidation of", t.Hash.Hex(), "not read:", err)
+			continue
+		}
+
+		liquidations = append(liquidations, l)
+	}
+
+	ws.RegisterConnectionUnsubscribeHandler(c, socket.UnsubscribeChannelHandler(id))
+	socket.SendInitMessage(c, liquidations)
+}
+
+// UnsubscribeLiquidations removes the client from the lending liquidations channel
+func (s *LendingTradeService) UnsubscribeLiquidations(c *ws.Client) {
+	socket := ws.GetLendingLiquidationSocket()
+	socket.Unsubscribe(c)
+}
+
+// broadcastLiquidation streams the liquidation of a trade once it is read from its liquidation transaction,
+// the trade may be updated again after it
+func (s *LendingTradeService) broadcastLiquidation(t *types.LendingTrade) {
+	l, cached, err := s.liquidation(t)
+	if err != nil {
+		logger.Error("Liquidation of", t.Hash.Hex(), "not broadcast:", err)
+		return
+	}
+
+	if cached {
+		return
+	}
+
+	id := utils.GetLendingLiquidationChannelID(ws.LendingLiquidationChannel)
+	ws.GetLendingLiquidationSocket().BroadcastMessage(id, []*types.LendingLiquidation{l})
+}
+
+// liquidation returns the liquidation of a trade from the transfers of its liquidation transaction and
+// true if it was already cached
+func (s *LendingTradeService) liquidation(t *types.LendingTrade) (*types.LendingLiquidation, bool, error) {
+	s.mutext.RLock()
+	for _, l := range s.liquidations {
+		if l.TradeHash == t.Hash {
+			s.mutext.RUnlock()
+			return l, true, nil
+		}
+	}
+	s.mutext.RUnlock()
+
+	receipt, err := s.provider.TransactionReceipt(t.TxHash)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if receipt == nil || receipt.Status != eth.ReceiptStatusSuccessful {
+		return nil, false, errors.New("Liquidation transaction not mined")
+	}
+
+	transfers, err := ethereum.DecodeTransferLogs(receipt.Logs)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// the decimals are only read for a collateral token transferred, the native collateral has no contract
+	var decimals uint8
+	for _, tr := range transfers {
+		if tr.Token != t.CollateralToken {
+			continue
+		}
+
+		decimals, err = s.provider.Decimals(t.CollateralToken)
+		if err != nil {
+			return nil, false, err
+		}
+
+		break
+	}
+
+	l := types.NewLendingLiquidation(t, transfers, decimals)
+
+	s.mutext.Lock()
+	s.liquidations = append(s.liquidations, l)
+	if len(s.liquidations) > recentLiquidations {
+		s.liquidations = s.liquidations[1:]
+	}
+	s.mutext.Unlock()
+
+	return l, false, nil
+}
+
 // GetLendingTradeByOrderBook get sorted lending trade from term and lending tokens
 func (s *LendingTradeService) GetLendingTradeByOrderBook(tern uint64, lendingToken common.Address, from, to int64, n int) ([]*types.LendingTrade, error) {
 	return s.lendingTradeDao.GetLendingTradeByOrderBook(tern, lendingToken, from, to, n)
@@ -221,6 +347,10 @@ func (s *LendingTradeService) HandleOperationInsert(trade *types.LendingTrade) e
 
 // HandleOperationUpdate sent WS messages to client when a trade is updated with status "SUCCESS" or "ERROR"
 func (s *LendingTradeService) HandleOperationUpdate(trade *types.LendingTrade) error {
+	if trade.Status == types.TradeStatusLiquidated {
+		s.broadcastLiquidation(trade)
+	}
+
 	m := &types.LendingMatches{LendingTrades: []*types.LendingTrade{trade}}
 	borrower, err := s.lendingDao.GetByHash(trade.BorrowingOrderHash)
 	if err != nil {
@@ -250,6 +380,13 @@ func (s *LendingTradeService) HandleTradeSuccess(m *types.LendingMatches) {
 
 		s.saveBulkTrades(t)
 
+		if t.Status == types.TradeStatusSuccess {
+			err := s.finalityService.Track(types.FinalityActionLendingTrade, t.Hash, t.TxHash)
+			if err != nil {
+				logger.Error(err)
+			}
+		}
+
 		ws.SendLendingOrderMessage("LENDING_ORDER_SUCCESS", investor, types.LendingOrderSuccessPayload{LendingMatches: m})
 		ws.SendLendingOrderMessage("LENDING_ORDER_SUCCESS", borrower, types.LendingOrderSuccessPayload{LendingMatches: m})
 		s.notificationDao.Create(&types.Notification{
@@ -276,6 +413,22 @@ func (s *LendingTradeService) HandleTradeSuccess(m *types.LendingMatches) {
 	}
 }
 
+// HandleTradeFinalized notifies the investor and the borrower once a lending trade reached the required confirmations
+func (s *LendingTradeService) HandleTradeFinalized(r *types.FinalityRecord) {
+	if r.Action != types.FinalityActionLendingTrade {
+		return
+	}
+
+	t, err := s.lendingTradeDao.GetByHash(r.Hash)
+	if err != nil || t == nil {
+		logger.Error("Can not find finalized lending trade", r.Hash.Hex(), err)
+		return
+	}
+
+	ws.SendLendingOrderMessage("LENDING_TRADE_FINALIZED", t.Investor, r)
+	ws.SendLendingOrderMessage("LENDING_TRADE_FINALIZED", t.Borrower, r)
+}
+
 func (s *LendingTradeService) saveBulkTrades(t *types.LendingTrade) {
 	s.mutext.Lock()
 	defer s.mutext.Unlock()
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"math/big"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// ListingService enforces the listing requirements of the operator on the pairs discovered
+// by the relayer reconciler. When the "listing" config section is enabled, a new pair is
+// created inactive and held in review until an operator approves it.
+// The SDK has no source for the token holders, they are attested by the reviewer with the liquidity commitment
+type ListingService struct {
+	listingReviewDao interfaces.ListingReviewDao
+	pairDao          interfaces.PairDao
+	enabled          bool
+	requirements     *types.ListingRequirements
+}
+
+// NewListingService returns a new instance of ListingService
+func NewListingService(listingReviewDao interfaces.ListingReviewDao, pairDao interfaces.PairDao) *ListingService {
+	return &ListingService{
+		listingReviewDao: listingReviewDao,
+		pairDao:          pairDao,
+		enabled:          app.Config.Listing["enabled"] > 0,
+		requirements: &types.ListingRequirements{
+			MinHolders:   app.Config.Listing["min_holders"],
+			MinLiquidity: int64(app.Config.Listing["min_liquidity"]),
+			MinDecimals:  app.Config.Listing["min_decimals"],
+			MaxDecimals:  app.Config.Listing["max_decimals"],
+		},
+	}
+}
+
+// Hold is called before a new pair is created, the pair is deactivated until its review is approved
+func (s *ListingService) Hold(p *types.Pair) error {
+	if !s.enabled {
+		return nil
+	}
+
+	existing, err := s.listingReviewDao.GetByPair(p.BaseTokenAddress, p.QuoteTokenAddress, p.RelayerAddress)
+	if err != nil {
+		return err
+	}
+
+	if existing != nil {
+		p.Active = existing.Status == types.ListingStatusApproved
+		return nil
+	}
+
+	p.Active = false
+
+	l := types.NewListingReview(p)
+	l.Evaluate(s.requirements)
+
+	logger.Infof("Pair %s/%s of relayer %s held for listing review", p.BaseTokenSymbol, p.QuoteTokenSymbol, p.RelayerAddress.Hex())
+	return s.listingReviewDao.Create(l)
+}
+
+// GetByStatus returns the listing reviews with one of the statuses
+func (s *ListingService) GetByStatus(statuses ...string) ([]*types.ListingReview, error) {
+	return s.listingReviewDao.GetByStatus(statuses...)
+}
+
+// Approve evaluates the requirements with the holders and the liquidity commitment attested
+// by the reviewer and activates the pair if all of them pass
+func (s *ListingService) Approve(id bson.ObjectId, holders int, commitment *big.Int, identity string) (*types.ListingReview, error) {
+	l, err := s.getPending(id)
+	if err != nil {
+		return nil, err
+	}
+
+	l.Holders = holders
+	l.LiquidityCommitment = commitment
+	passed := l.Evaluate(s.requirements)
+
+	if passed {
+		l.Status = types.ListingStatusApproved
+		l.ReviewedBy = identity
+	}
+
+	err = s.listingReviewDao.Update(l)
+	if err != nil {
+		return nil, err
+	}
+
+	if !passed {
+		return l, ErrListingRequirementsNotMet
+	}
+
+	logger.Infof("Listing of %s/%s approved by %s", l.BaseTokenSymbol, l.QuoteTokenSymbol, identity)
+
+	// the pair may have been removed by the relayer meanwhile, it is activated when it is discovered again
+	p, err := s.pairDao.GetByTokenAddress(l.BaseToken, l.QuoteToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if p == nil {
+		return l, nil
+	}
+
+	err = s.pairDao.SetActive(l.BaseToken, l.QuoteToken, l.RelayerAddress, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// Reject keeps the pair of a listing review inactive
+func (s *ListingService) Reject(id bson.ObjectId, reason string, identity string) (*types.ListingReview, error) {
+	l, err := s.getPending(id)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("Listing of %s/%s rejected by %s", l.BaseTokenSymbol, l.QuoteTokenSymbol, identity)
+
+	l.Status = types.ListingStatusRejected
+	l.ReviewedBy = identity
+	l.Reason = reason
+
+	err = s.listingReviewDao.Update(l)
+	if err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+func (s *ListingService) getPending(id bson.ObjectId) (*types.ListingReview, error) {
+	l, err := s.listingReviewDao.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if l == nil {
+		return nil, ErrListingNotFound
+	}
+
+	if l.Status != types.ListingStatusPending {
+		return nil, ErrListingReviewed
+	}
+
+	return l, nil
+}
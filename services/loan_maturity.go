@@ -0,0 +1,90 @@
+package services
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// defaultReminderLeads are the lead times of the maturity reminders in seconds: 7 days, 1 day and 1 hour
+var defaultReminderLeads = []int64{7 * 24 * 3600, 24 * 3600, 3600}
+
+// maxCalendarDays limits the period of the maturity calendar
+const maxCalendarDays = 366
+
+// LoanMaturityService lists the maturities of the open loans and reminds their parties before they mature
+type LoanMaturityService struct {
+	lendingTradeDao     interfaces.LendingTradeDao
+	notificationService interfaces.NotificationService
+}
+
+// NewLoanMaturityService returns a new instance of LoanMaturityService
+func NewLoanMaturityService(
+	lendingTradeDao interfaces.LendingTradeDao,
+	notificationService interfaces.NotificationService,
+) *LoanMaturityService {
+	return &LoanMaturityService{
+		lendingTradeDao:     lendingTradeDao,
+		notificationService: notificationService,
+	}
+}
+
+// GetCalendar returns the open loans of a user maturing in the next days, grouped by date
+func (s *LoanMaturityService) GetCalendar(addr common.Address, days int) ([]*types.MaturityDate, error) {
+	if days <= 0 || days > maxCalendarDays {
+		days = maxCalendarDays
+	}
+
+	now := time.Now()
+	to := now.AddDate(0, 0, days)
+
+	trades, err := s.lendingTradeDao.GetOpenMaturingByUser(addr, now.Unix(), to.Unix())
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return types.GroupMaturitiesByDate(trades, addr), nil
+}
+
+// SendReminders notifies the borrowers and the investors of the loans whose reminder time,
+// maturity minus one of the lead times, is in [from, to)
+func (s *LoanMaturityService) SendReminders(from, to time.Time) error {
+	for _, lead := range reminderLeads() {
+		trades, err := s.lendingTradeDao.GetOpenMaturing(from.Unix()+lead, to.Unix()+lead)
+		if err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		for _, t := range trades {
+			for _, user := range []common.Address{t.Borrower, t.Investor} {
+				m := types.NewLoanMaturity(t, user)
+
+				err := s.notificationService.Notify(&types.Notification{
+					Recipient: user,
+					Message:   m.ReminderMessage(time.Duration(lead) * time.Second),
+					Type:      types.TypeAlert,
+					Status:    types.StatusUnread,
+				})
+
+				if err != nil {
+					logger.Error(err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func reminderLeads() []int64 {
+	if len(app.Config.MaturityReminders) > 0 {
+		return app.Config.MaturityReminders
+	}
+
+	return defaultReminderLeads
+}
@@ -14,6 +14,54 @@ var ErrBaseTokenNotFound = errors.New("BaseToken not found")
 var ErrQuoteTokenNotFound = errors.New("QuoteToken not found")
 var ErrQuoteTokenInvalid = errors.New("Quote Token Invalid (not a quote)")
 var ErrTokenExists = errors.New("Token already exists")
+var ErrTokenNotFound = errors.New("Token not found")
+var ErrTooManyPricePoints = errors.New("Too many price points, reduce the time range or increase the interval")
 var ErrAccountNotFound = errors.New("Account not found")
 var ErrAccountExists = errors.New("Account already Exists")
 var ErrNoContractCode = errors.New("Contract not found at given address")
+var ErrInvalidSignature = errors.New("Invalid Signature")
+var ErrPairOverloaded = errors.New("Pair overloaded, try again later")
+var ErrPairPaused = errors.New("Pair paused by the operator")
+var ErrPairQueueFull = errors.New("Pair paused by the operator, its order queue is full")
+var ErrPairHalted = errors.New("Pair halted by a kill switch or a token migration")
+var ErrSettlementNotFound = errors.New("Settlement not found")
+var ErrSettlementResolved = errors.New("Settlement already resolved")
+var ErrDisputeHashNotFound = errors.New("No order or trade found for the hash")
+var ErrProfileNotFound = errors.New("Subscription profile not found")
+var ErrInvalidProfile = errors.New("Invalid subscription profile")
+var ErrTooManyProfiles = errors.New("Too many subscription profiles")
+var ErrLendingTradeNotFound = errors.New("Lending trade not found")
+var ErrInvalidIncomePeriod = errors.New("Invalid period, from must be before to and the period at most one year")
+var ErrInvalidLendingRateInterval = errors.New("Invalid interval, use raw, 1h, 4h, 1d or 1w")
+var ErrInterestOutOfCollar = errors.New("Interest rate too far from the recent average of the lending market")
+var ErrListingNotFound = errors.New("Listing review not found")
+var ErrListingReviewed = errors.New("Listing already reviewed")
+var ErrListingRequirementsNotMet = errors.New("Listing requirements not met")
+var ErrInvalidReportPeriod = errors.New("Invalid period, from must be before to and the period at most one year")
+var ErrBalanceSnapshotNotFound = errors.New("No balance snapshot found for the address")
+var ErrPairNotRegistered = errors.New("Pair not registered on the relayer contract")
+var ErrJobNotFound = errors.New("Job not found")
+var ErrRiskLimitNotFound = errors.New("Risk limit not found")
+var ErrRiskLimitExceeded = errors.New("Order exceeds the risk limits of the account")
+var ErrIncidentNotFound = errors.New("Incident not found")
+var ErrIncidentResolved = errors.New("Incident already resolved")
+var ErrTokenSymbolAmbiguous = errors.New("Several tokens share the symbol, use the token address")
+var ErrTokenSymbolMismatch = errors.New("Token symbol does not match the alias")
+var ErrTokenAliasNotFound = errors.New("Token alias not found")
+var ErrInvalidTokenMigration = errors.New("Invalid token migration, the old and new tokens must be distinct contracts")
+var ErrNewTokenNotListed = errors.New("New token not listed by the relayer")
+var ErrTokenMigrationNotFound = errors.New("Token migration not found")
+var ErrKillSwitchNotFound = errors.New("Kill switch not found")
+var ErrKillSwitchReleased = errors.New("Kill switch already released")
+var ErrKillSwitchSignerUnknown = errors.New("Signer not allowed to operate the kill switch")
+var ErrKillSwitchReplayed = errors.New("Kill switch request already executed")
+var ErrRelayerNotRegistered = errors.New("Relayer not registered on the relayer contract")
+var ErrIndexNotFound = errors.New("Composite index not found")
+var ErrIndexExists = errors.New("Composite index already exists")
+var ErrIndexPriceNotFound = errors.New("Price not found for a component of the index")
+var ErrInvalidExportStream = errors.New("Invalid stream, use trades or orders")
+var ErrInvalidCheckpoint = errors.New("Invalid checkpoint")
+var ErrFaucetTooSoon = errors.New("Address already funded by the faucet, try again later")
+var ErrFaucetFailed = errors.New("No faucet transfer could be sent")
+var ErrPendingTxNotFound = errors.New("Pending transaction not found")
+var ErrTooManyReplacements = errors.New("Transaction replaced too many times")
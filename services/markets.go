@@ -2,6 +2,7 @@ package services
 
 import (
 	"math/big"
+	"strings"
 	"time"
 
 	"github.com/globalsign/mgo/bson"
@@ -15,13 +16,21 @@ import (
 // MarketsService struct with daos required, responsible for communicating with daos.
 // MarketsService functions are responsible for interacting with daos and implements business logics.
 type MarketsService struct {
-	PairDao      interfaces.PairDao
-	OrderDao     interfaces.OrderDao
-	TradeDao     interfaces.TradeDao
-	OHLCVService interfaces.OHLCVService
-	PairService  interfaces.PairService
+	PairDao          interfaces.PairDao
+	OrderDao         interfaces.OrderDao
+	TradeDao         interfaces.TradeDao
+	OHLCVService     interfaces.OHLCVService
+	PairService      interfaces.PairService
+	OrderBookService interfaces.OrderBookService
 }
 
+// Sizes of the market snapshot
+const (
+	snapshotDepth   = 20
+	snapshotTrades  = 50
+	snapshotCandles = 100
+)
+
 // NewMarketsService returns a new instance of TradeService
 func NewMarketsService(
 	pairDao interfaces.PairDao,
@@ -29,14 +38,81 @@ func NewMarketsService(
 	tradeDao interfaces.TradeDao,
 	ohlcvService interfaces.OHLCVService,
 	pairService interfaces.PairService,
+	orderBookService interfaces.OrderBookService,
 ) *MarketsService {
 	return &MarketsService{
-		PairDao:      pairDao,
-		OrderDao:     orderdao,
-		TradeDao:     tradeDao,
-		OHLCVService: ohlcvService,
-		PairService:  pairService,
+		PairDao:          pairDao,
+		OrderDao:         orderdao,
+		TradeDao:         tradeDao,
+		OHLCVService:     ohlcvService,
+		PairService:      pairService,
+		OrderBookService: orderBookService,
+	}
+}
+
+// GetSnapshot returns the ticker, the top of the orderbook, the last trades
+// and the last candles of the interval of a pair
+func (s *MarketsService) GetSnapshot(pairName string, duration int64, unit string) (*types.MarketSnapshot, error) {
+	if strings.Count(pairName, "/") != 1 {
+		return nil, ErrPairNotFound
+	}
+
+	p, err := s.PairDao.GetByName(pairName)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if p == nil {
+		return nil, ErrPairNotFound
+	}
+
+	bt := p.BaseTokenAddress
+	qt := p.QuoteTokenAddress
+
+	ticker, err := s.PairService.GetTokenPairData(bt, qt)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	ob, err := s.OrderBookService.GetOrderBook(bt, qt)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if len(ob.Bids) > snapshotDepth {
+		ob.Bids = ob.Bids[:snapshotDepth]
+	}
+
+	if len(ob.Asks) > snapshotDepth {
+		ob.Asks = ob.Asks[:snapshotDepth]
 	}
+
+	spec := &types.TradeSpec{BaseToken: bt.Hex(), QuoteToken: qt.Hex()}
+	trades, err := s.TradeDao.GetTrades(spec, []string{"-createdAt"}, 0, snapshotTrades)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	from := now - snapshotCandles*utils.UnitToSecond(duration, unit)
+	pairs := []types.PairAddresses{{BaseToken: bt, QuoteToken: qt}}
+	candles, err := s.OHLCVService.GetOHLCV(pairs, duration, unit, from, now)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return &types.MarketSnapshot{
+		Ticker:    ticker,
+		OrderBook: ob,
+		Trades:    trades.Trades,
+		Candles:   candles,
+		Timestamp: now,
+	}, nil
 }
 
 // Subscribe market
@@ -218,6 +294,7 @@ func (s *MarketsService) GetPairData() ([]*types.PairData, error) {
 				pairData.Volume = t.Volume
 				pairData.Close = t.Close
 				pairData.Count = t.Count
+				pairData.VolumeUsd = s.OHLCVService.GetVolumeByUsdt(p.BaseTokenAddress, t.Volume)
 				price, err := s.OHLCVService.GetLastPriceCurrentByTime(p.BaseTokenSymbol, t.CloseTime)
 				if err == nil {
 					pairData.CloseBaseUsd = price
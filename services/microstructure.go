@@ -0,0 +1,177 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils"
+	"github.com/tomochain/tomox-sdk/ws"
+)
+
+// Defaults of the "microstructure" config section
+const (
+	defaultMicrostructureInterval = 5
+	defaultMicrostructureLevels   = 10
+	defaultMicrostructureWindow   = 300
+	defaultMicrostructureBuckets  = 10
+)
+
+// MicrostructureService computes the order book imbalance, the depth changes and the trade flow
+// toxicity of the pairs and streams them on the microstructure channel at the configured interval.
+// The metrics are only computed for the pairs with subscriptions, the last ones of each pair are
+// kept to compute the depth changes
+type MicrostructureService struct {
+	pairDao  interfaces.PairDao
+	orderDao interfaces.OrderDao
+	tradeDao interfaces.TradeDao
+	mutex    sync.Mutex
+	last     map[string]*types.Microstructure
+}
+
+// NewMicrostructureService returns a new instance of MicrostructureService
+func NewMicrostructureService(
+	pairDao interfaces.PairDao,
+	orderDao interfaces.OrderDao,
+	tradeDao interfaces.TradeDao,
+) *MicrostructureService {
+	return &MicrostructureService{
+		pairDao:  pairDao,
+		orderDao: orderDao,
+		tradeDao: tradeDao,
+		last:     make(map[string]*types.Microstructure),
+	}
+}
+
+func microstructureSetting(key string, def int) int {
+	if v := app.Config.Microstructure[key]; v > 0 {
+		return v
+	}
+
+	return def
+}
+
+// Interval returns the time between two updates of the metrics
+func (s *MicrostructureService) Interval() time.Duration {
+	return time.Duration(microstructureSetting("interval", defaultMicrostructureInterval)) * time.Second
+}
+
+// Get returns the current metrics of a pair, the depth changes are the changes since the last broadcast
+func (s *MicrostructureService) Get(bt, qt common.Address) (*types.Microstructure, error) {
+	p, err := s.pairDao.GetByTokenAddress(bt, qt)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if p == nil {
+		return nil, ErrPairNotFound
+	}
+
+	return s.compute(p, false)
+}
+
+// Broadcast sends the metrics of the active pairs to their subscriptions
+func (s *MicrostructureService) Broadcast() error {
+	socket := ws.GetMicrostructureSocket()
+	pairs, err := s.pairDao.GetActivePairs()
+	if err != nil {
+		return err
+	}
+
+	for _, p := range pairs {
+		id := utils.GetMicrostructureChannelID(p.BaseTokenAddress, p.QuoteTokenAddress)
+		if !socket.Subscribed(id) {
+			continue
+		}
+
+		m, err := s.compute(p, true)
+		if err != nil {
+			logger.Error(err)
+			continue
+		}
+
+		socket.BroadcastMessage(id, m)
+	}
+
+	return nil
+}
+
+// compute reads the book and the trades of the window of the pair, the metrics broadcast are recorded
+// as the previous ones of the pair
+func (s *MicrostructureService) compute(p *types.Pair, record bool) (*types.Microstructure, error) {
+	bids, asks, err := s.orderDao.GetOrderBook(p)
+	if err != nil {
+		return nil, err
+	}
+
+	window := microstructureSetting("window", defaultMicrostructureWindow)
+	now := time.Now()
+	trades, err := s.tradeDao.GetByPairAndPeriod(
+		p.BaseTokenAddress,
+		p.QuoteTokenAddress,
+		now.Add(-time.Duration(window)*time.Second),
+		now,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	m := types.NewMicrostructure(
+		p,
+		bids,
+		asks,
+		trades,
+		microstructureSetting("levels", defaultMicrostructureLevels),
+		microstructureSetting("buckets", defaultMicrostructureBuckets),
+		int64(window),
+		now.Unix(),
+		s.last[p.Name()],
+	)
+
+	if record {
+		s.last[p.Name()] = m
+	}
+
+	return m, nil
+}
+
+// Subscribe sends the current metrics of the pair to the connection and streams their updates
+func (s *MicrostructureService) Subscribe(c *ws.Client, bt, qt common.Address) {
+	socket := ws.GetMicrostructureSocket()
+
+	m, err := s.Get(bt, qt)
+	if err != nil {
+		socket.SendErrorMessage(c, err.Error())
+		return
+	}
+
+	id := utils.GetMicrostructureChannelID(bt, qt)
+	err = socket.Subscribe(id, c)
+	if err != nil {
+		msg := map[string]string{"Message": err.Error()}
+		socket.SendErrorMessage(c, msg)
+		return
+	}
+
+	ws.RegisterConnectionUnsubscribeHandler(c, socket.UnsubscribeChannelHandler(id))
+	socket.SendInitMessage(c, m)
+}
+
+// Unsubscribe removes the connection from the metrics of all the pairs
+func (s *MicrostructureService) Unsubscribe(c *ws.Client) {
+	ws.GetMicrostructureSocket().Unsubscribe(c)
+}
+
+// UnsubscribeChannel removes the connection from the metrics of a pair
+func (s *MicrostructureService) UnsubscribeChannel(c *ws.Client, bt, qt common.Address) {
+	id := utils.GetMicrostructureChannelID(bt, qt)
+	ws.GetMicrostructureSocket().UnsubscribeChannel(id, c)
+}
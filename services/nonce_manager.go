@@ -0,0 +1,407 @@
+package services
+
+import (
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	eth "github.com/ethereum/go-ethereum/core/types"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/relayer"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// Defaults of the nonce manager
+const (
+	defaultStuckAfter      = 3 * time.Minute
+	defaultMaxReplacements = 5
+)
+
+// accountNonces is the nonce state of an account: the next nonce to assign and its transactions not mined yet
+type accountNonces struct {
+	signer  *relayer.Signer
+	next    uint64
+	synced  bool
+	pending map[uint64]*types.PendingTx
+	mutex   sync.Mutex
+}
+
+// NonceManagerService assigns the nonces of the transactions sent by the accounts of the signers of the SDK.
+// The transactions of an account are sent one at a time, the nonce is only consumed once the node accepted the
+// transaction and is read again from the node after a failure. On every new block the transactions mined are
+// forgotten, the ones dropped by the node are sent again, the nonces left unused below the next one are filled
+// with empty transfers and the transactions pending for too long are replaced with a higher gas price
+type NonceManagerService struct {
+	provider        interfaces.EthereumProvider
+	gasOracle       interfaces.GasOracleService
+	accounts        map[common.Address]*accountNonces
+	stuckAfter      time.Duration
+	priceBump       int
+	maxReplacements int
+	mutex           sync.RWMutex
+}
+
+// NewNonceManagerService returns a new instance of NonceManagerService checking the pending transactions on
+// the heads of the block service
+func NewNonceManagerService(
+	blockService interfaces.BlockService,
+	provider interfaces.EthereumProvider,
+	gasOracle interfaces.GasOracleService,
+) *NonceManagerService {
+	stuckAfter := time.Duration(app.Config.NonceManager["stuck_after"]) * time.Second
+	if stuckAfter <= 0 {
+		stuckAfter = defaultStuckAfter
+	}
+
+	maxReplacements := app.Config.NonceManager["max_replacements"]
+	if maxReplacements <= 0 {
+		maxReplacements = defaultMaxReplacements
+	}
+
+	s := &NonceManagerService{
+		provider:        provider,
+		gasOracle:       gasOracle,
+		accounts:        make(map[common.Address]*accountNonces),
+		stuckAfter:      stuckAfter,
+		priceBump:       app.Config.NonceManager["price_bump"],
+		maxReplacements: maxReplacements,
+	}
+
+	blockService.RegisterNotify(s.HandleNewBlock)
+	return s
+}
+
+// Register manages the nonces of the accounts of a signer
+func (s *NonceManagerService) Register(signer *relayer.Signer) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, addr := range signer.Accounts() {
+		if _, ok := s.accounts[addr]; ok {
+			continue
+		}
+
+		s.accounts[addr] = &accountNonces{
+			signer:  signer,
+			pending: make(map[uint64]*types.PendingTx),
+		}
+	}
+}
+
+func (s *NonceManagerService) account(addr common.Address) (*accountNonces, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	a, ok := s.accounts[addr]
+	if !ok {
+		return nil, relayer.ErrUnknownSignerAccount
+	}
+
+	return a, nil
+}
+
+// Send signs a transaction of an account with its next nonce at the standard gas price and broadcasts it
+func (s *NonceManagerService) Send(
+	from common.Address,
+	to *common.Address,
+	value *big.Int,
+	gas uint64,
+	data []byte,
+) (*types.PendingTx, error) {
+	a, err := s.account(from)
+	if err != nil {
+		return nil, err
+	}
+
+	gasPrice, err := s.gasOracle.GasPrice(types.GasSpeedStandard)
+	if err != nil {
+		return nil, err
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if !a.synced {
+		next, err := s.provider.GetPendingNonceAt(from)
+		if err != nil {
+			return nil, err
+		}
+
+		a.next = next
+		a.synced = true
+	}
+
+	now := time.Now()
+	tx := &types.PendingTx{
+		From:     from,
+		Nonce:    a.next,
+		Kind:     types.PendingTxSend,
+		To:       to,
+		Value:    value,
+		Gas:      gas,
+		GasPrice: gasPrice,
+		Data:     data,
+		FirstAt:  now,
+	}
+
+	err = s.broadcast(a, tx)
+	if err != nil {
+		// the node may have taken the nonce before failing, it is read again for the next transaction
+		a.synced = false
+		return nil, err
+	}
+
+	a.pending[tx.Nonce] = tx
+	a.next++
+	return tx, nil
+}
+
+// broadcast signs a version of a transaction and sends it, its hash is added to the transaction once sent
+func (s *NonceManagerService) broadcast(a *accountNonces, tx *types.PendingTx) error {
+	var raw *eth.Transaction
+	if tx.To == nil {
+		raw = eth.NewContractCreation(tx.Nonce, tx.Value, tx.Gas, tx.GasPrice, tx.Data)
+	} else {
+		raw = eth.NewTransaction(tx.Nonce, *tx.To, tx.Value, tx.Gas, tx.GasPrice, tx.Data)
+	}
+
+	signed, err := a.signer.SignFor(tx.From, raw)
+	if err != nil {
+		return err
+	}
+
+	err = s.provider.SendTransaction(signed)
+	if err != nil {
+		return err
+	}
+
+	if tx.Hash() != signed.Hash() {
+		tx.Hashes = append(tx.Hashes, signed.Hash())
+	}
+
+	tx.SentAt = time.Now()
+	return nil
+}
+
+// replace sends a new version of a pending transaction at a gas price raised by the price bump, at least the
+// suggested price of a speed. The transaction is left unchanged if the replacement is refused
+func (s *NonceManagerService) replace(a *accountNonces, tx *types.PendingTx, speed string) error {
+	if tx.Replacements() >= s.maxReplacements {
+		return ErrTooManyReplacements
+	}
+
+	suggested, err := s.gasOracle.GasPrice(speed)
+	if err != nil {
+		return err
+	}
+
+	previous := tx.GasPrice
+	tx.GasPrice = tx.BumpedGasPrice(s.priceBump, suggested)
+	err = s.broadcast(a, tx)
+	if err != nil {
+		tx.GasPrice = previous
+		return err
+	}
+
+	return nil
+}
+
+// GetPending returns the transactions not mined yet of all the accounts, by account and nonce
+func (s *NonceManagerService) GetPending() []*types.PendingTx {
+	s.mutex.RLock()
+	accounts := make([]*accountNonces, 0, len(s.accounts))
+	for _, a := range s.accounts {
+		accounts = append(accounts, a)
+	}
+	s.mutex.RUnlock()
+
+	res := []*types.PendingTx{}
+	for _, a := range accounts {
+		a.mutex.Lock()
+		for _, tx := range a.pending {
+			res = append(res, tx)
+		}
+		a.mutex.Unlock()
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].From != res[j].From {
+			return res[i].From.Hex() < res[j].From.Hex()
+		}
+
+		return res[i].Nonce < res[j].Nonce
+	})
+
+	return res
+}
+
+// SpeedUp replaces a pending transaction, found by the hash of any of its versions, at the fast gas price
+func (s *NonceManagerService) SpeedUp(hash common.Hash) (*types.PendingTx, error) {
+	return s.update(hash, func(a *accountNonces, tx *types.PendingTx) error {
+		return s.replace(a, tx, types.GasSpeedFast)
+	})
+}
+
+// Cancel replaces a pending transaction, found by the hash of any of its versions, by an empty transfer to its
+// sender at the fast gas price. The cancel fails if the transaction is mined first
+func (s *NonceManagerService) Cancel(hash common.Hash) (*types.PendingTx, error) {
+	return s.update(hash, func(a *accountNonces, tx *types.PendingTx) error {
+		cancel := *tx
+		cancel.Kind = types.PendingTxCancel
+		from := tx.From
+		cancel.To = &from
+		cancel.Value = big.NewInt(0)
+		cancel.Gas = nativeTransferGas
+		cancel.Data = nil
+		cancel.Hashes = append([]common.Hash{}, tx.Hashes...)
+
+		err := s.replace(a, &cancel, types.GasSpeedFast)
+		if err != nil {
+			return err
+		}
+
+		*tx = cancel
+		return nil
+	})
+}
+
+// update applies a change to the pending transaction with a hash under the lock of its account
+func (s *NonceManagerService) update(hash common.Hash, fn func(*accountNonces, *types.PendingTx) error) (*types.PendingTx, error) {
+	s.mutex.RLock()
+	accounts := make([]*accountNonces, 0, len(s.accounts))
+	for _, a := range s.accounts {
+		accounts = append(accounts, a)
+	}
+	s.mutex.RUnlock()
+
+	for _, a := range accounts {
+		a.mutex.Lock()
+		for _, tx := range a.pending {
+			for _, h := range tx.Hashes {
+				if h != hash {
+					continue
+				}
+
+				err := fn(a, tx)
+				a.mutex.Unlock()
+				if err != nil {
+					return nil, err
+				}
+
+				return tx, nil
+			}
+		}
+
+		a.mutex.Unlock()
+	}
+
+	return nil, ErrPendingTxNotFound
+}
+
+// HandleNewBlock checks the pending transactions of the accounts
+func (s *NonceManagerService) HandleNewBlock(block *types.BlockHeader) {
+	s.mutex.RLock()
+	accounts := make(map[common.Address]*accountNonces, len(s.accounts))
+	for addr, a := range s.accounts {
+		accounts[addr] = a
+	}
+	s.mutex.RUnlock()
+
+	for addr, a := range accounts {
+		err := s.check(addr, a)
+		if err != nil {
+			logger.Error("Pending transactions of", addr.Hex(), "not checked:", err)
+		}
+	}
+}
+
+// check forgets the mined transactions of an account, sends again the ones dropped by the node, fills the
+// unused nonces below the next one and replaces the transactions pending for too long
+func (s *NonceManagerService) check(addr common.Address, a *accountNonces) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if len(a.pending) == 0 {
+		return nil
+	}
+
+	mined, err := s.provider.GetNonceAt(addr)
+	if err != nil {
+		return err
+	}
+
+	for n := range a.pending {
+		if n < mined {
+			delete(a.pending, n)
+		}
+	}
+
+	if a.next < mined {
+		a.next = mined
+	}
+
+	if len(a.pending) == 0 {
+		return nil
+	}
+
+	pooled, err := s.provider.GetPendingNonceAt(addr)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for n := mined; n < a.next; n++ {
+		tx, ok := a.pending[n]
+		switch {
+		case !ok && n >= pooled:
+			// a nonce unknown to the node blocks the transactions above it
+			s.fill(a, addr, n)
+		case !ok:
+			continue
+		case n >= pooled:
+			logger.Warningf("Transaction %s dropped by the node, sending it again", tx.Hash().Hex())
+			if err := s.broadcast(a, tx); err != nil {
+				logger.Error(err)
+			}
+		case now.Sub(tx.SentAt) >= s.stuckAfter && tx.Replacements() < s.maxReplacements:
+			logger.Warningf("Transaction %s pending since %s, replacing it", tx.Hash().Hex(), tx.SentAt)
+			if err := s.replace(a, tx, types.GasSpeedFast); err != nil {
+				logger.Error(err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// fill sends an empty transfer to the account itself with an unused nonce
+func (s *NonceManagerService) fill(a *accountNonces, addr common.Address, nonce uint64) {
+	gasPrice, err := s.gasOracle.GasPrice(types.GasSpeedFast)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	tx := &types.PendingTx{
+		From:     addr,
+		Nonce:    nonce,
+		Kind:     types.PendingTxFiller,
+		To:       &addr,
+		Value:    big.NewInt(0),
+		Gas:      nativeTransferGas,
+		GasPrice: gasPrice,
+		FirstAt:  time.Now(),
+	}
+
+	err = s.broadcast(a, tx)
+	if err != nil {
+		logger.Error("Nonce", nonce, "of", addr.Hex(), "not filled:", err)
+		return
+	}
+
+	logger.Warningf("Nonce %d of %s filled by %s", nonce, addr.Hex(), tx.Hash().Hex())
+	a.pending[nonce] = tx
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	eth "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/relayer"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// nonceProvider is the node seen by the nonce manager: the nonces mined and pooled and the transactions sent
+type nonceProvider struct {
+	interfaces.EthereumProvider
+	mined  uint64
+	pooled uint64
+	sent   []*eth.Transaction
+}
+
+func (p *nonceProvider) GetNonceAt(a common.Address) (uint64, error) {
+	return p.mined, nil
+}
+
+func (p *nonceProvider) GetPendingNonceAt(a common.Address) (uint64, error) {
+	return p.pooled, nil
+}
+
+func (p *nonceProvider) SendTransaction(tx *eth.Transaction) error {
+	p.sent = append(p.sent, tx)
+	return nil
+}
+
+// fixedGasOracle suggests the same gas prices on every call
+type fixedGasOracle struct {
+	prices map[string]*big.Int
+}
+
+func (o *fixedGasOracle) GetGasPrices() (*types.GasPrices, error) {
+	return &types.GasPrices{}, nil
+}
+
+func (o *fixedGasOracle) GasPrice(speed string) (*big.Int, error) {
+	return o.prices[speed], nil
+}
+
+// testSignerBackend signs with a key generated for the test
+type testSignerBackend struct {
+	key *ecdsa.PrivateKey
+}
+
+func (b *testSignerBackend) Address() common.Address {
+	return crypto.PubkeyToAddress(b.key.PublicKey)
+}
+
+func (b *testSignerBackend) SignTx(tx *eth.Transaction, chainID *big.Int) (*eth.Transaction, error) {
+	return eth.SignTx(tx, eth.HomesteadSigner{}, b.key)
+}
+
+func newTestNonceManager(t *testing.T, pooled uint64) (*NonceManagerService, *nonceProvider, common.Address) {
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+
+	backend := &testSignerBackend{key}
+	provider := &nonceProvider{mined: pooled, pooled: pooled}
+	s := &NonceManagerService{
+		provider: provider,
+		gasOracle: &fixedGasOracle{map[string]*big.Int{
+			types.GasSpeedStandard: big.NewInt(100),
+			types.GasSpeedFast:     big.NewInt(200),
+		}},
+		accounts:        make(map[common.Address]*accountNonces),
+		stuckAfter:      time.Minute,
+		maxReplacements: 2,
+	}
+
+	s.Register(relayer.NewSignerWithBackend(backend))
+	return s, provider, backend.Address()
+}
+
+func TestNonceManagerCheckMined(t *testing.T) {
+	s, provider, addr := newTestNonceManager(t, 5)
+	to := common.HexToAddress("0x1")
+
+	for i := 0; i < 3; i++ {
+		tx, err := s.Send(addr, &to, big.NewInt(1), nativeTransferGas, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(5+i), tx.Nonce)
+	}
+
+	// the transactions below the mined nonce are forgotten, the pooled ones are left alone
+	provider.mined = 7
+	provider.pooled = 8
+	a, _ := s.account(addr)
+	assert.NoError(t, s.check(addr, a))
+
+	pending := s.GetPending()
+	assert.Equal(t, 1, len(pending))
+	assert.Equal(t, uint64(7), pending[0].Nonce)
+	assert.Equal(t, 3, len(provider.sent))
+
+	provider.mined = 8
+	assert.NoError(t, s.check(addr, a))
+	assert.Equal(t, 0, len(s.GetPending()))
+}
+
+func TestNonceManagerCheckDropped(t *testing.T) {
+	s, provider, addr := newTestNonceManager(t, 5)
+	to := common.HexToAddress("0x1")
+
+	for i := 0; i < 3; i++ {
+		_, err := s.Send(addr, &to, big.NewInt(1), nativeTransferGas, nil)
+		assert.NoError(t, err)
+	}
+
+	// the node lost the transactions from nonce 6, the one of nonce 6 was never accepted
+	a, _ := s.account(addr)
+	delete(a.pending, 6)
+	provider.sent = nil
+	provider.pooled = 6
+	assert.NoError(t, s.check(addr, a))
+
+	// nonce 6 is filled with an empty transfer to the account, nonce 7 is sent again as is
+	assert.Equal(t, 2, len(provider.sent))
+
+	filler := provider.sent[0]
+	assert.Equal(t, uint64(6), filler.Nonce())
+	assert.Equal(t, addr, *filler.To())
+	assert.Equal(t, 0, filler.Value().Sign())
+	assert.Equal(t, big.NewInt(200), filler.GasPrice())
+	assert.Equal(t, types.PendingTxFiller, a.pending[6].Kind)
+	assert.Equal(t, filler.Hash(), a.pending[6].Hash())
+
+	resent := provider.sent[1]
+	assert.Equal(t, uint64(7), resent.Nonce())
+	assert.Equal(t, a.pending[7].Hash(), resent.Hash())
+	assert.Equal(t, 0, a.pending[7].Replacements())
+}
+
+func TestNonceManagerCheckStuck(t *testing.T) {
+	s, provider, addr := newTestNonceManager(t, 5)
+	to := common.HexToAddress("0x1")
+
+	tx, err := s.Send(addr, &to, big.NewInt(1), nativeTransferGas, nil)
+	assert.NoError(t, err)
+	provider.pooled = 6
+
+	// a transaction pending for less than stuckAfter is left alone
+	a, _ := s.account(addr)
+	assert.NoError(t, s.check(addr, a))
+	assert.Equal(t, 1, len(provider.sent))
+
+	// it is replaced at the fast gas price once stuck, until the maximum number of replacements
+	for i := 1; i <= 3; i++ {
+		tx.SentAt = time.Now().Add(-2 * time.Minute)
+		assert.NoError(t, s.check(addr, a))
+	}
+
+	assert.Equal(t, 3, len(provider.sent))
+	assert.Equal(t, 2, tx.Replacements())
+	assert.Equal(t, big.NewInt(220), tx.GasPrice)
+
+	replacement := provider.sent[2]
+	assert.Equal(t, uint64(5), replacement.Nonce())
+	assert.Equal(t, to, *replacement.To())
+	assert.Equal(t, big.NewInt(220), replacement.GasPrice())
+	assert.Equal(t, tx.Hash(), replacement.Hash())
+}
+
+func TestNonceManagerCancel(t *testing.T) {
+	s, provider, addr := newTestNonceManager(t, 5)
+	to := common.HexToAddress("0x1")
+
+	tx, err := s.Send(addr, &to, big.NewInt(1), nativeTransferGas, []byte{1})
+	assert.NoError(t, err)
+	first := tx.Hash()
+
+	_, err = s.Cancel(common.HexToHash("0x2"))
+	assert.Equal(t, ErrPendingTxNotFound, err)
+
+	// the transaction is replaced by an empty transfer to its sender at the fast gas price
+	res, err := s.Cancel(first)
+	assert.NoError(t, err)
+	assert.Equal(t, types.PendingTxCancel, res.Kind)
+	assert.Equal(t, addr, *res.To)
+	assert.Equal(t, 0, res.Value.Sign())
+	assert.Nil(t, res.Data)
+	assert.Equal(t, big.NewInt(200), res.GasPrice)
+	assert.Equal(t, []common.Hash{first, provider.sent[1].Hash()}, res.Hashes)
+
+	cancel := provider.sent[1]
+	assert.Equal(t, uint64(5), cancel.Nonce())
+	assert.Equal(t, addr, *cancel.To())
+	assert.Equal(t, 0, len(cancel.Data()))
+
+	// any version of the transaction finds it, the replacements are limited
+	_, err = s.Cancel(first)
+	assert.NoError(t, err)
+
+	_, err = s.Cancel(first)
+	assert.Equal(t, ErrTooManyReplacements, err)
+	assert.Equal(t, 3, len(provider.sent))
+}
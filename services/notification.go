@@ -5,20 +5,24 @@ import (
 	"github.com/globalsign/mgo/bson"
 	"github.com/tomochain/tomox-sdk/interfaces"
 	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/ws"
 )
 
 // NotificationService struct with daos required, responsible for communicating with dao
 // NotificationService functions are responsible for interacting with dao and implements business logic.
 type NotificationService struct {
 	NotificationDao interfaces.NotificationDao
+	PreferenceDao   interfaces.NotificationPreferenceDao
 }
 
 // NewNotificationService returns a new instance of NewNotificationService
 func NewNotificationService(
 	notificationDao interfaces.NotificationDao,
+	preferenceDao interfaces.NotificationPreferenceDao,
 ) *NotificationService {
 	return &NotificationService{
 		NotificationDao: notificationDao,
+		PreferenceDao:   preferenceDao,
 	}
 }
 
@@ -81,3 +85,50 @@ func (s *NotificationService) MarkUnRead(id bson.ObjectId) error {
 func (s *NotificationService) MarkAllRead(addr common.Address) error {
 	return s.NotificationDao.MarkAllRead(addr)
 }
+
+// GetPreference returns the notification preference of an address, the default channels if it has none
+func (s *NotificationService) GetPreference(addr common.Address) (*types.NotificationPreference, error) {
+	p, err := s.PreferenceDao.GetByAddress(addr)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if p == nil {
+		p = &types.NotificationPreference{Address: addr, Channels: types.DefaultNotificationChannels}
+	}
+
+	return p, nil
+}
+
+// SetPreference saves the notification preference of an address
+func (s *NotificationService) SetPreference(p *types.NotificationPreference) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	return s.PreferenceDao.Save(p)
+}
+
+// Notify delivers a notification to its recipient over its preferred channels
+func (s *NotificationService) Notify(n *types.Notification) error {
+	p, err := s.GetPreference(n.Recipient)
+	if err != nil {
+		return err
+	}
+
+	notifications := []*types.Notification{n}
+	if p.HasChannel(types.NotificationChannelApp) {
+		notifications, err = s.NotificationDao.Create(n)
+		if err != nil {
+			logger.Error(err)
+			return err
+		}
+	}
+
+	if p.HasChannel(types.NotificationChannelWebsocket) {
+		ws.SendNotificationMessage(types.SubscriptionEvent(n.Message.MessageType), n.Recipient, notifications)
+	}
+
+	return nil
+}
@@ -4,7 +4,6 @@ import (
 	"bufio"
 	"encoding/json"
 	"fmt"
-	"math"
 	"math/big"
 	"os"
 	"sort"
@@ -19,6 +18,7 @@ import (
 	"github.com/tomochain/tomox-sdk/interfaces"
 	"github.com/tomochain/tomox-sdk/types"
 	"github.com/tomochain/tomox-sdk/utils"
+	"github.com/tomochain/tomox-sdk/utils/math"
 	"github.com/tomochain/tomox-sdk/ws"
 )
 
@@ -32,6 +32,7 @@ const (
 	tomo                = "TOMO"
 	cacheTimeLifeMax    = 15 * 50
 	cacheCommitInterval = 60 * 10 * time.Second
+	maxPricePoints      = 1000
 )
 
 type PairCache struct {
@@ -128,13 +129,27 @@ func (s *OHLCVService) UnsubscribeChannel(conn *ws.Client, p *types.Subscription
 
 // Subscribe handles all the subscription messages for ticks corresponding to a pair
 // It calls the corresponding channel's subscription method and sends trade history back on the connection
+// in the timezone and the units of the display preferences of the connection, the timezone of the
+// subscription payload overrides the one of the connection
 func (s *OHLCVService) Subscribe(conn *ws.Client, p *types.SubscriptionPayload) {
 	socket := ws.GetOHLCVSocket()
 
-	ohlcv, err := s.GetOHLCV(
+	prefs := conn.Preferences()
+	if p.Timezone != "" {
+		loc, err := types.LoadTimezone(p.Timezone)
+		if err != nil {
+			socket.SendErrorMessage(conn, err.Error())
+			return
+		}
+
+		prefs = prefs.WithLocation(loc)
+	}
+
+	ohlcv, err := s.GetOHLCVWithPreferences(
 		[]types.PairAddresses{{BaseToken: p.BaseToken, QuoteToken: p.QuoteToken}},
 		p.Duration,
 		p.Units,
+		prefs,
 		p.From,
 		p.To,
 	)
@@ -146,7 +161,7 @@ func (s *OHLCVService) Subscribe(conn *ws.Client, p *types.SubscriptionPayload)
 	}
 
 	id := utils.GetOHLCVChannelID(p.BaseToken, p.QuoteToken, p.Units, p.Duration)
-	err = socket.Subscribe(id, conn)
+	err = socket.SubscribeWithPreferences(id, prefs, conn)
 	if err != nil {
 		logger.Error(err)
 		socket.SendErrorMessage(conn, err.Error())
@@ -495,9 +510,8 @@ func (s *OHLCVService) parseTickKey(key string) (common.Address, common.Address,
 func (s *OHLCVService) getVolumeByQuote(baseToken, quoteToken common.Address, amount *big.Int, price *big.Int) *big.Int {
 	token, err := s.getTokenByAddress(baseToken)
 	if err == nil && token != nil {
-		baseTokenDecimalBig := big.NewInt(int64(math.Pow10(token.Decimals)))
 		p := new(big.Int).Mul(amount, price)
-		return new(big.Int).Div(p, baseTokenDecimalBig)
+		return new(big.Int).Div(p, math.Pow10(token.Decimals))
 	}
 	return big.NewInt(0)
 }
@@ -507,9 +521,8 @@ func (s *OHLCVService) getVolumeByUsdt(baseToken, quoteToken common.Address, bas
 	if err == nil {
 		quoteTokenPrice, err := s.getTokenPriceByUsdt(quoteToken)
 		if err == nil {
-			quoteTokenDecimal := big.NewInt(int64(math.Pow10(token.Decimals)))
 			volumeByUsdt := new(big.Int).Mul(quoteVolume, quoteTokenPrice)
-			volumeByUsdt.Div(volumeByUsdt, quoteTokenDecimal)
+			volumeByUsdt.Div(volumeByUsdt, math.Pow10(token.Decimals))
 			return volumeByUsdt
 		}
 	}
@@ -529,7 +542,8 @@ func (s *OHLCVService) updateTick(key string, trade *types.Trade) error {
 			s.tickCache.ticks[key] = make(map[int64]*types.Tick)
 		}
 		if tickByTime, ok1 := s.tickCache.ticks[key]; ok1 {
-			if last, ok2 := tickByTime[modTime]; ok2 {
+			// empty ticks written by the gaps backfill are replaced by the first trade
+			if last, ok2 := tickByTime[modTime]; ok2 && last.Count.Sign() > 0 {
 				last.Timestamp = modTime
 				last.Close = trade.PricePoint
 				if last.High.Cmp(trade.PricePoint) < 0 {
@@ -808,6 +822,41 @@ func (s *OHLCVService) getRelayerTickByTime(relayerAddress common.Address, baseT
 	return nil
 }
 
+// BackfillGaps stores an empty tick for every recent interval without trades
+// so that the cached series are continuous
+func (s *OHLCVService) BackfillGaps() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now().Unix()
+	count := 0
+	for key, tickByTime := range s.tickCache.ticks {
+		_, _, duration, unit, err := s.parseTickKey(key)
+		if err != nil {
+			logger.Error(err)
+			continue
+		}
+
+		ticks := make([]*types.Tick, 0, len(tickByTime))
+		for _, t := range tickByTime {
+			ticks = append(ticks, t)
+		}
+
+		sort.Slice(ticks, func(i, j int) bool {
+			return ticks[i].Timestamp < ticks[j].Timestamp
+		})
+
+		for _, t := range types.FillTickGaps(ticks, duration, unit, now, time.Second) {
+			if _, ok := tickByTime[t.Timestamp]; !ok {
+				tickByTime[t.Timestamp] = t
+				count++
+			}
+		}
+	}
+
+	logger.Infof("OHLCV backfill added %d empty ticks", count)
+}
+
 // NotifyTrade trigger if trade comming
 func (s *OHLCVService) NotifyTrade(trade *types.Trade) {
 	s.mutex.Lock()
@@ -826,6 +875,63 @@ func (s *OHLCVService) NotifyTrade(trade *types.Trade) {
 	s.updatelasttimeframe(trade.CreatedAt.Unix(), lastFrame)
 }
 
+// RevertTrades rebuilds the cached candles of the pairs of trades reverted by a chain reorganization, from
+// the candle of the first reverted trade on, without them. The rebuilt candles are broadcast, a reverted
+// trade is counted again by NotifyTrade once it is settled again
+func (s *OHLCVService) RevertTrades(trades []*types.Trade) {
+	reverted := make(map[common.Hash]bool)
+	since := make(map[types.PairAddresses]int64)
+	for _, t := range trades {
+		reverted[t.Hash] = true
+		p := types.PairAddresses{BaseToken: t.BaseToken, QuoteToken: t.QuoteToken}
+		if ts, ok := since[p]; !ok || t.CreatedAt.Unix() < ts {
+			since[p] = t.CreatedAt.Unix()
+		}
+	}
+
+	durations := s.getConfig()
+	for p, ts := range since {
+		from := ts
+		for _, d := range durations {
+			start, _ := utils.GetModTime(ts, d.duration, d.unit)
+			if start < from {
+				from = start
+			}
+		}
+
+		pairTrades, err := s.tradeDao.GetByPairAndPeriod(p.BaseToken, p.QuoteToken, time.Unix(from, 0), time.Now())
+		if err != nil {
+			logger.Error(err)
+			continue
+		}
+
+		rebuilt := make(map[durationtick][]*types.Tick)
+		s.mutex.Lock()
+		for _, d := range durations {
+			key := s.getTickKey(p.BaseToken, p.QuoteToken, d.duration, d.unit)
+			start, _ := utils.GetModTime(ts, d.duration, d.unit)
+			for timestamp := range s.tickCache.ticks[key] {
+				if timestamp >= start {
+					delete(s.tickCache.ticks[key], timestamp)
+				}
+			}
+
+			for _, t := range pairTrades {
+				if !reverted[t.Hash] && t.CreatedAt.Unix() >= start {
+					s.updateTick(key, t)
+				}
+			}
+
+			rebuilt[d] = s.filterTick(key, start, 0)
+		}
+		s.mutex.Unlock()
+
+		for d, ticks := range rebuilt {
+			s.BroadcastTicks(ticks, d.duration, d.unit)
+		}
+	}
+}
+
 func (s *OHLCVService) getOHLCV(pairs []types.PairAddresses, duration int64, unit string, start, end time.Time) ([]*types.Tick, error) {
 	res := make([]*types.Tick, 0)
 	match := make(bson.M)
@@ -877,6 +983,116 @@ func (s *OHLCVService) GetOHLCV(pairs []types.PairAddresses, duration int64, uni
 	return ticks, nil
 }
 
+// GetOHLCVIn fetches the OHLCV data of a pair like GetOHLCV with the intervals starting at the
+// local boundaries of loc, e.g. the daily ticks start at local midnight. When the offset of loc
+// is not a multiple of the interval, the hourly (or quarter-hourly) ticks are merged into local intervals
+func (s *OHLCVService) GetOHLCVIn(pairs []types.PairAddresses, duration int64, unit string, loc *time.Location, from, to int64) ([]*types.Tick, error) {
+	source, sourceUnit, shifted := localSource(duration, unit, loc, from, to)
+	if !shifted {
+		return s.GetOHLCV(pairs, duration, unit, from, to)
+	}
+
+	start, _ := utils.GetModTimeIn(from, duration, unit, loc)
+	ticks, err := s.GetOHLCV(pairs, source, sourceUnit, start, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return types.MergeTicks(ticks, duration, unit, loc), nil
+}
+
+// localSource returns the interval of the ticks to merge into the local intervals of loc between
+// from and to, shifted is false if the local intervals are the UTC ones
+func localSource(duration int64, unit string, loc *time.Location, from, to int64) (int64, string, bool) {
+	interval := utils.UnitToSecond(duration, unit)
+	source, sourceUnit, shifted := int64(1), "hour", false
+	for _, ts := range []int64{from, to} {
+		_, offset := time.Unix(ts, 0).In(loc).Zone()
+		if interval > 0 && int64(offset)%interval != 0 {
+			shifted = true
+		}
+
+		if offset%hourSec != 0 {
+			source, sourceUnit = 15, "min"
+		}
+	}
+
+	return source, sourceUnit, shifted
+}
+
+// TokenTicks converts the ticks to whole tokens of their pairs
+func (s *OHLCVService) TokenTicks(ticks []*types.Tick) ([]*types.TokenTick, error) {
+	res := make([]*types.TokenTick, 0, len(ticks))
+	for _, t := range ticks {
+		p, err := s.getCachePairByAddress(t.Pair.BaseToken, t.Pair.QuoteToken)
+		if err != nil {
+			return nil, err
+		}
+
+		if p == nil {
+			return nil, ErrPairNotFound
+		}
+
+		res = append(res, t.TokenUnits(p, fiatToken.Decimals))
+	}
+
+	return res, nil
+}
+
+// GetOHLCVWithPreferences fetches the OHLCV data of a pair in the timezone and the units of the display preferences
+func (s *OHLCVService) GetOHLCVWithPreferences(pairs []types.PairAddresses, duration int64, unit string, prefs *types.Preferences, from, to int64) (interface{}, error) {
+	ticks, err := s.GetOHLCVIn(pairs, duration, unit, prefs.Location(), from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	if prefs.TokenUnits() {
+		return s.TokenTicks(ticks)
+	}
+
+	return ticks, nil
+}
+
+// BroadcastTicks streams the latest ticks to the subscribers of their pairs. The subscribers with
+// display preferences receive the tick of their local interval in their units
+func (s *OHLCVService) BroadcastTicks(ticks []*types.Tick, duration int64, unit string) {
+	socket := ws.GetOHLCVSocket()
+	variants := socket.Variants()
+
+	for _, tick := range ticks {
+		id := utils.GetTickChannelID(tick.Pair.BaseToken, tick.Pair.QuoteToken, unit, duration)
+		socket.BroadcastOHLCV(id, tick)
+
+		for _, prefs := range variants {
+			if !socket.HasSubscribers(id, prefs) {
+				continue
+			}
+
+			now := time.Now().Unix()
+			start, _ := utils.GetModTimeIn(now, duration, unit, prefs.Location())
+			pairs := []types.PairAddresses{{BaseToken: tick.Pair.BaseToken, QuoteToken: tick.Pair.QuoteToken}}
+
+			local, err := s.GetOHLCVIn(pairs, duration, unit, prefs.Location(), start, now)
+			if err != nil || len(local) == 0 {
+				continue
+			}
+
+			var last interface{} = local[len(local)-1]
+			if prefs.TokenUnits() {
+				res, err := s.TokenTicks(local[len(local)-1:])
+				if err != nil {
+					logger.Error(err)
+					continue
+				}
+
+				last = res[0]
+			}
+
+			socket.BroadcastOHLCVVariant(id, prefs, last)
+		}
+	}
+}
+
 func getMatchQuery(start, end time.Time, pairs ...types.PairAddresses) bson.M {
 	match := bson.M{
 		"createdAt": bson.M{
@@ -1094,6 +1310,7 @@ func (s *OHLCVService) getTokenPairData(pairName string, baseTokenSymbol string,
 		pairData.Close = tick.Close
 		pairData.Count = tick.Count
 		pairData.BaseVolume = tick.Volume
+		pairData.VolumeUsd = tick.VolumeUsdt
 		fOpen := new(big.Float).SetInt(tick.Open)
 		fLast := new(big.Float).SetInt(tick.Close)
 		delta := new(big.Float).Sub(fLast, fOpen)
@@ -1229,8 +1446,7 @@ func (s *OHLCVService) GetFiatPriceChart() (map[string][]*types.FiatPriceItem, e
 			if pair.BaseTokenSymbol == symbol {
 				tick := s.get24hTick(pair.BaseTokenAddress, pair.QuoteTokenAddress)
 				if tick != nil {
-					quoteTokenDecimal := int64(math.Pow10(pair.QuoteTokenDecimals))
-					volume := big.NewInt(0).Div(tick.VolumeByQuote, big.NewInt(quoteTokenDecimal))
+					volume := big.NewInt(0).Div(tick.VolumeByQuote, pair.QuoteTokenMultiplier())
 					totalVolume[symbol].Add(totalVolume[symbol], volume)
 				}
 			}
@@ -1275,8 +1491,7 @@ func (s *OHLCVService) getLastPricePairAtTime(pairName string, createAt time.Tim
 			key := s.getTickKey(pair.BaseTokenAddress, pair.QuoteTokenAddress, d.duration, d.unit)
 			if tradeTick, ok := s.tickCache.ticks[key]; ok {
 				if tick, ok := tradeTick[mod]; ok {
-					quoteTokenDecimal := int64(math.Pow10(pair.QuoteTokenDecimals))
-					return big.NewFloat(0).Quo(new(big.Float).SetInt(tick.Close), big.NewFloat(float64(quoteTokenDecimal))), nil
+					return pair.Price(tick.Close), nil
 				}
 			}
 		}
@@ -1309,6 +1524,53 @@ func (s *OHLCVService) getLastPriceCurrentByTime(symbol string, createAt time.Ti
 	return price, err
 }
 
+// GetTokenPriceHistory returns the fiat price of a token at every interval between from and to.
+// The price is derived from the pair with the fiat token or through the TOMO pairs
+func (s *OHLCVService) GetTokenPriceHistory(token common.Address, duration int64, unit string, from, to int64) (*types.TokenPriceHistory, error) {
+	t, err := s.getTokenByAddress(token)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if t == nil {
+		return nil, ErrTokenNotFound
+	}
+
+	step := utils.UnitToSecond(duration, unit)
+	if step <= 0 || (to-from)/step > maxPricePoints {
+		return nil, ErrTooManyPricePoints
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	res := &types.TokenPriceHistory{
+		Token:        token,
+		Symbol:       t.Symbol,
+		FiatCurrency: baseFiat,
+		Prices:       []*types.TokenPrice{},
+	}
+
+	start, _ := utils.GetModTime(from, duration, unit)
+	for ts := start; ts <= to; ts += step {
+		price := big.NewFloat(1)
+		if t.Symbol != baseFiat {
+			price, err = s.getLastPriceCurrentByTime(t.Symbol, time.Unix(ts, 0))
+			if err != nil {
+				continue
+			}
+		}
+
+		res.Prices = append(res.Prices, &types.TokenPrice{
+			Timestamp: ts,
+			Price:     price.String(),
+		})
+	}
+
+	return res, nil
+}
+
 // GetLastPriceCurrentByTime get last trade price
 func (s *OHLCVService) GetLastPriceCurrentByTime(symbol string, createAt time.Time) (*big.Float, error) {
 	s.mutex.RLock()
@@ -1389,21 +1651,18 @@ func (s *OHLCVService) getTokenPriceByUsdt(token common.Address) (*big.Int, erro
 	if err != nil || t == nil {
 		return big.NewInt(0), errors.New("cant not get token price by usdt")
 	}
-	fiatTokenDecimal := int64(math.Pow10(fiatToken.Decimals))
 	if t.Symbol == baseFiat {
 		s.priceCacheByUsdt[token] = &PriceUsdt{
-			price:    big.NewInt(fiatTokenDecimal),
+			price:    math.Pow10(fiatToken.Decimals),
 			timelife: now,
 		}
-		return big.NewInt(fiatTokenDecimal), nil
+		return math.Pow10(fiatToken.Decimals), nil
 	}
 	price, err := s.getLastPriceCurrentByTime(t.Symbol, time.Now())
 	if price == nil || err != nil {
 		return big.NewInt(0), err
 	}
-	priceDecimals := new(big.Float).Mul(price, big.NewFloat(float64(fiatTokenDecimal)))
-	priceDecimalsInt := new(big.Int)
-	priceDecimals.Int(priceDecimalsInt)
+	priceDecimalsInt := math.FromUnits(price, fiatToken.Decimals)
 	if err == nil {
 		s.priceCacheByUsdt[token] = &PriceUsdt{
 			price:    priceDecimalsInt,
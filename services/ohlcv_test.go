@@ -79,6 +79,10 @@ var testTimes = []string{
 var durationMap = make(map[string]map[int64]*types.Tick)
 
 func TestOHLCV(t *testing.T) {
+	if db == nil {
+		t.Skip("mongod is not installed")
+	}
+
 	pair := &types.PairAddresses{
 		Name:       "HPC/AUT",
 		BaseToken:  common.HexToAddress("0x2034842261b82651885751fc293bba7ba5398156"),
@@ -100,8 +104,8 @@ func TestOHLCV(t *testing.T) {
 	app.Config.DBName = "tomodex"
 	tradeDao := daos.NewTradeDao()
 	pairDao := daos.NewPairDao()
-	fiatPriceDao := daos.NewFiatPriceDao()
-	ohlcvService := NewOHLCVService(tradeDao, pairDao, fiatPriceDao)
+	tokenDao := daos.NewTokenDao()
+	ohlcvService := NewOHLCVService(tradeDao, pairDao, tokenDao)
 
 	for _, t := range testTimes {
 		tTime, err := time.Parse(timeLayoutString, t)
@@ -9,7 +9,9 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/app"
 	"github.com/tomochain/tomox-sdk/errors"
 	"github.com/tomochain/tomox-sdk/interfaces"
 	"github.com/tomochain/tomox-sdk/rabbitmq"
@@ -29,11 +31,17 @@ type OrderService struct {
 	engine            interfaces.Engine
 	validator         interfaces.ValidatorService
 	broker            *rabbitmq.Connection
+	journal           interfaces.EngineJournalService
+	risk              interfaces.RiskService
+	compliance        interfaces.ComplianceGate
+	attestation       interfaces.AttestationService
+	walletDao         interfaces.WalletDao
 	orderByPricepoint map[string]map[common.Hash]*amountByTime
 	mutext            sync.RWMutex
 	orderPending      []*types.Order
 	isFinishCache     bool
 	bulkOrders        map[*types.PairAddresses]map[common.Hash]*types.Order
+	dustCancels       map[common.Hash]bool
 }
 
 type amountByTime struct {
@@ -52,6 +60,11 @@ func NewOrderService(
 	engine interfaces.Engine,
 	validator interfaces.ValidatorService,
 	broker *rabbitmq.Connection,
+	journal interfaces.EngineJournalService,
+	risk interfaces.RiskService,
+	compliance interfaces.ComplianceGate,
+	attestation interfaces.AttestationService,
+	walletDao interfaces.WalletDao,
 ) *OrderService {
 	bulkOrders := make(map[*types.PairAddresses]map[common.Hash]*types.Order)
 	orderByPricepoint := make(map[string]map[common.Hash]*amountByTime)
@@ -65,11 +78,17 @@ func NewOrderService(
 		engine,
 		validator,
 		broker,
+		journal,
+		risk,
+		compliance,
+		attestation,
+		walletDao,
 		orderByPricepoint,
 		sync.RWMutex{},
 		[]*types.Order{},
 		false,
 		bulkOrders,
+		make(map[common.Hash]bool),
 	}
 }
 
@@ -230,7 +249,7 @@ func (s *OrderService) NewOrder(o *types.Order) error {
 	}
 
 	if !ok {
-		return errors.New("Invalid Signature")
+		return ErrInvalidSignature
 	}
 
 	p, err := s.pairDao.GetByTokenAddress(o.BaseToken, o.QuoteToken)
@@ -243,6 +262,21 @@ func (s *OrderService) NewOrder(o *types.Order) error {
 		return errors.New("Pair not found")
 	}
 
+	delay, ok := s.engine.AdmitOrder(p.Code())
+	if !ok && s.engine.IsPairPaused(p.Code()) {
+		return ErrPairPaused
+	}
+
+	if !ok && s.engine.IsPairQueueing(p.Code()) {
+		return ErrPairQueueFull
+	}
+
+	if !ok {
+		return ErrPairOverloaded
+	}
+
+	time.Sleep(delay)
+
 	/*
 		if math.IsStrictlySmallerThan(o.QuoteAmount(p), p.MinQuoteAmount()) {
 			return errors.New("Order amount too low")
@@ -255,6 +289,18 @@ func (s *OrderService) NewOrder(o *types.Order) error {
 		logger.Error(err)
 		return err
 	}
+
+	err = s.compliance.CheckOrder(o.UserAddress, p.Name())
+	if err != nil {
+		return err
+	}
+
+	err = s.risk.Check(o, p)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
 	if o.Type == types.TypeLimitOrder {
 		err = s.validator.ValidateAvailablExchangeBalance(o)
 		if err != nil {
@@ -272,6 +318,78 @@ func (s *OrderService) NewOrder(o *types.Order) error {
 	return nil
 }
 
+// ValidateOrder runs the validation pipeline of NewOrder on a copy of the order without sending it
+// to the engine. All the violations are returned, the signature and the balance are only checked
+// when the order parameters are valid. The load of the engine is not checked
+func (s *OrderService) ValidateOrder(order *types.Order) (*types.OrderDryRun, error) {
+	o := *order
+	res := types.NewOrderDryRun(&o)
+
+	if err := o.Validate(); err != nil {
+		res.Add(types.OrderRuleParams, err.Error())
+	}
+
+	if !res.Violates(types.OrderRuleParams) {
+		ok, err := o.VerifySignature()
+		if !ok {
+			msg := ErrInvalidSignature.Error()
+			if err != nil {
+				msg = err.Error()
+			}
+
+			res.Add(types.OrderRuleSignature, msg)
+		}
+	}
+
+	p, err := s.pairDao.GetByTokenAddress(o.BaseToken, o.QuoteToken)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if p == nil {
+		res.Add(types.OrderRulePair, ErrPairNotFound.Error())
+		return res, nil
+	}
+
+	if s.engine.IsPairPaused(p.Code()) {
+		res.Add(types.OrderRulePair, ErrPairPaused.Error())
+	}
+
+	if res.Violates(types.OrderRuleParams) {
+		return res, nil
+	}
+
+	err = o.Process(p)
+	if err != nil {
+		res.Add(types.OrderRuleParams, err.Error())
+		return res, nil
+	}
+
+	if err := s.compliance.CheckOrder(o.UserAddress, p.Name()); err != nil {
+		res.Add(types.OrderRuleCompliance, err.Error())
+	}
+
+	violations, err := s.risk.Evaluate(&o, p)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	for _, v := range violations {
+		res.Add(types.OrderRuleRisk, v)
+	}
+
+	if o.Type == types.TypeLimitOrder {
+		err = s.validator.ValidateAvailablExchangeBalance(&o)
+		if err != nil {
+			res.Add(types.OrderRuleBalance, err.Error())
+		}
+	}
+
+	return res, nil
+}
+
 // CancelOrder handles the cancellation order requests.
 // Only Orders which are OPEN or NEW i.e. Not yet filled/partially filled
 // can be cancelled
@@ -283,7 +401,8 @@ func (s *OrderService) CancelOrder(oc *types.OrderCancel) error {
 	if err != nil || o == nil {
 		return errors.New("No order with corresponding hash")
 	}
-	if o.Status == types.ORDER_FILLED || o.Status == types.ERROR_STATUS || o.Status == types.ORDER_CANCELLED {
+	if !types.CanTransitionOrderStatus(o.Status, types.OrderStatusCancelled) {
+		logger.Warning(&types.IllegalOrderTransitionError{From: o.Status, To: types.OrderStatusCancelled}, o.Hash.Hex())
 		return fmt.Errorf("Cannot cancel order. Status is %v", o.Status)
 	}
 
@@ -294,6 +413,11 @@ func (s *OrderService) CancelOrder(oc *types.OrderCancel) error {
 	o.UserAddress = oc.UserAddress
 	o.ExchangeAddress = oc.ExchangeAddress
 
+	err = s.admitCancel(o)
+	if err != nil {
+		return err
+	}
+
 	err = s.broker.PublishCancelOrderMessage(o)
 	if err != nil {
 		logger.Error(err)
@@ -303,6 +427,25 @@ func (s *OrderService) CancelOrder(oc *types.OrderCancel) error {
 	return nil
 }
 
+// admitCancel returns ErrPairQueueFull if the pair of the order is paused in queue mode and its queue is
+// full, the cancels are queued with the new orders
+func (s *OrderService) admitCancel(o *types.Order) error {
+	code, err := o.PairCode()
+	if err != nil {
+		return err
+	}
+
+	if !s.engine.IsPairQueueing(code) {
+		return nil
+	}
+
+	if _, ok := s.engine.AdmitOrder(code); !ok {
+		return ErrPairQueueFull
+	}
+
+	return nil
+}
+
 // CancelOrder handles the cancellation order requests.
 // Only Orders which are OPEN or NEW i.e. Not yet filled/partially filled
 // can be cancelled
@@ -319,6 +462,12 @@ func (s *OrderService) CancelAllOrder(a common.Address) error {
 	}
 
 	for _, o := range orders {
+		err = s.admitCancel(o)
+		if err != nil {
+			logger.Warning(o.Hash.Hex(), err)
+			continue
+		}
+
 		err = s.broker.PublishCancelOrderMessage(o)
 
 		if err != nil {
@@ -333,6 +482,11 @@ func (s *OrderService) CancelAllOrder(a common.Address) error {
 // HandleEngineResponse listens to messages incoming from the engine and handles websocket
 // responses and database updates accordingly
 func (s *OrderService) HandleEngineResponse(res *types.EngineResponse) error {
+	err := s.journal.Record(res)
+	if err != nil {
+		logger.Error("Record engine event", err)
+	}
+
 	switch res.Status {
 	case types.ORDER_ADDED:
 		s.handleEngineOrderAdded(res)
@@ -349,6 +503,9 @@ func (s *OrderService) HandleEngineResponse(res *types.EngineResponse) error {
 	case types.ORDER_FILLED:
 		s.handleOrderFilled(res)
 		break
+	case types.ORDER_FILLED_WITH_DUST:
+		s.handleOrderFilledWithDust(res)
+		break
 	case types.ERROR_STATUS:
 		s.handleEngineError(res)
 		break
@@ -408,7 +565,7 @@ func (s *OrderService) handleEngineOrderAdded(res *types.EngineResponse) {
 		logger.Error(err)
 	}
 
-	ws.SendOrderMessage("ORDER_ADDED", o.UserAddress, o)
+	ws.SendOrderMessage("ORDER_ADDED", o.UserAddress, s.attestation.Attest(types.AttestationOrderAdded, o))
 	ws.SendNotificationMessage("ORDER_ADDED", o.UserAddress, notifications)
 	s.updateOrderPricepoint(o)
 }
@@ -416,6 +573,7 @@ func (s *OrderService) handleEngineOrderAdded(res *types.EngineResponse) {
 func (s *OrderService) handleOrderPartialFilled(res *types.EngineResponse) {
 	logger.Info("BroadcastOrderBookUpdate PartialFilled")
 	s.updateOrderPricepoint(res.Order)
+	s.cancelDust(res.Order)
 }
 
 func (s *OrderService) handleOrderFilled(res *types.EngineResponse) {
@@ -423,8 +581,151 @@ func (s *OrderService) handleOrderFilled(res *types.EngineResponse) {
 	s.updateOrderPricepoint(res.Order)
 }
 
+// handleOrderFilledWithDust informs the client that the dust remainder of his order was cancelled
+func (s *OrderService) handleOrderFilledWithDust(res *types.EngineResponse) {
+	o := res.Order
+
+	notifications, err := s.notificationDao.Create(&types.Notification{
+		Recipient: o.UserAddress,
+		Message: types.Message{
+			MessageType: types.ORDER_FILLED_WITH_DUST,
+			Description: o.Hash.Hex(),
+		},
+		Type:   types.TypeLog,
+		Status: types.StatusUnread,
+	})
+
+	if err != nil {
+		logger.Error(err)
+	}
+
+	ws.SendOrderMessage(types.ORDER_FILLED_WITH_DUST, o.UserAddress, o)
+	ws.SendNotificationMessage(types.ORDER_FILLED_WITH_DUST, o.UserAddress, notifications)
+}
+
+// cancelDust cancels the remainder of a partially filled order below the dust threshold of its pair,
+// the order is marked FILLED_WITH_DUST once cancelled. TomoX only accepts the cancels signed by the
+// owner of the order, the remainder is left open if the SDK does not hold the wallet of the user
+func (s *OrderService) cancelDust(o *types.Order) {
+	if !o.IsDustRemainder(dustThreshold(o.PairName)) {
+		return
+	}
+
+	s.mutext.Lock()
+	if s.dustCancels[o.Hash] {
+		s.mutext.Unlock()
+		return
+	}
+	s.dustCancels[o.Hash] = true
+	s.mutext.Unlock()
+
+	err := s.publishDustCancel(o)
+	if err != nil {
+		logger.Warningf("Dust remainder of order %s not cancelled: %v", o.Hash.Hex(), err)
+
+		s.mutext.Lock()
+		delete(s.dustCancels, o.Hash)
+		s.mutext.Unlock()
+	}
+}
+
+// publishDustCancel signs a cancel of an order with the wallet of its owner and publishes it
+func (s *OrderService) publishDustCancel(o *types.Order) error {
+	wallet, err := s.walletDao.GetByAddress(o.UserAddress)
+	if err != nil {
+		return err
+	}
+
+	if wallet == nil {
+		return errors.New("the order can only be cancelled by its owner")
+	}
+
+	res, err := s.orderDao.GetOrderNonce(o.UserAddress)
+	if err != nil {
+		return err
+	}
+
+	nonce, err := decodeOrderNonce(res)
+	if err != nil {
+		return err
+	}
+
+	oc := &types.OrderCancel{
+		OrderHash:       o.Hash,
+		Nonce:           nonce,
+		OrderID:         o.OrderID,
+		Status:          types.OrderStatusCancelled,
+		UserAddress:     o.UserAddress,
+		ExchangeAddress: o.ExchangeAddress,
+	}
+
+	err = oc.Sign(wallet)
+	if err != nil {
+		return err
+	}
+
+	c := *o
+	c.Nonce = oc.Nonce
+	c.Signature = oc.Signature
+	c.Status = oc.Status
+
+	logger.Infof("Cancelling the dust remainder of order %s", o.Hash.Hex())
+	return s.broker.PublishCancelOrderMessage(&c)
+}
+
+// markDust marks an order cancelled by cancelDust as FILLED_WITH_DUST, the status change is streamed
+// back as an ORDER_FILLED_WITH_DUST response. It returns false for the orders cancelled by their owner
+func (s *OrderService) markDust(o *types.Order) bool {
+	s.mutext.Lock()
+	ok := s.dustCancels[o.Hash]
+	delete(s.dustCancels, o.Hash)
+	s.mutext.Unlock()
+
+	if !ok {
+		return false
+	}
+
+	err := s.orderDao.UpdateOrderStatus(o.Hash, types.OrderStatusFilledWithDust)
+	if err != nil {
+		logger.Error(err)
+		return false
+	}
+
+	return true
+}
+
+// decodeOrderNonce returns the order nonce read by OrderDao.GetOrderNonce
+func decodeOrderNonce(res interface{}) (*big.Int, error) {
+	switch v := res.(type) {
+	case string:
+		return hexutil.DecodeBig(v)
+	case float64:
+		return big.NewInt(int64(v)), nil
+	default:
+		return nil, fmt.Errorf("Invalid order nonce %v", res)
+	}
+}
+
+// dustThreshold returns the dust threshold of a pair, nil if it has none
+func dustThreshold(pairName string) *big.Int {
+	v, ok := app.Config.DustThresholds[pairName]
+	if !ok {
+		return nil
+	}
+
+	threshold, ok := new(big.Int).SetString(v, 10)
+	if !ok {
+		return nil
+	}
+
+	return threshold
+}
+
 func (s *OrderService) handleOrderCancelled(res *types.EngineResponse) {
 	o := res.Order
+	if s.markDust(o) {
+		return
+	}
 
 	// Save notification
 	notifications, err := s.notificationDao.Create(&types.Notification{
@@ -535,6 +836,22 @@ func (s *OrderService) broadcastRawOrderBookUpdate(orders []*types.Order) {
 	ws.GetRawOrderBookSocket().BroadcastMessage(id, orders)
 }
 
+// ApplyRelayerFees updates the fees of the pairs of a relayer whose fees changed on the relayer contract,
+// the balances of the new orders are then validated against the fees charged at settlement
+func (s *OrderService) ApplyRelayerFees(e *types.RelayerFeeEvent) {
+	err := s.pairDao.UpdateFeesByCoinbase(
+		e.Relayer,
+		big.NewInt(int64(e.PreviousMakeFee)),
+		big.NewInt(int64(e.PreviousTakeFee)),
+		big.NewInt(int64(e.MakeFee)),
+		big.NewInt(int64(e.TakeFee)),
+	)
+
+	if err != nil {
+		logger.Error(err)
+	}
+}
+
 // WatchChanges wath change record
 func (s *OrderService) WatchChanges() {
 	go func() {
@@ -631,9 +948,16 @@ func (s *OrderService) processBulkOrders() {
 
 // HandleDocumentType handle order frome changing db
 func (s *OrderService) HandleDocumentType(ev types.OrderChangeEvent) error {
-	if ev.FullDocument == nil {
+	if ev.FullDocument == nil || ev.IsPublicIDAssignment() {
 		return nil
 	}
+
+	if ev.FullDocument.PublicID == 0 {
+		err := s.orderDao.AssignPublicID(ev.FullDocument)
+		if err != nil {
+			logger.Error(err)
+		}
+	}
 	res := &types.EngineResponse{}
 	if ev.FullDocument.Status == types.OrderStatusOpen {
 		res.Status = types.ORDER_ADDED
@@ -650,6 +974,9 @@ func (s *OrderService) HandleDocumentType(ev types.OrderChangeEvent) error {
 	} else if ev.FullDocument.Status == types.OrderStatusPartialFilled {
 		res.Status = types.ORDER_PARTIALLY_FILLED
 		res.Order = ev.FullDocument
+	} else if ev.FullDocument.Status == types.OrderStatusFilledWithDust {
+		res.Status = types.ORDER_FILLED_WITH_DUST
+		res.Order = ev.FullDocument
 	}
 
 	if res.Status != "" {
@@ -663,6 +990,31 @@ func (s *OrderService) HandleDocumentType(ev types.OrderChangeEvent) error {
 	return nil
 }
 
+// HandleChainEvent applies an order cancellation read from the exchange contract by the chain listener,
+// the stored change is then broadcast through the change stream of the orders. The orders unknown
+// to the SDK or already cancelled are skipped, so that an event read twice is applied once
+func (s *OrderService) HandleChainEvent(ev *types.ChainEvent) error {
+	if ev.Type != types.ChainEventOrderCancelled {
+		return nil
+	}
+
+	o, err := s.orderDao.GetByHash(ev.OrderHash)
+	if err != nil {
+		return err
+	}
+
+	if o == nil || o.Status == types.OrderStatusCancelled {
+		return nil
+	}
+
+	if !types.CanTransitionOrderStatus(o.Status, types.OrderStatusCancelled) {
+		logger.Warning(&types.IllegalOrderTransitionError{From: o.Status, To: types.OrderStatusCancelled}, o.Hash.Hex())
+		return nil
+	}
+
+	return s.orderDao.UpdateOrderStatus(o.Hash, types.OrderStatusCancelled)
+}
+
 // GetOrderNonceByUserAddress return nonce of user order
 func (s *OrderService) GetOrderNonceByUserAddress(addr common.Address) (interface{}, error) {
 	return s.orderDao.GetOrderNonce(addr)
@@ -175,9 +175,14 @@ func (s *PairService) GetAll() ([]types.Pair, error) {
 	return s.pairDao.GetAll()
 }
 
-// GetAllByCoinbase get all pair by coinbase
-func (s *PairService) GetAllByCoinbase(addr common.Address) ([]types.Pair, error) {
-	return s.pairDao.GetAllByCoinbase(addr)
+// GetAllByCoinbase get all pair by coinbase, with only the fields if any
+func (s *PairService) GetAllByCoinbase(addr common.Address, fields ...string) ([]types.Pair, error) {
+	return s.pairDao.GetAllByCoinbase(addr, fields...)
+}
+
+// GetMarketStatus returns the order intake status of every pair
+func (s *PairService) GetMarketStatus() ([]*types.MarketStatus, error) {
+	return s.eng.GetMarketStatus()
 }
 
 // GetTokenPairData get tick of a token pair
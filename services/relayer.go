@@ -3,6 +3,8 @@ package services
 import (
 	"math/big"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/tomochain/tomox-sdk/relayer"
 
@@ -10,8 +12,22 @@ import (
 	"github.com/tomochain/tomox-sdk/app"
 	"github.com/tomochain/tomox-sdk/interfaces"
 	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils"
+	"github.com/tomochain/tomox-sdk/ws"
 )
 
+// relayerEventsRetryInterval is the default delay before subscribing again to the relayer events after a failure
+const relayerEventsRetryInterval = 10 * time.Second
+
+// relayerEventsWatcher is the name of the last processed block of the relayer events in the config collection
+const relayerEventsWatcher = "relayer_events"
+
+// defaultRelayerFeeInterval is the default time between two reads of the fees of the relayers
+const defaultRelayerFeeInterval = time.Minute
+
+// startedAt is the start time of the SDK, the uptime of the relayer info is counted from it
+var startedAt = time.Now()
+
 // RelayerService struct
 type RelayerService struct {
 	relayer           interfaces.Relayer
@@ -21,6 +37,13 @@ type RelayerService struct {
 	pairDao           interfaces.PairDao
 	lendingPairDao    interfaces.LendingPairDao
 	relayerDao        interfaces.RelayerDao
+	listingService    interfaces.ListingService
+	safetyService     interfaces.TokenSafetyService
+	engine            interfaces.Engine
+	configDao         interfaces.ConfigDao
+	feeMutex          sync.Mutex
+	fees              map[common.Address][2]uint16
+	feeListeners      []func(*types.RelayerFeeEvent)
 }
 
 // NewRelayerService returns a new instance of orderservice
@@ -32,15 +55,24 @@ func NewRelayerService(
 	pairDao interfaces.PairDao,
 	lendingPairDao interfaces.LendingPairDao,
 	relayerDao interfaces.RelayerDao,
+	listingService interfaces.ListingService,
+	safetyService interfaces.TokenSafetyService,
+	engine interfaces.Engine,
+	configDao interfaces.ConfigDao,
 ) *RelayerService {
 	return &RelayerService{
-		relaye,
-		tokenDao,
-		colateralTokenDao,
-		lendingTokenDao,
-		pairDao,
-		lendingPairDao,
-		relayerDao,
+		relayer:           relaye,
+		tokenDao:          tokenDao,
+		colateralTokenDao: colateralTokenDao,
+		lendingTokenDao:   lendingTokenDao,
+		pairDao:           pairDao,
+		lendingPairDao:    lendingPairDao,
+		relayerDao:        relayerDao,
+		listingService:    listingService,
+		safetyService:     safetyService,
+		engine:            engine,
+		configDao:         configDao,
+		fees:              make(map[common.Address][2]uint16),
 	}
 }
 
@@ -52,6 +84,17 @@ func (s *RelayerService) GetAll() ([]types.Relayer, error) {
 	return s.relayerDao.GetAll()
 }
 
+// InvalidateTokenInfo drops the cached metadata of the tokens, of all of them if none is given
+func (s *RelayerService) InvalidateTokenInfo(tokens ...common.Address) {
+	s.relayer.InvalidateTokenInfo(tokens...)
+	if len(tokens) == 0 {
+		logger.Info("Token info cache invalidated")
+		return
+	}
+
+	logger.Infof("Token info cache invalidated for %d tokens", len(tokens))
+}
+
 func (s *RelayerService) UpdateNameByAddress(addr common.Address, name string, url string) error {
 	return s.relayerDao.UpdateNameByAddress(addr, name, url)
 }
@@ -74,6 +117,61 @@ func (s *RelayerService) GetRelayerAddress(r *http.Request) common.Address {
 	return common.HexToAddress(relayerAddress)
 }
 
+// CreatePair creates a pair with the parameters of an operator. The pair must be registered on
+// the relayer contract, the token symbols and decimals are read from the contract
+func (s *RelayerService) CreatePair(p *types.Pair) error {
+	existing, err := s.pairDao.GetByTokenAddress(p.BaseTokenAddress, p.QuoteTokenAddress)
+	if err != nil {
+		return err
+	}
+
+	if existing != nil {
+		return ErrPairExists
+	}
+
+	relayerInfo, err := s.relayer.GetRelayer(p.RelayerAddress)
+	if err != nil {
+		return err
+	}
+
+	base := relayerInfo.Tokens[p.BaseTokenAddress]
+	if base == nil {
+		return ErrBaseTokenNotFound
+	}
+
+	quote := relayerInfo.Tokens[p.QuoteTokenAddress]
+	if quote == nil {
+		return ErrQuoteTokenNotFound
+	}
+
+	registered := false
+	for _, pair := range relayerInfo.Pairs {
+		if pair.BaseToken == p.BaseTokenAddress && pair.QuoteToken == p.QuoteTokenAddress {
+			registered = true
+		}
+	}
+
+	if !registered {
+		return ErrPairNotRegistered
+	}
+
+	p.BaseTokenSymbol = base.Symbol
+	p.BaseTokenDecimals = int(base.Decimals)
+	p.QuoteTokenSymbol = quote.Symbol
+	p.QuoteTokenDecimals = int(quote.Decimals)
+
+	if p.MakeFee == nil {
+		p.MakeFee = big.NewInt(int64(relayerInfo.MakeFee))
+	}
+
+	if p.TakeFee == nil {
+		p.TakeFee = big.NewInt(int64(relayerInfo.TakeFee))
+	}
+
+	logger.Info("Create Pair:", p.BaseTokenAddress.Hex(), p.QuoteTokenAddress.Hex(), p.RelayerAddress.Hex())
+	return s.pairDao.Create(p)
+}
+
 func (s *RelayerService) updatePairRelayer(relayerInfo *relayer.RInfo) error {
 	currentPairs, err := s.pairDao.GetAllByCoinbase(relayerInfo.Address)
 	logger.Info("UpdatePairRelayer starting...", relayerInfo.Address.Hex())
@@ -89,25 +187,7 @@ func (s *RelayerService) updatePairRelayer(relayerInfo *relayer.RInfo) error {
 			}
 		}
 		if !found {
-			pairBaseData := relayerInfo.Tokens[newpair.BaseToken]
-			pairQuoteData := relayerInfo.Tokens[newpair.QuoteToken]
-			pair := &types.Pair{
-				BaseTokenSymbol:    pairBaseData.Symbol,
-				BaseTokenAddress:   newpair.BaseToken,
-				BaseTokenDecimals:  int(pairBaseData.Decimals),
-				QuoteTokenSymbol:   pairQuoteData.Symbol,
-				QuoteTokenAddress:  newpair.QuoteToken,
-				QuoteTokenDecimals: int(pairQuoteData.Decimals),
-				RelayerAddress:     relayerInfo.Address,
-				Active:             true,
-				MakeFee:            big.NewInt(int64(relayerInfo.MakeFee)),
-				TakeFee:            big.NewInt(int64(relayerInfo.TakeFee)),
-			}
-			logger.Info("Create Pair:", pair.BaseTokenAddress.Hex(), pair.QuoteTokenAddress.Hex(), relayerInfo.Address.Hex())
-			err := s.pairDao.Create(pair)
-			if err != nil {
-				logger.Error(err)
-			}
+			s.createRelayerPair(relayerInfo, newpair)
 		}
 	}
 
@@ -129,6 +209,35 @@ func (s *RelayerService) updatePairRelayer(relayerInfo *relayer.RInfo) error {
 	return nil
 }
 
+// createRelayerPair creates a pair registered by a relayer, held in review if the listing requirements are enabled
+func (s *RelayerService) createRelayerPair(relayerInfo *relayer.RInfo, newpair *relayer.PairToken) {
+	pairBaseData := relayerInfo.Tokens[newpair.BaseToken]
+	pairQuoteData := relayerInfo.Tokens[newpair.QuoteToken]
+	pair := &types.Pair{
+		BaseTokenSymbol:    pairBaseData.Symbol,
+		BaseTokenAddress:   newpair.BaseToken,
+		BaseTokenDecimals:  int(pairBaseData.Decimals),
+		QuoteTokenSymbol:   pairQuoteData.Symbol,
+		QuoteTokenAddress:  newpair.QuoteToken,
+		QuoteTokenDecimals: int(pairQuoteData.Decimals),
+		RelayerAddress:     relayerInfo.Address,
+		Active:             true,
+		MakeFee:            big.NewInt(int64(relayerInfo.MakeFee)),
+		TakeFee:            big.NewInt(int64(relayerInfo.TakeFee)),
+	}
+	err := s.listingService.Hold(pair)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	logger.Info("Create Pair:", pair.BaseTokenAddress.Hex(), pair.QuoteTokenAddress.Hex(), relayerInfo.Address.Hex())
+	err = s.pairDao.Create(pair)
+	if err != nil {
+		logger.Error(err)
+	}
+}
+
 func (s *RelayerService) updateLendingPair(relayerInfo *relayer.LendingRInfo) error {
 	currentPairs, err := s.lendingPairDao.GetAllByCoinbase(relayerInfo.Address)
 	logger.Info("UpdateLendingPairRelayer starting...", relayerInfo.Address.Hex(), len(relayerInfo.LendingPairs), len(currentPairs))
@@ -208,6 +317,7 @@ func (s *RelayerService) updateRelayers(relayerInfos []*relayer.RInfo, lendingRe
 			Address:    r.Address,
 			Resign:     r.Resign,
 			LockTime:   r.LockTime,
+			Status:     r.Status,
 			MakeFee:    big.NewInt(int64(r.MakeFee)),
 			TakeFee:    big.NewInt(int64(r.TakeFee)),
 			LendingFee: big.NewInt(int64(lendingFee)),
@@ -265,6 +375,7 @@ func (s *RelayerService) updateRelayer(relayerInfo *relayer.RInfo, lendingRelaye
 		Address:    relayerInfo.Address,
 		Resign:     relayerInfo.Resign,
 		LockTime:   relayerInfo.LockTime,
+		Status:     relayerInfo.Status,
 		MakeFee:    big.NewInt(int64(relayerInfo.MakeFee)),
 		TakeFee:    big.NewInt(int64(relayerInfo.TakeFee)),
 		LendingFee: big.NewInt(int64(lendingFee)),
@@ -307,12 +418,20 @@ func (s *RelayerService) updateTokenRelayer(relayerInfo *relayer.RInfo) error {
 			Decimals:        int(v.Decimals),
 			MakeFee:         big.NewInt(int64(relayerInfo.MakeFee)),
 			TakeFee:         big.NewInt(int64(relayerInfo.TakeFee)),
+			TRC21:           v.TRC21,
+			Issuer:          v.Issuer,
+			MinFee:          v.MinFee,
 		}
 		if !found {
 			logger.Info("Create Token:", token.ContractAddress.Hex())
 			err = s.tokenDao.Create(token)
 			if err != nil {
 				logger.Error(err)
+			} else if s.safetyService != nil && !utils.IsNativeTokenByAddress(ntoken) {
+				_, err = s.safetyService.Check(ntoken)
+				if err != nil {
+					logger.Warning("Token safety check failed:", ntoken.Hex(), err)
+				}
 			}
 		} else {
 			logger.Info("Update Token:", token.ContractAddress.Hex())
@@ -358,6 +477,7 @@ func (s *RelayerService) updateCollateralTokenRelayer(relayerInfo *relayer.Lendi
 			Decimals:        int(v.Decimals),
 			MakeFee:         big.NewInt(int64(relayerInfo.Fee)),
 			TakeFee:         big.NewInt(int64(relayerInfo.Fee)),
+			Collateral:      collateralParams(relayerInfo.Collaterals[ntoken]),
 		}
 		if !found {
 			logger.Info("Create collateral token:", token.ContractAddress.Hex())
@@ -389,6 +509,27 @@ func (s *RelayerService) updateCollateralTokenRelayer(relayerInfo *relayer.Lendi
 	return nil
 }
 
+// collateralParams returns the lending parameters of a collateral token stored with it
+func collateralParams(c *relayer.CollateralInfo) *types.Collateral {
+	if c == nil {
+		return nil
+	}
+
+	res := &types.Collateral{
+		DepositRate:     c.DepositRate.String(),
+		LiquidationRate: c.LiquidationRate.String(),
+		Prices:          make(map[string]string),
+	}
+
+	for lendingToken, price := range c.Prices {
+		if price != nil {
+			res.Prices[lendingToken.Hex()] = price.String()
+		}
+	}
+
+	return res
+}
+
 func (s *RelayerService) updateLendingTokenRelayer(relayerInfo *relayer.LendingRInfo) error {
 	currentTokens, err := s.lendingTokenDao.GetAllByCoinbase(relayerInfo.Address)
 	if err != nil {
@@ -440,6 +581,304 @@ func (s *RelayerService) updateLendingTokenRelayer(relayerInfo *relayer.LendingR
 	return nil
 }
 
+// GetAggregated returns the merged view of the tokens and the pairs of the relayers of the "relayers"
+// setting with the fees of each of them, the relayer of the exchange address by default
+func (s *RelayerService) GetAggregated() (*relayer.AggregatedRInfo, error) {
+	return s.relayer.GetRelayers(operatedCoinbases())
+}
+
+// operatedCoinbases returns the coinbases of the "relayers" setting, the exchange address by default
+func operatedCoinbases() []common.Address {
+	coinbases := []common.Address{}
+	for _, a := range app.Config.Relayers {
+		coinbases = append(coinbases, common.HexToAddress(a))
+	}
+
+	if len(coinbases) == 0 {
+		coinbases = append(coinbases, common.HexToAddress(app.Config.Tomochain["exchange_address"]))
+	}
+
+	return coinbases
+}
+
+// OnFeesChanged registers a function called with the fee changes of the relayers, before they are
+// broadcast to the websocket clients
+func (s *RelayerService) OnFeesChanged(fn func(*types.RelayerFeeEvent)) {
+	s.feeMutex.Lock()
+	defer s.feeMutex.Unlock()
+	s.feeListeners = append(s.feeListeners, fn)
+}
+
+// FeeInterval returns the time between two reads of the fees of the relayers
+func (s *RelayerService) FeeInterval() time.Duration {
+	if interval := app.Config.RelayerEvents["fee_interval"]; interval > 0 {
+		return time.Duration(interval) * time.Second
+	}
+
+	return defaultRelayerFeeInterval
+}
+
+// WatchFees reads the fees of the relayers of the "relayers" setting from the relayer contract and
+// propagates their changes. It runs periodically, for the changes missed by the relayer events
+func (s *RelayerService) WatchFees() error {
+	for _, coinbase := range operatedCoinbases() {
+		info, err := s.relayer.GetRelayer(coinbase)
+		if err != nil {
+			return err
+		}
+
+		s.checkFees(info)
+	}
+
+	return nil
+}
+
+// checkFees propagates the fees of a relayer when they changed since they were last seen, or since they
+// were last synced to the database for the first check. The listeners are called first so that the
+// orders are validated against the new fees when the websocket clients display them
+func (s *RelayerService) checkFees(info *relayer.RInfo) {
+	cur := [2]uint16{info.MakeFee, info.TakeFee}
+
+	s.feeMutex.Lock()
+	prev, ok := s.fees[info.Address]
+	s.fees[info.Address] = cur
+	listeners := s.feeListeners
+	s.feeMutex.Unlock()
+
+	if !ok {
+		r, err := s.relayerDao.GetByAddress(info.Address)
+		if err != nil || r == nil || r.MakeFee == nil || r.TakeFee == nil {
+			return
+		}
+
+		prev = [2]uint16{uint16(r.MakeFee.Uint64()), uint16(r.TakeFee.Uint64())}
+	}
+
+	if prev == cur {
+		return
+	}
+
+	e := &types.RelayerFeeEvent{
+		Relayer:         info.Address,
+		MakeFee:         cur[0],
+		TakeFee:         cur[1],
+		PreviousMakeFee: prev[0],
+		PreviousTakeFee: prev[1],
+		UpdatedAt:       time.Now(),
+	}
+
+	logger.Infof("Relayer %s fees changed: make fee %d to %d, take fee %d to %d", info.Address.Hex(), prev[0], cur[0], prev[1], cur[1])
+	for _, fn := range listeners {
+		fn(e)
+	}
+
+	ws.GetSystemStatusSocket().BroadcastMessage(ws.SystemStatusChannel, e)
+}
+
+// GetDeposit returns the deposit locked by a relayer on the registration contract
+func (s *RelayerService) GetDeposit(coinbase common.Address) (*relayer.RelayerDeposit, error) {
+	d, err := s.relayer.GetRelayerDeposit(coinbase)
+	if err != nil {
+		return nil, err
+	}
+
+	if (d.Owner == common.Address{}) {
+		return nil, ErrRelayerNotRegistered
+	}
+
+	return d, nil
+}
+
+// GetByOwner returns the relayers registered by an owner on the registration contract, for the operators
+// of several coinbases
+func (s *RelayerService) GetByOwner(owner common.Address) ([]*relayer.RInfo, error) {
+	return s.relayer.GetRelayersByOwner(owner)
+}
+
+// GetByID returns the relayer at an index of the registration contract
+func (s *RelayerService) GetByID(id int64) (*relayer.RInfo, error) {
+	info, err := s.relayer.GetRelayerByID(id)
+	if err == relayer.ErrRelayerNotFound {
+		return nil, ErrRelayerNotRegistered
+	}
+
+	return info, err
+}
+
+// GetStateAt returns the spot and the lending registrations of a relayer, with its tokens, pairs and fees,
+// at a past block, the latest one for the zero BlockRef
+func (s *RelayerService) GetStateAt(coinbase common.Address, block relayer.BlockRef) (*relayer.RelayerSnapshot, error) {
+	snapshot, err := s.relayer.GetFullRelayerSnapshotAt(coinbase, block)
+	if err == relayer.ErrRelayerNotFound {
+		return nil, ErrRelayerNotRegistered
+	}
+
+	return snapshot, err
+}
+
+// GetInfo returns the metadata of the venue of a relayer coinbase, the branding of the relayer_branding
+// setting with the fees and the listings read from the relayer contract. The last ones synced to the
+// database are returned when the chain cannot be read
+func (s *RelayerService) GetInfo(coinbase common.Address) (*types.RelayerInfo, error) {
+	branding := app.Config.RelayerBranding
+	info := &types.RelayerInfo{
+		Name:         branding["name"],
+		Logo:         branding["logo_url"],
+		SupportEmail: branding["support_email"],
+		Links:        map[string]string{},
+		Coinbase:     coinbase,
+		StartedAt:    startedAt,
+		Uptime:       int64(time.Since(startedAt) / time.Second),
+	}
+
+	for _, l := range types.RelayerBrandingLinks {
+		if u := branding[l+"_url"]; u != "" {
+			info.Links[l] = u
+		}
+	}
+
+	relayerInfo, err := s.relayer.GetRelayer(coinbase)
+	if err == nil {
+		info.MakeFee = relayerInfo.MakeFee
+		info.TakeFee = relayerInfo.TakeFee
+		info.Tokens = len(relayerInfo.Tokens)
+		info.Pairs = len(relayerInfo.Pairs)
+	} else {
+		logger.Warning("Relayer info of", coinbase.Hex(), "read from the database:", err)
+		relayerInfo = nil
+
+		tokens, err := s.tokenDao.GetAllByCoinbase(coinbase)
+		if err != nil {
+			return nil, err
+		}
+
+		pairs, err := s.pairDao.GetAllByCoinbase(coinbase)
+		if err != nil {
+			return nil, err
+		}
+
+		info.Tokens = len(tokens)
+		info.Pairs = len(pairs)
+	}
+
+	r, err := s.relayerDao.GetByAddress(coinbase)
+	if err != nil {
+		return nil, err
+	}
+
+	if r != nil {
+		if info.Name == "" {
+			info.Name = r.Name
+		}
+
+		if relayerInfo == nil && r.MakeFee != nil && r.TakeFee != nil {
+			info.MakeFee = uint16(r.MakeFee.Uint64())
+			info.TakeFee = uint16(r.TakeFee.Uint64())
+		}
+	}
+
+	active, err := s.pairDao.GetActivePairsByCoinbase(coinbase)
+	if err != nil {
+		return nil, err
+	}
+
+	info.ActivePairs = len(active)
+	return info, nil
+}
+
+// EventsEnabled returns true if the relayers are followed from the events of the registration contract,
+// the full sync then only reconciles the changes missed
+func (s *RelayerService) EventsEnabled() bool {
+	return app.Config.RelayerEvents["enabled"] > 0
+}
+
+// WatchRelayers applies the changes of the relayers from the events of the registration contract.
+// The subscription is restored whenever it fails, the events missed meanwhile are read from the last
+// processed block saved in the config collection, so that no change is missed
+func (s *RelayerService) WatchRelayers() {
+	retry := time.Duration(app.Config.RelayerEvents["retry"]) * time.Second
+	if retry <= 0 {
+		retry = relayerEventsRetryInterval
+	}
+
+	known := make(map[common.Address]*relayer.RInfo)
+	cursor := &watcherCursor{s.configDao, relayerEventsWatcher}
+	for {
+		err := s.relayer.WatchRelayers(known, cursor, s.applyRelayerDiff)
+		if err != nil {
+			logger.Error("Relayer events subscription failed:", err)
+		}
+
+		time.Sleep(retry)
+	}
+}
+
+// applyRelayerDiff creates and deletes the tokens and the pairs of a relayer changed by an event
+func (s *RelayerService) applyRelayerDiff(d *relayer.RInfoDiff) {
+	logger.Infof(
+		"Relayer %s changed: %d tokens added, %d removed, %d pairs added, %d removed",
+		d.Address.Hex(), len(d.AddedTokens), len(d.RemovedTokens), len(d.AddedPairs), len(d.RemovedPairs),
+	)
+
+	if d.StatusChanged {
+		s.checkRelayerStatus(d.Relayer)
+	}
+
+	if d.FeesChanged {
+		s.checkFees(d.Relayer)
+	}
+
+	if len(d.AddedTokens) > 0 || len(d.RemovedTokens) > 0 {
+		err := s.updateTokenRelayer(d.Relayer)
+		if err != nil {
+			logger.Error(err)
+		}
+	}
+
+	currentPairs, err := s.pairDao.GetAllByCoinbase(d.Address)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	for _, p := range d.AddedPairs {
+		found := false
+		for _, currentPair := range currentPairs {
+			if p.BaseToken == currentPair.BaseTokenAddress && p.QuoteToken == currentPair.QuoteTokenAddress {
+				found = true
+			}
+		}
+
+		if !found {
+			s.createRelayerPair(d.Relayer, p)
+		}
+	}
+
+	for _, p := range d.RemovedPairs {
+		logger.Info("Delete Pair:", p.BaseToken.Hex(), p.QuoteToken.Hex())
+		err := s.pairDao.DeleteByTokenAndCoinbase(p.BaseToken, p.QuoteToken, d.Address)
+		if err != nil {
+			logger.Error(err)
+		}
+	}
+}
+
+// checkRelayerStatus stops the order intake of the pairs of a relayer which resigned,
+// and resumes it if the relayer is active again
+func (s *RelayerService) checkRelayerStatus(relayerInfo *relayer.RInfo) {
+	var err error
+	switch relayerInfo.Status {
+	case relayer.RelayerStatusResigning, relayer.RelayerStatusExpired:
+		err = s.engine.SuspendRelayer(relayerInfo.Address, relayerInfo.Status)
+	case relayer.RelayerStatusActive:
+		err = s.engine.ResumeRelayer(relayerInfo.Address, relayerInfo.Status)
+	}
+
+	if err != nil {
+		logger.Error(err)
+	}
+}
+
 // UpdateRelayer get the total number of orders amount created by a user
 func (s *RelayerService) UpdateRelayer(coinbase common.Address) error {
 	relayerInfo, err := s.relayer.GetRelayer(coinbase)
@@ -448,6 +887,8 @@ func (s *RelayerService) UpdateRelayer(coinbase common.Address) error {
 	}
 	s.updateTokenRelayer(relayerInfo)
 	s.updatePairRelayer(relayerInfo)
+	s.checkRelayerStatus(relayerInfo)
+	s.checkFees(relayerInfo)
 
 	relayerLendingInfo, err := s.relayer.GetLending()
 	if err != nil {
@@ -461,13 +902,17 @@ func (s *RelayerService) UpdateRelayer(coinbase common.Address) error {
 }
 
 func (s *RelayerService) UpdateRelayers() error {
-	relayerInfos, err := s.relayer.GetRelayers()
+	aggregated, err := s.relayer.GetRelayers(nil)
 	if err != nil {
 		return err
 	}
+
+	relayerInfos := aggregated.Relayers
 	for _, relayerInfo := range relayerInfos {
 		s.updateTokenRelayer(relayerInfo)
 		s.updatePairRelayer(relayerInfo)
+		s.checkRelayerStatus(relayerInfo)
+		s.checkFees(relayerInfo)
 	}
 
 	relayerLendingInfos, err := s.relayer.GetLendings()
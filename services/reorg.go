@@ -0,0 +1,227 @@
+package services
+
+import (
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	eth "github.com/ethereum/go-ethereum/core/types"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils"
+	"github.com/tomochain/tomox-sdk/ws"
+)
+
+// ReorgService detects the reorganizations of the chain from the hashes of the last blocks. The trades
+// settled in the replaced blocks are checked again: the ones whose settlement was included in the new
+// branch are re-applied at their new block, the other ones are reverted with their order fills, the
+// balances of their parties and the candles of their pairs. The corrections are sent to the subscribers.
+// The lending trades are not rolled back
+type ReorgService struct {
+	provider     interfaces.EthereumProvider
+	finalityDao  interfaces.FinalityDao
+	tradeDao     interfaces.TradeDao
+	orderDao     interfaces.OrderDao
+	pairDao      interfaces.PairDao
+	accountDao   interfaces.AccountDao
+	ohlcvService interfaces.OHLCVService
+	hashes       *types.BlockHashes
+	mutex        sync.Mutex
+}
+
+// NewReorgService returns a new instance of ReorgService following the heads of the block service
+func NewReorgService(
+	blockService interfaces.BlockService,
+	provider interfaces.EthereumProvider,
+	finalityDao interfaces.FinalityDao,
+	tradeDao interfaces.TradeDao,
+	orderDao interfaces.OrderDao,
+	pairDao interfaces.PairDao,
+	accountDao interfaces.AccountDao,
+	ohlcvService interfaces.OHLCVService,
+) *ReorgService {
+	s := &ReorgService{
+		provider:     provider,
+		finalityDao:  finalityDao,
+		tradeDao:     tradeDao,
+		orderDao:     orderDao,
+		pairDao:      pairDao,
+		accountDao:   accountDao,
+		ohlcvService: ohlcvService,
+		hashes:       types.NewBlockHashes(app.Config.ReorgDepth),
+	}
+
+	blockService.RegisterNotify(s.HandleNewBlock)
+	return s
+}
+
+// HandleNewBlock records a new head and rolls back the trades of the replaced blocks if it forked
+func (s *ReorgService) HandleNewBlock(block *types.BlockHeader) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	oldHead := s.hashes.Head()
+	if !s.hashes.Add(block) {
+		return
+	}
+
+	ancestor, complete, err := s.hashes.Fork(block, s.canonicalHash)
+	if err != nil {
+		logger.Error("Common ancestor of the chain reorganization not found:", err)
+		return
+	}
+
+	if !complete {
+		logger.Warningf("Chain reorganization deeper than the known blocks, rolling back the blocks above %d", ancestor)
+	}
+
+	r := &types.Reorg{
+		OldHead:        oldHead,
+		NewHead:        block,
+		CommonAncestor: ancestor,
+		Reverted:       []common.Hash{},
+		Reapplied:      []common.Hash{},
+		DetectedAt:     time.Now(),
+	}
+
+	if oldHead > ancestor {
+		r.Depth = oldHead - ancestor
+	}
+
+	logger.Warningf("Chain reorganization at block %d, %d blocks replaced", block.Number, r.Depth)
+	s.rollback(r)
+
+	id := utils.GetBlockChannelID(ws.BlockChannel)
+	ws.GetBlockSocket().BroadcastReorg(id, r)
+}
+
+func (s *ReorgService) canonicalHash(number uint64) (common.Hash, error) {
+	h, err := s.provider.HeaderByNumber(new(big.Int).SetUint64(number))
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	return h.Hash(), nil
+}
+
+// rollback checks the settlement of the trades whose receipt was in the blocks above the common ancestor
+func (s *ReorgService) rollback(r *types.Reorg) {
+	records, err := s.finalityDao.GetAboveBlock(r.CommonAncestor)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	reverted := []*types.Trade{}
+	for _, rec := range records {
+		if rec.Action != types.FinalityActionTrade {
+			continue
+		}
+
+		t, err := s.tradeDao.GetByHash(rec.Hash)
+		if err != nil || t == nil {
+			logger.Error("Can not find trade of the reorganized block", rec.Hash.Hex(), err)
+			continue
+		}
+
+		receipt, block, err := s.receipt(rec, t)
+		if err != nil {
+			logger.Error("Settlement of trade", t.Hash.Hex(), "not checked:", err)
+			continue
+		}
+
+		if receipt != nil && receipt.Status == eth.ReceiptStatusSuccessful {
+			rec.Rebase(block, r.NewHead.Number)
+			err := s.finalityDao.UpdateBlock(rec)
+			if err != nil {
+				logger.Error(err)
+			}
+
+			r.Reapplied = append(r.Reapplied, t.Hash)
+			continue
+		}
+
+		err = s.revertTrade(t)
+		if err != nil {
+			logger.Error("Trade not reverted:", t.Hash.Hex(), err)
+			continue
+		}
+
+		reverted = append(reverted, t)
+		r.Reverted = append(r.Reverted, t.Hash)
+	}
+
+	if len(reverted) > 0 {
+		s.ohlcvService.RevertTrades(reverted)
+	}
+}
+
+// receipt returns the receipt of the settlement of a trade in the canonical chain with its block, nil if it
+// is not there
+func (s *ReorgService) receipt(rec *types.FinalityRecord, t *types.Trade) (*eth.Receipt, uint64, error) {
+	txHash := rec.TxHash
+	if (txHash == common.Hash{}) {
+		txHash = t.TxHash
+	}
+
+	if (txHash == common.Hash{}) {
+		return nil, 0, nil
+	}
+
+	return s.provider.TransactionReceiptBlock(txHash)
+}
+
+// revertTrade moves a trade whose settlement left the chain back to pending, removes its amount from the
+// fills of its orders and reads the balances of its parties again. Its finality record is deleted, the
+// trade is tracked again once settled again
+func (s *ReorgService) revertTrade(t *types.Trade) error {
+	err := s.tradeDao.UpdateTradeStatus(t.Hash, types.TradeStatusPending)
+	if err != nil {
+		return err
+	}
+
+	err = s.finalityDao.DeleteByHash(t.Hash)
+	if err != nil {
+		return err
+	}
+
+	// UpdateOrderFilledAmounts does not match the amounts to the orders in the order it reads them,
+	// the orders are updated one at a time
+	for _, h := range []common.Hash{t.MakerOrderHash, t.TakerOrderHash} {
+		_, err := s.orderDao.UpdateOrderFilledAmounts([]common.Hash{h}, []*big.Int{t.Amount})
+		if err != nil {
+			logger.Error(err)
+		}
+	}
+
+	s.refreshBalances(t)
+
+	t.Status = types.TradeStatusPending
+	ws.SendOrderMessage("TRADE_REVERTED", t.Maker, t)
+	ws.SendOrderMessage("TRADE_REVERTED", t.Taker, t)
+	return nil
+}
+
+// refreshBalances stores the balances of the parties of a trade read from the canonical chain
+func (s *ReorgService) refreshBalances(t *types.Trade) {
+	p, err := s.pairDao.GetByTokenAddress(t.BaseToken, t.QuoteToken)
+	if err != nil || p == nil {
+		logger.Warningf("Pair of trade %s not found, balances not refreshed", t.Hash.Hex())
+		return
+	}
+
+	for _, b := range types.AddTradeBalanceChanges(nil, t, p) {
+		balance, err := s.provider.Balance(b.Address, b.Token)
+		if err != nil {
+			logger.Error(err)
+			continue
+		}
+
+		err = s.accountDao.UpdateBalance(b.Address, b.Token, balance)
+		if err != nil {
+			logger.Error(err)
+		}
+	}
+}
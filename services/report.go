@@ -0,0 +1,148 @@
+package services
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/math"
+)
+
+// maxReportDays limits the period of the reports
+const maxReportDays = 366
+
+// ReportService maintains the daily aggregates of the trades in dedicated collections
+// and serves the reports from them, the trades are never scanned at request time. The makers of the pairs
+// with a rebate program are owed the rebate of their tier, given by their volume of the previous days
+type ReportService struct {
+	tradeDao      interfaces.TradeDao
+	pairDao       interfaces.PairDao
+	dailyStatsDao interfaces.DailyStatsDao
+	rebates       types.RebateSchedule
+}
+
+// NewReportService returns a new instance of ReportService
+func NewReportService(
+	tradeDao interfaces.TradeDao,
+	pairDao interfaces.PairDao,
+	dailyStatsDao interfaces.DailyStatsDao,
+) *ReportService {
+	rebates, err := types.NewRebateSchedule(app.Config.MakerRebates)
+	if err != nil {
+		logger.Error("Maker rebates disabled:", err)
+		rebates = types.RebateSchedule{}
+	}
+
+	return &ReportService{
+		tradeDao:      tradeDao,
+		pairDao:       pairDao,
+		dailyStatsDao: dailyStatsDao,
+		rebates:       rebates,
+	}
+}
+
+// Refresh recomputes the aggregates of the UTC day of a time from its trades
+func (s *ReportService) Refresh(day time.Time) error {
+	day = day.UTC()
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 1)
+
+	pairs, err := s.pairDao.GetAll()
+	if err != nil {
+		return err
+	}
+
+	a := types.NewDailyAggregates(start)
+	for i := range pairs {
+		p := &pairs[i]
+		trades, err := s.tradeDao.GetByPairAndPeriod(p.BaseTokenAddress, p.QuoteTokenAddress, start, end)
+		if err != nil {
+			return err
+		}
+
+		tiers, err := s.rebateTiers(p, trades, start)
+		if err != nil {
+			return err
+		}
+
+		a.AddPairTrades(p, trades, tiers)
+	}
+
+	return s.dailyStatsDao.Save(a)
+}
+
+// rebateTiers returns the rebate tiers of the makers of the trades of a pair during a day, from their quote
+// volume on the pair during the previous days. Nil is returned for a pair without rebate program
+func (s *ReportService) rebateTiers(p *types.Pair, trades []*types.Trade, day time.Time) (map[common.Address]*types.RebateTier, error) {
+	if len(s.rebates[p.Name()]) == 0 {
+		return nil, nil
+	}
+
+	from := day.AddDate(0, 0, -types.RebateVolumeDays).Format(types.DailyStatsDateLayout)
+	to := day.AddDate(0, 0, -1).Format(types.DailyStatsDateLayout)
+
+	tiers := make(map[common.Address]*types.RebateTier)
+	for _, t := range trades {
+		if _, ok := tiers[t.Maker]; ok {
+			continue
+		}
+
+		volumes, err := s.dailyStatsDao.GetUserVolumes(t.Maker, from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		volume := big.NewInt(0)
+		for _, v := range volumes {
+			if v.BaseToken == p.BaseTokenAddress && v.QuoteToken == p.QuoteTokenAddress {
+				volume = math.Add(volume, v.QuoteVolume)
+			}
+		}
+
+		tiers[t.Maker] = s.rebates.Tier(p.Name(), volume)
+	}
+
+	return tiers, nil
+}
+
+// GetUserVolumes returns the daily volumes of a user between two days included
+func (s *ReportService) GetUserVolumes(addr common.Address, from, to time.Time) ([]*types.UserDailyVolume, error) {
+	f, t, err := reportPeriod(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.dailyStatsDao.GetUserVolumes(addr, f, t)
+}
+
+// GetPairStats returns the daily stats of a pair between two days included,
+// the stats of all the pairs if the tokens are zero addresses
+func (s *ReportService) GetPairStats(bt, qt common.Address, from, to time.Time) ([]*types.PairDailyStats, error) {
+	f, t, err := reportPeriod(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.dailyStatsDao.GetPairStats(bt, qt, f, t)
+}
+
+// GetFeeTotals returns the daily fee totals per token between two days included
+func (s *ReportService) GetFeeTotals(from, to time.Time) ([]*types.FeeTotal, error) {
+	f, t, err := reportPeriod(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.dailyStatsDao.GetFeeTotals(f, t)
+}
+
+func reportPeriod(from, to time.Time) (string, string, error) {
+	if to.Before(from) || to.Sub(from) > maxReportDays*24*time.Hour {
+		return "", "", ErrInvalidReportPeriod
+	}
+
+	return from.UTC().Format(types.DailyStatsDateLayout), to.UTC().Format(types.DailyStatsDateLayout), nil
+}
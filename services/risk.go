@@ -0,0 +1,275 @@
+package services
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/globalsign/mgo"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/errors"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/math"
+)
+
+// RiskService runs the pre-trade risk checks of the new orders before they are sent to the engine.
+// The limits are managed per account and pair with the admin API. The "risk" config section enables
+// the checks and sets the limits of the accounts without one, max_order_notional in whole quote tokens
+// and max_position in whole base tokens (0 for no limit)
+type RiskService struct {
+	riskLimitDao interfaces.RiskLimitDao
+	orderDao     interfaces.OrderDao
+	enabled      bool
+	notional     int64
+	position     int64
+	mutex        sync.RWMutex
+	limits       []*types.RiskLimit
+}
+
+// NewRiskService returns a new instance of RiskService
+func NewRiskService(riskLimitDao interfaces.RiskLimitDao, orderDao interfaces.OrderDao) *RiskService {
+	return &RiskService{
+		riskLimitDao: riskLimitDao,
+		orderDao:     orderDao,
+		enabled:      app.Config.Risk["enabled"] > 0,
+		notional:     int64(app.Config.Risk["max_order_notional"]),
+		position:     int64(app.Config.Risk["max_position"]),
+	}
+}
+
+// getLimits returns the risk limits, loaded from the database at the first order
+func (s *RiskService) getLimits() ([]*types.RiskLimit, error) {
+	s.mutex.RLock()
+	limits := s.limits
+	s.mutex.RUnlock()
+
+	if limits != nil {
+		return limits, nil
+	}
+
+	return s.reload()
+}
+
+func (s *RiskService) reload() ([]*types.RiskLimit, error) {
+	limits, err := s.riskLimitDao.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	s.limits = limits
+	s.mutex.Unlock()
+
+	return limits, nil
+}
+
+// resolve returns the limits of the account on the pair, the config limits apply if none is set
+func (s *RiskService) resolve(limits []*types.RiskLimit, account common.Address, p *types.Pair) *types.RiskLimit {
+	l := types.ResolveRiskLimit(limits, account, p.BaseTokenAddress, p.QuoteTokenAddress)
+	if l.MaxOrderNotional == nil && s.notional > 0 {
+		l.MaxOrderNotional = math.Mul(big.NewInt(s.notional), math.Pow10(p.QuoteTokenDecimals))
+	}
+
+	if l.MaxPosition == nil && s.position > 0 {
+		l.MaxPosition = math.Mul(big.NewInt(s.position), math.Pow10(p.BaseTokenDecimals))
+	}
+
+	return l
+}
+
+// Evaluate returns the risk limits of the account the order exceeds, the checks are skipped when disabled
+func (s *RiskService) Evaluate(o *types.Order, p *types.Pair) ([]string, error) {
+	if !s.enabled {
+		return nil, nil
+	}
+
+	limits, err := s.getLimits()
+	if err != nil {
+		return nil, err
+	}
+
+	l := s.resolve(limits, o.UserAddress, p)
+	res := []string{}
+
+	if l.MaxOrderNotional != nil && o.PricePoint != nil && o.PricePoint.Sign() > 0 {
+		notional := o.QuoteAmount(p)
+		if notional.Cmp(l.MaxOrderNotional) > 0 {
+			res = append(res, fmt.Sprintf("notional %s above the maximum %s", notional, l.MaxOrderNotional))
+		}
+	}
+
+	if l.MaxPosition != nil {
+		position, err := s.openPosition(o, p)
+		if err != nil {
+			return nil, err
+		}
+
+		if position.Cmp(l.MaxPosition) > 0 {
+			res = append(res, fmt.Sprintf("open position %s above the maximum %s", position, l.MaxPosition))
+		}
+	}
+
+	banned, err := s.bannedCounterparty(limits, l, o, p)
+	if err != nil {
+		return nil, err
+	}
+
+	if banned != nil {
+		res = append(res, fmt.Sprintf("matches an order of the banned counterparty %s", banned.Hex()))
+	}
+
+	return res, nil
+}
+
+// Check returns ErrRiskLimitExceeded with the exceeded limits if the order exceeds a risk limit of the account
+func (s *RiskService) Check(o *types.Order, p *types.Pair) error {
+	violations, err := s.Evaluate(o, p)
+	if err != nil {
+		return err
+	}
+
+	if len(violations) > 0 {
+		return errors.New(ErrRiskLimitExceeded.Error() + ": " + strings.Join(violations, ", "))
+	}
+
+	return nil
+}
+
+// openPosition returns the remaining amount of the open orders of the account on the pair with the order
+func (s *RiskService) openPosition(o *types.Order, p *types.Pair) (*big.Int, error) {
+	orders, err := s.orderDao.GetOpenOrdersByUserAddress(o.UserAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	res := new(big.Int).Set(o.Amount)
+	for _, open := range orders {
+		if open.BaseToken == p.BaseTokenAddress && open.QuoteToken == p.QuoteTokenAddress && open.Hash != o.Hash {
+			res.Add(res, open.RemainingAmount())
+		}
+	}
+
+	return res, nil
+}
+
+// bannedCounterparty returns the owner of an open order the order would match, if the owner is banned
+// by the account or bans the account
+func (s *RiskService) bannedCounterparty(limits []*types.RiskLimit, l *types.RiskLimit, o *types.Order, p *types.Pair) (*common.Address, error) {
+	bans := false
+	for _, limit := range limits {
+		bans = bans || len(limit.BannedCounterparties) > 0
+	}
+
+	if !bans {
+		return nil, nil
+	}
+
+	book, err := s.orderDao.GetRawOrderBook(p)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, resting := range book {
+		if !crosses(o, resting) {
+			continue
+		}
+
+		owner := resting.UserAddress
+		if l.Bans(owner) || types.ResolveRiskLimit(limits, owner, p.BaseTokenAddress, p.QuoteTokenAddress).Bans(o.UserAddress) {
+			return &owner, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// crosses returns true if the order would match the resting order
+func crosses(o *types.Order, resting *types.Order) bool {
+	if resting.Side == o.Side || resting.UserAddress == o.UserAddress {
+		return false
+	}
+
+	if o.Type == types.TypeMarketOrder {
+		return true
+	}
+
+	if resting.PricePoint == nil || o.PricePoint == nil {
+		return false
+	}
+
+	if o.Side == types.BUY {
+		return resting.PricePoint.Cmp(o.PricePoint) <= 0
+	}
+
+	return resting.PricePoint.Cmp(o.PricePoint) >= 0
+}
+
+// GetLimits returns the risk limits, the ones applying to an account if it is not nil
+func (s *RiskService) GetLimits(account *common.Address) ([]*types.RiskLimit, error) {
+	limits, err := s.reload()
+	if err != nil {
+		return nil, err
+	}
+
+	if account == nil {
+		return limits, nil
+	}
+
+	res := []*types.RiskLimit{}
+	for _, l := range limits {
+		if l.Account == *account || l.Account == (common.Address{}) {
+			res = append(res, l)
+		}
+	}
+
+	return res, nil
+}
+
+// SetLimit creates or replaces the risk limit of an account on a pair
+func (s *RiskService) SetLimit(l *types.RiskLimit, identity string) (*types.RiskLimit, error) {
+	limits, err := s.reload()
+	if err != nil {
+		return nil, err
+	}
+
+	l.ID = ""
+	for _, existing := range limits {
+		if existing.Account == l.Account && existing.BaseToken == l.BaseToken && existing.QuoteToken == l.QuoteToken {
+			l.ID = existing.ID
+			l.CreatedAt = existing.CreatedAt
+		}
+	}
+
+	if l.BannedCounterparties == nil {
+		l.BannedCounterparties = []common.Address{}
+	}
+
+	l.UpdatedBy = identity
+	err = s.riskLimitDao.Upsert(l)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("Risk limit of %s on %s/%s set by %s", l.Account.Hex(), l.BaseToken.Hex(), l.QuoteToken.Hex(), identity)
+	_, err = s.reload()
+	return l, err
+}
+
+// DeleteLimit removes the risk limit of an account on a pair
+func (s *RiskService) DeleteLimit(account, bt, qt common.Address, identity string) error {
+	err := s.riskLimitDao.Delete(account, bt, qt)
+	if err == mgo.ErrNotFound {
+		return ErrRiskLimitNotFound
+	}
+
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Risk limit of %s on %s/%s deleted by %s", account.Hex(), bt.Hex(), qt.Hex(), identity)
+	_, err = s.reload()
+	return err
+}
@@ -1,16 +1,23 @@
 package services
 
 import (
+	"io/ioutil"
+	"os/exec"
+
 	"github.com/globalsign/mgo"
 	"github.com/globalsign/mgo/dbtest"
 	"github.com/tomochain/tomox-sdk/daos"
-	"io/ioutil"
 )
 
 var server dbtest.DBServer
 var db *mgo.Session
 
+// init starts a test database if mongod is installed, the tests which need it are skipped otherwise
 func init() {
+	if _, err := exec.LookPath("mongod"); err != nil {
+		return
+	}
+
 	temp, _ := ioutil.TempDir("", "test")
 	server.SetPath(temp)
 
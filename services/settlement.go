@@ -0,0 +1,272 @@
+package services
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	eth "github.com/ethereum/go-ethereum/core/types"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/errors"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// Defaults of the settlement recovery, overridden by the "settlement" config section
+const (
+	defaultSettlementAttempts = 5
+	defaultSettlementDelay    = 30 * time.Second
+)
+
+// SettlementService reports the trades whose on-chain settlement failed.
+// The settlement transactions are issued by the TomoX masternodes, so the SDK
+// cannot resubmit them with another gas strategy: a reverted settlement is stuck at once
+// until an operator abandons it. A settlement whose receipt can not be read yet is checked
+// again on new blocks with a capped number of attempts before it is stuck
+type SettlementService struct {
+	settlementDao  interfaces.SettlementDao
+	tradeDao       interfaces.TradeDao
+	pairDao        interfaces.PairDao
+	engine         interfaces.Engine
+	maxAttempts    int
+	retryDelay     time.Duration
+	pauseThreshold int
+}
+
+// NewSettlementService returns a new instance of SettlementService
+func NewSettlementService(
+	settlementDao interfaces.SettlementDao,
+	tradeDao interfaces.TradeDao,
+	pairDao interfaces.PairDao,
+	engine interfaces.Engine,
+	blockService interfaces.BlockService,
+) *SettlementService {
+	s := &SettlementService{
+		settlementDao:  settlementDao,
+		tradeDao:       tradeDao,
+		pairDao:        pairDao,
+		engine:         engine,
+		maxAttempts:    defaultSettlementAttempts,
+		retryDelay:     defaultSettlementDelay,
+		pauseThreshold: app.Config.Settlement["pause_threshold"],
+	}
+
+	if v := app.Config.Settlement["max_attempts"]; v > 0 {
+		s.maxAttempts = v
+	}
+
+	if v := app.Config.Settlement["retry_delay"]; v > 0 {
+		s.retryDelay = time.Duration(v) * time.Second
+	}
+
+	blockService.RegisterNotify(s.HandleNewBlock)
+	return s
+}
+
+// Track starts the recovery of a trade whose settlement failed
+func (s *SettlementService) Track(t *types.Trade) error {
+	existing, err := s.settlementDao.GetByTradeHash(t.Hash)
+	if err != nil {
+		return err
+	}
+
+	if existing != nil {
+		return nil
+	}
+
+	logger.Warningf("Settlement of trade %s failed", t.Hash.Hex())
+	return s.settlementDao.Create(types.NewSettlement(t))
+}
+
+// GetByStatus returns the settlements with one of the statuses
+func (s *SettlementService) GetByStatus(statuses ...string) ([]*types.Settlement, error) {
+	return s.settlementDao.GetByStatus(statuses...)
+}
+
+// HandleNewBlock checks the settlements whose retry time is reached
+func (s *SettlementService) HandleNewBlock(block *types.BlockHeader) {
+	settlements, err := s.settlementDao.GetDue(time.Now())
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	for _, st := range settlements {
+		s.attempt(st)
+	}
+}
+
+// Retry checks the settlement transaction of a stuck settlement again with a fresh attempt budget, a
+// reverted transaction leaves it stuck
+func (s *SettlementService) Retry(hash common.Hash, identity string) (*types.Settlement, error) {
+	st, err := s.get(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("Settlement of trade %s retried by %s", hash.Hex(), identity)
+
+	st.Status = types.SettlementStatusRetrying
+	st.Attempts = 0
+	st.NextRetryAt = time.Now()
+	s.attempt(st)
+
+	return st, nil
+}
+
+// Abandon stops the recovery of a settlement
+func (s *SettlementService) Abandon(hash common.Hash, identity string) (*types.Settlement, error) {
+	st, err := s.get(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Warningf("Settlement of trade %s abandoned by %s", hash.Hex(), identity)
+
+	st.Status = types.SettlementStatusAbandoned
+	st.ResolvedBy = identity
+
+	err = s.settlementDao.Update(st)
+	if err != nil {
+		return nil, err
+	}
+
+	return st, nil
+}
+
+// PausePair rejects the new orders of a pair until the pause reason is lifted
+func (s *SettlementService) PausePair(bt, qt common.Address, reason string) error {
+	p, err := s.getPair(bt, qt)
+	if err != nil {
+		return err
+	}
+
+	s.engine.PausePair(p.Code(), reason)
+	return nil
+}
+
+// PausePairQueued stops the matching of a pair until it is resumed, up to limit new orders are queued
+// meanwhile, the pause_queue_limit of the admission config if zero
+func (s *SettlementService) PausePairQueued(bt, qt common.Address, limit int) error {
+	p, err := s.getPair(bt, qt)
+	if err != nil {
+		return err
+	}
+
+	s.engine.PausePairQueued(p.Code(), limit)
+	return nil
+}
+
+// ResumePair lifts the operator and settlement pauses of a pair. ErrPairHalted is returned if a kill switch
+// or a token migration holds the pair, the pauses of the other sources are kept
+func (s *SettlementService) ResumePair(bt, qt common.Address) error {
+	p, err := s.getPair(bt, qt)
+	if err != nil {
+		return err
+	}
+
+	for _, reason := range s.engine.PauseReasons(p.Code()) {
+		if types.IsHaltPauseReason(reason) {
+			return ErrPairHalted
+		}
+	}
+
+	s.engine.ResumePair(p.Code(), types.PauseReasonSettlement)
+	s.engine.ResumePair(p.Code(), types.PauseReasonOperator)
+	return nil
+}
+
+func (s *SettlementService) get(hash common.Hash) (*types.Settlement, error) {
+	st, err := s.settlementDao.GetByTradeHash(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	if st == nil {
+		return nil, ErrSettlementNotFound
+	}
+
+	if st.IsResolved() {
+		return nil, ErrSettlementResolved
+	}
+
+	return st, nil
+}
+
+func (s *SettlementService) getPair(bt, qt common.Address) (*types.Pair, error) {
+	p, err := s.pairDao.GetByTokenAddress(bt, qt)
+	if err != nil {
+		return nil, err
+	}
+
+	if p == nil {
+		return nil, ErrPairNotFound
+	}
+
+	return p, nil
+}
+
+// attempt reads the receipt of the settlement transaction of the trade, it is read again later if it is
+// not available yet
+func (s *SettlementService) attempt(st *types.Settlement) {
+	var receipt *eth.Receipt
+	var err error
+
+	if (st.TxHash == common.Hash{}) {
+		err = errors.New("Settlement transaction unknown")
+	} else {
+		receipt, err = s.engine.Provider().TransactionReceipt(st.TxHash)
+	}
+
+	switch {
+	case err != nil:
+		st.Fail(err.Error(), s.maxAttempts, s.retryDelay)
+	case receipt == nil:
+		st.Fail("Settlement transaction not mined", s.maxAttempts, s.retryDelay)
+	case receipt.Status != eth.ReceiptStatusSuccessful:
+		st.Revert("Settlement transaction reverted")
+	default:
+		st.Status = types.SettlementStatusSettled
+		err = s.tradeDao.UpdateTradeStatus(st.TradeHash, types.TradeStatusSuccess)
+		if err != nil {
+			logger.Error(err)
+		}
+	}
+
+	err = s.settlementDao.Update(st)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	if st.Status == types.SettlementStatusStuck {
+		logger.Errorf("Settlement of trade %s stuck after %d attempts: %s", st.TradeHash.Hex(), st.Attempts, st.LastError)
+		s.checkPause(st)
+	}
+}
+
+// checkPause pauses the pair once it has too many stuck settlements
+func (s *SettlementService) checkPause(st *types.Settlement) {
+	if s.pauseThreshold <= 0 {
+		return
+	}
+
+	stuck, err := s.settlementDao.GetByStatus(types.SettlementStatusStuck)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	count := 0
+	for _, other := range stuck {
+		if other.BaseToken == st.BaseToken && other.QuoteToken == st.QuoteToken {
+			count++
+		}
+	}
+
+	if count >= s.pauseThreshold {
+		err = s.PausePair(st.BaseToken, st.QuoteToken, types.PauseReasonSettlement)
+		if err != nil {
+			logger.Error(err)
+		}
+	}
+}
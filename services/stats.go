@@ -0,0 +1,93 @@
+package services
+
+import (
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// publicStatsTTL is how long the public stats are cached
+const publicStatsTTL = time.Minute
+
+// publicStatsTopPairs is the number of pairs ranked by volume in the public stats
+const publicStatsTopPairs = 10
+
+// StatsService computes the network-wide statistics, anonymous and cached
+type StatsService struct {
+	pairDao      interfaces.PairDao
+	tradeDao     interfaces.TradeDao
+	ohlcvService interfaces.OHLCVService
+	publicStats  *types.PublicStats
+	cachedAt     time.Time
+	mutex        sync.Mutex
+}
+
+// NewStatsService returns a new instance of StatsService
+func NewStatsService(
+	pairDao interfaces.PairDao,
+	tradeDao interfaces.TradeDao,
+	ohlcvService interfaces.OHLCVService,
+) *StatsService {
+	return &StatsService{
+		pairDao:      pairDao,
+		tradeDao:     tradeDao,
+		ohlcvService: ohlcvService,
+	}
+}
+
+// GetPublicStats returns the 24h volume, trade count, active pairs and unique traders of the network
+func (s *StatsService) GetPublicStats() (*types.PublicStats, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.publicStats != nil && time.Since(s.cachedAt) < publicStatsTTL {
+		return s.publicStats, nil
+	}
+
+	pairs, err := s.pairDao.GetActivePairs()
+	if err != nil {
+		return nil, err
+	}
+
+	volume := big.NewInt(0)
+	stats := &types.PublicStats{ListedPairs: len(pairs), TopPairs: []*types.PairVolume{}}
+	ranked := []*types.PairData{}
+	for _, p := range pairs {
+		tick := s.ohlcvService.Get24hTick(p.BaseTokenAddress, p.QuoteTokenAddress)
+		if tick == nil || tick.Count == nil || tick.Count.Sign() == 0 {
+			continue
+		}
+
+		stats.ActivePairs++
+		stats.TradeCount24h += tick.Count.Int64()
+		if tick.VolumeUsdt != nil && tick.VolumeUsdt.Sign() > 0 {
+			volume.Add(volume, tick.VolumeUsdt)
+			ranked = append(ranked, &types.PairData{Pair: types.PairID{PairName: p.Name()}, VolumeUsd: tick.VolumeUsdt})
+		}
+	}
+
+	// the pairs are ranked by their volume in USDT, a pair whose quote token has no price is left out
+	types.SortPairDataByVolumeUsd(ranked)
+	for i, d := range ranked {
+		if i == publicStatsTopPairs {
+			break
+		}
+
+		stats.TopPairs = append(stats.TopPairs, &types.PairVolume{PairName: d.Pair.PairName, Volume24hUsdt: d.VolumeUsd.String()})
+	}
+
+	stats.UniqueTraders, err = s.tradeDao.CountUniqueTraders(time.Now().AddDate(0, 0, -1))
+	if err != nil {
+		return nil, err
+	}
+
+	stats.Volume24hUsdt = volume.String()
+	stats.Timestamp = time.Now().Unix()
+
+	s.publicStats = stats
+	s.cachedAt = time.Now()
+	return stats, nil
+}
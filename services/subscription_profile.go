@@ -0,0 +1,92 @@
+package services
+
+import (
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/ws"
+)
+
+// maxProfileNameLength is the length limit of the subscription profile names
+const maxProfileNameLength = 64
+
+// SubscriptionProfileService saves the websocket subscription profiles of the clients
+// and subscribes a connection to all the channels of a profile
+type SubscriptionProfileService struct {
+	profileDao interfaces.SubscriptionProfileDao
+}
+
+// NewSubscriptionProfileService returns a new instance of SubscriptionProfileService
+func NewSubscriptionProfileService(profileDao interfaces.SubscriptionProfileDao) *SubscriptionProfileService {
+	return &SubscriptionProfileService{profileDao}
+}
+
+// Save creates or replaces a profile of the owner
+func (s *SubscriptionProfileService) Save(owner string, p *types.SubscriptionProfilePayload) error {
+	if p.Name == "" || len(p.Name) > maxProfileNameLength {
+		return ErrInvalidProfile
+	}
+
+	if len(p.Subscriptions) == 0 || len(p.Subscriptions) > types.MaxProfileSubscriptions {
+		return ErrInvalidProfile
+	}
+
+	for _, sub := range p.Subscriptions {
+		if sub == nil || sub.Channel == ws.ProfileChannel || !ws.HasChannel(sub.Channel) {
+			return ErrInvalidProfile
+		}
+	}
+
+	existing, err := s.profileDao.GetByName(owner, p.Name)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		count, err := s.profileDao.Count(owner)
+		if err != nil {
+			return err
+		}
+
+		if count >= types.MaxSubscriptionProfiles {
+			return ErrTooManyProfiles
+		}
+	}
+
+	return s.profileDao.Save(&types.SubscriptionProfile{
+		Owner:         owner,
+		Name:          p.Name,
+		Subscriptions: p.Subscriptions,
+	})
+}
+
+// GetAll returns the profiles of the owner
+func (s *SubscriptionProfileService) GetAll(owner string) ([]*types.SubscriptionProfile, error) {
+	return s.profileDao.GetByOwner(owner)
+}
+
+// Delete removes a profile of the owner
+func (s *SubscriptionProfileService) Delete(owner, name string) error {
+	return s.profileDao.Delete(owner, name)
+}
+
+// Activate subscribes the connection to all the channels of a profile of the owner
+func (s *SubscriptionProfileService) Activate(owner, name string, c *ws.Client) (*types.SubscriptionProfile, error) {
+	p, err := s.profileDao.GetByName(owner, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if p == nil {
+		return nil, ErrProfileNotFound
+	}
+
+	for _, sub := range p.Subscriptions {
+		ev := types.WebsocketEvent{Type: types.SUBSCRIBE, Payload: sub.Payload}
+		err := ws.Dispatch(sub.Channel, ev, c)
+		if err != nil {
+			logger.Warningf("Subscription of profile %s skipped: %s", name, err)
+		}
+	}
+
+	return p, nil
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/globalsign/mgo"
 	"github.com/globalsign/mgo/bson"
 	"github.com/tomochain/tomox-sdk/interfaces"
 
@@ -11,12 +12,13 @@ import (
 // TokenService struct with daos required, responsible for communicating with daos.
 // TokenService functions are responsible for interacting with daos and implements business logics.
 type TokenService struct {
-	tokenDao interfaces.TokenDao
+	tokenDao      interfaces.TokenDao
+	tokenAliasDao interfaces.TokenAliasDao
 }
 
 // NewTokenService returns a new instance of TokenService
-func NewTokenService(tokenDao interfaces.TokenDao) *TokenService {
-	return &TokenService{tokenDao}
+func NewTokenService(tokenDao interfaces.TokenDao, tokenAliasDao interfaces.TokenAliasDao) *TokenService {
+	return &TokenService{tokenDao, tokenAliasDao}
 }
 
 // Create inserts a new token into the database
@@ -68,3 +70,109 @@ func (s *TokenService) GetQuoteTokens() ([]types.Token, error) {
 func (s *TokenService) GetBaseTokens() ([]types.Token, error) {
 	return s.tokenDao.GetBaseTokens()
 }
+
+// GetAllBySymbol fetches the tokens of different contracts sharing a symbol
+func (s *TokenService) GetAllBySymbol(symbol string) ([]types.Token, error) {
+	return s.tokenDao.GetAllBySymbol(symbol)
+}
+
+// ResolveSymbol returns the token a symbol refers to, the canonical one of the symbol if several
+// tokens share it. When the symbol is ambiguous ErrTokenSymbolAmbiguous is returned with the candidates
+func (s *TokenService) ResolveSymbol(symbol string) (*types.Token, []types.Token, error) {
+	tokens, err := s.tokenDao.GetAllBySymbol(symbol)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	alias, err := s.tokenAliasDao.GetBySymbol(symbol)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	t, ambiguous := types.ResolveSymbol(tokens, alias)
+	if ambiguous {
+		return nil, tokens, ErrTokenSymbolAmbiguous
+	}
+
+	if t == nil {
+		return nil, nil, ErrTokenNotFound
+	}
+
+	return t, nil, nil
+}
+
+// SetVerified marks a token contract as verified or not by the operators
+func (s *TokenService) SetVerified(addr common.Address, verified bool, identity string) (*types.Token, error) {
+	t, err := s.tokenDao.GetByAddress(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	if t == nil {
+		return nil, ErrTokenNotFound
+	}
+
+	err = s.tokenDao.SetVerified(addr, verified)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("Token %s (%s) verified %t by %s", t.Symbol, addr.Hex(), verified, identity)
+	t.Verified = verified
+	return t, nil
+}
+
+// GetAliases returns the canonical tokens of the symbols
+func (s *TokenService) GetAliases() ([]*types.TokenAlias, error) {
+	return s.tokenAliasDao.GetAll()
+}
+
+// SetAlias makes a token the canonical one of its symbol
+func (s *TokenService) SetAlias(symbol string, addr common.Address, identity string) (*types.TokenAlias, error) {
+	t, err := s.tokenDao.GetByAddress(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	if t == nil {
+		return nil, ErrTokenNotFound
+	}
+
+	if t.Symbol != symbol {
+		return nil, ErrTokenSymbolMismatch
+	}
+
+	a, err := s.tokenAliasDao.GetBySymbol(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	if a == nil {
+		a = &types.TokenAlias{Symbol: symbol}
+	}
+
+	a.Address = addr
+	a.UpdatedBy = identity
+	err = s.tokenAliasDao.Upsert(a)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("Symbol %s aliased to %s by %s", symbol, addr.Hex(), identity)
+	return a, nil
+}
+
+// DeleteAlias removes the canonical token of a symbol
+func (s *TokenService) DeleteAlias(symbol, identity string) error {
+	err := s.tokenAliasDao.Delete(symbol)
+	if err == mgo.ErrNotFound {
+		return ErrTokenAliasNotFound
+	}
+
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Alias of symbol %s deleted by %s", symbol, identity)
+	return nil
+}
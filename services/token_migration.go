@@ -0,0 +1,222 @@
+package services
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils"
+)
+
+// TokenMigrationService guides the migration of a listed token to a new contract. All the pairs of
+// the old token are halted before their open orders are read so that the report is a consistent
+// snapshot, then the pairs are regenerated with the new token, which must be registered on the
+// relayer contract. The open orders are signed over the old token address, they stay on the halted
+// pairs and the report maps each of them to the equivalent order on the new pair for its owner to sign.
+// A dry run reports the migration without halting or creating anything and is not recorded
+type TokenMigrationService struct {
+	tokenMigrationDao interfaces.TokenMigrationDao
+	tokenDao          interfaces.TokenDao
+	tokenAliasDao     interfaces.TokenAliasDao
+	pairDao           interfaces.PairDao
+	orderDao          interfaces.OrderDao
+	settlementService interfaces.SettlementService
+	relayerService    interfaces.RelayerService
+	mutex             sync.Mutex
+}
+
+// NewTokenMigrationService returns a new instance of TokenMigrationService
+func NewTokenMigrationService(
+	tokenMigrationDao interfaces.TokenMigrationDao,
+	tokenDao interfaces.TokenDao,
+	tokenAliasDao interfaces.TokenAliasDao,
+	pairDao interfaces.PairDao,
+	orderDao interfaces.OrderDao,
+	settlementService interfaces.SettlementService,
+	relayerService interfaces.RelayerService,
+) *TokenMigrationService {
+	return &TokenMigrationService{
+		tokenMigrationDao: tokenMigrationDao,
+		tokenDao:          tokenDao,
+		tokenAliasDao:     tokenAliasDao,
+		pairDao:           pairDao,
+		orderDao:          orderDao,
+		settlementService: settlementService,
+		relayerService:    relayerService,
+	}
+}
+
+// Migrate migrates the pairs of a token to its new contract and returns the report of the migration
+func (s *TokenMigrationService) Migrate(oldToken, newToken common.Address, dryRun bool, identity string) (*types.TokenMigration, error) {
+	if oldToken == newToken || utils.IsNativeTokenByAddress(oldToken) || utils.IsNativeTokenByAddress(newToken) {
+		return nil, ErrInvalidTokenMigration
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	old, err := s.tokenDao.GetByAddress(oldToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if old == nil {
+		return nil, ErrTokenNotFound
+	}
+
+	migrated, err := s.tokenDao.GetByAddress(newToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if migrated == nil {
+		return nil, ErrNewTokenNotListed
+	}
+
+	all, err := s.pairDao.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	m := &types.TokenMigration{
+		OldToken:    oldToken,
+		NewToken:    newToken,
+		Symbol:      old.Symbol,
+		OldDecimals: old.Decimals,
+		NewDecimals: migrated.Decimals,
+		DryRun:      dryRun,
+		Status:      types.TokenMigrationStatusCompleted,
+		Pairs:       []*types.TokenMigrationPair{},
+		StartedBy:   identity,
+	}
+
+	pairs := []types.Pair{}
+	for _, p := range all {
+		if p.BaseTokenAddress == oldToken || p.QuoteTokenAddress == oldToken {
+			pairs = append(pairs, p)
+			m.Pairs = append(m.Pairs, types.NewTokenMigrationPair(&p, oldToken, newToken))
+		}
+	}
+
+	if !dryRun {
+		logger.Infof("Migration of token %s (%s) to %s started by %s", old.Symbol, oldToken.Hex(), newToken.Hex(), identity)
+		s.halt(pairs, m.Pairs)
+	}
+
+	for i, p := range pairs {
+		orders, err := s.orderDao.GetRawOrderBook(&p)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, o := range orders {
+			m.Pairs[i].MapOrder(o, m.OldDecimals, m.NewDecimals)
+		}
+	}
+
+	if dryRun {
+		return m, nil
+	}
+
+	for i, p := range pairs {
+		s.regenerate(&p, m.Pairs[i])
+		if !m.Pairs[i].Halted || !m.Pairs[i].Regenerated {
+			m.Status = types.TokenMigrationStatusPartial
+		}
+	}
+
+	if migrated.Symbol == old.Symbol {
+		s.moveAlias(old.Symbol, newToken, identity)
+	}
+
+	err = s.tokenMigrationDao.Create(m)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("Migration of token %s to %s %s", oldToken.Hex(), newToken.Hex(), m.Status)
+	return m, nil
+}
+
+// GetByID returns the report of a token migration
+func (s *TokenMigrationService) GetByID(id bson.ObjectId) (*types.TokenMigration, error) {
+	m, err := s.tokenMigrationDao.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if m == nil {
+		return nil, ErrTokenMigrationNotFound
+	}
+
+	return m, nil
+}
+
+// GetAll returns the reports of the token migrations
+func (s *TokenMigrationService) GetAll() ([]*types.TokenMigration, error) {
+	return s.tokenMigrationDao.GetAll()
+}
+
+// halt rejects the new orders of the pairs and deactivates them, before any open order is read
+func (s *TokenMigrationService) halt(pairs []types.Pair, migrations []*types.TokenMigrationPair) {
+	for i, p := range pairs {
+		err := s.settlementService.PausePair(p.BaseTokenAddress, p.QuoteTokenAddress, types.PauseReasonMigration)
+		if err == nil {
+			err = s.pairDao.SetActive(p.BaseTokenAddress, p.QuoteTokenAddress, p.RelayerAddress, false)
+		}
+
+		if err != nil {
+			logger.Error("Pair not halted", p.Name(), err)
+			migrations[i].Error = err.Error()
+			continue
+		}
+
+		migrations[i].Halted = true
+	}
+}
+
+// regenerate creates the pair of the new token with the parameters of the pair of the old token,
+// a pair which already exists is kept
+func (s *TokenMigrationService) regenerate(p *types.Pair, mp *types.TokenMigrationPair) {
+	pair := &types.Pair{
+		BaseTokenAddress:  mp.NewBaseToken,
+		QuoteTokenAddress: mp.NewQuoteToken,
+		RelayerAddress:    p.RelayerAddress,
+		Listed:            p.Listed,
+		Active:            true,
+		Rank:              p.Rank,
+		MakeFee:           p.MakeFee,
+		TakeFee:           p.TakeFee,
+	}
+
+	err := s.relayerService.CreatePair(pair)
+	switch err {
+	case nil, ErrPairExists:
+		mp.Regenerated = true
+	default:
+		logger.Error("Pair not regenerated", mp.Name, err)
+		mp.Error = err.Error()
+	}
+}
+
+// moveAlias points the alias of the symbol to the new token, which shares the symbol of the old one
+func (s *TokenMigrationService) moveAlias(symbol string, newToken common.Address, identity string) {
+	a, err := s.tokenAliasDao.GetBySymbol(symbol)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	if a == nil {
+		a = &types.TokenAlias{Symbol: symbol}
+	}
+
+	a.Address = newToken
+	a.UpdatedBy = identity
+	err = s.tokenAliasDao.Upsert(a)
+	if err != nil {
+		logger.Error(err)
+	}
+}
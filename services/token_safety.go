@@ -0,0 +1,167 @@
+package services
+
+import (
+	"math/big"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils"
+)
+
+// Addresses of the simulated transfers, the probe is an arbitrary address holding no token
+var (
+	tokenSafetyProbe     = common.HexToAddress("0x5afe5afe5afe5afe5afe5afe5afe5afe5afe5afe")
+	tokenSafetyRecipient = common.HexToAddress("0x5afe5afe5afe5afe5afe5afe5afe5afe5afe0001")
+)
+
+var (
+	transferSelector  = crypto.Keccak256([]byte("transfer(address,uint256)"))[:4]
+	balanceOfSelector = crypto.Keccak256([]byte("balanceOf(address)"))[:4]
+	ownerSelector     = crypto.Keccak256([]byte("owner()"))[:4]
+)
+
+// TokenSafetyService runs heuristics detecting the tokens restricting their transfers or taking a hidden
+// fee on them. Transfers are simulated with eth_call: a zero transfer from an arbitrary address and a
+// transfer of a part of the balance of a known holder (the owner of the token or the exchange).
+// A call cannot observe the balances after the transfer, the fees are found from the functions of the
+// contract. The tokens are checked when they are listed and daily
+type TokenSafetyService struct {
+	tokenDao interfaces.TokenDao
+	provider interfaces.EthereumProvider
+}
+
+// NewTokenSafetyService returns a new instance of TokenSafetyService
+func NewTokenSafetyService(tokenDao interfaces.TokenDao, provider interfaces.EthereumProvider) *TokenSafetyService {
+	return &TokenSafetyService{tokenDao, provider}
+}
+
+// Check runs the checks of a token contract and records their result on the token
+func (s *TokenSafetyService) Check(token common.Address) (*types.TokenSafety, error) {
+	code, err := s.provider.CodeAt(token)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := types.CodeFindings(code)
+	if len(code) > 0 {
+		findings = append(findings, s.simulateTransfers(token)...)
+	}
+
+	safety := types.NewTokenSafety(findings, time.Now())
+	if safety.Score > 0 {
+		logger.Infof("Token %s risk score %d: %v", token.Hex(), safety.Score, safety.Findings)
+	}
+
+	err = s.tokenDao.SetSafety(token, safety)
+	if err != nil {
+		return nil, err
+	}
+
+	return safety, nil
+}
+
+// CheckAll runs the checks of all the tokens, a token failing to be checked keeps its previous result
+func (s *TokenSafetyService) CheckAll() error {
+	tokens, err := s.tokenDao.GetAll()
+	if err != nil {
+		return err
+	}
+
+	for _, t := range tokens {
+		if utils.IsNativeTokenByAddress(t.ContractAddress) {
+			continue
+		}
+
+		_, err := s.Check(t.ContractAddress)
+		if err != nil {
+			logger.Warning("Token safety check failed:", t.ContractAddress.Hex(), err)
+		}
+	}
+
+	return nil
+}
+
+// simulateTransfers returns the findings of the simulated transfers of a token
+func (s *TokenSafetyService) simulateTransfers(token common.Address) []string {
+	findings := []string{}
+
+	res, ok := s.simulate(tokenSafetyProbe, token, packTransfer(tokenSafetyRecipient, big.NewInt(0)))
+	if !ok {
+		findings = append(findings, types.TokenRiskTransferReverts)
+	} else if len(res) == 32 && new(big.Int).SetBytes(res).Sign() == 0 {
+		findings = append(findings, types.TokenRiskTransferReturnFalse)
+	}
+
+	holder, balance := s.holder(token)
+	if holder == (common.Address{}) {
+		return findings
+	}
+
+	amount := new(big.Int).Div(balance, big.NewInt(100))
+	if amount.Sign() == 0 {
+		amount = balance
+	}
+
+	res, ok = s.simulate(holder, token, packTransfer(tokenSafetyRecipient, amount))
+	if !ok || (len(res) == 32 && new(big.Int).SetBytes(res).Sign() == 0) {
+		findings = append(findings, types.TokenRiskHolderTransferFails)
+	}
+
+	return findings
+}
+
+// holder returns the first of the owner of the token and the exchange holding some of the token
+func (s *TokenSafetyService) holder(token common.Address) (common.Address, *big.Int) {
+	candidates := []common.Address{}
+	if res, ok := s.simulate(tokenSafetyProbe, token, ownerSelector); ok && len(res) == 32 {
+		candidates = append(candidates, common.BytesToAddress(res))
+	}
+
+	candidates = append(candidates, common.HexToAddress(app.Config.Tomochain["exchange_address"]))
+	for _, c := range candidates {
+		if c == (common.Address{}) {
+			continue
+		}
+
+		data := append(append([]byte{}, balanceOfSelector...), common.LeftPadBytes(c.Bytes(), 32)...)
+		res, ok := s.simulate(tokenSafetyProbe, token, data)
+		if !ok || len(res) != 32 {
+			continue
+		}
+
+		if balance := new(big.Int).SetBytes(res); balance.Sign() > 0 {
+			return c, balance
+		}
+	}
+
+	return common.Address{}, nil
+}
+
+// simulate calls the token as the sender, false is returned if the call reverts. The node may answer
+// a reverted call without an error, the gas estimation then tells it apart from a call returning nothing
+func (s *TokenSafetyService) simulate(from, token common.Address, data []byte) ([]byte, bool) {
+	msg := ethereum.CallMsg{From: from, To: &token, Data: data}
+	res, err := s.provider.CallContract(msg)
+	if err != nil {
+		return nil, false
+	}
+
+	if len(res) == 0 {
+		if _, err := s.provider.EstimateGas(msg); err != nil {
+			return nil, false
+		}
+	}
+
+	return res, true
+}
+
+func packTransfer(to common.Address, amount *big.Int) []byte {
+	data := append([]byte{}, transferSelector...)
+	data = append(data, common.LeftPadBytes(to.Bytes(), 32)...)
+	return append(data, common.LeftPadBytes(amount.Bytes(), 32)...)
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"math/big"
 	"sync"
 	"time"
 
@@ -18,13 +19,16 @@ import (
 // TradeService struct with daos required, responsible for communicating with daos.
 // TradeService functions are responsible for interacting with daos and implements business logics.
 type TradeService struct {
-	OrderDao        interfaces.OrderDao
-	tradeDao        interfaces.TradeDao
-	notificationDao interfaces.NotificationDao
-	broker          *rabbitmq.Connection
-	ohlcvService    *OHLCVService
-	bulkTrades      map[types.PairAddresses][]*types.Trade
-	mutext          sync.RWMutex
+	OrderDao          interfaces.OrderDao
+	tradeDao          interfaces.TradeDao
+	notificationDao   interfaces.NotificationDao
+	broker            *rabbitmq.Connection
+	ohlcvService      *OHLCVService
+	finalityService   interfaces.FinalityService
+	settlementService interfaces.SettlementService
+	attestation       interfaces.AttestationService
+	bulkTrades        map[types.PairAddresses][]*types.Trade
+	mutext            sync.RWMutex
 }
 
 // NewTradeService returns a new instance of TradeService
@@ -33,24 +37,65 @@ func NewTradeService(
 	tradeDao interfaces.TradeDao,
 	ohlcvService *OHLCVService,
 	notificationDao interfaces.NotificationDao,
+	finalityService interfaces.FinalityService,
+	settlementService interfaces.SettlementService,
 	broker *rabbitmq.Connection,
+	attestation interfaces.AttestationService,
 ) *TradeService {
 	bulkTrades := make(map[types.PairAddresses][]*types.Trade)
-	return &TradeService{
-		OrderDao:        orderdao,
-		tradeDao:        tradeDao,
-		notificationDao: notificationDao,
-		broker:          broker,
-		ohlcvService:    ohlcvService,
-		bulkTrades:      bulkTrades,
-		mutext:          sync.RWMutex{},
+	s := &TradeService{
+		OrderDao:          orderdao,
+		tradeDao:          tradeDao,
+		notificationDao:   notificationDao,
+		broker:            broker,
+		ohlcvService:      ohlcvService,
+		finalityService:   finalityService,
+		settlementService: settlementService,
+		attestation:       attestation,
+		bulkTrades:        bulkTrades,
+		mutext:            sync.RWMutex{},
 	}
+
+	finalityService.RegisterNotify(s.HandleTradeFinalized)
+	return s
 }
 
 // Subscribe
 func (s *TradeService) Subscribe(c *ws.Client, bt, qt common.Address) {
+	s.subscribe(ws.GetTradeSocket(), c, bt, qt)
+}
+
+// Unsubscribe
+func (s *TradeService) UnsubscribeChannel(c *ws.Client, bt, qt common.Address) {
 	socket := ws.GetTradeSocket()
 
+	id := utils.GetTradeChannelID(bt, qt)
+	socket.UnsubscribeChannel(id, c)
+}
+
+// Unsubscribe
+func (s *TradeService) Unsubscribe(c *ws.Client) {
+	socket := ws.GetTradeSocket()
+	socket.Unsubscribe(c)
+}
+
+// SubscribeRawTrades subscribes to the unsampled trades of a pair
+func (s *TradeService) SubscribeRawTrades(c *ws.Client, bt, qt common.Address) {
+	s.subscribe(ws.GetRawTradeSocket(), c, bt, qt)
+}
+
+// UnsubscribeRawTradesChannel unsubscribes from the unsampled trades of a pair
+func (s *TradeService) UnsubscribeRawTradesChannel(c *ws.Client, bt, qt common.Address) {
+	id := utils.GetTradeChannelID(bt, qt)
+	ws.GetRawTradeSocket().UnsubscribeChannel(id, c)
+}
+
+// UnsubscribeRawTrades unsubscribes from the unsampled trades of all the pairs
+func (s *TradeService) UnsubscribeRawTrades(c *ws.Client) {
+	ws.GetRawTradeSocket().Unsubscribe(c)
+}
+
+func (s *TradeService) subscribe(socket *ws.TradeSocket, c *ws.Client, bt, qt common.Address) {
 	numTrades := types.DefaultLimit
 	trades, err := s.GetSortedTrades(bt, qt, 0, 0, numTrades)
 	if err != nil {
@@ -71,18 +116,11 @@ func (s *TradeService) Subscribe(c *ws.Client, bt, qt common.Address) {
 	socket.SendInitMessage(c, trades)
 }
 
-// Unsubscribe
-func (s *TradeService) UnsubscribeChannel(c *ws.Client, bt, qt common.Address) {
-	socket := ws.GetTradeSocket()
-
+// broadcastTrades sends the trades of a pair on the sampled public channel and on the raw channel
+func (s *TradeService) broadcastTrades(bt, qt common.Address, trades []*types.Trade) {
 	id := utils.GetTradeChannelID(bt, qt)
-	socket.UnsubscribeChannel(id, c)
-}
-
-// Unsubscribe
-func (s *TradeService) Unsubscribe(c *ws.Client) {
-	socket := ws.GetTradeSocket()
-	socket.Unsubscribe(c)
+	ws.GetTradeSocket().BroadcastTrades(id, trades[0].PairName, trades)
+	ws.GetRawTradeSocket().BroadcastMessage(id, trades)
 }
 
 // GetByPairName fetches all the trades corresponding to a pair using pair's name
@@ -195,8 +233,7 @@ func (s *TradeService) processBulkTrades() {
 	for pair, trades := range s.bulkTrades {
 		bulkPairs[pair] = true
 		if len(trades) > 0 {
-			id := utils.GetTradeChannelID(pair.BaseToken, pair.QuoteToken)
-			ws.GetTradeSocket().BroadcastMessage(id, trades)
+			s.broadcastTrades(pair.BaseToken, pair.QuoteToken, trades)
 		}
 	}
 	s.bulkTrades = make(map[types.PairAddresses][]*types.Trade)
@@ -214,6 +251,17 @@ func (s *TradeService) processBulkTrades() {
 
 // HandleDocumentType handle trade insert/update db trigger
 func (s *TradeService) HandleDocumentType(ev types.TradeChangeEvent) error {
+	if ev.IsPublicIDAssignment() {
+		return nil
+	}
+
+	if ev.FullDocument != nil && ev.FullDocument.PublicID == 0 {
+		err := s.tradeDao.AssignPublicID(ev.FullDocument)
+		if err != nil {
+			logger.Error(err)
+		}
+	}
+
 	res := &types.EngineResponse{}
 
 	if ev.OperationType == types.OPERATION_TYPE_INSERT {
@@ -305,6 +353,13 @@ func (s *TradeService) HandleOperationUpdate(trade *types.Trade) error {
 		s.HandleTradeSuccess(m)
 	}
 
+	if trade.Status == types.TradeStatusError {
+		err := s.settlementService.Track(trade)
+		if err != nil {
+			logger.Error(err)
+		}
+	}
+
 	return nil
 }
 
@@ -317,8 +372,16 @@ func (s *TradeService) HandleTradeSuccess(m *types.Matches) {
 
 		s.saveBulkTrades(t)
 
-		ws.SendOrderMessage("ORDER_SUCCESS", maker, types.OrderSuccessPayload{Matches: m})
-		ws.SendOrderMessage("ORDER_SUCCESS", taker, types.OrderSuccessPayload{Matches: m})
+		if t.Status == types.TradeStatusSuccess {
+			err := s.finalityService.Track(types.FinalityActionTrade, t.Hash, t.TxHash)
+			if err != nil {
+				logger.Error(err)
+			}
+		}
+
+		payload := s.attestation.Attest(types.AttestationOrderSuccess, types.OrderSuccessPayload{Matches: m})
+		ws.SendOrderMessage("ORDER_SUCCESS", maker, payload)
+		ws.SendOrderMessage("ORDER_SUCCESS", taker, payload)
 		s.notificationDao.Create(&types.Notification{
 			Recipient: taker,
 			Message: types.Message{
@@ -342,6 +405,69 @@ func (s *TradeService) HandleTradeSuccess(m *types.Matches) {
 	s.ohlcvService.NotifyTrade(trades[0])
 }
 
+// HandleTradeFinalized notifies the maker and the taker once a trade reached the required confirmations
+func (s *TradeService) HandleTradeFinalized(r *types.FinalityRecord) {
+	if r.Action != types.FinalityActionTrade {
+		return
+	}
+
+	t, err := s.tradeDao.GetByHash(r.Hash)
+	if err != nil || t == nil {
+		logger.Error("Can not find finalized trade", r.Hash.Hex(), err)
+		return
+	}
+
+	ws.SendOrderMessage("TRADE_FINALIZED", t.Maker, r)
+	ws.SendOrderMessage("TRADE_FINALIZED", t.Taker, r)
+}
+
+// HandleChainEvent applies a trade settled or failed on the exchange contract, read by the chain listener.
+// The stored change is then broadcast through the change stream of the trades, a failed trade is tracked
+// by the settlement service from there. The trades unknown to the SDK or no longer pending are skipped,
+// so that an event read twice is applied once
+func (s *TradeService) HandleChainEvent(ev *types.ChainEvent) error {
+	switch ev.Type {
+	case types.ChainEventTradeSettled:
+		t, err := s.tradeDao.GetByHash(ev.TradeHash)
+		if err != nil {
+			return err
+		}
+
+		if t == nil || t.Status == types.TradeStatusSuccess {
+			return nil
+		}
+
+		t.Status = types.TradeStatusSuccess
+		t.TxHash = ev.TxHash
+		err = s.tradeDao.Update(t)
+		if err != nil {
+			return err
+		}
+
+		// the trade is tracked once its settlement transaction is known
+		return s.finalityService.Track(types.FinalityActionTrade, t.Hash, t.TxHash)
+	case types.ChainEventTradeFailed:
+		trades, err := s.tradeDao.GetByTakerOrderHash(ev.TakerOrderHash)
+		if err != nil {
+			return err
+		}
+
+		for _, t := range trades {
+			if t.MakerOrderHash != ev.MakerOrderHash || t.Status != types.TradeStatusPending {
+				continue
+			}
+
+			logger.Warningf("Trade %s failed on the exchange contract, error %d", t.Hash.Hex(), ev.ErrorID)
+			err := s.tradeDao.UpdateTradeStatus(t.Hash, types.TradeStatusError)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 func (s *TradeService) saveBulkTrades(t *types.Trade) {
 	s.mutext.Lock()
 	defer s.mutext.Unlock()
@@ -359,12 +485,7 @@ func (s *TradeService) broadcastTickUpdate(pairs []types.PairAddresses) {
 				return
 			}
 
-			for _, tick := range ticks {
-				baseTokenAddress := tick.Pair.BaseToken
-				quoteTokenAddress := tick.Pair.QuoteToken
-				id := utils.GetTickChannelID(baseTokenAddress, quoteTokenAddress, unit, duration)
-				ws.GetOHLCVSocket().BroadcastOHLCV(id, tick)
-			}
+			s.ohlcvService.BroadcastTicks(ticks, duration, unit)
 		}
 	}
 }
@@ -376,8 +497,7 @@ func (s *TradeService) broadcastTradeUpdate(trades []*types.Trade) {
 		return
 	}
 
-	id := utils.GetTradeChannelID(p.BaseTokenAddress, p.QuoteTokenAddress)
-	ws.GetTradeSocket().BroadcastMessage(id, trades)
+	s.broadcastTrades(p.BaseTokenAddress, p.QuoteTokenAddress, trades)
 }
 
 // GetTrades filter trade
@@ -385,6 +505,27 @@ func (s *TradeService) GetTrades(tradeSpec *types.TradeSpec, sortedBy []string,
 	return s.tradeDao.GetTrades(tradeSpec, sortedBy, pageOffset, pageSize)
 }
 
+// GetVolumeProfile returns the volume traded over the window per price level and taker side
+func (s *TradeService) GetVolumeProfile(bt, qt common.Address, window time.Duration, priceStep *big.Int) (*types.TradeVolumeProfile, error) {
+	now := time.Now()
+	from := now.Add(-window)
+
+	levels, err := s.tradeDao.GetVolumeProfile(bt, qt, from, priceStep)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return &types.TradeVolumeProfile{
+		BaseToken:  bt,
+		QuoteToken: qt,
+		From:       from,
+		To:         now,
+		PriceStep:  priceStep.String(),
+		Levels:     levels,
+	}, nil
+}
+
 // GetTradesUserHistory get trade by history
 func (s *TradeService) GetTradesUserHistory(a common.Address, tradeSpec *types.TradeSpec, sortedBy []string, pageOffset int, pageSize int) (*types.TradeRes, error) {
 	return s.tradeDao.GetTradesUserHistory(a, tradeSpec, sortedBy, pageOffset, pageSize)
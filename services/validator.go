@@ -2,7 +2,6 @@ package services
 
 import (
 	"fmt"
-	m "math"
 	"math/big"
 
 	"github.com/tomochain/tomox-sdk/errors"
@@ -124,7 +123,7 @@ func (s *ValidatorService) ValidateAvailablLendingBalance(o *types.LendingOrder)
 			return err
 		}
 
-		collateralDecimals := big.NewInt(int64(m.Pow10(collateralTokenInfo.Decimals)))
+		collateralDecimals := math.Pow10(collateralTokenInfo.Decimals)
 		collateralAmount := new(big.Int).Mul(o.Quantity, collateralDecimals)
 		collateralAmount = math.Mul(collateralAmount, big.NewInt(int64(types.LendingRate)))
 		collateralAmount = new(big.Int).Div(collateralAmount, collateralPrice)
@@ -170,11 +169,11 @@ func (s *ValidatorService) ValidateAvailablLendingBalance(o *types.LendingOrder)
 
 	//Sell Token Balance
 	if sellTokenBalance.Cmp(totalRequiredAmount) == -1 {
-		return fmt.Errorf("insufficient %v Balance", sellToken.Hex(), "balance:", sellTokenBalance, "expected:", totalRequiredAmount)
+		return fmt.Errorf("insufficient %v Balance, balance: %v, expected: %v", sellToken.Hex(), sellTokenBalance, totalRequiredAmount)
 	}
 
 	if availableSellTokenBalance.Cmp(totalRequiredAmount) == -1 {
-		return fmt.Errorf("insufficient %v available", sellToken.Hex(), "available balance:", sellTokenBalance, "expected:", totalRequiredAmount)
+		return fmt.Errorf("insufficient %v available, available balance: %v, expected: %v", sellToken.Hex(), availableSellTokenBalance, totalRequiredAmount)
 	}
 
 	return nil
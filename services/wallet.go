@@ -63,3 +63,21 @@ func (s *WalletService) GetAll() ([]types.Wallet, error) {
 func (s *WalletService) GetByAddress(a common.Address) (*types.Wallet, error) {
 	return s.WalletDao.GetByAddress(a)
 }
+
+// RotateKeys encrypts the private keys of all the wallets with the current master key
+func (s *WalletService) RotateKeys() (int, error) {
+	wallets, err := s.WalletDao.GetAll()
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range wallets {
+		err := s.WalletDao.Update(&wallets[i])
+		if err != nil {
+			logger.Error(err)
+			return i, err
+		}
+	}
+
+	return len(wallets), nil
+}
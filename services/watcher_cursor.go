@@ -0,0 +1,29 @@
+package services
+
+import "github.com/tomochain/tomox-sdk/interfaces"
+
+// watcherCursor persists the last block processed by a chain log watcher in the config collection,
+// see relayer.LogCursor
+type watcherCursor struct {
+	configDao interfaces.ConfigDao
+	watcher   string
+}
+
+// LastProcessedBlock returns the last block processed by the watcher, 0 if it processed none
+func (c *watcherCursor) LastProcessedBlock() (uint64, error) {
+	return c.configDao.GetWatcherBlock(c.watcher)
+}
+
+// SaveProcessedBlock saves the last block processed by the watcher, the cursor never moves back
+func (c *watcherCursor) SaveProcessedBlock(block uint64) error {
+	last, err := c.configDao.GetWatcherBlock(c.watcher)
+	if err != nil {
+		return err
+	}
+
+	if block <= last {
+		return nil
+	}
+
+	return c.configDao.SaveWatcherBlock(c.watcher, block)
+}
@@ -0,0 +1,49 @@
+// Package tomoscan is a minimal client of the Tomoscan explorer API
+package tomoscan
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const requestTimeout = 10 * time.Second
+
+// Client sends requests to a Tomoscan API server
+type Client struct {
+	url  string
+	http *http.Client
+}
+
+// NewClient returns a new instance of Client
+func NewClient(url string) *Client {
+	return &Client{
+		url:  strings.TrimRight(url, "/"),
+		http: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// IsVerified returns true if the source of the contract is verified on Tomoscan,
+// which only serves the contracts whose source was submitted
+func (c *Client) IsVerified(addr common.Address) (bool, error) {
+	resp, err := c.http.Get(fmt.Sprintf("%s/api/contracts/%s", c.url, strings.ToLower(addr.Hex())))
+	if err != nil {
+		return false, err
+	}
+
+	defer resp.Body.Close()
+	ioutil.ReadAll(resp.Body)
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return false, nil
+	case resp.StatusCode >= 400:
+		return false, fmt.Errorf("tomoscan: contract %s returned %d", addr.Hex(), resp.StatusCode)
+	default:
+		return true, nil
+	}
+}
@@ -0,0 +1,41 @@
+package tomoscan
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsVerified(t *testing.T) {
+	verified := common.HexToAddress("0x1")
+	broken := common.HexToAddress("0x2")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/contracts/" + strings.ToLower(verified.Hex()):
+			w.Write([]byte(`{"contractName":"Token"}`))
+		case "/api/contracts/" + strings.ToLower(broken.Hex()):
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL + "/")
+
+	ok, err := c.IsVerified(verified)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = c.IsVerified(common.HexToAddress("0x3"))
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	_, err = c.IsVerified(broken)
+	assert.Error(t, err)
+}
@@ -0,0 +1,40 @@
+package types
+
+import (
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+// Kinds of the access logs
+const (
+	AccessLogHTTP = "http"
+	AccessLogWS   = "ws"
+)
+
+// AccessLog is a sampled HTTP request or websocket message of the API. The route is the template
+// of the matched route or the websocket channel, the user is a hash of the usage key of the caller,
+// removed when the log is anonymized, and the weight is the number of calls the sample stands for
+type AccessLog struct {
+	ID        bson.ObjectId `json:"id" bson:"_id"`
+	Kind      string        `json:"kind" bson:"kind"`
+	Route     string        `json:"route" bson:"route"`
+	Method    string        `json:"method" bson:"method"`
+	Status    int           `json:"status" bson:"status"`
+	LatencyMs float64       `json:"latencyMs" bson:"latencyMs"`
+	Bytes     int64         `json:"bytes" bson:"bytes"`
+	User      string        `json:"user,omitempty" bson:"user,omitempty"`
+	Weight    float64       `json:"weight" bson:"weight"`
+	CreatedAt time.Time     `json:"createdAt" bson:"createdAt"`
+}
+
+// AccessStats are the estimated calls of a route since a time, from the sampled access logs
+type AccessStats struct {
+	Kind         string  `json:"kind" bson:"kind"`
+	Route        string  `json:"route" bson:"route"`
+	Requests     float64 `json:"requests" bson:"requests"`
+	Errors       float64 `json:"errors" bson:"errors"`
+	Bytes        float64 `json:"bytes" bson:"bytes"`
+	AvgLatencyMs float64 `json:"avgLatencyMs" bson:"avgLatencyMs"`
+	MaxLatencyMs float64 `json:"maxLatencyMs" bson:"maxLatencyMs"`
+}
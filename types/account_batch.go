@@ -0,0 +1,127 @@
+package types
+
+import (
+	"encoding/json"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/errors"
+	"github.com/tomochain/tomox-sdk/utils/math"
+)
+
+// AccountBatchPayload is the list of addresses of a batch account query
+type AccountBatchPayload struct {
+	Addresses []common.Address `json:"addresses"`
+}
+
+// AccountSummary is the state of an address returned by the batch account queries: its token balances,
+// its number of open orders and its volume per pair of the last 24 hours. Error is set instead when the
+// state of the address could not be read
+type AccountSummary struct {
+	Address       common.Address
+	TokenBalances map[common.Address]*TokenBalance
+	OpenOrders    int
+	Volumes       []*AccountPairVolume
+	Error         string
+}
+
+// AccountPairVolume is the volume traded by an address on a pair, in base and quote units
+type AccountPairVolume struct {
+	PairName    string
+	BaseToken   common.Address
+	QuoteToken  common.Address
+	Volume      *big.Int
+	QuoteVolume *big.Int
+	TradeCount  int
+}
+
+// Validate checks the addresses of the payload, there are between 1 and max of them and no duplicates
+func (p *AccountBatchPayload) Validate(max int) error {
+	if len(p.Addresses) == 0 || len(p.Addresses) > max {
+		return errors.Errorf("A batch query has 1 to %d addresses", max)
+	}
+
+	seen := map[common.Address]bool{}
+	for _, a := range p.Addresses {
+		if seen[a] {
+			return errors.Errorf("Duplicate address %s", a.Hex())
+		}
+
+		seen[a] = true
+	}
+
+	return nil
+}
+
+// NewAccountPairVolumes sums the trades of an address per pair, the pairs are keyed by "BASE::QUOTE" token
+// addresses and the trades of the unknown pairs are skipped
+func NewAccountPairVolumes(trades []*Trade, pairs map[string]*Pair) []*AccountPairVolume {
+	volumes := map[string]*AccountPairVolume{}
+	for _, t := range trades {
+		code := t.BaseToken.Hex() + "::" + t.QuoteToken.Hex()
+		p := pairs[code]
+		if p == nil || t.Status == TradeStatusError || t.Amount == nil {
+			continue
+		}
+
+		v, ok := volumes[code]
+		if !ok {
+			v = &AccountPairVolume{
+				PairName:    p.Name(),
+				BaseToken:   p.BaseTokenAddress,
+				QuoteToken:  p.QuoteTokenAddress,
+				Volume:      big.NewInt(0),
+				QuoteVolume: big.NewInt(0),
+			}
+
+			volumes[code] = v
+		}
+
+		v.Volume = math.Add(v.Volume, t.Amount)
+		v.QuoteVolume = math.Add(v.QuoteVolume, t.QuoteAmount(p))
+		v.TradeCount++
+	}
+
+	res := []*AccountPairVolume{}
+	for _, v := range volumes {
+		res = append(res, v)
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].PairName < res[j].PairName })
+	return res
+}
+
+// MarshalJSON returns the json encoded byte array representing the account summary
+func (s *AccountSummary) MarshalJSON() ([]byte, error) {
+	summary := map[string]interface{}{
+		"address": s.Address.Hex(),
+	}
+
+	if s.Error != "" {
+		summary["error"] = s.Error
+		return json.Marshal(summary)
+	}
+
+	balances := map[string]*TokenBalance{}
+	for token, balance := range s.TokenBalances {
+		balances[token.Hex()] = balance
+	}
+
+	summary["tokenBalances"] = balances
+	summary["openOrders"] = s.OpenOrders
+	summary["volumes24h"] = s.Volumes
+	return json.Marshal(summary)
+}
+
+// MarshalJSON returns the json encoded byte array representing the volume of the address on the pair
+func (v *AccountPairVolume) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"pairName":    v.PairName,
+		"baseToken":   v.BaseToken.Hex(),
+		"quoteToken":  v.QuoteToken.Hex(),
+		"volume":      v.Volume.String(),
+		"quoteVolume": v.QuoteVolume.String(),
+		"tradeCount":  v.TradeCount,
+	})
+}
@@ -0,0 +1,56 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountBatchPayloadValidate(t *testing.T) {
+	p := &AccountBatchPayload{Addresses: []common.Address{common.HexToAddress("0x1"), common.HexToAddress("0x2")}}
+	assert.Nil(t, p.Validate(2))
+	assert.NotNil(t, p.Validate(1))
+
+	p.Addresses = append(p.Addresses, common.HexToAddress("0x1"))
+	assert.NotNil(t, p.Validate(5))
+
+	p.Addresses = nil
+	assert.NotNil(t, p.Validate(5))
+}
+
+func TestNewAccountPairVolumes(t *testing.T) {
+	p := &Pair{
+		BaseTokenSymbol:   "TOMO",
+		BaseTokenAddress:  common.HexToAddress("0x1"),
+		BaseTokenDecimals: 18,
+		QuoteTokenSymbol:  "USDT",
+		QuoteTokenAddress: common.HexToAddress("0x2"),
+	}
+
+	e18 := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+	trade := func(amount int64, price int64, status string) *Trade {
+		return &Trade{
+			BaseToken:  p.BaseTokenAddress,
+			QuoteToken: p.QuoteTokenAddress,
+			Amount:     new(big.Int).Mul(big.NewInt(amount), e18),
+			PricePoint: big.NewInt(price),
+			Status:     status,
+		}
+	}
+
+	trades := []*Trade{
+		trade(2, 5, SUCCESS),
+		trade(3, 10, SUCCESS),
+		trade(100, 10, TradeStatusError),
+		{BaseToken: common.HexToAddress("0x3"), QuoteToken: p.QuoteTokenAddress, Amount: big.NewInt(1)},
+	}
+
+	res := NewAccountPairVolumes(trades, map[string]*Pair{p.BaseTokenAddress.Hex() + "::" + p.QuoteTokenAddress.Hex(): p})
+	assert.Len(t, res, 1)
+	assert.Equal(t, "TOMO/USDT", res[0].PairName)
+	assert.Equal(t, new(big.Int).Mul(big.NewInt(5), e18).String(), res[0].Volume.String())
+	assert.Equal(t, "40", res[0].QuoteVolume.String())
+	assert.Equal(t, 2, res[0].TradeCount)
+}
@@ -0,0 +1,22 @@
+package types
+
+// Formats of the amounts of the API responses
+const (
+	AmountFormatWei     = "wei"
+	AmountFormatDecimal = "decimal"
+	AmountFormatFloat   = "float"
+)
+
+// AmountFormats are the supported formats of the amounts
+var AmountFormats = []string{AmountFormatWei, AmountFormatDecimal, AmountFormatFloat}
+
+// IsAmountFormat returns true if the format is supported
+func IsAmountFormat(format string) bool {
+	for _, f := range AmountFormats {
+		if f == format {
+			return true
+		}
+	}
+
+	return false
+}
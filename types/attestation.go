@@ -0,0 +1,104 @@
+package types
+
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/sha3"
+	"github.com/tomochain/tomox-sdk/errors"
+)
+
+// Types of the attested payloads
+const (
+	AttestationOrderAck     = "ORDER_ACK"
+	AttestationOrderAdded   = "ORDER_ADDED"
+	AttestationOrderSuccess = "ORDER_SUCCESS"
+)
+
+// Attestation is the signature by the relayer of a payload reported to a client. Message is the JSON of
+// the payload as it was signed, the hash covers the type, the signature time and the message and is
+// signed as an Ethereum signed message, so that the client can keep a proof of what the venue reported
+type Attestation struct {
+	Type      string         `json:"type"`
+	Message   string         `json:"message"`
+	Hash      common.Hash    `json:"hash"`
+	Signer    common.Address `json:"signer"`
+	Signature *Signature     `json:"signature"`
+	SignedAt  int64          `json:"signedAt"`
+}
+
+// NewAttestation returns the unsigned attestation of the JSON of a payload
+func NewAttestation(kind string, payload interface{}, signedAt int64) (*Attestation, error) {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Attestation{Type: kind, Message: string(b), SignedAt: signedAt}, nil
+}
+
+// ComputeHash computes the hash of the type, the signature time and the message of the attestation
+func (a *Attestation) ComputeHash() common.Hash {
+	sha := sha3.NewKeccak256()
+	sha.Write([]byte(a.Type))
+	sha.Write(common.BigToHash(big.NewInt(a.SignedAt)).Bytes())
+	sha.Write([]byte(a.Message))
+	return common.BytesToHash(sha.Sum(nil))
+}
+
+// Sign computes the hash of the attestation then signs it with the wallet
+func (a *Attestation) Sign(w *Wallet) error {
+	h := a.ComputeHash()
+	sig, err := w.SignHash(h)
+	if err != nil {
+		return err
+	}
+
+	a.Hash = h
+	a.Signer = w.Address
+	a.Signature = sig
+	return nil
+}
+
+// Verify returns an error if the attestation was not signed by its signer
+func (a *Attestation) Verify() error {
+	if a.Signature == nil || a.ComputeHash() != a.Hash {
+		return errors.New("Invalid hash")
+	}
+
+	message := crypto.Keccak256(
+		[]byte("\x19Ethereum Signed Message:\n32"),
+		a.Hash.Bytes(),
+	)
+
+	signer, err := a.Signature.Verify(common.BytesToHash(message))
+	if err != nil {
+		return err
+	}
+
+	if signer != a.Signer {
+		return errors.New("Recovered address is incorrect")
+	}
+
+	return nil
+}
+
+// AttachTo returns the JSON object of the payload with the attestation in its "attestation" field
+func (a *Attestation) AttachTo() (json.RawMessage, error) {
+	fields := map[string]json.RawMessage{}
+
+	err := json.Unmarshal([]byte(a.Message), &fields)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+
+	fields["attestation"] = b
+	return json.Marshal(fields)
+}
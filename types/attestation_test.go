@@ -0,0 +1,40 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttestationVerify(t *testing.T) {
+	w := NewWallet()
+	payload := map[string]string{"hash": common.HexToHash("0x1").Hex(), "status": "ADDED"}
+
+	a, err := NewAttestation(AttestationOrderAck, payload, time.Now().Unix())
+	assert.NoError(t, err)
+	assert.Error(t, a.Verify())
+
+	err = a.Sign(w)
+	assert.NoError(t, err)
+	assert.NoError(t, a.Verify())
+	assert.Equal(t, w.Address, a.Signer)
+
+	b, err := a.AttachTo()
+	assert.NoError(t, err)
+
+	decoded := struct {
+		Status      string       `json:"status"`
+		Attestation *Attestation `json:"attestation"`
+	}{}
+
+	err = json.Unmarshal(b, &decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, "ADDED", decoded.Status)
+	assert.NoError(t, decoded.Attestation.Verify())
+
+	decoded.Attestation.Message = `{"hash":"0x1","status":"CANCELLED"}`
+	assert.Error(t, decoded.Attestation.Verify())
+}
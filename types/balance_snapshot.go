@@ -0,0 +1,134 @@
+package types
+
+import (
+	"encoding/json"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/utils/math"
+)
+
+// BalanceSnapshot holds the token balances of an address at a time
+type BalanceSnapshot struct {
+	ID        bson.ObjectId
+	Address   common.Address
+	Balances  map[common.Address]*big.Int
+	CreatedAt time.Time
+}
+
+// BalanceSnapshotRecord is the balance snapshot stored in the database
+type BalanceSnapshotRecord struct {
+	ID        bson.ObjectId     `bson:"_id"`
+	Address   string            `bson:"address"`
+	Balances  map[string]string `bson:"balances"`
+	CreatedAt time.Time         `bson:"createdAt"`
+}
+
+// HistoricalBalances are the balances of an address reconstructed at a past time from the
+// closest snapshot and the trades between the snapshot and that time.
+// The transfers made outside of the exchange are not included, the balances are approximate
+type HistoricalBalances struct {
+	Address    common.Address
+	At         time.Time
+	SnapshotAt time.Time
+	Trades     int
+	Balances   map[common.Address]*big.Int
+}
+
+// NewHistoricalBalances applies the balance changes of the trades made between the snapshot and a time.
+// The changes are added if the snapshot is before the time and removed if it is after,
+// the trades must be those of the address between the two times
+func NewHistoricalBalances(s *BalanceSnapshot, at time.Time, trades []*Trade, pairs map[string]*Pair) *HistoricalBalances {
+	h := &HistoricalBalances{
+		Address:    s.Address,
+		At:         at,
+		SnapshotAt: s.CreatedAt,
+		Balances:   make(map[common.Address]*big.Int),
+	}
+
+	for token, balance := range s.Balances {
+		h.Balances[token] = balance
+	}
+
+	var changes []*BalanceEvidence
+	for _, t := range trades {
+		p := pairs[t.BaseToken.Hex()+"::"+t.QuoteToken.Hex()]
+		if p == nil || t.Status == TradeStatusError {
+			continue
+		}
+
+		changes = AddTradeBalanceChanges(changes, t, p)
+		h.Trades++
+	}
+
+	for _, c := range changes {
+		if c.Address != s.Address {
+			continue
+		}
+
+		balance := h.Balances[c.Token]
+		if balance == nil {
+			balance = big.NewInt(0)
+		}
+
+		if s.CreatedAt.After(at) {
+			h.Balances[c.Token] = math.Sub(balance, c.Change)
+		} else {
+			h.Balances[c.Token] = math.Add(balance, c.Change)
+		}
+	}
+
+	return h
+}
+
+// GetBSON implements bson.Getter
+func (s *BalanceSnapshot) GetBSON() (interface{}, error) {
+	balances := make(map[string]string)
+	for token, balance := range s.Balances {
+		balances[token.Hex()] = balance.String()
+	}
+
+	return BalanceSnapshotRecord{
+		ID:        s.ID,
+		Address:   s.Address.Hex(),
+		Balances:  balances,
+		CreatedAt: s.CreatedAt,
+	}, nil
+}
+
+// SetBSON implements bson.Setter
+func (s *BalanceSnapshot) SetBSON(raw bson.Raw) error {
+	decoded := &BalanceSnapshotRecord{}
+	err := raw.Unmarshal(decoded)
+	if err != nil {
+		return err
+	}
+
+	s.ID = decoded.ID
+	s.Address = common.HexToAddress(decoded.Address)
+	s.Balances = make(map[common.Address]*big.Int)
+	for token, balance := range decoded.Balances {
+		s.Balances[common.HexToAddress(token)] = math.ToBigInt(balance)
+	}
+
+	s.CreatedAt = decoded.CreatedAt
+	return nil
+}
+
+// MarshalJSON returns the json encoded byte array representing the historical balances
+func (h *HistoricalBalances) MarshalJSON() ([]byte, error) {
+	balances := make(map[string]string)
+	for token, balance := range h.Balances {
+		balances[token.Hex()] = balance.String()
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"address":    h.Address.Hex(),
+		"at":         h.At.Unix(),
+		"snapshotAt": h.SnapshotAt.Unix(),
+		"trades":     h.Trades,
+		"balances":   balances,
+	})
+}
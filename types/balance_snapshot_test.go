@@ -0,0 +1,66 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHistoricalBalances(t *testing.T) {
+	user := common.HexToAddress("0x1")
+	other := common.HexToAddress("0x2")
+	p := &Pair{
+		BaseTokenAddress:   common.HexToAddress("0x3"),
+		BaseTokenDecimals:  18,
+		QuoteTokenAddress:  common.HexToAddress("0x4"),
+		QuoteTokenDecimals: 18,
+	}
+	pairs := map[string]*Pair{p.Code(): p}
+
+	now := time.Now()
+	s := &BalanceSnapshot{
+		Address: user,
+		Balances: map[common.Address]*big.Int{
+			p.BaseTokenAddress:  big.NewInt(5e18),
+			p.QuoteTokenAddress: big.NewInt(9e18),
+		},
+		CreatedAt: now,
+	}
+
+	trades := []*Trade{
+		{
+			Maker:          other,
+			Taker:          user,
+			BaseToken:      p.BaseTokenAddress,
+			QuoteToken:     p.QuoteTokenAddress,
+			TakerOrderSide: BUY,
+			Amount:         big.NewInt(1e18),
+			PricePoint:     big.NewInt(2e18),
+			MakeFee:        big.NewInt(0),
+			TakeFee:        big.NewInt(0),
+			Status:         TradeStatusSuccess,
+		},
+		{
+			Maker:      other,
+			Taker:      user,
+			BaseToken:  p.BaseTokenAddress,
+			QuoteToken: p.QuoteTokenAddress,
+			Amount:     big.NewInt(5e18),
+			PricePoint: big.NewInt(2e18),
+			Status:     TradeStatusError,
+		},
+	}
+
+	h := NewHistoricalBalances(s, now.Add(time.Hour), trades, pairs)
+	assert.Equal(t, 1, h.Trades)
+	assert.Equal(t, "6000000000000000000", h.Balances[p.BaseTokenAddress].String())
+	assert.Equal(t, "7000000000000000000", h.Balances[p.QuoteTokenAddress].String())
+
+	h = NewHistoricalBalances(s, now.Add(-time.Hour), trades, pairs)
+	assert.Equal(t, "4000000000000000000", h.Balances[p.BaseTokenAddress].String())
+	assert.Equal(t, "11000000000000000000", h.Balances[p.QuoteTokenAddress].String())
+	assert.Equal(t, "5000000000000000000", s.Balances[p.BaseTokenAddress].String())
+}
@@ -0,0 +1,74 @@
+package types
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/relayer"
+)
+
+// Chain status values reported by the health check
+const (
+	ChainStatusOK      = "OK"
+	ChainStatusLagging = "LAGGING"
+	ChainStatusUnknown = "UNKNOWN"
+)
+
+// BlockHeader is the summary of a block header sent over the blocks channel
+type BlockHeader struct {
+	Number     uint64      `json:"number"`
+	Hash       common.Hash `json:"hash"`
+	ParentHash common.Hash `json:"parentHash"`
+	Timestamp  int64       `json:"timestamp"`
+	TxCount    uint        `json:"txCount"`
+}
+
+// ChainStatus describes how far the node connected to the SDK is behind the chain head
+type ChainStatus struct {
+	Status      string       `json:"status"`
+	LatestBlock *BlockHeader `json:"latestBlock"`
+	Lag         int64        `json:"lag"`
+	MaxLag      int64        `json:"maxLag"`
+}
+
+// Health is the readiness reported by /healthz, the chain status from the latest received block header
+// and the health check of the node answering the contract calls. The SDK is ready if both are healthy,
+// the metrics of the clients of the other dependencies are details
+type Health struct {
+	*ChainStatus
+	Ready        bool                 `json:"ready"`
+	Node         *relayer.ChainHealth `json:"node"`
+	Dependencies *DependencyMetrics   `json:"dependencies,omitempty"`
+}
+
+// NewChainStatus computes the chain status from the latest known block header.
+// The lag is the number of seconds between now and the block timestamp.
+func NewChainStatus(h *BlockHeader, now time.Time, maxLag int64) *ChainStatus {
+	s := &ChainStatus{
+		Status:      ChainStatusUnknown,
+		LatestBlock: h,
+		MaxLag:      maxLag,
+	}
+
+	if h == nil {
+		return s
+	}
+
+	s.Lag = now.Unix() - h.Timestamp
+	if s.Lag < 0 {
+		s.Lag = 0
+	}
+
+	if s.Lag > maxLag {
+		s.Status = ChainStatusLagging
+	} else {
+		s.Status = ChainStatusOK
+	}
+
+	return s
+}
+
+// IsHealthy returns true if the node is known to be in sync with the chain
+func (s *ChainStatus) IsHealthy() bool {
+	return s.Status == ChainStatusOK
+}
@@ -0,0 +1,26 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewChainStatus(t *testing.T) {
+	now := time.Unix(1000, 0)
+
+	s := NewChainStatus(nil, now, 60)
+	assert.Equal(t, ChainStatusUnknown, s.Status)
+	assert.False(t, s.IsHealthy())
+
+	s = NewChainStatus(&BlockHeader{Number: 1, Timestamp: 980}, now, 60)
+	assert.Equal(t, ChainStatusOK, s.Status)
+	assert.Equal(t, int64(20), s.Lag)
+	assert.True(t, s.IsHealthy())
+
+	s = NewChainStatus(&BlockHeader{Number: 1, Timestamp: 900}, now, 60)
+	assert.Equal(t, ChainStatusLagging, s.Status)
+	assert.Equal(t, int64(100), s.Lag)
+	assert.False(t, s.IsHealthy())
+}
@@ -0,0 +1,125 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Stages of a canary probe, timed from the submission of the order reaching them
+const (
+	CanaryStageSubmit    = "submit"
+	CanaryStageAck       = "ack"
+	CanaryStageMatch     = "match"
+	CanaryStageBroadcast = "broadcast"
+)
+
+// CanaryStages are the stages of a canary probe in order
+var CanaryStages = []string{CanaryStageSubmit, CanaryStageAck, CanaryStageMatch, CanaryStageBroadcast}
+
+// CanaryProbe is a run of the synthetic order canary: a maker order is submitted on the test pair,
+// then a taker order matching it. Submit is the time NewOrder takes, ack the time until the maker order
+// is added to the book, match and broadcast the times until the trade of the taker order is sent to
+// the canary and published on the trades channel. The latencies are in milliseconds, a stage not
+// reached before the timeout is missing
+type CanaryProbe struct {
+	StartedAt time.Time        `json:"startedAt"`
+	PairName  string           `json:"pairName"`
+	MakerHash common.Hash      `json:"makerHash"`
+	TakerHash common.Hash      `json:"takerHash"`
+	Latencies map[string]int64 `json:"latencies"`
+	Breaches  []string         `json:"breaches"`
+	Error     string           `json:"error,omitempty"`
+}
+
+// CanaryStats summarizes the recent probes of the canary, the percentiles are in milliseconds
+type CanaryStats struct {
+	Probes   int              `json:"probes"`
+	Breached int              `json:"breached"`
+	P50      map[string]int64 `json:"p50"`
+	P95      map[string]int64 `json:"p95"`
+	Max      map[string]int64 `json:"max"`
+	Latest   []*CanaryProbe   `json:"latest"`
+}
+
+// NewCanaryProbe returns a probe started now
+func NewCanaryProbe(pairName string, now time.Time) *CanaryProbe {
+	return &CanaryProbe{
+		StartedAt: now,
+		PairName:  pairName,
+		Latencies: make(map[string]int64),
+		Breaches:  []string{},
+	}
+}
+
+// Record sets the latency of a stage
+func (p *CanaryProbe) Record(stage string, d time.Duration) {
+	p.Latencies[stage] = int64(d / time.Millisecond)
+}
+
+// Complete returns true if all the stages were reached
+func (p *CanaryProbe) Complete() bool {
+	for _, s := range CanaryStages {
+		if _, ok := p.Latencies[s]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Evaluate sets the breaches of the probe: the stages not reached and the ones slower than their
+// threshold (a zero threshold is not checked)
+func (p *CanaryProbe) Evaluate(thresholds map[string]time.Duration) []string {
+	p.Breaches = []string{}
+	for _, s := range CanaryStages {
+		latency, ok := p.Latencies[s]
+		if !ok {
+			p.Breaches = append(p.Breaches, fmt.Sprintf("%s not reached", s))
+			continue
+		}
+
+		if t := thresholds[s]; t > 0 && latency > int64(t/time.Millisecond) {
+			p.Breaches = append(p.Breaches, fmt.Sprintf("%s took %dms, above %dms", s, latency, int64(t/time.Millisecond)))
+		}
+	}
+
+	return p.Breaches
+}
+
+// NewCanaryStats returns the summary of the probes with the latest ones, newest first
+func NewCanaryStats(probes []*CanaryProbe, latest int) *CanaryStats {
+	s := &CanaryStats{
+		Probes: len(probes),
+		P50:    make(map[string]int64),
+		P95:    make(map[string]int64),
+		Max:    make(map[string]int64),
+		Latest: []*CanaryProbe{},
+	}
+
+	latencies := make(map[string][]int64)
+	for _, p := range probes {
+		if len(p.Breaches) > 0 {
+			s.Breached++
+		}
+
+		for stage, l := range p.Latencies {
+			latencies[stage] = append(latencies[stage], l)
+		}
+	}
+
+	for stage, l := range latencies {
+		sort.Slice(l, func(i, j int) bool { return l[i] < l[j] })
+		s.P50[stage] = l[(len(l)-1)*50/100]
+		s.P95[stage] = l[(len(l)-1)*95/100]
+		s.Max[stage] = l[len(l)-1]
+	}
+
+	for i := len(probes) - 1; i >= 0 && len(s.Latest) < latest; i-- {
+		s.Latest = append(s.Latest, probes[i])
+	}
+
+	return s
+}
@@ -0,0 +1,46 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanaryProbeEvaluate(t *testing.T) {
+	p := NewCanaryProbe("TOMO/USDT", time.Now())
+	p.Record(CanaryStageSubmit, 20*time.Millisecond)
+	p.Record(CanaryStageAck, 1500*time.Millisecond)
+	p.Record(CanaryStageMatch, 3*time.Second)
+
+	thresholds := map[string]time.Duration{CanaryStageAck: time.Second, CanaryStageMatch: 5 * time.Second}
+	assert.False(t, p.Complete())
+	assert.Equal(t, []string{"ack took 1500ms, above 1000ms", "broadcast not reached"}, p.Evaluate(thresholds))
+
+	p.Record(CanaryStageBroadcast, 3100*time.Millisecond)
+	thresholds[CanaryStageAck] = 2 * time.Second
+	assert.True(t, p.Complete())
+	assert.Empty(t, p.Evaluate(thresholds))
+}
+
+func TestNewCanaryStats(t *testing.T) {
+	probes := []*CanaryProbe{}
+	for i := 1; i <= 20; i++ {
+		p := NewCanaryProbe("TOMO/USDT", time.Now())
+		p.Record(CanaryStageAck, time.Duration(i)*time.Millisecond)
+		if i == 20 {
+			p.Breaches = []string{"ack took 20ms, above 10ms"}
+		}
+
+		probes = append(probes, p)
+	}
+
+	s := NewCanaryStats(probes, 3)
+	assert.Equal(t, 20, s.Probes)
+	assert.Equal(t, 1, s.Breached)
+	assert.Equal(t, int64(10), s.P50[CanaryStageAck])
+	assert.Equal(t, int64(19), s.P95[CanaryStageAck])
+	assert.Equal(t, int64(20), s.Max[CanaryStageAck])
+	assert.Equal(t, 3, len(s.Latest))
+	assert.Equal(t, probes[19], s.Latest[0])
+}
@@ -0,0 +1,41 @@
+package types
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Types of the events of the exchange contract read by the chain listener
+const (
+	ChainEventTradeSettled   = "TRADE_SETTLED"
+	ChainEventTradeFailed    = "TRADE_FAILED"
+	ChainEventOrderCancelled = "ORDER_CANCELLED"
+)
+
+// ChainEvent is a log of the exchange contract decoded by the chain listener. A settled trade carries
+// its hash, the hash of its order and its parties, a failed trade the hashes of its maker and taker
+// orders with the error code of the contract and a cancelled order its hash, its owner and its amount
+type ChainEvent struct {
+	Type           string         `json:"type"`
+	BlockNumber    uint64         `json:"blockNumber"`
+	TxHash         common.Hash    `json:"txHash"`
+	LogIndex       uint           `json:"logIndex"`
+	TradeHash      common.Hash    `json:"tradeHash,omitempty"`
+	OrderHash      common.Hash    `json:"orderHash,omitempty"`
+	MakerOrderHash common.Hash    `json:"makerOrderHash,omitempty"`
+	TakerOrderHash common.Hash    `json:"takerOrderHash,omitempty"`
+	Maker          common.Address `json:"maker,omitempty"`
+	Taker          common.Address `json:"taker,omitempty"`
+	UserAddress    common.Address `json:"userAddress,omitempty"`
+	Amount         *big.Int       `json:"amount,omitempty"`
+	ErrorID        uint8          `json:"errorId,omitempty"`
+}
+
+// TokenTransfer is a Transfer log of a token contract
+type TokenTransfer struct {
+	Token common.Address `json:"token"`
+	From  common.Address `json:"from"`
+	To    common.Address `json:"to"`
+	Value *big.Int       `json:"value"`
+}
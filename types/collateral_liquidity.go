@@ -0,0 +1,33 @@
+package types
+
+import (
+	"math/big"
+
+	"github.com/tomochain/tomox-sdk/utils/math"
+)
+
+// IlliquidCollateralMessage is the message type of the alerts sent to the lenders of loans whose
+// collateral became illiquid
+const IlliquidCollateralMessage = "ILLIQUID_COLLATERAL"
+
+// BidDepth returns the amount of base token bid within bandBps basis points of the best bid.
+// The bids are ordered from the best price, as returned by the order book
+func BidDepth(bids []map[string]string, bandBps int) *big.Int {
+	depth := big.NewInt(0)
+	if len(bids) == 0 {
+		return depth
+	}
+
+	best := math.ToBigInt(bids[0]["pricepoint"])
+	min := math.Div(math.Mul(best, big.NewInt(int64(10000-bandBps))), big.NewInt(10000))
+
+	for _, b := range bids {
+		if math.IsStrictlySmallerThan(math.ToBigInt(b["pricepoint"]), min) {
+			break
+		}
+
+		depth = math.Add(depth, math.ToBigInt(b["amount"]))
+	}
+
+	return depth
+}
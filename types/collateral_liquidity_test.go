@@ -0,0 +1,20 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBidDepth(t *testing.T) {
+	bids := []map[string]string{
+		{"pricepoint": "1000", "amount": "5"},
+		{"pricepoint": "990", "amount": "7"},
+		{"pricepoint": "980", "amount": "11"},
+		{"pricepoint": "979", "amount": "13"},
+	}
+
+	assert.Equal(t, "23", BidDepth(bids, 200).String())
+	assert.Equal(t, "5", BidDepth(bids, 0).String())
+	assert.Equal(t, "0", BidDepth(nil, 200).String())
+}
@@ -0,0 +1,152 @@
+package types
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/errors"
+)
+
+// Limits of the composite indices
+const (
+	DefaultIndexBaseValue = 1000
+	MaxIndexComponents    = 20
+)
+
+var indexNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,31}$`)
+
+// IndexComponent is a token of a composite index with its share of the index, the weights of the
+// components add up to 1, and its price in the fiat currency when the index was last rebased
+type IndexComponent struct {
+	Token     common.Address `json:"token" bson:"token"`
+	Symbol    string         `json:"symbol" bson:"symbol"`
+	Weight    float64        `json:"weight" bson:"weight"`
+	BasePrice float64        `json:"basePrice" bson:"basePrice"`
+}
+
+// CompositeIndex is a weighted basket of listed tokens defined by the operators. Its value is the base
+// value times the sum of the weighted changes of the prices of its components since the last rebase
+type CompositeIndex struct {
+	ID          bson.ObjectId     `json:"id" bson:"_id"`
+	Name        string            `json:"name" bson:"name"`
+	Description string            `json:"description" bson:"description"`
+	BaseValue   float64           `json:"baseValue" bson:"baseValue"`
+	Components  []*IndexComponent `json:"components" bson:"components"`
+	RebasedAt   time.Time         `json:"rebasedAt" bson:"rebasedAt"`
+	CreatedBy   string            `json:"createdBy" bson:"createdBy"`
+	CreatedAt   time.Time         `json:"createdAt" bson:"createdAt"`
+	UpdatedAt   time.Time         `json:"updatedAt" bson:"updatedAt"`
+}
+
+// IndexComponentPayload is a token of an index and its weight, the weights are relative to each other
+type IndexComponentPayload struct {
+	Token  common.Address `json:"token"`
+	Weight float64        `json:"weight"`
+}
+
+// CompositeIndexPayload is the definition of an index sent by the operators
+type CompositeIndexPayload struct {
+	Name        string                   `json:"name"`
+	Description string                   `json:"description"`
+	BaseValue   float64                  `json:"baseValue"`
+	Components  []*IndexComponentPayload `json:"components"`
+}
+
+// IndexComponentValue is the price of a component of an index and its part of the value of the index
+type IndexComponentValue struct {
+	Token        common.Address `json:"token"`
+	Symbol       string         `json:"symbol"`
+	Weight       float64        `json:"weight"`
+	Price        float64        `json:"price"`
+	Contribution float64        `json:"contribution"`
+}
+
+// IndexValue is the value of a composite index computed from the prices of its components
+type IndexValue struct {
+	Name       string                 `json:"name"`
+	Value      float64                `json:"value"`
+	Components []*IndexComponentValue `json:"components"`
+	Timestamp  int64                  `json:"timestamp"`
+}
+
+// Validate checks the payload of an index, its name is lower case letters, digits and dashes
+func (p *CompositeIndexPayload) Validate() error {
+	if !indexNameRegexp.MatchString(p.Name) {
+		return errors.New("Invalid name, use up to 32 lower case letters, digits and dashes")
+	}
+
+	if p.BaseValue < 0 {
+		return errors.New("Invalid base value")
+	}
+
+	if len(p.Components) == 0 || len(p.Components) > MaxIndexComponents {
+		return errors.Errorf("An index has 1 to %d components", MaxIndexComponents)
+	}
+
+	seen := map[common.Address]bool{}
+	for _, c := range p.Components {
+		if c == nil || (c.Token == common.Address{}) || c.Weight <= 0 {
+			return errors.New("Invalid component, a component needs a token and a positive weight")
+		}
+
+		if seen[c.Token] {
+			return errors.Errorf("Duplicate component %s", c.Token.Hex())
+		}
+
+		seen[c.Token] = true
+	}
+
+	return nil
+}
+
+// Rebase sets the components of the index with their weights normalized and the current prices as
+// their base prices, the value of the index is kept
+func (i *CompositeIndex) Rebase(components []*IndexComponentPayload, symbols map[common.Address]string, prices map[common.Address]float64, value float64, now time.Time) {
+	total := 0.0
+	for _, c := range components {
+		total += c.Weight
+	}
+
+	i.Components = []*IndexComponent{}
+	for _, c := range components {
+		i.Components = append(i.Components, &IndexComponent{
+			Token:     c.Token,
+			Symbol:    symbols[c.Token],
+			Weight:    c.Weight / total,
+			BasePrice: prices[c.Token],
+		})
+	}
+
+	i.BaseValue = value
+	i.RebasedAt = now
+}
+
+// Value computes the value of the index from the prices of its components, all of them need a price
+func (i *CompositeIndex) Value(prices map[common.Address]float64, now time.Time) (*IndexValue, error) {
+	res := &IndexValue{
+		Name:       i.Name,
+		Components: []*IndexComponentValue{},
+		Timestamp:  now.Unix(),
+	}
+
+	for _, c := range i.Components {
+		price, ok := prices[c.Token]
+		if !ok || price <= 0 || c.BasePrice <= 0 {
+			return nil, errors.Errorf("Price of %s not found", c.Symbol)
+		}
+
+		contribution := i.BaseValue * c.Weight * price / c.BasePrice
+		res.Value += contribution
+		res.Components = append(res.Components, &IndexComponentValue{
+			Token:        c.Token,
+			Symbol:       c.Symbol,
+			Weight:       c.Weight,
+			Price:        price,
+			Contribution: contribution,
+		})
+	}
+
+	return res, nil
+}
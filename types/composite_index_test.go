@@ -0,0 +1,69 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompositeIndexPayloadValidate(t *testing.T) {
+	a := common.HexToAddress("0x1")
+	b := common.HexToAddress("0x2")
+
+	p := &CompositeIndexPayload{Name: "defi-10", Components: []*IndexComponentPayload{{Token: a, Weight: 1}, {Token: b, Weight: 3}}}
+	assert.Nil(t, p.Validate())
+
+	p.Name = "DeFi"
+	assert.NotNil(t, p.Validate())
+
+	p.Name = "defi"
+	p.BaseValue = -1
+	assert.NotNil(t, p.Validate())
+
+	p.BaseValue = 0
+	p.Components = []*IndexComponentPayload{{Token: a, Weight: 1}, {Token: a, Weight: 1}}
+	assert.NotNil(t, p.Validate())
+
+	p.Components = []*IndexComponentPayload{{Token: a, Weight: 0}}
+	assert.NotNil(t, p.Validate())
+
+	p.Components = []*IndexComponentPayload{}
+	assert.NotNil(t, p.Validate())
+}
+
+func TestCompositeIndexRebaseAndValue(t *testing.T) {
+	a := common.HexToAddress("0x1")
+	b := common.HexToAddress("0x2")
+	now := time.Unix(100, 0)
+
+	i := &CompositeIndex{Name: "defi"}
+	components := []*IndexComponentPayload{{Token: a, Weight: 1}, {Token: b, Weight: 3}}
+	symbols := map[common.Address]string{a: "AAA", b: "BBB"}
+	i.Rebase(components, symbols, map[common.Address]float64{a: 2, b: 10}, 1000, now)
+
+	assert.Equal(t, 0.25, i.Components[0].Weight)
+	assert.Equal(t, 0.75, i.Components[1].Weight)
+	assert.Equal(t, "BBB", i.Components[1].Symbol)
+
+	v, err := i.Value(map[common.Address]float64{a: 2, b: 10}, now)
+	assert.Nil(t, err)
+	assert.Equal(t, 1000.0, v.Value)
+
+	// a doubles, b is unchanged
+	v, err = i.Value(map[common.Address]float64{a: 4, b: 10}, now)
+	assert.Nil(t, err)
+	assert.Equal(t, 1250.0, v.Value)
+	assert.Equal(t, 500.0, v.Components[0].Contribution)
+	assert.Equal(t, int64(100), v.Timestamp)
+
+	// rebasing keeps the value of the index
+	i.Rebase([]*IndexComponentPayload{{Token: a, Weight: 1}}, symbols, map[common.Address]float64{a: 4}, v.Value, now)
+	v, err = i.Value(map[common.Address]float64{a: 4}, now)
+	assert.Nil(t, err)
+	assert.Equal(t, 1250.0, v.Value)
+
+	_, err = i.Value(map[common.Address]float64{b: 4}, now)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,420 @@
+package types
+
+import (
+	"encoding/json"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/utils/math"
+)
+
+// DailyStatsDateLayout is the layout of the dates of the daily aggregates
+const DailyStatsDateLayout = "2006-01-02"
+
+// UserDailyVolume is the volume traded by a user on a pair during a UTC day.
+// The quote volume, the fees paid and the maker rebates owed are in quote token
+type UserDailyVolume struct {
+	Date        string
+	Address     common.Address
+	PairName    string
+	BaseToken   common.Address
+	QuoteToken  common.Address
+	Volume      *big.Int
+	QuoteVolume *big.Int
+	Fees        *big.Int
+	Rebates     *big.Int
+	TradeCount  int
+	UpdatedAt   time.Time
+}
+
+// UserDailyVolumeRecord is the user daily volume stored in the database
+type UserDailyVolumeRecord struct {
+	ID          bson.ObjectId `bson:"_id,omitempty"`
+	Date        string        `bson:"date"`
+	Address     string        `bson:"address"`
+	PairName    string        `bson:"pairName"`
+	BaseToken   string        `bson:"baseToken"`
+	QuoteToken  string        `bson:"quoteToken"`
+	Volume      string        `bson:"volume"`
+	QuoteVolume string        `bson:"quoteVolume"`
+	Fees        string        `bson:"fees"`
+	Rebates     string        `bson:"rebates"`
+	TradeCount  int           `bson:"tradeCount"`
+	UpdatedAt   time.Time     `bson:"updatedAt"`
+}
+
+// PairDailyStats is the price range, the volume, the fees and the maker rebates of a pair during a UTC day
+type PairDailyStats struct {
+	Date         string
+	PairName     string
+	BaseToken    common.Address
+	QuoteToken   common.Address
+	Open         *big.Int
+	High         *big.Int
+	Low          *big.Int
+	Close        *big.Int
+	Volume       *big.Int
+	QuoteVolume  *big.Int
+	MakeFees     *big.Int
+	TakeFees     *big.Int
+	MakerRebates *big.Int
+	TradeCount   int
+	Traders      int
+	UpdatedAt    time.Time
+}
+
+// PairDailyStatsRecord is the pair daily stats stored in the database
+type PairDailyStatsRecord struct {
+	ID           bson.ObjectId `bson:"_id,omitempty"`
+	Date         string        `bson:"date"`
+	PairName     string        `bson:"pairName"`
+	BaseToken    string        `bson:"baseToken"`
+	QuoteToken   string        `bson:"quoteToken"`
+	Open         string        `bson:"open"`
+	High         string        `bson:"high"`
+	Low          string        `bson:"low"`
+	Close        string        `bson:"close"`
+	Volume       string        `bson:"volume"`
+	QuoteVolume  string        `bson:"quoteVolume"`
+	MakeFees     string        `bson:"makeFees"`
+	TakeFees     string        `bson:"takeFees"`
+	MakerRebates string        `bson:"makerRebates"`
+	TradeCount   int           `bson:"tradeCount"`
+	Traders      int           `bson:"traders"`
+	UpdatedAt    time.Time     `bson:"updatedAt"`
+}
+
+// FeeTotal is the fees collected in a token during a UTC day and the maker rebates owed from them
+type FeeTotal struct {
+	Date         string
+	Token        common.Address
+	MakeFees     *big.Int
+	TakeFees     *big.Int
+	MakerRebates *big.Int
+	TradeCount   int
+	UpdatedAt    time.Time
+}
+
+// FeeTotalRecord is the fee total stored in the database
+type FeeTotalRecord struct {
+	ID           bson.ObjectId `bson:"_id,omitempty"`
+	Date         string        `bson:"date"`
+	Token        string        `bson:"token"`
+	MakeFees     string        `bson:"makeFees"`
+	TakeFees     string        `bson:"takeFees"`
+	MakerRebates string        `bson:"makerRebates"`
+	TradeCount   int           `bson:"tradeCount"`
+	UpdatedAt    time.Time     `bson:"updatedAt"`
+}
+
+// DailyAggregates holds the aggregates of the trades of a UTC day, they are materialized
+// in dedicated collections so that the reports never scan the trades
+type DailyAggregates struct {
+	Date  string
+	Users []*UserDailyVolume
+	Pairs []*PairDailyStats
+	Fees  []*FeeTotal
+
+	users map[string]*UserDailyVolume
+	fees  map[common.Address]*FeeTotal
+}
+
+// NewDailyAggregates returns the empty aggregates of the UTC day of a time
+func NewDailyAggregates(day time.Time) *DailyAggregates {
+	return &DailyAggregates{
+		Date:  day.UTC().Format(DailyStatsDateLayout),
+		Users: []*UserDailyVolume{},
+		Pairs: []*PairDailyStats{},
+		Fees:  []*FeeTotal{},
+		users: make(map[string]*UserDailyVolume),
+		fees:  make(map[common.Address]*FeeTotal),
+	}
+}
+
+// AddPairTrades adds the trades of a pair during the day, the failed trades are ignored. The makers with a
+// rebate tier are owed the rebate of the tier on their trades, a nil map gives no rebate
+func (a *DailyAggregates) AddPairTrades(p *Pair, trades []*Trade, tiers map[common.Address]*RebateTier) {
+	sorted := make([]*Trade, 0, len(trades))
+	for _, t := range trades {
+		if t.Status != TradeStatusError {
+			sorted = append(sorted, t)
+		}
+	}
+
+	if len(sorted) == 0 {
+		return
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].CreatedAt.Before(sorted[j].CreatedAt) })
+
+	stats := &PairDailyStats{
+		Date:         a.Date,
+		PairName:     p.Name(),
+		BaseToken:    p.BaseTokenAddress,
+		QuoteToken:   p.QuoteTokenAddress,
+		Open:         sorted[0].PricePoint,
+		High:         sorted[0].PricePoint,
+		Low:          sorted[0].PricePoint,
+		Close:        sorted[len(sorted)-1].PricePoint,
+		Volume:       big.NewInt(0),
+		QuoteVolume:  big.NewInt(0),
+		MakeFees:     big.NewInt(0),
+		TakeFees:     big.NewInt(0),
+		MakerRebates: big.NewInt(0),
+	}
+
+	traders := make(map[common.Address]bool)
+	for _, t := range sorted {
+		quoteAmount := p.QuoteAmount(t.Amount, t.PricePoint)
+		rebate := big.NewInt(0)
+		if tier := tiers[t.Maker]; tier != nil {
+			rebate = tier.Rebate(p, t)
+		}
+
+		stats.High = math.Max(stats.High, t.PricePoint)
+		if t.PricePoint.Cmp(stats.Low) < 0 {
+			stats.Low = t.PricePoint
+		}
+
+		stats.Volume = math.Add(stats.Volume, t.Amount)
+		stats.QuoteVolume = math.Add(stats.QuoteVolume, quoteAmount)
+		stats.MakeFees = math.Add(stats.MakeFees, fee(t.MakeFee))
+		stats.TakeFees = math.Add(stats.TakeFees, fee(t.TakeFee))
+		stats.MakerRebates = math.Add(stats.MakerRebates, rebate)
+		stats.TradeCount++
+		traders[t.Maker] = true
+		traders[t.Taker] = true
+
+		a.addUserTrade(p, t.Maker, t.Amount, quoteAmount, fee(t.MakeFee), rebate)
+		a.addUserTrade(p, t.Taker, t.Amount, quoteAmount, fee(t.TakeFee), big.NewInt(0))
+		a.addFees(p.QuoteTokenAddress, fee(t.MakeFee), fee(t.TakeFee), rebate)
+	}
+
+	stats.Traders = len(traders)
+	a.Pairs = append(a.Pairs, stats)
+}
+
+func (a *DailyAggregates) addUserTrade(p *Pair, user common.Address, amount, quoteAmount, fees, rebate *big.Int) {
+	key := user.Hex() + "/" + p.Code()
+	v := a.users[key]
+	if v == nil {
+		v = &UserDailyVolume{
+			Date:        a.Date,
+			Address:     user,
+			PairName:    p.Name(),
+			BaseToken:   p.BaseTokenAddress,
+			QuoteToken:  p.QuoteTokenAddress,
+			Volume:      big.NewInt(0),
+			QuoteVolume: big.NewInt(0),
+			Fees:        big.NewInt(0),
+			Rebates:     big.NewInt(0),
+		}
+
+		a.users[key] = v
+		a.Users = append(a.Users, v)
+	}
+
+	v.Volume = math.Add(v.Volume, amount)
+	v.QuoteVolume = math.Add(v.QuoteVolume, quoteAmount)
+	v.Fees = math.Add(v.Fees, fees)
+	v.Rebates = math.Add(v.Rebates, rebate)
+	v.TradeCount++
+}
+
+func (a *DailyAggregates) addFees(token common.Address, makeFee, takeFee, rebate *big.Int) {
+	f := a.fees[token]
+	if f == nil {
+		f = &FeeTotal{
+			Date:         a.Date,
+			Token:        token,
+			MakeFees:     big.NewInt(0),
+			TakeFees:     big.NewInt(0),
+			MakerRebates: big.NewInt(0),
+		}
+
+		a.fees[token] = f
+		a.Fees = append(a.Fees, f)
+	}
+
+	f.MakeFees = math.Add(f.MakeFees, makeFee)
+	f.TakeFees = math.Add(f.TakeFees, takeFee)
+	f.MakerRebates = math.Add(f.MakerRebates, rebate)
+	f.TradeCount++
+}
+
+// GetBSON returns the record of the user daily volume
+func (v *UserDailyVolume) GetBSON() (interface{}, error) {
+	return UserDailyVolumeRecord{
+		Date:        v.Date,
+		Address:     v.Address.Hex(),
+		PairName:    v.PairName,
+		BaseToken:   v.BaseToken.Hex(),
+		QuoteToken:  v.QuoteToken.Hex(),
+		Volume:      v.Volume.String(),
+		QuoteVolume: v.QuoteVolume.String(),
+		Fees:        v.Fees.String(),
+		Rebates:     v.Rebates.String(),
+		TradeCount:  v.TradeCount,
+		UpdatedAt:   v.UpdatedAt,
+	}, nil
+}
+
+// SetBSON decodes the record of the user daily volume
+func (v *UserDailyVolume) SetBSON(raw bson.Raw) error {
+	decoded := &UserDailyVolumeRecord{}
+	err := raw.Unmarshal(decoded)
+	if err != nil {
+		return err
+	}
+
+	v.Date = decoded.Date
+	v.Address = common.HexToAddress(decoded.Address)
+	v.PairName = decoded.PairName
+	v.BaseToken = common.HexToAddress(decoded.BaseToken)
+	v.QuoteToken = common.HexToAddress(decoded.QuoteToken)
+	v.Volume = math.ToBigInt(decoded.Volume)
+	v.QuoteVolume = math.ToBigInt(decoded.QuoteVolume)
+	v.Fees = math.ToBigInt(decoded.Fees)
+	v.Rebates = math.ToBigInt(decoded.Rebates)
+	v.TradeCount = decoded.TradeCount
+	v.UpdatedAt = decoded.UpdatedAt
+	return nil
+}
+
+// MarshalJSON returns the json encoded byte array representing the user daily volume, the net fees are
+// negative when the rebates exceed the fees paid
+func (v *UserDailyVolume) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"date":        v.Date,
+		"address":     v.Address.Hex(),
+		"pairName":    v.PairName,
+		"baseToken":   v.BaseToken.Hex(),
+		"quoteToken":  v.QuoteToken.Hex(),
+		"volume":      v.Volume.String(),
+		"quoteVolume": v.QuoteVolume.String(),
+		"fees":        v.Fees.String(),
+		"rebates":     v.Rebates.String(),
+		"netFees":     math.Sub(v.Fees, v.Rebates).String(),
+		"tradeCount":  v.TradeCount,
+	})
+}
+
+// GetBSON returns the record of the pair daily stats
+func (s *PairDailyStats) GetBSON() (interface{}, error) {
+	return PairDailyStatsRecord{
+		Date:         s.Date,
+		PairName:     s.PairName,
+		BaseToken:    s.BaseToken.Hex(),
+		QuoteToken:   s.QuoteToken.Hex(),
+		Open:         s.Open.String(),
+		High:         s.High.String(),
+		Low:          s.Low.String(),
+		Close:        s.Close.String(),
+		Volume:       s.Volume.String(),
+		QuoteVolume:  s.QuoteVolume.String(),
+		MakeFees:     s.MakeFees.String(),
+		TakeFees:     s.TakeFees.String(),
+		MakerRebates: s.MakerRebates.String(),
+		TradeCount:   s.TradeCount,
+		Traders:      s.Traders,
+		UpdatedAt:    s.UpdatedAt,
+	}, nil
+}
+
+// SetBSON decodes the record of the pair daily stats
+func (s *PairDailyStats) SetBSON(raw bson.Raw) error {
+	decoded := &PairDailyStatsRecord{}
+	err := raw.Unmarshal(decoded)
+	if err != nil {
+		return err
+	}
+
+	s.Date = decoded.Date
+	s.PairName = decoded.PairName
+	s.BaseToken = common.HexToAddress(decoded.BaseToken)
+	s.QuoteToken = common.HexToAddress(decoded.QuoteToken)
+	s.Open = math.ToBigInt(decoded.Open)
+	s.High = math.ToBigInt(decoded.High)
+	s.Low = math.ToBigInt(decoded.Low)
+	s.Close = math.ToBigInt(decoded.Close)
+	s.Volume = math.ToBigInt(decoded.Volume)
+	s.QuoteVolume = math.ToBigInt(decoded.QuoteVolume)
+	s.MakeFees = math.ToBigInt(decoded.MakeFees)
+	s.TakeFees = math.ToBigInt(decoded.TakeFees)
+	s.MakerRebates = math.ToBigInt(decoded.MakerRebates)
+	s.TradeCount = decoded.TradeCount
+	s.Traders = decoded.Traders
+	s.UpdatedAt = decoded.UpdatedAt
+	return nil
+}
+
+// MarshalJSON returns the json encoded byte array representing the pair daily stats
+func (s *PairDailyStats) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"date":         s.Date,
+		"pairName":     s.PairName,
+		"baseToken":    s.BaseToken.Hex(),
+		"quoteToken":   s.QuoteToken.Hex(),
+		"open":         s.Open.String(),
+		"high":         s.High.String(),
+		"low":          s.Low.String(),
+		"close":        s.Close.String(),
+		"volume":       s.Volume.String(),
+		"quoteVolume":  s.QuoteVolume.String(),
+		"makeFees":     s.MakeFees.String(),
+		"takeFees":     s.TakeFees.String(),
+		"makerRebates": s.MakerRebates.String(),
+		"tradeCount":   s.TradeCount,
+		"traders":      s.Traders,
+	})
+}
+
+// GetBSON returns the record of the fee total
+func (f *FeeTotal) GetBSON() (interface{}, error) {
+	return FeeTotalRecord{
+		Date:         f.Date,
+		Token:        f.Token.Hex(),
+		MakeFees:     f.MakeFees.String(),
+		TakeFees:     f.TakeFees.String(),
+		MakerRebates: f.MakerRebates.String(),
+		TradeCount:   f.TradeCount,
+		UpdatedAt:    f.UpdatedAt,
+	}, nil
+}
+
+// SetBSON decodes the record of the fee total
+func (f *FeeTotal) SetBSON(raw bson.Raw) error {
+	decoded := &FeeTotalRecord{}
+	err := raw.Unmarshal(decoded)
+	if err != nil {
+		return err
+	}
+
+	f.Date = decoded.Date
+	f.Token = common.HexToAddress(decoded.Token)
+	f.MakeFees = math.ToBigInt(decoded.MakeFees)
+	f.TakeFees = math.ToBigInt(decoded.TakeFees)
+	f.MakerRebates = math.ToBigInt(decoded.MakerRebates)
+	f.TradeCount = decoded.TradeCount
+	f.UpdatedAt = decoded.UpdatedAt
+	return nil
+}
+
+// MarshalJSON returns the json encoded byte array representing the fee total, the earnings of the relayer
+// are the total of the fees minus the maker rebates
+func (f *FeeTotal) MarshalJSON() ([]byte, error) {
+	total := math.Add(f.MakeFees, f.TakeFees)
+	return json.Marshal(map[string]interface{}{
+		"date":         f.Date,
+		"token":        f.Token.Hex(),
+		"makeFees":     f.MakeFees.String(),
+		"takeFees":     f.TakeFees.String(),
+		"total":        total.String(),
+		"makerRebates": f.MakerRebates.String(),
+		"earnings":     math.Sub(total, f.MakerRebates).String(),
+		"tradeCount":   f.TradeCount,
+	})
+}
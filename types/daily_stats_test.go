@@ -0,0 +1,72 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDailyAggregatesAddPairTrades(t *testing.T) {
+	maker := common.HexToAddress("0x1")
+	taker := common.HexToAddress("0x2")
+	p := &Pair{
+		BaseTokenSymbol:    "TOMO",
+		BaseTokenAddress:   common.HexToAddress("0x3"),
+		BaseTokenDecimals:  18,
+		QuoteTokenSymbol:   "USDT",
+		QuoteTokenAddress:  common.HexToAddress("0x4"),
+		QuoteTokenDecimals: 18,
+	}
+
+	now := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	trade := func(price int64, offset time.Duration, status string) *Trade {
+		return &Trade{
+			Maker:      maker,
+			Taker:      taker,
+			Amount:     big.NewInt(1e18),
+			PricePoint: big.NewInt(price),
+			MakeFee:    big.NewInt(1),
+			TakeFee:    big.NewInt(2),
+			Status:     status,
+			CreatedAt:  now.Add(offset),
+		}
+	}
+
+	a := NewDailyAggregates(now)
+	a.AddPairTrades(p, []*Trade{
+		trade(3e18, time.Hour, TradeStatusSuccess),
+		trade(2e18, 0, TradeStatusSuccess),
+		trade(1e18, 2*time.Hour, TradeStatusSuccess),
+		trade(9e18, 3*time.Hour, TradeStatusError),
+	}, nil)
+
+	assert.Equal(t, "2019-06-01", a.Date)
+	assert.Equal(t, 1, len(a.Pairs))
+
+	s := a.Pairs[0]
+	assert.Equal(t, "TOMO/USDT", s.PairName)
+	assert.Equal(t, "2000000000000000000", s.Open.String())
+	assert.Equal(t, "3000000000000000000", s.High.String())
+	assert.Equal(t, "1000000000000000000", s.Low.String())
+	assert.Equal(t, "1000000000000000000", s.Close.String())
+	assert.Equal(t, "3000000000000000000", s.Volume.String())
+	assert.Equal(t, "6000000000000000000", s.QuoteVolume.String())
+	assert.Equal(t, 3, s.TradeCount)
+	assert.Equal(t, 2, s.Traders)
+
+	assert.Equal(t, 2, len(a.Users))
+	for _, v := range a.Users {
+		assert.Equal(t, 3, v.TradeCount)
+		assert.Equal(t, "6000000000000000000", v.QuoteVolume.String())
+	}
+
+	assert.Equal(t, 1, len(a.Fees))
+	assert.Equal(t, "3", a.Fees[0].MakeFees.String())
+	assert.Equal(t, "6", a.Fees[0].TakeFees.String())
+
+	a.AddPairTrades(p, []*Trade{trade(1e18, 0, TradeStatusError)}, nil)
+	assert.Equal(t, 1, len(a.Pairs))
+}
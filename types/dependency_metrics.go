@@ -0,0 +1,67 @@
+package types
+
+import "time"
+
+// LatencyStats are the number of operations of a kind with their mean and max durations in milliseconds
+type LatencyStats struct {
+	Count   int64   `json:"count"`
+	MeanMs  float64 `json:"meanMs"`
+	MaxMs   float64 `json:"maxMs"`
+	totalMs float64
+}
+
+// Add records the duration of an operation
+func (s *LatencyStats) Add(d time.Duration) {
+	ms := float64(d) / float64(time.Millisecond)
+	s.Count++
+	s.totalMs += ms
+	s.MeanMs = s.totalMs / float64(s.Count)
+	if ms > s.MaxMs {
+		s.MaxMs = ms
+	}
+}
+
+// MongoMetrics is the health of the MongoDB client: the answer to a ping, the sockets of the connection pool
+// and the operations sent since the start, and the queries slower than the threshold by collection
+type MongoMetrics struct {
+	Reachable          bool             `json:"reachable"`
+	PingMs             float64          `json:"pingMs"`
+	Error              string           `json:"error,omitempty"`
+	Clusters           int              `json:"clusters"`
+	MasterConns        int              `json:"masterConns"`
+	SlaveConns         int              `json:"slaveConns"`
+	SocketsAlive       int              `json:"socketsAlive"`
+	SocketsInUse       int              `json:"socketsInUse"`
+	SocketRefs         int              `json:"socketRefs"`
+	SentOps            int              `json:"sentOps"`
+	ReceivedOps        int              `json:"receivedOps"`
+	ReceivedDocs       int              `json:"receivedDocs"`
+	Queries            LatencyStats     `json:"queries"`
+	SlowQueries        int64            `json:"slowQueries"`
+	SlowQueryThreshold int64            `json:"slowQueryThresholdMs"`
+	SlowCollections    map[string]int64 `json:"slowCollections"`
+}
+
+// QueueMetrics is the health of a RabbitMQ queue: the messages waiting for its consumers, the latency of
+// its publishes and the lag of its consumers, the time from the publish of a message to its delivery
+type QueueMetrics struct {
+	Name          string       `json:"name"`
+	Messages      int          `json:"messages"`
+	Consumers     int          `json:"consumers"`
+	Published     LatencyStats `json:"published"`
+	PublishErrors int64        `json:"publishErrors"`
+	ConsumerLag   LatencyStats `json:"consumerLag"`
+	Error         string       `json:"error,omitempty"`
+}
+
+// RabbitMQMetrics is the health of the RabbitMQ client, the state of its connection and of its queues
+type RabbitMQMetrics struct {
+	Connected bool            `json:"connected"`
+	Queues    []*QueueMetrics `json:"queues"`
+}
+
+// DependencyMetrics is the health of the clients of the dependencies of the SDK
+type DependencyMetrics struct {
+	Mongo    *MongoMetrics    `json:"mongo"`
+	RabbitMQ *RabbitMQMetrics `json:"rabbitmq"`
+}
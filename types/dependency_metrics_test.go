@@ -0,0 +1,19 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatencyStats(t *testing.T) {
+	s := LatencyStats{}
+	s.Add(10 * time.Millisecond)
+	s.Add(30 * time.Millisecond)
+	s.Add(20 * time.Millisecond)
+
+	assert.Equal(t, int64(3), s.Count)
+	assert.Equal(t, float64(20), s.MeanMs)
+	assert.Equal(t, float64(30), s.MaxMs)
+}
@@ -0,0 +1,118 @@
+package types
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/utils/math"
+)
+
+// DepthLevel is a price level of the order book, the amount is the remaining base amount of its orders
+type DepthLevel struct {
+	PricePoint string `json:"pricepoint" bson:"pricepoint"`
+	Amount     string `json:"amount" bson:"amount"`
+}
+
+// DepthSnapshot is the top levels of the order book of a pair at a time, the bids from the best price
+// down and the asks from the best price up. BidDepth and AskDepth are the base amounts of the levels kept
+type DepthSnapshot struct {
+	ID         bson.ObjectId  `json:"-"`
+	BaseToken  common.Address `json:"baseToken"`
+	QuoteToken common.Address `json:"quoteToken"`
+	PairName   string         `json:"pairName"`
+	Bids       []*DepthLevel  `json:"bids"`
+	Asks       []*DepthLevel  `json:"asks"`
+	BidDepth   *big.Int       `json:"bidDepth"`
+	AskDepth   *big.Int       `json:"askDepth"`
+	CreatedAt  time.Time      `json:"createdAt"`
+}
+
+// DepthSnapshotRecord is the depth snapshot stored in the database
+type DepthSnapshotRecord struct {
+	ID         bson.ObjectId `bson:"_id"`
+	BaseToken  string        `bson:"baseToken"`
+	QuoteToken string        `bson:"quoteToken"`
+	PairName   string        `bson:"pairName"`
+	Bids       []*DepthLevel `bson:"bids"`
+	Asks       []*DepthLevel `bson:"asks"`
+	BidDepth   string        `bson:"bidDepth"`
+	AskDepth   string        `bson:"askDepth"`
+	CreatedAt  time.Time     `bson:"createdAt"`
+}
+
+// DepthSnapshotPage is a page of the depth snapshots of a pair between two times, oldest first.
+// Total is the number of snapshots of the period
+type DepthSnapshotPage struct {
+	BaseToken  common.Address   `json:"baseToken"`
+	QuoteToken common.Address   `json:"quoteToken"`
+	From       int64            `json:"from"`
+	To         int64            `json:"to"`
+	Total      int              `json:"total"`
+	Snapshots  []*DepthSnapshot `json:"snapshots"`
+}
+
+// NewDepthSnapshot keeps the top levels of the sides of the order book of a pair, sorted from the best price
+func NewDepthSnapshot(p *Pair, bids, asks []map[string]string, levels int, now time.Time) *DepthSnapshot {
+	s := &DepthSnapshot{
+		BaseToken:  p.BaseTokenAddress,
+		QuoteToken: p.QuoteTokenAddress,
+		PairName:   p.Name(),
+		CreatedAt:  now,
+	}
+
+	s.Bids, s.BidDepth = depthLevels(bids, levels)
+	s.Asks, s.AskDepth = depthLevels(asks, levels)
+	return s
+}
+
+func depthLevels(side []map[string]string, levels int) ([]*DepthLevel, *big.Int) {
+	res := []*DepthLevel{}
+	depth := big.NewInt(0)
+	for i, l := range side {
+		if i >= levels {
+			break
+		}
+
+		res = append(res, &DepthLevel{PricePoint: l["pricepoint"], Amount: l["amount"]})
+		depth = math.Add(depth, math.ToBigInt(l["amount"]))
+	}
+
+	return res, depth
+}
+
+// GetBSON implements bson.Getter
+func (s *DepthSnapshot) GetBSON() (interface{}, error) {
+	return DepthSnapshotRecord{
+		ID:         s.ID,
+		BaseToken:  s.BaseToken.Hex(),
+		QuoteToken: s.QuoteToken.Hex(),
+		PairName:   s.PairName,
+		Bids:       s.Bids,
+		Asks:       s.Asks,
+		BidDepth:   s.BidDepth.String(),
+		AskDepth:   s.AskDepth.String(),
+		CreatedAt:  s.CreatedAt,
+	}, nil
+}
+
+// SetBSON implements bson.Setter
+func (s *DepthSnapshot) SetBSON(raw bson.Raw) error {
+	decoded := &DepthSnapshotRecord{}
+	err := raw.Unmarshal(decoded)
+	if err != nil {
+		return err
+	}
+
+	s.ID = decoded.ID
+	s.BaseToken = common.HexToAddress(decoded.BaseToken)
+	s.QuoteToken = common.HexToAddress(decoded.QuoteToken)
+	s.PairName = decoded.PairName
+	s.Bids = decoded.Bids
+	s.Asks = decoded.Asks
+	s.BidDepth = math.ToBigInt(decoded.BidDepth)
+	s.AskDepth = math.ToBigInt(decoded.AskDepth)
+	s.CreatedAt = decoded.CreatedAt
+	return nil
+}
@@ -0,0 +1,43 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/globalsign/mgo/bson"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDepthSnapshot(t *testing.T) {
+	p := &Pair{
+		BaseTokenSymbol:   "TOMO",
+		BaseTokenAddress:  common.HexToAddress("0x1"),
+		QuoteTokenSymbol:  "USDT",
+		QuoteTokenAddress: common.HexToAddress("0x2"),
+	}
+
+	bids := []map[string]string{{"pricepoint": "10", "amount": "30"}, {"pricepoint": "9", "amount": "50"}, {"pricepoint": "8", "amount": "100"}}
+	asks := []map[string]string{{"pricepoint": "11", "amount": "20"}}
+	now := time.Unix(1600000000, 0)
+
+	s := NewDepthSnapshot(p, bids, asks, 2, now)
+	assert.Equal(t, "TOMO/USDT", s.PairName)
+	assert.Equal(t, []*DepthLevel{{"10", "30"}, {"9", "50"}}, s.Bids)
+	assert.Equal(t, []*DepthLevel{{"11", "20"}}, s.Asks)
+	assert.Equal(t, big.NewInt(80), s.BidDepth)
+	assert.Equal(t, big.NewInt(20), s.AskDepth)
+	assert.Equal(t, now, s.CreatedAt)
+
+	s.ID = bson.NewObjectId()
+	raw, err := bson.Marshal(s)
+	assert.Nil(t, err)
+
+	decoded := &DepthSnapshot{}
+	assert.Nil(t, bson.Unmarshal(raw, decoded))
+	assert.Equal(t, s.BaseToken, decoded.BaseToken)
+	assert.Equal(t, s.Bids, decoded.Bids)
+	assert.Equal(t, s.BidDepth, decoded.BidDepth)
+	assert.Equal(t, s.CreatedAt.Unix(), decoded.CreatedAt.Unix())
+}
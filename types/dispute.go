@@ -0,0 +1,234 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tomochain/tomox-sdk/utils/math"
+)
+
+// Kinds of hash a dispute report is generated for
+const (
+	DisputeKindOrder = "order"
+	DisputeKindTrade = "trade"
+)
+
+// orderStatusSigned is the status of an order when it is signed and sent to the exchange
+const orderStatusSigned = "NEW"
+
+// DisputeReport is the evidence bundle of an order or a trade used to settle the customer support disputes
+type DisputeReport struct {
+	Hash        common.Hash           `json:"hash"`
+	Kind        string                `json:"kind"`
+	GeneratedAt time.Time             `json:"generatedAt"`
+	Orders      []*Order              `json:"orders"`
+	Validations []*OrderValidation    `json:"validations"`
+	Trades      []*Trade              `json:"trades"`
+	Settlements []*SettlementEvidence `json:"settlements"`
+	Balances    []*BalanceEvidence    `json:"balances"`
+}
+
+// OrderValidation is the result of the verification of a signed order payload
+type OrderValidation struct {
+	OrderHash      common.Hash    `json:"orderHash"`
+	ComputedHash   common.Hash    `json:"computedHash"`
+	HashMatches    bool           `json:"hashMatches"`
+	Signer         common.Address `json:"signer"`
+	SignatureValid bool           `json:"signatureValid"`
+	Error          string         `json:"error,omitempty"`
+}
+
+// SettlementEvidence is the on-chain settlement of a trade.
+// Recovery is set if the settlement failed and was followed by the settlement service
+type SettlementEvidence struct {
+	TradeHash     common.Hash `json:"tradeHash"`
+	TxHash        common.Hash `json:"txHash"`
+	TradeStatus   string      `json:"tradeStatus"`
+	ReceiptFound  bool        `json:"receiptFound"`
+	ReceiptStatus uint64      `json:"receiptStatus"`
+	GasUsed       uint64      `json:"gasUsed"`
+	Error         string      `json:"error,omitempty"`
+	Recovery      *Settlement `json:"recovery,omitempty"`
+}
+
+// BalanceEvidence is the net balance change of an account caused by the trades of the report
+// and its balance when the report was generated
+type BalanceEvidence struct {
+	Address common.Address
+	Token   common.Address
+	Change  *big.Int
+	Current *big.Int
+	Error   string
+}
+
+// ValidateOrder recomputes the hash of the order as it was signed and recovers its signer
+func ValidateOrder(o *Order) *OrderValidation {
+	v := &OrderValidation{OrderHash: o.Hash}
+
+	signed := *o
+	signed.Status = orderStatusSigned
+	v.ComputedHash = signed.ComputeHash()
+	v.HashMatches = v.ComputedHash == o.Hash
+
+	if o.Signature == nil {
+		v.Error = "Signature missing"
+		return v
+	}
+
+	message := crypto.Keccak256([]byte("\x19Ethereum Signed Message:\n32"), o.Hash.Bytes())
+	signer, err := o.Signature.Verify(common.BytesToHash(message))
+	if err != nil {
+		v.Error = err.Error()
+		return v
+	}
+
+	v.Signer = signer
+	v.SignatureValid = signer == o.UserAddress
+	if !v.SignatureValid {
+		v.Error = "Recovered address is incorrect"
+	}
+
+	return v
+}
+
+// AddTradeBalanceChanges adds the balance changes of the maker and the taker of a trade.
+// The price point is in quote token units per base token and the fees are paid in quote token
+func AddTradeBalanceChanges(balances []*BalanceEvidence, t *Trade, p *Pair) []*BalanceEvidence {
+	baseAmount := t.Amount
+	quoteAmount := p.QuoteAmount(t.Amount, t.PricePoint)
+
+	buyer, seller := t.Taker, t.Maker
+	buyerFee, sellerFee := t.TakeFee, t.MakeFee
+	if t.TakerOrderSide == SELL {
+		buyer, seller = t.Maker, t.Taker
+		buyerFee, sellerFee = t.MakeFee, t.TakeFee
+	}
+
+	balances = addBalanceChange(balances, buyer, t.BaseToken, baseAmount)
+	balances = addBalanceChange(balances, buyer, t.QuoteToken, math.Neg(math.Add(quoteAmount, fee(buyerFee))))
+	balances = addBalanceChange(balances, seller, t.BaseToken, math.Neg(baseAmount))
+	balances = addBalanceChange(balances, seller, t.QuoteToken, math.Sub(quoteAmount, fee(sellerFee)))
+
+	return balances
+}
+
+func addBalanceChange(balances []*BalanceEvidence, a, token common.Address, change *big.Int) []*BalanceEvidence {
+	for _, b := range balances {
+		if b.Address == a && b.Token == token {
+			b.Change = math.Add(b.Change, change)
+			return balances
+		}
+	}
+
+	return append(balances, &BalanceEvidence{Address: a, Token: token, Change: change})
+}
+
+func fee(f *big.Int) *big.Int {
+	if f == nil {
+		return big.NewInt(0)
+	}
+
+	return f
+}
+
+// MarshalJSON returns the json encoded byte array representing the balance evidence
+func (b *BalanceEvidence) MarshalJSON() ([]byte, error) {
+	balance := map[string]interface{}{
+		"address": b.Address.Hex(),
+		"token":   b.Token.Hex(),
+	}
+
+	if b.Change != nil {
+		balance["change"] = b.Change.String()
+	}
+
+	if b.Current != nil {
+		balance["current"] = b.Current.String()
+	}
+
+	if b.Error != "" {
+		balance["error"] = b.Error
+	}
+
+	return json.Marshal(balance)
+}
+
+// Lines returns the report as lines of text, used to render the printable report
+func (r *DisputeReport) Lines() []string {
+	lines := []string{
+		fmt.Sprintf("Hash: %s (%s)", r.Hash.Hex(), r.Kind),
+		fmt.Sprintf("Generated at: %s", r.GeneratedAt.UTC().Format(time.RFC3339)),
+		"",
+		"SIGNED ORDERS",
+	}
+
+	for _, o := range r.Orders {
+		lines = append(lines,
+			fmt.Sprintf("- Order %s", o.Hash.Hex()),
+			fmt.Sprintf("  User: %s  Exchange: %s", o.UserAddress.Hex(), o.ExchangeAddress.Hex()),
+			fmt.Sprintf("  Pair: %s  Side: %s  Type: %s  Status: %s", o.PairName, o.Side, o.Type, o.Status),
+			fmt.Sprintf("  Amount: %s  Price: %s  Filled: %s  Nonce: %s", o.Amount, o.PricePoint, o.FilledAmount, o.Nonce),
+			fmt.Sprintf("  Created: %s", o.CreatedAt.UTC().Format(time.RFC3339)),
+		)
+
+		if o.Signature != nil {
+			lines = append(lines, fmt.Sprintf("  Signature: V=%d R=%s S=%s", o.Signature.V, o.Signature.R.Hex(), o.Signature.S.Hex()))
+		}
+	}
+
+	lines = append(lines, "", "VALIDATION")
+	for _, v := range r.Validations {
+		lines = append(lines,
+			fmt.Sprintf("- Order %s", v.OrderHash.Hex()),
+			fmt.Sprintf("  Computed hash: %s  Matches: %t", v.ComputedHash.Hex(), v.HashMatches),
+			fmt.Sprintf("  Signer: %s  Signature valid: %t", v.Signer.Hex(), v.SignatureValid),
+		)
+
+		if v.Error != "" {
+			lines = append(lines, fmt.Sprintf("  Error: %s", v.Error))
+		}
+	}
+
+	lines = append(lines, "", "MATCHING EVENTS")
+	for _, t := range r.Trades {
+		lines = append(lines,
+			fmt.Sprintf("- Trade %s", t.Hash.Hex()),
+			fmt.Sprintf("  Maker: %s  Order: %s", t.Maker.Hex(), t.MakerOrderHash.Hex()),
+			fmt.Sprintf("  Taker: %s  Order: %s  Side: %s", t.Taker.Hex(), t.TakerOrderHash.Hex(), t.TakerOrderSide),
+			fmt.Sprintf("  Amount: %s  Price: %s  Make fee: %s  Take fee: %s", t.Amount, t.PricePoint, t.MakeFee, t.TakeFee),
+			fmt.Sprintf("  Status: %s  Created: %s", t.Status, t.CreatedAt.UTC().Format(time.RFC3339)),
+		)
+	}
+
+	lines = append(lines, "", "SETTLEMENT")
+	for _, s := range r.Settlements {
+		lines = append(lines,
+			fmt.Sprintf("- Trade %s  Tx: %s", s.TradeHash.Hex(), s.TxHash.Hex()),
+			fmt.Sprintf("  Trade status: %s  Receipt found: %t  Receipt status: %d  Gas used: %d", s.TradeStatus, s.ReceiptFound, s.ReceiptStatus, s.GasUsed),
+		)
+
+		if s.Error != "" {
+			lines = append(lines, fmt.Sprintf("  Error: %s", s.Error))
+		}
+
+		if s.Recovery != nil {
+			lines = append(lines, fmt.Sprintf("  Recovery: %s after %d attempts  Last error: %s", s.Recovery.Status, s.Recovery.Attempts, s.Recovery.LastError))
+		}
+	}
+
+	lines = append(lines, "", "BALANCES")
+	for _, b := range r.Balances {
+		line := fmt.Sprintf("- %s  Token: %s  Change: %s  Current: %s", b.Address.Hex(), b.Token.Hex(), b.Change, b.Current)
+		if b.Error != "" {
+			line += "  Error: " + b.Error
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines
+}
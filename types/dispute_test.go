@@ -0,0 +1,75 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateOrder(t *testing.T) {
+	w := NewWallet()
+	o := &Order{
+		UserAddress: w.Address,
+		BaseToken:   common.HexToAddress("0x1"),
+		QuoteToken:  common.HexToAddress("0x2"),
+		Amount:      big.NewInt(1000),
+		PricePoint:  big.NewInt(10),
+		Side:        BUY,
+		Type:        TypeLimitOrder,
+		Status:      orderStatusSigned,
+		Nonce:       big.NewInt(1),
+	}
+
+	err := o.Sign(w)
+	assert.Nil(t, err)
+
+	o.Status = OrderStatusFilled
+	v := ValidateOrder(o)
+	assert.True(t, v.HashMatches)
+	assert.True(t, v.SignatureValid)
+	assert.Equal(t, w.Address, v.Signer)
+
+	o.Amount = big.NewInt(2000)
+	v = ValidateOrder(o)
+	assert.False(t, v.HashMatches)
+
+	o.UserAddress = common.HexToAddress("0x3")
+	v = ValidateOrder(o)
+	assert.False(t, v.SignatureValid)
+	assert.NotEmpty(t, v.Error)
+}
+
+func TestAddTradeBalanceChanges(t *testing.T) {
+	maker := common.HexToAddress("0x1")
+	taker := common.HexToAddress("0x2")
+	bt := common.HexToAddress("0x3")
+	qt := common.HexToAddress("0x4")
+	p := &Pair{BaseTokenDecimals: 18, QuoteTokenDecimals: 18}
+
+	tr := &Trade{
+		Maker:          maker,
+		Taker:          taker,
+		BaseToken:      bt,
+		QuoteToken:     qt,
+		TakerOrderSide: BUY,
+		Amount:         big.NewInt(1e18),
+		PricePoint:     big.NewInt(2e18),
+		MakeFee:        big.NewInt(1),
+		TakeFee:        big.NewInt(2),
+	}
+
+	balances := AddTradeBalanceChanges(nil, tr, p)
+	balances = AddTradeBalanceChanges(balances, tr, p)
+	assert.Equal(t, 4, len(balances))
+
+	expected := map[common.Address]map[common.Address]*big.Int{
+		taker: {bt: big.NewInt(2e18), qt: big.NewInt(-4e18 - 4)},
+		maker: {bt: big.NewInt(-2e18), qt: big.NewInt(4e18 - 2)},
+	}
+
+	for _, b := range balances {
+		assert.Equal(t, expected[b.Address][b.Token].String(), b.Change.String())
+	}
+}
@@ -0,0 +1,157 @@
+package types
+
+import (
+	"encoding/json"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/utils/math"
+)
+
+// Types of the events of the engine journal
+const (
+	EngineEventAccepted  = "ACCEPTED"
+	EngineEventMatched   = "MATCHED"
+	EngineEventCancelled = "CANCELLED"
+	EngineEventRejected  = "REJECTED"
+	EngineEventError     = "ERROR"
+)
+
+// EngineEvent is an entry of the engine journal, the sequence numbers are increasing
+// in the order the engine responses are received
+type EngineEvent struct {
+	ID           bson.ObjectId
+	Sequence     int64
+	Type         string
+	OrderHash    common.Hash
+	PairName     string
+	BaseToken    common.Address
+	QuoteToken   common.Address
+	UserAddress  common.Address
+	Status       string
+	FilledAmount *big.Int
+	CreatedAt    time.Time
+}
+
+// EngineEventRecord is the engine event stored in the database
+type EngineEventRecord struct {
+	ID           bson.ObjectId `bson:"_id"`
+	Sequence     int64         `bson:"sequence"`
+	Type         string        `bson:"type"`
+	OrderHash    string        `bson:"orderHash"`
+	PairName     string        `bson:"pairName"`
+	BaseToken    string        `bson:"baseToken"`
+	QuoteToken   string        `bson:"quoteToken"`
+	UserAddress  string        `bson:"userAddress"`
+	Status       string        `bson:"status"`
+	FilledAmount string        `bson:"filledAmount"`
+	CreatedAt    time.Time     `bson:"createdAt"`
+}
+
+// NewEngineEvent returns the journal event of an engine response, nil if the response
+// has no order or an unknown status
+func NewEngineEvent(res *EngineResponse) *EngineEvent {
+	if res == nil || res.Order == nil {
+		return nil
+	}
+
+	var t string
+	switch res.Status {
+	case ORDER_ADDED:
+		t = EngineEventAccepted
+	case ORDER_PARTIALLY_FILLED, ORDER_FILLED:
+		t = EngineEventMatched
+	case ORDER_CANCELLED:
+		t = EngineEventCancelled
+	case ORDER_REJECTED:
+		t = EngineEventRejected
+	case ERROR_STATUS:
+		t = EngineEventError
+	default:
+		return nil
+	}
+
+	o := res.Order
+	filled := big.NewInt(0)
+	if o.FilledAmount != nil {
+		filled = o.FilledAmount
+	}
+
+	return &EngineEvent{
+		Type:         t,
+		OrderHash:    o.Hash,
+		PairName:     o.PairName,
+		BaseToken:    o.BaseToken,
+		QuoteToken:   o.QuoteToken,
+		UserAddress:  o.UserAddress,
+		Status:       o.Status,
+		FilledAmount: filled,
+	}
+}
+
+// MarshalJSON returns the json encoded byte array representing the engine event
+func (e *EngineEvent) MarshalJSON() ([]byte, error) {
+	filled := "0"
+	if e.FilledAmount != nil {
+		filled = e.FilledAmount.String()
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"sequence":     e.Sequence,
+		"type":         e.Type,
+		"orderHash":    e.OrderHash.Hex(),
+		"pairName":     e.PairName,
+		"baseToken":    e.BaseToken.Hex(),
+		"quoteToken":   e.QuoteToken.Hex(),
+		"userAddress":  e.UserAddress.Hex(),
+		"status":       e.Status,
+		"filledAmount": filled,
+		"createdAt":    e.CreatedAt.Format(time.RFC3339Nano),
+	})
+}
+
+// GetBSON implements bson.Getter
+func (e *EngineEvent) GetBSON() (interface{}, error) {
+	filled := "0"
+	if e.FilledAmount != nil {
+		filled = e.FilledAmount.String()
+	}
+
+	return EngineEventRecord{
+		ID:           e.ID,
+		Sequence:     e.Sequence,
+		Type:         e.Type,
+		OrderHash:    e.OrderHash.Hex(),
+		PairName:     e.PairName,
+		BaseToken:    e.BaseToken.Hex(),
+		QuoteToken:   e.QuoteToken.Hex(),
+		UserAddress:  e.UserAddress.Hex(),
+		Status:       e.Status,
+		FilledAmount: filled,
+		CreatedAt:    e.CreatedAt,
+	}, nil
+}
+
+// SetBSON implements bson.Setter
+func (e *EngineEvent) SetBSON(raw bson.Raw) error {
+	decoded := &EngineEventRecord{}
+	err := raw.Unmarshal(decoded)
+	if err != nil {
+		return err
+	}
+
+	e.ID = decoded.ID
+	e.Sequence = decoded.Sequence
+	e.Type = decoded.Type
+	e.OrderHash = common.HexToHash(decoded.OrderHash)
+	e.PairName = decoded.PairName
+	e.BaseToken = common.HexToAddress(decoded.BaseToken)
+	e.QuoteToken = common.HexToAddress(decoded.QuoteToken)
+	e.UserAddress = common.HexToAddress(decoded.UserAddress)
+	e.Status = decoded.Status
+	e.FilledAmount = math.ToBigInt(decoded.FilledAmount)
+	e.CreatedAt = decoded.CreatedAt
+	return nil
+}
@@ -0,0 +1,42 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewEngineEvent(t *testing.T) {
+	o := &Order{
+		Hash:         common.HexToHash("0x1"),
+		PairName:     "TOMO/USDT",
+		BaseToken:    common.HexToAddress("0x2"),
+		QuoteToken:   common.HexToAddress("0x3"),
+		UserAddress:  common.HexToAddress("0x4"),
+		Status:       "PARTIAL_FILLED",
+		FilledAmount: big.NewInt(100),
+	}
+
+	cases := map[string]string{
+		ORDER_ADDED:            EngineEventAccepted,
+		ORDER_PARTIALLY_FILLED: EngineEventMatched,
+		ORDER_FILLED:           EngineEventMatched,
+		ORDER_CANCELLED:        EngineEventCancelled,
+		ORDER_REJECTED:         EngineEventRejected,
+		ERROR_STATUS:           EngineEventError,
+	}
+
+	for status, expected := range cases {
+		e := NewEngineEvent(&EngineResponse{Status: status, Order: o})
+		assert.NotNil(t, e)
+		assert.Equal(t, expected, e.Type)
+		assert.Equal(t, o.Hash, e.OrderHash)
+		assert.Equal(t, o.BaseToken, e.BaseToken)
+		assert.Equal(t, big.NewInt(100), e.FilledAmount)
+	}
+
+	assert.Nil(t, NewEngineEvent(&EngineResponse{Status: "UNKNOWN", Order: o}))
+	assert.Nil(t, NewEngineEvent(&EngineResponse{Status: ORDER_ADDED}))
+}
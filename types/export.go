@@ -0,0 +1,72 @@
+package types
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/errors"
+)
+
+// Streams of the export API
+const (
+	ExportTrades = "trades"
+	ExportOrders = "orders"
+)
+
+// ExportCheckpoint is the position of a consumer in an export stream, the last document delivered
+// in the order of the last update of the documents. The zero checkpoint is the start of the stream
+type ExportCheckpoint struct {
+	UpdatedAt time.Time
+	ID        bson.ObjectId
+}
+
+// ExportPage is a batch of documents of an export stream, the trades or the orders changed after the
+// checkpoint of the request. Checkpoint is the position after the batch, to be sent with the next
+// request once the batch is processed, and HasMore tells that more documents are ready
+type ExportPage struct {
+	Stream     string      `json:"stream"`
+	Items      interface{} `json:"items"`
+	Count      int         `json:"count"`
+	Checkpoint string      `json:"checkpoint"`
+	HasMore    bool        `json:"hasMore"`
+}
+
+// Encode returns the opaque token of the checkpoint sent to the consumers
+func (c *ExportCheckpoint) Encode() string {
+	if c.ID == "" {
+		return ""
+	}
+
+	ms := c.UpdatedAt.UnixNano() / int64(time.Millisecond)
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(ms, 10) + "." + c.ID.Hex()))
+}
+
+// ParseExportCheckpoint decodes the token of a checkpoint, the empty token is the start of the stream
+func ParseExportCheckpoint(token string) (*ExportCheckpoint, error) {
+	if token == "" {
+		return &ExportCheckpoint{}, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errors.New("Invalid checkpoint")
+	}
+
+	parts := strings.Split(string(raw), ".")
+	if len(parts) != 2 || !bson.IsObjectIdHex(parts[1]) {
+		return nil, errors.New("Invalid checkpoint")
+	}
+
+	ms, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || ms < 0 {
+		return nil, errors.New("Invalid checkpoint")
+	}
+
+	return &ExportCheckpoint{
+		UpdatedAt: time.Unix(0, ms*int64(time.Millisecond)),
+		ID:        bson.ObjectIdHex(parts[1]),
+	}, nil
+}
@@ -0,0 +1,31 @@
+package types
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportCheckpoint(t *testing.T) {
+	cp := &ExportCheckpoint{UpdatedAt: time.Unix(1546300800, 123000000), ID: bson.NewObjectId()}
+
+	parsed, err := ParseExportCheckpoint(cp.Encode())
+	assert.Nil(t, err)
+	assert.True(t, cp.UpdatedAt.Equal(parsed.UpdatedAt))
+	assert.Equal(t, cp.ID, parsed.ID)
+
+	// the start of the stream
+	parsed, err = ParseExportCheckpoint("")
+	assert.Nil(t, err)
+	assert.Equal(t, bson.ObjectId(""), parsed.ID)
+	assert.Equal(t, "", parsed.Encode())
+
+	_, err = ParseExportCheckpoint("not a checkpoint")
+	assert.NotNil(t, err)
+
+	_, err = ParseExportCheckpoint(base64.RawURLEncoding.EncodeToString([]byte("1546300800123.xyz")))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,64 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// FaucetTransfer is a transfer of the faucet, TOMO for the native token address
+type FaucetTransfer struct {
+	Token  common.Address `json:"token"`
+	Amount *big.Int       `json:"amount"`
+	TxHash common.Hash    `json:"txHash,omitempty"`
+	Error  string         `json:"error,omitempty"`
+}
+
+// FaucetRequest is the result of a request to the faucet: the transfers sent to the address and the time
+// from which it can request again
+type FaucetRequest struct {
+	Address       common.Address    `json:"address"`
+	Transfers     []*FaucetTransfer `json:"transfers"`
+	NextRequestAt time.Time         `json:"nextRequestAt"`
+}
+
+// ParseFaucetTokens parses a comma separated list of token:amount, the amounts in the units of the token.
+// The order of the list is kept
+func ParseFaucetTokens(list string) ([]*FaucetTransfer, error) {
+	res := []*FaucetTransfer{}
+	seen := make(map[common.Address]bool)
+	for _, v := range strings.Split(list, ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+
+		parts := strings.Split(v, ":")
+		if len(parts) != 2 || !common.IsHexAddress(parts[0]) {
+			return nil, fmt.Errorf("%s: must be token:amount", v)
+		}
+
+		amount, ok := new(big.Int).SetString(parts[1], 10)
+		if !ok || amount.Sign() <= 0 {
+			return nil, fmt.Errorf("%s: amount must be a positive integer", v)
+		}
+
+		token := common.HexToAddress(parts[0])
+		if seen[token] {
+			return nil, fmt.Errorf("%s: duplicate token", v)
+		}
+
+		seen[token] = true
+		res = append(res, &FaucetTransfer{Token: token, Amount: amount})
+	}
+
+	if len(res) == 0 {
+		return nil, errors.New("no faucet token")
+	}
+
+	return res, nil
+}
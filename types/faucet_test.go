@@ -0,0 +1,35 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFaucetTokens(t *testing.T) {
+	tomo := "0x0000000000000000000000000000000000000001"
+	token := "0x1D3D6eC9e36c2fC5ad6d2fA0bc9d1D7cd5D2DE03"
+
+	res, err := ParseFaucetTokens(tomo + ":1000, " + token + ":5,")
+	assert.NoError(t, err)
+	assert.Len(t, res, 2)
+	assert.Equal(t, common.HexToAddress(tomo), res[0].Token)
+	assert.Equal(t, big.NewInt(1000), res[0].Amount)
+	assert.Equal(t, common.HexToAddress(token), res[1].Token)
+	assert.Equal(t, big.NewInt(5), res[1].Amount)
+
+	for _, list := range []string{
+		"",
+		tomo,
+		"0x01:10",
+		tomo + ":0",
+		tomo + ":-1",
+		tomo + ":1.5",
+		tomo + ":1," + tomo + ":2",
+	} {
+		_, err := ParseFaucetTokens(list)
+		assert.Error(t, err, list)
+	}
+}
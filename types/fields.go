@@ -0,0 +1,145 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+// OrderFields are the fields of an order which can be selected with the fields parameter,
+// keyed by their name in the responses with their stored name
+var OrderFields = map[string]string{
+	"exchangeAddress": "exchangeAddress",
+	"userAddress":     "userAddress",
+	"baseToken":       "baseToken",
+	"quoteToken":      "quoteToken",
+	"side":            "side",
+	"type":            "type",
+	"status":          "status",
+	"pairName":        "pairName",
+	"amount":          "quantity",
+	"pricepoint":      "price",
+	"filledAmount":    "filledAmount",
+	"hash":            "hash",
+	"nonce":           "nonce",
+	"publicId":        "publicId",
+	"orderID":         "orderID",
+	"createdAt":       "createdAt",
+	"updatedAt":       "updatedAt",
+}
+
+// TradeFields are the fields of a trade which can be selected with the fields parameter
+var TradeFields = map[string]string{
+	"taker":          "taker",
+	"maker":          "maker",
+	"baseToken":      "baseToken",
+	"quoteToken":     "quoteToken",
+	"status":         "status",
+	"hash":           "hash",
+	"txHash":         "txHash",
+	"pairName":       "pairName",
+	"pricepoint":     "pricepoint",
+	"amount":         "amount",
+	"makeFee":        "makeFee",
+	"takeFee":        "takeFee",
+	"makerOrderHash": "makerOrderHash",
+	"takerOrderHash": "takerOrderHash",
+	"takerOrderSide": "takerOrderSide",
+	"takerOrderType": "takerOrderType",
+	"makerOrderType": "makerOrderType",
+	"makerExchange":  "makerExchange",
+	"takerExchange":  "takerExchange",
+	"publicId":       "publicId",
+	"createdAt":      "createdAt",
+}
+
+// PairFields are the fields of a pair which can be selected with the fields parameter
+var PairFields = map[string]string{
+	"baseTokenSymbol":    "baseTokenSymbol",
+	"baseTokenDecimals":  "baseTokenDecimals",
+	"baseTokenAddress":   "baseTokenAddress",
+	"quoteTokenSymbol":   "quoteTokenSymbol",
+	"quoteTokenDecimals": "quoteTokenDecimals",
+	"quoteTokenAddress":  "quoteTokenAddress",
+	"relayerAddress":     "relayerAddress",
+	"rank":               "rank",
+	"active":             "active",
+	"listed":             "listed",
+	"unverifiedTokens":   "unverifiedTokens",
+	"makeFee":            "makeFee",
+	"takeFee":            "takeFee",
+}
+
+// ParseFields returns the fields of a comma separated fields parameter, no field selects all of them
+func ParseFields(s string, allowed map[string]string) ([]string, error) {
+	fields := []string{}
+	for _, f := range strings.Split(s, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+
+		if _, ok := allowed[f]; !ok {
+			return nil, fmt.Errorf("Invalid field %s", f)
+		}
+
+		fields = append(fields, f)
+	}
+
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	return fields, nil
+}
+
+// FieldsProjection returns the projection reading only the stored fields of the fields,
+// nil reads the whole documents
+func FieldsProjection(fields []string, allowed map[string]string) bson.M {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	p := bson.M{}
+	for _, f := range fields {
+		p[allowed[f]] = 1
+	}
+
+	return p
+}
+
+// SelectFields returns the items of a slice with only the fields, as they are encoded in the responses
+func SelectFields(items interface{}, fields []string) ([]map[string]json.RawMessage, error) {
+	b, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []map[string]json.RawMessage{}
+	err = json.Unmarshal(b, &res)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range res {
+		for k := range item {
+			if !containsField(fields, k) {
+				delete(item, k)
+			}
+		}
+	}
+
+	return res, nil
+}
+
+func containsField(fields []string, f string) bool {
+	for _, field := range fields {
+		if field == f {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,38 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/globalsign/mgo/bson"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFields(t *testing.T) {
+	fields, err := ParseFields("pricepoint, amount,,status", OrderFields)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"pricepoint", "amount", "status"}, fields)
+	assert.Equal(t, bson.M{"price": 1, "quantity": 1, "status": 1}, FieldsProjection(fields, OrderFields))
+
+	fields, err = ParseFields("", OrderFields)
+	assert.Nil(t, err)
+	assert.Nil(t, fields)
+	assert.Nil(t, FieldsProjection(fields, OrderFields))
+
+	_, err = ParseFields("amount,signature", OrderFields)
+	assert.EqualError(t, err, "Invalid field signature")
+}
+
+func TestSelectFields(t *testing.T) {
+	trades := []*Trade{
+		{PairName: "TOMO/USDT", PricePoint: big.NewInt(100), Amount: big.NewInt(5), Maker: common.HexToAddress("0x1")},
+	}
+
+	res, err := SelectFields(trades, []string{"pricepoint", "amount"})
+	assert.Nil(t, err)
+	assert.Len(t, res, 1)
+	assert.Len(t, res[0], 2)
+	assert.Equal(t, `"100"`, string(res[0]["pricepoint"]))
+	assert.Equal(t, `"5"`, string(res[0]["amount"]))
+}
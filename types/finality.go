@@ -0,0 +1,158 @@
+package types
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/globalsign/mgo/bson"
+)
+
+// Actions that are tracked until they reach finality
+const (
+	FinalityActionTrade        = "trade"
+	FinalityActionLendingTrade = "lending_trade"
+	FinalityActionDeposit      = "deposit"
+)
+
+// Finality statuses of a tracked record
+const (
+	FinalityStatusPending   = "PENDING"
+	FinalityStatusFinalized = "FINALIZED"
+)
+
+// DefaultConfirmations is the number of confirmations required for actions
+// without an explicit confirmation policy
+const DefaultConfirmations = uint64(1)
+
+// FinalityPolicy maps an action to the number of blocks required before it is final
+type FinalityPolicy map[string]uint64
+
+// RequiredConfirmations returns the number of confirmations required by an action
+func (p FinalityPolicy) RequiredConfirmations(action string) uint64 {
+	if n, ok := p[action]; ok {
+		return n
+	}
+
+	return DefaultConfirmations
+}
+
+// FinalityRecord tracks the number of confirmations of a settled record. BlockNumber is the block of the
+// receipt of the settlement transaction TxHash, zero until the transaction is mined
+type FinalityRecord struct {
+	ID            bson.ObjectId `json:"-" bson:"_id"`
+	Action        string        `json:"action" bson:"action"`
+	Hash          common.Hash   `json:"hash" bson:"hash"`
+	TxHash        common.Hash   `json:"txHash" bson:"txHash"`
+	BlockNumber   uint64        `json:"blockNumber" bson:"blockNumber"`
+	Confirmations uint64        `json:"confirmations" bson:"confirmations"`
+	Required      uint64        `json:"required" bson:"required"`
+	Status        string        `json:"status" bson:"status"`
+	CreatedAt     time.Time     `json:"createdAt" bson:"createdAt"`
+	UpdatedAt     time.Time     `json:"updatedAt" bson:"updatedAt"`
+}
+
+// FinalityRecordBSON is the representation of a FinalityRecord in the database
+type FinalityRecordBSON struct {
+	ID            bson.ObjectId `bson:"_id"`
+	Action        string        `bson:"action"`
+	Hash          string        `bson:"hash"`
+	TxHash        string        `bson:"txHash"`
+	BlockNumber   int64         `bson:"blockNumber"`
+	Confirmations int64         `bson:"confirmations"`
+	Required      int64         `bson:"required"`
+	Status        string        `bson:"status"`
+	CreatedAt     time.Time     `bson:"createdAt"`
+	UpdatedAt     time.Time     `bson:"updatedAt"`
+}
+
+// NewFinalityRecord returns a pending record settled by the given transaction, its confirmations are counted
+// once the receipt of the transaction is found
+func NewFinalityRecord(action string, hash common.Hash, txHash common.Hash, required uint64) *FinalityRecord {
+	return &FinalityRecord{
+		Action:   action,
+		Hash:     hash,
+		TxHash:   txHash,
+		Required: required,
+		Status:   FinalityStatusPending,
+	}
+}
+
+// IsIncluded returns true if the block of the receipt of the settlement is known
+func (r *FinalityRecord) IsIncluded() bool {
+	return r.BlockNumber > 0
+}
+
+// Include sets the block of the receipt of the settlement, the confirmations are counted from there.
+// It returns true if the record is final at the current head
+func (r *FinalityRecord) Include(blockNumber uint64, head uint64) bool {
+	r.BlockNumber = blockNumber
+	r.Confirmations = 0
+	r.Status = FinalityStatusPending
+	return r.Confirm(head)
+}
+
+// Confirm updates the confirmations of the record with the current chain head.
+// It returns true if the record became final
+func (r *FinalityRecord) Confirm(head uint64) bool {
+	if !r.IsIncluded() || head < r.BlockNumber {
+		return false
+	}
+
+	r.Confirmations = head - r.BlockNumber + 1
+	if r.Status == FinalityStatusPending && r.Confirmations >= r.Required {
+		r.Status = FinalityStatusFinalized
+		return true
+	}
+
+	return false
+}
+
+// Rebase moves the record to the block its settlement was included in after a reorganization, its
+// confirmations are counted again from there. It returns true if the record is final at the current head
+func (r *FinalityRecord) Rebase(blockNumber uint64, head uint64) bool {
+	return r.Include(blockNumber, head)
+}
+
+// IsFinalized returns true if the record reached the required number of confirmations
+func (r *FinalityRecord) IsFinalized() bool {
+	return r.Status == FinalityStatusFinalized
+}
+
+// GetBSON implements bson.Getter
+func (r *FinalityRecord) GetBSON() (interface{}, error) {
+	return FinalityRecordBSON{
+		ID:            r.ID,
+		Action:        r.Action,
+		Hash:          r.Hash.Hex(),
+		TxHash:        r.TxHash.Hex(),
+		BlockNumber:   int64(r.BlockNumber),
+		Confirmations: int64(r.Confirmations),
+		Required:      int64(r.Required),
+		Status:        r.Status,
+		CreatedAt:     r.CreatedAt,
+		UpdatedAt:     r.UpdatedAt,
+	}, nil
+}
+
+// SetBSON implements bson.Setter
+func (r *FinalityRecord) SetBSON(raw bson.Raw) error {
+	decoded := &FinalityRecordBSON{}
+
+	err := raw.Unmarshal(decoded)
+	if err != nil {
+		return err
+	}
+
+	r.ID = decoded.ID
+	r.Action = decoded.Action
+	r.Hash = common.HexToHash(decoded.Hash)
+	r.TxHash = common.HexToHash(decoded.TxHash)
+	r.BlockNumber = uint64(decoded.BlockNumber)
+	r.Confirmations = uint64(decoded.Confirmations)
+	r.Required = uint64(decoded.Required)
+	r.Status = decoded.Status
+	r.CreatedAt = decoded.CreatedAt
+	r.UpdatedAt = decoded.UpdatedAt
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/globalsign/mgo/bson"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFinalityPolicy(t *testing.T) {
+	p := FinalityPolicy{FinalityActionTrade: 12}
+
+	assert.Equal(t, uint64(12), p.RequiredConfirmations(FinalityActionTrade))
+	assert.Equal(t, DefaultConfirmations, p.RequiredConfirmations(FinalityActionDeposit))
+}
+
+func TestFinalityRecordConfirm(t *testing.T) {
+	r := NewFinalityRecord(FinalityActionTrade, common.HexToHash("0x1"), common.HexToHash("0x11"), 3)
+
+	// the confirmations are counted once the block of the receipt is known
+	assert.False(t, r.IsIncluded())
+	assert.False(t, r.Confirm(102))
+	assert.Equal(t, uint64(0), r.Confirmations)
+
+	assert.False(t, r.Include(100, 99))
+	assert.True(t, r.IsIncluded())
+	assert.False(t, r.Confirm(99))
+	assert.False(t, r.Confirm(101))
+	assert.Equal(t, uint64(2), r.Confirmations)
+	assert.False(t, r.IsFinalized())
+
+	assert.True(t, r.Confirm(102))
+	assert.True(t, r.IsFinalized())
+
+	// a finalized record is only reported once
+	assert.False(t, r.Confirm(103))
+	assert.Equal(t, uint64(4), r.Confirmations)
+}
+
+func TestFinalityRecordRebase(t *testing.T) {
+	r := NewFinalityRecord(FinalityActionTrade, common.HexToHash("0x1"), common.HexToHash("0x11"), 3)
+	assert.True(t, r.Include(100, 102))
+
+	assert.False(t, r.Rebase(101, 102))
+	assert.Equal(t, uint64(101), r.BlockNumber)
+	assert.Equal(t, uint64(2), r.Confirmations)
+	assert.False(t, r.IsFinalized())
+
+	assert.True(t, r.Confirm(103))
+}
+
+func TestFinalityRecordBSON(t *testing.T) {
+	r := NewFinalityRecord(FinalityActionDeposit, common.HexToHash("0x2"), common.HexToHash("0x12"), 5)
+	r.Include(10, 11)
+	r.ID = bson.NewObjectId()
+
+	data, err := bson.Marshal(r)
+	if err != nil {
+		t.Error(err)
+	}
+
+	decoded := &FinalityRecord{}
+	if err := bson.Unmarshal(data, decoded); err != nil {
+		t.Error(err)
+	}
+
+	assert.Equal(t, r, decoded)
+}
@@ -0,0 +1,113 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/tomochain/tomox-sdk/utils/math"
+)
+
+// Speeds of the suggested gas prices
+const (
+	GasSpeedSafe     = "safe"
+	GasSpeedStandard = "standard"
+	GasSpeedFast     = "fast"
+)
+
+// Default percentiles of the gas prices paid in the sampled blocks giving the suggested prices
+const (
+	DefaultGasSafePercentile     = 30
+	DefaultGasStandardPercentile = 60
+	DefaultGasFastPercentile     = 90
+)
+
+// GasSample is the gas prices paid by the transactions of a block, the free system transactions excluded
+type GasSample struct {
+	BlockNumber uint64
+	Prices      []*big.Int
+}
+
+// GasPrices is the suggestion of the gas oracle. The chain has no base fee, BaseFee is the lowest price
+// included by the sampled blocks and the priority fees are the part of the suggested prices above it,
+// as EIP-1559 wallets show them
+type GasPrices struct {
+	BaseFee     *big.Int            `json:"baseFee"`
+	Safe        *big.Int            `json:"safe"`
+	Standard    *big.Int            `json:"standard"`
+	Fast        *big.Int            `json:"fast"`
+	PriorityFee map[string]*big.Int `json:"priorityFee"`
+	BlockNumber uint64              `json:"blockNumber"`
+	Blocks      int                 `json:"blocks"`
+	UpdatedAt   time.Time           `json:"updatedAt"`
+}
+
+// NewGasPrices returns the percentiles of the prices of the samples (safe, standard and fast), none of
+// them below the floor. Without any price, all the speeds get the floor
+func NewGasPrices(samples []*GasSample, percentiles [3]int, floor *big.Int) *GasPrices {
+	if floor == nil {
+		floor = big.NewInt(0)
+	}
+
+	prices := []*big.Int{}
+	var head uint64
+	for _, s := range samples {
+		prices = append(prices, s.Prices...)
+		if s.BlockNumber > head {
+			head = s.BlockNumber
+		}
+	}
+
+	sort.Slice(prices, func(i, j int) bool { return prices[i].Cmp(prices[j]) < 0 })
+
+	g := &GasPrices{
+		BaseFee:     atLeast(floor, percentile(prices, 0)),
+		BlockNumber: head,
+		Blocks:      len(samples),
+		UpdatedAt:   time.Now(),
+	}
+
+	g.Safe = atLeast(g.BaseFee, percentile(prices, percentiles[0]))
+	g.Standard = atLeast(g.Safe, percentile(prices, percentiles[1]))
+	g.Fast = atLeast(g.Standard, percentile(prices, percentiles[2]))
+	g.PriorityFee = map[string]*big.Int{
+		GasSpeedSafe:     new(big.Int).Sub(g.Safe, g.BaseFee),
+		GasSpeedStandard: new(big.Int).Sub(g.Standard, g.BaseFee),
+		GasSpeedFast:     new(big.Int).Sub(g.Fast, g.BaseFee),
+	}
+
+	return g
+}
+
+// Price returns the suggested price of a speed
+func (g *GasPrices) Price(speed string) (*big.Int, error) {
+	switch speed {
+	case GasSpeedSafe:
+		return g.Safe, nil
+	case GasSpeedStandard, "":
+		return g.Standard, nil
+	case GasSpeedFast:
+		return g.Fast, nil
+	default:
+		return nil, fmt.Errorf("Unknown gas speed %s, use safe, standard or fast", speed)
+	}
+}
+
+// percentile returns the p-th percentile of sorted values, nil without values
+func percentile(sorted []*big.Int, p int) *big.Int {
+	if len(sorted) == 0 {
+		return nil
+	}
+
+	return sorted[(len(sorted)-1)*p/100]
+}
+
+// atLeast returns a copy of a value raised to a minimum, the minimum for a nil value
+func atLeast(min *big.Int, v *big.Int) *big.Int {
+	if v == nil {
+		return new(big.Int).Set(min)
+	}
+
+	return new(big.Int).Set(math.Max(min, v))
+}
@@ -0,0 +1,53 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func gasSample(block uint64, prices ...int64) *GasSample {
+	s := &GasSample{BlockNumber: block}
+	for _, p := range prices {
+		s.Prices = append(s.Prices, big.NewInt(p))
+	}
+
+	return s
+}
+
+func TestNewGasPrices(t *testing.T) {
+	samples := []*GasSample{
+		gasSample(10, 5, 1, 9, 3),
+		gasSample(12, 2, 8, 4),
+		gasSample(11, 7, 6, 10),
+	}
+
+	g := NewGasPrices(samples, [3]int{30, 60, 90}, big.NewInt(0))
+	assert.Equal(t, big.NewInt(1), g.BaseFee)
+	assert.Equal(t, big.NewInt(3), g.Safe)
+	assert.Equal(t, big.NewInt(6), g.Standard)
+	assert.Equal(t, big.NewInt(9), g.Fast)
+	assert.Equal(t, big.NewInt(5), g.PriorityFee[GasSpeedStandard])
+	assert.Equal(t, uint64(12), g.BlockNumber)
+	assert.Equal(t, 3, g.Blocks)
+
+	// the floor raises all the speeds
+	g = NewGasPrices(samples, [3]int{30, 60, 90}, big.NewInt(7))
+	assert.Equal(t, big.NewInt(7), g.BaseFee)
+	assert.Equal(t, big.NewInt(7), g.Safe)
+	assert.Equal(t, big.NewInt(7), g.Standard)
+	assert.Equal(t, big.NewInt(9), g.Fast)
+	assert.Equal(t, big.NewInt(2), g.PriorityFee[GasSpeedFast])
+
+	g = NewGasPrices(nil, [3]int{30, 60, 90}, big.NewInt(250000000))
+	assert.Equal(t, big.NewInt(250000000), g.Fast)
+	assert.Equal(t, 0, g.Blocks)
+
+	p, err := g.Price(GasSpeedSafe)
+	assert.NoError(t, err)
+	assert.Equal(t, g.Safe, p)
+
+	_, err = g.Price("slow")
+	assert.Error(t, err)
+}
@@ -0,0 +1,249 @@
+package types
+
+import (
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/errors"
+)
+
+// Statuses of an incident
+const (
+	IncidentStatusInvestigating = "INVESTIGATING"
+	IncidentStatusIdentified    = "IDENTIFIED"
+	IncidentStatusMonitoring    = "MONITORING"
+	IncidentStatusResolved      = "RESOLVED"
+)
+
+// Impacts of an incident on its components
+const (
+	IncidentImpactMinor    = "MINOR"
+	IncidentImpactMajor    = "MAJOR"
+	IncidentImpactCritical = "CRITICAL"
+)
+
+// Components of the exchange an incident affects
+const (
+	ComponentAPI        = "api"
+	ComponentWebsocket  = "websocket"
+	ComponentMatching   = "matching"
+	ComponentSettlement = "settlement"
+	ComponentLending    = "lending"
+	ComponentDeposits   = "deposits"
+)
+
+// Statuses of the system and of its components, from the active incidents
+const (
+	SystemStatusOperational   = "OPERATIONAL"
+	SystemStatusDegraded      = "DEGRADED"
+	SystemStatusPartialOutage = "PARTIAL_OUTAGE"
+	SystemStatusMajorOutage   = "MAJOR_OUTAGE"
+)
+
+// Components are the components reported on the status page
+var Components = []string{
+	ComponentAPI,
+	ComponentWebsocket,
+	ComponentMatching,
+	ComponentSettlement,
+	ComponentLending,
+	ComponentDeposits,
+}
+
+var incidentStatuses = map[string]bool{
+	IncidentStatusInvestigating: true,
+	IncidentStatusIdentified:    true,
+	IncidentStatusMonitoring:    true,
+	IncidentStatusResolved:      true,
+}
+
+// impactStatuses are the statuses of the components affected by an incident of each impact, by severity
+var impactStatuses = map[string]string{
+	IncidentImpactMinor:    SystemStatusDegraded,
+	IncidentImpactMajor:    SystemStatusPartialOutage,
+	IncidentImpactCritical: SystemStatusMajorOutage,
+}
+
+var systemStatusSeverity = map[string]int{
+	SystemStatusOperational:   0,
+	SystemStatusDegraded:      1,
+	SystemStatusPartialOutage: 2,
+	SystemStatusMajorOutage:   3,
+}
+
+// Incident is an outage or a degradation of the exchange communicated by the operators
+type Incident struct {
+	ID         bson.ObjectId     `json:"id" bson:"_id"`
+	Title      string            `json:"title" bson:"title"`
+	Status     string            `json:"status" bson:"status"`
+	Impact     string            `json:"impact" bson:"impact"`
+	Components []string          `json:"components" bson:"components"`
+	Updates    []*IncidentUpdate `json:"updates" bson:"updates"`
+	CreatedBy  string            `json:"createdBy,omitempty" bson:"createdBy"`
+	ResolvedAt *time.Time        `json:"resolvedAt,omitempty" bson:"resolvedAt,omitempty"`
+	CreatedAt  time.Time         `json:"createdAt" bson:"createdAt"`
+	UpdatedAt  time.Time         `json:"updatedAt" bson:"updatedAt"`
+}
+
+// IncidentUpdate is a message posted on an incident, the latest first
+type IncidentUpdate struct {
+	Status    string    `json:"status" bson:"status"`
+	Message   string    `json:"message" bson:"message"`
+	UpdatedBy string    `json:"updatedBy,omitempty" bson:"updatedBy"`
+	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
+}
+
+// IncidentPayload creates or updates an incident, the empty fields of an update are unchanged
+type IncidentPayload struct {
+	Title      string   `json:"title"`
+	Status     string   `json:"status"`
+	Impact     string   `json:"impact"`
+	Components []string `json:"components"`
+	Message    string   `json:"message"`
+}
+
+// SystemStatus is the data of the status page: the status of the system and of each component,
+// the active incidents and the ones resolved recently
+type SystemStatus struct {
+	Status     string            `json:"status"`
+	Components map[string]string `json:"components"`
+	Active     []*Incident       `json:"active"`
+	Resolved   []*Incident       `json:"resolved"`
+	UpdatedAt  time.Time         `json:"updatedAt"`
+}
+
+// SystemStatusEvent is the message of the system_status channel sent when an incident is created or updated
+type SystemStatusEvent struct {
+	Incident *Incident     `json:"incident"`
+	Status   *SystemStatus `json:"status"`
+}
+
+// Validate checks the status, the impact and the components of the payload
+func (p *IncidentPayload) Validate() error {
+	if p.Status != "" && !incidentStatuses[p.Status] {
+		return errors.Errorf("Invalid status %s", p.Status)
+	}
+
+	if p.Impact != "" && impactStatuses[p.Impact] == "" {
+		return errors.Errorf("Invalid impact %s", p.Impact)
+	}
+
+	for _, c := range p.Components {
+		if !isComponent(c) {
+			return errors.Errorf("Invalid component %s", c)
+		}
+	}
+
+	return nil
+}
+
+// ValidateNew checks the payload of a new incident, which needs a title, a message and its components
+func (p *IncidentPayload) ValidateNew() error {
+	if p.Title == "" || p.Message == "" {
+		return errors.New("title and message are required")
+	}
+
+	if len(p.Components) == 0 {
+		return errors.New("components are required")
+	}
+
+	return p.Validate()
+}
+
+func isComponent(c string) bool {
+	for _, component := range Components {
+		if component == c {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewIncident returns a new incident from a payload, investigating with a minor impact by default
+func NewIncident(p *IncidentPayload, identity string, now time.Time) (*Incident, error) {
+	err := p.ValidateNew()
+	if err != nil {
+		return nil, err
+	}
+
+	i := &Incident{
+		Title:      p.Title,
+		Status:     IncidentStatusInvestigating,
+		Impact:     IncidentImpactMinor,
+		Components: p.Components,
+		Updates:    []*IncidentUpdate{},
+		CreatedBy:  identity,
+	}
+
+	i.Apply(p, identity, now)
+	return i, nil
+}
+
+// Apply changes the incident with the non empty fields of the payload and posts its message
+func (i *Incident) Apply(p *IncidentPayload, identity string, now time.Time) {
+	if p.Title != "" {
+		i.Title = p.Title
+	}
+
+	if p.Status != "" {
+		i.Status = p.Status
+	}
+
+	if p.Impact != "" {
+		i.Impact = p.Impact
+	}
+
+	if len(p.Components) > 0 {
+		i.Components = p.Components
+	}
+
+	if i.IsResolved() && i.ResolvedAt == nil {
+		i.ResolvedAt = &now
+	}
+
+	if p.Message != "" {
+		u := &IncidentUpdate{Status: i.Status, Message: p.Message, UpdatedBy: identity, CreatedAt: now}
+		i.Updates = append([]*IncidentUpdate{u}, i.Updates...)
+	}
+}
+
+// IsResolved returns true if the incident is over
+func (i *Incident) IsResolved() bool {
+	return i.Status == IncidentStatusResolved
+}
+
+// NewSystemStatus returns the status of the system from the active incidents,
+// each component takes the status of the highest impact of the incidents affecting it
+func NewSystemStatus(active, resolved []*Incident, now time.Time) *SystemStatus {
+	s := &SystemStatus{
+		Status:     SystemStatusOperational,
+		Components: make(map[string]string),
+		Active:     active,
+		Resolved:   resolved,
+		UpdatedAt:  now,
+	}
+
+	for _, c := range Components {
+		s.Components[c] = SystemStatusOperational
+	}
+
+	for _, i := range active {
+		status := impactStatuses[i.Impact]
+		if status == "" {
+			continue
+		}
+
+		for _, c := range i.Components {
+			if systemStatusSeverity[status] > systemStatusSeverity[s.Components[c]] {
+				s.Components[c] = status
+			}
+		}
+
+		if systemStatusSeverity[status] > systemStatusSeverity[s.Status] {
+			s.Status = status
+		}
+	}
+
+	return s
+}
@@ -0,0 +1,51 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewIncident(t *testing.T) {
+	now := time.Now()
+
+	_, err := NewIncident(&IncidentPayload{Title: "Slow matching", Components: []string{ComponentMatching}}, "ops", now)
+	assert.Error(t, err)
+
+	_, err = NewIncident(&IncidentPayload{Title: "Slow matching", Message: "Looking", Components: []string{"engine"}}, "ops", now)
+	assert.Error(t, err)
+
+	i, err := NewIncident(&IncidentPayload{Title: "Slow matching", Message: "Looking", Components: []string{ComponentMatching}}, "ops", now)
+	assert.NoError(t, err)
+	assert.Equal(t, IncidentStatusInvestigating, i.Status)
+	assert.Equal(t, IncidentImpactMinor, i.Impact)
+	assert.Equal(t, 1, len(i.Updates))
+
+	i.Apply(&IncidentPayload{Status: IncidentStatusResolved, Message: "Fixed"}, "ops", now.Add(time.Hour))
+	assert.True(t, i.IsResolved())
+	assert.Equal(t, now.Add(time.Hour), *i.ResolvedAt)
+	assert.Equal(t, "Fixed", i.Updates[0].Message)
+	assert.Equal(t, IncidentStatusResolved, i.Updates[0].Status)
+}
+
+func TestNewSystemStatus(t *testing.T) {
+	now := time.Now()
+
+	s := NewSystemStatus(nil, nil, now)
+	assert.Equal(t, SystemStatusOperational, s.Status)
+	assert.Equal(t, SystemStatusOperational, s.Components[ComponentSettlement])
+
+	active := []*Incident{
+		{Impact: IncidentImpactMinor, Components: []string{ComponentMatching, ComponentAPI}},
+		{Impact: IncidentImpactMajor, Components: []string{ComponentSettlement}},
+		{Impact: IncidentImpactCritical, Components: []string{ComponentAPI}},
+	}
+
+	s = NewSystemStatus(active, nil, now)
+	assert.Equal(t, SystemStatusMajorOutage, s.Status)
+	assert.Equal(t, SystemStatusDegraded, s.Components[ComponentMatching])
+	assert.Equal(t, SystemStatusPartialOutage, s.Components[ComponentSettlement])
+	assert.Equal(t, SystemStatusMajorOutage, s.Components[ComponentAPI])
+	assert.Equal(t, SystemStatusOperational, s.Components[ComponentLending])
+}
@@ -0,0 +1,231 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/utils"
+)
+
+// Invariants cross-checked by the integrity job
+const (
+	IntegrityCheckFilledOrders   = "filled_orders"
+	IntegrityCheckOHLCVVolume    = "ohlcv_volume"
+	IntegrityCheckOrphanedTrades = "orphaned_trades"
+)
+
+// IntegrityMessage is the message type of the notifications sent to the admins when the integrity
+// job finds violations
+const IntegrityMessage = "INTEGRITY_VIOLATIONS"
+
+// MaxIntegrityViolations is the number of violations recorded per check in a report, the others are only counted
+const MaxIntegrityViolations = 100
+
+// IntegrityViolation is a record breaking an invariant, with the value expected from the other
+// collection and the value found
+type IntegrityViolation struct {
+	Check    string `json:"check" bson:"check"`
+	Key      string `json:"key" bson:"key"`
+	Expected string `json:"expected" bson:"expected"`
+	Actual   string `json:"actual" bson:"actual"`
+}
+
+// IntegrityReport is the result of a run of the integrity job on the records of a period,
+// with the number of records checked and of violations per check
+type IntegrityReport struct {
+	ID         bson.ObjectId         `json:"id" bson:"_id"`
+	From       time.Time             `json:"from" bson:"from"`
+	To         time.Time             `json:"to" bson:"to"`
+	Checked    map[string]int        `json:"checked" bson:"checked"`
+	Violations map[string]int        `json:"violations" bson:"violations"`
+	Diffs      []*IntegrityViolation `json:"diffs" bson:"diffs"`
+	CreatedAt  time.Time             `json:"createdAt" bson:"createdAt"`
+}
+
+// NewIntegrityReport returns an empty report of the period
+func NewIntegrityReport(from, to time.Time) *IntegrityReport {
+	return &IntegrityReport{
+		From:       from,
+		To:         to,
+		Checked:    make(map[string]int),
+		Violations: make(map[string]int),
+		Diffs:      []*IntegrityViolation{},
+	}
+}
+
+// Add records the result of a check, up to MaxIntegrityViolations of its violations are kept
+func (r *IntegrityReport) Add(check string, checked int, violations []*IntegrityViolation) {
+	r.Checked[check] += checked
+	for _, v := range violations {
+		if r.Violations[check] < MaxIntegrityViolations {
+			r.Diffs = append(r.Diffs, v)
+		}
+
+		r.Violations[check]++
+	}
+}
+
+// HasViolations returns true if any check found a violation
+func (r *IntegrityReport) HasViolations() bool {
+	for _, n := range r.Violations {
+		if n > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Summary returns the number of violations per check, for the notifications
+func (r *IntegrityReport) Summary() string {
+	checks := []string{}
+	for c, n := range r.Violations {
+		if n > 0 {
+			checks = append(checks, fmt.Sprintf("%s: %d", c, n))
+		}
+	}
+
+	sort.Strings(checks)
+	return fmt.Sprintf(
+		"Integrity check of %s to %s found violations (%s), report %s",
+		r.From.UTC().Format(time.RFC3339),
+		r.To.UTC().Format(time.RFC3339),
+		strings.Join(checks, ", "),
+		r.ID.Hex(),
+	)
+}
+
+// CheckFilledOrders returns the filled orders whose amount differs from the volume of their trades,
+// the failed trades are not counted
+func CheckFilledOrders(orders []*Order, trades []*Trade) []*IntegrityViolation {
+	volumes := make(map[common.Hash]*big.Int)
+	for _, t := range trades {
+		if t.Status == TradeStatusError || t.Amount == nil {
+			continue
+		}
+
+		for _, h := range []common.Hash{t.MakerOrderHash, t.TakerOrderHash} {
+			if volumes[h] == nil {
+				volumes[h] = big.NewInt(0)
+			}
+
+			volumes[h].Add(volumes[h], t.Amount)
+		}
+	}
+
+	res := []*IntegrityViolation{}
+	for _, o := range orders {
+		traded := volumes[o.Hash]
+		if traded == nil {
+			traded = big.NewInt(0)
+		}
+
+		if o.Amount == nil || o.Amount.Cmp(traded) != 0 {
+			res = append(res, &IntegrityViolation{
+				Check:    IntegrityCheckFilledOrders,
+				Key:      o.Hash.Hex(),
+				Expected: fmt.Sprint(o.Amount),
+				Actual:   traded.String(),
+			})
+		}
+	}
+
+	return res
+}
+
+// CheckOHLCVVolumes returns the buckets of the ticks of a pair whose volume differs from the sum of
+// the amounts of its successful trades. The timestamps of the ticks are in milliseconds
+func CheckOHLCVVolumes(pairName string, ticks []*Tick, trades []*Trade, duration int64, unit string) []*IntegrityViolation {
+	expected := make(map[int64]*big.Int)
+	for _, t := range trades {
+		if t.Status != SUCCESS || t.Amount == nil {
+			continue
+		}
+
+		bucket, _ := utils.GetModTime(t.CreatedAt.Unix(), duration, unit)
+		if expected[bucket] == nil {
+			expected[bucket] = big.NewInt(0)
+		}
+
+		expected[bucket].Add(expected[bucket], t.Amount)
+	}
+
+	actual := make(map[int64]*big.Int)
+	for _, t := range ticks {
+		if t.Volume != nil && t.Volume.Sign() > 0 {
+			actual[t.Timestamp/1000] = t.Volume
+		}
+	}
+
+	buckets := []int64{}
+	for b := range expected {
+		buckets = append(buckets, b)
+	}
+
+	for b := range actual {
+		if expected[b] == nil {
+			buckets = append(buckets, b)
+		}
+	}
+
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i] < buckets[j] })
+
+	res := []*IntegrityViolation{}
+	for _, b := range buckets {
+		e, a := expected[b], actual[b]
+		if e == nil {
+			e = big.NewInt(0)
+		}
+
+		if a == nil {
+			a = big.NewInt(0)
+		}
+
+		if e.Cmp(a) != 0 {
+			res = append(res, &IntegrityViolation{
+				Check:    IntegrityCheckOHLCVVolume,
+				Key:      fmt.Sprintf("%s@%s", pairName, time.Unix(b, 0).UTC().Format(time.RFC3339)),
+				Expected: e.String(),
+				Actual:   a.String(),
+			})
+		}
+	}
+
+	return res
+}
+
+// CheckOrphanedTrades returns the trades whose maker or taker order is not in the orders
+func CheckOrphanedTrades(trades []*Trade, orders []*Order) []*IntegrityViolation {
+	known := make(map[common.Hash]bool)
+	for _, o := range orders {
+		known[o.Hash] = true
+	}
+
+	res := []*IntegrityViolation{}
+	for _, t := range trades {
+		missing := []string{}
+		if !known[t.MakerOrderHash] {
+			missing = append(missing, "maker order "+t.MakerOrderHash.Hex())
+		}
+
+		if !known[t.TakerOrderHash] {
+			missing = append(missing, "taker order "+t.TakerOrderHash.Hex())
+		}
+
+		if len(missing) > 0 {
+			res = append(res, &IntegrityViolation{
+				Check:    IntegrityCheckOrphanedTrades,
+				Key:      t.Hash.Hex(),
+				Expected: "maker and taker orders",
+				Actual:   "missing " + strings.Join(missing, ", "),
+			})
+		}
+	}
+
+	return res
+}
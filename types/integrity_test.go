@@ -0,0 +1,86 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckFilledOrders(t *testing.T) {
+	maker := &Order{Hash: common.HexToHash("0x1"), Amount: big.NewInt(10)}
+	taker := &Order{Hash: common.HexToHash("0x2"), Amount: big.NewInt(10)}
+	trades := []*Trade{
+		{MakerOrderHash: maker.Hash, TakerOrderHash: taker.Hash, Amount: big.NewInt(6), Status: SUCCESS},
+		{MakerOrderHash: maker.Hash, TakerOrderHash: taker.Hash, Amount: big.NewInt(4), Status: TradeStatusPending},
+	}
+
+	assert.Empty(t, CheckFilledOrders([]*Order{maker, taker}, trades))
+
+	trades[1].Status = TradeStatusError
+	res := CheckFilledOrders([]*Order{maker, taker}, trades)
+	assert.Len(t, res, 2)
+	assert.Equal(t, maker.Hash.Hex(), res[0].Key)
+	assert.Equal(t, "10", res[0].Expected)
+	assert.Equal(t, "6", res[0].Actual)
+}
+
+func TestCheckOHLCVVolumes(t *testing.T) {
+	hour := time.Date(2019, 10, 1, 10, 0, 0, 0, time.UTC)
+	trades := []*Trade{
+		{Amount: big.NewInt(3), Status: SUCCESS, CreatedAt: hour.Add(5 * time.Minute)},
+		{Amount: big.NewInt(4), Status: SUCCESS, CreatedAt: hour.Add(50 * time.Minute)},
+		{Amount: big.NewInt(9), Status: TradeStatusError, CreatedAt: hour.Add(50 * time.Minute)},
+		{Amount: big.NewInt(2), Status: SUCCESS, CreatedAt: hour.Add(70 * time.Minute)},
+	}
+
+	ticks := []*Tick{
+		{Volume: big.NewInt(7), Timestamp: hour.Unix() * 1000},
+		{Volume: big.NewInt(2), Timestamp: hour.Add(time.Hour).Unix() * 1000},
+	}
+
+	assert.Empty(t, CheckOHLCVVolumes("BTC/TOMO", ticks, trades, 1, "hour"))
+
+	ticks[1].Volume = big.NewInt(1)
+	ticks = append(ticks, &Tick{Volume: big.NewInt(5), Timestamp: hour.Add(2*time.Hour).Unix() * 1000})
+
+	res := CheckOHLCVVolumes("BTC/TOMO", ticks, trades, 1, "hour")
+	assert.Len(t, res, 2)
+	assert.Equal(t, "BTC/TOMO@2019-10-01T11:00:00Z", res[0].Key)
+	assert.Equal(t, "2", res[0].Expected)
+	assert.Equal(t, "1", res[0].Actual)
+	assert.Equal(t, "0", res[1].Expected)
+	assert.Equal(t, "5", res[1].Actual)
+}
+
+func TestCheckOrphanedTrades(t *testing.T) {
+	orders := []*Order{{Hash: common.HexToHash("0x1")}, {Hash: common.HexToHash("0x2")}}
+	trades := []*Trade{
+		{Hash: common.HexToHash("0xa"), MakerOrderHash: common.HexToHash("0x1"), TakerOrderHash: common.HexToHash("0x2")},
+		{Hash: common.HexToHash("0xb"), MakerOrderHash: common.HexToHash("0x1"), TakerOrderHash: common.HexToHash("0x3")},
+	}
+
+	res := CheckOrphanedTrades(trades, orders)
+	assert.Len(t, res, 1)
+	assert.Equal(t, common.HexToHash("0xb").Hex(), res[0].Key)
+	assert.Contains(t, res[0].Actual, "taker order")
+}
+
+func TestIntegrityReport(t *testing.T) {
+	r := NewIntegrityReport(time.Unix(0, 0), time.Unix(86400, 0))
+	r.Add(IntegrityCheckFilledOrders, 3, nil)
+	assert.False(t, r.HasViolations())
+
+	violations := []*IntegrityViolation{}
+	for i := 0; i < MaxIntegrityViolations+5; i++ {
+		violations = append(violations, &IntegrityViolation{Check: IntegrityCheckOrphanedTrades})
+	}
+
+	r.Add(IntegrityCheckOrphanedTrades, 200, violations)
+	assert.True(t, r.HasViolations())
+	assert.Len(t, r.Diffs, MaxIntegrityViolations)
+	assert.Equal(t, MaxIntegrityViolations+5, r.Violations[IntegrityCheckOrphanedTrades])
+	assert.Contains(t, r.Summary(), "orphaned_trades: 105")
+}
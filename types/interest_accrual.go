@@ -0,0 +1,148 @@
+package types
+
+import (
+	"encoding/json"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/utils/math"
+)
+
+// AccrualDateLayout is the layout of the dates of the daily interest accruals
+const AccrualDateLayout = "2006-01-02"
+
+// InterestAccrual is the interest accrued by a lending trade during a UTC day and since its start
+type InterestAccrual struct {
+	TradeHash    common.Hash
+	Investor     common.Address
+	Borrower     common.Address
+	LendingToken common.Address
+	Date         string
+	Daily        *big.Int
+	Total        *big.Int
+	CreatedAt    time.Time
+}
+
+// InterestAccrualRecord is the interest accrual stored in the database
+type InterestAccrualRecord struct {
+	ID           bson.ObjectId `bson:"_id,omitempty"`
+	TradeHash    string        `bson:"tradeHash"`
+	Investor     string        `bson:"investor"`
+	Borrower     string        `bson:"borrower"`
+	LendingToken string        `bson:"lendingToken"`
+	Date         string        `bson:"date"`
+	Daily        string        `bson:"daily"`
+	Total        string        `bson:"total"`
+	CreatedAt    time.Time     `bson:"createdAt"`
+}
+
+// InterestIncome is the interest accrued by the lends of an investor over a period, with the totals per lending token
+type InterestIncome struct {
+	Address  common.Address     `json:"address"`
+	From     string             `json:"from"`
+	To       string             `json:"to"`
+	Totals   map[string]string  `json:"totals"`
+	Accruals []*InterestAccrual `json:"accruals"`
+}
+
+// NewInterestAccrual returns the interest accrued by a lending trade during the UTC day of a time
+func NewInterestAccrual(t *LendingTrade, day time.Time) *InterestAccrual {
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	return newInterestAccrual(t, start, start.AddDate(0, 0, 1))
+}
+
+// CurrentInterestAccrual returns the interest accrued by a lending trade from the start of the UTC day to a time
+func CurrentInterestAccrual(t *LendingTrade, now time.Time) *InterestAccrual {
+	now = now.UTC()
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return newInterestAccrual(t, start, now)
+}
+
+func newInterestAccrual(t *LendingTrade, start, end time.Time) *InterestAccrual {
+	total := AccruedInterest(t, end)
+
+	return &InterestAccrual{
+		TradeHash:    t.Hash,
+		Investor:     t.Investor,
+		Borrower:     t.Borrower,
+		LendingToken: t.LendingToken,
+		Date:         start.Format(AccrualDateLayout),
+		Daily:        math.Sub(total, AccruedInterest(t, start)),
+		Total:        total,
+	}
+}
+
+// NewInterestIncome sums the daily accruals of an investor per lending token
+func NewInterestIncome(addr common.Address, from, to string, accruals []*InterestAccrual) *InterestIncome {
+	totals := make(map[string]*big.Int)
+	for _, a := range accruals {
+		token := a.LendingToken.Hex()
+		if totals[token] == nil {
+			totals[token] = big.NewInt(0)
+		}
+
+		totals[token] = math.Add(totals[token], a.Daily)
+	}
+
+	res := &InterestIncome{
+		Address:  addr,
+		From:     from,
+		To:       to,
+		Totals:   make(map[string]string),
+		Accruals: accruals,
+	}
+
+	for token, total := range totals {
+		res.Totals[token] = total.String()
+	}
+
+	return res
+}
+
+// GetBSON returns the record of the interest accrual
+func (a *InterestAccrual) GetBSON() (interface{}, error) {
+	return InterestAccrualRecord{
+		TradeHash:    a.TradeHash.Hex(),
+		Investor:     a.Investor.Hex(),
+		Borrower:     a.Borrower.Hex(),
+		LendingToken: a.LendingToken.Hex(),
+		Date:         a.Date,
+		Daily:        a.Daily.String(),
+		Total:        a.Total.String(),
+		CreatedAt:    a.CreatedAt,
+	}, nil
+}
+
+// SetBSON decodes the record of the interest accrual
+func (a *InterestAccrual) SetBSON(raw bson.Raw) error {
+	decoded := &InterestAccrualRecord{}
+	err := raw.Unmarshal(decoded)
+	if err != nil {
+		return err
+	}
+
+	a.TradeHash = common.HexToHash(decoded.TradeHash)
+	a.Investor = common.HexToAddress(decoded.Investor)
+	a.Borrower = common.HexToAddress(decoded.Borrower)
+	a.LendingToken = common.HexToAddress(decoded.LendingToken)
+	a.Date = decoded.Date
+	a.Daily = math.ToBigInt(decoded.Daily)
+	a.Total = math.ToBigInt(decoded.Total)
+	a.CreatedAt = decoded.CreatedAt
+	return nil
+}
+
+// MarshalJSON returns the json encoded byte array representing the interest accrual
+func (a *InterestAccrual) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"tradeHash":    a.TradeHash.Hex(),
+		"investor":     a.Investor.Hex(),
+		"borrower":     a.Borrower.Hex(),
+		"lendingToken": a.LendingToken.Hex(),
+		"date":         a.Date,
+		"daily":        a.Daily.String(),
+		"total":        a.Total.String(),
+	})
+}
@@ -0,0 +1,41 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewInterestAccrual(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	tr := &LendingTrade{
+		LendingToken:    common.HexToAddress("0x1"),
+		Amount:          big.NewInt(365e15),
+		Interest:        10 * LendingInterestDecimals,
+		Term:            secondsPerYear,
+		LiquidationTime: uint64(start.Unix()) + secondsPerYear,
+	}
+
+	a := NewInterestAccrual(tr, start.Add(12*time.Hour))
+	assert.Equal(t, "2020-01-01", a.Date)
+	assert.Equal(t, "100000000000000", a.Daily.String())
+	assert.Equal(t, "100000000000000", a.Total.String())
+
+	b := NewInterestAccrual(tr, start.AddDate(0, 0, 1))
+	assert.Equal(t, "100000000000000", b.Daily.String())
+	assert.Equal(t, "200000000000000", b.Total.String())
+
+	c := CurrentInterestAccrual(tr, start.AddDate(0, 0, 1).Add(12*time.Hour))
+	assert.Equal(t, "2020-01-02", c.Date)
+	assert.Equal(t, "50000000000000", c.Daily.String())
+	assert.Equal(t, "150000000000000", c.Total.String())
+
+	a = NewInterestAccrual(tr, start.AddDate(-1, 0, 0))
+	assert.Equal(t, "0", a.Daily.String())
+
+	income := NewInterestIncome(tr.Investor, "2020-01-01", "2020-01-02", []*InterestAccrual{a, b, NewInterestAccrual(tr, start)})
+	assert.Equal(t, map[string]string{tr.LendingToken.Hex(): "200000000000000"}, income.Totals)
+}
@@ -0,0 +1,25 @@
+package types
+
+import (
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+// JobState is the state of a scheduled job, persisted so that the paused jobs stay paused
+// and the run history survives the restarts
+type JobState struct {
+	ID           bson.ObjectId `json:"-" bson:"_id"`
+	Name         string        `json:"name" bson:"name"`
+	Spec         string        `json:"spec" bson:"spec"`
+	Paused       bool          `json:"paused" bson:"paused"`
+	Running      bool          `json:"running" bson:"running"`
+	LastRunAt    time.Time     `json:"lastRunAt" bson:"lastRunAt"`
+	LastDuration int64         `json:"lastDurationMs" bson:"lastDuration"`
+	LastSuccess  bool          `json:"lastSuccess" bson:"lastSuccess"`
+	LastError    string        `json:"lastError,omitempty" bson:"lastError"`
+	NextRunAt    time.Time     `json:"nextRunAt" bson:"nextRunAt"`
+	Runs         int64         `json:"runs" bson:"runs"`
+	Failures     int64         `json:"failures" bson:"failures"`
+	UpdatedAt    time.Time     `json:"updatedAt" bson:"updatedAt"`
+}
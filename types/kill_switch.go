@@ -0,0 +1,171 @@
+package types
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/sha3"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/errors"
+)
+
+// Scopes of a kill switch
+const (
+	KillSwitchScopeGlobal = "GLOBAL"
+	KillSwitchScopePair   = "PAIR"
+)
+
+// Statuses of a kill switch
+const (
+	KillSwitchStatusActive   = "ACTIVE"
+	KillSwitchStatusReleased = "RELEASED"
+)
+
+// Actions of a signed kill switch request
+const (
+	KillSwitchActionActivate = "activate"
+	KillSwitchActionRelease  = "release"
+)
+
+// KillSwitchRequestWindow is how long a signed kill switch request is accepted after its timestamp
+const KillSwitchRequestWindow = 2 * time.Minute
+
+// KillSwitch records an emergency halt of the order intake, of all the pairs or of one pair,
+// with the resting orders cancelled on activation if requested
+type KillSwitch struct {
+	ID              bson.ObjectId `json:"id" bson:"_id"`
+	Scope           string        `json:"scope" bson:"scope"`
+	Pairs           []string      `json:"pairs" bson:"pairs"`
+	PairCodes       []string      `json:"-" bson:"pairCodes"`
+	CancelOrders    bool          `json:"cancelOrders" bson:"cancelOrders"`
+	CancelledOrders int           `json:"cancelledOrders" bson:"cancelledOrders"`
+	Reason          string        `json:"reason" bson:"reason"`
+	Status          string        `json:"status" bson:"status"`
+	ActivatedBy     string        `json:"activatedBy" bson:"activatedBy"`
+	ReleasedBy      string        `json:"releasedBy,omitempty" bson:"releasedBy"`
+	ReleasedAt      *time.Time    `json:"releasedAt,omitempty" bson:"releasedAt,omitempty"`
+	CreatedAt       time.Time     `json:"createdAt" bson:"createdAt"`
+	UpdatedAt       time.Time     `json:"updatedAt" bson:"updatedAt"`
+}
+
+// KillSwitchPayload activates a kill switch, on the pair of the tokens or on all the pairs
+// when both tokens are empty
+type KillSwitchPayload struct {
+	BaseToken    common.Address `json:"baseToken"`
+	QuoteToken   common.Address `json:"quoteToken"`
+	CancelOrders bool           `json:"cancelOrders"`
+	Reason       string         `json:"reason"`
+}
+
+// KillSwitchEvent is the message of the system_status channel sent when a kill switch is activated or released
+type KillSwitchEvent struct {
+	KillSwitch *KillSwitch `json:"killSwitch"`
+	Halted     bool        `json:"halted"`
+}
+
+// SignedKillSwitchRequest activates or releases a kill switch outside of the admin API,
+// signed by the key of one of the configured kill switch signers
+type SignedKillSwitchRequest struct {
+	Action    string             `json:"action"`
+	ID        string             `json:"id,omitempty"`
+	Payload   *KillSwitchPayload `json:"payload,omitempty"`
+	Timestamp int64              `json:"timestamp"`
+	Hash      common.Hash        `json:"hash"`
+	Signature *Signature         `json:"signature"`
+}
+
+// Validate checks the payload has a reason and either both tokens or none
+func (p *KillSwitchPayload) Validate() error {
+	if p.Reason == "" {
+		return errors.New("reason is required")
+	}
+
+	if (p.BaseToken == common.Address{}) != (p.QuoteToken == common.Address{}) {
+		return errors.New("baseToken and quoteToken must be set together")
+	}
+
+	return nil
+}
+
+// IsGlobal returns true if the payload halts all the pairs
+func (p *KillSwitchPayload) IsGlobal() bool {
+	return p.BaseToken == common.Address{} && p.QuoteToken == common.Address{}
+}
+
+// IsActive returns true if the kill switch still halts its pairs
+func (k *KillSwitch) IsActive() bool {
+	return k.Status == KillSwitchStatusActive
+}
+
+// ComputeHash computes the hash of the action, the kill switch id or the payload, and the timestamp
+func (r *SignedKillSwitchRequest) ComputeHash() common.Hash {
+	sha := sha3.NewKeccak256()
+	sha.Write([]byte(r.Action))
+	sha.Write([]byte(r.ID))
+
+	if r.Payload != nil {
+		sha.Write(r.Payload.BaseToken.Bytes())
+		sha.Write(r.Payload.QuoteToken.Bytes())
+		if r.Payload.CancelOrders {
+			sha.Write([]byte{1})
+		} else {
+			sha.Write([]byte{0})
+		}
+		sha.Write([]byte(r.Payload.Reason))
+	}
+
+	sha.Write(common.BigToHash(big.NewInt(r.Timestamp)).Bytes())
+	return common.BytesToHash(sha.Sum(nil))
+}
+
+// Sign computes the request hash then signs and sets the signature
+func (r *SignedKillSwitchRequest) Sign(w *Wallet) error {
+	h := r.ComputeHash()
+	sig, err := w.SignHash(h)
+	if err != nil {
+		return err
+	}
+
+	r.Hash = h
+	r.Signature = sig
+	return nil
+}
+
+// Verify checks the request is recent, well formed and signed, and returns its signer
+func (r *SignedKillSwitchRequest) Verify(now time.Time) (common.Address, error) {
+	switch r.Action {
+	case KillSwitchActionActivate:
+		if r.Payload == nil {
+			return common.Address{}, errors.New("payload is required")
+		}
+
+		err := r.Payload.Validate()
+		if err != nil {
+			return common.Address{}, err
+		}
+	case KillSwitchActionRelease:
+		if !bson.IsObjectIdHex(r.ID) {
+			return common.Address{}, errors.New("Invalid kill switch id")
+		}
+	default:
+		return common.Address{}, errors.Errorf("Invalid action %s", r.Action)
+	}
+
+	t := time.Unix(r.Timestamp, 0)
+	if t.Before(now.Add(-KillSwitchRequestWindow)) || t.After(now.Add(KillSwitchRequestWindow)) {
+		return common.Address{}, errors.New("Request expired")
+	}
+
+	if r.Signature == nil || r.ComputeHash() != r.Hash {
+		return common.Address{}, errors.New("Invalid hash")
+	}
+
+	message := crypto.Keccak256(
+		[]byte("\x19Ethereum Signed Message:\n32"),
+		r.Hash.Bytes(),
+	)
+
+	return r.Signature.Verify(common.BytesToHash(message))
+}
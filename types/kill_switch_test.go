@@ -0,0 +1,65 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKillSwitchPayloadValidate(t *testing.T) {
+	p := &KillSwitchPayload{}
+	assert.Error(t, p.Validate())
+
+	p.Reason = "matching bug"
+	assert.NoError(t, p.Validate())
+	assert.True(t, p.IsGlobal())
+
+	p.BaseToken = common.HexToAddress("0x1")
+	assert.Error(t, p.Validate())
+
+	p.QuoteToken = common.HexToAddress("0x2")
+	assert.NoError(t, p.Validate())
+	assert.False(t, p.IsGlobal())
+}
+
+func TestSignedKillSwitchRequestVerify(t *testing.T) {
+	w := NewWallet()
+	now := time.Now()
+
+	req := &SignedKillSwitchRequest{
+		Action:    KillSwitchActionActivate,
+		Payload:   &KillSwitchPayload{CancelOrders: true, Reason: "matching bug"},
+		Timestamp: now.Unix(),
+	}
+
+	err := req.Sign(w)
+	assert.NoError(t, err)
+
+	b, err := json.Marshal(req)
+	assert.NoError(t, err)
+
+	decoded := &SignedKillSwitchRequest{}
+	err = json.Unmarshal(b, decoded)
+	assert.NoError(t, err)
+
+	signer, err := decoded.Verify(now)
+	assert.NoError(t, err)
+	assert.Equal(t, w.Address, signer)
+
+	_, err = decoded.Verify(now.Add(KillSwitchRequestWindow + time.Minute))
+	assert.Error(t, err)
+
+	decoded.Payload.CancelOrders = false
+	_, err = decoded.Verify(now)
+	assert.Error(t, err)
+
+	release := &SignedKillSwitchRequest{Action: KillSwitchActionRelease, ID: "invalid", Timestamp: now.Unix()}
+	err = release.Sign(w)
+	assert.NoError(t, err)
+
+	_, err = release.Verify(now)
+	assert.Error(t, err)
+}
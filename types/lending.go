@@ -284,6 +284,31 @@ func (o *LendingOrder) MarshalJSON() ([]byte, error) {
 	return json.Marshal(lending)
 }
 
+// lendingOrderJSONKeys are the fields of a lending order payload
+var lendingOrderJSONKeys = map[string]bool{
+	"id":              true,
+	"relayerAddress":  true,
+	"userAddress":     true,
+	"collateralToken": true,
+	"lendingToken":    true,
+	"quantity":        true,
+	"term":            true,
+	"interest":        true,
+	"filledAmount":    true,
+	"nonce":           true,
+	"hash":            true,
+	"side":            true,
+	"type":            true,
+	"status":          true,
+	"signature":       true,
+	"createdAt":       true,
+	"updatedAt":       true,
+	"lendingId":       true,
+	"tradeId":         true,
+	"autoTopUp":       true,
+	"key":             true,
+}
+
 // UnmarshalJSON : write custom logic to unmarshal bytes to LendingOrder
 func (o *LendingOrder) UnmarshalJSON(b []byte) error {
 	lending := map[string]interface{}{}
@@ -291,6 +316,11 @@ func (o *LendingOrder) UnmarshalJSON(b []byte) error {
 	if err != nil {
 		return err
 	}
+
+	if err := checkJSONKeys(lending, lendingOrderJSONKeys); err != nil {
+		return err
+	}
+
 	if lending["id"] != nil && bson.IsObjectIdHex(lending["id"].(string)) {
 		o.ID = bson.ObjectIdHex(lending["id"].(string))
 	}
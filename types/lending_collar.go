@@ -0,0 +1,37 @@
+package types
+
+// InterestCollar is the band of interest rates accepted for the new lending orders of a lending token and term.
+// It is centered on the average interest of the recent lending trades
+type InterestCollar struct {
+	Average uint64 `json:"average"`
+	Min     uint64 `json:"min"`
+	Max     uint64 `json:"max"`
+}
+
+// NewInterestCollar returns the collar of width bandBps basis points around the average interest of the trades.
+// It returns nil if there is no trade to compute the average from
+func NewInterestCollar(trades []*LendingTrade, bandBps int) *InterestCollar {
+	if len(trades) == 0 || bandBps <= 0 {
+		return nil
+	}
+
+	var sum uint64
+	for _, t := range trades {
+		sum += t.Interest
+	}
+
+	avg := sum / uint64(len(trades))
+	delta := avg * uint64(bandBps) / 10000
+
+	c := &InterestCollar{Average: avg, Max: avg + delta}
+	if delta < avg {
+		c.Min = avg - delta
+	}
+
+	return c
+}
+
+// Contains returns true if the interest is inside the collar
+func (c *InterestCollar) Contains(interest uint64) bool {
+	return interest >= c.Min && interest <= c.Max
+}
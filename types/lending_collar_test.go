@@ -0,0 +1,27 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewInterestCollar(t *testing.T) {
+	trades := []*LendingTrade{{Interest: 800}, {Interest: 1000}, {Interest: 1200}}
+
+	c := NewInterestCollar(trades, 2000)
+	assert.Equal(t, uint64(1000), c.Average)
+	assert.Equal(t, uint64(800), c.Min)
+	assert.Equal(t, uint64(1200), c.Max)
+	assert.True(t, c.Contains(800))
+	assert.True(t, c.Contains(1200))
+	assert.False(t, c.Contains(1201))
+	assert.False(t, c.Contains(10000))
+
+	c = NewInterestCollar(trades, 20000)
+	assert.Equal(t, uint64(0), c.Min)
+	assert.Equal(t, uint64(3000), c.Max)
+
+	assert.Nil(t, NewInterestCollar(nil, 2000))
+	assert.Nil(t, NewInterestCollar(trades, 0))
+}
@@ -0,0 +1,92 @@
+package types
+
+import (
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// LendingLiquidation is the liquidation of the collateral of a loan, streamed on the lending liquidations channel
+type LendingLiquidation struct {
+	TradeHash       common.Hash
+	TxHash          common.Hash
+	Term            uint64
+	LendingToken    common.Address
+	CollateralToken common.Address
+	Borrower        common.Address
+	Investor        common.Address
+	Amount          *big.Int
+	CollateralSold  *big.Int
+	Price           *big.Int
+	LiquidatedAt    time.Time
+}
+
+// NewLendingLiquidation returns the liquidation of a liquidated lending trade from the token transfers of its
+// liquidation transaction, the transaction of the trade. The collateral sold is the collateral transferred
+// except the surplus returned to the borrower, the price the lending tokens received by the investor per
+// collateral token. They are left unset if the transaction has no such transfer
+func NewLendingLiquidation(t *LendingTrade, transfers []*TokenTransfer, collateralDecimals uint8) *LendingLiquidation {
+	l := &LendingLiquidation{
+		TradeHash:       t.Hash,
+		TxHash:          t.TxHash,
+		Term:            t.Term,
+		LendingToken:    t.LendingToken,
+		CollateralToken: t.CollateralToken,
+		Borrower:        t.Borrower,
+		Investor:        t.Investor,
+		Amount:          t.Amount,
+		LiquidatedAt:    t.UpdatedAt,
+	}
+
+	sold := big.NewInt(0)
+	proceeds := big.NewInt(0)
+	for _, tr := range transfers {
+		switch {
+		case tr.Token == t.CollateralToken && tr.To != t.Borrower && tr.To != tr.From:
+			sold.Add(sold, tr.Value)
+		case tr.Token == t.LendingToken && tr.To == t.Investor && tr.From != t.Investor:
+			proceeds.Add(proceeds, tr.Value)
+		}
+	}
+
+	if sold.Sign() == 0 {
+		return l
+	}
+
+	l.CollateralSold = sold
+	if proceeds.Sign() > 0 {
+		unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(collateralDecimals)), nil)
+		l.Price = new(big.Int).Div(new(big.Int).Mul(proceeds, unit), sold)
+	}
+
+	return l
+}
+
+// MarshalJSON returns the json encoded byte array representing the lending liquidation
+func (l *LendingLiquidation) MarshalJSON() ([]byte, error) {
+	liquidation := map[string]interface{}{
+		"tradeHash":       l.TradeHash.Hex(),
+		"txHash":          l.TxHash.Hex(),
+		"term":            strconv.FormatUint(l.Term, 10),
+		"lendingToken":    l.LendingToken.Hex(),
+		"collateralToken": l.CollateralToken.Hex(),
+		"borrower":        l.Borrower.Hex(),
+		"investor":        l.Investor.Hex(),
+		"liquidatedAt":    l.LiquidatedAt.Format(time.RFC3339Nano),
+	}
+
+	for k, v := range map[string]*big.Int{
+		"amount":         l.Amount,
+		"collateralSold": l.CollateralSold,
+		"price":          l.Price,
+	} {
+		if v != nil {
+			liquidation[k] = v.String()
+		}
+	}
+
+	return json.Marshal(liquidation)
+}
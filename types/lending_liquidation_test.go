@@ -0,0 +1,60 @@
+package types
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLendingLiquidation(t *testing.T) {
+	lendingToken := common.HexToAddress("0xa")
+	collateralToken := common.HexToAddress("0xb")
+	borrower := common.HexToAddress("0x1")
+	investor := common.HexToAddress("0x2")
+	lending := common.HexToAddress("0x3")
+	buyer := common.HexToAddress("0x4")
+
+	trade := &LendingTrade{
+		Hash:                   common.HexToHash("0x10"),
+		TxHash:                 common.HexToHash("0x11"),
+		LendingToken:           lendingToken,
+		CollateralToken:        collateralToken,
+		Borrower:               borrower,
+		Investor:               investor,
+		Amount:                 big.NewInt(1000),
+		CollateralLockedAmount: big.NewInt(5000),
+		LiquidationPrice:       big.NewInt(1),
+	}
+
+	// the collateral is sold to a buyer, the surplus is returned to the borrower
+	transfers := []*TokenTransfer{
+		{Token: collateralToken, From: lending, To: buyer, Value: big.NewInt(3400)},
+		{Token: collateralToken, From: lending, To: borrower, Value: big.NewInt(1600)},
+		{Token: lendingToken, From: buyer, To: investor, Value: big.NewInt(1000)},
+		{Token: lendingToken, From: buyer, To: lending, Value: big.NewInt(7)},
+	}
+
+	l := NewLendingLiquidation(trade, transfers, 2)
+	assert.Equal(t, trade.Hash, l.TradeHash)
+	assert.Equal(t, trade.TxHash, l.TxHash)
+	assert.Equal(t, big.NewInt(1000), l.Amount)
+	assert.Equal(t, big.NewInt(3400), l.CollateralSold)
+	assert.Equal(t, big.NewInt(29), l.Price)
+
+	// without transfer of the collateral the amounts are left out
+	l = NewLendingLiquidation(trade, []*TokenTransfer{}, 2)
+	assert.Nil(t, l.CollateralSold)
+	assert.Nil(t, l.Price)
+
+	raw, err := json.Marshal(l)
+	assert.NoError(t, err)
+
+	res := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(raw, &res))
+	assert.Equal(t, "1000", res["amount"])
+	assert.NotContains(t, res, "collateralSold")
+	assert.NotContains(t, res, "price")
+}
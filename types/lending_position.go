@@ -0,0 +1,127 @@
+package types
+
+import (
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/utils/math"
+)
+
+// LendingInterestDecimals is the precision of the interests, an annual rate of 10% is 10 * 10^8
+const LendingInterestDecimals = 1e8
+
+const secondsPerYear = 365 * 24 * 3600
+
+// LendingPosition is an open loan of an account with its accrued interest and the health of its collateral
+type LendingPosition struct {
+	TradeHash              common.Hash
+	Role                   string
+	LendingToken           common.Address
+	CollateralToken        common.Address
+	Term                   uint64
+	Interest               uint64
+	Amount                 *big.Int
+	AccruedInterest        *big.Int
+	CollateralLockedAmount *big.Int
+	CollateralPrice        *big.Int
+	LiquidationPrice       *big.Int
+	MaturityTime           time.Time
+	HealthFactor           float64
+	AutoTopUp              bool
+}
+
+// LendingPositions are the open lends and borrows of an account
+type LendingPositions struct {
+	Address common.Address     `json:"address"`
+	Lends   []*LendingPosition `json:"lends"`
+	Borrows []*LendingPosition `json:"borrows"`
+}
+
+// NewLendingPosition returns the position of a party of an open lending trade at a time.
+// The collateral price is the current price of the collateral in lending token, nil if unknown
+func NewLendingPosition(t *LendingTrade, user common.Address, collateralPrice *big.Int, now time.Time) *LendingPosition {
+	m := NewLoanMaturity(t, user)
+
+	p := &LendingPosition{
+		TradeHash:              t.Hash,
+		Role:                   m.Role,
+		LendingToken:           t.LendingToken,
+		CollateralToken:        t.CollateralToken,
+		Term:                   t.Term,
+		Interest:               t.Interest,
+		Amount:                 t.Amount,
+		AccruedInterest:        AccruedInterest(t, now),
+		CollateralLockedAmount: t.CollateralLockedAmount,
+		CollateralPrice:        collateralPrice,
+		LiquidationPrice:       t.LiquidationPrice,
+		MaturityTime:           m.MaturityTime,
+		AutoTopUp:              t.AutoTopUp == 1,
+	}
+
+	p.HealthFactor = HealthFactor(collateralPrice, t.LiquidationPrice)
+	return p
+}
+
+// AccruedInterest returns the interest of a loan accrued from its start to a time, capped to its term
+func AccruedInterest(t *LendingTrade, now time.Time) *big.Int {
+	if t.Amount == nil || t.LiquidationTime < t.Term {
+		return big.NewInt(0)
+	}
+
+	start := int64(t.LiquidationTime - t.Term)
+	elapsed := now.Unix() - start
+	if elapsed <= 0 {
+		return big.NewInt(0)
+	}
+
+	if uint64(elapsed) > t.Term {
+		elapsed = int64(t.Term)
+	}
+
+	interest := math.Mul(t.Amount, new(big.Int).SetUint64(t.Interest))
+	interest = math.Mul(interest, big.NewInt(elapsed))
+	return math.Div(interest, big.NewInt(100*LendingInterestDecimals*secondsPerYear))
+}
+
+// HealthFactor returns the ratio of the current collateral price to the liquidation price.
+// The loan is liquidated when it drops below 1, it returns 0 if a price is unknown
+func HealthFactor(collateralPrice, liquidationPrice *big.Int) float64 {
+	if collateralPrice == nil || liquidationPrice == nil || liquidationPrice.Sign() == 0 {
+		return 0
+	}
+
+	f, _ := new(big.Float).Quo(new(big.Float).SetInt(collateralPrice), new(big.Float).SetInt(liquidationPrice)).Float64()
+	return f
+}
+
+// MarshalJSON returns the json encoded byte array representing the lending position
+func (p *LendingPosition) MarshalJSON() ([]byte, error) {
+	position := map[string]interface{}{
+		"tradeHash":       p.TradeHash.Hex(),
+		"role":            p.Role,
+		"lendingToken":    p.LendingToken.Hex(),
+		"collateralToken": p.CollateralToken.Hex(),
+		"term":            strconv.FormatUint(p.Term, 10),
+		"interest":        strconv.FormatUint(p.Interest, 10),
+		"maturityTime":    p.MaturityTime.Format(time.RFC3339),
+		"healthFactor":    p.HealthFactor,
+		"autoTopUp":       p.AutoTopUp,
+	}
+
+	for k, v := range map[string]*big.Int{
+		"amount":                 p.Amount,
+		"accruedInterest":        p.AccruedInterest,
+		"collateralLockedAmount": p.CollateralLockedAmount,
+		"collateralPrice":        p.CollateralPrice,
+		"liquidationPrice":       p.LiquidationPrice,
+	} {
+		if v != nil {
+			position[k] = v.String()
+		}
+	}
+
+	return json.Marshal(position)
+}
@@ -0,0 +1,46 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccruedInterest(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	tr := &LendingTrade{
+		Amount:          big.NewInt(1e18),
+		Interest:        10 * LendingInterestDecimals,
+		Term:            secondsPerYear,
+		LiquidationTime: uint64(start.Unix()) + secondsPerYear,
+	}
+
+	assert.Equal(t, "0", AccruedInterest(tr, start.Add(-time.Hour)).String())
+	assert.Equal(t, "50000000000000000", AccruedInterest(tr, start.Add(secondsPerYear/2*time.Second)).String())
+	assert.Equal(t, "100000000000000000", AccruedInterest(tr, start.Add(2*secondsPerYear*time.Second)).String())
+}
+
+func TestNewLendingPosition(t *testing.T) {
+	borrower := common.HexToAddress("0x1")
+	tr := &LendingTrade{
+		Borrower:         borrower,
+		Investor:         common.HexToAddress("0x2"),
+		Amount:           big.NewInt(1000),
+		Term:             3600,
+		LiquidationTime:  7200,
+		LiquidationPrice: big.NewInt(80),
+		AutoTopUp:        1,
+	}
+
+	p := NewLendingPosition(tr, borrower, big.NewInt(100), time.Unix(3600, 0))
+	assert.Equal(t, LoanRoleBorrower, p.Role)
+	assert.Equal(t, 1.25, p.HealthFactor)
+	assert.True(t, p.AutoTopUp)
+
+	p = NewLendingPosition(tr, tr.Investor, nil, time.Unix(3600, 0))
+	assert.Equal(t, LoanRoleInvestor, p.Role)
+	assert.Equal(t, float64(0), p.HealthFactor)
+}
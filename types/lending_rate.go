@@ -0,0 +1,215 @@
+package types
+
+import (
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/utils/math"
+)
+
+// LendingRateIntervals are the downsampling intervals of the lending rate series in seconds,
+// "raw" returns the snapshots as they were taken
+var LendingRateIntervals = map[string]int64{
+	"raw": 0,
+	"1h":  3600,
+	"4h":  4 * 3600,
+	"1d":  24 * 3600,
+	"1w":  7 * 24 * 3600,
+}
+
+// LendingRateSnapshot is the utilization and the borrow rate of a lending token at a time. Borrowed is
+// the amount of the open loans and Available the remaining amount of the open lend orders, the
+// utilization is Borrowed / (Borrowed + Available). BorrowRate is the mean annual interest in percent
+// of the open loans weighted by their amounts
+type LendingRateSnapshot struct {
+	ID           bson.ObjectId
+	LendingToken common.Address
+	Borrowed     *big.Int
+	Available    *big.Int
+	Utilization  float64
+	BorrowRate   float64
+	Loans        int
+	CreatedAt    time.Time
+}
+
+// LendingRateSnapshotRecord is the lending rate snapshot stored in the database
+type LendingRateSnapshotRecord struct {
+	ID           bson.ObjectId `bson:"_id"`
+	LendingToken string        `bson:"lendingToken"`
+	Borrowed     string        `bson:"borrowed"`
+	Available    string        `bson:"available"`
+	Utilization  float64       `bson:"utilization"`
+	BorrowRate   float64       `bson:"borrowRate"`
+	Loans        int           `bson:"loans"`
+	CreatedAt    time.Time     `bson:"createdAt"`
+}
+
+// LendingRatePoint is a point of a lending rate series, the mean utilization and borrow rate of the
+// snapshots of its interval with the amounts of the last one, as decimal strings
+type LendingRatePoint struct {
+	Timestamp   int64   `json:"timestamp"`
+	Borrowed    string  `json:"borrowed"`
+	Available   string  `json:"available"`
+	Utilization float64 `json:"utilization"`
+	BorrowRate  float64 `json:"borrowRate"`
+	Loans       int     `json:"loans"`
+	Samples     int     `json:"samples"`
+}
+
+// LendingRateSeries is a page of the points of a lending token between two times, oldest first.
+// Total is the number of points of the period
+type LendingRateSeries struct {
+	LendingToken common.Address      `json:"lendingToken"`
+	Interval     string              `json:"interval"`
+	From         int64               `json:"from"`
+	To           int64               `json:"to"`
+	Total        int                 `json:"total"`
+	Points       []*LendingRatePoint `json:"points"`
+}
+
+// NewLendingRateSnapshots computes the snapshots of the lending tokens of the open loans and lend orders
+func NewLendingRateSnapshots(loans []*LendingTrade, lends []*LendingOrder, now time.Time) []*LendingRateSnapshot {
+	snapshots := map[common.Address]*LendingRateSnapshot{}
+	rates := map[common.Address]*big.Int{}
+	get := func(token common.Address) *LendingRateSnapshot {
+		s, ok := snapshots[token]
+		if !ok {
+			s = &LendingRateSnapshot{
+				LendingToken: token,
+				Borrowed:     big.NewInt(0),
+				Available:    big.NewInt(0),
+				CreatedAt:    now,
+			}
+
+			snapshots[token] = s
+			rates[token] = big.NewInt(0)
+		}
+
+		return s
+	}
+
+	for _, t := range loans {
+		if t.Status != TradeStatusOpen || t.Amount == nil {
+			continue
+		}
+
+		s := get(t.LendingToken)
+		s.Borrowed = math.Add(s.Borrowed, t.Amount)
+		s.Loans++
+		rates[t.LendingToken] = math.Add(rates[t.LendingToken], math.Mul(t.Amount, new(big.Int).SetUint64(t.Interest)))
+	}
+
+	for _, o := range lends {
+		if o.Side != LEND || o.Quantity == nil {
+			continue
+		}
+
+		remaining := o.Quantity
+		if o.FilledAmount != nil {
+			remaining = math.Sub(o.Quantity, o.FilledAmount)
+		}
+
+		if remaining.Sign() <= 0 {
+			continue
+		}
+
+		s := get(o.LendingToken)
+		s.Available = math.Add(s.Available, remaining)
+	}
+
+	res := []*LendingRateSnapshot{}
+	for token, s := range snapshots {
+		total := math.Add(s.Borrowed, s.Available)
+		if total.Sign() > 0 {
+			s.Utilization = lendingRatio(s.Borrowed, total)
+		}
+
+		if s.Borrowed.Sign() > 0 {
+			s.BorrowRate = lendingRatio(rates[token], s.Borrowed) / LendingInterestDecimals
+		}
+
+		res = append(res, s)
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].LendingToken.Hex() < res[j].LendingToken.Hex() })
+	return res
+}
+
+func lendingRatio(a, b *big.Int) float64 {
+	r, _ := new(big.Float).Quo(new(big.Float).SetInt(a), new(big.Float).SetInt(b)).Float64()
+	return r
+}
+
+// DownsampleLendingRates groups the snapshots, oldest first, in intervals of the given seconds aligned
+// on the unix epoch. The points of the raw interval, 0, are the snapshots
+func DownsampleLendingRates(snapshots []*LendingRateSnapshot, interval int64) []*LendingRatePoint {
+	res := []*LendingRatePoint{}
+
+	var p *LendingRatePoint
+	for _, s := range snapshots {
+		ts := s.CreatedAt.Unix()
+		if interval > 0 {
+			ts -= ts % interval
+		}
+
+		if p == nil || interval == 0 || p.Timestamp != ts {
+			if p != nil {
+				p.Utilization /= float64(p.Samples)
+				p.BorrowRate /= float64(p.Samples)
+			}
+
+			p = &LendingRatePoint{Timestamp: ts}
+			res = append(res, p)
+		}
+
+		p.Borrowed = s.Borrowed.String()
+		p.Available = s.Available.String()
+		p.Loans = s.Loans
+		p.Utilization += s.Utilization
+		p.BorrowRate += s.BorrowRate
+		p.Samples++
+	}
+
+	if p != nil {
+		p.Utilization /= float64(p.Samples)
+		p.BorrowRate /= float64(p.Samples)
+	}
+
+	return res
+}
+
+// GetBSON implements bson.Getter
+func (s *LendingRateSnapshot) GetBSON() (interface{}, error) {
+	return LendingRateSnapshotRecord{
+		ID:           s.ID,
+		LendingToken: s.LendingToken.Hex(),
+		Borrowed:     s.Borrowed.String(),
+		Available:    s.Available.String(),
+		Utilization:  s.Utilization,
+		BorrowRate:   s.BorrowRate,
+		Loans:        s.Loans,
+		CreatedAt:    s.CreatedAt,
+	}, nil
+}
+
+// SetBSON implements bson.Setter
+func (s *LendingRateSnapshot) SetBSON(raw bson.Raw) error {
+	decoded := &LendingRateSnapshotRecord{}
+	err := raw.Unmarshal(decoded)
+	if err != nil {
+		return err
+	}
+
+	s.ID = decoded.ID
+	s.LendingToken = common.HexToAddress(decoded.LendingToken)
+	s.Borrowed = math.ToBigInt(decoded.Borrowed)
+	s.Available = math.ToBigInt(decoded.Available)
+	s.Utilization = decoded.Utilization
+	s.BorrowRate = decoded.BorrowRate
+	s.Loans = decoded.Loans
+	s.CreatedAt = decoded.CreatedAt
+	return nil
+}
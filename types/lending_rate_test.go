@@ -0,0 +1,79 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLendingRateSnapshots(t *testing.T) {
+	usdt := common.HexToAddress("0x1")
+	btc := common.HexToAddress("0x2")
+	now := time.Unix(1000, 0)
+
+	loans := []*LendingTrade{
+		{LendingToken: usdt, Amount: big.NewInt(100), Interest: 10 * LendingInterestDecimals, Status: TradeStatusOpen},
+		{LendingToken: usdt, Amount: big.NewInt(300), Interest: 6 * LendingInterestDecimals, Status: TradeStatusOpen},
+		{LendingToken: usdt, Amount: big.NewInt(1000), Interest: 50 * LendingInterestDecimals, Status: TradeStatusLiquidated},
+	}
+
+	lends := []*LendingOrder{
+		{LendingToken: usdt, Side: LEND, Quantity: big.NewInt(700), FilledAmount: big.NewInt(100)},
+		{LendingToken: btc, Side: LEND, Quantity: big.NewInt(50)},
+		{LendingToken: btc, Side: BORROW, Quantity: big.NewInt(50)},
+	}
+
+	res := NewLendingRateSnapshots(loans, lends, now)
+	assert.Len(t, res, 2)
+
+	assert.Equal(t, usdt, res[0].LendingToken)
+	assert.Equal(t, big.NewInt(400), res[0].Borrowed)
+	assert.Equal(t, big.NewInt(600), res[0].Available)
+	assert.Equal(t, 0.4, res[0].Utilization)
+	assert.Equal(t, 7.0, res[0].BorrowRate)
+	assert.Equal(t, 2, res[0].Loans)
+	assert.Equal(t, now, res[0].CreatedAt)
+
+	assert.Equal(t, btc, res[1].LendingToken)
+	assert.Equal(t, big.NewInt(0), res[1].Borrowed)
+	assert.Equal(t, 0.0, res[1].Utilization)
+	assert.Equal(t, 0.0, res[1].BorrowRate)
+}
+
+func TestDownsampleLendingRates(t *testing.T) {
+	snapshot := func(at int64, borrowed int64, utilization, rate float64) *LendingRateSnapshot {
+		return &LendingRateSnapshot{
+			Borrowed:    big.NewInt(borrowed),
+			Available:   big.NewInt(10),
+			Utilization: utilization,
+			BorrowRate:  rate,
+			CreatedAt:   time.Unix(at, 0),
+		}
+	}
+
+	snapshots := []*LendingRateSnapshot{
+		snapshot(3600, 1, 0.2, 4),
+		snapshot(4200, 2, 0.4, 6),
+		snapshot(7300, 3, 0.5, 8),
+	}
+
+	res := DownsampleLendingRates(snapshots, 3600)
+	assert.Len(t, res, 2)
+	assert.Equal(t, int64(3600), res[0].Timestamp)
+	assert.InDelta(t, 0.3, res[0].Utilization, 1e-9)
+	assert.Equal(t, 5.0, res[0].BorrowRate)
+	assert.Equal(t, "2", res[0].Borrowed)
+	assert.Equal(t, 2, res[0].Samples)
+	assert.Equal(t, int64(7200), res[1].Timestamp)
+	assert.Equal(t, 1, res[1].Samples)
+
+	res = DownsampleLendingRates(snapshots, 0)
+	assert.Len(t, res, 3)
+	assert.Equal(t, int64(4200), res[1].Timestamp)
+	assert.Equal(t, 0.4, res[1].Utilization)
+
+	assert.Empty(t, DownsampleLendingRates(nil, 3600))
+}
@@ -0,0 +1,215 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/utils/math"
+)
+
+// Statuses of the review of a new pair
+const (
+	ListingStatusPending  = "PENDING_REVIEW"
+	ListingStatusApproved = "APPROVED"
+	ListingStatusRejected = "REJECTED"
+)
+
+// Names of the listing checks
+const (
+	ListingCheckDecimals  = "decimals"
+	ListingCheckHolders   = "holders"
+	ListingCheckLiquidity = "liquidity"
+)
+
+// ListingRequirements are the requirements a new pair must meet to be listed.
+// MinLiquidity is in whole quote tokens, a zero requirement is not checked
+type ListingRequirements struct {
+	MinHolders   int
+	MinLiquidity int64
+	MinDecimals  int
+	MaxDecimals  int
+}
+
+// ListingCheck is the result of a listing requirement
+type ListingCheck struct {
+	Name   string `json:"name" bson:"name"`
+	Passed bool   `json:"passed" bson:"passed"`
+	Detail string `json:"detail" bson:"detail"`
+}
+
+// ListingReview holds a pair discovered by the relayer reconciler until an operator approves it.
+// The holders and the liquidity commitment are attested by the reviewer
+type ListingReview struct {
+	ID                  bson.ObjectId   `json:"id" bson:"_id"`
+	BaseToken           common.Address  `json:"baseToken" bson:"baseToken"`
+	QuoteToken          common.Address  `json:"quoteToken" bson:"quoteToken"`
+	BaseTokenSymbol     string          `json:"baseTokenSymbol" bson:"baseTokenSymbol"`
+	QuoteTokenSymbol    string          `json:"quoteTokenSymbol" bson:"quoteTokenSymbol"`
+	BaseTokenDecimals   int             `json:"baseTokenDecimals" bson:"baseTokenDecimals"`
+	QuoteTokenDecimals  int             `json:"quoteTokenDecimals" bson:"quoteTokenDecimals"`
+	RelayerAddress      common.Address  `json:"relayerAddress" bson:"relayerAddress"`
+	Status              string          `json:"status" bson:"status"`
+	Checks              []*ListingCheck `json:"checks" bson:"checks"`
+	Holders             int             `json:"holders" bson:"holders"`
+	LiquidityCommitment *big.Int        `json:"liquidityCommitment" bson:"liquidityCommitment"`
+	ReviewedBy          string          `json:"reviewedBy,omitempty" bson:"reviewedBy"`
+	Reason              string          `json:"reason,omitempty" bson:"reason"`
+	CreatedAt           time.Time       `json:"createdAt" bson:"createdAt"`
+	UpdatedAt           time.Time       `json:"updatedAt" bson:"updatedAt"`
+}
+
+// ListingReviewRecord is the representation of a ListingReview in the database
+type ListingReviewRecord struct {
+	ID                  bson.ObjectId   `bson:"_id"`
+	BaseToken           string          `bson:"baseToken"`
+	QuoteToken          string          `bson:"quoteToken"`
+	BaseTokenSymbol     string          `bson:"baseTokenSymbol"`
+	QuoteTokenSymbol    string          `bson:"quoteTokenSymbol"`
+	BaseTokenDecimals   int             `bson:"baseTokenDecimals"`
+	QuoteTokenDecimals  int             `bson:"quoteTokenDecimals"`
+	RelayerAddress      string          `bson:"relayerAddress"`
+	Status              string          `bson:"status"`
+	Checks              []*ListingCheck `bson:"checks"`
+	Holders             int             `bson:"holders"`
+	LiquidityCommitment string          `bson:"liquidityCommitment"`
+	ReviewedBy          string          `bson:"reviewedBy"`
+	Reason              string          `bson:"reason"`
+	CreatedAt           time.Time       `bson:"createdAt"`
+	UpdatedAt           time.Time       `bson:"updatedAt"`
+}
+
+// NewListingReview returns the pending review of a pair discovered by the relayer reconciler
+func NewListingReview(p *Pair) *ListingReview {
+	return &ListingReview{
+		BaseToken:           p.BaseTokenAddress,
+		QuoteToken:          p.QuoteTokenAddress,
+		BaseTokenSymbol:     p.BaseTokenSymbol,
+		QuoteTokenSymbol:    p.QuoteTokenSymbol,
+		BaseTokenDecimals:   p.BaseTokenDecimals,
+		QuoteTokenDecimals:  p.QuoteTokenDecimals,
+		RelayerAddress:      p.RelayerAddress,
+		Status:              ListingStatusPending,
+		LiquidityCommitment: big.NewInt(0),
+	}
+}
+
+// Evaluate runs the checks of the requirements on the review and returns true if all of them pass
+func (l *ListingReview) Evaluate(req *ListingRequirements) bool {
+	l.Checks = []*ListingCheck{}
+	passed := true
+
+	add := func(name string, ok bool, detail string) {
+		l.Checks = append(l.Checks, &ListingCheck{Name: name, Passed: ok, Detail: detail})
+		passed = passed && ok
+	}
+
+	if req.MinDecimals > 0 || req.MaxDecimals > 0 {
+		ok := decimalsInRange(l.BaseTokenDecimals, req) && decimalsInRange(l.QuoteTokenDecimals, req)
+		add(ListingCheckDecimals, ok, fmt.Sprintf(
+			"base %d, quote %d, expected between %d and %d",
+			l.BaseTokenDecimals, l.QuoteTokenDecimals, req.MinDecimals, req.MaxDecimals,
+		))
+	}
+
+	if req.MinHolders > 0 {
+		add(ListingCheckHolders, l.Holders >= req.MinHolders, fmt.Sprintf(
+			"%d holders, expected at least %d", l.Holders, req.MinHolders,
+		))
+	}
+
+	if req.MinLiquidity > 0 {
+		min := math.Mul(big.NewInt(req.MinLiquidity), math.Exp(big.NewInt(10), big.NewInt(int64(l.QuoteTokenDecimals))))
+		commitment := l.LiquidityCommitment
+		if commitment == nil {
+			commitment = big.NewInt(0)
+		}
+
+		add(ListingCheckLiquidity, commitment.Cmp(min) >= 0, fmt.Sprintf(
+			"%s committed, expected at least %s", commitment, min,
+		))
+	}
+
+	return passed
+}
+
+func decimalsInRange(decimals int, req *ListingRequirements) bool {
+	if decimals < req.MinDecimals {
+		return false
+	}
+
+	return req.MaxDecimals == 0 || decimals <= req.MaxDecimals
+}
+
+// MarshalJSON returns the json encoded byte array representing the listing review
+func (l *ListingReview) MarshalJSON() ([]byte, error) {
+	type alias ListingReview
+	commitment := "0"
+	if l.LiquidityCommitment != nil {
+		commitment = l.LiquidityCommitment.String()
+	}
+
+	return json.Marshal(&struct {
+		*alias
+		LiquidityCommitment string `json:"liquidityCommitment"`
+	}{(*alias)(l), commitment})
+}
+
+// GetBSON implements bson.Getter
+func (l *ListingReview) GetBSON() (interface{}, error) {
+	commitment := "0"
+	if l.LiquidityCommitment != nil {
+		commitment = l.LiquidityCommitment.String()
+	}
+
+	return ListingReviewRecord{
+		ID:                  l.ID,
+		BaseToken:           l.BaseToken.Hex(),
+		QuoteToken:          l.QuoteToken.Hex(),
+		BaseTokenSymbol:     l.BaseTokenSymbol,
+		QuoteTokenSymbol:    l.QuoteTokenSymbol,
+		BaseTokenDecimals:   l.BaseTokenDecimals,
+		QuoteTokenDecimals:  l.QuoteTokenDecimals,
+		RelayerAddress:      l.RelayerAddress.Hex(),
+		Status:              l.Status,
+		Checks:              l.Checks,
+		Holders:             l.Holders,
+		LiquidityCommitment: commitment,
+		ReviewedBy:          l.ReviewedBy,
+		Reason:              l.Reason,
+		CreatedAt:           l.CreatedAt,
+		UpdatedAt:           l.UpdatedAt,
+	}, nil
+}
+
+// SetBSON implements bson.Setter
+func (l *ListingReview) SetBSON(raw bson.Raw) error {
+	decoded := &ListingReviewRecord{}
+
+	err := raw.Unmarshal(decoded)
+	if err != nil {
+		return err
+	}
+
+	l.ID = decoded.ID
+	l.BaseToken = common.HexToAddress(decoded.BaseToken)
+	l.QuoteToken = common.HexToAddress(decoded.QuoteToken)
+	l.BaseTokenSymbol = decoded.BaseTokenSymbol
+	l.QuoteTokenSymbol = decoded.QuoteTokenSymbol
+	l.BaseTokenDecimals = decoded.BaseTokenDecimals
+	l.QuoteTokenDecimals = decoded.QuoteTokenDecimals
+	l.RelayerAddress = common.HexToAddress(decoded.RelayerAddress)
+	l.Status = decoded.Status
+	l.Checks = decoded.Checks
+	l.Holders = decoded.Holders
+	l.LiquidityCommitment = math.ToBigInt(decoded.LiquidityCommitment)
+	l.ReviewedBy = decoded.ReviewedBy
+	l.Reason = decoded.Reason
+	l.CreatedAt = decoded.CreatedAt
+	l.UpdatedAt = decoded.UpdatedAt
+
+	return nil
+}
@@ -0,0 +1,29 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListingReviewEvaluate(t *testing.T) {
+	req := &ListingRequirements{MinHolders: 100, MinLiquidity: 1000, MinDecimals: 6, MaxDecimals: 18}
+	l := NewListingReview(&Pair{BaseTokenDecimals: 18, QuoteTokenDecimals: 6})
+
+	assert.False(t, l.Evaluate(req))
+	assert.Equal(t, 3, len(l.Checks))
+	assert.True(t, l.Checks[0].Passed)
+	assert.False(t, l.Checks[1].Passed)
+	assert.False(t, l.Checks[2].Passed)
+
+	l.Holders = 100
+	l.LiquidityCommitment = big.NewInt(1000e6)
+	assert.True(t, l.Evaluate(req))
+
+	l.BaseTokenDecimals = 0
+	assert.False(t, l.Evaluate(req))
+
+	l.Evaluate(&ListingRequirements{})
+	assert.Equal(t, 0, len(l.Checks))
+}
@@ -0,0 +1,120 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Roles of a user in a loan
+const (
+	LoanRoleBorrower = "borrower"
+	LoanRoleInvestor = "investor"
+)
+
+// LoanMaturityMessage is the message type of the loan maturity reminders
+const LoanMaturityMessage = "LOAN_MATURITY"
+
+// maturityDateLayout is the layout of the dates the maturities are grouped by
+const maturityDateLayout = "2006-01-02"
+
+// LoanMaturity is an open loan of a user with its maturity
+type LoanMaturity struct {
+	TradeHash       common.Hash
+	Role            string
+	LendingToken    common.Address
+	CollateralToken common.Address
+	Term            uint64
+	Interest        uint64
+	Amount          *big.Int
+	MaturityTime    time.Time
+}
+
+// MaturityDate is the list of loans of a user maturing on the same UTC date
+type MaturityDate struct {
+	Date  string          `json:"date"`
+	Loans []*LoanMaturity `json:"loans"`
+}
+
+// NewLoanMaturity returns the maturity of a lending trade for one of its parties
+func NewLoanMaturity(t *LendingTrade, user common.Address) *LoanMaturity {
+	role := LoanRoleInvestor
+	if t.Borrower == user {
+		role = LoanRoleBorrower
+	}
+
+	return &LoanMaturity{
+		TradeHash:       t.Hash,
+		Role:            role,
+		LendingToken:    t.LendingToken,
+		CollateralToken: t.CollateralToken,
+		Term:            t.Term,
+		Interest:        t.Interest,
+		Amount:          t.Amount,
+		MaturityTime:    time.Unix(int64(t.LiquidationTime), 0).UTC(),
+	}
+}
+
+// GroupMaturitiesByDate returns the maturities of the lending trades of a user grouped by UTC date,
+// ordered by maturity time
+func GroupMaturitiesByDate(trades []*LendingTrade, user common.Address) []*MaturityDate {
+	maturities := make([]*LoanMaturity, 0, len(trades))
+	for _, t := range trades {
+		maturities = append(maturities, NewLoanMaturity(t, user))
+	}
+
+	sort.SliceStable(maturities, func(i, j int) bool {
+		return maturities[i].MaturityTime.Before(maturities[j].MaturityTime)
+	})
+
+	dates := []*MaturityDate{}
+	for _, m := range maturities {
+		date := m.MaturityTime.Format(maturityDateLayout)
+		if len(dates) == 0 || dates[len(dates)-1].Date != date {
+			dates = append(dates, &MaturityDate{Date: date, Loans: []*LoanMaturity{}})
+		}
+
+		d := dates[len(dates)-1]
+		d.Loans = append(d.Loans, m)
+	}
+
+	return dates
+}
+
+// ReminderMessage returns the reminder message of a loan maturing after the lead time
+func (m *LoanMaturity) ReminderMessage(lead time.Duration) Message {
+	return Message{
+		MessageType: LoanMaturityMessage,
+		Description: fmt.Sprintf(
+			"Your loan %s as %s matures in %s, on %s",
+			m.TradeHash.Hex(),
+			m.Role,
+			lead,
+			m.MaturityTime.Format(time.RFC3339),
+		),
+	}
+}
+
+// MarshalJSON returns the json encoded byte array representing the loan maturity
+func (m *LoanMaturity) MarshalJSON() ([]byte, error) {
+	maturity := map[string]interface{}{
+		"tradeHash":       m.TradeHash.Hex(),
+		"role":            m.Role,
+		"lendingToken":    m.LendingToken.Hex(),
+		"collateralToken": m.CollateralToken.Hex(),
+		"term":            strconv.FormatUint(m.Term, 10),
+		"interest":        strconv.FormatUint(m.Interest, 10),
+		"maturityTime":    m.MaturityTime.Format(time.RFC3339),
+	}
+
+	if m.Amount != nil {
+		maturity["amount"] = m.Amount.String()
+	}
+
+	return json.Marshal(maturity)
+}
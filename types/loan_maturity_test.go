@@ -0,0 +1,33 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupMaturitiesByDate(t *testing.T) {
+	user := common.HexToAddress("0x1")
+	other := common.HexToAddress("0x2")
+	day := time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	trades := []*LendingTrade{
+		{Hash: common.HexToHash("0x3"), Borrower: other, Investor: user, Amount: big.NewInt(1), LiquidationTime: uint64(day.Add(26 * time.Hour).Unix())},
+		{Hash: common.HexToHash("0x4"), Borrower: user, Investor: other, Amount: big.NewInt(1), LiquidationTime: uint64(day.Add(10 * time.Hour).Unix())},
+		{Hash: common.HexToHash("0x5"), Borrower: user, Investor: other, Amount: big.NewInt(1), LiquidationTime: uint64(day.Add(2 * time.Hour).Unix())},
+	}
+
+	dates := GroupMaturitiesByDate(trades, user)
+	assert.Equal(t, 2, len(dates))
+	assert.Equal(t, "2020-05-01", dates[0].Date)
+	assert.Equal(t, 2, len(dates[0].Loans))
+	assert.Equal(t, common.HexToHash("0x5"), dates[0].Loans[0].TradeHash)
+	assert.Equal(t, LoanRoleBorrower, dates[0].Loans[0].Role)
+	assert.Equal(t, "2020-05-02", dates[1].Date)
+	assert.Equal(t, LoanRoleInvestor, dates[1].Loans[0].Role)
+
+	assert.Equal(t, 0, len(GroupMaturitiesByDate(nil, user)))
+}
@@ -1,6 +1,10 @@
 package types
 
-import "github.com/tomochain/tomox-sdk/utils"
+import (
+	"fmt"
+
+	"github.com/tomochain/tomox-sdk/utils"
+)
 
 const (
 	PENDING      = "PENDING"
@@ -10,3 +14,15 @@ const (
 )
 
 var logger = utils.Logger
+
+// checkJSONKeys returns an error for the first key of a decoded payload which is not in known. The custom
+// decoders read the payloads into maps, where the unknown fields are not rejected by the json decoder
+func checkJSONKeys(parsed map[string]interface{}, known map[string]bool) error {
+	for k := range parsed {
+		if !known[k] {
+			return fmt.Errorf("json: unknown field %q", k)
+		}
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/globalsign/mgo/bson"
 )
 
@@ -9,6 +10,29 @@ type MarketData struct {
 	SmallChartsData map[string][]*FiatPriceItem `json:"smallChartsData" bson:"smallChartsData"`
 }
 
+// MarketSnapshot bundles the public market data of a pair needed to render a market page
+type MarketSnapshot struct {
+	Ticker    *PairData  `json:"ticker"`
+	OrderBook *OrderBook `json:"orderBook"`
+	Trades    []*Trade   `json:"trades"`
+	Candles   []*Tick    `json:"candles"`
+	Timestamp int64      `json:"timestamp"`
+}
+
+// TokenPrice is the derived fiat price of a token at a timestamp
+type TokenPrice struct {
+	Timestamp int64  `json:"timestamp"`
+	Price     string `json:"price"`
+}
+
+// TokenPriceHistory is the derived fiat price series of a token
+type TokenPriceHistory struct {
+	Token        common.Address `json:"token"`
+	Symbol       string         `json:"symbol"`
+	FiatCurrency string         `json:"fiatCurrency"`
+	Prices       []*TokenPrice  `json:"prices"`
+}
+
 type ChartItem [2]float64
 
 type CoinsIDMarketChart struct {
@@ -64,3 +88,20 @@ func (i *FiatPriceItem) SetBSON(raw bson.Raw) error {
 
 	return nil
 }
+
+// PublicStats holds the network-wide aggregates of the last 24 hours
+type PublicStats struct {
+	Volume24hUsdt string        `json:"volume24hUsdt"`
+	TradeCount24h int64         `json:"tradeCount24h"`
+	ListedPairs   int           `json:"listedPairs"`
+	ActivePairs   int           `json:"activePairs"`
+	UniqueTraders int           `json:"uniqueTraders"`
+	TopPairs      []*PairVolume `json:"topPairs"`
+	Timestamp     int64         `json:"timestamp"`
+}
+
+// PairVolume is the 24h volume of a pair in USDT
+type PairVolume struct {
+	PairName      string `json:"pairName"`
+	Volume24hUsdt string `json:"volume24hUsdt"`
+}
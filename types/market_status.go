@@ -0,0 +1,58 @@
+package types
+
+import (
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/globalsign/mgo/bson"
+)
+
+// Status of the order intake of a pair
+const (
+	MarketStatusNormal     = "NORMAL"
+	MarketStatusThrottled  = "THROTTLED"
+	MarketStatusOverloaded = "OVERLOADED"
+	MarketStatusPaused     = "PAUSED"
+	MarketStatusQueueing   = "QUEUEING"
+)
+
+// Reasons of the pauses of a pair, each reason is lifted by its source only. The kill switches and the
+// relayers pause the pairs with a reason of their own
+const (
+	PauseReasonOperator   = "operator"
+	PauseReasonSettlement = "settlement"
+	PauseReasonMigration  = "migration"
+)
+
+const killSwitchPauseReasonPrefix = "killswitch:"
+
+// KillSwitchPauseReason returns the pause reason of the pairs halted by a kill switch
+func KillSwitchPauseReason(id bson.ObjectId) string {
+	return killSwitchPauseReasonPrefix + id.Hex()
+}
+
+// IsHaltPauseReason returns true for the pause reasons of the kill switches and the token migrations,
+// which are not lifted by the operator resume of a pair
+func IsHaltPauseReason(reason string) bool {
+	return reason == PauseReasonMigration || strings.HasPrefix(reason, killSwitchPauseReasonPrefix)
+}
+
+// RelayerPauseReason returns the pause reason of the pairs of a suspended relayer
+func RelayerPauseReason(coinbase common.Address) string {
+	return "relayer:" + coinbase.Hex()
+}
+
+// MarketStatus holds the engine load of a pair, PendingOrders are its new orders in the order queue of the
+// engine and not matched yet. New orders are delayed
+// while the pair is throttled and rejected while it is overloaded or paused, PauseReasons are the reasons
+// the pair is paused for. A pair paused in queue mode accepts up to QueueLimit new orders, matched once it is resumed
+type MarketStatus struct {
+	PairName      string   `json:"pairName"`
+	Code          string   `json:"code"`
+	Status        string   `json:"status"`
+	PendingOrders int      `json:"pendingOrders"`
+	MatchLatency  int64    `json:"matchLatency"`
+	QueuedOrders  int      `json:"queuedOrders,omitempty"`
+	QueueLimit    int      `json:"queueLimit,omitempty"`
+	PauseReasons  []string `json:"pauseReasons,omitempty"`
+}
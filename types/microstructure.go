@@ -0,0 +1,168 @@
+package types
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/utils/math"
+)
+
+// Microstructure are the metrics derived from the order book and the recent trades of a pair.
+// Imbalance is (bids - asks) / (bids + asks) of the volume of the top levels of the book, the depth
+// changes are the changes of this volume since the previous metrics of the pair. TradeFlowImbalance is
+// (buys - sells) / (buys + sells) of the taker volume of the window and Toxicity its volume-synchronized
+// probability of informed trading (VPIN), the mean order flow imbalance of equal volume buckets
+type Microstructure struct {
+	PairName           string         `json:"pairName"`
+	BaseToken          common.Address `json:"baseToken"`
+	QuoteToken         common.Address `json:"quoteToken"`
+	Levels             int            `json:"levels"`
+	BidDepth           *big.Int       `json:"bidDepth"`
+	AskDepth           *big.Int       `json:"askDepth"`
+	BidDepthChange     *big.Int       `json:"bidDepthChange"`
+	AskDepthChange     *big.Int       `json:"askDepthChange"`
+	Imbalance          float64        `json:"imbalance"`
+	Window             int64          `json:"window"`
+	Trades             int            `json:"trades"`
+	BuyVolume          *big.Int       `json:"buyVolume"`
+	SellVolume         *big.Int       `json:"sellVolume"`
+	TradeFlowImbalance float64        `json:"tradeFlowImbalance"`
+	Toxicity           float64        `json:"toxicity"`
+	Timestamp          int64          `json:"timestamp"`
+}
+
+// NewMicrostructure computes the metrics of a pair from the levels of its book, best first, and its
+// trades of the window, oldest first. The depth changes are zero without previous metrics
+func NewMicrostructure(
+	p *Pair,
+	bids, asks []map[string]string,
+	trades []*Trade,
+	levels, buckets int,
+	window, timestamp int64,
+	previous *Microstructure,
+) *Microstructure {
+	m := &Microstructure{
+		PairName:       p.Name(),
+		BaseToken:      p.BaseTokenAddress,
+		QuoteToken:     p.QuoteTokenAddress,
+		Levels:         levels,
+		BidDepth:       BookDepth(bids, levels),
+		AskDepth:       BookDepth(asks, levels),
+		BidDepthChange: big.NewInt(0),
+		AskDepthChange: big.NewInt(0),
+		Window:         window,
+		Trades:         len(trades),
+		Timestamp:      timestamp,
+	}
+
+	if previous != nil {
+		m.BidDepthChange = math.Sub(m.BidDepth, previous.BidDepth)
+		m.AskDepthChange = math.Sub(m.AskDepth, previous.AskDepth)
+	}
+
+	m.Imbalance = ImbalanceRatio(m.BidDepth, m.AskDepth)
+	m.BuyVolume, m.SellVolume = TakerVolumes(trades)
+	m.TradeFlowImbalance = ImbalanceRatio(m.BuyVolume, m.SellVolume)
+	m.Toxicity = VPIN(trades, buckets)
+	return m
+}
+
+// BookDepth returns the volume of the first levels of a side of the book
+func BookDepth(side []map[string]string, levels int) *big.Int {
+	res := big.NewInt(0)
+	for i, l := range side {
+		if i >= levels {
+			break
+		}
+
+		res.Add(res, math.ToBigInt(l["amount"]))
+	}
+
+	return res
+}
+
+// ImbalanceRatio returns (a - b) / (a + b), zero if both are zero
+func ImbalanceRatio(a, b *big.Int) float64 {
+	total := math.Add(a, b)
+	if total.Sign() == 0 {
+		return 0
+	}
+
+	r, _ := new(big.Float).Quo(new(big.Float).SetInt(math.Sub(a, b)), new(big.Float).SetInt(total)).Float64()
+	return r
+}
+
+// TakerVolumes returns the volumes of the successful trades bought and sold by their takers
+func TakerVolumes(trades []*Trade) (*big.Int, *big.Int) {
+	buy, sell := big.NewInt(0), big.NewInt(0)
+	for _, t := range trades {
+		if t.Status == TradeStatusError || t.Amount == nil {
+			continue
+		}
+
+		if t.TakerOrderSide == BUY {
+			buy.Add(buy, t.Amount)
+		} else {
+			sell.Add(sell, t.Amount)
+		}
+	}
+
+	return buy, sell
+}
+
+// VPIN splits the volume of the trades, oldest first, in buckets of equal volume and returns the mean
+// of |buys - sells| / volume of the buckets, from 0 for a balanced flow to 1 for a one-sided flow.
+// A trade spanning two buckets is split between them, the last bucket holds the rounding remainder
+func VPIN(trades []*Trade, buckets int) float64 {
+	buy, sell := TakerVolumes(trades)
+	total := math.Add(buy, sell)
+	if buckets <= 0 || total.Sign() == 0 {
+		return 0
+	}
+
+	size := new(big.Int).Div(total, big.NewInt(int64(buckets)))
+	if size.Sign() == 0 {
+		size = total
+	}
+
+	sorted := make([]*Trade, len(trades))
+	copy(sorted, trades)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].CreatedAt.Before(sorted[j].CreatedAt) })
+
+	imbalances := big.NewInt(0)
+	bucketBuy, bucketSell := big.NewInt(0), big.NewInt(0)
+	filled := 0
+	for _, t := range sorted {
+		if t.Status == TradeStatusError || t.Amount == nil {
+			continue
+		}
+
+		remaining := new(big.Int).Set(t.Amount)
+		for remaining.Sign() > 0 {
+			part := remaining
+			room := new(big.Int).Sub(size, math.Add(bucketBuy, bucketSell))
+			last := filled == buckets-1
+			if !last && part.Cmp(room) > 0 {
+				part = room
+			}
+
+			if t.TakerOrderSide == BUY {
+				bucketBuy.Add(bucketBuy, part)
+			} else {
+				bucketSell.Add(bucketSell, part)
+			}
+
+			remaining = new(big.Int).Sub(remaining, part)
+			if !last && math.Add(bucketBuy, bucketSell).Cmp(size) == 0 {
+				imbalances.Add(imbalances, new(big.Int).Abs(math.Sub(bucketBuy, bucketSell)))
+				bucketBuy, bucketSell = big.NewInt(0), big.NewInt(0)
+				filled++
+			}
+		}
+	}
+
+	imbalances.Add(imbalances, new(big.Int).Abs(math.Sub(bucketBuy, bucketSell)))
+	r, _ := new(big.Float).Quo(new(big.Float).SetInt(imbalances), new(big.Float).SetInt(total)).Float64()
+	return r
+}
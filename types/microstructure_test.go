@@ -0,0 +1,73 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func microstructureTrade(side string, amount int64, at int64) *Trade {
+	return &Trade{
+		TakerOrderSide: side,
+		Amount:         big.NewInt(amount),
+		Status:         SUCCESS,
+		CreatedAt:      time.Unix(at, 0),
+	}
+}
+
+func TestNewMicrostructure(t *testing.T) {
+	p := &Pair{
+		BaseTokenSymbol:   "TOMO",
+		BaseTokenAddress:  common.HexToAddress("0x1"),
+		QuoteTokenSymbol:  "USDT",
+		QuoteTokenAddress: common.HexToAddress("0x2"),
+	}
+
+	bids := []map[string]string{{"pricepoint": "10", "amount": "30"}, {"pricepoint": "9", "amount": "50"}, {"pricepoint": "8", "amount": "100"}}
+	asks := []map[string]string{{"pricepoint": "11", "amount": "20"}}
+	trades := []*Trade{
+		microstructureTrade(BUY, 60, 1),
+		microstructureTrade(SELL, 20, 2),
+		microstructureTrade(BUY, 20, 3),
+		{TakerOrderSide: SELL, Amount: big.NewInt(1000), Status: TradeStatusError, CreatedAt: time.Unix(4, 0)},
+	}
+
+	m := NewMicrostructure(p, bids, asks, trades, 2, 2, 300, 10, nil)
+	assert.Equal(t, "TOMO/USDT", m.PairName)
+	assert.Equal(t, big.NewInt(80), m.BidDepth)
+	assert.Equal(t, big.NewInt(20), m.AskDepth)
+	assert.Equal(t, big.NewInt(0), m.BidDepthChange)
+	assert.Equal(t, 0.6, m.Imbalance)
+	assert.Equal(t, big.NewInt(80), m.BuyVolume)
+	assert.Equal(t, big.NewInt(20), m.SellVolume)
+	assert.Equal(t, 0.6, m.TradeFlowImbalance)
+	// buckets of 50: 50 bought, then 10 bought, 20 sold and 20 bought
+	assert.Equal(t, 0.6, m.Toxicity)
+
+	next := NewMicrostructure(p, bids[:1], asks, nil, 2, 2, 300, 20, m)
+	assert.Equal(t, int64(-50), next.BidDepthChange.Int64())
+	assert.Equal(t, int64(0), next.AskDepthChange.Int64())
+	assert.Equal(t, float64(0), next.TradeFlowImbalance)
+	assert.Equal(t, float64(0), next.Toxicity)
+}
+
+func TestVPIN(t *testing.T) {
+	// a one-sided flow is fully toxic, an alternating flow within each bucket is not
+	oneSided := []*Trade{microstructureTrade(BUY, 10, 1), microstructureTrade(BUY, 10, 2)}
+	assert.Equal(t, float64(1), VPIN(oneSided, 2))
+
+	balanced := []*Trade{
+		microstructureTrade(BUY, 5, 1),
+		microstructureTrade(SELL, 5, 2),
+		microstructureTrade(SELL, 5, 3),
+		microstructureTrade(BUY, 5, 4),
+	}
+	assert.Equal(t, float64(0), VPIN(balanced, 2))
+
+	// the trades are bucketed in time order
+	assert.Equal(t, float64(0), VPIN([]*Trade{balanced[3], balanced[1], balanced[0], balanced[2]}, 2))
+	assert.Equal(t, float64(0), VPIN(nil, 2))
+}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -17,6 +18,75 @@ const (
 	TypeLog      = "LOG"
 )
 
+// Channels the notifications are delivered over, "app" notifications are stored and listed by the
+// notifications API, "websocket" notifications are pushed to the notification channel
+const (
+	NotificationChannelApp       = "app"
+	NotificationChannelWebsocket = "websocket"
+)
+
+// DefaultNotificationChannels are the channels of the users without preference
+var DefaultNotificationChannels = []string{NotificationChannelApp, NotificationChannelWebsocket}
+
+// NotificationPreference holds the channels a user receives its notifications over
+type NotificationPreference struct {
+	Address   common.Address `json:"address" bson:"address"`
+	Channels  []string       `json:"channels" bson:"channels"`
+	UpdatedAt time.Time      `json:"updatedAt" bson:"updatedAt"`
+}
+
+// NotificationPreferenceRecord is the notification preference stored in the database
+type NotificationPreferenceRecord struct {
+	Address   string    `json:"address" bson:"address"`
+	Channels  []string  `json:"channels" bson:"channels"`
+	UpdatedAt time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// GetBSON returns the record of the notification preference
+func (p *NotificationPreference) GetBSON() (interface{}, error) {
+	return NotificationPreferenceRecord{
+		Address:   p.Address.Hex(),
+		Channels:  p.Channels,
+		UpdatedAt: p.UpdatedAt,
+	}, nil
+}
+
+// SetBSON decodes the record of the notification preference
+func (p *NotificationPreference) SetBSON(raw bson.Raw) error {
+	decoded := &NotificationPreferenceRecord{}
+	err := raw.Unmarshal(decoded)
+	if err != nil {
+		return err
+	}
+
+	p.Address = common.HexToAddress(decoded.Address)
+	p.Channels = decoded.Channels
+	p.UpdatedAt = decoded.UpdatedAt
+	return nil
+}
+
+// Validate checks the channels of the preference are known
+func (p *NotificationPreference) Validate() error {
+	for _, c := range p.Channels {
+		if c != NotificationChannelApp && c != NotificationChannelWebsocket {
+			return fmt.Errorf("Unknown notification channel %s", c)
+		}
+	}
+
+	return nil
+}
+
+// HasChannel returns true if the notifications are delivered over the channel
+func (p *NotificationPreference) HasChannel(channel string) bool {
+	for _, c := range p.Channels {
+		if c == channel {
+			return true
+		}
+	}
+
+	return false
+}
+
 //Message struct
 type Message struct {
 	MessageType string `json:"type" bson:"type"`
@@ -7,9 +7,13 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/utils"
 	"github.com/tomochain/tomox-sdk/utils/math"
 )
 
+// MaxEmptyTicks is the number of most recent intervals in which gaps are filled
+const MaxEmptyTicks = 1000
+
 // Tick is the format in which mongo aggregate pipeline returns data when queried for OHLCV data
 type Tick struct {
 	Pair          PairID    `json:"id,omitempty" bson:"_id"`
@@ -61,6 +65,151 @@ func (t *Tick) AveragePrice() *big.Int {
 	return math.Avg(t.Open, t.Close)
 }
 
+// NewEmptyTick returns a tick without trades at the timestamp, its prices are the previous close
+func NewEmptyTick(prev *Tick, timestamp int64, duration int64, unit string) *Tick {
+	return &Tick{
+		Pair:          prev.Pair,
+		Open:          prev.Close,
+		Close:         prev.Close,
+		High:          prev.Close,
+		Low:           prev.Close,
+		Volume:        big.NewInt(0),
+		VolumeByQuote: big.NewInt(0),
+		VolumeUsdt:    big.NewInt(0),
+		Count:         big.NewInt(0),
+		Timestamp:     timestamp,
+		Duration:      duration,
+		Unit:          unit,
+	}
+}
+
+// FillTickGaps returns the sorted ticks with an empty tick for every interval without trades,
+// from the first tick until end. The timestamps of the ticks and end are expressed in precision
+// (time.Second or time.Millisecond). Only the last MaxEmptyTicks intervals are filled
+func FillTickGaps(ticks []*Tick, duration int64, unit string, end int64, precision time.Duration) []*Tick {
+	step := utils.UnitToSecond(duration, unit) * int64(time.Second/precision)
+	if step <= 0 || len(ticks) == 0 {
+		return ticks
+	}
+
+	horizon := end - (MaxEmptyTicks-1)*step
+	res := make([]*Tick, 0, len(ticks))
+	fill := func(prev *Tick, until int64) {
+		next := prev.Timestamp + step
+		if next < horizon {
+			next += (horizon - next) / step * step
+		}
+
+		for ; next < until; next += step {
+			res = append(res, NewEmptyTick(prev, next, duration, unit))
+		}
+	}
+
+	for i, t := range ticks {
+		if i > 0 {
+			fill(ticks[i-1], t.Timestamp)
+		}
+
+		res = append(res, t)
+	}
+
+	fill(ticks[len(ticks)-1], end+1)
+	return res
+}
+
+// MergeTicks aggregates sorted ticks of a pair into intervals of duration and unit starting at the
+// local boundaries of loc. The timestamps of the ticks are expressed in milliseconds
+func MergeTicks(ticks []*Tick, duration int64, unit string, loc *time.Location) []*Tick {
+	res := make([]*Tick, 0)
+	var last *Tick
+
+	for _, t := range ticks {
+		start, _ := utils.GetModTimeIn(t.Timestamp/1000, duration, unit, loc)
+		if last == nil || last.Timestamp != start*1000 {
+			last = &Tick{
+				Pair:          t.Pair,
+				Open:          t.Open,
+				Close:         t.Close,
+				High:          t.High,
+				Low:           t.Low,
+				Volume:        new(big.Int),
+				VolumeByQuote: new(big.Int),
+				VolumeUsdt:    new(big.Int),
+				Count:         new(big.Int),
+				Timestamp:     start * 1000,
+				OpenTime:      t.OpenTime,
+				Duration:      duration,
+				Unit:          unit,
+			}
+
+			res = append(res, last)
+		}
+
+		last.Close = t.Close
+		last.CloseTime = t.CloseTime
+		if t.High != nil && (last.High == nil || t.High.Cmp(last.High) > 0) {
+			last.High = t.High
+		}
+
+		if t.Low != nil && (last.Low == nil || t.Low.Cmp(last.Low) < 0) {
+			last.Low = t.Low
+		}
+
+		for _, v := range [][2]*big.Int{
+			{last.Volume, t.Volume},
+			{last.VolumeByQuote, t.VolumeByQuote},
+			{last.VolumeUsdt, t.VolumeUsdt},
+			{last.Count, t.Count},
+		} {
+			if v[1] != nil {
+				v[0].Add(v[0], v[1])
+			}
+		}
+	}
+
+	return res
+}
+
+// TokenTick is a tick with its prices and volumes in whole tokens, the prices in quote tokens
+// and the USDT volume in whole USDT
+type TokenTick struct {
+	Pair          PairID `json:"pair"`
+	Open          string `json:"open"`
+	Close         string `json:"close"`
+	High          string `json:"high"`
+	Low           string `json:"low"`
+	Volume        string `json:"volume"`
+	VolumeByQuote string `json:"volumebyquote"`
+	VolumeUsdt    string `json:"volumeusdt"`
+	Count         string `json:"count"`
+	Timestamp     int64  `json:"timestamp"`
+	Duration      int64  `json:"duration"`
+	Unit          string `json:"unit"`
+}
+
+// TokenUnits returns the tick in whole tokens of the pair, usdtDecimals are the decimals of the USDT volume
+func (t *Tick) TokenUnits(p *Pair, usdtDecimals int) *TokenTick {
+	count := "0"
+	if t.Count != nil {
+		count = t.Count.String()
+	}
+
+	return &TokenTick{
+		Pair:          t.Pair,
+		Open:          math.FormatUnits(t.Open, p.QuoteTokenDecimals),
+		Close:         math.FormatUnits(t.Close, p.QuoteTokenDecimals),
+		High:          math.FormatUnits(t.High, p.QuoteTokenDecimals),
+		Low:           math.FormatUnits(t.Low, p.QuoteTokenDecimals),
+		Volume:        math.FormatUnits(t.Volume, p.BaseTokenDecimals),
+		VolumeByQuote: math.FormatUnits(t.VolumeByQuote, p.QuoteTokenDecimals),
+		VolumeUsdt:    math.FormatUnits(t.VolumeUsdt, usdtDecimals),
+		Count:         count,
+		Timestamp:     t.Timestamp,
+		Duration:      t.Duration,
+		Unit:          t.Unit,
+	}
+}
+
 // MarshalJSON returns the json encoded byte array representing the trade struct
 func (t *Tick) MarshalJSON() ([]byte, error) {
 	tick := map[string]interface{}{
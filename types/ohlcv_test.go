@@ -0,0 +1,128 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFillTickGaps(t *testing.T) {
+	ticks := []*Tick{
+		{Close: big.NewInt(10), Count: big.NewInt(2), Timestamp: 60000},
+		{Close: big.NewInt(20), Count: big.NewInt(1), Timestamp: 240000},
+	}
+
+	res := FillTickGaps(ticks, 1, "min", 360000, time.Millisecond)
+	assert.Equal(t, 6, len(res))
+
+	timestamps := []int64{}
+	for _, tick := range res {
+		timestamps = append(timestamps, tick.Timestamp)
+	}
+	assert.Equal(t, []int64{60000, 120000, 180000, 240000, 300000, 360000}, timestamps)
+
+	empty := res[1]
+	assert.Equal(t, big.NewInt(10), empty.Open)
+	assert.Equal(t, big.NewInt(10), empty.Close)
+	assert.Equal(t, big.NewInt(0), empty.Volume)
+	assert.Equal(t, big.NewInt(0), empty.Count)
+	assert.Equal(t, big.NewInt(20), res[5].Close)
+}
+
+func TestFillTickGapsHorizon(t *testing.T) {
+	ticks := []*Tick{
+		{Close: big.NewInt(10), Count: big.NewInt(1), Timestamp: 0},
+	}
+
+	end := int64(5000 * 60)
+	res := FillTickGaps(ticks, 1, "min", end, time.Second)
+	assert.Equal(t, MaxEmptyTicks+1, len(res))
+	assert.Equal(t, end-(MaxEmptyTicks-1)*60, res[1].Timestamp)
+	assert.Equal(t, end, res[len(res)-1].Timestamp)
+}
+
+func TestMergeTicksLocalDays(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Ho_Chi_Minh")
+	assert.Nil(t, err)
+
+	// hourly ticks from 2019-03-01 15:00 UTC (22:00 local) to 2019-03-01 19:00 UTC (02:00 local)
+	start := time.Date(2019, 3, 1, 15, 0, 0, 0, time.UTC).Unix() * 1000
+	ticks := []*Tick{}
+	for i := int64(0); i < 4; i++ {
+		p := big.NewInt(100 + i)
+		ticks = append(ticks, &Tick{
+			Open:      p,
+			Close:     p,
+			High:      big.NewInt(110 + i),
+			Low:       big.NewInt(90 + i),
+			Volume:    big.NewInt(1),
+			Count:     big.NewInt(2),
+			Timestamp: start + i*3600*1000,
+		})
+	}
+
+	res := MergeTicks(ticks, 1, "day", loc)
+	assert.Equal(t, 2, len(res))
+
+	midnight := time.Date(2019, 3, 2, 0, 0, 0, 0, loc).Unix() * 1000
+	assert.Equal(t, time.Date(2019, 3, 1, 0, 0, 0, 0, loc).Unix()*1000, res[0].Timestamp)
+	assert.Equal(t, midnight, res[1].Timestamp)
+
+	assert.Equal(t, big.NewInt(100), res[0].Open)
+	assert.Equal(t, big.NewInt(101), res[0].Close)
+	assert.Equal(t, big.NewInt(111), res[0].High)
+	assert.Equal(t, big.NewInt(90), res[0].Low)
+	assert.Equal(t, big.NewInt(2), res[0].Volume)
+	assert.Equal(t, big.NewInt(4), res[0].Count)
+
+	assert.Equal(t, big.NewInt(102), res[1].Open)
+	assert.Equal(t, big.NewInt(103), res[1].Close)
+	assert.Equal(t, "day", res[1].Unit)
+	assert.Equal(t, int64(1), res[1].Duration)
+}
+
+func TestTickTokenUnits(t *testing.T) {
+	p := &Pair{BaseTokenDecimals: 18, QuoteTokenDecimals: 6}
+	tick := &Tick{
+		Open:          big.NewInt(1500000),
+		Close:         big.NewInt(2000000),
+		High:          big.NewInt(2250000),
+		Low:           big.NewInt(1000001),
+		Volume:        new(big.Int).Mul(big.NewInt(25), big.NewInt(1e17)),
+		VolumeByQuote: big.NewInt(5000000),
+		VolumeUsdt:    big.NewInt(5000000),
+		Count:         big.NewInt(3),
+	}
+
+	res := tick.TokenUnits(p, 6)
+	assert.Equal(t, "1.5", res.Open)
+	assert.Equal(t, "2", res.Close)
+	assert.Equal(t, "2.25", res.High)
+	assert.Equal(t, "1.000001", res.Low)
+	assert.Equal(t, "2.5", res.Volume)
+	assert.Equal(t, "5", res.VolumeByQuote)
+	assert.Equal(t, "5", res.VolumeUsdt)
+	assert.Equal(t, "3", res.Count)
+}
+
+func TestMergeTicksOffsetWeeks(t *testing.T) {
+	loc, err := LoadTimezone("-05:30")
+	assert.Nil(t, err)
+
+	// quarter-hourly ticks around the local week boundary, the UTC weeks start on thursdays
+	boundary := time.Date(2019, 3, 7, 5, 30, 0, 0, time.UTC).Unix() * 1000
+	ticks := []*Tick{
+		{Close: big.NewInt(1), Volume: big.NewInt(1), Timestamp: boundary - 30*60*1000},
+		{Close: big.NewInt(2), Volume: big.NewInt(1), Timestamp: boundary - 15*60*1000},
+		{Close: big.NewInt(3), Volume: big.NewInt(1), Timestamp: boundary},
+	}
+
+	res := MergeTicks(ticks, 1, "week", loc)
+	assert.Equal(t, 2, len(res))
+	assert.Equal(t, boundary, res[1].Timestamp)
+	assert.Equal(t, big.NewInt(2), res[0].Close)
+	assert.Equal(t, big.NewInt(2), res[0].Volume)
+	assert.Equal(t, big.NewInt(3), res[1].Close)
+}
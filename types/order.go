@@ -22,16 +22,18 @@ const (
 	TypeMarketOrder = "MO"
 	TypeLimitOrder  = "LO"
 
-	OrderStatusOpen          = "OPEN"
-	OrderStatusPartialFilled = "PARTIAL_FILLED"
-	OrderStatusFilled        = "FILLED"
-	OrderStatusRejected      = "REJECTED"
-	OrderStatusCancelled     = "CANCELLED"
+	OrderStatusOpen           = "OPEN"
+	OrderStatusPartialFilled  = "PARTIAL_FILLED"
+	OrderStatusFilled         = "FILLED"
+	OrderStatusFilledWithDust = "FILLED_WITH_DUST"
+	OrderStatusRejected       = "REJECTED"
+	OrderStatusCancelled      = "CANCELLED"
 )
 
 // Order contains the data related to an order sent by the user
 type Order struct {
 	ID              bson.ObjectId  `json:"id" bson:"_id"`
+	PublicID        int64          `json:"publicId,omitempty" bson:"publicId"`
 	UserAddress     common.Address `json:"userAddress" bson:"userAddress"`
 	ExchangeAddress common.Address `json:"exchangeAddress" bson:"exchangeAddress"`
 	BaseToken       common.Address `json:"baseToken" bson:"baseToken"`
@@ -79,6 +81,8 @@ type OrderSpec struct {
 	DateFrom       int64
 	DateTo         int64
 	OrderHash      string
+	// Fields are the only fields of the orders read, all of them if empty
+	Fields []string
 }
 
 func (o *Order) String() string {
@@ -260,28 +264,23 @@ func (o *Order) BuyToken() common.Address {
 }
 
 func (o *Order) QuoteAmount(p *Pair) *big.Int {
-	pairMultiplier := p.PairMultiplier()
-	return math.Div(math.Mul(o.Amount, o.PricePoint), pairMultiplier)
+	return p.QuoteAmount(o.Amount, o.PricePoint)
 }
 
 // SellAmount
 // If order is a "BUY", then sellToken = quoteToken
 func (o *Order) SellAmount(p *Pair) *big.Int {
-	pairMultiplier := p.PairMultiplier()
-
 	if o.Side == BUY {
-		return math.Div(math.Mul(o.Amount, o.PricePoint), pairMultiplier)
+		return p.QuoteAmount(o.Amount, o.PricePoint)
 	} else {
 		return o.Amount
 	}
 }
 
 func (o *Order) RemainingSellAmount(p *Pair) *big.Int {
-	pairMultiplier := p.PairMultiplier()
-
 	if o.Side == BUY {
 		remainingAmount := math.Sub(o.Amount, o.FilledAmount)
-		return math.Div(math.Mul(remainingAmount, o.PricePoint), pairMultiplier)
+		return p.QuoteAmount(remainingAmount, o.PricePoint)
 	} else {
 		return math.Sub(o.Amount, o.FilledAmount)
 	}
@@ -290,10 +289,8 @@ func (o *Order) RemainingSellAmount(p *Pair) *big.Int {
 func (o *Order) RequiredSellAmount(p *Pair) *big.Int {
 	var requiredSellTokenAmount *big.Int
 
-	pairMultiplier := p.PairMultiplier()
-
 	if o.Side == BUY {
-		requiredSellTokenAmount = math.Div(math.Mul(o.Amount, o.PricePoint), pairMultiplier)
+		requiredSellTokenAmount = p.QuoteAmount(o.Amount, o.PricePoint)
 	} else {
 		requiredSellTokenAmount = o.Amount
 	}
@@ -304,10 +301,8 @@ func (o *Order) RequiredSellAmount(p *Pair) *big.Int {
 func (o *Order) TotalRequiredSellAmount(p *Pair) *big.Int {
 	var requiredSellTokenAmount *big.Int
 
-	pairMultiplier := p.PairMultiplier()
-
 	if o.Side == BUY {
-		sellAmount := math.Div(math.Mul(o.Amount, o.PricePoint), pairMultiplier)
+		sellAmount := p.QuoteAmount(o.Amount, o.PricePoint)
 		fee := math.Max(p.MakeFee, p.TakeFee)
 		requiredSellTokenAmount = math.Add(sellAmount, fee)
 	} else {
@@ -317,11 +312,11 @@ func (o *Order) TotalRequiredSellAmount(p *Pair) *big.Int {
 	return requiredSellTokenAmount
 }
 
-func (o *Order) BuyAmount(pairMultiplier *big.Int) *big.Int {
+func (o *Order) BuyAmount(p *Pair) *big.Int {
 	if o.Side == SELL {
-		return o.Amount
+		return p.QuoteAmount(o.Amount, o.PricePoint)
 	} else {
-		return math.Div(math.Mul(o.Amount, o.PricePoint), pairMultiplier)
+		return o.Amount
 	}
 }
 
@@ -387,6 +382,10 @@ func (o *Order) MarshalJSON() ([]byte, error) {
 		"key":             o.Key,
 	}
 
+	if o.PublicID != 0 {
+		order["publicId"] = strconv.FormatInt(o.PublicID, 10)
+	}
+
 	if o.FilledAmount != nil {
 		order["filledAmount"] = o.FilledAmount.String()
 	}
@@ -408,6 +407,30 @@ func (o *Order) MarshalJSON() ([]byte, error) {
 	return json.Marshal(order)
 }
 
+// orderJSONKeys are the fields of an order payload
+var orderJSONKeys = map[string]bool{
+	"id":              true,
+	"publicId":        true,
+	"pairName":        true,
+	"exchangeAddress": true,
+	"userAddress":     true,
+	"baseToken":       true,
+	"quoteToken":      true,
+	"pricepoint":      true,
+	"amount":          true,
+	"filledAmount":    true,
+	"nonce":           true,
+	"hash":            true,
+	"side":            true,
+	"type":            true,
+	"status":          true,
+	"signature":       true,
+	"createdAt":       true,
+	"updatedAt":       true,
+	"orderID":         true,
+	"key":             true,
+}
+
 // UnmarshalJSON : write custom logic to unmarshal bytes to Order
 func (o *Order) UnmarshalJSON(b []byte) error {
 	order := map[string]interface{}{}
@@ -417,10 +440,22 @@ func (o *Order) UnmarshalJSON(b []byte) error {
 		return err
 	}
 
+	if err := checkJSONKeys(order, orderJSONKeys); err != nil {
+		return err
+	}
+
 	if order["id"] != nil && bson.IsObjectIdHex(order["id"].(string)) {
 		o.ID = bson.ObjectIdHex(order["id"].(string))
 	}
 
+	if order["publicId"] != nil {
+		publicID, err := strconv.ParseInt(order["publicId"].(string), 10, 64)
+		if err != nil {
+			return err
+		}
+		o.PublicID = publicID
+	}
+
 	if order["pairName"] != nil {
 		o.PairName = order["pairName"].(string)
 	}
@@ -511,6 +546,7 @@ func (o *Order) UnmarshalJSON(b []byte) error {
 // GetBSON return bson
 func (o *Order) GetBSON() (interface{}, error) {
 	or := OrderRecord{
+		PublicID:        o.PublicID,
 		PairName:        o.PairName,
 		ExchangeAddress: o.ExchangeAddress.Hex(),
 		UserAddress:     o.UserAddress.Hex(),
@@ -556,6 +592,7 @@ func (o *Order) GetBSON() (interface{}, error) {
 func (o *Order) SetBSON(raw bson.Raw) error {
 	decoded := new(struct {
 		ID              bson.ObjectId    `json:"id,omitempty" bson:"_id"`
+		PublicID        int64            `json:"publicId" bson:"publicId"`
 		PairName        string           `json:"pairName" bson:"pairName"`
 		ExchangeAddress string           `json:"exchangeAddress" bson:"exchangeAddress"`
 		UserAddress     string           `json:"userAddress" bson:"userAddress"`
@@ -586,6 +623,7 @@ func (o *Order) SetBSON(raw bson.Raw) error {
 	}
 
 	o.ID = decoded.ID
+	o.PublicID = decoded.PublicID
 	o.PairName = decoded.PairName
 	o.ExchangeAddress = common.HexToAddress(decoded.ExchangeAddress)
 	o.UserAddress = common.HexToAddress(decoded.UserAddress)
@@ -620,11 +658,14 @@ func (o *Order) SetBSON(raw bson.Raw) error {
 	o.CreatedAt = decoded.CreatedAt
 	o.UpdatedAt = decoded.UpdatedAt
 
-	orderID, err := strconv.ParseInt(decoded.OrderID, 10, 64)
-	if err != nil {
-		logger.Error(err)
+	// the orderID is not read when the fields of the orders are selected
+	if decoded.OrderID != "" {
+		orderID, err := strconv.ParseInt(decoded.OrderID, 10, 64)
+		if err != nil {
+			logger.Error(err)
+		}
+		o.OrderID = uint64(orderID)
 	}
-	o.OrderID = uint64(orderID)
 	o.NextOrder = common.Hex2Bytes(decoded.NextOrder)
 	o.PrevOrder = common.Hex2Bytes(decoded.PrevOrder)
 	o.OrderList = common.Hex2Bytes(decoded.OrderList)
@@ -636,6 +677,7 @@ func (o *Order) SetBSON(raw bson.Raw) error {
 // OrderRecord is the object that will be saved in the database
 type OrderRecord struct {
 	ID              bson.ObjectId    `json:"id" bson:"_id"`
+	PublicID        int64            `json:"publicId,omitempty" bson:"publicId,omitempty"`
 	UserAddress     string           `json:"userAddress" bson:"userAddress"`
 	ExchangeAddress string           `json:"exchangeAddress" bson:"exchangeAddress"`
 	BaseToken       string           `json:"baseToken" bson:"baseToken"`
@@ -864,6 +906,21 @@ type updateDesc struct {
 	RemovedFields []string               `bson:"removedFields"`
 }
 
+// onlyPublicID tells if the update only assigned the public id of the document
+func (u *updateDesc) onlyPublicID() bool {
+	if u == nil || len(u.RemovedFields) > 0 || len(u.UpdatedFields) == 0 {
+		return false
+	}
+
+	for field := range u.UpdatedFields {
+		if field != "publicId" {
+			return false
+		}
+	}
+
+	return true
+}
+
 type evNamespace struct {
 	DB   string `bson:"db"`
 	Coll string `bson:"coll"`
@@ -880,6 +937,12 @@ type OrderChangeEvent struct {
 	UpdateDescription *updateDesc `bson:"updateDescription,omitempty"`
 }
 
+// IsPublicIDAssignment tells if the event is the assignment of the public id of an order,
+// which is not a change of the order itself
+func (ev *OrderChangeEvent) IsPublicIDAssignment() bool {
+	return ev.OperationType == OPERATION_TYPE_UPDATE && ev.UpdateDescription.onlyPublicID()
+}
+
 const (
 	OPERATION_TYPE_INSERT  = "insert"
 	OPERATION_TYPE_UPDATE  = "update"
@@ -65,6 +65,18 @@ func (oc *OrderCancel) String() string {
 		oc.OrderHash, oc.Hash, oc.Signature.V, oc.Signature.R, oc.Signature.S)
 }
 
+// orderCancelJSONKeys are the fields of an order cancel payload
+var orderCancelJSONKeys = map[string]bool{
+	"orderHash":       true,
+	"hash":            true,
+	"nonce":           true,
+	"status":          true,
+	"orderID":         true,
+	"userAddress":     true,
+	"exchangeAddress": true,
+	"signature":       true,
+}
+
 // UnmarshalJSON creates an OrderCancel object from a json byte string
 func (oc *OrderCancel) UnmarshalJSON(b []byte) error {
 	parsed := map[string]interface{}{}
@@ -74,6 +86,10 @@ func (oc *OrderCancel) UnmarshalJSON(b []byte) error {
 		return err
 	}
 
+	if err := checkJSONKeys(parsed, orderCancelJSONKeys); err != nil {
+		return err
+	}
+
 	if parsed["orderHash"] == nil {
 		return errors.New("Order Hash is missing")
 	}
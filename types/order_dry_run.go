@@ -0,0 +1,53 @@
+package types
+
+import "github.com/ethereum/go-ethereum/common"
+
+// Rules of the order validation pipeline
+const (
+	OrderRuleParams     = "params"
+	OrderRuleAccount    = "account"
+	OrderRuleSignature  = "signature"
+	OrderRulePair       = "pair"
+	OrderRuleBalance    = "balance"
+	OrderRuleRisk       = "risk"
+	OrderRuleCompliance = "compliance"
+)
+
+// OrderViolation is a rule of the order validation pipeline an order does not satisfy
+type OrderViolation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// OrderDryRun is the result of the validation of an order that is not sent to the engine
+type OrderDryRun struct {
+	Hash       common.Hash       `json:"hash"`
+	Valid      bool              `json:"valid"`
+	Violations []*OrderViolation `json:"violations"`
+}
+
+// NewOrderDryRun returns the valid result of the dry run of an order, until a violation is added
+func NewOrderDryRun(o *Order) *OrderDryRun {
+	return &OrderDryRun{
+		Hash:       o.Hash,
+		Valid:      true,
+		Violations: []*OrderViolation{},
+	}
+}
+
+// Add records the violation of a rule
+func (r *OrderDryRun) Add(rule string, message string) {
+	r.Valid = false
+	r.Violations = append(r.Violations, &OrderViolation{Rule: rule, Message: message})
+}
+
+// Violates returns true if the order violates a rule
+func (r *OrderDryRun) Violates(rule string) bool {
+	for _, v := range r.Violations {
+		if v.Rule == rule {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,125 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// orderStatusTransitions lists the statuses an order can move to from each status.
+// The transitions from FILLED and PARTIAL_FILLED back to a less filled status happen
+// when the engine reverts a trade. A cancelled order whose remainder is dust is marked
+// FILLED_WITH_DUST, which is final as REJECTED
+var orderStatusTransitions = map[string][]string{
+	"": {
+		OrderStatusOpen,
+		OrderStatusRejected,
+	},
+	OrderStatusOpen: {
+		OrderStatusPartialFilled,
+		OrderStatusFilled,
+		OrderStatusCancelled,
+		OrderStatusRejected,
+	},
+	OrderStatusPartialFilled: {
+		OrderStatusOpen,
+		OrderStatusFilled,
+		OrderStatusCancelled,
+		OrderStatusFilledWithDust,
+	},
+	OrderStatusFilled: {
+		OrderStatusOpen,
+		OrderStatusPartialFilled,
+	},
+	OrderStatusCancelled: {
+		OrderStatusFilledWithDust,
+	},
+	OrderStatusFilledWithDust: {},
+	OrderStatusRejected:       {},
+}
+
+// IllegalOrderTransitionError is returned when an order is moved to a status it cannot reach from its current one
+type IllegalOrderTransitionError struct {
+	From string
+	To   string
+}
+
+func (e *IllegalOrderTransitionError) Error() string {
+	return fmt.Sprintf("Illegal order status transition from %q to %q", e.From, e.To)
+}
+
+// IsValidOrderStatus returns true if the status is a known order status
+func IsValidOrderStatus(status string) bool {
+	if status == "" {
+		return false
+	}
+
+	_, ok := orderStatusTransitions[status]
+	return ok
+}
+
+// CanTransitionOrderStatus returns true if an order can move from a status to another,
+// staying in the same status is always allowed
+func CanTransitionOrderStatus(from, to string) bool {
+	if !IsValidOrderStatus(to) {
+		return false
+	}
+
+	if from == to {
+		return true
+	}
+
+	for _, s := range orderStatusTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// OrderStatusPredecessors returns the statuses from which an order can move to a status,
+// it is used to guard the status updates in the database
+func OrderStatusPredecessors(to string) []string {
+	res := []string{}
+	for from := range orderStatusTransitions {
+		if from != "" && CanTransitionOrderStatus(from, to) {
+			res = append(res, from)
+		}
+	}
+
+	return res
+}
+
+// OrderStatusFromFill returns the status of a non cancelled order with a filled amount
+func OrderStatusFromFill(filled, amount *big.Int) string {
+	if filled == nil || filled.Sign() <= 0 {
+		return OrderStatusOpen
+	}
+
+	if amount != nil && filled.Cmp(amount) >= 0 {
+		return OrderStatusFilled
+	}
+
+	return OrderStatusPartialFilled
+}
+
+// IsDustRemainder returns true if the order is partially filled and the amount left to fill is below the threshold,
+// a nil threshold disables the dust handling
+func (o *Order) IsDustRemainder(threshold *big.Int) bool {
+	if threshold == nil || o.Amount == nil || o.FilledAmount == nil || o.FilledAmount.Sign() <= 0 {
+		return false
+	}
+
+	remainder := new(big.Int).Sub(o.Amount, o.FilledAmount)
+	return remainder.Sign() > 0 && remainder.Cmp(threshold) < 0
+}
+
+// TransitionTo moves the order to a status, the order is left unchanged if the transition is illegal
+func (o *Order) TransitionTo(status string) error {
+	if !CanTransitionOrderStatus(o.Status, status) {
+		return &IllegalOrderTransitionError{From: o.Status, To: status}
+	}
+
+	o.Status = status
+	return nil
+}
@@ -0,0 +1,64 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanTransitionOrderStatus(t *testing.T) {
+	assert.True(t, CanTransitionOrderStatus("", OrderStatusOpen))
+	assert.True(t, CanTransitionOrderStatus(OrderStatusOpen, OrderStatusPartialFilled))
+	assert.True(t, CanTransitionOrderStatus(OrderStatusPartialFilled, OrderStatusFilled))
+	assert.True(t, CanTransitionOrderStatus(OrderStatusPartialFilled, OrderStatusCancelled))
+	assert.True(t, CanTransitionOrderStatus(OrderStatusFilled, OrderStatusPartialFilled))
+	assert.True(t, CanTransitionOrderStatus(OrderStatusFilled, OrderStatusFilled))
+
+	assert.False(t, CanTransitionOrderStatus(OrderStatusFilled, OrderStatusCancelled))
+	assert.False(t, CanTransitionOrderStatus(OrderStatusCancelled, OrderStatusOpen))
+	assert.False(t, CanTransitionOrderStatus(OrderStatusRejected, OrderStatusFilled))
+	assert.False(t, CanTransitionOrderStatus(OrderStatusPartialFilled, OrderStatusRejected))
+	assert.False(t, CanTransitionOrderStatus(OrderStatusOpen, "ADDED"))
+
+	assert.True(t, CanTransitionOrderStatus(OrderStatusCancelled, OrderStatusFilledWithDust))
+	assert.False(t, CanTransitionOrderStatus(OrderStatusOpen, OrderStatusFilledWithDust))
+	assert.False(t, CanTransitionOrderStatus(OrderStatusFilledWithDust, OrderStatusCancelled))
+}
+
+func TestOrderStatusPredecessors(t *testing.T) {
+	assert.ElementsMatch(t, []string{OrderStatusOpen, OrderStatusPartialFilled, OrderStatusCancelled}, OrderStatusPredecessors(OrderStatusCancelled))
+	assert.ElementsMatch(t, []string{OrderStatusOpen, OrderStatusRejected}, OrderStatusPredecessors(OrderStatusRejected))
+}
+
+func TestOrderStatusFromFill(t *testing.T) {
+	amount := big.NewInt(100)
+	assert.Equal(t, OrderStatusOpen, OrderStatusFromFill(big.NewInt(0), amount))
+	assert.Equal(t, OrderStatusPartialFilled, OrderStatusFromFill(big.NewInt(40), amount))
+	assert.Equal(t, OrderStatusFilled, OrderStatusFromFill(big.NewInt(100), amount))
+}
+
+func TestOrderTransitionTo(t *testing.T) {
+	o := &Order{Status: OrderStatusFilled}
+
+	err := o.TransitionTo(OrderStatusCancelled)
+	assert.Error(t, err)
+	assert.Equal(t, OrderStatusFilled, o.Status)
+
+	o.Status = OrderStatusOpen
+	assert.NoError(t, o.TransitionTo(OrderStatusCancelled))
+	assert.Equal(t, OrderStatusCancelled, o.Status)
+}
+
+func TestOrderIsDustRemainder(t *testing.T) {
+	o := &Order{Amount: big.NewInt(100), FilledAmount: big.NewInt(95)}
+	assert.True(t, o.IsDustRemainder(big.NewInt(10)))
+	assert.False(t, o.IsDustRemainder(big.NewInt(5)))
+	assert.False(t, o.IsDustRemainder(nil))
+
+	o.FilledAmount = big.NewInt(100)
+	assert.False(t, o.IsDustRemainder(big.NewInt(10)))
+
+	o.FilledAmount = big.NewInt(0)
+	assert.False(t, o.IsDustRemainder(big.NewInt(1000)))
+}
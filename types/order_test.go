@@ -274,3 +274,47 @@ func TestOrderBSON(t *testing.T) {
 
 // 	assert.Equal(decoded, account)
 // }
+
+func TestOrderDryRun(t *testing.T) {
+	r := NewOrderDryRun(&Order{})
+	assert.True(t, r.Valid)
+	assert.False(t, r.Violates(OrderRuleBalance))
+
+	r.Add(OrderRuleBalance, "insufficient TOMO Balance")
+	assert.False(t, r.Valid)
+	assert.True(t, r.Violates(OrderRuleBalance))
+	assert.False(t, r.Violates(OrderRuleSignature))
+	assert.Equal(t, 1, len(r.Violations))
+}
+
+func TestOrderPublicID(t *testing.T) {
+	o := &Order{
+		ID:          bson.NewObjectId(),
+		PublicID:    1234567890123456789,
+		UserAddress: common.HexToAddress("0x7a9f3cd060ab180f36c17fe6bdf9974f577d77aa"),
+		PricePoint:  big.NewInt(1000),
+		Amount:      big.NewInt(1000),
+		Nonce:       big.NewInt(1),
+		Status:      OrderStatusOpen,
+	}
+
+	encoded, err := json.Marshal(o)
+	assert.Nil(t, err)
+	assert.Contains(t, string(encoded), `"publicId":"1234567890123456789"`)
+
+	decoded := &Order{}
+	assert.Nil(t, json.Unmarshal(encoded, decoded))
+	assert.Equal(t, o.PublicID, decoded.PublicID)
+
+	data, err := bson.Marshal(o)
+	assert.Nil(t, err)
+
+	decoded = &Order{}
+	assert.Nil(t, bson.Unmarshal(data, decoded))
+	assert.Equal(t, o.PublicID, decoded.PublicID)
+
+	// the orders stored before the public ids have none
+	o.PublicID = 0
+	encoded, _ = json.Marshal(o)
+	assert.NotContains(t, string(encoded), "publicId")
+}
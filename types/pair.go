@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"sort"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -29,6 +30,7 @@ type Pair struct {
 	MakeFee            *big.Int       `json:"makeFee,omitempty" bson:"makeFee"`
 	TakeFee            *big.Int       `json:"takeFee,omitempty" bson:"takeFee"`
 	RelayerAddress     common.Address `json:"relayerAddress,omitempty" bson:"relayerAddress"`
+	UnverifiedTokens   bool           `json:"unverifiedTokens,omitempty" bson:"unverifiedTokens"`
 	CreatedAt          time.Time      `json:"-" bson:"createdAt"`
 	UpdatedAt          time.Time      `json:"-" bson:"updatedAt"`
 }
@@ -89,6 +91,7 @@ func (p *Pair) MarshalJSON() ([]byte, error) {
 		"rank":               p.Rank,
 		"active":             p.Active,
 		"listed":             p.Listed,
+		"unverifiedTokens":   p.UnverifiedTokens,
 	}
 
 	if p.MakeFee != nil {
@@ -127,6 +130,7 @@ func (p *Pair) SetBSON(raw bson.Raw) error {
 	p.Listed = decoded.Listed
 	p.Active = decoded.Active
 	p.Rank = decoded.Rank
+	p.UnverifiedTokens = decoded.UnverifiedTokens
 	p.MakeFee = makeFee
 	p.TakeFee = takeFee
 
@@ -148,6 +152,7 @@ func (p *Pair) GetBSON() (interface{}, error) {
 		Active:             p.Active,
 		Listed:             p.Listed,
 		Rank:               p.Rank,
+		UnverifiedTokens:   p.UnverifiedTokens,
 		MakeFee:            p.MakeFee.String(),
 		TakeFee:            p.TakeFee.String(),
 		CreatedAt:          p.CreatedAt,
@@ -155,27 +160,43 @@ func (p *Pair) GetBSON() (interface{}, error) {
 	}, nil
 }
 
+// The pricepoints are in quote token base units per whole base token, the price of 1.5 quote tokens
+// with 6 decimals per base token is 1500000 whatever the decimals of the base token.
+// All the price and amount conversions go through the methods below so that the pairs with
+// different base and quote token decimals are handled the same way everywhere
+
+// BaseTokenMultiplier returns the number of base units of a whole base token
 func (p *Pair) BaseTokenMultiplier() *big.Int {
-	return math.Exp(big.NewInt(10), big.NewInt(int64(p.BaseTokenDecimals)))
+	return math.Pow10(p.BaseTokenDecimals)
 }
 
+// QuoteTokenMultiplier returns the number of base units of a whole quote token
 func (p *Pair) QuoteTokenMultiplier() *big.Int {
-	return math.Exp(big.NewInt(10), big.NewInt(int64(p.QuoteTokenDecimals)))
+	return math.Pow10(p.QuoteTokenDecimals)
+}
+
+// QuoteAmount returns the amount of quote token, in base units, of an amount of base token at a pricepoint
+func (p *Pair) QuoteAmount(amount, pricepoint *big.Int) *big.Int {
+	return math.Div(math.Mul(amount, pricepoint), p.BaseTokenMultiplier())
 }
 
-func (p *Pair) PairMultiplier() *big.Int {
-	defaultMultiplier := math.Exp(big.NewInt(10), big.NewInt(18))
-	baseTokenMultiplier := math.Exp(big.NewInt(10), big.NewInt(int64(p.BaseTokenDecimals)))
+// BaseAmount returns the amount of base token, in base units, worth an amount of quote token at a pricepoint
+func (p *Pair) BaseAmount(quoteAmount, pricepoint *big.Int) *big.Int {
+	if pricepoint == nil || pricepoint.Sign() == 0 {
+		return big.NewInt(0)
+	}
 
-	return math.Mul(defaultMultiplier, baseTokenMultiplier)
+	return math.Div(math.Mul(quoteAmount, p.BaseTokenMultiplier()), pricepoint)
 }
 
-func (p *Pair) PricepointMultiplier() *big.Int {
-	baseTokenMultiplier := math.Exp(big.NewInt(10), big.NewInt(int64(p.BaseTokenDecimals)))
-	quoteTokenMultiplier := math.Exp(big.NewInt(10), big.NewInt(int64(p.QuoteTokenDecimals)))
-	defaultMultiplier := math.Exp(big.NewInt(10), big.NewInt(9))
+// Price returns the price in whole quote tokens per whole base token of a pricepoint
+func (p *Pair) Price(pricepoint *big.Int) *big.Float {
+	return math.ToUnits(pricepoint, p.QuoteTokenDecimals)
+}
 
-	return math.Div(math.Mul(baseTokenMultiplier, defaultMultiplier), quoteTokenMultiplier)
+// Pricepoint returns the pricepoint of a price in whole quote tokens per whole base token
+func (p *Pair) Pricepoint(price *big.Float) *big.Int {
+	return math.FromUnits(price, p.QuoteTokenDecimals)
 }
 
 func (p *Pair) DecimalsMultiplier() *big.Int {
@@ -205,20 +226,22 @@ func (p *Pair) EncodedTopic() string {
 	return fmt.Sprintf("0x%s", s)
 }
 
+// ParseAmount returns an amount of base token in whole tokens
 func (p *Pair) ParseAmount(a *big.Int) float64 {
-	nominator := a
-	denominator := p.BaseTokenMultiplier()
-	amount := math.DivideToFloat(nominator, denominator)
+	if a == nil {
+		return 0
+	}
 
-	return amount
+	return math.DivideToFloat(a, p.BaseTokenMultiplier())
 }
 
+// ParsePricePoint returns the price of a pricepoint in whole quote tokens per whole base token
 func (p *Pair) ParsePricePoint(pp *big.Int) float64 {
-	nominator := pp
-	denominator := math.Mul(math.Exp(big.NewInt(10), big.NewInt(18)), p.QuoteTokenMultiplier())
-	price := math.DivideToFloat(nominator, denominator)
+	if pp == nil {
+		return 0
+	}
 
-	return price
+	return math.DivideToFloat(pp, p.QuoteTokenMultiplier())
 }
 
 func (p *Pair) MinQuoteAmount() *big.Int {
@@ -243,6 +266,68 @@ func (p Pair) Validate() error {
 	)
 }
 
+// CreatePairPayload is the payload of the creation of a pair by an operator.
+// The token symbols and decimals are read from the relayer contract and the fees
+// default to the fees of the relayer. The pair is active unless Active is false
+type CreatePairPayload struct {
+	BaseTokenAddress  string `json:"baseTokenAddress"`
+	QuoteTokenAddress string `json:"quoteTokenAddress"`
+	RelayerAddress    string `json:"relayerAddress"`
+	MakeFee           string `json:"makeFee"`
+	TakeFee           string `json:"takeFee"`
+	Rank              int    `json:"rank"`
+	Listed            bool   `json:"listed"`
+	Active            *bool  `json:"active"`
+}
+
+// Validate checks the addresses and the fees of the payload
+func (p *CreatePairPayload) Validate() error {
+	for _, a := range []string{p.BaseTokenAddress, p.QuoteTokenAddress, p.RelayerAddress} {
+		if !common.IsHexAddress(a) {
+			return fmt.Errorf("Invalid address %q", a)
+		}
+	}
+
+	if p.BaseTokenAddress == p.QuoteTokenAddress {
+		return fmt.Errorf("Base token and quote token must differ")
+	}
+
+	for _, f := range []string{p.MakeFee, p.TakeFee} {
+		if f == "" {
+			continue
+		}
+
+		v, ok := new(big.Int).SetString(f, 10)
+		if !ok || v.Sign() < 0 {
+			return fmt.Errorf("Invalid fee %q", f)
+		}
+	}
+
+	return nil
+}
+
+// ToPair returns the pair of the payload, the fees are nil if they are not set
+func (p *CreatePairPayload) ToPair() *Pair {
+	pair := &Pair{
+		BaseTokenAddress:  common.HexToAddress(p.BaseTokenAddress),
+		QuoteTokenAddress: common.HexToAddress(p.QuoteTokenAddress),
+		RelayerAddress:    common.HexToAddress(p.RelayerAddress),
+		Rank:              p.Rank,
+		Listed:            p.Listed,
+		Active:            p.Active == nil || *p.Active,
+	}
+
+	if p.MakeFee != "" {
+		pair.MakeFee = math.ToBigInt(p.MakeFee)
+	}
+
+	if p.TakeFee != "" {
+		pair.TakeFee = math.ToBigInt(p.TakeFee)
+	}
+
+	return pair
+}
+
 // GetOrderBookKeys returns the orderbook price point keys for corresponding pair
 // It is used to fetch the orderbook of a pair
 func (p *Pair) GetOrderBookKeys() (sell, buy string) {
@@ -280,6 +365,7 @@ type PairRecord struct {
 	MakeFee            string    `json:"makeFee" bson:"makeFee"`
 	TakeFee            string    `json:"takeFee" bson:"takeFee"`
 	Rank               int       `json:"rank" bson:"rank"`
+	UnverifiedTokens   bool      `json:"unverifiedTokens" bson:"unverifiedTokens"`
 	CreatedAt          time.Time `json:"createdAt" bson:"createdAt"`
 	UpdatedAt          time.Time `json:"updatedAt" bson:"updatedAt"`
 }
@@ -293,6 +379,7 @@ type PairData struct {
 	CloseBaseUsd       *big.Float `json:"closeBaseUsd,omitempty" bson:"closeBaseUsd"`
 	Volume             *big.Int   `json:"volume,omitempty" bson:"volume"`
 	BaseVolume         *big.Int   `json:"baseVolume,omitempty" bson:"baseVolume"`
+	VolumeUsd          *big.Int   `json:"volumeUsd,omitempty" bson:"volumeUsd"`
 	Change             float32    `json:"change,omitempty" bson:"change"`
 	Count              *big.Int   `json:"count,omitempty" bson:"count"`
 	Timestamp          int64      `json:"timestamp,omitempty" bson:"timestamp"`
@@ -335,6 +422,9 @@ func (p *PairData) MarshalJSON() ([]byte, error) {
 	if p.BaseVolume != nil {
 		pairData["baseVolume"] = p.BaseVolume.String()
 	}
+	if p.VolumeUsd != nil {
+		pairData["volumeUsd"] = p.VolumeUsd.String()
+	}
 	if p.Close != nil {
 		pairData["close"] = p.Close.String()
 	}
@@ -379,6 +469,20 @@ func (p *PairData) MarshalJSON() ([]byte, error) {
 	return bytes, err
 }
 
+// SortPairDataByVolumeUsd sorts the pairs by their volume in USDT, the largest first. The volumes
+// of the pairs are in the units of their own quote token and can't be compared, a pair whose volume
+// could not be converted to USDT is ranked after the others
+func SortPairDataByVolumeUsd(data []*PairData) {
+	sort.SliceStable(data, func(i, j int) bool {
+		a, b := data[i].VolumeUsd, data[j].VolumeUsd
+		if b == nil || b.Sign() == 0 {
+			return a != nil && a.Sign() > 0
+		}
+
+		return a != nil && a.Cmp(b) > 0
+	})
+}
+
 func (p *PairData) AddressCode() string {
 	code := p.Pair.BaseToken.Hex() + "::" + p.Pair.QuoteToken.Hex()
 	return code
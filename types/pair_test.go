@@ -45,3 +45,122 @@ func TestPairBSON(t *testing.T) {
 
 	ComparePair(t, pair, decoded)
 }
+
+func TestCreatePairPayload(t *testing.T) {
+	p := &CreatePairPayload{
+		BaseTokenAddress:  "0xcf7389dc6c63637598402907d5431160ec8972a5",
+		QuoteTokenAddress: "0x7a9f3cd060ab180f36c17fe6bdf9974f577d77aa",
+		RelayerAddress:    "0x0000000000000000000000000000000000000001",
+		TakeFee:           "20",
+		Rank:              2,
+	}
+
+	assert.Nil(t, p.Validate())
+
+	pair := p.ToPair()
+	assert.True(t, pair.Active)
+	assert.Nil(t, pair.MakeFee)
+	assert.Equal(t, big.NewInt(20), pair.TakeFee)
+	assert.Equal(t, 2, pair.Rank)
+
+	inactive := false
+	p.Active = &inactive
+	assert.False(t, p.ToPair().Active)
+
+	p.MakeFee = "-1"
+	assert.NotNil(t, p.Validate())
+
+	p.MakeFee = ""
+	p.QuoteTokenAddress = p.BaseTokenAddress
+	assert.NotNil(t, p.Validate())
+
+	p.RelayerAddress = "0x1"
+	assert.NotNil(t, p.Validate())
+}
+
+func TestPairDecimalNormalization(t *testing.T) {
+	units := func(s string) *big.Int {
+		n, _ := new(big.Int).SetString(s, 10)
+		return n
+	}
+
+	cases := []struct {
+		name        string
+		base, quote int
+		pricepoint  *big.Int
+		amount      *big.Int
+		quoteAmount *big.Int
+		price       float64
+	}{
+		// 2 base tokens with 18 decimals at 1.5 quote tokens with 6 decimals
+		{"18/6", 18, 6, units("1500000"), units("2000000000000000000"), units("3000000"), 1.5},
+		// 2 base tokens with 6 decimals at 1.5 quote tokens with 18 decimals
+		{"6/18", 6, 18, units("1500000000000000000"), units("2000000"), units("3000000000000000000"), 1.5},
+		{"18/18", 18, 18, units("1500000000000000000"), units("2000000000000000000"), units("3000000000000000000"), 1.5},
+		{"8/2", 8, 2, units("150"), units("200000000"), units("300"), 1.5},
+		// more decimals than fit in an int64 multiplier
+		{"24/20", 24, 20, units("150000000000000000000"), units("2000000000000000000000000"), units("300000000000000000000"), 1.5},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p := &Pair{BaseTokenDecimals: c.base, QuoteTokenDecimals: c.quote}
+
+			assert.Equal(t, c.quoteAmount, p.QuoteAmount(c.amount, c.pricepoint))
+			assert.Equal(t, c.amount, p.BaseAmount(c.quoteAmount, c.pricepoint))
+			assert.Equal(t, c.price, p.ParsePricePoint(c.pricepoint))
+			assert.Equal(t, 2.0, p.ParseAmount(c.amount))
+			assert.Equal(t, c.pricepoint, p.Pricepoint(big.NewFloat(c.price)))
+
+			price, _ := p.Price(c.pricepoint).Float64()
+			assert.Equal(t, c.price, price)
+
+			buy := &Order{Side: BUY, Amount: c.amount, FilledAmount: big.NewInt(0), PricePoint: c.pricepoint}
+			sell := &Order{Side: SELL, Amount: c.amount, FilledAmount: big.NewInt(0), PricePoint: c.pricepoint}
+			assert.Equal(t, c.quoteAmount, buy.QuoteAmount(p))
+			assert.Equal(t, c.quoteAmount, buy.SellAmount(p))
+			assert.Equal(t, c.amount, sell.SellAmount(p))
+			assert.Equal(t, c.amount, buy.BuyAmount(p))
+			assert.Equal(t, c.quoteAmount, sell.BuyAmount(p))
+
+			trade := &Trade{Amount: c.amount, PricePoint: c.pricepoint}
+			assert.Equal(t, c.quoteAmount, trade.QuoteAmount(p))
+		})
+	}
+}
+
+func TestPairDataPrices(t *testing.T) {
+	p := &Pair{BaseTokenSymbol: "TOMO", QuoteTokenSymbol: "USDT", BaseTokenDecimals: 18, QuoteTokenDecimals: 6}
+	close, _ := new(big.Int).SetString("1250000", 10)
+	volume, _ := new(big.Int).SetString("3000000000000000000", 10)
+
+	data := &PairData{
+		Pair:       PairID{PairName: "TOMO/USDT"},
+		Close:      close,
+		Volume:     volume,
+		Count:      big.NewInt(1),
+		OrderCount: big.NewInt(1),
+	}
+	res := data.ToSimplifiedAPIData(p)
+
+	assert.Equal(t, 1.25, res.LastPrice)
+	assert.Equal(t, 3.0, res.Volume)
+}
+
+func TestSortPairDataByVolumeUsd(t *testing.T) {
+	data := []*PairData{
+		{Pair: PairID{PairName: "AAA/BTC"}, Volume: big.NewInt(5), VolumeUsd: big.NewInt(0)},
+		{Pair: PairID{PairName: "TOMO/USDT"}, Volume: big.NewInt(1000), VolumeUsd: big.NewInt(1000)},
+		{Pair: PairID{PairName: "ETH/TOMO"}, Volume: big.NewInt(10)},
+		{Pair: PairID{PairName: "BTC/USDT"}, Volume: big.NewInt(200), VolumeUsd: big.NewInt(5000)},
+	}
+
+	SortPairDataByVolumeUsd(data)
+
+	names := []string{}
+	for _, d := range data {
+		names = append(names, d.Pair.PairName)
+	}
+
+	assert.Equal(t, []string{"BTC/USDT", "TOMO/USDT", "AAA/BTC", "ETH/TOMO"}, names)
+}
@@ -0,0 +1,98 @@
+package types
+
+import (
+	"encoding/json"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/utils/math"
+)
+
+// Kinds of the transactions sent by the nonce manager
+const (
+	PendingTxSend   = "SEND"
+	PendingTxCancel = "CANCEL"
+	PendingTxFiller = "FILLER"
+)
+
+// DefaultPriceBump is the minimum increase in percent of the gas price of a replacement transaction
+// accepted by the transaction pools of the nodes
+const DefaultPriceBump = 10
+
+// PendingTx is a transaction sent by the SDK and not mined yet. A replacement keeps the nonce with a higher gas
+// price, the hashes of all the versions are kept as any of them may be mined. A cancel replaces the transaction
+// with an empty transfer to its sender, a filler takes a nonce left unused so that the next ones are mined
+type PendingTx struct {
+	From     common.Address  `json:"from"`
+	Nonce    uint64          `json:"nonce"`
+	Kind     string          `json:"kind"`
+	To       *common.Address `json:"to"`
+	Value    *big.Int        `json:"value"`
+	Gas      uint64          `json:"gas"`
+	GasPrice *big.Int        `json:"gasPrice"`
+	Data     []byte          `json:"-"`
+	Hashes   []common.Hash   `json:"hashes"`
+	SentAt   time.Time       `json:"sentAt"`
+	FirstAt  time.Time       `json:"firstAt"`
+}
+
+// Hash returns the hash of the last version of the transaction
+func (tx *PendingTx) Hash() common.Hash {
+	if len(tx.Hashes) == 0 {
+		return common.Hash{}
+	}
+
+	return tx.Hashes[len(tx.Hashes)-1]
+}
+
+// Replacements returns the number of times the transaction was replaced
+func (tx *PendingTx) Replacements() int {
+	if len(tx.Hashes) == 0 {
+		return 0
+	}
+
+	return len(tx.Hashes) - 1
+}
+
+// BumpedGasPrice returns the gas price of a replacement of the transaction, raised by bump percent and at
+// least the suggested price
+func (tx *PendingTx) BumpedGasPrice(bump int, suggested *big.Int) *big.Int {
+	if bump < DefaultPriceBump {
+		bump = DefaultPriceBump
+	}
+
+	price := math.Div(math.Mul(tx.GasPrice, big.NewInt(int64(100+bump))), big.NewInt(100))
+	if price.Cmp(tx.GasPrice) <= 0 {
+		price = math.Add(tx.GasPrice, big.NewInt(1))
+	}
+
+	if suggested != nil && suggested.Cmp(price) > 0 {
+		return new(big.Int).Set(suggested)
+	}
+
+	return price
+}
+
+// MarshalJSON returns the json encoded byte array representing the pending transaction
+func (tx *PendingTx) MarshalJSON() ([]byte, error) {
+	res := map[string]interface{}{
+		"from":         tx.From.Hex(),
+		"nonce":        tx.Nonce,
+		"kind":         tx.Kind,
+		"value":        tx.Value.String(),
+		"gas":          tx.Gas,
+		"gasPrice":     tx.GasPrice.String(),
+		"hash":         tx.Hash().Hex(),
+		"hashes":       tx.Hashes,
+		"replacements": tx.Replacements(),
+		"sentAt":       tx.SentAt,
+		"firstAt":      tx.FirstAt,
+	}
+
+	if tx.To != nil {
+		res["to"] = tx.To.Hex()
+	}
+
+	return json.Marshal(res)
+}
@@ -0,0 +1,38 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPendingTxReplacements(t *testing.T) {
+	tx := &PendingTx{GasPrice: big.NewInt(100)}
+	assert.Equal(t, common.Hash{}, tx.Hash())
+	assert.Equal(t, 0, tx.Replacements())
+
+	tx.Hashes = []common.Hash{common.HexToHash("0x1")}
+	assert.Equal(t, common.HexToHash("0x1"), tx.Hash())
+	assert.Equal(t, 0, tx.Replacements())
+
+	tx.Hashes = append(tx.Hashes, common.HexToHash("0x2"))
+	assert.Equal(t, common.HexToHash("0x2"), tx.Hash())
+	assert.Equal(t, 1, tx.Replacements())
+}
+
+func TestPendingTxBumpedGasPrice(t *testing.T) {
+	tx := &PendingTx{GasPrice: big.NewInt(100)}
+
+	// the bump is at least the one accepted by the transaction pools
+	assert.Equal(t, big.NewInt(110), tx.BumpedGasPrice(0, nil))
+	assert.Equal(t, big.NewInt(125), tx.BumpedGasPrice(25, big.NewInt(120)))
+
+	// the suggested price is taken when higher
+	assert.Equal(t, big.NewInt(200), tx.BumpedGasPrice(10, big.NewInt(200)))
+
+	// a price too low to be bumped by a percentage is raised by one
+	tx.GasPrice = big.NewInt(1)
+	assert.Equal(t, big.NewInt(2), tx.BumpedGasPrice(10, nil))
+}
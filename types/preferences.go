@@ -0,0 +1,132 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/tomochain/tomox-sdk/errors"
+)
+
+// Amount units of the display preferences
+const (
+	// AmountUnitsBase displays the prices and amounts as integers in token base units, the default
+	AmountUnitsBase = "base"
+	// AmountUnitsToken displays the prices and amounts as decimals in whole tokens
+	AmountUnitsToken = "token"
+)
+
+// Events of the preferences channel
+const (
+	GetPreferences SubscriptionEvent = "GET_PREFERENCES"
+	SetPreferences SubscriptionEvent = "SET_PREFERENCES"
+)
+
+// utcOffsetPattern matches the UTC offsets "+07:00", "-0530", "+7" or "UTC+07:00"
+var utcOffsetPattern = regexp.MustCompile(`^(?:UTC)?([+-])(\d{1,2})(?::?(\d{2}))?$`)
+
+// Preferences are the display preferences of a websocket connection or of a REST call.
+// Timezone is an IANA timezone name or a UTC offset, the day, week and month candles start
+// at its local midnight. Units is the representation of the prices and amounts
+type Preferences struct {
+	Timezone string `json:"timezone"`
+	Units    string `json:"units"`
+
+	location *time.Location
+}
+
+// NewPreferences validates the timezone and the units of the display preferences,
+// empty values select UTC and base units
+func NewPreferences(timezone, units string) (*Preferences, error) {
+	if timezone == "" {
+		timezone = "UTC"
+	}
+
+	loc, err := LoadTimezone(timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	switch units {
+	case "":
+		units = AmountUnitsBase
+	case AmountUnitsBase, AmountUnitsToken:
+	default:
+		return nil, errors.Errorf("Invalid units %s", units)
+	}
+
+	return &Preferences{Timezone: loc.String(), Units: units, location: loc}, nil
+}
+
+// LoadTimezone returns the location of an IANA timezone name or of a UTC offset. The offsets
+// are multiples of 15 minutes up to 14 hours, like the offsets of the IANA timezones
+func LoadTimezone(timezone string) (*time.Location, error) {
+	if m := utcOffsetPattern.FindStringSubmatch(timezone); m != nil {
+		hours, _ := strconv.Atoi(m[2])
+		minutes := 0
+		if m[3] != "" {
+			minutes, _ = strconv.Atoi(m[3])
+		}
+
+		if hours > 14 || minutes >= 60 || minutes%15 != 0 || hours == 14 && minutes > 0 {
+			return nil, errors.Errorf("Invalid timezone %s", timezone)
+		}
+
+		offset := hours*3600 + minutes*60
+		if m[1] == "-" {
+			offset = -offset
+		}
+
+		if offset == 0 {
+			return time.UTC, nil
+		}
+
+		return time.FixedZone(fmt.Sprintf("UTC%s%02d:%02d", m[1], hours, minutes), offset), nil
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil || timezone == "Local" {
+		return nil, errors.Errorf("Invalid timezone %s", timezone)
+	}
+
+	return loc, nil
+}
+
+// DefaultPreferences returns the preferences of the clients which did not set any
+func DefaultPreferences() *Preferences {
+	return &Preferences{Timezone: "UTC", Units: AmountUnitsBase, location: time.UTC}
+}
+
+// Location returns the location of the timezone
+func (p *Preferences) Location() *time.Location {
+	if p == nil || p.location == nil {
+		return time.UTC
+	}
+
+	return p.location
+}
+
+// WithLocation returns a copy of the preferences with another timezone
+func (p *Preferences) WithLocation(loc *time.Location) *Preferences {
+	units := AmountUnitsBase
+	if p != nil {
+		units = p.Units
+	}
+
+	return &Preferences{Timezone: loc.String(), Units: units, location: loc}
+}
+
+// TokenUnits returns true if the amounts are displayed in whole tokens
+func (p *Preferences) TokenUnits() bool {
+	return p != nil && p.Units == AmountUnitsToken
+}
+
+// Key identifies the preferences, it is empty for the default preferences
+func (p *Preferences) Key() string {
+	if p.Location() == time.UTC && !p.TokenUnits() {
+		return ""
+	}
+
+	return p.Location().String() + "::" + p.Units
+}
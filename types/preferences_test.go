@@ -0,0 +1,70 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPreferences(t *testing.T) {
+	p, err := NewPreferences("", "")
+	assert.Nil(t, err)
+	assert.Equal(t, time.UTC, p.Location())
+	assert.False(t, p.TokenUnits())
+	assert.Equal(t, "", p.Key())
+	assert.Equal(t, "", DefaultPreferences().Key())
+
+	p, err = NewPreferences("Asia/Singapore", AmountUnitsToken)
+	assert.Nil(t, err)
+	assert.Equal(t, "Asia/Singapore", p.Location().String())
+	assert.True(t, p.TokenUnits())
+	assert.Equal(t, "Asia/Singapore::token", p.Key())
+
+	_, err = NewPreferences("Mars/Olympus", "")
+	assert.NotNil(t, err)
+
+	_, err = NewPreferences("Local", "")
+	assert.NotNil(t, err)
+
+	_, err = NewPreferences("UTC", "satoshi")
+	assert.NotNil(t, err)
+}
+
+func TestLoadTimezone(t *testing.T) {
+	cases := map[string]int{
+		"+07:00":    7 * 3600,
+		"+7":        7 * 3600,
+		"-0530":     -(5*3600 + 30*60),
+		"UTC+05:45": 5*3600 + 45*60,
+		"+14:00":    14 * 3600,
+	}
+
+	for tz, offset := range cases {
+		loc, err := LoadTimezone(tz)
+		assert.Nil(t, err, tz)
+		_, o := time.Unix(0, 0).In(loc).Zone()
+		assert.Equal(t, offset, o, tz)
+	}
+
+	loc, err := LoadTimezone("+00:00")
+	assert.Nil(t, err)
+	assert.Equal(t, time.UTC, loc)
+
+	for _, tz := range []string{"+15", "+07:10", "+14:30", "+07:60", "7"} {
+		_, err := LoadTimezone(tz)
+		assert.NotNil(t, err, tz)
+	}
+
+	p, err := NewPreferences("+7", "")
+	assert.Nil(t, err)
+	assert.Equal(t, "UTC+07:00", p.Timezone)
+	assert.Equal(t, "UTC+07:00::base", p.Key())
+
+	// the canonical name of an offset is a valid timezone
+	_, err = NewPreferences(p.Timezone, "")
+	assert.Nil(t, err)
+
+	p = DefaultPreferences().WithLocation(loc)
+	assert.Equal(t, "", p.Key())
+}
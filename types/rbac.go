@@ -0,0 +1,80 @@
+package types
+
+import (
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+// Roles of the admin and operator APIs, each role includes the permissions of the previous one
+const (
+	RoleViewer   = "viewer"
+	RoleOperator = "operator"
+	RoleAdmin    = "admin"
+)
+
+// Methods used to authenticate an identity
+const (
+	AuthMethodAPIKey    = "api_key"
+	AuthMethodSSO       = "sso"
+	AuthMethodSignature = "signature"
+)
+
+var roleLevels = map[string]int{
+	RoleViewer:   1,
+	RoleOperator: 2,
+	RoleAdmin:    3,
+}
+
+// IsValidRole returns true if the role is known
+func IsValidRole(role string) bool {
+	_, ok := roleLevels[role]
+	return ok
+}
+
+// HasRole returns true if the role grants the permissions of the required role
+func HasRole(role, required string) bool {
+	level, ok := roleLevels[role]
+	if !ok {
+		return false
+	}
+
+	return level >= roleLevels[required]
+}
+
+// Identity is the caller of an admin or operator API
+type Identity struct {
+	Name   string `json:"name"`
+	Role   string `json:"role"`
+	Method string `json:"method"`
+}
+
+// AuditLog records an action performed on the admin and operator APIs
+type AuditLog struct {
+	ID         bson.ObjectId `json:"id" bson:"_id"`
+	Identity   string        `json:"identity" bson:"identity"`
+	Role       string        `json:"role" bson:"role"`
+	Method     string        `json:"method" bson:"method"`
+	Action     string        `json:"action" bson:"action"`
+	HTTPMethod string        `json:"httpMethod" bson:"httpMethod"`
+	Path       string        `json:"path" bson:"path"`
+	RemoteAddr string        `json:"remoteAddr" bson:"remoteAddr"`
+	Status     int           `json:"status" bson:"status"`
+	CreatedAt  time.Time     `json:"createdAt" bson:"createdAt"`
+}
+
+// BlockedKey is an IP or an address locked out after too many authentication failures
+type BlockedKey struct {
+	Key         string    `json:"key"`
+	Failures    int       `json:"failures"`
+	LockedUntil time.Time `json:"lockedUntil"`
+}
+
+// AuthGuardStatus holds the locked out keys and the authentication failure metrics
+type AuthGuardStatus struct {
+	Blocked       []*BlockedKey `json:"blocked"`
+	Tracked       int           `json:"tracked"`
+	TotalFailures uint64        `json:"totalFailures"`
+	TotalLockouts uint64        `json:"totalLockouts"`
+	TotalRejected uint64        `json:"totalRejected"`
+}
@@ -0,0 +1,120 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tomochain/tomox-sdk/utils/math"
+)
+
+// RebateBasisPoints is the denominator of the maker fee rates of the rebate program
+const RebateBasisPoints = 10000
+
+// RebateVolumeDays is the number of days before the day of a trade whose quote volume on the pair gives the
+// tier of its maker
+const RebateVolumeDays = 30
+
+// RebateTier is a tier of the maker fee rebate program of a pair: the makers who traded at least MinVolume
+// of quote token on the pair during the last days pay MakeFee basis points of the quote amount of their
+// trades, a negative rate pays them a share of the quote amount
+type RebateTier struct {
+	MinVolume *big.Int `json:"minVolume"`
+	MakeFee   int64    `json:"makeFee"`
+}
+
+// RebateSchedule maps a pair name to its rebate tiers, by increasing minimum volume
+type RebateSchedule map[string][]*RebateTier
+
+// NewRebateSchedule parses the tiers of the pairs, a comma separated list of minVolume:makeFee per pair name
+func NewRebateSchedule(config map[string]string) (RebateSchedule, error) {
+	s := RebateSchedule{}
+	for pair, list := range config {
+		tiers, err := ParseRebateTiers(list)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", pair, err)
+		}
+
+		s[pair] = tiers
+	}
+
+	return s, nil
+}
+
+// ParseRebateTiers parses a comma separated list of minVolume:makeFee, the rates in basis points between
+// -10000 and 10000. The tiers are returned by increasing minimum volume
+func ParseRebateTiers(list string) ([]*RebateTier, error) {
+	tiers := []*RebateTier{}
+	for _, v := range strings.Split(list, ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+
+		parts := strings.Split(v, ":")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("%s: must be minVolume:makeFee", v)
+		}
+
+		volume, ok := new(big.Int).SetString(parts[0], 10)
+		if !ok || volume.Sign() < 0 {
+			return nil, fmt.Errorf("%s: minimum volume must be a non negative integer", v)
+		}
+
+		rate, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil || rate < -RebateBasisPoints || rate > RebateBasisPoints {
+			return nil, fmt.Errorf("%s: maker fee must be between -%d and %d basis points", v, RebateBasisPoints, RebateBasisPoints)
+		}
+
+		for _, t := range tiers {
+			if t.MinVolume.Cmp(volume) == 0 {
+				return nil, fmt.Errorf("%s: duplicate minimum volume", v)
+			}
+		}
+
+		tiers = append(tiers, &RebateTier{MinVolume: volume, MakeFee: rate})
+	}
+
+	if len(tiers) == 0 {
+		return nil, fmt.Errorf("no rebate tier")
+	}
+
+	sort.Slice(tiers, func(i, j int) bool { return tiers[i].MinVolume.Cmp(tiers[j].MinVolume) < 0 })
+	return tiers, nil
+}
+
+// Tier returns the highest tier of a pair reached by a volume, nil if the pair has no rebate program or the
+// volume is below its first tier
+func (s RebateSchedule) Tier(pairName string, volume *big.Int) *RebateTier {
+	var tier *RebateTier
+	for _, t := range s[pairName] {
+		if volume == nil || volume.Cmp(t.MinVolume) < 0 {
+			break
+		}
+
+		tier = t
+	}
+
+	return tier
+}
+
+// Rebate returns the amount of quote token owed back to the maker of a trade: the fee charged by the
+// exchange contract minus the fee of the tier, more than the fee charged when the rate of the tier is
+// negative. The fee of the tier is rounded up when it is charged and toward zero when it is paid, so that a
+// rebate is never overpaid. A tier charging more than the contract gives no rebate
+func (t *RebateTier) Rebate(p *Pair, trade *Trade) *big.Int {
+	quoteAmount := p.QuoteAmount(trade.Amount, trade.PricePoint)
+	tierFee, rem := new(big.Int).QuoRem(math.Mul(quoteAmount, big.NewInt(t.MakeFee)), big.NewInt(RebateBasisPoints), new(big.Int))
+	if rem.Sign() > 0 {
+		tierFee.Add(tierFee, big.NewInt(1))
+	}
+
+	rebate := math.Sub(fee(trade.MakeFee), tierFee)
+	if rebate.Sign() < 0 {
+		return big.NewInt(0)
+	}
+
+	return rebate
+}
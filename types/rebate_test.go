@@ -0,0 +1,103 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRebateTiers(t *testing.T) {
+	tiers, err := ParseRebateTiers("1000:-2, 0:5,500:0")
+	assert.NoError(t, err)
+	assert.Len(t, tiers, 3)
+	assert.Equal(t, int64(5), tiers[0].MakeFee)
+	assert.Equal(t, int64(0), tiers[1].MakeFee)
+	assert.Equal(t, int64(-2), tiers[2].MakeFee)
+
+	for _, list := range []string{"", "1000", "-1:2", "1000:x", "1000:10001", "0:1,0:2"} {
+		_, err := ParseRebateTiers(list)
+		assert.Error(t, err, list)
+	}
+
+	s, err := NewRebateSchedule(map[string]string{"TOMO/USDT": "1000:-2,0:5"})
+	assert.NoError(t, err)
+	assert.Nil(t, s.Tier("BTC/USDT", big.NewInt(5000)))
+	assert.Equal(t, int64(5), s.Tier("TOMO/USDT", big.NewInt(999)).MakeFee)
+	assert.Equal(t, int64(-2), s.Tier("TOMO/USDT", big.NewInt(1000)).MakeFee)
+
+	s, _ = NewRebateSchedule(map[string]string{"TOMO/USDT": "1000:-2"})
+	assert.Nil(t, s.Tier("TOMO/USDT", big.NewInt(999)))
+}
+
+func TestRebateTierRebate(t *testing.T) {
+	p := &Pair{BaseTokenDecimals: 18, QuoteTokenDecimals: 18}
+
+	// 2 base tokens at 0.5, 1e18 of quote token
+	tr := &Trade{Amount: big.NewInt(2e18), PricePoint: big.NewInt(5e17), MakeFee: big.NewInt(1e15)}
+
+	// the tier pays 2 bps of the quote amount on top of the fee charged
+	tier := &RebateTier{MakeFee: -2}
+	assert.Equal(t, big.NewInt(1e15+2e14), tier.Rebate(p, tr))
+
+	// the tier charges 5 bps, half of the fee charged is owed back
+	tier = &RebateTier{MakeFee: 5}
+	assert.Equal(t, big.NewInt(5e14), tier.Rebate(p, tr))
+
+	// a tier charging more than the contract gives no rebate
+	tier = &RebateTier{MakeFee: 20}
+	assert.Equal(t, big.NewInt(0), tier.Rebate(p, tr))
+
+	// the fee paid by a tier is rounded toward zero
+	tr = &Trade{Amount: big.NewInt(1e18), PricePoint: big.NewInt(4999), MakeFee: big.NewInt(0)}
+	tier = &RebateTier{MakeFee: -1}
+	assert.Equal(t, big.NewInt(0), tier.Rebate(p, tr))
+
+	// the fee charged by a tier is rounded up, 0.4999 of quote base unit is charged as 1
+	tr = &Trade{Amount: big.NewInt(1e18), PricePoint: big.NewInt(4999), MakeFee: big.NewInt(1)}
+	tier = &RebateTier{MakeFee: 1}
+	assert.Equal(t, int64(0), tier.Rebate(p, tr).Int64())
+
+	tr = &Trade{Amount: big.NewInt(1e18), PricePoint: big.NewInt(10001), MakeFee: big.NewInt(3)}
+	assert.Equal(t, int64(1), tier.Rebate(p, tr).Int64())
+}
+
+func TestDailyAggregatesMakerRebates(t *testing.T) {
+	maker := common.HexToAddress("0x1")
+	taker := common.HexToAddress("0x2")
+	p := &Pair{
+		BaseTokenSymbol:    "TOMO",
+		BaseTokenDecimals:  18,
+		QuoteTokenSymbol:   "USDT",
+		QuoteTokenAddress:  common.HexToAddress("0x4"),
+		QuoteTokenDecimals: 18,
+	}
+
+	tr := &Trade{
+		Maker:      maker,
+		Taker:      taker,
+		Amount:     big.NewInt(1e18),
+		PricePoint: big.NewInt(1e18),
+		MakeFee:    big.NewInt(1e14),
+		TakeFee:    big.NewInt(2e14),
+		Status:     TradeStatusSuccess,
+	}
+
+	a := NewDailyAggregates(tr.CreatedAt)
+	a.AddPairTrades(p, []*Trade{tr}, map[common.Address]*RebateTier{maker: {MakeFee: -1}})
+
+	assert.Equal(t, big.NewInt(2e14), a.Pairs[0].MakerRebates)
+	assert.Equal(t, big.NewInt(2e14), a.Fees[0].MakerRebates)
+	for _, v := range a.Users {
+		if v.Address == maker {
+			assert.Equal(t, big.NewInt(2e14), v.Rebates)
+		} else {
+			assert.Equal(t, big.NewInt(0), v.Rebates)
+		}
+	}
+
+	b, err := a.Fees[0].MarshalJSON()
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `"earnings":"100000000000000"`)
+}
@@ -22,6 +22,7 @@ type Relayer struct {
 	Name       string         `json:"name" bson:"name"`
 	Resign     bool           `json:"resign" bson:"resign"`
 	LockTime   int            `json:"lockTime" bson:"lockTime"`
+	Status     string         `json:"status" bson:"status"`
 	MakeFee    *big.Int       `json:"makeFee,omitempty" bson:"makeFee,omitempty"`
 	TakeFee    *big.Int       `json:"takeFee,omitempty" bson:"makeFee,omitempty"`
 	LendingFee *big.Int       `json:"lendingFee,omitempty" bson:"lendingFee,omitempty"`
@@ -39,6 +40,7 @@ func (a *Relayer) GetBSON() (interface{}, error) {
 		Name:      a.Name,
 		Resign:    a.Resign,
 		LockTime:  a.LockTime,
+		Status:    a.Status,
 		Address:   a.Address.Hex(),
 		CreatedAt: a.CreatedAt,
 		UpdatedAt: a.UpdatedAt,
@@ -83,6 +85,7 @@ func (a *Relayer) SetBSON(raw bson.Raw) error {
 	a.Name = decoded.Name
 	a.Resign = decoded.Resign
 	a.LockTime = decoded.LockTime
+	a.Status = decoded.Status
 	a.CreatedAt = decoded.CreatedAt
 	a.UpdatedAt = decoded.UpdatedAt
 	if decoded.MakeFee != "" {
@@ -109,6 +112,7 @@ func (a *Relayer) MarshalJSON() ([]byte, error) {
 		"name":      a.Name,
 		"resign":    a.Resign,
 		"lockTime":  a.LockTime,
+		"status":    a.Status,
 		"rid":       a.RID,
 		"owner":     a.Owner.Hex(),
 		"deposit":   a.Deposit.String(),
@@ -174,6 +178,10 @@ func (a *Relayer) UnmarshalJSON(b []byte) error {
 		a.LockTime = relayer["lockTime"].(int)
 	}
 
+	if relayer["status"] != nil {
+		a.Status = relayer["status"].(string)
+	}
+
 	if relayer["makeFee"] != nil {
 		a.MakeFee = math.ToBigInt(relayer["makeFee"].(string))
 	}
@@ -207,6 +215,7 @@ type RelayerRecord struct {
 	Name       string        `json:"name" bson:"name"`
 	Resign     bool          `json:"resign" bson:"resign"`
 	LockTime   int           `json:"lockTime" bson:"lockTime"`
+	Status     string        `json:"status" bson:"status"`
 	MakeFee    string        `json:"makeFee,omitempty" bson:"makeFee,omitempty"`
 	TakeFee    string        `json:"takeFee,omitempty" bson:"takeFee,omitempty"`
 	LendingFee string        `json:"lendingFee,omitempty" bson:"lendingFee,omitempty"`
@@ -238,3 +247,23 @@ func (a *RelayerBSONUpdate) GetBSON() (interface{}, error) {
 
 	return update, nil
 }
+
+// RelayerWarningEvent is the message of the system_status channel sent when the engine stops the order
+// intake of the pairs of a relayer which resigned, or accepts their orders again
+type RelayerWarningEvent struct {
+	Relayer common.Address `json:"relayer"`
+	Status  string         `json:"status"`
+	Pairs   []string       `json:"pairs"`
+	Message string         `json:"message"`
+}
+
+// RelayerFeeEvent is the message of the system_status channel sent when the fees of a relayer change on
+// the relayer contract, in hundredths of a percent
+type RelayerFeeEvent struct {
+	Relayer         common.Address `json:"relayer"`
+	MakeFee         uint16         `json:"makeFee"`
+	TakeFee         uint16         `json:"takeFee"`
+	PreviousMakeFee uint16         `json:"previousMakeFee"`
+	PreviousTakeFee uint16         `json:"previousTakeFee"`
+	UpdatedAt       time.Time      `json:"updatedAt"`
+}
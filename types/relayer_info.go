@@ -0,0 +1,28 @@
+package types
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// RelayerBrandingLinks are the links of the relayer_branding setting, each set from its <name>_url key
+var RelayerBrandingLinks = []string{"website", "support", "terms", "privacy", "twitter", "telegram"}
+
+// RelayerInfo is the metadata of the venue for the white label frontends: the branding set by the
+// operator, the coinbase of the relayer with its fees on the relayer contract, in hundredths of a
+// percent, the number of tokens and pairs it lists and the uptime of the SDK in seconds
+type RelayerInfo struct {
+	Name         string            `json:"name"`
+	Logo         string            `json:"logo,omitempty"`
+	SupportEmail string            `json:"supportEmail,omitempty"`
+	Links        map[string]string `json:"links"`
+	Coinbase     common.Address    `json:"coinbase"`
+	MakeFee      uint16            `json:"makeFee"`
+	TakeFee      uint16            `json:"takeFee"`
+	Tokens       int               `json:"tokens"`
+	Pairs        int               `json:"pairs"`
+	ActivePairs  int               `json:"activePairs"`
+	StartedAt    time.Time         `json:"startedAt"`
+	Uptime       int64             `json:"uptime"`
+}
@@ -0,0 +1,113 @@
+package types
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// REORG is the event of the blocks channel sent when the chain reorganizes
+const REORG SubscriptionEvent = "REORG"
+
+// DefaultReorgDepth is the default number of block hashes kept to find where the chain forked
+const DefaultReorgDepth = 64
+
+// Reorg is a reorganization of the chain: the blocks above the common ancestor were replaced by the
+// branch of the new head. The trades settled in the replaced blocks are reverted, or re-applied when
+// their settlement was included again in the new branch
+type Reorg struct {
+	OldHead        uint64        `json:"oldHead"`
+	NewHead        *BlockHeader  `json:"newHead"`
+	CommonAncestor uint64        `json:"commonAncestor"`
+	Depth          uint64        `json:"depth"`
+	Reverted       []common.Hash `json:"reverted"`
+	Reapplied      []common.Hash `json:"reapplied"`
+	DetectedAt     time.Time     `json:"detectedAt"`
+}
+
+// BlockHashes keeps the hashes of the last blocks of the chain followed by the SDK, to detect when the
+// chain reorganizes and find the block where it forked
+type BlockHashes struct {
+	depth  uint64
+	head   uint64
+	hashes map[uint64]common.Hash
+}
+
+// NewBlockHashes returns the hashes of the last depth blocks, DefaultReorgDepth if zero
+func NewBlockHashes(depth int) *BlockHashes {
+	if depth <= 0 {
+		depth = DefaultReorgDepth
+	}
+
+	return &BlockHashes{
+		depth:  uint64(depth),
+		hashes: make(map[uint64]common.Hash),
+	}
+}
+
+// Head returns the number of the last head added
+func (b *BlockHashes) Head() uint64 {
+	return b.head
+}
+
+// Add records a new chain head and returns true if it does not extend the known chain: its parent is not
+// the known block below it or a known block of its height has another hash. The blocks above it are
+// forgotten, they belong to the abandoned branch
+func (b *BlockHashes) Add(h *BlockHeader) bool {
+	forked := false
+	if parent, ok := b.hashes[h.Number-1]; ok && h.Number > 0 && parent != h.ParentHash {
+		forked = true
+	}
+
+	if known, ok := b.hashes[h.Number]; ok && known != h.Hash {
+		forked = true
+	}
+
+	for n := h.Number + 1; n <= b.head; n++ {
+		delete(b.hashes, n)
+	}
+
+	b.hashes[h.Number] = h.Hash
+	b.head = h.Number
+
+	for n := range b.hashes {
+		if n+b.depth <= b.head {
+			delete(b.hashes, n)
+		}
+	}
+
+	return forked
+}
+
+// Fork returns the common ancestor of a forked head and the known chain, walking back from its parent
+// with the hashes of the canonical chain returned by the node. The known hashes of the abandoned branch
+// are replaced. False is returned if the fork is deeper than the known blocks, the ancestor is then the
+// highest block below them
+func (b *BlockHashes) Fork(h *BlockHeader, canonical func(number uint64) (common.Hash, error)) (uint64, bool, error) {
+	n := h.Number
+	hash := h.ParentHash
+	for n > 0 {
+		n--
+		known, ok := b.hashes[n]
+		if !ok {
+			return n, false, nil
+		}
+
+		if known == hash {
+			return n, true, nil
+		}
+
+		b.hashes[n] = hash
+		if n == 0 {
+			break
+		}
+
+		var err error
+		hash, err = canonical(n - 1)
+		if err != nil {
+			return 0, false, err
+		}
+	}
+
+	return 0, true, nil
+}
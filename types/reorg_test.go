@@ -0,0 +1,67 @@
+package types
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func chainHeader(number uint64, branch byte) *BlockHeader {
+	return &BlockHeader{
+		Number:     number,
+		Hash:       common.BytesToHash([]byte{branch, byte(number)}),
+		ParentHash: common.BytesToHash([]byte{branch, byte(number - 1)}),
+	}
+}
+
+func TestBlockHashesFork(t *testing.T) {
+	b := NewBlockHashes(10)
+	for n := uint64(1); n <= 8; n++ {
+		assert.False(t, b.Add(chainHeader(n, 'a')))
+	}
+
+	// branch b forks after the block 5 and its head is the block 8
+	head := chainHeader(8, 'b')
+	canonical := func(n uint64) (common.Hash, error) {
+		if n <= 5 {
+			return chainHeader(n, 'a').Hash, nil
+		}
+
+		return chainHeader(n, 'b').Hash, nil
+	}
+
+	assert.True(t, b.Add(head))
+	ancestor, ok, err := b.Fork(head, canonical)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(5), ancestor)
+	assert.Equal(t, uint64(8), b.Head())
+
+	// the known chain is the branch b now
+	assert.False(t, b.Add(chainHeader(9, 'b')))
+}
+
+func TestBlockHashesForkTooDeep(t *testing.T) {
+	b := NewBlockHashes(3)
+	for n := uint64(1); n <= 8; n++ {
+		b.Add(chainHeader(n, 'a'))
+	}
+
+	head := chainHeader(9, 'b')
+	assert.True(t, b.Add(head))
+
+	ancestor, ok, err := b.Fork(head, func(n uint64) (common.Hash, error) {
+		return chainHeader(n, 'b').Hash, nil
+	})
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, uint64(6), ancestor)
+
+	b.Add(chainHeader(10, 'c'))
+	_, _, err = b.Fork(chainHeader(10, 'c'), func(n uint64) (common.Hash, error) {
+		return common.Hash{}, errors.New("node down")
+	})
+	assert.Error(t, err)
+}
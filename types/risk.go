@@ -0,0 +1,236 @@
+package types
+
+import (
+	"encoding/json"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/errors"
+	"github.com/tomochain/tomox-sdk/utils/math"
+)
+
+// RiskLimit holds the pre-trade limits of an account on a pair. A zero account applies to
+// all the accounts and zero tokens to all the pairs. MaxOrderNotional is in quote token base units,
+// MaxPosition is the maximum amount of the open orders of the account on the pair in base token base
+// units. A nil limit is not checked. The orders matching the open orders of the banned counterparties
+// are rejected
+type RiskLimit struct {
+	ID                   bson.ObjectId    `json:"id" bson:"_id"`
+	Account              common.Address   `json:"account" bson:"account"`
+	BaseToken            common.Address   `json:"baseToken" bson:"baseToken"`
+	QuoteToken           common.Address   `json:"quoteToken" bson:"quoteToken"`
+	MaxOrderNotional     *big.Int         `json:"maxOrderNotional" bson:"maxOrderNotional"`
+	MaxPosition          *big.Int         `json:"maxPosition" bson:"maxPosition"`
+	BannedCounterparties []common.Address `json:"bannedCounterparties" bson:"bannedCounterparties"`
+	UpdatedBy            string           `json:"updatedBy,omitempty" bson:"updatedBy"`
+	CreatedAt            time.Time        `json:"createdAt" bson:"createdAt"`
+	UpdatedAt            time.Time        `json:"updatedAt" bson:"updatedAt"`
+}
+
+// RiskLimitRecord is the representation of a RiskLimit in the database
+type RiskLimitRecord struct {
+	ID                   bson.ObjectId `bson:"_id"`
+	Account              string        `bson:"account"`
+	BaseToken            string        `bson:"baseToken"`
+	QuoteToken           string        `bson:"quoteToken"`
+	MaxOrderNotional     string        `bson:"maxOrderNotional,omitempty"`
+	MaxPosition          string        `bson:"maxPosition,omitempty"`
+	BannedCounterparties []string      `bson:"bannedCounterparties"`
+	UpdatedBy            string        `bson:"updatedBy"`
+	CreatedAt            time.Time     `bson:"createdAt"`
+	UpdatedAt            time.Time     `bson:"updatedAt"`
+}
+
+// Matches returns true if the limit applies to the orders of the account on the pair
+func (l *RiskLimit) Matches(account, bt, qt common.Address) bool {
+	if l.Account != (common.Address{}) && l.Account != account {
+		return false
+	}
+
+	if l.BaseToken == (common.Address{}) && l.QuoteToken == (common.Address{}) {
+		return true
+	}
+
+	return l.BaseToken == bt && l.QuoteToken == qt
+}
+
+// specificity ranks the limits from the default ones to the ones of an account on a pair
+func (l *RiskLimit) specificity() int {
+	res := 0
+	if l.Account != (common.Address{}) {
+		res += 2
+	}
+
+	if l.BaseToken != (common.Address{}) || l.QuoteToken != (common.Address{}) {
+		res++
+	}
+
+	return res
+}
+
+// Bans returns true if the counterparty is banned by the limit
+func (l *RiskLimit) Bans(counterparty common.Address) bool {
+	for _, a := range l.BannedCounterparties {
+		if a == counterparty {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ResolveRiskLimit returns the limits applying to the orders of the account on the pair. Each limit
+// is taken from the most specific limit setting it (account and pair, account, pair, then default),
+// the banned counterparties of all the matching limits are banned
+func ResolveRiskLimit(limits []*RiskLimit, account, bt, qt common.Address) *RiskLimit {
+	res := &RiskLimit{Account: account, BaseToken: bt, QuoteToken: qt, BannedCounterparties: []common.Address{}}
+	notional, position := -1, -1
+
+	for _, l := range limits {
+		if !l.Matches(account, bt, qt) {
+			continue
+		}
+
+		s := l.specificity()
+		if l.MaxOrderNotional != nil && s > notional {
+			res.MaxOrderNotional = l.MaxOrderNotional
+			notional = s
+		}
+
+		if l.MaxPosition != nil && s > position {
+			res.MaxPosition = l.MaxPosition
+			position = s
+		}
+
+		for _, a := range l.BannedCounterparties {
+			if !res.Bans(a) {
+				res.BannedCounterparties = append(res.BannedCounterparties, a)
+			}
+		}
+	}
+
+	return res
+}
+
+// MarshalJSON returns the json encoded byte array representing the risk limit
+func (l *RiskLimit) MarshalJSON() ([]byte, error) {
+	type alias RiskLimit
+	var notional, position *string
+
+	if l.MaxOrderNotional != nil {
+		s := l.MaxOrderNotional.String()
+		notional = &s
+	}
+
+	if l.MaxPosition != nil {
+		s := l.MaxPosition.String()
+		position = &s
+	}
+
+	return json.Marshal(&struct {
+		*alias
+		MaxOrderNotional *string `json:"maxOrderNotional"`
+		MaxPosition      *string `json:"maxPosition"`
+	}{(*alias)(l), notional, position})
+}
+
+// UnmarshalJSON decodes the risk limit, the limits are decimal strings in base units
+func (l *RiskLimit) UnmarshalJSON(b []byte) error {
+	type alias RiskLimit
+	decoded := &struct {
+		*alias
+		MaxOrderNotional *string `json:"maxOrderNotional"`
+		MaxPosition      *string `json:"maxPosition"`
+	}{alias: (*alias)(l)}
+
+	err := json.Unmarshal(b, decoded)
+	if err != nil {
+		return err
+	}
+
+	l.MaxOrderNotional, err = parseRiskAmount(decoded.MaxOrderNotional)
+	if err != nil {
+		return err
+	}
+
+	l.MaxPosition, err = parseRiskAmount(decoded.MaxPosition)
+	return err
+}
+
+func parseRiskAmount(s *string) (*big.Int, error) {
+	if s == nil || *s == "" {
+		return nil, nil
+	}
+
+	v, ok := new(big.Int).SetString(*s, 10)
+	if !ok || v.Sign() < 0 {
+		return nil, errors.Errorf("Invalid risk limit amount %s", *s)
+	}
+
+	return v, nil
+}
+
+// GetBSON implements bson.Getter
+func (l *RiskLimit) GetBSON() (interface{}, error) {
+	r := RiskLimitRecord{
+		ID:                   l.ID,
+		Account:              l.Account.Hex(),
+		BaseToken:            l.BaseToken.Hex(),
+		QuoteToken:           l.QuoteToken.Hex(),
+		BannedCounterparties: []string{},
+		UpdatedBy:            l.UpdatedBy,
+		CreatedAt:            l.CreatedAt,
+		UpdatedAt:            l.UpdatedAt,
+	}
+
+	if l.MaxOrderNotional != nil {
+		r.MaxOrderNotional = l.MaxOrderNotional.String()
+	}
+
+	if l.MaxPosition != nil {
+		r.MaxPosition = l.MaxPosition.String()
+	}
+
+	for _, a := range l.BannedCounterparties {
+		r.BannedCounterparties = append(r.BannedCounterparties, a.Hex())
+	}
+
+	return r, nil
+}
+
+// SetBSON implements bson.Setter
+func (l *RiskLimit) SetBSON(raw bson.Raw) error {
+	decoded := &RiskLimitRecord{}
+
+	err := raw.Unmarshal(decoded)
+	if err != nil {
+		return err
+	}
+
+	l.ID = decoded.ID
+	l.Account = common.HexToAddress(decoded.Account)
+	l.BaseToken = common.HexToAddress(decoded.BaseToken)
+	l.QuoteToken = common.HexToAddress(decoded.QuoteToken)
+	l.MaxOrderNotional = nil
+	if decoded.MaxOrderNotional != "" {
+		l.MaxOrderNotional = math.ToBigInt(decoded.MaxOrderNotional)
+	}
+
+	l.MaxPosition = nil
+	if decoded.MaxPosition != "" {
+		l.MaxPosition = math.ToBigInt(decoded.MaxPosition)
+	}
+
+	l.BannedCounterparties = []common.Address{}
+	for _, a := range decoded.BannedCounterparties {
+		l.BannedCounterparties = append(l.BannedCounterparties, common.HexToAddress(a))
+	}
+
+	l.UpdatedBy = decoded.UpdatedBy
+	l.CreatedAt = decoded.CreatedAt
+	l.UpdatedAt = decoded.UpdatedAt
+
+	return nil
+}
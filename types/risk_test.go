@@ -0,0 +1,88 @@
+package types
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/globalsign/mgo/bson"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveRiskLimit(t *testing.T) {
+	account := common.HexToAddress("0x1")
+	other := common.HexToAddress("0x2")
+	bt := common.HexToAddress("0x10")
+	qt := common.HexToAddress("0x11")
+	banned := common.HexToAddress("0x99")
+
+	limits := []*RiskLimit{
+		{MaxOrderNotional: big.NewInt(1000), MaxPosition: big.NewInt(50)},
+		{BaseToken: bt, QuoteToken: qt, MaxOrderNotional: big.NewInt(500)},
+		{Account: account, BannedCounterparties: []common.Address{banned}},
+		{Account: account, BaseToken: bt, QuoteToken: qt, MaxPosition: big.NewInt(10)},
+		{Account: other, MaxOrderNotional: big.NewInt(1)},
+	}
+
+	l := ResolveRiskLimit(limits, account, bt, qt)
+	assert.Equal(t, big.NewInt(500), l.MaxOrderNotional)
+	assert.Equal(t, big.NewInt(10), l.MaxPosition)
+	assert.True(t, l.Bans(banned))
+
+	l = ResolveRiskLimit(limits, account, common.HexToAddress("0x20"), qt)
+	assert.Equal(t, big.NewInt(1000), l.MaxOrderNotional)
+	assert.Equal(t, big.NewInt(50), l.MaxPosition)
+	assert.True(t, l.Bans(banned))
+
+	l = ResolveRiskLimit(limits, other, bt, qt)
+	assert.Equal(t, big.NewInt(1), l.MaxOrderNotional)
+	assert.False(t, l.Bans(banned))
+
+	l = ResolveRiskLimit(nil, other, bt, qt)
+	assert.Nil(t, l.MaxOrderNotional)
+	assert.Nil(t, l.MaxPosition)
+}
+
+func TestRiskLimitJSON(t *testing.T) {
+	l := &RiskLimit{}
+	err := json.Unmarshal([]byte(`{
+		"account": "0x0000000000000000000000000000000000000001",
+		"maxOrderNotional": "1000000000000000000000",
+		"bannedCounterparties": ["0x0000000000000000000000000000000000000002"]
+	}`), l)
+	assert.Nil(t, err)
+	assert.Equal(t, "1000000000000000000000", l.MaxOrderNotional.String())
+	assert.Nil(t, l.MaxPosition)
+	assert.Equal(t, 1, len(l.BannedCounterparties))
+
+	b, err := json.Marshal(l)
+	assert.Nil(t, err)
+	decoded := map[string]interface{}{}
+	json.Unmarshal(b, &decoded)
+	assert.Equal(t, "1000000000000000000000", decoded["maxOrderNotional"])
+	assert.Nil(t, decoded["maxPosition"])
+
+	err = json.Unmarshal([]byte(`{"maxPosition": "-1"}`), &RiskLimit{})
+	assert.NotNil(t, err)
+}
+
+func TestRiskLimitBSON(t *testing.T) {
+	l := &RiskLimit{
+		ID:                   bson.NewObjectId(),
+		Account:              common.HexToAddress("0x1"),
+		MaxPosition:          big.NewInt(42),
+		BannedCounterparties: []common.Address{common.HexToAddress("0x2")},
+	}
+
+	data, err := bson.Marshal(l)
+	assert.Nil(t, err)
+
+	decoded := &RiskLimit{}
+	err = bson.Unmarshal(data, decoded)
+	assert.Nil(t, err)
+	assert.Equal(t, l.Account, decoded.Account)
+	assert.Nil(t, decoded.MaxOrderNotional)
+	assert.Equal(t, big.NewInt(42), decoded.MaxPosition)
+	assert.Equal(t, l.BannedCounterparties, decoded.BannedCounterparties)
+}
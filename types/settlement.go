@@ -0,0 +1,135 @@
+package types
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/globalsign/mgo/bson"
+)
+
+// Statuses of a failed trade settlement
+const (
+	SettlementStatusRetrying  = "RETRYING"
+	SettlementStatusStuck     = "STUCK"
+	SettlementStatusSettled   = "SETTLED"
+	SettlementStatusAbandoned = "ABANDONED"
+)
+
+// Settlement follows the recovery of a trade whose on-chain settlement failed
+type Settlement struct {
+	ID          bson.ObjectId  `json:"-" bson:"_id"`
+	TradeHash   common.Hash    `json:"tradeHash" bson:"tradeHash"`
+	TxHash      common.Hash    `json:"txHash" bson:"txHash"`
+	PairName    string         `json:"pairName" bson:"pairName"`
+	BaseToken   common.Address `json:"baseToken" bson:"baseToken"`
+	QuoteToken  common.Address `json:"quoteToken" bson:"quoteToken"`
+	Status      string         `json:"status" bson:"status"`
+	Attempts    int            `json:"attempts" bson:"attempts"`
+	LastError   string         `json:"lastError" bson:"lastError"`
+	NextRetryAt time.Time      `json:"nextRetryAt" bson:"nextRetryAt"`
+	ResolvedBy  string         `json:"resolvedBy,omitempty" bson:"resolvedBy"`
+	CreatedAt   time.Time      `json:"createdAt" bson:"createdAt"`
+	UpdatedAt   time.Time      `json:"updatedAt" bson:"updatedAt"`
+}
+
+// SettlementBSON is the representation of a Settlement in the database
+type SettlementBSON struct {
+	ID          bson.ObjectId `bson:"_id"`
+	TradeHash   string        `bson:"tradeHash"`
+	TxHash      string        `bson:"txHash"`
+	PairName    string        `bson:"pairName"`
+	BaseToken   string        `bson:"baseToken"`
+	QuoteToken  string        `bson:"quoteToken"`
+	Status      string        `bson:"status"`
+	Attempts    int           `bson:"attempts"`
+	LastError   string        `bson:"lastError"`
+	NextRetryAt time.Time     `bson:"nextRetryAt"`
+	ResolvedBy  string        `bson:"resolvedBy"`
+	CreatedAt   time.Time     `bson:"createdAt"`
+	UpdatedAt   time.Time     `bson:"updatedAt"`
+}
+
+// NewSettlement returns the settlement of a failed trade, retried immediately
+func NewSettlement(t *Trade) *Settlement {
+	return &Settlement{
+		TradeHash:   t.Hash,
+		TxHash:      t.TxHash,
+		PairName:    t.PairName,
+		BaseToken:   t.BaseToken,
+		QuoteToken:  t.QuoteToken,
+		Status:      SettlementStatusRetrying,
+		NextRetryAt: time.Now(),
+	}
+}
+
+// Fail records a failed attempt. The next attempt is delayed exponentially from
+// baseDelay and the settlement is stuck once maxAttempts is reached
+func (s *Settlement) Fail(reason string, maxAttempts int, baseDelay time.Duration) {
+	s.Attempts++
+	s.LastError = reason
+
+	if s.Attempts >= maxAttempts {
+		s.Status = SettlementStatusStuck
+		return
+	}
+
+	s.NextRetryAt = time.Now().Add(baseDelay << uint(s.Attempts-1))
+}
+
+// Revert records a settlement transaction reverted on chain. A reverted receipt never changes, the
+// settlement is stuck at once
+func (s *Settlement) Revert(reason string) {
+	s.Attempts++
+	s.LastError = reason
+	s.Status = SettlementStatusStuck
+}
+
+// IsResolved returns true if the settlement does not need any more action
+func (s *Settlement) IsResolved() bool {
+	return s.Status == SettlementStatusSettled || s.Status == SettlementStatusAbandoned
+}
+
+// GetBSON implements bson.Getter
+func (s *Settlement) GetBSON() (interface{}, error) {
+	return SettlementBSON{
+		ID:          s.ID,
+		TradeHash:   s.TradeHash.Hex(),
+		TxHash:      s.TxHash.Hex(),
+		PairName:    s.PairName,
+		BaseToken:   s.BaseToken.Hex(),
+		QuoteToken:  s.QuoteToken.Hex(),
+		Status:      s.Status,
+		Attempts:    s.Attempts,
+		LastError:   s.LastError,
+		NextRetryAt: s.NextRetryAt,
+		ResolvedBy:  s.ResolvedBy,
+		CreatedAt:   s.CreatedAt,
+		UpdatedAt:   s.UpdatedAt,
+	}, nil
+}
+
+// SetBSON implements bson.Setter
+func (s *Settlement) SetBSON(raw bson.Raw) error {
+	decoded := &SettlementBSON{}
+
+	err := raw.Unmarshal(decoded)
+	if err != nil {
+		return err
+	}
+
+	s.ID = decoded.ID
+	s.TradeHash = common.HexToHash(decoded.TradeHash)
+	s.TxHash = common.HexToHash(decoded.TxHash)
+	s.PairName = decoded.PairName
+	s.BaseToken = common.HexToAddress(decoded.BaseToken)
+	s.QuoteToken = common.HexToAddress(decoded.QuoteToken)
+	s.Status = decoded.Status
+	s.Attempts = decoded.Attempts
+	s.LastError = decoded.LastError
+	s.NextRetryAt = decoded.NextRetryAt
+	s.ResolvedBy = decoded.ResolvedBy
+	s.CreatedAt = decoded.CreatedAt
+	s.UpdatedAt = decoded.UpdatedAt
+
+	return nil
+}
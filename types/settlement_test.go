@@ -0,0 +1,40 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSettlementFail(t *testing.T) {
+	s := NewSettlement(&Trade{PairName: "TOMO/USDT"})
+	assert.Equal(t, SettlementStatusRetrying, s.Status)
+
+	s.Fail("receipt not found", 3, time.Minute)
+	assert.Equal(t, 1, s.Attempts)
+	assert.Equal(t, SettlementStatusRetrying, s.Status)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), s.NextRetryAt, time.Second)
+
+	s.Fail("receipt not found", 3, time.Minute)
+	assert.WithinDuration(t, time.Now().Add(2*time.Minute), s.NextRetryAt, time.Second)
+
+	s.Fail("receipt not found", 3, time.Minute)
+	assert.Equal(t, SettlementStatusStuck, s.Status)
+	assert.Equal(t, "receipt not found", s.LastError)
+	assert.False(t, s.IsResolved())
+
+	s.Status = SettlementStatusAbandoned
+	assert.True(t, s.IsResolved())
+}
+
+func TestSettlementRevert(t *testing.T) {
+	s := NewSettlement(&Trade{PairName: "TOMO/USDT"})
+
+	// a reverted settlement is stuck without retry
+	s.Revert("reverted")
+	assert.Equal(t, 1, s.Attempts)
+	assert.Equal(t, SettlementStatusStuck, s.Status)
+	assert.Equal(t, "reverted", s.LastError)
+	assert.False(t, s.IsResolved())
+}
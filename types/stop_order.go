@@ -10,7 +10,6 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/crypto/sha3"
 	"github.com/globalsign/mgo/bson"
-	"github.com/tomochain/tomox-sdk/app"
 	"github.com/tomochain/tomox-sdk/errors"
 	"github.com/tomochain/tomox-sdk/utils/math"
 )
@@ -354,9 +353,10 @@ func (so *StopOrder) ToOrder() (*Order, error) {
 	return o, nil
 }
 
+// Validate checks the parameters of the stop order, its exchange address must be the one of the relayer
 // TODO: Verify userAddress, baseToken, quoteToken, etc. conditions are working
-func (so *StopOrder) Validate() error {
-	if so.ExchangeAddress != common.HexToAddress(app.Config.Tomochain["exchange_address"]) {
+func (so *StopOrder) Validate(exchangeAddress common.Address) error {
+	if so.ExchangeAddress != exchangeAddress {
 		return errors.New("Order 'exchangeAddress' parameter is incorrect")
 	}
 
@@ -468,8 +468,7 @@ func (so *StopOrder) Process(p *Pair) error {
 }
 
 func (so *StopOrder) QuoteAmount(p *Pair) *big.Int {
-	pairMultiplier := p.PairMultiplier()
-	return math.Div(math.Mul(so.Amount, so.StopPrice), pairMultiplier)
+	return p.QuoteAmount(so.Amount, so.StopPrice)
 }
 
 //TODO handle error case ?
@@ -0,0 +1,45 @@
+package types
+
+import (
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+// Events of the subscription profiles channel
+const (
+	SaveProfile     SubscriptionEvent = "SAVE_PROFILE"
+	ActivateProfile SubscriptionEvent = "ACTIVATE_PROFILE"
+	DeleteProfile   SubscriptionEvent = "DELETE_PROFILE"
+	GetProfiles     SubscriptionEvent = "GET_PROFILES"
+)
+
+// Limits of the subscription profiles
+const (
+	MaxSubscriptionProfiles = 20
+	MaxProfileSubscriptions = 50
+)
+
+// ProfileSubscription is a channel subscription saved in a profile,
+// the payload is sent as is in the SUBSCRIBE message of the channel
+type ProfileSubscription struct {
+	Channel string      `json:"channel" bson:"channel"`
+	Payload interface{} `json:"payload" bson:"payload"`
+}
+
+// SubscriptionProfile is a named set of websocket subscriptions saved by a client
+// and activated with a single message after reconnecting
+type SubscriptionProfile struct {
+	ID            bson.ObjectId          `json:"-" bson:"_id"`
+	Owner         string                 `json:"-" bson:"owner"`
+	Name          string                 `json:"name" bson:"name"`
+	Subscriptions []*ProfileSubscription `json:"subscriptions" bson:"subscriptions"`
+	CreatedAt     time.Time              `json:"createdAt" bson:"createdAt"`
+	UpdatedAt     time.Time              `json:"updatedAt" bson:"updatedAt"`
+}
+
+// SubscriptionProfilePayload is the payload of the subscription profiles channel messages
+type SubscriptionProfilePayload struct {
+	Name          string                 `json:"name"`
+	Subscriptions []*ProfileSubscription `json:"subscriptions"`
+}
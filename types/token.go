@@ -24,9 +24,16 @@ type Token struct {
 	Active          bool           `json:"active" bson:"active"`
 	Listed          bool           `json:"listed" bson:"listed"`
 	Quote           bool           `json:"quote" bson:"quote"`
+	Verified        bool           `json:"verified" bson:"verified"`
+	SourceVerified  *bool          `json:"sourceVerified" bson:"sourceVerified,omitempty"`
+	Safety          *TokenSafety   `json:"safety,omitempty" bson:"safety,omitempty"`
+	Collateral      *Collateral    `json:"collateral,omitempty" bson:"collateral,omitempty"`
 	MakeFee         *big.Int       `json:"makeFee,omitempty" bson:"makeFee,omitempty"`
 	TakeFee         *big.Int       `json:"takeFee,omitempty" bson:"makeFee,omitempty"`
 	USD             string         `json:"usd,omitempty" bson:"usd,omitempty"`
+	TRC21           bool           `json:"trc21" bson:"trc21"`
+	Issuer          common.Address `json:"issuer,omitempty" bson:"issuer,omitempty"`
+	MinFee          *big.Int       `json:"minFee,omitempty" bson:"minFee,omitempty"`
 
 	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt" bson:"updatedAt"`
@@ -43,14 +50,29 @@ type TokenRecord struct {
 	Decimals        int           `json:"decimals" bson:"decimals"`
 	Active          bool          `json:"active" bson:"active"`
 	Quote           bool          `json:"quote" bson:"quote"`
+	Verified        bool          `json:"verified" bson:"verified"`
+	SourceVerified  *bool         `json:"sourceVerified" bson:"sourceVerified,omitempty"`
+	Safety          *TokenSafety  `json:"safety,omitempty" bson:"safety,omitempty"`
+	Collateral      *Collateral   `json:"collateral,omitempty" bson:"collateral,omitempty"`
 	MakeFee         string        `json:"makeFee,omitempty" bson:"makeFee,omitempty"`
 	TakeFee         string        `json:"takeFee,omitempty" bson:"takeFee,omitempty"`
 	USD             string        `json:"usd,omitempty" bson:"usd,omitempty"`
+	TRC21           bool          `json:"trc21" bson:"trc21"`
+	Issuer          string        `json:"issuer,omitempty" bson:"issuer,omitempty"`
+	MinFee          string        `json:"minFee,omitempty" bson:"minFee,omitempty"`
 
 	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt" bson:"updatedAt"`
 }
 
+// Collateral are the lending parameters of a collateral token read from the lending contract,
+// the rates in percent of the loan and the prices in each lending token, keyed by its address
+type Collateral struct {
+	DepositRate     string            `json:"depositRate" bson:"depositRate"`
+	LiquidationRate string            `json:"liquidationRate" bson:"liquidationRate"`
+	Prices          map[string]string `json:"prices" bson:"prices"`
+}
+
 // Image is a sub document used to store data related to images
 type Image struct {
 	URL  string                 `json:"url" bson:"url"`
@@ -108,7 +130,10 @@ func (t *Token) MarshalJSON() ([]byte, error) {
 		"image":           t.Image,
 		"active":          t.Active,
 		"quote":           t.Quote,
+		"verified":        t.Verified,
+		"sourceVerified":  t.SourceVerified,
 		"usd":             t.USD,
+		"trc21":           t.TRC21,
 		"createdAt":       t.CreatedAt.Format(time.RFC3339Nano),
 		"updatedAt":       t.UpdatedAt.Format(time.RFC3339Nano),
 	}
@@ -121,6 +146,21 @@ func (t *Token) MarshalJSON() ([]byte, error) {
 		token["takeFee"] = t.TakeFee.String()
 	}
 
+	if t.TRC21 {
+		token["issuer"] = t.Issuer.Hex()
+		if t.MinFee != nil {
+			token["minFee"] = t.MinFee.String()
+		}
+	}
+
+	if t.Safety != nil {
+		token["safety"] = t.Safety
+	}
+
+	if t.Collateral != nil {
+		token["collateral"] = t.Collateral
+	}
+
 	return json.Marshal(token)
 }
 
@@ -137,7 +177,19 @@ func (t *Token) UnmarshalJSON(b []byte) error {
 	t.Decimals = token["decimals"].(int)
 	t.Active = token["active"].(bool)
 	t.Quote = token["quote"].(bool)
+	t.Verified, _ = token["verified"].(bool)
+	if v, ok := token["sourceVerified"].(bool); ok {
+		t.SourceVerified = &v
+	}
 	t.USD = token["usd"].(string)
+	t.TRC21, _ = token["trc21"].(bool)
+	if issuer, ok := token["issuer"].(string); ok {
+		t.Issuer = common.HexToAddress(issuer)
+	}
+
+	if minFee, ok := token["minFee"].(string); ok {
+		t.MinFee = math.ToBigInt(minFee)
+	}
 
 	if token["createdAt"] != nil {
 		tm, _ := time.Parse(time.RFC3339Nano, token["createdAt"].(string))
@@ -179,11 +231,23 @@ func (t *Token) GetBSON() (interface{}, error) {
 		Decimals:        t.Decimals,
 		Active:          t.Active,
 		Quote:           t.Quote,
+		Verified:        t.Verified,
+		SourceVerified:  t.SourceVerified,
+		Safety:          t.Safety,
+		Collateral:      t.Collateral,
 		USD:             t.USD,
+		TRC21:           t.TRC21,
 		CreatedAt:       t.CreatedAt,
 		UpdatedAt:       t.UpdatedAt,
 	}
 
+	if t.TRC21 {
+		tr.Issuer = t.Issuer.Hex()
+		if t.MinFee != nil {
+			tr.MinFee = t.MinFee.String()
+		}
+	}
+
 	if t.MakeFee != nil {
 		tr.MakeFee = t.MakeFee.String()
 	}
@@ -216,7 +280,18 @@ func (t *Token) SetBSON(raw bson.Raw) error {
 	t.Decimals = decoded.Decimals
 	t.Active = decoded.Active
 	t.Quote = decoded.Quote
+	t.Verified = decoded.Verified
+	t.SourceVerified = decoded.SourceVerified
+	t.Safety = decoded.Safety
+	t.Collateral = decoded.Collateral
 	t.USD = decoded.USD
+	t.TRC21 = decoded.TRC21
+	if common.IsHexAddress(decoded.Issuer) {
+		t.Issuer = common.HexToAddress(decoded.Issuer)
+	}
+	if decoded.MinFee != "" {
+		t.MinFee = math.ToBigInt(decoded.MinFee)
+	}
 	t.CreatedAt = decoded.CreatedAt
 	t.UpdatedAt = decoded.UpdatedAt
 	if decoded.MakeFee != "" {
@@ -0,0 +1,79 @@
+package types
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/globalsign/mgo/bson"
+)
+
+// TokenAlias makes a token the canonical one of its symbol, which is then resolved to it
+// when several tokens share the symbol
+type TokenAlias struct {
+	ID        bson.ObjectId  `json:"id" bson:"_id"`
+	Symbol    string         `json:"symbol" bson:"symbol"`
+	Address   common.Address `json:"address" bson:"address"`
+	UpdatedBy string         `json:"updatedBy,omitempty" bson:"updatedBy"`
+	CreatedAt time.Time      `json:"createdAt" bson:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt" bson:"updatedAt"`
+}
+
+// TokenAliasRecord is the representation of a TokenAlias in the database
+type TokenAliasRecord struct {
+	ID        bson.ObjectId `bson:"_id"`
+	Symbol    string        `bson:"symbol"`
+	Address   string        `bson:"address"`
+	UpdatedBy string        `bson:"updatedBy"`
+	CreatedAt time.Time     `bson:"createdAt"`
+	UpdatedAt time.Time     `bson:"updatedAt"`
+}
+
+// ResolveSymbol returns the token a symbol refers to among the tokens sharing it: the token of
+// the alias if set and still listed, the only token otherwise. Nil is returned if no token has
+// the symbol or if it is ambiguous, in which case true is returned
+func ResolveSymbol(tokens []Token, alias *TokenAlias) (*Token, bool) {
+	if alias != nil {
+		if token := TokensFrom(alias.Address, tokens); token != nil {
+			return token, false
+		}
+	}
+
+	switch len(tokens) {
+	case 0:
+		return nil, false
+	case 1:
+		return &tokens[0], false
+	default:
+		return nil, true
+	}
+}
+
+// GetBSON implements bson.Getter
+func (a *TokenAlias) GetBSON() (interface{}, error) {
+	return TokenAliasRecord{
+		ID:        a.ID,
+		Symbol:    a.Symbol,
+		Address:   a.Address.Hex(),
+		UpdatedBy: a.UpdatedBy,
+		CreatedAt: a.CreatedAt,
+		UpdatedAt: a.UpdatedAt,
+	}, nil
+}
+
+// SetBSON implements bson.Setter
+func (a *TokenAlias) SetBSON(raw bson.Raw) error {
+	decoded := &TokenAliasRecord{}
+
+	err := raw.Unmarshal(decoded)
+	if err != nil {
+		return err
+	}
+
+	a.ID = decoded.ID
+	a.Symbol = decoded.Symbol
+	a.Address = common.HexToAddress(decoded.Address)
+	a.UpdatedBy = decoded.UpdatedBy
+	a.CreatedAt = decoded.CreatedAt
+	a.UpdatedAt = decoded.UpdatedAt
+	return nil
+}
@@ -0,0 +1,34 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveSymbol(t *testing.T) {
+	usdt := Token{Symbol: "USDT", ContractAddress: common.HexToAddress("0x1")}
+	fake := Token{Symbol: "USDT", ContractAddress: common.HexToAddress("0x2")}
+
+	res, ambiguous := ResolveSymbol(nil, nil)
+	assert.Nil(t, res)
+	assert.False(t, ambiguous)
+
+	res, ambiguous = ResolveSymbol([]Token{usdt}, nil)
+	assert.Equal(t, usdt.ContractAddress, res.ContractAddress)
+	assert.False(t, ambiguous)
+
+	res, ambiguous = ResolveSymbol([]Token{usdt, fake}, nil)
+	assert.Nil(t, res)
+	assert.True(t, ambiguous)
+
+	res, ambiguous = ResolveSymbol([]Token{usdt, fake}, &TokenAlias{Symbol: "USDT", Address: fake.ContractAddress})
+	assert.Equal(t, fake.ContractAddress, res.ContractAddress)
+	assert.False(t, ambiguous)
+
+	// an alias to a token no longer listed is ignored
+	res, ambiguous = ResolveSymbol([]Token{usdt, fake}, &TokenAlias{Symbol: "USDT", Address: common.HexToAddress("0x3")})
+	assert.Nil(t, res)
+	assert.True(t, ambiguous)
+}
@@ -0,0 +1,332 @@
+package types
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/utils/math"
+)
+
+// Statuses of a token migration
+const (
+	TokenMigrationStatusCompleted = "COMPLETED"
+	TokenMigrationStatusPartial   = "PARTIAL"
+)
+
+// Reasons of the open orders which cannot be mapped to the new token
+const (
+	TokenMigrationAmountPrecision     = "amount loses precision"
+	TokenMigrationPricepointPrecision = "pricepoint loses precision"
+)
+
+// TokenMigration is the report of the migration of a listed token to a new contract. The pairs of
+// the old token are halted and regenerated with the new token. The open orders are signed over the
+// old token address so they cannot be moved, each is mapped to the equivalent order on the new pair
+// for its owner to sign again
+type TokenMigration struct {
+	ID          bson.ObjectId         `json:"id" bson:"_id"`
+	OldToken    common.Address        `json:"oldToken" bson:"oldToken"`
+	NewToken    common.Address        `json:"newToken" bson:"newToken"`
+	Symbol      string                `json:"symbol" bson:"symbol"`
+	OldDecimals int                   `json:"oldDecimals" bson:"oldDecimals"`
+	NewDecimals int                   `json:"newDecimals" bson:"newDecimals"`
+	DryRun      bool                  `json:"dryRun" bson:"dryRun"`
+	Status      string                `json:"status" bson:"status"`
+	Pairs       []*TokenMigrationPair `json:"pairs" bson:"pairs"`
+	StartedBy   string                `json:"startedBy,omitempty" bson:"startedBy"`
+	CreatedAt   time.Time             `json:"createdAt" bson:"createdAt"`
+	UpdatedAt   time.Time             `json:"updatedAt" bson:"updatedAt"`
+}
+
+// TokenMigrationPair is the migration of a pair of the old token
+type TokenMigrationPair struct {
+	Name           string                 `json:"name" bson:"name"`
+	OldBaseToken   common.Address         `json:"oldBaseToken" bson:"oldBaseToken"`
+	OldQuoteToken  common.Address         `json:"oldQuoteToken" bson:"oldQuoteToken"`
+	NewBaseToken   common.Address         `json:"newBaseToken" bson:"newBaseToken"`
+	NewQuoteToken  common.Address         `json:"newQuoteToken" bson:"newQuoteToken"`
+	RelayerAddress common.Address         `json:"relayerAddress" bson:"relayerAddress"`
+	Halted         bool                   `json:"halted" bson:"halted"`
+	Regenerated    bool                   `json:"regenerated" bson:"regenerated"`
+	Error          string                 `json:"error,omitempty" bson:"error"`
+	Orders         []*TokenMigrationOrder `json:"orders" bson:"orders"`
+}
+
+// TokenMigrationOrder maps the remaining amount of an open order of the old pair to the new pair.
+// Valid is false with the reason if the order has no equivalent with the decimals of the new token
+type TokenMigrationOrder struct {
+	Hash          common.Hash    `json:"hash" bson:"hash"`
+	UserAddress   common.Address `json:"userAddress" bson:"userAddress"`
+	Side          string         `json:"side" bson:"side"`
+	PricePoint    *big.Int       `json:"pricepoint" bson:"pricepoint"`
+	Amount        *big.Int       `json:"amount" bson:"amount"`
+	NewPricePoint *big.Int       `json:"newPricepoint,omitempty" bson:"newPricepoint"`
+	NewAmount     *big.Int       `json:"newAmount,omitempty" bson:"newAmount"`
+	Valid         bool           `json:"valid" bson:"valid"`
+	Reason        string         `json:"reason,omitempty" bson:"reason"`
+}
+
+// TokenMigrationRecord is the representation of a TokenMigration in the database
+type TokenMigrationRecord struct {
+	ID          bson.ObjectId               `bson:"_id"`
+	OldToken    string                      `bson:"oldToken"`
+	NewToken    string                      `bson:"newToken"`
+	Symbol      string                      `bson:"symbol"`
+	OldDecimals int                         `bson:"oldDecimals"`
+	NewDecimals int                         `bson:"newDecimals"`
+	DryRun      bool                        `bson:"dryRun"`
+	Status      string                      `bson:"status"`
+	Pairs       []*TokenMigrationPairRecord `bson:"pairs"`
+	StartedBy   string                      `bson:"startedBy"`
+	CreatedAt   time.Time                   `bson:"createdAt"`
+	UpdatedAt   time.Time                   `bson:"updatedAt"`
+}
+
+// TokenMigrationPairRecord is the representation of a TokenMigrationPair in the database
+type TokenMigrationPairRecord struct {
+	Name           string                       `bson:"name"`
+	OldBaseToken   string                       `bson:"oldBaseToken"`
+	OldQuoteToken  string                       `bson:"oldQuoteToken"`
+	NewBaseToken   string                       `bson:"newBaseToken"`
+	NewQuoteToken  string                       `bson:"newQuoteToken"`
+	RelayerAddress string                       `bson:"relayerAddress"`
+	Halted         bool                         `bson:"halted"`
+	Regenerated    bool                         `bson:"regenerated"`
+	Error          string                       `bson:"error"`
+	Orders         []*TokenMigrationOrderRecord `bson:"orders"`
+}
+
+// TokenMigrationOrderRecord is the representation of a TokenMigrationOrder in the database
+type TokenMigrationOrderRecord struct {
+	Hash          string `bson:"hash"`
+	UserAddress   string `bson:"userAddress"`
+	Side          string `bson:"side"`
+	PricePoint    string `bson:"pricepoint"`
+	Amount        string `bson:"amount"`
+	NewPricePoint string `bson:"newPricepoint"`
+	NewAmount     string `bson:"newAmount"`
+	Valid         bool   `bson:"valid"`
+	Reason        string `bson:"reason"`
+}
+
+// NewTokenMigrationPair returns the migration of a pair of the old token to the new token
+func NewTokenMigrationPair(p *Pair, oldToken, newToken common.Address) *TokenMigrationPair {
+	m := &TokenMigrationPair{
+		Name:           p.Name(),
+		OldBaseToken:   p.BaseTokenAddress,
+		OldQuoteToken:  p.QuoteTokenAddress,
+		NewBaseToken:   p.BaseTokenAddress,
+		NewQuoteToken:  p.QuoteTokenAddress,
+		RelayerAddress: p.RelayerAddress,
+		Orders:         []*TokenMigrationOrder{},
+	}
+
+	if p.BaseTokenAddress == oldToken {
+		m.NewBaseToken = newToken
+	}
+
+	if p.QuoteTokenAddress == oldToken {
+		m.NewQuoteToken = newToken
+	}
+
+	return m
+}
+
+// MapOrder maps the remaining amount of an open order of the old pair with the decimals of the new token.
+// The amounts are in base token units and the pricepoints in quote token units per whole base token,
+// so the amount is rescaled when the base token migrates and the pricepoint when the quote token does.
+// Nil is returned if nothing remains of the order
+func (m *TokenMigrationPair) MapOrder(o *Order, oldDecimals, newDecimals int) *TokenMigrationOrder {
+	remaining := new(big.Int).Set(o.Amount)
+	if o.FilledAmount != nil {
+		remaining = math.Sub(o.Amount, o.FilledAmount)
+	}
+
+	if remaining.Sign() <= 0 {
+		return nil
+	}
+
+	res := &TokenMigrationOrder{
+		Hash:          o.Hash,
+		UserAddress:   o.UserAddress,
+		Side:          o.Side,
+		PricePoint:    o.PricePoint,
+		Amount:        remaining,
+		NewPricePoint: o.PricePoint,
+		NewAmount:     remaining,
+		Valid:         true,
+	}
+
+	if m.NewBaseToken != m.OldBaseToken {
+		amount, ok := rescale(remaining, oldDecimals, newDecimals)
+		if !ok {
+			res.Valid = false
+			res.Reason = TokenMigrationAmountPrecision
+		}
+
+		res.NewAmount = amount
+	}
+
+	if m.NewQuoteToken != m.OldQuoteToken {
+		pricepoint, ok := rescale(o.PricePoint, oldDecimals, newDecimals)
+		if !ok {
+			res.Valid = false
+			res.Reason = TokenMigrationPricepointPrecision
+		}
+
+		res.NewPricePoint = pricepoint
+	}
+
+	if !res.Valid {
+		res.NewAmount = nil
+		res.NewPricePoint = nil
+	}
+
+	m.Orders = append(m.Orders, res)
+	return res
+}
+
+// rescale converts a value from a number of decimals to another, false is returned if
+// the value cannot be represented exactly or is zero
+func rescale(v *big.Int, from, to int) (*big.Int, bool) {
+	if v == nil {
+		return nil, false
+	}
+
+	if to >= from {
+		return math.Mul(v, math.Pow10(to-from)), v.Sign() > 0
+	}
+
+	q, r := new(big.Int).QuoRem(v, math.Pow10(from-to), new(big.Int))
+	return q, r.Sign() == 0 && q.Sign() > 0
+}
+
+// GetBSON implements bson.Getter
+func (m *TokenMigration) GetBSON() (interface{}, error) {
+	pairs := []*TokenMigrationPairRecord{}
+	for _, p := range m.Pairs {
+		pairs = append(pairs, p.record())
+	}
+
+	return TokenMigrationRecord{
+		ID:          m.ID,
+		OldToken:    m.OldToken.Hex(),
+		NewToken:    m.NewToken.Hex(),
+		Symbol:      m.Symbol,
+		OldDecimals: m.OldDecimals,
+		NewDecimals: m.NewDecimals,
+		DryRun:      m.DryRun,
+		Status:      m.Status,
+		Pairs:       pairs,
+		StartedBy:   m.StartedBy,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}, nil
+}
+
+// SetBSON implements bson.Setter
+func (m *TokenMigration) SetBSON(raw bson.Raw) error {
+	decoded := &TokenMigrationRecord{}
+
+	err := raw.Unmarshal(decoded)
+	if err != nil {
+		return err
+	}
+
+	m.ID = decoded.ID
+	m.OldToken = common.HexToAddress(decoded.OldToken)
+	m.NewToken = common.HexToAddress(decoded.NewToken)
+	m.Symbol = decoded.Symbol
+	m.OldDecimals = decoded.OldDecimals
+	m.NewDecimals = decoded.NewDecimals
+	m.DryRun = decoded.DryRun
+	m.Status = decoded.Status
+	m.StartedBy = decoded.StartedBy
+	m.CreatedAt = decoded.CreatedAt
+	m.UpdatedAt = decoded.UpdatedAt
+
+	m.Pairs = []*TokenMigrationPair{}
+	for _, p := range decoded.Pairs {
+		m.Pairs = append(m.Pairs, p.pair())
+	}
+
+	return nil
+}
+
+func (m *TokenMigrationPair) record() *TokenMigrationPairRecord {
+	orders := []*TokenMigrationOrderRecord{}
+	for _, o := range m.Orders {
+		orders = append(orders, &TokenMigrationOrderRecord{
+			Hash:          o.Hash.Hex(),
+			UserAddress:   o.UserAddress.Hex(),
+			Side:          o.Side,
+			PricePoint:    bigString(o.PricePoint),
+			Amount:        bigString(o.Amount),
+			NewPricePoint: bigString(o.NewPricePoint),
+			NewAmount:     bigString(o.NewAmount),
+			Valid:         o.Valid,
+			Reason:        o.Reason,
+		})
+	}
+
+	return &TokenMigrationPairRecord{
+		Name:           m.Name,
+		OldBaseToken:   m.OldBaseToken.Hex(),
+		OldQuoteToken:  m.OldQuoteToken.Hex(),
+		NewBaseToken:   m.NewBaseToken.Hex(),
+		NewQuoteToken:  m.NewQuoteToken.Hex(),
+		RelayerAddress: m.RelayerAddress.Hex(),
+		Halted:         m.Halted,
+		Regenerated:    m.Regenerated,
+		Error:          m.Error,
+		Orders:         orders,
+	}
+}
+
+func (r *TokenMigrationPairRecord) pair() *TokenMigrationPair {
+	orders := []*TokenMigrationOrder{}
+	for _, o := range r.Orders {
+		orders = append(orders, &TokenMigrationOrder{
+			Hash:          common.HexToHash(o.Hash),
+			UserAddress:   common.HexToAddress(o.UserAddress),
+			Side:          o.Side,
+			PricePoint:    stringBig(o.PricePoint),
+			Amount:        stringBig(o.Amount),
+			NewPricePoint: stringBig(o.NewPricePoint),
+			NewAmount:     stringBig(o.NewAmount),
+			Valid:         o.Valid,
+			Reason:        o.Reason,
+		})
+	}
+
+	return &TokenMigrationPair{
+		Name:           r.Name,
+		OldBaseToken:   common.HexToAddress(r.OldBaseToken),
+		OldQuoteToken:  common.HexToAddress(r.OldQuoteToken),
+		NewBaseToken:   common.HexToAddress(r.NewBaseToken),
+		NewQuoteToken:  common.HexToAddress(r.NewQuoteToken),
+		RelayerAddress: common.HexToAddress(r.RelayerAddress),
+		Halted:         r.Halted,
+		Regenerated:    r.Regenerated,
+		Error:          r.Error,
+		Orders:         orders,
+	}
+}
+
+func bigString(v *big.Int) string {
+	if v == nil {
+		return ""
+	}
+
+	return v.String()
+}
+
+func stringBig(s string) *big.Int {
+	if s == "" {
+		return nil
+	}
+
+	return math.ToBigInt(s)
+}
@@ -0,0 +1,51 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenMigrationMapOrder(t *testing.T) {
+	oldToken := common.HexToAddress("0x1")
+	newToken := common.HexToAddress("0x2")
+	quote := common.HexToAddress("0x3")
+
+	p := &Pair{BaseTokenSymbol: "AAA", BaseTokenAddress: oldToken, QuoteTokenSymbol: "USDT", QuoteTokenAddress: quote}
+	m := NewTokenMigrationPair(p, oldToken, newToken)
+	assert.Equal(t, newToken, m.NewBaseToken)
+	assert.Equal(t, quote, m.NewQuoteToken)
+
+	// the remaining amount of the base token is rescaled from 18 to 6 decimals, the pricepoint is kept
+	o := &Order{Side: BUY, PricePoint: big.NewInt(1500000), Amount: big.NewInt(3e18), FilledAmount: big.NewInt(1e18)}
+	res := m.MapOrder(o, 18, 6)
+	assert.True(t, res.Valid)
+	assert.Equal(t, big.NewInt(2e18), res.Amount)
+	assert.Equal(t, big.NewInt(2e6), res.NewAmount)
+	assert.Equal(t, big.NewInt(1500000), res.NewPricePoint)
+
+	o = &Order{Side: SELL, PricePoint: big.NewInt(1500000), Amount: big.NewInt(1e18 + 1), FilledAmount: big.NewInt(0)}
+	res = m.MapOrder(o, 18, 6)
+	assert.False(t, res.Valid)
+	assert.Equal(t, TokenMigrationAmountPrecision, res.Reason)
+	assert.Nil(t, res.NewAmount)
+
+	o = &Order{Side: SELL, PricePoint: big.NewInt(1500000), Amount: big.NewInt(1e18), FilledAmount: big.NewInt(1e18)}
+	assert.Nil(t, m.MapOrder(o, 18, 6))
+	assert.Len(t, m.Orders, 2)
+
+	// the pricepoint is rescaled when the quote token migrates
+	p = &Pair{BaseTokenAddress: quote, QuoteTokenAddress: oldToken}
+	m = NewTokenMigrationPair(p, oldToken, newToken)
+	o = &Order{Side: BUY, PricePoint: big.NewInt(1500000), Amount: big.NewInt(1e18)}
+	res = m.MapOrder(o, 6, 18)
+	assert.True(t, res.Valid)
+	assert.Equal(t, big.NewInt(1e18), res.NewAmount)
+	assert.Equal(t, big.NewInt(15e17), res.NewPricePoint)
+
+	res = m.MapOrder(o, 6, 0)
+	assert.False(t, res.Valid)
+	assert.Equal(t, TokenMigrationPricepointPrecision, res.Reason)
+}
@@ -0,0 +1,114 @@
+package types
+
+import (
+	"bytes"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Findings of the token safety checks
+const (
+	TokenRiskNoCode              = "no_code"
+	TokenRiskTransferReverts     = "transfer_reverts"
+	TokenRiskTransferReturnFalse = "transfer_returns_false"
+	TokenRiskHolderTransferFails = "holder_transfer_fails"
+	TokenRiskFeeFunctions        = "fee_functions"
+	TokenRiskBlacklistFunctions  = "blacklist_functions"
+	TokenRiskMaxTransaction      = "max_transaction"
+	TokenRiskPausable            = "pausable"
+)
+
+// tokenRiskWeights is the part of the risk score of each finding, the score is capped at 100
+var tokenRiskWeights = map[string]int{
+	TokenRiskNoCode:              100,
+	TokenRiskTransferReverts:     60,
+	TokenRiskTransferReturnFalse: 40,
+	TokenRiskHolderTransferFails: 60,
+	TokenRiskFeeFunctions:        30,
+	TokenRiskBlacklistFunctions:  20,
+	TokenRiskMaxTransaction:      20,
+	TokenRiskPausable:            10,
+}
+
+// tokenRiskFunctions are the functions of the contracts restricting the transfers or taking a fee on them,
+// found in the bytecode by their selectors
+var tokenRiskFunctions = map[string][]string{
+	TokenRiskFeeFunctions: {
+		"setFee(uint256)",
+		"setTaxFee(uint256)",
+		"setTaxFeePercent(uint256)",
+		"setLiquidityFeePercent(uint256)",
+		"setBuyFee(uint256)",
+		"setSellFee(uint256)",
+		"setTransferFee(uint256)",
+	},
+	TokenRiskBlacklistFunctions: {
+		"blacklist(address)",
+		"addBlackList(address)",
+		"addToBlacklist(address)",
+		"setBlacklist(address,bool)",
+		"isBlacklisted(address)",
+	},
+	TokenRiskMaxTransaction: {
+		"setMaxTxAmount(uint256)",
+		"setMaxTxPercent(uint256)",
+		"setMaxWalletSize(uint256)",
+	},
+	TokenRiskPausable: {
+		"pause()",
+	},
+}
+
+// TokenSafety is the result of the heuristics detecting the tokens restricting their transfers
+// or taking a hidden fee on them. The score goes from 0 (no finding) to 100
+type TokenSafety struct {
+	Score     int       `json:"score" bson:"score"`
+	Findings  []string  `json:"findings" bson:"findings"`
+	CheckedAt time.Time `json:"checkedAt" bson:"checkedAt"`
+}
+
+// NewTokenSafety returns the safety of a token from the findings of its checks
+func NewTokenSafety(findings []string, now time.Time) *TokenSafety {
+	s := &TokenSafety{Findings: []string{}, CheckedAt: now}
+	seen := make(map[string]bool)
+	for _, f := range findings {
+		if seen[f] {
+			continue
+		}
+
+		seen[f] = true
+		s.Findings = append(s.Findings, f)
+		s.Score += tokenRiskWeights[f]
+	}
+
+	if s.Score > 100 {
+		s.Score = 100
+	}
+
+	sort.Strings(s.Findings)
+	return s
+}
+
+// CodeFindings returns the findings of the functions of a contract bytecode, a selector is
+// looked for as the operand of a PUSH4 of the function dispatcher
+func CodeFindings(code []byte) []string {
+	if len(code) == 0 {
+		return []string{TokenRiskNoCode}
+	}
+
+	res := []string{}
+	for finding, signatures := range tokenRiskFunctions {
+		for _, sig := range signatures {
+			push := append([]byte{0x63}, crypto.Keccak256([]byte(sig))[:4]...)
+			if bytes.Contains(code, push) {
+				res = append(res, finding)
+				break
+			}
+		}
+	}
+
+	sort.Strings(res)
+	return res
+}
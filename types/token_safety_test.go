@@ -0,0 +1,37 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTokenSafety(t *testing.T) {
+	now := time.Now()
+
+	s := NewTokenSafety(nil, now)
+	assert.Equal(t, 0, s.Score)
+	assert.Equal(t, []string{}, s.Findings)
+
+	s = NewTokenSafety([]string{TokenRiskPausable, TokenRiskFeeFunctions, TokenRiskPausable}, now)
+	assert.Equal(t, 40, s.Score)
+	assert.Equal(t, []string{TokenRiskFeeFunctions, TokenRiskPausable}, s.Findings)
+
+	s = NewTokenSafety([]string{TokenRiskTransferReverts, TokenRiskHolderTransferFails}, now)
+	assert.Equal(t, 100, s.Score)
+}
+
+func TestCodeFindings(t *testing.T) {
+	assert.Equal(t, []string{TokenRiskNoCode}, CodeFindings(nil))
+
+	code := []byte{0x60, 0x80, 0x60, 0x40, 0x52}
+	assert.Equal(t, []string{}, CodeFindings(code))
+
+	code = append(code, 0x63)
+	code = append(code, crypto.Keccak256([]byte("setTaxFeePercent(uint256)"))[:4]...)
+	code = append(code, 0x63)
+	code = append(code, crypto.Keccak256([]byte("pause()"))[:4]...)
+	assert.Equal(t, []string{TokenRiskFeeFunctions, TokenRiskPausable}, CodeFindings(code))
+}
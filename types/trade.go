@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"strconv"
 	"time"
 
 	"github.com/tomochain/tomox-sdk/errors"
@@ -15,6 +16,12 @@ import (
 	"github.com/globalsign/mgo/bson"
 )
 
+// Roles of a user in a trade
+const (
+	TradeRoleMaker = "maker"
+	TradeRoleTaker = "taker"
+)
+
 const (
 	TradeStatusPending = "PENDING"
 	TradeStatusSuccess = "SUCCESS"
@@ -26,6 +33,7 @@ const (
 // the trade signature must be made from the trader Maker account
 type Trade struct {
 	ID             bson.ObjectId  `json:"id,omitempty" bson:"_id"`
+	PublicID       int64          `json:"publicId,omitempty" bson:"publicId"`
 	Taker          common.Address `json:"taker" bson:"taker"`
 	Maker          common.Address `json:"maker" bson:"maker"`
 	BaseToken      common.Address `json:"baseToken" bson:"baseToken"`
@@ -56,6 +64,15 @@ type TradeSpec struct {
 	RelayerAddress common.Address
 	DateFrom       int64
 	DateTo         int64
+	// Side filters the trades by taker order side (BUY or SELL)
+	Side string
+	// MinAmount filters out the trades smaller than the amount
+	MinAmount *big.Int
+	// Address and Role filter the trades of a user as maker or taker ("" for both)
+	Address string
+	Role    string
+	// Fields are the only fields of the trades read, all of them if empty
+	Fields []string
 }
 
 // TradeRes response api
@@ -65,6 +82,7 @@ type TradeRes struct {
 }
 type TradeRecord struct {
 	ID             bson.ObjectId `json:"id" bson:"_id"`
+	PublicID       int64         `json:"publicId,omitempty" bson:"publicId,omitempty"`
 	Taker          string        `json:"taker" bson:"taker"`
 	Maker          string        `json:"maker" bson:"maker"`
 	BaseToken      string        `json:"baseToken" bson:"baseToken"`
@@ -158,6 +176,11 @@ func (t *Trade) Validate() error {
 
 // MarshalJSON returns the json encoded byte array representing the trade struct
 func (t *Trade) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.jsonFields())
+}
+
+// jsonFields returns the fields of the JSON encoding of the trade
+func (t *Trade) jsonFields() map[string]interface{} {
 	trade := map[string]interface{}{
 		"taker":          t.Taker,
 		"maker":          t.Maker,
@@ -176,6 +199,10 @@ func (t *Trade) MarshalJSON() ([]byte, error) {
 		"takerExchange":  t.TakerExchange,
 	}
 
+	if t.PublicID != 0 {
+		trade["publicId"] = strconv.FormatInt(t.PublicID, 10)
+	}
+
 	if (t.BaseToken != common.Address{}) {
 		trade["baseToken"] = t.BaseToken.Hex()
 	}
@@ -196,7 +223,7 @@ func (t *Trade) MarshalJSON() ([]byte, error) {
 		trade["makerOrderHash"] = t.MakerOrderHash.Hex()
 	}
 
-	return json.Marshal(trade)
+	return trade
 }
 
 // UnmarshalJSON creates a trade object from a json byte string
@@ -252,6 +279,14 @@ func (t *Trade) UnmarshalJSON(b []byte) error {
 		t.ID = bson.ObjectIdHex(trade["id"].(string))
 	}
 
+	if trade["publicId"] != nil {
+		publicID, err := strconv.ParseInt(trade["publicId"].(string), 10, 64)
+		if err != nil {
+			return err
+		}
+		t.PublicID = publicID
+	}
+
 	if trade["txHash"] != nil {
 		t.TxHash = common.HexToHash(trade["txHash"].(string))
 	}
@@ -305,13 +340,13 @@ func (t *Trade) UnmarshalJSON(b []byte) error {
 }
 
 func (t *Trade) QuoteAmount(p *Pair) *big.Int {
-	pairMultiplier := p.PairMultiplier()
-	return math.Div(math.Mul(t.Amount, t.PricePoint), pairMultiplier)
+	return p.QuoteAmount(t.Amount, t.PricePoint)
 }
 
 func (t *Trade) GetBSON() (interface{}, error) {
 	tr := TradeRecord{
 		ID:             t.ID,
+		PublicID:       t.PublicID,
 		PairName:       t.PairName,
 		Maker:          t.Maker.Hex(),
 		Taker:          t.Taker.Hex(),
@@ -341,6 +376,7 @@ func (t *Trade) GetBSON() (interface{}, error) {
 func (t *Trade) SetBSON(raw bson.Raw) error {
 	decoded := new(struct {
 		ID             bson.ObjectId `json:"id,omitempty" bson:"_id"`
+		PublicID       int64         `json:"publicId" bson:"publicId"`
 		PairName       string        `json:"pairName" bson:"pairName"`
 		Taker          string        `json:"taker" bson:"taker"`
 		Maker          string        `json:"maker" bson:"maker"`
@@ -370,6 +406,7 @@ func (t *Trade) SetBSON(raw bson.Raw) error {
 	}
 
 	t.ID = decoded.ID
+	t.PublicID = decoded.PublicID
 	t.PairName = decoded.PairName
 	t.Taker = common.HexToAddress(decoded.Taker)
 	t.Maker = common.HexToAddress(decoded.Maker)
@@ -470,3 +507,9 @@ type TradeChangeEvent struct {
 	DocumentKey       M           `bson:"documentKey"`
 	UpdateDescription *updateDesc `bson:"updateDescription,omitempty"`
 }
+
+// IsPublicIDAssignment tells if the event is the assignment of the public id of a trade,
+// which is not a change of the trade itself
+func (ev *TradeChangeEvent) IsPublicIDAssignment() bool {
+	return ev.OperationType == OPERATION_TYPE_UPDATE && ev.UpdateDescription.onlyPublicID()
+}
@@ -0,0 +1,70 @@
+package types
+
+import (
+	"encoding/json"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/utils/math"
+)
+
+// TradeVolumeLevel is the volume traded at a price level by the takers of one side
+type TradeVolumeLevel struct {
+	PricePoint *big.Int
+	Side       string
+	Volume     *big.Int
+	Count      int64
+}
+
+// TradeVolumeProfile is the volume traded per price level and side over a window
+type TradeVolumeProfile struct {
+	BaseToken  common.Address      `json:"baseToken"`
+	QuoteToken common.Address      `json:"quoteToken"`
+	From       time.Time           `json:"from"`
+	To         time.Time           `json:"to"`
+	PriceStep  string              `json:"priceStep"`
+	Levels     []*TradeVolumeLevel `json:"levels"`
+}
+
+// MarshalJSON returns the json encoded byte array representing the volume level
+func (l *TradeVolumeLevel) MarshalJSON() ([]byte, error) {
+	level := map[string]interface{}{
+		"side":  l.Side,
+		"count": l.Count,
+	}
+
+	if l.PricePoint != nil {
+		level["pricepoint"] = l.PricePoint.String()
+	}
+
+	if l.Volume != nil {
+		level["volume"] = l.Volume.String()
+	}
+
+	return json.Marshal(level)
+}
+
+// SetBSON decodes a volume level returned by the trade aggregation pipeline
+func (l *TradeVolumeLevel) SetBSON(raw bson.Raw) error {
+	decoded := new(struct {
+		ID struct {
+			PricePoint bson.Decimal128 `bson:"pricepoint"`
+			Side       string          `bson:"side"`
+		} `bson:"_id"`
+		Volume bson.Decimal128 `bson:"volume"`
+		Count  int64           `bson:"count"`
+	})
+
+	err := raw.Unmarshal(decoded)
+	if err != nil {
+		return err
+	}
+
+	l.PricePoint = math.ToBigInt(decoded.ID.PricePoint.String())
+	l.Side = decoded.ID.Side
+	l.Volume = math.ToBigInt(decoded.Volume.String())
+	l.Count = decoded.Count
+	return nil
+}
@@ -0,0 +1,36 @@
+package types
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTradeVolumeLevelBSON(t *testing.T) {
+	price, _ := bson.ParseDecimal128("1500000000000000000")
+	volume, _ := bson.ParseDecimal128("25000000000000000000")
+
+	b, err := bson.Marshal(bson.M{
+		"_id":    bson.M{"pricepoint": price, "side": BUY},
+		"volume": volume,
+		"count":  int64(3),
+	})
+	assert.Nil(t, err)
+
+	l := &TradeVolumeLevel{}
+	err = bson.Unmarshal(b, l)
+	assert.Nil(t, err)
+
+	expected, _ := new(big.Int).SetString("1500000000000000000", 10)
+	assert.Equal(t, expected, l.PricePoint)
+	assert.Equal(t, BUY, l.Side)
+	assert.Equal(t, "25000000000000000000", l.Volume.String())
+	assert.Equal(t, int64(3), l.Count)
+
+	encoded, err := json.Marshal(l)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"pricepoint":"1500000000000000000","side":"BUY","volume":"25000000000000000000","count":3}`, string(encoded))
+}
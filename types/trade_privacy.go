@@ -0,0 +1,57 @@
+package types
+
+import (
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Modes of the maker and taker addresses of the trades of the public feed
+const (
+	TradePrivacyFull      = "full"
+	TradePrivacyTruncated = "truncated"
+	TradePrivacyHidden    = "hidden"
+)
+
+// PublicTrade is a trade of the public feed. Its maker and taker addresses are shown in full, truncated
+// to their first and last 4 hex digits or left out according to the privacy mode
+type PublicTrade struct {
+	*Trade
+	Privacy string
+}
+
+// MarshalJSON returns the json encoding of the trade with the addresses of its privacy mode
+func (t *PublicTrade) MarshalJSON() ([]byte, error) {
+	trade := t.Trade.jsonFields()
+	switch t.Privacy {
+	case TradePrivacyTruncated:
+		trade["maker"] = TruncateAddress(t.Maker)
+		trade["taker"] = TruncateAddress(t.Taker)
+	case TradePrivacyHidden:
+		delete(trade, "maker")
+		delete(trade, "taker")
+	}
+
+	return json.Marshal(trade)
+}
+
+// PublicTrades returns the trades as shown by the public feed in the privacy mode, the trades themselves
+// in the full mode
+func PublicTrades(trades []*Trade, privacy string) interface{} {
+	if privacy == "" || privacy == TradePrivacyFull {
+		return trades
+	}
+
+	res := make([]*PublicTrade, len(trades))
+	for i, t := range trades {
+		res[i] = &PublicTrade{Trade: t, Privacy: privacy}
+	}
+
+	return res
+}
+
+// TruncateAddress returns the address with its first and last 4 hex digits only, as 0x1234...abcd
+func TruncateAddress(a common.Address) string {
+	hex := a.Hex()
+	return hex[:6] + "..." + hex[len(hex)-4:]
+}
@@ -0,0 +1,43 @@
+package types
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublicTrades(t *testing.T) {
+	trade := &Trade{
+		Maker:      common.HexToAddress("0x7a9f3cd060ab180f36c17fe6bdf9974f577d77aa"),
+		Taker:      common.HexToAddress("0xae55690d4b079460e6ac28aaa58c9ec7b73a7485"),
+		PairName:   "ZRX/WETH",
+		PricePoint: big.NewInt(10000),
+		Amount:     big.NewInt(100),
+	}
+
+	trades := []*Trade{trade}
+	assert.Equal(t, trades, PublicTrades(trades, ""))
+	assert.Equal(t, trades, PublicTrades(trades, TradePrivacyFull))
+
+	decode := func(privacy string) map[string]interface{} {
+		b, err := json.Marshal(PublicTrades(trades, privacy))
+		assert.Nil(t, err)
+
+		res := []map[string]interface{}{}
+		assert.Nil(t, json.Unmarshal(b, &res))
+		return res[0]
+	}
+
+	truncated := decode(TradePrivacyTruncated)
+	assert.Equal(t, "0x7a9F...77Aa", truncated["maker"])
+	assert.Equal(t, "0xae55...7485", truncated["taker"])
+	assert.Equal(t, "ZRX/WETH", truncated["pairName"])
+
+	hidden := decode(TradePrivacyHidden)
+	assert.NotContains(t, hidden, "maker")
+	assert.NotContains(t, hidden, "taker")
+	assert.Equal(t, "100", hidden["amount"])
+}
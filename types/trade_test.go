@@ -72,3 +72,47 @@ func TestTradeBSON(t *testing.T) {
 
 	assert.Equal(t, decoded, expected)
 }
+
+func TestTradePublicID(t *testing.T) {
+	trade := &Trade{
+		ID:             bson.NewObjectId(),
+		PublicID:       1234567890123456789,
+		Maker:          common.HexToAddress("0x7a9f3cd060ab180f36c17fe6bdf9974f577d77aa"),
+		Taker:          common.HexToAddress("0xae55690d4b079460e6ac28aaa58c9ec7b73a7485"),
+		BaseToken:      common.HexToAddress("0xe41d2489571d322189246dafa5ebde1f4699f498"),
+		QuoteToken:     common.HexToAddress("0x12459c951127e0c374ff9105dda097662a027093"),
+		MakerOrderHash: common.HexToHash("0x1"),
+		Hash:           common.HexToHash("0x2"),
+		PricePoint:     big.NewInt(10000),
+		Amount:         big.NewInt(100),
+	}
+
+	encoded, err := json.Marshal(trade)
+	assert.Nil(t, err)
+	assert.Contains(t, string(encoded), `"publicId":"1234567890123456789"`)
+
+	decoded := &Trade{}
+	assert.Nil(t, json.Unmarshal(encoded, decoded))
+	assert.Equal(t, trade.PublicID, decoded.PublicID)
+
+	data, err := bson.Marshal(trade)
+	assert.Nil(t, err)
+
+	decoded = &Trade{}
+	assert.Nil(t, bson.Unmarshal(data, decoded))
+	assert.Equal(t, trade.PublicID, decoded.PublicID)
+}
+
+func TestTradeChangeEventPublicIDAssignment(t *testing.T) {
+	ev := &TradeChangeEvent{
+		OperationType:     OPERATION_TYPE_UPDATE,
+		UpdateDescription: &updateDesc{UpdatedFields: map[string]interface{}{"publicId": int64(1)}},
+	}
+	assert.True(t, ev.IsPublicIDAssignment())
+
+	ev.UpdateDescription.UpdatedFields["status"] = TradeStatusSuccess
+	assert.False(t, ev.IsPublicIDAssignment())
+
+	ev = &TradeChangeEvent{OperationType: OPERATION_TYPE_INSERT}
+	assert.False(t, ev.IsPublicIDAssignment())
+}
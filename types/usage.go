@@ -0,0 +1,32 @@
+package types
+
+import "time"
+
+// APIUsage is the number of API requests and websocket messages of a user over the current window.
+// The limits are 0 when not configured
+type APIUsage struct {
+	Key           string    `json:"key"`
+	Requests      uint64    `json:"requests"`
+	Errors        uint64    `json:"errors"`
+	ErrorRate     float64   `json:"errorRate"`
+	WSMessagesIn  uint64    `json:"wsMessagesIn"`
+	WSMessagesOut uint64    `json:"wsMessagesOut"`
+	WindowStart   time.Time `json:"windowStart"`
+	WindowEnd     time.Time `json:"windowEnd"`
+	MaxRequests   int       `json:"maxRequests"`
+	MaxWSMessages int       `json:"maxWsMessages"`
+	// Routes are the calls of the user per route over the window, from the access logs if enabled
+	Routes []*AccessStats `json:"routes,omitempty"`
+}
+
+// APIUsageRollup is the usage of all the users over the current window with the heaviest users
+type APIUsageRollup struct {
+	WindowStart   time.Time   `json:"windowStart"`
+	WindowEnd     time.Time   `json:"windowEnd"`
+	Users         int         `json:"users"`
+	Requests      uint64      `json:"requests"`
+	Errors        uint64      `json:"errors"`
+	WSMessagesIn  uint64      `json:"wsMessagesIn"`
+	WSMessagesOut uint64      `json:"wsMessagesOut"`
+	Top           []*APIUsage `json:"top"`
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/kms"
 )
 
 // Wallet holds both the address and the private key of an ethereum account
@@ -65,11 +66,17 @@ type WalletRecord struct {
 	Operator   bool          `json:"operator" bson:"operator"`
 }
 
+// GetBSON encrypts the private key with the configured key manager
 func (w *Wallet) GetBSON() (interface{}, error) {
+	privateKey, err := kms.EncryptString(hex.EncodeToString(w.PrivateKey.D.Bytes()))
+	if err != nil {
+		return nil, err
+	}
+
 	return WalletRecord{
 		ID:         w.ID,
 		Address:    w.Address.Hex(),
-		PrivateKey: hex.EncodeToString(w.PrivateKey.D.Bytes()),
+		PrivateKey: privateKey,
 		Admin:      w.Admin,
 	}, nil
 }
@@ -84,7 +91,13 @@ func (w *Wallet) SetBSON(raw bson.Raw) error {
 
 	w.ID = decoded.ID
 	w.Address = common.HexToAddress(decoded.Address)
-	w.PrivateKey, err = crypto.HexToECDSA(decoded.PrivateKey)
+	privateKey, err := kms.DecryptString(decoded.PrivateKey)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	w.PrivateKey, err = crypto.HexToECDSA(privateKey)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -30,6 +30,7 @@ const (
 	ORDER_ADDED            = "ORDER_ADDED"
 	ORDER_FILLED           = "ORDER_FILLED"
 	ORDER_PARTIALLY_FILLED = "ORDER_PARTIALLY_FILLED"
+	ORDER_FILLED_WITH_DUST = "ORDER_FILLED_WITH_DUST"
 	ORDER_CANCELLED        = "ORDER_CANCELLED"
 	ORDER_REJECTED         = "ORDER_REJECTED"
 	ERROR_STATUS           = "ERROR"
@@ -119,6 +120,11 @@ type SubscriptionPayload struct {
 	Units        string         `json:"units"`
 	Term         uint64         `json:"term"`
 	LendingToken common.Address `json:"lendingToken,omitempty"`
+	// Timezone aligns the OHLCV intervals on the local boundaries of an IANA timezone or a UTC offset,
+	// it overrides the timezone of the preferences of the connection
+	Timezone string `json:"timezone,omitempty"`
+	// Index is the name of a composite index of the indices channel
+	Index string `json:"index,omitempty"`
 }
 
 /*
@@ -0,0 +1,130 @@
+package usage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils"
+)
+
+var logger = utils.Logger
+
+// Defaults of the access logs
+const (
+	accessLogBatch         = 500
+	accessLogFlushInterval = 10 * time.Second
+)
+
+// AccessLogStore stores the access logs
+type AccessLogStore interface {
+	Create(logs ...*types.AccessLog) error
+}
+
+// AccessLogger samples the HTTP requests and the websocket messages of the API and writes them to
+// the store in batches. The callers are recorded by a hash of their usage key, never by the key itself
+type AccessLogger struct {
+	store      AccessLogStore
+	sampleRate int
+	buffer     []*types.AccessLog
+	mutex      sync.Mutex
+}
+
+var accessLogger *AccessLogger
+var accessLoggerOnce sync.Once
+
+// GetAccessLogger returns the access logger configured from app.Config, it records nothing until
+// its store is set by StartAccessLog
+func GetAccessLogger() *AccessLogger {
+	accessLoggerOnce.Do(func() {
+		accessLogger = NewAccessLogger(app.Config.AccessLog["sample_rate"])
+	})
+
+	return accessLogger
+}
+
+// StartAccessLog sets the store of the access logger and writes its logs every 10 seconds
+func StartAccessLog(store AccessLogStore) {
+	l := GetAccessLogger()
+
+	l.mutex.Lock()
+	l.store = store
+	l.mutex.Unlock()
+
+	go func() {
+		for range time.Tick(accessLogFlushInterval) {
+			l.Flush()
+		}
+	}()
+}
+
+// NewAccessLogger returns a new instance of AccessLogger keeping the percentage of the calls
+// of the sample rate, all of them if it is 0 or above 100
+func NewAccessLogger(sampleRate int) *AccessLogger {
+	if sampleRate <= 0 || sampleRate > 100 {
+		sampleRate = 100
+	}
+
+	return &AccessLogger{sampleRate: sampleRate}
+}
+
+// HashKey returns the hash of a usage key stored in the access logs
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Log records a call of a route by the key if it is sampled
+func (l *AccessLogger) Log(kind, route, method string, status int, latency time.Duration, bytes int64, key string) {
+	if l.sampleRate < 100 && rand.Intn(100) >= l.sampleRate {
+		return
+	}
+
+	entry := &types.AccessLog{
+		Kind:      kind,
+		Route:     route,
+		Method:    method,
+		Status:    status,
+		LatencyMs: float64(latency) / float64(time.Millisecond),
+		Bytes:     bytes,
+		User:      HashKey(key),
+		Weight:    100 / float64(l.sampleRate),
+		CreatedAt: time.Now(),
+	}
+
+	l.mutex.Lock()
+	if l.store == nil {
+		l.mutex.Unlock()
+		return
+	}
+
+	l.buffer = append(l.buffer, entry)
+	full := len(l.buffer) >= accessLogBatch
+	l.mutex.Unlock()
+
+	if full {
+		go l.Flush()
+	}
+}
+
+// Flush writes the buffered logs, they are dropped if the store fails
+func (l *AccessLogger) Flush() {
+	l.mutex.Lock()
+	logs := l.buffer
+	store := l.store
+	l.buffer = nil
+	l.mutex.Unlock()
+
+	if len(logs) == 0 || store == nil {
+		return
+	}
+
+	err := store.Create(logs...)
+	if err != nil {
+		logger.Error("Access logs dropped:", len(logs), err)
+	}
+}
@@ -0,0 +1,61 @@
+package usage
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+type memoryStore struct {
+	logs []*types.AccessLog
+}
+
+func (s *memoryStore) Create(logs ...*types.AccessLog) error {
+	s.logs = append(s.logs, logs...)
+	return nil
+}
+
+func TestHashKey(t *testing.T) {
+	h := HashKey("address:0x0000000000000000000000000000000000000001")
+	assert.Len(t, h, 16)
+	assert.Equal(t, h, HashKey("address:0x0000000000000000000000000000000000000001"))
+	assert.NotEqual(t, h, HashKey("ip:1.2.3.4"))
+}
+
+func TestAccessLogger(t *testing.T) {
+	l := NewAccessLogger(0)
+	l.Log(types.AccessLogHTTP, "/api/pairs", "GET", http.StatusOK, time.Millisecond, 10, "ip:1.2.3.4")
+	assert.Empty(t, l.buffer, "nothing is recorded without a store")
+
+	store := &memoryStore{}
+	l.store = store
+	l.Log(types.AccessLogHTTP, "/api/pairs", "GET", http.StatusOK, 2*time.Millisecond, 10, "ip:1.2.3.4")
+	l.Log(types.AccessLogWS, "orderbook", "SUBSCRIBE", http.StatusOK, time.Millisecond, 20, "ip:1.2.3.4")
+	l.Flush()
+
+	assert.Len(t, store.logs, 2)
+	assert.Empty(t, l.buffer)
+
+	log := store.logs[0]
+	assert.Equal(t, "/api/pairs", log.Route)
+	assert.Equal(t, 2.0, log.LatencyMs)
+	assert.Equal(t, HashKey("ip:1.2.3.4"), log.User)
+	assert.Equal(t, 1.0, log.Weight)
+}
+
+func TestAccessLoggerSampling(t *testing.T) {
+	store := &memoryStore{}
+	l := NewAccessLogger(10)
+	l.store = store
+
+	for i := 0; i < 1000; i++ {
+		l.Log(types.AccessLogHTTP, "/api/pairs", "GET", http.StatusOK, time.Millisecond, 10, "ip:1.2.3.4")
+	}
+	l.Flush()
+
+	assert.True(t, len(store.logs) > 0 && len(store.logs) < 300)
+	assert.Equal(t, 10.0, store.logs[0].Weight)
+}
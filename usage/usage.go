@@ -0,0 +1,211 @@
+// Package usage counts the API requests and websocket messages of each user,
+// identified by its API key, its address or its IP
+package usage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gorilla/mux"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+const defaultWindow = 24 * time.Hour
+
+type counters struct {
+	requests      uint64
+	errors        uint64
+	wsMessagesIn  uint64
+	wsMessagesOut uint64
+}
+
+// Tracker counts the usage of each key over a window, the counters are reset when the window ends
+type Tracker struct {
+	usage         map[string]*counters
+	mutex         sync.Mutex
+	window        time.Duration
+	windowStart   time.Time
+	maxRequests   int
+	maxWSMessages int
+}
+
+var tracker *Tracker
+var trackerOnce sync.Once
+
+// GetTracker returns the usage tracker configured from app.Config
+func GetTracker() *Tracker {
+	trackerOnce.Do(func() {
+		window := defaultWindow
+		if v := app.Config.Usage["window"]; v > 0 {
+			window = time.Duration(v) * time.Second
+		}
+
+		tracker = NewTracker(window, app.Config.Usage["max_requests"], app.Config.Usage["max_ws_messages"])
+	})
+
+	return tracker
+}
+
+// NewTracker returns a new instance of Tracker. The limits are reported to the users, 0 means no limit
+func NewTracker(window time.Duration, maxRequests, maxWSMessages int) *Tracker {
+	return &Tracker{
+		usage:         make(map[string]*counters),
+		window:        window,
+		windowStart:   time.Now(),
+		maxRequests:   maxRequests,
+		maxWSMessages: maxWSMessages,
+	}
+}
+
+// Key returns the usage key of the caller of a request: its API key, the address of its
+// signature public key, the address of the request or its IP
+func Key(r *http.Request) string {
+	if key := r.Header.Get("X-Api-Key"); key != "" {
+		sum := sha256.Sum256([]byte(key))
+		return "apikey:" + hex.EncodeToString(sum[:4])
+	}
+
+	if pubkey := r.Header.Get("Pubkey"); pubkey != "" {
+		pub, err := crypto.UnmarshalPubkey(common.FromHex(pubkey))
+		if err == nil {
+			return "address:" + crypto.PubkeyToAddress(*pub).Hex()
+		}
+	}
+
+	address := mux.Vars(r)["address"]
+	if address == "" {
+		address = r.URL.Query().Get("address")
+	}
+
+	if common.IsHexAddress(address) {
+		return "address:" + common.HexToAddress(address).Hex()
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	return "ip:" + host
+}
+
+// Request records a request of the key, status is the HTTP status of the response
+func (t *Tracker) Request(key string, status int) {
+	t.add(key, func(c *counters) {
+		c.requests++
+		if status >= http.StatusBadRequest {
+			c.errors++
+		}
+	})
+}
+
+// WSMessageIn records a websocket message received from the key
+func (t *Tracker) WSMessageIn(key string) {
+	t.add(key, func(c *counters) { c.wsMessagesIn++ })
+}
+
+// WSMessageOut records a websocket message sent to the key
+func (t *Tracker) WSMessageOut(key string) {
+	t.add(key, func(c *counters) { c.wsMessagesOut++ })
+}
+
+// Get returns the usage of a key in the current window
+func (t *Tracker) Get(key string) *types.APIUsage {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.rotate()
+
+	c := t.usage[key]
+	if c == nil {
+		c = &counters{}
+	}
+
+	return t.toAPIUsage(key, c)
+}
+
+// GetRollup returns the total usage of the current window with the top users by number of requests
+func (t *Tracker) GetRollup(top int) *types.APIUsageRollup {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.rotate()
+
+	res := &types.APIUsageRollup{
+		WindowStart: t.windowStart,
+		WindowEnd:   t.windowStart.Add(t.window),
+		Users:       len(t.usage),
+		Top:         []*types.APIUsage{},
+	}
+
+	for k, c := range t.usage {
+		res.Requests += c.requests
+		res.Errors += c.errors
+		res.WSMessagesIn += c.wsMessagesIn
+		res.WSMessagesOut += c.wsMessagesOut
+		res.Top = append(res.Top, t.toAPIUsage(k, c))
+	}
+
+	sort.Slice(res.Top, func(i, j int) bool {
+		return res.Top[i].Requests+res.Top[i].WSMessagesOut > res.Top[j].Requests+res.Top[j].WSMessagesOut
+	})
+
+	if len(res.Top) > top {
+		res.Top = res.Top[:top]
+	}
+
+	return res
+}
+
+func (t *Tracker) add(key string, fn func(c *counters)) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.rotate()
+
+	c := t.usage[key]
+	if c == nil {
+		c = &counters{}
+		t.usage[key] = c
+	}
+
+	fn(c)
+}
+
+// rotate resets the counters when the window ends, the caller holds the mutex
+func (t *Tracker) rotate() {
+	if time.Since(t.windowStart) < t.window {
+		return
+	}
+
+	t.usage = make(map[string]*counters)
+	t.windowStart = time.Now()
+}
+
+func (t *Tracker) toAPIUsage(key string, c *counters) *types.APIUsage {
+	u := &types.APIUsage{
+		Key:           key,
+		Requests:      c.requests,
+		Errors:        c.errors,
+		WSMessagesIn:  c.wsMessagesIn,
+		WSMessagesOut: c.wsMessagesOut,
+		WindowStart:   t.windowStart,
+		WindowEnd:     t.windowStart.Add(t.window),
+		MaxRequests:   t.maxRequests,
+		MaxWSMessages: t.maxWSMessages,
+	}
+
+	if c.requests > 0 {
+		u.ErrorRate = float64(c.errors) / float64(c.requests)
+	}
+
+	return u
+}
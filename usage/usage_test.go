@@ -0,0 +1,51 @@
+package usage
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKey(t *testing.T) {
+	r := httptest.NewRequest("GET", "/api/account/usage?address=0x0000000000000000000000000000000000000001", nil)
+	r.RemoteAddr = "1.2.3.4:5678"
+	assert.Equal(t, "address:0x0000000000000000000000000000000000000001", Key(r))
+
+	r = httptest.NewRequest("GET", "/api/account/usage", nil)
+	r.RemoteAddr = "1.2.3.4:5678"
+	assert.Equal(t, "ip:1.2.3.4", Key(r))
+
+	r.Header.Set("X-Api-Key", "secret")
+	assert.Equal(t, "apikey:2bb80d53", Key(r))
+}
+
+func TestTracker(t *testing.T) {
+	tr := NewTracker(time.Hour, 100, 0)
+
+	tr.Request("a", http.StatusOK)
+	tr.Request("a", http.StatusBadRequest)
+	tr.Request("b", http.StatusOK)
+	tr.WSMessageIn("a")
+	tr.WSMessageOut("a")
+	tr.WSMessageOut("a")
+
+	u := tr.Get("a")
+	assert.Equal(t, uint64(2), u.Requests)
+	assert.Equal(t, uint64(1), u.Errors)
+	assert.Equal(t, 0.5, u.ErrorRate)
+	assert.Equal(t, uint64(1), u.WSMessagesIn)
+	assert.Equal(t, uint64(2), u.WSMessagesOut)
+	assert.Equal(t, 100, u.MaxRequests)
+
+	rollup := tr.GetRollup(1)
+	assert.Equal(t, 2, rollup.Users)
+	assert.Equal(t, uint64(3), rollup.Requests)
+	assert.Equal(t, 1, len(rollup.Top))
+	assert.Equal(t, "a", rollup.Top[0].Key)
+
+	tr.windowStart = time.Now().Add(-2 * time.Hour)
+	assert.Equal(t, uint64(0), tr.Get("a").Requests)
+}
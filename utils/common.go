@@ -79,6 +79,17 @@ func GetPriceBoardChannelID(bt, qt common.Address) string {
 func GetMarketsChannelID(channel string) string {
 	return strings.ToLower(channel)
 }
+
+// GetBlockChannelID format block channel id
+func GetBlockChannelID(channel string) string {
+	return strings.ToLower(channel)
+}
+
+// GetLendingLiquidationChannelID format lending liquidation channel id
+func GetLendingLiquidationChannelID(channel string) string {
+	return strings.ToLower(channel)
+}
+
 func GetLendingPairName(term uint64, lendingTokenName string) string {
 	return strings.ToUpper(fmt.Sprintf("%s::%s", strconv.FormatUint(term, 10), lendingTokenName))
 }
@@ -208,6 +219,17 @@ func GetModTime(ts, interval int64, unit string) (int64, int64) {
 	return modTime, intervalInSeconds
 }
 
+// GetModTimeIn rounds the time like GetModTime with the intervals starting at the local
+// boundaries of loc, e.g. the days start at local midnight
+func GetModTimeIn(ts, interval int64, unit string, loc *time.Location) (int64, int64) {
+	_, offset := time.Unix(ts, 0).In(loc).Zone()
+	modTime, intervalInSeconds := GetModTime(ts+int64(offset), interval, unit)
+
+	// the offset at the start of the interval differs from the current one after a daylight saving change
+	_, offset = time.Unix(modTime-int64(offset), 0).In(loc).Zone()
+	return modTime - int64(offset), intervalInSeconds
+}
+
 // UnitToSecond time uint to second
 func UnitToSecond(interval int64, unit string) int64 {
 	var intervalInSeconds int64
@@ -240,3 +262,7 @@ func ToBigInt(s string) *big.Int {
 	res.SetString(s, 10)
 	return res
 }
+
+func GetMicrostructureChannelID(bt, qt common.Address) string {
+	return strings.ToLower(fmt.Sprintf("%s::%s", bt.Hex(), qt.Hex()))
+}
@@ -0,0 +1,90 @@
+package httputils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+)
+
+// MaxJSONDepth is the maximum nesting of objects and arrays accepted in a request body
+const MaxJSONDepth = 32
+
+var (
+	// ErrJSONTooDeep is returned when a payload is nested deeper than MaxJSONDepth
+	ErrJSONTooDeep = errors.New("JSON payload is too deeply nested")
+	// ErrTrailingData is returned when a payload holds more than one JSON value
+	ErrTrailingData = errors.New("Unexpected data after JSON payload")
+)
+
+// DecodeJSON decodes the request body into v. Payloads nested deeper than
+// MaxJSONDepth and fields that do not exist in v are rejected.
+// The body size is bounded by the LimitBody middleware
+func DecodeJSON(r *http.Request, v interface{}) error {
+	defer r.Body.Close()
+
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+
+	return UnmarshalJSON(b, v)
+}
+
+// UnmarshalJSON decodes b into v with the same rules as DecodeJSON
+func UnmarshalJSON(b []byte, v interface{}) error {
+	if err := CheckJSONDepth(b, MaxJSONDepth); err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(v)
+	if err != nil {
+		return err
+	}
+
+	if decoder.More() {
+		return ErrTrailingData
+	}
+
+	return nil
+}
+
+// CheckJSONDepth returns ErrJSONTooDeep if b nests objects or arrays deeper than max
+func CheckJSONDepth(b []byte, max int) error {
+	depth := 0
+	inString := false
+	escaped := false
+
+	for _, c := range b {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+			if depth > max {
+				return ErrJSONTooDeep
+			}
+		case '}', ']':
+			depth--
+		}
+	}
+
+	return nil
+}
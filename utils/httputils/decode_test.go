@@ -0,0 +1,60 @@
+package httputils
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+func TestUnmarshalJSON(t *testing.T) {
+	type payload struct {
+		Name string `json:"name"`
+	}
+
+	p := &payload{}
+	assert.Nil(t, UnmarshalJSON([]byte(`{"name":"{[\"}"}`), p))
+	assert.Equal(t, `{["}`, p.Name)
+
+	assert.NotNil(t, UnmarshalJSON([]byte(`{"name":"a","unknown":1}`), p))
+	assert.Equal(t, ErrTrailingData, UnmarshalJSON([]byte(`{"name":"a"}{"name":"b"}`), p))
+
+	deep := strings.Repeat("[", MaxJSONDepth+1) + strings.Repeat("]", MaxJSONDepth+1)
+	assert.Equal(t, ErrJSONTooDeep, UnmarshalJSON([]byte(deep), &[]interface{}{}))
+}
+
+func TestDecodeJSONOrderUnknownField(t *testing.T) {
+	order := `{
+		"userAddress": "0x14d281013d8ee8ccfa0eca87524e5b3cfa6152ba",
+		"exchangeAddress": "0xae55690d4b079460e6ac28aaa58c9ec7b73a7485",
+		"baseToken": "0x12459c951127e0c374ff9105dda097662a027093",
+		"quoteToken": "0xe41d2489571d322189246dafa5ebde1f4699f498",
+		"amount": "100",
+		"pricepoint": "100",
+		"side": "BUY",
+		"type": "LO",
+		"status": "NEW",
+		"nonce": "1"%s
+	}`
+
+	var o *types.Order
+	r := httptest.NewRequest("POST", "/api/orders", strings.NewReader(fmt.Sprintf(order, "")))
+	assert.Nil(t, DecodeJSON(r, &o))
+	assert.Equal(t, "BUY", o.Side)
+
+	// the custom decoder of the order rejects the fields it does not know, like the strict decoder
+	o = nil
+	r = httptest.NewRequest("POST", "/api/orders", strings.NewReader(fmt.Sprintf(order, `, "makeFee": "0"`)))
+	assert.EqualError(t, DecodeJSON(r, &o), `json: unknown field "makeFee"`)
+
+	oc := &types.OrderCancel{}
+	r = httptest.NewRequest("POST", "/api/orders/cancel", strings.NewReader(`{"orderHash": "0x1", "unknown": 1}`))
+	assert.EqualError(t, DecodeJSON(r, &oc), `json: unknown field "unknown"`)
+
+	lo := &types.LendingOrder{}
+	r = httptest.NewRequest("POST", "/api/lending/orders", strings.NewReader(`{"term": "86400", "unknown": 1}`))
+	assert.EqualError(t, DecodeJSON(r, &lo), `json: unknown field "unknown"`)
+}
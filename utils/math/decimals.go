@@ -0,0 +1,52 @@
+package math
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Pow10 returns 10^decimals, the number of base units of a whole token with these decimals
+func Pow10(decimals int) *big.Int {
+	return Exp(big.NewInt(10), big.NewInt(int64(decimals)))
+}
+
+// ToUnits converts an amount in base units to whole tokens with these decimals
+func ToUnits(value *big.Int, decimals int) *big.Float {
+	if value == nil {
+		return big.NewFloat(0)
+	}
+
+	return new(big.Float).Quo(new(big.Float).SetInt(value), new(big.Float).SetInt(Pow10(decimals)))
+}
+
+// FromUnits converts an amount of whole tokens with these decimals to base units, truncated
+func FromUnits(value *big.Float, decimals int) *big.Int {
+	res, _ := new(big.Float).Mul(value, new(big.Float).SetInt(Pow10(decimals))).Int(nil)
+	return res
+}
+
+// FormatUnits formats an amount in base units as an exact decimal number of whole tokens
+// with these decimals, without trailing zeros
+func FormatUnits(value *big.Int, decimals int) string {
+	if value == nil {
+		return "0"
+	}
+
+	if decimals <= 0 {
+		return new(big.Int).Mul(value, Pow10(-decimals)).String()
+	}
+
+	q, r := new(big.Int).QuoRem(new(big.Int).Abs(value), Pow10(decimals), new(big.Int))
+	res := q.String()
+	if r.Sign() != 0 {
+		frac := strings.TrimRight(fmt.Sprintf("%0*s", decimals, r.String()), "0")
+		res += "." + frac
+	}
+
+	if value.Sign() < 0 {
+		res = "-" + res
+	}
+
+	return res
+}
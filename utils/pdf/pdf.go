@@ -0,0 +1,127 @@
+// Package pdf writes plain text documents in the PDF format.
+// It only supports a monospaced font and an A4 layout, which is enough for the generated reports
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Layout of the pages, in points
+const (
+	pageWidth    = 595
+	pageHeight   = 842
+	margin       = 40
+	fontSize     = 9
+	lineHeight   = 11
+	charsPerLine = (pageWidth - 2*margin) * 10 / (fontSize * 6)
+	linesPerPage = (pageHeight - 2*margin) / lineHeight
+)
+
+// Render returns a PDF document with the title followed by the lines of text.
+// The long lines are wrapped and the document is split in as many pages as needed
+func Render(title string, lines []string) []byte {
+	wrapped := []string{title, ""}
+	for _, l := range lines {
+		wrapped = append(wrapped, wrap(l)...)
+	}
+
+	pages := [][]string{}
+	for len(wrapped) > 0 {
+		n := linesPerPage
+		if n > len(wrapped) {
+			n = len(wrapped)
+		}
+
+		pages = append(pages, wrapped[:n])
+		wrapped = wrapped[n:]
+	}
+
+	// objects 1 to 3 are the catalog, the page tree and the font,
+	// then each page is followed by its content stream
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier >>",
+	}
+
+	kids := []string{}
+	for _, p := range pages {
+		page := len(objects) + 1
+		kids = append(kids, fmt.Sprintf("%d 0 R", page))
+
+		objects = append(objects, fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, page+1,
+		))
+
+		content := pageContent(p)
+		objects = append(objects, fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content))
+	}
+
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages))
+
+	buf := &bytes.Buffer{}
+	buf.WriteString("%PDF-1.4\n")
+
+	offsets := make([]int, len(objects))
+	for i, o := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(buf, "%d 0 obj\n%s\nendobj\n", i+1, o)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(buf, "%010d 00000 n \n", offset)
+	}
+
+	fmt.Fprintf(buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
+
+func pageContent(lines []string) string {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "BT\n/F1 %d Tf\n%d TL\n%d %d Td\n", fontSize, lineHeight, margin, pageHeight-margin)
+	for _, l := range lines {
+		fmt.Fprintf(b, "(%s) Tj T*\n", escape(l))
+	}
+
+	b.WriteString("ET")
+	return b.String()
+}
+
+// wrap splits a line in chunks that fit the width of the page
+func wrap(line string) []string {
+	runes := []rune(line)
+	if len(runes) <= charsPerLine {
+		return []string{line}
+	}
+
+	res := []string{}
+	for len(runes) > charsPerLine {
+		res = append(res, string(runes[:charsPerLine]))
+		runes = runes[charsPerLine:]
+	}
+
+	return append(res, string(runes))
+}
+
+// escape escapes the special characters of a PDF string, the non ASCII characters are replaced
+func escape(s string) string {
+	b := &strings.Builder{}
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case r < 32 || r > 126:
+			b.WriteRune('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
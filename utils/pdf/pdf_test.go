@@ -0,0 +1,37 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	lines := []string{}
+	for i := 0; i < 100; i++ {
+		lines = append(lines, fmt.Sprintf("line %d (escaped)", i))
+	}
+
+	doc := Render("Report", lines)
+
+	assert.True(t, bytes.HasPrefix(doc, []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(doc, []byte("%%EOF\n")))
+	assert.Contains(t, string(doc), "/Count 2")
+	assert.Contains(t, string(doc), `(line 99 \(escaped\)) Tj`)
+}
+
+func TestWrap(t *testing.T) {
+	line := strings.Repeat("a", charsPerLine*2+1)
+
+	res := wrap(line)
+	assert.Equal(t, 3, len(res))
+	assert.Equal(t, "a", res[2])
+	assert.Equal(t, []string{"short"}, wrap("short"))
+}
+
+func TestEscape(t *testing.T) {
+	assert.Equal(t, `a\\b\(c\)?`, escape("a\\b(c)é"))
+}
@@ -0,0 +1,92 @@
+// Package snowflake generates the public ids of the orders and trades.
+// An id is a positive int64 made of the milliseconds since Epoch (41 bits), the node
+// number (10 bits) and a sequence number (12 bits), the ids of a node are strictly
+// increasing and the ids of different nodes never collide
+package snowflake
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	nodeBits     = 10
+	sequenceBits = 12
+
+	// MaxNode is the highest node number
+	MaxNode = 1<<nodeBits - 1
+
+	maxSequence = 1<<sequenceBits - 1
+	timeShift   = nodeBits + sequenceBits
+)
+
+// Epoch is the origin of the timestamps of the ids, 2019-01-01 UTC
+var Epoch = time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Generator issues the ids of a node
+type Generator struct {
+	mutex    sync.Mutex
+	node     int64
+	last     int64
+	sequence int64
+	now      func() time.Time
+}
+
+// NewGenerator returns a generator for a node number between 0 and MaxNode
+func NewGenerator(node int) (*Generator, error) {
+	if node < 0 || node > MaxNode {
+		return nil, fmt.Errorf("snowflake node must be between 0 and %d, got %d", MaxNode, node)
+	}
+
+	return &Generator{node: int64(node), now: time.Now}, nil
+}
+
+// Next returns a new id, greater than all the ids issued before by the generator.
+// When the clock goes backwards or the sequence of a millisecond is exhausted the
+// timestamp of the last id is carried forward instead of waiting
+func (g *Generator) Next() int64 {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	ts := g.now().Sub(Epoch).Nanoseconds() / int64(time.Millisecond)
+	if ts > g.last {
+		g.last = ts
+		g.sequence = 0
+	} else if g.sequence < maxSequence {
+		g.sequence++
+	} else {
+		g.last++
+		g.sequence = 0
+	}
+
+	return g.last<<timeShift | g.node<<sequenceBits | g.sequence
+}
+
+// Time returns the time an id was issued at, to the millisecond
+func Time(id int64) time.Time {
+	return Epoch.Add(time.Duration(id>>timeShift) * time.Millisecond)
+}
+
+// Node returns the node number that issued an id
+func Node(id int64) int {
+	return int(id >> sequenceBits & MaxNode)
+}
+
+var generator, _ = NewGenerator(0)
+
+// Init sets the node number of the ids returned by Next, it is called once at startup
+func Init(node int) error {
+	g, err := NewGenerator(node)
+	if err != nil {
+		return err
+	}
+
+	generator = g
+	return nil
+}
+
+// Next returns a new id of the node set by Init
+func Next() int64 {
+	return generator.Next()
+}
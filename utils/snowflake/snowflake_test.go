@@ -0,0 +1,51 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeneratorNext(t *testing.T) {
+	g, err := NewGenerator(7)
+	assert.Nil(t, err)
+
+	now := Epoch.Add(time.Hour)
+	g.now = func() time.Time { return now }
+
+	first := g.Next()
+	assert.Equal(t, now, Time(first))
+	assert.Equal(t, 7, Node(first))
+
+	last := first
+	for i := 0; i < 2*maxSequence; i++ {
+		id := g.Next()
+		assert.True(t, id > last)
+		last = id
+	}
+
+	// the clock going backwards does not break the ordering
+	now = now.Add(-time.Minute)
+	assert.True(t, g.Next() > last)
+}
+
+func TestGeneratorNodes(t *testing.T) {
+	a, _ := NewGenerator(1)
+	b, _ := NewGenerator(2)
+
+	now := func() time.Time { return Epoch.Add(time.Second) }
+	a.now, b.now = now, now
+
+	assert.NotEqual(t, a.Next(), b.Next())
+}
+
+func TestNewGeneratorInvalidNode(t *testing.T) {
+	_, err := NewGenerator(-1)
+	assert.NotNil(t, err)
+
+	_, err = NewGenerator(MaxNode + 1)
+	assert.NotNil(t, err)
+
+	assert.NotNil(t, Init(MaxNode+1))
+}
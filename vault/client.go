@@ -0,0 +1,121 @@
+// Package vault is a minimal client of the HashiCorp Vault HTTP API
+package vault
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const requestTimeout = 10 * time.Second
+
+// Response is the envelope of the Vault API responses
+type Response struct {
+	LeaseID       string                 `json:"lease_id"`
+	LeaseDuration int64                  `json:"lease_duration"`
+	Renewable     bool                   `json:"renewable"`
+	Data          map[string]interface{} `json:"data"`
+	Auth          *Auth                  `json:"auth"`
+	Errors        []string               `json:"errors"`
+}
+
+// Auth holds the token returned by the login and renew endpoints
+type Auth struct {
+	ClientToken   string `json:"client_token"`
+	LeaseDuration int64  `json:"lease_duration"`
+	Renewable     bool   `json:"renewable"`
+}
+
+// Client sends authenticated requests to a Vault server
+type Client struct {
+	addr  string
+	token string
+	http  *http.Client
+	mutex sync.RWMutex
+}
+
+// NewClient returns a new instance of Client
+func NewClient(addr, token string) *Client {
+	return &Client{
+		addr:  strings.TrimRight(addr, "/"),
+		token: token,
+		http:  &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// SetToken replaces the token used to authenticate requests
+func (c *Client) SetToken(token string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.token = token
+}
+
+// Token returns the token used to authenticate requests
+func (c *Client) Token() string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.token
+}
+
+// Read sends a GET request to the given path, e.g. "secret/data/tomox"
+func (c *Client) Read(path string) (*Response, error) {
+	return c.do("GET", path, nil)
+}
+
+// Write sends a POST request with a JSON body to the given path
+func (c *Client) Write(path string, data interface{}) (*Response, error) {
+	return c.do("POST", path, data)
+}
+
+func (c *Client) do(method, path string, data interface{}) (*Response, error) {
+	var body []byte
+	if data != nil {
+		b, err := json.Marshal(data)
+		if err != nil {
+			return nil, err
+		}
+
+		body = b
+	}
+
+	url := fmt.Sprintf("%s/v1/%s", c.addr, strings.TrimLeft(path, "/"))
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if token := c.Token(); token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Response{}
+	if len(b) > 0 {
+		if err := json.Unmarshal(b, res); err != nil {
+			return nil, err
+		}
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("vault: %s %s returned %d: %s", method, path, resp.StatusCode, strings.Join(res.Errors, ", "))
+	}
+
+	return res, nil
+}
@@ -0,0 +1,85 @@
+package ws
+
+import (
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+var blockSocket *BlockSocket
+
+// BlockSocket holds the map of subscriptions subscribed to blocks channels
+// corresponding to the key/event they have subscribed to.
+type BlockSocket struct {
+	topics *Broker
+}
+
+func NewBlockSocket() *BlockSocket {
+	return &BlockSocket{
+		topics: NewBroker(),
+	}
+}
+
+// GetBlockSocket return singleton instance of BlockSocket type struct
+func GetBlockSocket() *BlockSocket {
+	if blockSocket == nil {
+		blockSocket = NewBlockSocket()
+	}
+	return blockSocket
+}
+
+// Subscribe handles the subscription of connection to get
+// streaming data over the socker for new blocks.
+func (s *BlockSocket) Subscribe(channelID string, c *Client) error {
+	return s.topics.Subscribe(channelID, c)
+}
+
+// UnsubscribeHandler unsubscribes a connection from a certain blocks channel id
+func (s *BlockSocket) UnsubscribeChannelHandler(channelID string) func(c *Client) {
+	return func(c *Client) {
+		s.UnsubscribeChannel(channelID, c)
+	}
+}
+
+func (s *BlockSocket) UnsubscribeHandler() func(c *Client) {
+	return func(c *Client) {
+		s.Unsubscribe(c)
+	}
+}
+
+// Unsubscribe removes a websocket connection from the blocks channel updates
+func (s *BlockSocket) UnsubscribeChannel(channelID string, c *Client) {
+	s.topics.Unsubscribe(channelID, c)
+}
+
+func (s *BlockSocket) Unsubscribe(c *Client) {
+	s.topics.UnsubscribeAll(c)
+}
+
+// BroadcastMessage streams message to all the subscriptions subscribed to the blocks channel
+func (s *BlockSocket) BroadcastMessage(channelID string, p interface{}) error {
+	return s.topics.Publish(channelID, BlockChannel, types.UPDATE, p)
+}
+
+// BroadcastReorg streams a chain reorganization to all the subscriptions of the blocks channel
+func (s *BlockSocket) BroadcastReorg(channelID string, r *types.Reorg) error {
+	return s.topics.Publish(channelID, BlockChannel, types.REORG, r)
+}
+
+// SendMessage sends a websocket message on the blocks channel
+func (s *BlockSocket) SendMessage(c *Client, msgType types.SubscriptionEvent, p interface{}) {
+	c.SendMessage(BlockChannel, msgType, p)
+}
+
+// SendInitMessage sends INIT message on blocks channel on subscription event
+func (s *BlockSocket) SendInitMessage(c *Client, data interface{}) {
+	c.SendMessage(BlockChannel, types.INIT, data)
+}
+
+// SendUpdateMessage sends UPDATE message on blocks channel as new data is created
+func (s *BlockSocket) SendUpdateMessage(c *Client, data interface{}) {
+	c.SendMessage(BlockChannel, types.UPDATE, data)
+}
+
+// SendErrorMessage sends error message on blocks channel
+func (s *BlockSocket) SendErrorMessage(c *Client, data interface{}) {
+	c.SendMessage(BlockChannel, types.ERROR, data)
+}
@@ -0,0 +1,294 @@
+package ws
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/tomochain/tomox-sdk/errors"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+const (
+	// brokerShards is the number of independently locked topic registries of a broker
+	brokerShards = 32
+
+	// fanoutWorkers is the number of workers writing the broadcast messages to the clients
+	fanoutWorkers = 64
+
+	// fanoutQueueSize is the number of messages a fanout worker holds before the broadcasts block
+	fanoutQueueSize = 1024
+)
+
+// Broker holds the subscribers of the topics (channel ids) of a socket.
+// The topics are spread over shards locked independently so that the subscriptions
+// to a pair do not contend with the broadcasts to the other pairs. The subscribers
+// of a topic are held per fanout worker, a broadcast encodes its message once and
+// hands each worker the list of its subscribers
+type Broker struct {
+	shards [brokerShards]*brokerShard
+}
+
+type brokerShard struct {
+	mutex  sync.RWMutex
+	topics map[string]*topic
+	// clients are the topics of this shard subscribed by each client
+	clients map[*Client]map[string]bool
+}
+
+// topic holds the subscribers of a topic by fanout worker, the lists are copied on write
+// so that a broadcast reads them without holding the lock
+type topic struct {
+	subscribers [fanoutWorkers][]*Client
+	count       int
+}
+
+// NewBroker returns an empty broker
+func NewBroker() *Broker {
+	b := &Broker{}
+	for i := range b.shards {
+		b.shards[i] = &brokerShard{
+			topics:  make(map[string]*topic),
+			clients: make(map[*Client]map[string]bool),
+		}
+	}
+
+	return b
+}
+
+func (b *Broker) shard(id string) *brokerShard {
+	h := fnv.New32a()
+	h.Write([]byte(id))
+	return b.shards[h.Sum32()%brokerShards]
+}
+
+// Subscribe adds a client to the subscribers of a topic
+func (b *Broker) Subscribe(id string, c *Client) error {
+	if c == nil {
+		return errors.New("No connection found")
+	}
+
+	s := b.shard(id)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.clients[c] == nil {
+		s.clients[c] = make(map[string]bool)
+	}
+
+	if s.clients[c][id] {
+		return nil
+	}
+
+	s.clients[c][id] = true
+
+	t := s.topics[id]
+	if t == nil {
+		t = &topic{}
+		s.topics[id] = t
+	}
+
+	w := c.worker()
+	subscribers := make([]*Client, len(t.subscribers[w]), len(t.subscribers[w])+1)
+	copy(subscribers, t.subscribers[w])
+	t.subscribers[w] = append(subscribers, c)
+	t.count++
+
+	return nil
+}
+
+// Unsubscribe removes a client from the subscribers of a topic
+func (b *Broker) Unsubscribe(id string, c *Client) {
+	s := b.shard(id)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.unsubscribe(id, c)
+}
+
+// UnsubscribeAll removes a client from the subscribers of all the topics
+func (b *Broker) UnsubscribeAll(c *Client) {
+	for _, s := range b.shards {
+		s.mutex.Lock()
+		for id := range s.clients[c] {
+			s.unsubscribe(id, c)
+		}
+		s.mutex.Unlock()
+	}
+}
+
+// unsubscribe removes a client from a topic, the caller holds the shard lock
+func (s *brokerShard) unsubscribe(id string, c *Client) {
+	if !s.clients[c][id] {
+		return
+	}
+
+	delete(s.clients[c], id)
+	if len(s.clients[c]) == 0 {
+		delete(s.clients, c)
+	}
+
+	t := s.topics[id]
+	w := c.worker()
+
+	subscribers := make([]*Client, 0, len(t.subscribers[w]))
+	for _, sub := range t.subscribers[w] {
+		if sub != c {
+			subscribers = append(subscribers, sub)
+		}
+	}
+
+	t.subscribers[w] = subscribers
+	t.count--
+
+	if t.count == 0 {
+		delete(s.topics, id)
+	}
+}
+
+// Count returns the number of subscribers of a topic
+func (b *Broker) Count(id string) int {
+	s := b.shard(id)
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if t := s.topics[id]; t != nil {
+		return t.count
+	}
+
+	return 0
+}
+
+// Subscribers returns the subscribers of a topic
+func (b *Broker) Subscribers(id string) []*Client {
+	s := b.shard(id)
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	t := s.topics[id]
+	if t == nil {
+		return nil
+	}
+
+	res := make([]*Client, 0, t.count)
+	for _, subscribers := range t.subscribers {
+		res = append(res, subscribers...)
+	}
+
+	return res
+}
+
+// Publish sends a message on a channel to the subscribers of a topic. The message is encoded once
+// and written by the fanout workers, the messages of a client are written in the order they were published
+func (b *Broker) Publish(id, channel string, msgType types.SubscriptionEvent, payload interface{}) error {
+	s := b.shard(id)
+	s.mutex.RLock()
+	t := s.topics[id]
+	var subscribers [fanoutWorkers][]*Client
+	if t != nil {
+		subscribers = t.subscribers
+	}
+	s.mutex.RUnlock()
+
+	if t == nil {
+		return nil
+	}
+
+	m := types.WebsocketMessage{
+		Channel: channel,
+		Event: types.WebsocketEvent{
+			Type:    msgType,
+			Payload: payload,
+		},
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	pm, err := websocket.NewPreparedMessage(websocket.TextMessage, data)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	published := time.Now()
+	pool := getFanout()
+	for w, clients := range subscribers {
+		if len(clients) > 0 {
+			pool.queues[w] <- &fanoutJob{
+				clients:   clients,
+				message:   pm,
+				data:      data,
+				channel:   channel,
+				msgType:   msgType,
+				published: published,
+			}
+		}
+	}
+
+	return nil
+}
+
+type fanoutJob struct {
+	clients   []*Client
+	message   *websocket.PreparedMessage
+	data      []byte
+	channel   string
+	msgType   types.SubscriptionEvent
+	published time.Time
+}
+
+// fanout is the pool of workers writing the broadcast messages, a client is always
+// served by the same worker so that its messages keep their order
+type fanout struct {
+	queues [fanoutWorkers]chan *fanoutJob
+}
+
+var (
+	fanoutPool *fanout
+	fanoutOnce sync.Once
+)
+
+// deliver writes a broadcast message to a client, after the market data delay if the client
+// is not licensed. It is replaced in the tests
+var deliver = func(c *Client, job *fanoutJob) {
+	if d := c.delay(job.channel, job.msgType); d > 0 {
+		c.sendLater(job.message, job.published.Add(d))
+		return
+	}
+
+	c.writePreparedMessage(job.message)
+}
+
+func getFanout() *fanout {
+	fanoutOnce.Do(func() {
+		fanoutPool = &fanout{}
+		for i := range fanoutPool.queues {
+			queue := make(chan *fanoutJob, fanoutQueueSize)
+			fanoutPool.queues[i] = queue
+
+			go func() {
+				for job := range queue {
+					for _, c := range job.clients {
+						deliver(c, job)
+					}
+				}
+			}()
+		}
+	})
+
+	return fanoutPool
+}
+
+// clientSeq numbers the clients to spread them over the fanout workers
+var clientSeq uint64
+
+func nextClientSeq() uint64 {
+	return atomic.AddUint64(&clientSeq, 1)
+}
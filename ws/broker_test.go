@@ -0,0 +1,145 @@
+package ws
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// recordDeliveries replaces the delivery of the broadcast messages for the duration of a test
+func recordDeliveries(fn func(c *Client, job *fanoutJob)) func() {
+	previous := deliver
+	deliver = fn
+	return func() { deliver = previous }
+}
+
+func newTestClients(n int) []*Client {
+	clients := make([]*Client, n)
+	for i := range clients {
+		clients[i] = &Client{seq: nextClientSeq()}
+	}
+
+	return clients
+}
+
+func TestBrokerSubscriptions(t *testing.T) {
+	b := NewBroker()
+	clients := newTestClients(3)
+
+	assert.NotNil(t, b.Subscribe("a", nil))
+
+	for _, c := range clients {
+		assert.Nil(t, b.Subscribe("a", c))
+	}
+	assert.Nil(t, b.Subscribe("a", clients[0]))
+	assert.Nil(t, b.Subscribe("b", clients[0]))
+
+	assert.Equal(t, 3, b.Count("a"))
+	assert.Equal(t, 1, b.Count("b"))
+	assert.ElementsMatch(t, clients, b.Subscribers("a"))
+
+	b.Unsubscribe("a", clients[1])
+	assert.Equal(t, 2, b.Count("a"))
+	assert.ElementsMatch(t, []*Client{clients[0], clients[2]}, b.Subscribers("a"))
+
+	b.UnsubscribeAll(clients[0])
+	assert.Equal(t, 1, b.Count("a"))
+	assert.Equal(t, 0, b.Count("b"))
+	assert.Nil(t, b.Subscribers("b"))
+
+	// unsubscribing twice is harmless
+	b.Unsubscribe("a", clients[1])
+	assert.Equal(t, 1, b.Count("a"))
+}
+
+func TestBrokerPublishOrder(t *testing.T) {
+	const messages = 50
+
+	b := NewBroker()
+	clients := newTestClients(100)
+	for _, c := range clients {
+		b.Subscribe("pair", c)
+	}
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	received := make(map[*Client][]float64)
+
+	defer recordDeliveries(func(c *Client, job *fanoutJob) {
+		defer wg.Done()
+
+		m := types.WebsocketMessage{}
+		json.Unmarshal(job.data, &m)
+
+		mutex.Lock()
+		received[c] = append(received[c], m.Event.Payload.(float64))
+		mutex.Unlock()
+	})()
+
+	wg.Add(messages * len(clients))
+	for i := 0; i < messages; i++ {
+		assert.Nil(t, b.Publish("pair", OrderBookChannel, types.UPDATE, i))
+	}
+	wg.Wait()
+
+	assert.Equal(t, len(clients), len(received))
+	for _, c := range clients {
+		assert.Equal(t, messages, len(received[c]))
+		for i, v := range received[c] {
+			assert.Equal(t, float64(i), v)
+		}
+	}
+
+	// no subscribers, nothing delivered
+	assert.Nil(t, b.Publish("other", OrderBookChannel, types.UPDATE, 0))
+}
+
+// BenchmarkBrokerPublish broadcasts a message to 10k subscribers of a pair
+func BenchmarkBrokerPublish(b *testing.B) {
+	broker := NewBroker()
+	clients := newTestClients(10000)
+	for _, c := range clients {
+		broker.Subscribe("pair", c)
+	}
+
+	var wg sync.WaitGroup
+	defer recordDeliveries(func(c *Client, job *fanoutJob) { wg.Done() })()
+
+	payload := &types.OrderBook{PairName: "TOMO/USDT", Bids: []map[string]string{{"pricepoint": "1000", "amount": "1"}}}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		wg.Add(len(clients))
+		broker.Publish("pair", OrderBookChannel, types.UPDATE, payload)
+		wg.Wait()
+	}
+}
+
+// BenchmarkBrokerPublishPairs broadcasts concurrently to 10 pairs of 10k subscribers each
+func BenchmarkBrokerPublishPairs(b *testing.B) {
+	const pairs = 10
+
+	broker := NewBroker()
+	clients := newTestClients(10000)
+	for p := 0; p < pairs; p++ {
+		for _, c := range clients {
+			broker.Subscribe(fmt.Sprintf("pair%d", p), c)
+		}
+	}
+
+	var wg sync.WaitGroup
+	defer recordDeliveries(func(c *Client, job *fanoutJob) { wg.Done() })()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		wg.Add(pairs * len(clients))
+		for p := 0; p < pairs; p++ {
+			go broker.Publish(fmt.Sprintf("pair%d", p), OrderBookChannel, types.UPDATE, i)
+		}
+		wg.Wait()
+	}
+}
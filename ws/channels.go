@@ -4,10 +4,12 @@ import (
 	"fmt"
 
 	"github.com/tomochain/tomox-sdk/errors"
+	"github.com/tomochain/tomox-sdk/types"
 )
 
 const (
 	TradeChannel        = "trades"
+	RawTradeChannel     = "raw_trades"
 	RawOrderBookChannel = "raw_orderbook"
 	OrderChannel        = "orders"
 	OrderBookChannel    = "orderbook"
@@ -17,6 +19,13 @@ const (
 	DepositChannel      = "deposit"
 	MarketsChannel      = "markets"
 	NotificationChannel = "notification"
+	BlockChannel        = "blocks"
+	ProfileChannel      = "profiles"
+	PreferencesChannel  = "preferences"
+	SystemStatusChannel = "system_status"
+
+	MicrostructureChannel = "microstructure"
+	IndicesChannel        = "indices"
 
 	// Lending channel
 	LendingOrderChannel        = "lending_orders"
@@ -26,6 +35,7 @@ const (
 	LendingOhlcvChannel        = "lending_ohlcv"
 	LendingMarketsChannel      = "lending_markets"
 	LendingPriceBoardChannel   = "lending_price_board"
+	LendingLiquidationChannel  = "lending_liquidations"
 )
 
 var socketChannels map[string]func(interface{}, *Client)
@@ -48,6 +58,22 @@ func RegisterChannel(channel string, fn func(interface{}, *Client)) error {
 	return nil
 }
 
+// HasChannel returns true if a handler is registered for the channel
+func HasChannel(channel string) bool {
+	return getChannels()[channel] != nil
+}
+
+// Dispatch handles an event of a channel as if it was sent by the client
+func Dispatch(channel string, ev types.WebsocketEvent, c *Client) error {
+	fn := getChannels()[channel]
+	if fn == nil {
+		return fmt.Errorf("Channel %s not registered", channel)
+	}
+
+	fn(ev, c)
+	return nil
+}
+
 func getChannels() map[string]func(interface{}, *Client) {
 	if socketChannels == nil {
 		socketChannels = make(map[string]func(interface{}, *Client))
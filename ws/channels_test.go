@@ -0,0 +1,24 @@
+package ws
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+func TestDispatch(t *testing.T) {
+	var received interface{}
+	err := RegisterChannel("test_dispatch", func(ev interface{}, c *Client) {
+		received = ev
+	})
+	assert.Nil(t, err)
+
+	assert.True(t, HasChannel("test_dispatch"))
+	assert.False(t, HasChannel("unknown"))
+
+	ev := types.WebsocketEvent{Type: types.SUBSCRIBE, Payload: "payload"}
+	assert.Nil(t, Dispatch("test_dispatch", ev, nil))
+	assert.Equal(t, ev, received)
+	assert.NotNil(t, Dispatch("unknown", ev, nil))
+}
@@ -7,18 +7,34 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/gorilla/websocket"
 	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/usage"
 )
 
 type Client struct {
 	*websocket.Conn
 	mu   sync.Mutex
 	send chan types.WebsocketMessage
+	// usageKey identifies the user in the API usage statistics
+	usageKey string
+	// apiKey authenticates the subscriptions of the restricted channels
+	apiKey string
+	// seq assigns the client to a fanout worker
+	seq uint64
+	// prefs are the display preferences of the connection
+	prefs      *types.Preferences
+	prefsMutex sync.RWMutex
+	// licensed connections get the real-time market data, the others get it after the market data delay
+	licensed bool
+	delayed  delayLine
+	// closed is closed with the connection
+	closed    chan struct{}
+	closeOnce sync.Once
 }
 
 var unsubscribeHandlers map[*Client][]func(*Client)
 
 func NewClient(c *websocket.Conn) *Client {
-	conn := &Client{Conn: c, mu: sync.Mutex{}, send: make(chan types.WebsocketMessage)}
+	conn := &Client{Conn: c, mu: sync.Mutex{}, send: make(chan types.WebsocketMessage), seq: nextClientSeq(), closed: make(chan struct{})}
 
 	if unsubscribeHandlers == nil {
 		unsubscribeHandlers = make(map[*Client][]func(*Client))
@@ -40,6 +56,53 @@ func (c *Client) writeMessage(m types.WebsocketMessage) {
 		return
 	}
 
+	usage.GetTracker().WSMessageOut(c.usageKey)
+}
+
+// writePreparedMessage writes a message encoded once for all the subscribers of a topic
+func (c *Client) writePreparedMessage(pm *websocket.PreparedMessage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.SetWriteDeadline(time.Now().Add(writeWait))
+	err := c.WritePreparedMessage(pm)
+	if err != nil {
+		logger.Info("writePreparedMessage closing connection:", err)
+		c.closeConnection()
+		return
+	}
+
+	usage.GetTracker().WSMessageOut(c.usageKey)
+}
+
+// worker returns the fanout worker of the client
+func (c *Client) worker() int {
+	return int(c.seq % fanoutWorkers)
+}
+
+// APIKey returns the API key sent at connection ("X-Api-Key" header or "apiKey" param)
+func (c *Client) APIKey() string {
+	return c.apiKey
+}
+
+// Preferences returns the display preferences of the connection
+func (c *Client) Preferences() *types.Preferences {
+	c.prefsMutex.RLock()
+	defer c.prefsMutex.RUnlock()
+
+	if c.prefs == nil {
+		return types.DefaultPreferences()
+	}
+
+	return c.prefs
+}
+
+// SetPreferences sets the display preferences of the connection, they apply to the subscriptions made afterwards
+func (c *Client) SetPreferences(p *types.Preferences) {
+	c.prefsMutex.Lock()
+	defer c.prefsMutex.Unlock()
+
+	c.prefs = p
 }
 
 // SendMessage constructs the message with proper structure to be sent over websocket
@@ -60,6 +123,11 @@ func (c *Client) SendMessage(channel string, msgType types.SubscriptionEvent, pa
 		Event:   e,
 	}
 
+	if d := c.delay(channel, msgType); d > 0 {
+		c.sendMessageLater(m, d)
+		return
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -75,6 +143,8 @@ func (c *Client) SendPingMessage() error {
 }
 
 func (c *Client) closeConnection() {
+	c.closeOnce.Do(func() { close(c.closed) })
+
 	for _, unsub := range unsubscribeHandlers[c] {
 		unsub(c)
 	}
@@ -0,0 +1,70 @@
+package ws
+
+import (
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+var compositeIndexSocket *CompositeIndexSocket
+
+// CompositeIndexSocket holds the subscriptions to the values of the composite indices
+type CompositeIndexSocket struct {
+	topics *Broker
+}
+
+// NewCompositeIndexSocket returns a new instance of CompositeIndexSocket
+func NewCompositeIndexSocket() *CompositeIndexSocket {
+	return &CompositeIndexSocket{
+		topics: NewBroker(),
+	}
+}
+
+// GetCompositeIndexSocket returns the singleton instance of CompositeIndexSocket
+func GetCompositeIndexSocket() *CompositeIndexSocket {
+	if compositeIndexSocket == nil {
+		compositeIndexSocket = NewCompositeIndexSocket()
+	}
+
+	return compositeIndexSocket
+}
+
+// Subscribe registers a connection to the values of an index
+func (s *CompositeIndexSocket) Subscribe(channelID string, c *Client) error {
+	return s.topics.Subscribe(channelID, c)
+}
+
+// Subscribed returns true if a connection is subscribed to the channel id
+func (s *CompositeIndexSocket) Subscribed(channelID string) bool {
+	return s.topics.Count(channelID) > 0
+}
+
+// UnsubscribeChannelHandler unsubscribes a connection from an index
+func (s *CompositeIndexSocket) UnsubscribeChannelHandler(channelID string) func(c *Client) {
+	return func(c *Client) {
+		s.UnsubscribeChannel(channelID, c)
+	}
+}
+
+// UnsubscribeChannel removes a connection from an index
+func (s *CompositeIndexSocket) UnsubscribeChannel(channelID string, c *Client) {
+	s.topics.Unsubscribe(channelID, c)
+}
+
+// Unsubscribe removes a connection from all the indices
+func (s *CompositeIndexSocket) Unsubscribe(c *Client) {
+	s.topics.UnsubscribeAll(c)
+}
+
+// BroadcastMessage streams the value of an index to its subscriptions
+func (s *CompositeIndexSocket) BroadcastMessage(channelID string, p interface{}) error {
+	return s.topics.Publish(channelID, IndicesChannel, types.UPDATE, p)
+}
+
+// SendInitMessage sends the current value of the index on subscription
+func (s *CompositeIndexSocket) SendInitMessage(c *Client, p interface{}) {
+	c.SendMessage(IndicesChannel, types.INIT, p)
+}
+
+// SendErrorMessage sends an error message on the indices channel
+func (s *CompositeIndexSocket) SendErrorMessage(c *Client, p interface{}) {
+	c.SendMessage(IndicesChannel, types.ERROR, p)
+}
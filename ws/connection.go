@@ -8,12 +8,16 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/tomochain/tomox-sdk/app"
 	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/usage"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
 )
 
 const (
 	writeWait  = 30 * time.Second
 	pongWait   = 30 * time.Second
 	pingPeriod = (pongWait * 9) / 10
+	// maxMessageSize is the size limit of the messages read from a client
+	maxMessageSize = 64 * 1024
 )
 
 var logger = NewWebsocketLogger()
@@ -37,8 +41,24 @@ func ConnectionEndpoint(w http.ResponseWriter, r *http.Request) {
 	}
 
 	c := NewClient(conn)
+	c.usageKey = usage.Key(r)
+	c.apiKey = r.Header.Get("X-Api-Key")
+	if c.apiKey == "" {
+		c.apiKey = r.URL.Query().Get("apiKey")
+	}
+	c.licensed = isLicensed(c.apiKey)
 	c.SetCloseHandler(closeHandler(c))
 
+	v := r.URL.Query()
+	if v.Get("timezone") != "" || v.Get("units") != "" {
+		prefs, err := types.NewPreferences(v.Get("timezone"), v.Get("units"))
+		if err != nil {
+			c.SendMessage(PreferencesChannel, types.ERROR, err.Error())
+		} else {
+			c.SetPreferences(prefs)
+		}
+	}
+
 	go readHandler(c)
 	go pingHandler(c)
 }
@@ -49,6 +69,7 @@ func readHandler(c *Client) {
 		c.closeConnection()
 	}()
 
+	c.SetReadLimit(maxMessageSize)
 	c.SetReadDeadline(time.Now().Add(pongWait))
 	c.SetPongHandler(func(string) error {
 		c.SetReadDeadline(time.Now().Add(pongWait))
@@ -69,7 +90,16 @@ func readHandler(c *Client) {
 			return
 		}
 
+		usage.GetTracker().WSMessageIn(c.usageKey)
+		start := time.Now()
+
 		msg := types.WebsocketMessage{}
+		if err := httputils.CheckJSONDepth(payload, httputils.MaxJSONDepth); err != nil {
+			logger.Error(err)
+			c.SendMessage(msg.Channel, types.ERROR, err.Error())
+			return
+		}
+
 		if err := json.Unmarshal(payload, &msg); err != nil {
 			logger.Error(err)
 			c.SendMessage(msg.Channel, types.ERROR, err.Error())
@@ -83,12 +113,17 @@ func readHandler(c *Client) {
 
 		logger.Infof("%v", msg.String())
 
-		if socketChannels[msg.Channel] == nil {
+		handler := socketChannels[msg.Channel]
+		if handler == nil {
 			c.SendMessage(msg.Channel, types.ERROR, "INVALID_CHANNEL")
+			usage.GetAccessLogger().Log(types.AccessLogWS, "invalid", "", http.StatusNotFound, time.Since(start), int64(len(payload)), c.usageKey)
 			return
 		}
 
-		go socketChannels[msg.Channel](msg.Event, c)
+		go func(msg types.WebsocketMessage, size int) {
+			handler(msg.Event, c)
+			usage.GetAccessLogger().Log(types.AccessLogWS, msg.Channel, string(msg.Event.Type), http.StatusOK, time.Since(start), int64(size), c.usageKey)
+		}(msg, len(payload))
 	}
 }
 
@@ -0,0 +1,165 @@
+package ws
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// maxDelayedMessages is the number of messages a connection holds while they are delayed,
+// the oldest ones are dropped when a connection receives more
+const maxDelayedMessages = 10000
+
+// marketDataChannels are the public market data channels, delayed for the connections without a license
+var marketDataChannels = map[string]bool{
+	TradeChannel:               true,
+	RawOrderBookChannel:        true,
+	OrderBookChannel:           true,
+	OHLCVChannel:               true,
+	PriceBoardChannel:          true,
+	MarketsChannel:             true,
+	MicrostructureChannel:      true,
+	IndicesChannel:             true,
+	LendingTradeChannel:        true,
+	RawLendingOrderBookChannel: true,
+	LendingOrderBookChannel:    true,
+	LendingOhlcvChannel:        true,
+	LendingMarketsChannel:      true,
+	LendingPriceBoardChannel:   true,
+}
+
+var (
+	marketDataDelay   time.Duration
+	marketDataLicense func(apiKey string) bool
+)
+
+// SetMarketDataDelay delays the market data channels of the connections whose API key
+// is not licensed for the real-time data, a zero delay serves the real-time data to everyone
+func SetMarketDataDelay(delay time.Duration, licensed func(apiKey string) bool) {
+	marketDataDelay = delay
+	marketDataLicense = licensed
+}
+
+// MarketDataDelay returns the delay of the public market data feed
+func MarketDataDelay() time.Duration {
+	return marketDataDelay
+}
+
+// isLicensed returns true if the API key gets the real-time market data
+func isLicensed(apiKey string) bool {
+	return marketDataDelay == 0 || (marketDataLicense != nil && marketDataLicense(apiKey))
+}
+
+// delayedMessage is a message written to a connection once due
+type delayedMessage struct {
+	due     time.Time
+	message *websocket.PreparedMessage
+}
+
+// delayLine holds the delayed messages of a connection, they are written in the order they were sent
+type delayLine struct {
+	mutex   sync.Mutex
+	pending []*delayedMessage
+	wake    chan struct{}
+	started bool
+}
+
+// delay returns how long a message is held before it is written to the connection
+func (c *Client) delay(channel string, msgType types.SubscriptionEvent) time.Duration {
+	if c.licensed || !marketDataChannels[channel] || msgType == types.ERROR {
+		return 0
+	}
+
+	return marketDataDelay
+}
+
+// sendLater writes a message to the connection once due, the writer is started with the first message
+func (c *Client) sendLater(pm *websocket.PreparedMessage, due time.Time) {
+	l := &c.delayed
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if len(l.pending) >= maxDelayedMessages {
+		logger.Info("Delayed messages dropped:", len(l.pending)-maxDelayedMessages+1)
+		l.pending = l.pending[len(l.pending)-maxDelayedMessages+1:]
+	}
+
+	l.pending = append(l.pending, &delayedMessage{due: due, message: pm})
+	if !l.started {
+		l.started = true
+		l.wake = make(chan struct{}, 1)
+		go c.writeDelayed()
+	}
+
+	select {
+	case l.wake <- struct{}{}:
+	default:
+	}
+}
+
+// sendMessageLater encodes a message and writes it to the connection after the delay
+func (c *Client) sendMessageLater(m types.WebsocketMessage, delay time.Duration) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	pm, err := websocket.NewPreparedMessage(websocket.TextMessage, data)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	c.sendLater(pm, time.Now().Add(delay))
+}
+
+// writeDelayed writes the delayed messages when they are due until the connection is closed
+func (c *Client) writeDelayed() {
+	l := &c.delayed
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		l.mutex.Lock()
+		wait := time.Hour
+		var due *delayedMessage
+		if len(l.pending) > 0 {
+			wait = time.Until(l.pending[0].due)
+			if wait <= 0 {
+				due = l.pending[0]
+				l.pending = l.pending[1:]
+			}
+		}
+		l.mutex.Unlock()
+
+		if due != nil {
+			select {
+			case <-c.closed:
+				return
+			default:
+			}
+
+			c.writePreparedMessage(due.message)
+			continue
+		}
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(wait)
+
+		select {
+		case <-c.closed:
+			return
+		case <-l.wake:
+		case <-timer.C:
+		}
+	}
+}
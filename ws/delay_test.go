@@ -0,0 +1,23 @@
+package ws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+func TestClientDelay(t *testing.T) {
+	SetMarketDataDelay(5*time.Minute, func(apiKey string) bool { return apiKey == "licensed" })
+	defer SetMarketDataDelay(0, nil)
+
+	public := &Client{licensed: isLicensed("")}
+	licensed := &Client{licensed: isLicensed("licensed")}
+
+	assert.Equal(t, 5*time.Minute, public.delay(TradeChannel, types.UPDATE))
+	assert.Equal(t, 5*time.Minute, public.delay(OrderBookChannel, types.INIT))
+	assert.Equal(t, time.Duration(0), public.delay(TradeChannel, types.ERROR))
+	assert.Equal(t, time.Duration(0), public.delay(OrderChannel, types.UPDATE))
+	assert.Equal(t, time.Duration(0), licensed.delay(TradeChannel, types.UPDATE))
+}
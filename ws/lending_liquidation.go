@@ -0,0 +1,81 @@
+package ws
+
+import (
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+var lendingLiquidationSocket *LendingLiquidationSocket
+
+// LendingLiquidationSocket holds the map of subscriptions subscribed to lending liquidations channels
+// corresponding to the key/event they have subscribed to.
+type LendingLiquidationSocket struct {
+	topics *Broker
+}
+
+// NewLendingLiquidationSocket returns a new instance of LendingLiquidationSocket
+func NewLendingLiquidationSocket() *LendingLiquidationSocket {
+	return &LendingLiquidationSocket{
+		topics: NewBroker(),
+	}
+}
+
+// GetLendingLiquidationSocket return singleton instance of LendingLiquidationSocket type struct
+func GetLendingLiquidationSocket() *LendingLiquidationSocket {
+	if lendingLiquidationSocket == nil {
+		lendingLiquidationSocket = NewLendingLiquidationSocket()
+	}
+	return lendingLiquidationSocket
+}
+
+// Subscribe handles the subscription of connection to get
+// streaming data over the socker for new liquidations.
+func (s *LendingLiquidationSocket) Subscribe(channelID string, c *Client) error {
+	return s.topics.Subscribe(channelID, c)
+}
+
+// UnsubscribeHandler unsubscribes a connection from a certain lending liquidations channel id
+func (s *LendingLiquidationSocket) UnsubscribeChannelHandler(channelID string) func(c *Client) {
+	return func(c *Client) {
+		s.UnsubscribeChannel(channelID, c)
+	}
+}
+
+func (s *LendingLiquidationSocket) UnsubscribeHandler() func(c *Client) {
+	return func(c *Client) {
+		s.Unsubscribe(c)
+	}
+}
+
+// Unsubscribe removes a websocket connection from the lending liquidations channel updates
+func (s *LendingLiquidationSocket) UnsubscribeChannel(channelID string, c *Client) {
+	s.topics.Unsubscribe(channelID, c)
+}
+
+func (s *LendingLiquidationSocket) Unsubscribe(c *Client) {
+	s.topics.UnsubscribeAll(c)
+}
+
+// BroadcastMessage streams message to all the subscriptions subscribed to the lending liquidations channel
+func (s *LendingLiquidationSocket) BroadcastMessage(channelID string, p interface{}) error {
+	return s.topics.Publish(channelID, LendingLiquidationChannel, types.UPDATE, p)
+}
+
+// SendMessage sends a websocket message on the lending liquidations channel
+func (s *LendingLiquidationSocket) SendMessage(c *Client, msgType types.SubscriptionEvent, p interface{}) {
+	c.SendMessage(LendingLiquidationChannel, msgType, p)
+}
+
+// SendInitMessage sends INIT message on lending liquidations channel on subscription event
+func (s *LendingLiquidationSocket) SendInitMessage(c *Client, data interface{}) {
+	c.SendMessage(LendingLiquidationChannel, types.INIT, data)
+}
+
+// SendUpdateMessage sends UPDATE message on lending liquidations channel as new data is created
+func (s *LendingLiquidationSocket) SendUpdateMessage(c *Client, data interface{}) {
+	c.SendMessage(LendingLiquidationChannel, types.UPDATE, data)
+}
+
+// SendErrorMessage sends error message on lending liquidations channel
+func (s *LendingLiquidationSocket) SendErrorMessage(c *Client, data interface{}) {
+	c.SendMessage(LendingLiquidationChannel, types.ERROR, data)
+}
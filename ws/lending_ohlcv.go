@@ -1,9 +1,6 @@
 package ws
 
 import (
-	"sync"
-
-	"github.com/tomochain/tomox-sdk/errors"
 	"github.com/tomochain/tomox-sdk/types"
 )
 
@@ -12,17 +9,13 @@ var lendingOhlcvSocket *LendingOhlcvSocket
 // LendingOhlcvSocket holds the map of subscribtions subscribed to OHLCV channels
 // corresponding to the key/event they have subscribed to.
 type LendingOhlcvSocket struct {
-	subscriptions     map[string]map[*Client]bool
-	subscriptionsList map[*Client][]string
-	subsMutex         sync.RWMutex
-	subsListMutex     sync.RWMutex
+	topics *Broker
 }
 
 // NewLendingOhlcvSocket create new instance
 func NewLendingOhlcvSocket() *LendingOhlcvSocket {
 	return &LendingOhlcvSocket{
-		subscriptions:     make(map[string]map[*Client]bool),
-		subscriptionsList: make(map[*Client][]string),
+		topics: NewBroker(),
 	}
 }
 
@@ -38,25 +31,7 @@ func GetLendingOhlcvSocket() *LendingOhlcvSocket {
 // Subscribe handles the registration of connection to get
 // streaming data over the socket for any pair.
 func (s *LendingOhlcvSocket) Subscribe(channelID string, c *Client) error {
-	if c == nil {
-		return errors.New("No connection found")
-	}
-	s.subsMutex.Lock()
-	s.subsListMutex.Lock()
-	defer s.subsMutex.Unlock()
-	defer s.subsListMutex.Unlock()
-
-	if s.subscriptions[channelID] == nil {
-		s.subscriptions[channelID] = make(map[*Client]bool)
-	}
-
-	s.subscriptions[channelID][c] = true
-
-	if s.subscriptionsList[c] == nil {
-		s.subscriptionsList[c] = []string{}
-	}
-	s.subscriptionsList[c] = append(s.subscriptionsList[c], channelID)
-	return nil
+	return s.topics.Subscribe(channelID, c)
 }
 
 // UnsubscribeChannelHandler returns function of type unsubscribe handler,
@@ -67,7 +42,7 @@ func (s *LendingOhlcvSocket) UnsubscribeChannelHandler(channelID string) func(c
 	}
 }
 
-//UnsubscribeHandler returns function of type unsubscribe handler
+// UnsubscribeHandler returns function of type unsubscribe handler
 func (s *LendingOhlcvSocket) UnsubscribeHandler() func(c *Client) {
 	return func(c *Client) {
 		s.Unsubscribe(c)
@@ -78,39 +53,17 @@ func (s *LendingOhlcvSocket) UnsubscribeHandler() func(c *Client) {
 // subscribed to. It can be called on unsubscription message from user or due to some other reason by
 // system
 func (s *LendingOhlcvSocket) UnsubscribeChannel(channelID string, c *Client) {
-	s.subsMutex.Lock()
-	defer s.subsMutex.Unlock()
-	if s.subscriptions[channelID][c] {
-		s.subscriptions[channelID][c] = false
-		delete(s.subscriptions[channelID], c)
-	}
+	s.topics.Unsubscribe(channelID, c)
 }
 
 // Unsubscribe  returns function of type unsubscribe handler
 func (s *LendingOhlcvSocket) Unsubscribe(c *Client) {
-	s.subsListMutex.RLock()
-	defer s.subsListMutex.RUnlock()
-	channelIDs := s.subscriptionsList[c]
-	if channelIDs == nil {
-		return
-	}
-
-	for _, id := range s.subscriptionsList[c] {
-		s.UnsubscribeChannel(id, c)
-	}
+	s.topics.UnsubscribeAll(c)
 }
 
 // BroadcastLendingOhlcv Message streams message to all the subscriptions subscribed to the pair
 func (s *LendingOhlcvSocket) BroadcastLendingOhlcv(channelID string, p interface{}) error {
-	s.subsMutex.RLock()
-	defer s.subsMutex.RUnlock()
-	for c, status := range s.subscriptions[channelID] {
-		if status {
-			s.SendUpdateMessage(c, p)
-		}
-	}
-
-	return nil
+	return s.topics.Publish(channelID, LendingOhlcvChannel, types.UPDATE, p)
 }
 
 // SendMessage sends a websocket message on the trade channel
@@ -1,9 +1,6 @@
 package ws
 
 import (
-	"sync"
-
-	"github.com/tomochain/tomox-sdk/errors"
 	"github.com/tomochain/tomox-sdk/types"
 )
 
@@ -12,17 +9,13 @@ var lendingOrderbookSocket *LendingOrderBookSocket
 // LendingOrderBookSocket holds the map of subscriptions subscribed to orderbook channels
 // corresponding to the key/event they have subscribed to.
 type LendingOrderBookSocket struct {
-	subscriptions     map[string]map[*Client]bool
-	subscriptionsList map[*Client][]string
-	subsMutex         sync.RWMutex
-	subsListMutex     sync.RWMutex
+	topics *Broker
 }
 
 // NewLendingOrderBookSocket new lending order book instance
 func NewLendingOrderBookSocket() *LendingOrderBookSocket {
 	return &LendingOrderBookSocket{
-		subscriptions:     make(map[string]map[*Client]bool),
-		subscriptionsList: make(map[*Client][]string),
+		topics: NewBroker(),
 	}
 }
 
@@ -39,27 +32,7 @@ func GetLendingOrderBookSocket() *LendingOrderBookSocket {
 // streaming data over the socker for any pair.
 // pair := utils.GetPairKey(bt, qt)
 func (s *LendingOrderBookSocket) Subscribe(channelID string, c *Client) error {
-	if c == nil {
-		return errors.New("No connection found")
-	}
-	s.subsMutex.Lock()
-	s.subsListMutex.Lock()
-	defer s.subsMutex.Unlock()
-	defer s.subsListMutex.Unlock()
-
-	if s.subscriptions[channelID] == nil {
-		s.subscriptions[channelID] = make(map[*Client]bool)
-	}
-
-	s.subscriptions[channelID][c] = true
-
-	if s.subscriptionsList[c] == nil {
-		s.subscriptionsList[c] = []string{}
-	}
-
-	s.subscriptionsList[c] = append(s.subscriptionsList[c], channelID)
-
-	return nil
+	return s.topics.Subscribe(channelID, c)
 }
 
 // UnsubscribeChannelHandler unsubscribes a connection from a certain orderbook channel id
@@ -78,45 +51,17 @@ func (s *LendingOrderBookSocket) UnsubscribeHandler() func(c *Client) {
 
 // UnsubscribeChannel removes a websocket connection from the orderbook channel updates
 func (s *LendingOrderBookSocket) UnsubscribeChannel(channelID string, c *Client) {
-	s.subsMutex.Lock()
-	defer s.subsMutex.Unlock()
-	if s.subscriptions[channelID][c] {
-		s.subscriptions[channelID][c] = false
-		delete(s.subscriptions[channelID], c)
-	}
-
+	s.topics.Unsubscribe(channelID, c)
 }
 
 // Unsubscribe unsubscribe
 func (s *LendingOrderBookSocket) Unsubscribe(c *Client) {
-	s.subsListMutex.RLock()
-	defer s.subsListMutex.RUnlock()
-	channelIDs := s.subscriptionsList[c]
-	if channelIDs == nil {
-		return
-	}
-
-	for _, id := range s.subscriptionsList[c] {
-		s.UnsubscribeChannel(id, c)
-	}
-}
-
-func (s *LendingOrderBookSocket) getSubscriptions() map[string]map[*Client]bool {
-	s.subsMutex.RLock()
-	defer s.subsMutex.RUnlock()
-	return s.subscriptions
+	s.topics.UnsubscribeAll(c)
 }
 
 // BroadcastMessage streams message to all the subscribtions subscribed to the pair
 func (s *LendingOrderBookSocket) BroadcastMessage(channelID string, p interface{}) error {
-	subs := s.getSubscriptions()
-	for c, status := range subs[channelID] {
-		if status {
-			s.SendUpdateMessage(c, p)
-		}
-	}
-
-	return nil
+	return s.topics.Publish(channelID, LendingOrderBookChannel, types.UPDATE, p)
 }
 
 // SendMessage sends a websocket message on the orderbook channel
@@ -1,9 +1,6 @@
 package ws
 
 import (
-	"sync"
-
-	"github.com/tomochain/tomox-sdk/errors"
 	"github.com/tomochain/tomox-sdk/types"
 )
 
@@ -12,16 +9,12 @@ var lendingPriceBoardSocket *LendingPriceBoardSocket
 // LendingPriceBoardSocket holds the map of subscriptions subscribed to price board channels
 // corresponding to the key/event they have subscribed to.
 type LendingPriceBoardSocket struct {
-	subscriptions     map[string]map[*Client]bool
-	subscriptionsList map[*Client][]string
-	subsMutex         sync.RWMutex
-	subsListMutex     sync.RWMutex
+	topics *Broker
 }
 
 func NewLendingPriceBoardSocket() *LendingPriceBoardSocket {
 	return &LendingPriceBoardSocket{
-		subscriptions:     make(map[string]map[*Client]bool),
-		subscriptionsList: make(map[*Client][]string),
+		topics: NewBroker(),
 	}
 }
 
@@ -37,25 +30,7 @@ func GetLendingPriceBoardSocket() *LendingPriceBoardSocket {
 // Subscribe handles the subscription of connection to get
 // streaming data over the socker for any pair.
 func (s *LendingPriceBoardSocket) Subscribe(channelID string, c *Client) error {
-	s.subsMutex.Lock()
-	s.subsListMutex.Lock()
-	defer s.subsMutex.Unlock()
-	defer s.subsListMutex.Unlock()
-	if c == nil {
-		return errors.New("No connection found")
-	}
-
-	if s.subscriptions[channelID] == nil {
-		s.subscriptions[channelID] = make(map[*Client]bool)
-	}
-
-	s.subscriptions[channelID][c] = true
-
-	if s.subscriptionsList[c] == nil {
-		s.subscriptionsList[c] = []string{}
-	}
-	s.subscriptionsList[c] = append(s.subscriptionsList[c], channelID)
-	return nil
+	return s.topics.Subscribe(channelID, c)
 }
 
 // UnsubscribeChannelHandler unsubscribes a connection from a certain lending price board channel id
@@ -73,43 +48,16 @@ func (s *LendingPriceBoardSocket) UnsubscribeHandler() func(c *Client) {
 
 // UnsubscribeChannel removes a websocket connection from the price board channel updates
 func (s *LendingPriceBoardSocket) UnsubscribeChannel(channelID string, c *Client) {
-	s.subsMutex.Lock()
-	defer s.subsMutex.Unlock()
-	if s.subscriptions[channelID][c] {
-		s.subscriptions[channelID][c] = false
-		delete(s.subscriptions[channelID], c)
-	}
+	s.topics.Unsubscribe(channelID, c)
 }
 
 func (s *LendingPriceBoardSocket) Unsubscribe(c *Client) {
-	s.subsListMutex.RLock()
-	defer s.subsListMutex.RUnlock()
-	channelIDs := s.subscriptionsList[c]
-	if channelIDs == nil {
-		return
-	}
-
-	for _, id := range s.subscriptionsList[c] {
-		s.UnsubscribeChannel(id, c)
-	}
-}
-
-func (s *LendingPriceBoardSocket) getSubscriptions() map[string]map[*Client]bool {
-	s.subsMutex.RLock()
-	defer s.subsMutex.RUnlock()
-	return s.subscriptions
+	s.topics.UnsubscribeAll(c)
 }
 
 // BroadcastMessage streams message to all the subscriptions subscribed to the pair
 func (s *LendingPriceBoardSocket) BroadcastMessage(channelID string, p interface{}) error {
-	subs := s.getSubscriptions()
-	for c, status := range subs[channelID] {
-		if status {
-			s.SendUpdateMessage(c, p)
-		}
-	}
-
-	return nil
+	return s.topics.Publish(channelID, LendingPriceBoardChannel, types.UPDATE, p)
 }
 
 // SendMessage sends a websocket message on the price board channel
@@ -1,9 +1,6 @@
 package ws
 
 import (
-	"sync"
-
-	"github.com/tomochain/tomox-sdk/errors"
 	"github.com/tomochain/tomox-sdk/types"
 )
 
@@ -12,17 +9,13 @@ var lendingTradeSocket *LendingTradeSocket
 // LendingTradeSocket holds the map of connections subscribed to pair channels
 // corresponding to the key/event they have subscribed to.
 type LendingTradeSocket struct {
-	subscriptions     map[string]map[*Client]bool
-	subscriptionsList map[*Client][]string
-	subsMutex         sync.RWMutex
-	subsListMutex     sync.RWMutex
+	topics *Broker
 }
 
 // NewLendingTradeSocket init lending socket instance
 func NewLendingTradeSocket() *LendingTradeSocket {
 	return &LendingTradeSocket{
-		subscriptions:     make(map[string]map[*Client]bool),
-		subscriptionsList: make(map[*Client][]string),
+		topics: NewBroker(),
 	}
 }
 
@@ -37,26 +30,7 @@ func GetLendingTradeSocket() *LendingTradeSocket {
 
 // Subscribe registers a new websocket connections to the trade channel updates
 func (s *LendingTradeSocket) Subscribe(channelID string, c *Client) error {
-	s.subsMutex.Lock()
-	s.subsListMutex.Lock()
-	defer s.subsMutex.Unlock()
-	defer s.subsListMutex.Unlock()
-
-	if c == nil {
-		return errors.New("No connection found")
-	}
-
-	if s.subscriptions[channelID] == nil {
-		s.subscriptions[channelID] = make(map[*Client]bool)
-	}
-
-	s.subscriptions[channelID][c] = true
-
-	if s.subscriptionsList[c] == nil {
-		s.subscriptionsList[c] = []string{}
-	}
-	s.subscriptionsList[c] = append(s.subscriptionsList[c], channelID)
-	return nil
+	return s.topics.Subscribe(channelID, c)
 }
 
 // UnsubscribeChannelHandler unsubscribes a connection from a certain trade channel id
@@ -75,44 +49,17 @@ func (s *LendingTradeSocket) UnsubscribeHandler() func(c *Client) {
 
 // UnsubscribeChannel removes a websocket connection from the trade channel updates
 func (s *LendingTradeSocket) UnsubscribeChannel(channelID string, c *Client) {
-	s.subsMutex.Lock()
-	defer s.subsMutex.Unlock()
-	if s.subscriptions[channelID][c] {
-		s.subscriptions[channelID][c] = false
-		delete(s.subscriptions[channelID], c)
-	}
+	s.topics.Unsubscribe(channelID, c)
 }
 
 // Unsubscribe removes a websocket connection from the trade channel updates
 func (s *LendingTradeSocket) Unsubscribe(c *Client) {
-	s.subsListMutex.RLock()
-	defer s.subsListMutex.RUnlock()
-	channelIDs := s.subscriptionsList[c]
-	if channelIDs == nil {
-		return
-	}
-
-	for _, id := range s.subscriptionsList[c] {
-		s.UnsubscribeChannel(id, c)
-	}
-}
-
-func (s *LendingTradeSocket) getSubscriptions() map[string]map[*Client]bool {
-	s.subsMutex.RLock()
-	defer s.subsMutex.RUnlock()
-	return lendingTradeSocket.subscriptions
+	s.topics.UnsubscribeAll(c)
 }
 
 // BroadcastMessage broadcasts trade message to all subscribed sockets
 func (s *LendingTradeSocket) BroadcastMessage(channelID string, p interface{}) {
-	go func() {
-		subs := s.getSubscriptions()
-		for conn, active := range subs[channelID] {
-			if active {
-				s.SendUpdateMessage(conn, p)
-			}
-		}
-	}()
+	s.topics.Publish(channelID, LendingTradeChannel, types.UPDATE, p)
 }
 
 // SendMessage sends a websocket message on the trade channel
@@ -1,9 +1,6 @@
 package ws
 
 import (
-	"sync"
-
-	"github.com/tomochain/tomox-sdk/errors"
 	"github.com/tomochain/tomox-sdk/types"
 )
 
@@ -12,16 +9,12 @@ var marketsSocket *MarketsSocket
 // MarketsSocket holds the map of subscriptions subscribed to markets channels
 // corresponding to the key/event they have subscribed to.
 type MarketsSocket struct {
-	subscriptions     map[string]map[*Client]bool
-	subscriptionsList map[*Client][]string
-	subsMutex         sync.RWMutex
-	subsListMutex     sync.RWMutex
+	topics *Broker
 }
 
 func NewMarketsSocket() *MarketsSocket {
 	return &MarketsSocket{
-		subscriptions:     make(map[string]map[*Client]bool),
-		subscriptionsList: make(map[*Client][]string),
+		topics: NewBroker(),
 	}
 }
 
@@ -36,28 +29,7 @@ func GetMarketSocket() *MarketsSocket {
 // Subscribe handles the subscription of connection to get
 // streaming data over the socker for any pair.
 func (s *MarketsSocket) Subscribe(channelID string, c *Client) error {
-	s.subsMutex.Lock()
-	s.subsListMutex.Lock()
-	defer s.subsMutex.Unlock()
-	defer s.subsListMutex.Unlock()
-
-	if c == nil {
-		return errors.New("No connection found")
-	}
-
-	if s.subscriptions[channelID] == nil {
-		s.subscriptions[channelID] = make(map[*Client]bool)
-	}
-
-	s.subscriptions[channelID][c] = true
-
-	if s.subscriptionsList[c] == nil {
-		s.subscriptionsList[c] = []string{}
-	}
-
-	s.subscriptionsList[c] = append(s.subscriptionsList[c], channelID)
-
-	return nil
+	return s.topics.Subscribe(channelID, c)
 }
 
 // UnsubscribeHandler unsubscribes a connection from a certain markets channel id
@@ -75,43 +47,16 @@ func (s *MarketsSocket) UnsubscribeHandler() func(c *Client) {
 
 // Unsubscribe removes a websocket connection from the markets channel updates
 func (s *MarketsSocket) UnsubscribeChannel(channelID string, c *Client) {
-	s.subsMutex.Lock()
-	defer s.subsMutex.Unlock()
-	if s.subscriptions[channelID][c] {
-		s.subscriptions[channelID][c] = false
-		delete(s.subscriptions[channelID], c)
-	}
+	s.topics.Unsubscribe(channelID, c)
 }
 
 func (s *MarketsSocket) Unsubscribe(c *Client) {
-	s.subsListMutex.RLock()
-	defer s.subsListMutex.RUnlock()
-	channelIDs := s.subscriptionsList[c]
-	if channelIDs == nil {
-		return
-	}
-
-	for _, id := range s.subscriptionsList[c] {
-		s.UnsubscribeChannel(id, c)
-	}
-}
-
-func (s *MarketsSocket) getSubscriptions() map[string]map[*Client]bool {
-	s.subsMutex.RLock()
-	defer s.subsMutex.RUnlock()
-	return s.subscriptions
+	s.topics.UnsubscribeAll(c)
 }
 
 // BroadcastMessage streams message to all the subscriptions subscribed to the pair
 func (s *MarketsSocket) BroadcastMessage(channelID string, p interface{}) error {
-	subs := s.getSubscriptions()
-	for c, status := range subs[channelID] {
-		if status {
-			s.SendUpdateMessage(c, p)
-		}
-	}
-
-	return nil
+	return s.topics.Publish(channelID, MarketsChannel, types.UPDATE, p)
 }
 
 // SendMessage sends a websocket message on the markets channel
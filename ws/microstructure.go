@@ -0,0 +1,70 @@
+package ws
+
+import (
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+var microstructureSocket *MicrostructureSocket
+
+// MicrostructureSocket holds the subscriptions to the microstructure metrics of the pairs
+type MicrostructureSocket struct {
+	topics *Broker
+}
+
+// NewMicrostructureSocket returns a new instance of MicrostructureSocket
+func NewMicrostructureSocket() *MicrostructureSocket {
+	return &MicrostructureSocket{
+		topics: NewBroker(),
+	}
+}
+
+// GetMicrostructureSocket returns the singleton instance of MicrostructureSocket
+func GetMicrostructureSocket() *MicrostructureSocket {
+	if microstructureSocket == nil {
+		microstructureSocket = NewMicrostructureSocket()
+	}
+
+	return microstructureSocket
+}
+
+// Subscribe registers a connection to the metrics of a pair
+func (s *MicrostructureSocket) Subscribe(channelID string, c *Client) error {
+	return s.topics.Subscribe(channelID, c)
+}
+
+// Subscribed returns true if a connection is subscribed to the channel id
+func (s *MicrostructureSocket) Subscribed(channelID string) bool {
+	return s.topics.Count(channelID) > 0
+}
+
+// UnsubscribeChannelHandler unsubscribes a connection from a microstructure channel id
+func (s *MicrostructureSocket) UnsubscribeChannelHandler(channelID string) func(c *Client) {
+	return func(c *Client) {
+		s.UnsubscribeChannel(channelID, c)
+	}
+}
+
+// UnsubscribeChannel removes a connection from a microstructure channel id
+func (s *MicrostructureSocket) UnsubscribeChannel(channelID string, c *Client) {
+	s.topics.Unsubscribe(channelID, c)
+}
+
+// Unsubscribe removes a connection from all the microstructure channels
+func (s *MicrostructureSocket) Unsubscribe(c *Client) {
+	s.topics.UnsubscribeAll(c)
+}
+
+// BroadcastMessage streams the metrics to the subscriptions of the pair
+func (s *MicrostructureSocket) BroadcastMessage(channelID string, p interface{}) error {
+	return s.topics.Publish(channelID, MicrostructureChannel, types.UPDATE, p)
+}
+
+// SendInitMessage sends the current metrics of the pair on subscription
+func (s *MicrostructureSocket) SendInitMessage(c *Client, p interface{}) {
+	c.SendMessage(MicrostructureChannel, types.INIT, p)
+}
+
+// SendErrorMessage sends an error message on the microstructure channel
+func (s *MicrostructureSocket) SendErrorMessage(c *Client, p interface{}) {
+	c.SendMessage(MicrostructureChannel, types.ERROR, p)
+}
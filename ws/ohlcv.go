@@ -3,7 +3,6 @@ package ws
 import (
 	"sync"
 
-	"github.com/tomochain/tomox-sdk/errors"
 	"github.com/tomochain/tomox-sdk/types"
 )
 
@@ -11,18 +10,29 @@ var ohlcvSocket *OHLCVSocket
 
 // OHLCVSocket holds the map of subscribtions subscribed to OHLCV channels
 // corresponding to the key/event they have subscribed to.
+// The clients with display preferences subscribe to a variant of the channel
+// receiving the ticks of their timezone in their units.
 type OHLCVSocket struct {
-	subscriptions     map[string]map[*Client]bool
-	subscriptionsList map[*Client][]string
-	subsMutex         sync.RWMutex
-	subsListMutex     sync.RWMutex
+	topics *Broker
+
+	variants      map[string]*types.Preferences
+	variantsMutex sync.RWMutex
 }
 
 func NewOHLCVSocket() *OHLCVSocket {
 	return &OHLCVSocket{
-		subscriptions:     make(map[string]map[*Client]bool),
-		subscriptionsList: make(map[*Client][]string),
+		topics:   NewBroker(),
+		variants: make(map[string]*types.Preferences),
+	}
+}
+
+// variantID returns the topic of the subscribers of a channel with these preferences
+func variantID(channelID string, prefs *types.Preferences) string {
+	if key := prefs.Key(); key != "" {
+		return channelID + "::" + key
 	}
+
+	return channelID
 }
 
 // GetOHLCVSocket return singleton instance of OHLCVSocket type struct
@@ -36,27 +46,38 @@ func GetOHLCVSocket() *OHLCVSocket {
 
 // Subscribe handles the registration of connection to get
 // streaming data over the socket for any pair.
+// The client receives the ticks according to its current display preferences.
 func (s *OHLCVSocket) Subscribe(channelID string, c *Client) error {
-	s.subsMutex.Lock()
-	s.subsListMutex.Lock()
-	defer s.subsMutex.Unlock()
-	defer s.subsListMutex.Unlock()
+	return s.SubscribeWithPreferences(channelID, c.Preferences(), c)
+}
 
-	if c == nil {
-		return errors.New("No connection found")
+// SubscribeWithPreferences subscribes a client to the ticks of a pair in the timezone and the units of prefs
+func (s *OHLCVSocket) SubscribeWithPreferences(channelID string, prefs *types.Preferences, c *Client) error {
+	if key := prefs.Key(); key != "" {
+		s.variantsMutex.Lock()
+		s.variants[key] = prefs
+		s.variantsMutex.Unlock()
 	}
 
-	if s.subscriptions[channelID] == nil {
-		s.subscriptions[channelID] = make(map[*Client]bool)
-	}
+	return s.topics.Subscribe(variantID(channelID, prefs), c)
+}
 
-	s.subscriptions[channelID][c] = true
+// Variants returns the display preferences the clients subscribed with, except the default ones
+func (s *OHLCVSocket) Variants() []*types.Preferences {
+	s.variantsMutex.RLock()
+	defer s.variantsMutex.RUnlock()
 
-	if s.subscriptionsList[c] == nil {
-		s.subscriptionsList[c] = []string{}
+	res := make([]*types.Preferences, 0, len(s.variants))
+	for _, prefs := range s.variants {
+		res = append(res, prefs)
 	}
-	s.subscriptionsList[c] = append(s.subscriptionsList[c], channelID)
-	return nil
+
+	return res
+}
+
+// HasSubscribers returns true if some clients subscribed to the channel with these preferences
+func (s *OHLCVSocket) HasSubscribers(channelID string, prefs *types.Preferences) bool {
+	return s.topics.Count(variantID(channelID, prefs)) > 0
 }
 
 // UnsubscribeHandler returns function of type unsubscribe handler,
@@ -77,38 +98,24 @@ func (s *OHLCVSocket) UnsubscribeHandler() func(c *Client) {
 // subscribed to. It can be called on unsubscription message from user or due to some other reason by
 // system
 func (s *OHLCVSocket) UnsubscribeChannel(channelID string, c *Client) {
-	s.subsMutex.Lock()
-	defer s.subsMutex.Unlock()
-	if s.subscriptions[channelID][c] {
-		s.subscriptions[channelID][c] = false
-		delete(s.subscriptions[channelID], c)
+	s.topics.Unsubscribe(channelID, c)
+	for _, prefs := range s.Variants() {
+		s.topics.Unsubscribe(variantID(channelID, prefs), c)
 	}
 }
 
 func (s *OHLCVSocket) Unsubscribe(c *Client) {
-	s.subsListMutex.RLock()
-	defer s.subsListMutex.RUnlock()
-	channelIDs := s.subscriptionsList[c]
-	if channelIDs == nil {
-		return
-	}
-
-	for _, id := range s.subscriptionsList[c] {
-		s.UnsubscribeChannel(id, c)
-	}
+	s.topics.UnsubscribeAll(c)
 }
 
 // BroadcastOHLCV Message streams message to all the subscriptions subscribed to the pair
 func (s *OHLCVSocket) BroadcastOHLCV(channelID string, p interface{}) error {
-	s.subsMutex.RLock()
-	defer s.subsMutex.RUnlock()
-	for c, status := range s.subscriptions[channelID] {
-		if status {
-			s.SendUpdateMessage(c, p)
-		}
-	}
+	return s.topics.Publish(channelID, OHLCVChannel, types.UPDATE, p)
+}
 
-	return nil
+// BroadcastOHLCVVariant streams a message to the subscriptions to the pair with these preferences
+func (s *OHLCVSocket) BroadcastOHLCVVariant(channelID string, prefs *types.Preferences, p interface{}) error {
+	return s.topics.Publish(variantID(channelID, prefs), OHLCVChannel, types.UPDATE, p)
 }
 
 // SendMessage sends a websocket message on the trade channel
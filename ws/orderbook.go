@@ -1,9 +1,6 @@
 package ws
 
 import (
-	"sync"
-
-	"github.com/tomochain/tomox-sdk/errors"
 	"github.com/tomochain/tomox-sdk/types"
 )
 
@@ -12,16 +9,12 @@ var orderbookSocket *OrderBookSocket
 // OrderBookSocket holds the map of subscriptions subscribed to orderbook channels
 // corresponding to the key/event they have subscribed to.
 type OrderBookSocket struct {
-	subscriptions     map[string]map[*Client]bool
-	subscriptionsList map[*Client][]string
-	subsMutex         sync.RWMutex
-	subsListMutex     sync.RWMutex
+	topics *Broker
 }
 
 func NewOrderBookSocket() *OrderBookSocket {
 	return &OrderBookSocket{
-		subscriptions:     make(map[string]map[*Client]bool),
-		subscriptionsList: make(map[*Client][]string),
+		topics: NewBroker(),
 	}
 }
 
@@ -38,28 +31,7 @@ func GetOrderBookSocket() *OrderBookSocket {
 // streaming data over the socker for any pair.
 // pair := utils.GetPairKey(bt, qt)
 func (s *OrderBookSocket) Subscribe(channelID string, c *Client) error {
-	s.subsMutex.Lock()
-	s.subsListMutex.Lock()
-	defer s.subsMutex.Unlock()
-	defer s.subsListMutex.Unlock()
-
-	if c == nil {
-		return errors.New("No connection found")
-	}
-
-	if s.subscriptions[channelID] == nil {
-		s.subscriptions[channelID] = make(map[*Client]bool)
-	}
-
-	s.subscriptions[channelID][c] = true
-
-	if s.subscriptionsList[c] == nil {
-		s.subscriptionsList[c] = []string{}
-	}
-
-	s.subscriptionsList[c] = append(s.subscriptionsList[c], channelID)
-
-	return nil
+	return s.topics.Subscribe(channelID, c)
 }
 
 // UnsubscribeHandler unsubscribes a connection from a certain orderbook channel id
@@ -77,43 +49,16 @@ func (s *OrderBookSocket) UnsubscribeHandler() func(c *Client) {
 
 // UnsubscribeChannel removes a websocket connection from the orderbook channel updates
 func (s *OrderBookSocket) UnsubscribeChannel(channelID string, c *Client) {
-	s.subsMutex.Lock()
-	defer s.subsMutex.Unlock()
-	if s.subscriptions[channelID][c] {
-		s.subscriptions[channelID][c] = false
-		delete(s.subscriptions[channelID], c)
-	}
+	s.topics.Unsubscribe(channelID, c)
 }
 
 func (s *OrderBookSocket) Unsubscribe(c *Client) {
-	s.subsListMutex.RLock()
-	defer s.subsListMutex.RUnlock()
-	channelIDs := s.subscriptionsList[c]
-	if channelIDs == nil {
-		return
-	}
-
-	for _, id := range s.subscriptionsList[c] {
-		s.UnsubscribeChannel(id, c)
-	}
-}
-
-func (s *OrderBookSocket) getSubscriptions() map[string]map[*Client]bool {
-	s.subsMutex.RLock()
-	defer s.subsMutex.RUnlock()
-	return s.subscriptions
+	s.topics.UnsubscribeAll(c)
 }
 
 // BroadcastMessage streams message to all the subscribtions subscribed to the pair
 func (s *OrderBookSocket) BroadcastMessage(channelID string, p interface{}) error {
-	subs := s.getSubscriptions()
-	for c, status := range subs[channelID] {
-		if status {
-			s.SendUpdateMessage(c, p)
-		}
-	}
-
-	return nil
+	return s.topics.Publish(channelID, OrderBookChannel, types.UPDATE, p)
 }
 
 // SendMessage sends a websocket message on the orderbook channel
@@ -83,6 +83,8 @@ func RegisterOrderConnection(a common.Address, c *Client) {
 }
 
 func SendOrderMessage(msgType types.SubscriptionEvent, a common.Address, payload interface{}) {
+	probe(a, OrderChannel, msgType, payload)
+
 	conn := GetOrderConnections(a)
 	if conn == nil {
 		return
@@ -0,0 +1,63 @@
+package ws
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// ProbeFunc is called with a message sent to a probed address
+type ProbeFunc func(channel string, msgType types.SubscriptionEvent, payload interface{})
+
+var (
+	probes     = make(map[common.Address]ProbeFunc)
+	probeMutex sync.RWMutex
+)
+
+// SetProbe observes the messages of the orders channel sent to an address and the trades
+// of the address published on the trades channel, the canary times the broadcasts with it
+func SetProbe(a common.Address, fn ProbeFunc) {
+	probeMutex.Lock()
+	defer probeMutex.Unlock()
+
+	probes[a] = fn
+}
+
+// RemoveProbe stops observing the messages of an address
+func RemoveProbe(a common.Address) {
+	probeMutex.Lock()
+	defer probeMutex.Unlock()
+
+	delete(probes, a)
+}
+
+// probe hands a message sent to an address to its probe, if any
+func probe(a common.Address, channel string, msgType types.SubscriptionEvent, payload interface{}) {
+	probeMutex.RLock()
+	fn := probes[a]
+	probeMutex.RUnlock()
+
+	if fn != nil {
+		fn(channel, msgType, payload)
+	}
+}
+
+// probeTrades hands the published trades to the probes of their makers and takers
+func probeTrades(channel string, payload interface{}) {
+	probeMutex.RLock()
+	empty := len(probes) == 0
+	probeMutex.RUnlock()
+
+	trades, ok := payload.([]*types.Trade)
+	if empty || !ok {
+		return
+	}
+
+	for _, t := range trades {
+		probe(t.Taker, channel, types.UPDATE, payload)
+		if t.Maker != t.Taker {
+			probe(t.Maker, channel, types.UPDATE, payload)
+		}
+	}
+}
@@ -1,7 +1,6 @@
 package ws
 
 import (
-	"github.com/tomochain/tomox-sdk/errors"
 	"github.com/tomochain/tomox-sdk/types"
 )
 
@@ -10,14 +9,12 @@ var rawOrderBookSocket *RawOrderBookSocket
 // RawOrderBookSocket holds the map of subscribtions subscribed to pair channels
 // corresponding to the key/event they have subscribed to.
 type RawOrderBookSocket struct {
-	subscriptions     map[string]map[*Client]bool
-	subscriptionsList map[*Client][]string
+	topics *Broker
 }
 
 func NewRawOrderBookSocket() *RawOrderBookSocket {
 	return &RawOrderBookSocket{
-		subscriptions:     make(map[string]map[*Client]bool),
-		subscriptionsList: make(map[*Client][]string),
+		topics: NewBroker(),
 	}
 }
 
@@ -34,23 +31,7 @@ func GetRawOrderBookSocket() *RawOrderBookSocket {
 // streaming data over the socker for any pair.
 // pair := utils.GetPairKey(bt, qt)
 func (s *RawOrderBookSocket) Subscribe(channelID string, c *Client) error {
-	if c == nil {
-		return errors.New("No connection found")
-	}
-
-	if s.subscriptions[channelID] == nil {
-		s.subscriptions[channelID] = make(map[*Client]bool)
-	}
-
-	s.subscriptions[channelID][c] = true
-
-	if s.subscriptionsList[c] == nil {
-		s.subscriptionsList[c] = []string{}
-	}
-
-	s.subscriptionsList[c] = append(s.subscriptionsList[c], channelID)
-
-	return nil
+	return s.topics.Subscribe(channelID, c)
 }
 
 // UnsubscribeHandler returns function of type unsubscribe handler,
@@ -71,32 +52,16 @@ func (s *RawOrderBookSocket) UnsubscribeHandler() func(c *Client) {
 // subscribed to. It can be called on unsubscription message from user or due to some other reason by
 // system
 func (s *RawOrderBookSocket) UnsubscribeChannel(channelID string, c *Client) {
-	if s.subscriptions[channelID][c] {
-		s.subscriptions[channelID][c] = false
-		delete(s.subscriptions[channelID], c)
-	}
+	s.topics.Unsubscribe(channelID, c)
 }
 
 func (s *RawOrderBookSocket) Unsubscribe(c *Client) {
-	channelIDs := s.subscriptionsList[c]
-	if channelIDs == nil {
-		return
-	}
-
-	for _, id := range s.subscriptionsList[c] {
-		s.UnsubscribeChannel(id, c)
-	}
+	s.topics.UnsubscribeAll(c)
 }
 
 // BroadcastMessage streams message to all the subscribtions subscribed to the pair
 func (s *RawOrderBookSocket) BroadcastMessage(channelID string, p interface{}) error {
-	for c, status := range s.subscriptions[channelID] {
-		if status {
-			s.SendUpdateMessage(c, p)
-		}
-	}
-
-	return nil
+	return s.topics.Publish(channelID, RawOrderBookChannel, types.UPDATE, p)
 }
 
 // SendInitMessage sends INIT message on orderbookchannel on subscription event
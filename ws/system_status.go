@@ -0,0 +1,65 @@
+package ws
+
+import (
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+var systemStatusSocket *SystemStatusSocket
+
+// SystemStatusSocket holds the subscriptions to the incidents of the status page
+type SystemStatusSocket struct {
+	topics *Broker
+}
+
+// NewSystemStatusSocket returns a new instance of SystemStatusSocket
+func NewSystemStatusSocket() *SystemStatusSocket {
+	return &SystemStatusSocket{
+		topics: NewBroker(),
+	}
+}
+
+// GetSystemStatusSocket returns the singleton instance of SystemStatusSocket
+func GetSystemStatusSocket() *SystemStatusSocket {
+	if systemStatusSocket == nil {
+		systemStatusSocket = NewSystemStatusSocket()
+	}
+
+	return systemStatusSocket
+}
+
+// Subscribe registers a connection to the incidents updates
+func (s *SystemStatusSocket) Subscribe(channelID string, c *Client) error {
+	return s.topics.Subscribe(channelID, c)
+}
+
+// UnsubscribeChannelHandler unsubscribes a connection from a system status channel id
+func (s *SystemStatusSocket) UnsubscribeChannelHandler(channelID string) func(c *Client) {
+	return func(c *Client) {
+		s.UnsubscribeChannel(channelID, c)
+	}
+}
+
+// UnsubscribeChannel removes a connection from a system status channel id
+func (s *SystemStatusSocket) UnsubscribeChannel(channelID string, c *Client) {
+	s.topics.Unsubscribe(channelID, c)
+}
+
+// Unsubscribe removes a connection from the system status channel
+func (s *SystemStatusSocket) Unsubscribe(c *Client) {
+	s.topics.UnsubscribeAll(c)
+}
+
+// BroadcastMessage streams an incident update to the subscriptions of the system status channel
+func (s *SystemStatusSocket) BroadcastMessage(channelID string, p interface{}) error {
+	return s.topics.Publish(channelID, SystemStatusChannel, types.UPDATE, p)
+}
+
+// SendInitMessage sends the status page on subscription
+func (s *SystemStatusSocket) SendInitMessage(c *Client, p interface{}) {
+	c.SendMessage(SystemStatusChannel, types.INIT, p)
+}
+
+// SendErrorMessage sends an error message on the system status channel
+func (s *SystemStatusSocket) SendErrorMessage(c *Client, p interface{}) {
+	c.SendMessage(SystemStatusChannel, types.ERROR, p)
+}
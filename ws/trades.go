@@ -1,30 +1,49 @@
 package ws
 
 import (
+	"math/big"
+	"strings"
 	"sync"
+	"time"
 
-	"github.com/tomochain/tomox-sdk/errors"
+	"github.com/tomochain/tomox-sdk/app"
 	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/math"
 )
 
 var tradeSocket *TradeSocket
+var rawTradeSocket *TradeSocket
 
 // TradeSocket holds the map of connections subscribed to pair channels
 // corresponding to the key/event they have subscribed to.
 type TradeSocket struct {
-	subscriptions     map[string]map[*Client]bool
-	subscriptionsList map[*Client][]string
-	subsMutex         sync.RWMutex
-	subsListMutex     sync.RWMutex
+	channel       string
+	topics        *Broker
+	samplers      map[string]*tradeSampler
+	samplersMutex sync.Mutex
+}
+
+// tradeSampler conflates the trades of a channel broadcast more often than its interval
+type tradeSampler struct {
+	interval  time.Duration
+	last      time.Time
+	pending   []*types.Trade
+	scheduled bool
 }
 
 func NewTradeSocket() *TradeSocket {
+	return newTradeSocket(TradeChannel)
+}
+
+func newTradeSocket(channel string) *TradeSocket {
 	return &TradeSocket{
-		subscriptions:     make(map[string]map[*Client]bool),
-		subscriptionsList: make(map[*Client][]string),
+		channel:  channel,
+		topics:   NewBroker(),
+		samplers: make(map[string]*tradeSampler),
 	}
 }
 
+// GetTradeSocket returns the socket of the public trades channel
 func GetTradeSocket() *TradeSocket {
 	if tradeSocket == nil {
 		tradeSocket = NewTradeSocket()
@@ -33,28 +52,18 @@ func GetTradeSocket() *TradeSocket {
 	return tradeSocket
 }
 
-// Subscribe registers a new websocket connections to the trade channel updates
-func (s *TradeSocket) Subscribe(channelID string, c *Client) error {
-	s.subsMutex.Lock()
-	s.subsListMutex.Lock()
-	defer s.subsMutex.Unlock()
-	defer s.subsListMutex.Unlock()
-
-	if c == nil {
-		return errors.New("No connection found")
+// GetRawTradeSocket returns the socket of the authenticated trades channel, which is never sampled
+func GetRawTradeSocket() *TradeSocket {
+	if rawTradeSocket == nil {
+		rawTradeSocket = newTradeSocket(RawTradeChannel)
 	}
 
-	if s.subscriptions[channelID] == nil {
-		s.subscriptions[channelID] = make(map[*Client]bool)
-	}
-
-	s.subscriptions[channelID][c] = true
+	return rawTradeSocket
+}
 
-	if s.subscriptionsList[c] == nil {
-		s.subscriptionsList[c] = []string{}
-	}
-	s.subscriptionsList[c] = append(s.subscriptionsList[c], channelID)
-	return nil
+// Subscribe registers a new websocket connections to the trade channel updates
+func (s *TradeSocket) Subscribe(channelID string, c *Client) error {
+	return s.topics.Subscribe(channelID, c)
 }
 
 // UnsubscribeChannelHandler unsubscribes a connection from a certain trade channel id
@@ -72,61 +81,153 @@ func (s *TradeSocket) UnsubscribeHandler() func(c *Client) {
 
 // UnsubscribeChannel removes a websocket connection from the trade channel updates
 func (s *TradeSocket) UnsubscribeChannel(channelID string, c *Client) {
-	s.subsMutex.Lock()
-	defer s.subsMutex.Unlock()
-	if s.subscriptions[channelID][c] {
-		s.subscriptions[channelID][c] = false
-		delete(s.subscriptions[channelID], c)
-	}
+	s.topics.Unsubscribe(channelID, c)
 }
 
 func (s *TradeSocket) Unsubscribe(c *Client) {
-	s.subsListMutex.RLock()
-	defer s.subsListMutex.RUnlock()
-	channelIDs := s.subscriptionsList[c]
-	if channelIDs == nil {
+	s.topics.UnsubscribeAll(c)
+}
+
+// BroadcastMessage broadcasts trade message to all subscribed sockets
+func (s *TradeSocket) BroadcastMessage(channelID string, p interface{}) {
+	probeTrades(s.channel, p)
+	s.topics.Publish(channelID, s.channel, types.UPDATE, s.payload(p))
+}
+
+// payload masks the maker and taker addresses of the trades of the public channel according to the
+// "trade_feed_privacy" config, the authenticated channel always gets them in full
+func (s *TradeSocket) payload(p interface{}) interface{} {
+	trades, ok := p.([]*types.Trade)
+	if !ok || s.channel != TradeChannel {
+		return p
+	}
+
+	return types.PublicTrades(trades, app.Config.TradeFeedPrivacy)
+}
+
+// BroadcastTrades broadcasts the trades of a pair, sampled according to the "trade_sampling" config.
+// When the trades of the pair are broadcast more often than allowed, they are held
+// until the next slot and the fills of the same taker order at the same price are aggregated
+func (s *TradeSocket) BroadcastTrades(channelID, pairName string, trades []*types.Trade) {
+	interval := tradeSamplingInterval(pairName)
+	if interval == 0 {
+		s.BroadcastMessage(channelID, trades)
 		return
 	}
 
-	for _, id := range s.subscriptionsList[c] {
-		s.UnsubscribeChannel(id, c)
+	s.samplersMutex.Lock()
+	defer s.samplersMutex.Unlock()
+
+	sampler := s.samplers[channelID]
+	if sampler == nil {
+		sampler = &tradeSampler{}
+		s.samplers[channelID] = sampler
 	}
+
+	sampler.interval = interval
+	sampler.pending = append(sampler.pending, trades...)
+	if sampler.scheduled {
+		return
+	}
+
+	wait := interval - time.Since(sampler.last)
+	if wait <= 0 {
+		s.flush(channelID, sampler)
+		return
+	}
+
+	sampler.scheduled = true
+	time.AfterFunc(wait, func() {
+		s.samplersMutex.Lock()
+		defer s.samplersMutex.Unlock()
+		s.flush(channelID, sampler)
+	})
 }
 
-func (s *TradeSocket) getSubscriptions() map[string]map[*Client]bool {
-	s.subsMutex.RLock()
-	defer s.subsMutex.RUnlock()
-	return tradeSocket.subscriptions
+// flush broadcasts the pending trades of a sampler, the caller holds the samplers mutex
+func (s *TradeSocket) flush(channelID string, sampler *tradeSampler) {
+	trades := ConflateTrades(sampler.pending)
+	sampler.pending = nil
+	sampler.scheduled = false
+	sampler.last = time.Now()
+
+	if len(trades) > 0 {
+		s.BroadcastMessage(channelID, trades)
+	}
 }
 
-// BroadcastMessage broadcasts trade message to all subscribed sockets
-func (s *TradeSocket) BroadcastMessage(channelID string, p interface{}) {
-	go func() {
-		subs := s.getSubscriptions()
-		for conn, active := range subs[channelID] {
-			if active {
-				s.SendUpdateMessage(conn, p)
-			}
+// ConflateTrades aggregates the fills of the same taker order at the same price into one trade
+func ConflateTrades(trades []*types.Trade) []*types.Trade {
+	res := []*types.Trade{}
+	fills := make(map[string]*types.Trade)
+
+	for _, t := range trades {
+		key := t.TakerOrderHash.Hex() + "/" + t.PricePoint.String()
+		if fills[key] == nil {
+			fill := *t
+			fills[key] = &fill
+			res = append(res, &fill)
+			continue
+		}
+
+		fill := fills[key]
+
+		fill.Hash = t.Hash
+		fill.Amount = math.Add(fill.Amount, t.Amount)
+		fill.MakeFee = addFee(fill.MakeFee, t.MakeFee)
+		fill.TakeFee = addFee(fill.TakeFee, t.TakeFee)
+		if t.CreatedAt.After(fill.CreatedAt) {
+			fill.CreatedAt = t.CreatedAt
+			fill.UpdatedAt = t.UpdatedAt
 		}
-	}()
+	}
+
+	return res
+}
+
+func addFee(x, y *big.Int) *big.Int {
+	if x == nil {
+		return y
+	}
+
+	if y == nil {
+		return x
+	}
+
+	return math.Add(x, y)
+}
+
+// tradeSamplingInterval returns the minimal interval between two trade messages of a pair,
+// configured as the maximal number of messages per second for the pair or by default
+func tradeSamplingInterval(pairName string) time.Duration {
+	rate, ok := app.Config.TradeSampling[strings.ToLower(pairName)]
+	if !ok {
+		rate = app.Config.TradeSampling["default"]
+	}
+
+	if rate <= 0 {
+		return 0
+	}
+
+	return time.Second / time.Duration(rate)
 }
 
 // SendMessage sends a websocket message on the trade channel
 func (s *TradeSocket) SendMessage(c *Client, msgType types.SubscriptionEvent, p interface{}) {
-	c.SendMessage(TradeChannel, msgType, p)
+	c.SendMessage(s.channel, msgType, p)
 }
 
 // SendInitMessage is responsible for sending message on trade ohlcv channel at subscription
 func (s *TradeSocket) SendInitMessage(c *Client, p interface{}) {
-	c.SendMessage(TradeChannel, types.INIT, p)
+	c.SendMessage(s.channel, types.INIT, s.payload(p))
 }
 
 // SendUpdateMessage is responsible for sending message on trade ohlcv channel at subscription
 func (s *TradeSocket) SendUpdateMessage(c *Client, p interface{}) {
-	c.SendMessage(TradeChannel, types.UPDATE, p)
+	c.SendMessage(s.channel, types.UPDATE, s.payload(p))
 }
 
 // SendErrorMessage sends an error message on the trade channel
 func (s *TradeSocket) SendErrorMessage(c *Client, p interface{}) {
-	c.SendMessage(TradeChannel, types.ERROR, p)
+	c.SendMessage(s.channel, types.ERROR, p)
 }
@@ -0,0 +1,42 @@
+package ws
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+func TestConflateTrades(t *testing.T) {
+	order := common.HexToHash("0x1")
+	now := time.Now()
+
+	trades := []*types.Trade{
+		{Hash: common.HexToHash("0xa"), TakerOrderHash: order, PricePoint: big.NewInt(10), Amount: big.NewInt(1), TakeFee: big.NewInt(1), CreatedAt: now},
+		{Hash: common.HexToHash("0xb"), TakerOrderHash: order, PricePoint: big.NewInt(11), Amount: big.NewInt(2), CreatedAt: now},
+		{Hash: common.HexToHash("0xc"), TakerOrderHash: order, PricePoint: big.NewInt(10), Amount: big.NewInt(3), TakeFee: big.NewInt(2), CreatedAt: now.Add(time.Second)},
+	}
+
+	res := ConflateTrades(trades)
+	assert.Equal(t, 2, len(res))
+	assert.Equal(t, common.HexToHash("0xc"), res[0].Hash)
+	assert.Equal(t, big.NewInt(4), res[0].Amount)
+	assert.Equal(t, big.NewInt(3), res[0].TakeFee)
+	assert.Equal(t, now.Add(time.Second), res[0].CreatedAt)
+	assert.Equal(t, big.NewInt(2), res[1].Amount)
+
+	// the broadcast trades are not modified
+	assert.Equal(t, big.NewInt(1), trades[0].Amount)
+}
+
+func TestTradeSamplingInterval(t *testing.T) {
+	app.Config.TradeSampling = map[string]int{"default": 0, "tomo/usdt": 20}
+	defer func() { app.Config.TradeSampling = nil }()
+
+	assert.Equal(t, 50*time.Millisecond, tradeSamplingInterval("TOMO/USDT"))
+	assert.Equal(t, time.Duration(0), tradeSamplingInterval("BTC/USDT"))
+}